@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/urfave/cli/v2"
+
+	"github.com/ethereum-optimism/optimism/cannon/gdbstub"
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm"
+	"github.com/ethereum-optimism/optimism/cannon/serialize"
+)
+
+var (
+	DebugInputFlag = &cli.PathFlag{
+		Name:      "input",
+		Usage:     "path of input state, either JSON or binary.",
+		TakesFile: true,
+		Value:     "state.json",
+		Required:  true,
+	}
+	DebugOutputFlag = &cli.PathFlag{
+		Name:      "output",
+		Usage:     "path of output state, written when the debugger kills or detaches from the program. Not written if empty.",
+		TakesFile: true,
+		Required:  false,
+	}
+	DebugMetaFlag = &cli.PathFlag{
+		Name:     "meta",
+		Usage:    "path to metadata file for symbol lookup of breakpoints.",
+		Value:    "meta.json",
+		Required: false,
+	}
+	DebugListenAddrFlag = &cli.StringFlag{
+		Name:  "listen",
+		Usage: "TCP address to serve the GDB remote serial protocol on.",
+		Value: "127.0.0.1:1234",
+	}
+	DebugBreakFlag = &cli.StringSliceFlag{
+		Name:  "break",
+		Usage: "symbol to break at, may be repeated. Breakpoints can also be added with 'monitor break <symbol>' in gdb.",
+	}
+)
+
+func Debug(ctx *cli.Context) error {
+	state, err := serialize.Load[mipsevm.State](ctx.Path(DebugInputFlag.Name))
+	if err != nil {
+		return err
+	}
+
+	l := Logger(os.Stderr, log.LvlInfo)
+	outLog := &mipsevm.LoggingWriter{Name: "program std-out", Log: l}
+	errLog := &mipsevm.LoggingWriter{Name: "program std-err", Log: l}
+
+	// split CLI args after first '--'
+	args := ctx.Args().Slice()
+	for i, arg := range args {
+		if arg == "--" {
+			args = args[i+1:]
+			break
+		}
+	}
+	if len(args) == 0 {
+		args = []string{""}
+	}
+
+	po, err := NewProcessPreimageOracle(args[0], args[1:])
+	if err != nil {
+		return fmt.Errorf("failed to create pre-image oracle process: %w", err)
+	}
+	if err := po.Start(); err != nil {
+		return fmt.Errorf("failed to start pre-image oracle server: %w", err)
+	}
+	defer func() {
+		if err := po.Close(); err != nil {
+			l.Error("failed to close pre-image server", "err", err)
+		}
+	}()
+
+	meta := &mipsevm.Metadata{}
+	if metaPath := ctx.Path(DebugMetaFlag.Name); metaPath != "" {
+		if meta, err = serialize.Load[mipsevm.Metadata](metaPath); err != nil {
+			return fmt.Errorf("failed to load metadata: %w", err)
+		}
+	}
+
+	us := mipsevm.NewInstrumentedState(state, po, outLog, errLog)
+	stepFn := us.Step
+	if po.cmd != nil {
+		stepFn = Guard(po.cmd.ProcessState, stepFn)
+	}
+
+	dbg := gdbstub.NewDebugger(state, gdbstub.StepFn(stepFn), meta)
+	for _, name := range ctx.StringSlice(DebugBreakFlag.Name) {
+		if err := dbg.AddSymbolBreakpoint(name); err != nil {
+			return fmt.Errorf("failed to add breakpoint: %w", err)
+		}
+	}
+
+	server := gdbstub.NewServer(l, dbg)
+	if err := server.ListenAndServe(ctx.Context, ctx.String(DebugListenAddrFlag.Name)); err != nil {
+		return err
+	}
+
+	if err := serialize.Write(ctx.Path(DebugOutputFlag.Name), state); err != nil {
+		return fmt.Errorf("failed to write state output: %w", err)
+	}
+	return nil
+}
+
+var DebugCommand = &cli.Command{
+	Name:        "debug",
+	Usage:       "Debug a VM state with gdb.",
+	Description: "Serve a VM state over the GDB remote serial protocol, to attach e.g. gdb-multiarch with 'target remote'. Supports breakpoints, single-stepping, register and memory reads, and watchpoints.",
+	Action:      Debug,
+	Flags: []cli.Flag{
+		DebugInputFlag,
+		DebugOutputFlag,
+		DebugMetaFlag,
+		DebugListenAddrFlag,
+		DebugBreakFlag,
+	},
+}
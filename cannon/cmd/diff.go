@@ -0,0 +1,431 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/urfave/cli/v2"
+
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm"
+	"github.com/ethereum-optimism/optimism/cannon/serialize"
+)
+
+var (
+	DiffInputAFlag = &cli.PathFlag{
+		Name:      "input-a",
+		Usage:     "path of the input state of execution A, either JSON or binary.",
+		TakesFile: true,
+		Required:  true,
+	}
+	DiffInputBFlag = &cli.PathFlag{
+		Name:      "input-b",
+		Usage:     "path of the input state of execution B, either JSON or binary.",
+		TakesFile: true,
+		Required:  true,
+	}
+	DiffMetaAFlag = &cli.PathFlag{
+		Name:     "meta-a",
+		Usage:    "path to metadata file for symbol lookup of execution A.",
+		Required: false,
+	}
+	DiffMetaBFlag = &cli.PathFlag{
+		Name:     "meta-b",
+		Usage:    "path to metadata file for symbol lookup of execution B.",
+		Required: false,
+	}
+	DiffCheckEveryFlag = &cli.Uint64Flag{
+		Name:  "check-every",
+		Usage: "number of steps to run in lockstep between comparisons of the state hashes.",
+		Value: 100_000,
+	}
+	DiffOutputFlag = &cli.PathFlag{
+		Name:      "output",
+		Usage:     "path to write the JSON report of the first differing step to. Not written if empty, use - to write to Stdout.",
+		TakesFile: true,
+		Required:  false,
+	}
+	DiffProofFmtFlag = &cli.StringFlag{
+		Name:     "proof-fmt",
+		Usage:    "format for the proof data file names of the first differing step, %s is replaced with a or b. Proofs are written in binary if the format ends in .bin or .bin.gz, JSON otherwise. Not written if empty.",
+		Required: false,
+	}
+)
+
+// DiffSide is the result of the first differing step, of one of the two executions.
+type DiffSide struct {
+	PreStateHash  common.Hash `json:"preStateHash"`
+	PostStateHash common.Hash `json:"postStateHash"`
+
+	PC     uint32 `json:"pc"`
+	Insn   uint32 `json:"insn"`
+	Symbol string `json:"symbol"`
+
+	// Post-state of the registers
+	Registers [32]uint32 `json:"registers"`
+	LO        uint32     `json:"lo"`
+	HI        uint32     `json:"hi"`
+	NextPC    uint32     `json:"nextPC"`
+	Exited    bool       `json:"exited"`
+	ExitCode  uint8      `json:"exit"`
+
+	MemProof hexutil.Bytes `json:"memProof"`
+
+	Proof *Proof `json:"-"`
+}
+
+// Divergence is the first step of which the post-states of the two executions differ.
+type Divergence struct {
+	// Step is the step of the last equal state, i.e. the pre-state of the differing step.
+	Step uint64   `json:"step"`
+	A    DiffSide `json:"a"`
+	B    DiffSide `json:"b"`
+}
+
+// diffVM is one of the two executions that are compared.
+type diffVM struct {
+	name   string
+	meta   *mipsevm.Metadata
+	oracle mipsevm.PreimageOracle
+	stdOut io.Writer
+	stdErr io.Writer
+	// guard wraps the step function, e.g. to detect a failed pre-image server.
+	guard func(StepFn) StepFn
+
+	state  *mipsevm.State
+	stepFn StepFn
+}
+
+func (vm *diffVM) setState(state *mipsevm.State) {
+	vm.state = state
+	us := mipsevm.NewInstrumentedState(state, vm.oracle, vm.stdOut, vm.stdErr)
+	vm.stepFn = us.Step
+	if vm.guard != nil {
+		vm.stepFn = vm.guard(vm.stepFn)
+	}
+}
+
+// snapshot returns a copy of the current state, in the binary serialization.
+func (vm *diffVM) snapshot() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := vm.state.Serialize(&buf); err != nil {
+		return nil, fmt.Errorf("failed to snapshot state %s: %w", vm.name, err)
+	}
+	return buf.Bytes(), nil
+}
+
+func (vm *diffVM) restore(snapshot []byte) error {
+	state := new(mipsevm.State)
+	if err := state.Deserialize(bytes.NewReader(snapshot)); err != nil {
+		return fmt.Errorf("failed to restore state %s: %w", vm.name, err)
+	}
+	vm.setState(state)
+	return nil
+}
+
+// runTo runs until the given step, or until the program exits.
+func (vm *diffVM) runTo(ctx context.Context, step uint64) error {
+	for !vm.state.Exited && vm.state.Step < step {
+		if vm.state.Step%100 == 0 {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+		if _, err := vm.stepFn(false); err != nil {
+			return fmt.Errorf("execution %s failed at step %d (PC: %08x): %w", vm.name, vm.state.Step, vm.state.PC, err)
+		}
+	}
+	return nil
+}
+
+func (vm *diffVM) stateHash() (common.Hash, error) {
+	h, err := vm.state.EncodeWitness().StateHash()
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to hash state %s: %w", vm.name, err)
+	}
+	return h, nil
+}
+
+// proveStep executes the next step with a proof, and describes the step.
+func (vm *diffVM) proveStep() (DiffSide, error) {
+	pre, err := vm.stateHash()
+	if err != nil {
+		return DiffSide{}, err
+	}
+	step, pc := vm.state.Step, vm.state.PC
+	insn := vm.state.Memory.GetMemory(pc)
+	witness, err := vm.stepFn(true)
+	if err != nil {
+		return DiffSide{}, fmt.Errorf("execution %s failed at proof-gen step %d (PC: %08x): %w", vm.name, step, pc, err)
+	}
+	post, err := vm.stateHash()
+	if err != nil {
+		return DiffSide{}, err
+	}
+	return DiffSide{
+		PreStateHash:  pre,
+		PostStateHash: post,
+		PC:            pc,
+		Insn:          insn,
+		Symbol:        vm.meta.LookupSymbol(pc),
+		Registers:     vm.state.Registers,
+		LO:            vm.state.LO,
+		HI:            vm.state.HI,
+		NextPC:        vm.state.NextPC,
+		Exited:        vm.state.Exited,
+		ExitCode:      vm.state.ExitCode,
+		MemProof:      witness.MemProof,
+		Proof:         NewProof(step, pre, post, witness),
+	}, nil
+}
+
+// findDivergence runs both executions in lockstep, and compares the state hashes every checkEvery steps.
+// Once the hashes differ, the executions are restored from the last snapshot with equal hashes,
+// and the first differing step is located with a binary search.
+// Nil is returned if both executions exit with the same state.
+func findDivergence(ctx context.Context, logger log.Logger, a, b *diffVM, checkEvery uint64) (*Divergence, error) {
+	if checkEvery == 0 {
+		return nil, fmt.Errorf("check interval must be at least 1 step")
+	}
+	if a.state.Step != b.state.Step {
+		return nil, fmt.Errorf("executions must start at the same step, but got %d and %d", a.state.Step, b.state.Step)
+	}
+	if equal, err := equalStates(a, b); err != nil {
+		return nil, err
+	} else if !equal {
+		return nil, fmt.Errorf("executions already differ at the start step %d", a.state.Step)
+	}
+
+	lo, snapA, snapB, err := snapshotBoth(a, b)
+	if err != nil {
+		return nil, err
+	}
+	var hi uint64
+	for {
+		hi = lo + checkEvery
+		if err := runBoth(ctx, a, b, hi); err != nil {
+			return nil, err
+		}
+		equal, err := equalStates(a, b)
+		if err != nil {
+			return nil, err
+		}
+		if !equal {
+			break
+		}
+		if a.state.Exited && b.state.Exited {
+			return nil, nil
+		}
+		logger.Info("executions match", "step", a.state.Step)
+		if lo, snapA, snapB, err = snapshotBoth(a, b); err != nil {
+			return nil, err
+		}
+	}
+
+	logger.Info("executions differ, searching first differing step", "matching", lo, "differing", hi)
+	for hi-lo > 1 {
+		mid := lo + (hi-lo)/2
+		if err := restoreBoth(a, b, snapA, snapB); err != nil {
+			return nil, err
+		}
+		if err := runBoth(ctx, a, b, mid); err != nil {
+			return nil, err
+		}
+		equal, err := equalStates(a, b)
+		if err != nil {
+			return nil, err
+		}
+		if equal {
+			if lo, snapA, snapB, err = snapshotBoth(a, b); err != nil {
+				return nil, err
+			}
+		} else {
+			hi = mid
+		}
+		logger.Debug("searching first differing step", "matching", lo, "differing", hi)
+	}
+
+	if err := restoreBoth(a, b, snapA, snapB); err != nil {
+		return nil, err
+	}
+	sideA, err := a.proveStep()
+	if err != nil {
+		return nil, err
+	}
+	sideB, err := b.proveStep()
+	if err != nil {
+		return nil, err
+	}
+	return &Divergence{Step: lo, A: sideA, B: sideB}, nil
+}
+
+func equalStates(a, b *diffVM) (bool, error) {
+	hashA, err := a.stateHash()
+	if err != nil {
+		return false, err
+	}
+	hashB, err := b.stateHash()
+	if err != nil {
+		return false, err
+	}
+	return hashA == hashB, nil
+}
+
+func runBoth(ctx context.Context, a, b *diffVM, step uint64) error {
+	if err := a.runTo(ctx, step); err != nil {
+		return err
+	}
+	return b.runTo(ctx, step)
+}
+
+func snapshotBoth(a, b *diffVM) (uint64, []byte, []byte, error) {
+	snapA, err := a.snapshot()
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	snapB, err := b.snapshot()
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	return a.state.Step, snapA, snapB, nil
+}
+
+func restoreBoth(a, b *diffVM, snapA, snapB []byte) error {
+	if err := a.restore(snapA); err != nil {
+		return err
+	}
+	return b.restore(snapB)
+}
+
+func loadDiffVM(l log.Logger, name string, inputPath string, metaPath string, po *ProcessPreimageOracle) (*diffVM, error) {
+	state, err := serialize.Load[mipsevm.State](inputPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load state %s: %w", name, err)
+	}
+	meta := &mipsevm.Metadata{}
+	if metaPath != "" {
+		if meta, err = serialize.Load[mipsevm.Metadata](metaPath); err != nil {
+			return nil, fmt.Errorf("failed to load metadata %s: %w", name, err)
+		}
+	}
+	vm := &diffVM{
+		name:   name,
+		meta:   meta,
+		oracle: po,
+		stdOut: &mipsevm.LoggingWriter{Name: "program " + name + " std-out", Log: l},
+		stdErr: &mipsevm.LoggingWriter{Name: "program " + name + " std-err", Log: l},
+	}
+	if po.cmd != nil {
+		vm.guard = func(fn StepFn) StepFn {
+			return Guard(po.cmd.ProcessState, fn)
+		}
+	}
+	vm.setState(state)
+	return vm, nil
+}
+
+func startPreimageOracle(l log.Logger, name string, args []string) (*ProcessPreimageOracle, func(), error) {
+	if len(args) == 0 {
+		args = []string{""}
+	}
+	po, err := NewProcessPreimageOracle(args[0], args[1:])
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create pre-image oracle process %s: %w", name, err)
+	}
+	if err := po.Start(); err != nil {
+		return nil, nil, fmt.Errorf("failed to start pre-image oracle server %s: %w", name, err)
+	}
+	return po, func() {
+		if err := po.Close(); err != nil {
+			l.Error("failed to close pre-image server", "name", name, "err", err)
+		}
+	}, nil
+}
+
+func Diff(ctx *cli.Context) error {
+	l := Logger(os.Stderr, log.LvlInfo)
+
+	// The pre-image server of A is the command after the first '--', and of B after the second '--'.
+	// B uses the same pre-image server command as A, if there is no second '--'.
+	args := ctx.Args().Slice()
+	for i, arg := range args {
+		if arg == "--" {
+			args = args[i+1:]
+			break
+		}
+	}
+	argsA, argsB := args, args
+	for i, arg := range args {
+		if arg == "--" {
+			argsA, argsB = args[:i], args[i+1:]
+			break
+		}
+	}
+
+	poA, closeA, err := startPreimageOracle(l, "a", argsA)
+	if err != nil {
+		return err
+	}
+	defer closeA()
+	poB, closeB, err := startPreimageOracle(l, "b", argsB)
+	if err != nil {
+		return err
+	}
+	defer closeB()
+
+	a, err := loadDiffVM(l, "a", ctx.Path(DiffInputAFlag.Name), ctx.Path(DiffMetaAFlag.Name), poA)
+	if err != nil {
+		return err
+	}
+	b, err := loadDiffVM(l, "b", ctx.Path(DiffInputBFlag.Name), ctx.Path(DiffMetaBFlag.Name), poB)
+	if err != nil {
+		return err
+	}
+
+	div, err := findDivergence(ctx.Context, l, a, b, ctx.Uint64(DiffCheckEveryFlag.Name))
+	if err != nil {
+		return err
+	}
+	if div == nil {
+		l.Info("executions do not differ", "step", a.state.Step, "exit", a.state.ExitCode)
+		return nil
+	}
+	for name, side := range map[string]DiffSide{"a": div.A, "b": div.B} {
+		l.Info("first differing step", "execution", name, "step", div.Step,
+			"pc", mipsevm.HexU32(side.PC), "insn", mipsevm.HexU32(side.Insn), "name", side.Symbol,
+			"pre", side.PreStateHash, "post", side.PostStateHash)
+	}
+	if err := serialize.Write(ctx.Path(DiffOutputFlag.Name), div); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	if proofFmt := ctx.String(DiffProofFmtFlag.Name); proofFmt != "" {
+		if err := serialize.Write(fmt.Sprintf(proofFmt, "a"), div.A.Proof); err != nil {
+			return fmt.Errorf("failed to write proof data: %w", err)
+		}
+		if err := serialize.Write(fmt.Sprintf(proofFmt, "b"), div.B.Proof); err != nil {
+			return fmt.Errorf("failed to write proof data: %w", err)
+		}
+	}
+	return nil
+}
+
+var DiffCommand = &cli.Command{
+	Name:        "diff",
+	Usage:       "Find the first step at which two VM executions differ.",
+	Description: "Run two VM executions in lockstep, e.g. with different pre-image servers or program builds, and find the first step at which their state hashes differ. The pre-image server command of A follows the first --, and of B the second --.",
+	Action:      Diff,
+	Flags: []cli.Flag{
+		DiffInputAFlag,
+		DiffInputBFlag,
+		DiffMetaAFlag,
+		DiffMetaBFlag,
+		DiffCheckEveryFlag,
+		DiffOutputFlag,
+		DiffProofFmtFlag,
+	},
+}
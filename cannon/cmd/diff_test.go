@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+)
+
+type staticOracle []byte
+
+func (o staticOracle) Hint(v []byte) {}
+
+func (o staticOracle) GetPreimage(k [32]byte) []byte {
+	return o
+}
+
+// diffTestVM creates an execution that spins for the given number of steps, then reads
+// 4 bytes of pre-image data from the oracle into memory, spins again, and exits.
+func diffTestVM(t *testing.T, name string, spin uint32, oracle mipsevm.PreimageOracle) *diffVM {
+	const start = 0x1000
+	var program []uint32
+	for i := uint32(0); i < spin; i++ {
+		program = append(program, 0x25080001) // addiu $t0, $t0, 1
+	}
+	program = append(program,
+		0x24020fa3, // addiu $v0, $zero, 4003 (read)
+		0x24040005, // addiu $a0, $zero, 5 (pre-image fd)
+		0x24050100, // addiu $a1, $zero, 0x100
+		0x24060004, // addiu $a2, $zero, 4
+		0x0000000c, // syscall
+	)
+	for i := uint32(0); i < spin; i++ {
+		program = append(program, 0x25080001) // addiu $t0, $t0, 1
+	}
+	program = append(program,
+		0x24021096, // addiu $v0, $zero, 4246 (exit_group)
+		0x24040000, // addiu $a0, $zero, 0
+		0x0000000c, // syscall
+	)
+
+	state := &mipsevm.State{
+		Memory:         mipsevm.NewMemory(),
+		PreimageKey:    [32]byte{0x02, 0xaa},
+		PreimageOffset: 8, // skip the length prefix
+		PC:             start,
+		NextPC:         start + 4,
+	}
+	for i, insn := range program {
+		state.Memory.SetMemory(start+uint32(i)*4, insn)
+	}
+	vm := &diffVM{
+		name:   name,
+		meta:   &mipsevm.Metadata{Symbols: []mipsevm.Symbol{{Name: "main.main", Start: start, Size: uint32(len(program)) * 4}}},
+		oracle: oracle,
+		stdOut: io.Discard,
+		stdErr: io.Discard,
+	}
+	vm.setState(state)
+	return vm
+}
+
+func TestFindDivergence(t *testing.T) {
+	logger := testlog.Logger(t, log.LvlInfo)
+	const spin = 50
+	// The read syscall is the 5th instruction after spinning.
+	const readStep = spin + 4
+
+	t.Run("Diverge", func(t *testing.T) {
+		for _, checkEvery := range []uint64{1, 7, 10, 1000} {
+			a := diffTestVM(t, "a", spin, staticOracle{0x11, 0x22, 0x33, 0x44})
+			b := diffTestVM(t, "b", spin, staticOracle{0x11, 0x22, 0x33, 0x55})
+			div, err := findDivergence(context.Background(), logger, a, b, checkEvery)
+			require.NoError(t, err)
+			require.NotNil(t, div)
+			require.Equal(t, uint64(readStep), div.Step, "check every %d", checkEvery)
+
+			require.Equal(t, div.A.PreStateHash, div.B.PreStateHash)
+			require.NotEqual(t, div.A.PostStateHash, div.B.PostStateHash)
+			for _, side := range []DiffSide{div.A, div.B} {
+				require.Equal(t, uint32(0x0000000c), side.Insn)
+				require.Equal(t, uint32(0x1000+readStep*4), side.PC)
+				require.Equal(t, "main.main", side.Symbol)
+				require.Equal(t, uint32(4), side.Registers[2], "v0 must be set to the bytes read")
+				require.Len(t, side.MemProof, 2*28*32)
+				require.Equal(t, uint64(readStep), side.Proof.Step)
+				require.Equal(t, side.PreStateHash, side.Proof.Pre)
+				require.Equal(t, side.PostStateHash, side.Proof.Post)
+				require.NotEmpty(t, side.Proof.OracleKey)
+			}
+			require.Equal(t, hexutil.Bytes{0x02, 0xaa}, div.A.Proof.OracleKey[:2])
+		}
+	})
+
+	t.Run("Equal", func(t *testing.T) {
+		a := diffTestVM(t, "a", spin, staticOracle{0x11, 0x22, 0x33, 0x44})
+		b := diffTestVM(t, "b", spin, staticOracle{0x11, 0x22, 0x33, 0x44})
+		div, err := findDivergence(context.Background(), logger, a, b, 7)
+		require.NoError(t, err)
+		require.Nil(t, div)
+		require.True(t, a.state.Exited)
+		require.True(t, b.state.Exited)
+	})
+
+	t.Run("DifferentStart", func(t *testing.T) {
+		a := diffTestVM(t, "a", spin, staticOracle{})
+		b := diffTestVM(t, "b", spin+1, staticOracle{})
+		_, err := findDivergence(context.Background(), logger, a, b, 7)
+		require.ErrorContains(t, err, "already differ at the start step 0")
+	})
+}
@@ -1,65 +0,0 @@
-package cmd
-
-import (
-	"encoding/json"
-	"errors"
-	"fmt"
-	"io"
-	"os"
-	"path"
-
-	"github.com/ethereum-optimism/optimism/op-service/ioutil"
-)
-
-func loadJSON[X any](inputPath string) (*X, error) {
-	if inputPath == "" {
-		return nil, errors.New("no path specified")
-	}
-	var f io.ReadCloser
-	f, err := ioutil.OpenDecompressed(inputPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to open file %q: %w", inputPath, err)
-	}
-	defer f.Close()
-	var state X
-	if err := json.NewDecoder(f).Decode(&state); err != nil {
-		return nil, fmt.Errorf("failed to decode file %q: %w", inputPath, err)
-	}
-	return &state, nil
-}
-
-func writeJSON[X any](outputPath string, value X) error {
-	if outputPath == "" {
-		return nil
-	}
-	var out io.Writer
-	finish := func() error { return nil }
-	if outputPath != "-" {
-		// Write to a tmp file but reserve the file extension if present
-		tmpPath := outputPath + "-tmp" + path.Ext(outputPath)
-		f, err := ioutil.OpenCompressed(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0755)
-		if err != nil {
-			return fmt.Errorf("failed to open output file: %w", err)
-		}
-		defer f.Close()
-		out = f
-		finish = func() error {
-			// Rename the file into place as atomically as the OS will allow
-			return os.Rename(tmpPath, outputPath)
-		}
-	} else {
-		out = os.Stdout
-	}
-	enc := json.NewEncoder(out)
-	if err := enc.Encode(value); err != nil {
-		return fmt.Errorf("failed to encode to JSON: %w", err)
-	}
-	_, err := out.Write([]byte{'\n'})
-	if err != nil {
-		return fmt.Errorf("failed to append new-line: %w", err)
-	}
-	if err := finish(); err != nil {
-		return fmt.Errorf("failed to finish write: %w", err)
-	}
-	return nil
-}
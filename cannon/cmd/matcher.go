@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"math"
 	"strconv"
 	"strings"
 
@@ -13,6 +14,8 @@ type StepMatcher func(st *mipsevm.State) bool
 type StepMatcherFlag struct {
 	repr    string
 	matcher StepMatcher
+	// next returns the first matching step at or after the given step
+	next func(step uint64) uint64
 }
 
 func MustStepMatcherFlag(pattern string) *StepMatcherFlag {
@@ -29,10 +32,14 @@ func (m *StepMatcherFlag) Set(value string) error {
 		m.matcher = func(st *mipsevm.State) bool {
 			return false
 		}
+		m.next = nil
 	} else if value == "always" {
 		m.matcher = func(st *mipsevm.State) bool {
 			return true
 		}
+		m.next = func(step uint64) uint64 {
+			return step
+		}
 	} else if strings.HasPrefix(value, "=") {
 		when, err := strconv.ParseUint(value[1:], 0, 64)
 		if err != nil {
@@ -41,6 +48,12 @@ func (m *StepMatcherFlag) Set(value string) error {
 		m.matcher = func(st *mipsevm.State) bool {
 			return st.Step == when
 		}
+		m.next = func(step uint64) uint64 {
+			if step > when {
+				return math.MaxUint64
+			}
+			return when
+		}
 	} else if strings.HasPrefix(value, "%") {
 		when, err := strconv.ParseUint(value[1:], 0, 64)
 		if err != nil {
@@ -49,6 +62,16 @@ func (m *StepMatcherFlag) Set(value string) error {
 		m.matcher = func(st *mipsevm.State) bool {
 			return st.Step%when == 0
 		}
+		m.next = func(step uint64) uint64 {
+			if step%when == 0 {
+				return step
+			}
+			next := step - step%when + when
+			if next < step { // overflow
+				return math.MaxUint64
+			}
+			return next
+		}
 	} else {
 		return fmt.Errorf("unrecognized step matcher: %q", value)
 	}
@@ -68,6 +91,15 @@ func (m *StepMatcherFlag) Matcher() StepMatcher {
 	return m.matcher
 }
 
+// NextMatch returns the first step at or after the given step that matches,
+// or math.MaxUint64 if no later step matches.
+func (m *StepMatcherFlag) NextMatch(step uint64) uint64 {
+	if m.next == nil {
+		return math.MaxUint64
+	}
+	return m.next(step)
+}
+
 func (m *StepMatcherFlag) Clone() any {
 	var out StepMatcherFlag
 	if err := out.Set(m.repr); err != nil {
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm"
+)
+
+func TestStepMatcherNextMatch(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		step     uint64
+		expected uint64
+	}{
+		{"never", 10, math.MaxUint64},
+		{"", 10, math.MaxUint64},
+		{"always", 10, 10},
+		{"=100", 10, 100},
+		{"=100", 100, 100},
+		{"=100", 101, math.MaxUint64},
+		{"%100", 0, 0},
+		{"%100", 1, 100},
+		{"%100", 200, 200},
+		{"%100", 201, 300},
+		{"%100", math.MaxUint64 - 10, math.MaxUint64},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.pattern, func(t *testing.T) {
+			m := MustStepMatcherFlag(test.pattern)
+			next := m.NextMatch(test.step)
+			require.Equal(t, test.expected, next, "next match of %q after %d", test.pattern, test.step)
+			if next != math.MaxUint64 {
+				require.True(t, m.Matcher()(&mipsevm.State{Step: next}), "next match must match")
+			}
+		})
+	}
+
+	t.Run("Unset", func(t *testing.T) {
+		require.Equal(t, uint64(math.MaxUint64), new(StepMatcherFlag).NextMatch(0))
+	})
+}
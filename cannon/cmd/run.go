@@ -8,27 +8,28 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/urfave/cli/v2"
 
 	"github.com/pkg/profile"
 
 	"github.com/ethereum-optimism/optimism/cannon/mipsevm"
+	"github.com/ethereum-optimism/optimism/cannon/profiler"
+	"github.com/ethereum-optimism/optimism/cannon/serialize"
 	preimage "github.com/ethereum-optimism/optimism/op-preimage"
 )
 
 var (
 	RunInputFlag = &cli.PathFlag{
 		Name:      "input",
-		Usage:     "path of input JSON state. Stdin if left empty.",
+		Usage:     "path of input state, either JSON or binary.",
 		TakesFile: true,
 		Value:     "state.json",
 		Required:  true,
 	}
 	RunOutputFlag = &cli.PathFlag{
 		Name:      "output",
-		Usage:     "path of output JSON state. Not written if empty, use - to write to Stdout.",
+		Usage:     "path of output state. Written in binary if the path ends in .bin or .bin.gz, JSON otherwise. Not written if empty, use - to write JSON to Stdout.",
 		TakesFile: true,
 		Value:     "out.json",
 		Required:  false,
@@ -42,7 +43,7 @@ var (
 	}
 	RunProofFmtFlag = &cli.StringFlag{
 		Name:     "proof-fmt",
-		Usage:    "format for proof data output file names. Proof data is written to stdout if -.",
+		Usage:    "format for proof data output file names. Proofs are written in binary if the format ends in .bin or .bin.gz, JSON otherwise. Proof data is written to stdout if -.",
 		Value:    "proof-%d.json",
 		Required: false,
 	}
@@ -54,7 +55,7 @@ var (
 	}
 	RunSnapshotFmtFlag = &cli.StringFlag{
 		Name:     "snapshot-fmt",
-		Usage:    "format for snapshot output file names.",
+		Usage:    "format for snapshot output file names. Snapshots are written in binary if the format ends in .bin or .bin.gz, JSON otherwise.",
 		Value:    "state-%d.json",
 		Required: false,
 	}
@@ -80,20 +81,36 @@ var (
 		Name:  "pprof.cpu",
 		Usage: "enable pprof cpu profiling",
 	}
+	RunPProfGuest = &cli.PathFlag{
+		Name:      "pprof.guest",
+		Usage:     "path to write a pprof profile of the MIPS program to, with symbols from the metadata. Disabled if empty.",
+		TakesFile: true,
+	}
+	RunPProfGuestInterval = &cli.Uint64Flag{
+		Name:  "pprof.guest-interval",
+		Usage: "number of steps between samples of the PC of the MIPS program, for --pprof.guest.",
+		Value: 1000,
+	}
 )
 
-type Proof struct {
-	Step uint64 `json:"step"`
-
-	Pre  common.Hash `json:"pre"`
-	Post common.Hash `json:"post"`
-
-	StateData hexutil.Bytes `json:"state-data"`
-	ProofData hexutil.Bytes `json:"proof-data"`
-
-	OracleKey    hexutil.Bytes `json:"oracle-key,omitempty"`
-	OracleValue  hexutil.Bytes `json:"oracle-value,omitempty"`
-	OracleOffset uint32        `json:"oracle-offset,omitempty"`
+// Proof is the proof data of a step, written at every step matching --proof-at.
+type Proof = mipsevm.Proof
+
+// NewProof creates the proof of the given step, from the witness of the step.
+func NewProof(step uint64, pre, post common.Hash, witness *mipsevm.StepWitness) *Proof {
+	proof := &Proof{
+		Step:      step,
+		Pre:       pre,
+		Post:      post,
+		StateData: witness.State,
+		ProofData: witness.MemProof,
+	}
+	if witness.HasPreimage() {
+		proof.OracleKey = witness.PreimageKey[:]
+		proof.OracleValue = witness.PreimageValue
+		proof.OracleOffset = witness.PreimageOffset
+	}
+	return proof
 }
 
 type rawHint string
@@ -118,6 +135,10 @@ type ProcessPreimageOracle struct {
 
 const clientPollTimeout = time.Second * 15
 
+// fastForwardInterval is the max number of steps that are executed in fast-forward mode at once,
+// between checks of the context and of the program getting stuck.
+const fastForwardInterval = 100_000
+
 func NewProcessPreimageOracle(name string, args []string) (*ProcessPreimageOracle, error) {
 	if name == "" {
 		return &ProcessPreimageOracle{}, nil
@@ -207,16 +228,31 @@ func Guard(proc *os.ProcessState, fn StepFn) StepFn {
 	return func(proof bool) (*mipsevm.StepWitness, error) {
 		wit, err := fn(proof)
 		if err != nil {
-			if proc.Exited() {
-				return nil, fmt.Errorf("pre-image server exited with code %d, resulting in err %w", proc.ExitCode(), err)
-			} else {
-				return nil, err
-			}
+			return nil, guardErr(proc, err)
 		}
 		return wit, nil
 	}
 }
 
+type FastForwardFn func(until uint64) error
+
+// GuardFastForward is like Guard, but for fast-forwarding.
+func GuardFastForward(proc *os.ProcessState, fn FastForwardFn) FastForwardFn {
+	return func(until uint64) error {
+		if err := fn(until); err != nil {
+			return guardErr(proc, err)
+		}
+		return nil
+	}
+}
+
+func guardErr(proc *os.ProcessState, err error) error {
+	if proc.Exited() {
+		return fmt.Errorf("pre-image server exited with code %d, resulting in err %w", proc.ExitCode(), err)
+	}
+	return err
+}
+
 var _ mipsevm.PreimageOracle = (*ProcessPreimageOracle)(nil)
 
 func Run(ctx *cli.Context) error {
@@ -224,7 +260,7 @@ func Run(ctx *cli.Context) error {
 		defer profile.Start(profile.NoShutdownHook, profile.ProfilePath("."), profile.CPUProfile).Stop()
 	}
 
-	state, err := loadJSON[mipsevm.State](ctx.Path(RunInputFlag.Name))
+	state, err := serialize.Load[mipsevm.State](ctx.Path(RunInputFlag.Name))
 	if err != nil {
 		return err
 	}
@@ -258,17 +294,31 @@ func Run(ctx *cli.Context) error {
 		}
 	}()
 
-	stopAt := ctx.Generic(RunStopAtFlag.Name).(*StepMatcherFlag).Matcher()
-	proofAt := ctx.Generic(RunProofAtFlag.Name).(*StepMatcherFlag).Matcher()
-	snapshotAt := ctx.Generic(RunSnapshotAtFlag.Name).(*StepMatcherFlag).Matcher()
-	infoAt := ctx.Generic(RunInfoAtFlag.Name).(*StepMatcherFlag).Matcher()
+	stopAtFlag := ctx.Generic(RunStopAtFlag.Name).(*StepMatcherFlag)
+	proofAtFlag := ctx.Generic(RunProofAtFlag.Name).(*StepMatcherFlag)
+	snapshotAtFlag := ctx.Generic(RunSnapshotAtFlag.Name).(*StepMatcherFlag)
+	infoAtFlag := ctx.Generic(RunInfoAtFlag.Name).(*StepMatcherFlag)
+	stopAt := stopAtFlag.Matcher()
+	proofAt := proofAtFlag.Matcher()
+	snapshotAt := snapshotAtFlag.Matcher()
+	infoAt := infoAtFlag.Matcher()
+	// nextCheck returns the first step, after the given step, at which any of the step patterns may match.
+	nextCheck := func(step uint64) uint64 {
+		next := (step/fastForwardInterval + 1) * fastForwardInterval
+		for _, f := range []*StepMatcherFlag{stopAtFlag, proofAtFlag, snapshotAtFlag, infoAtFlag} {
+			if n := f.NextMatch(step + 1); n < next {
+				next = n
+			}
+		}
+		return next
+	}
 
 	var meta *mipsevm.Metadata
 	if metaPath := ctx.Path(RunMetaFlag.Name); metaPath == "" {
 		l.Info("no metadata file specified, defaulting to empty metadata")
 		meta = &mipsevm.Metadata{Symbols: nil} // provide empty metadata by default
 	} else {
-		if m, err := loadJSON[mipsevm.Metadata](metaPath); err != nil {
+		if m, err := serialize.Load[mipsevm.Metadata](metaPath); err != nil {
 			return fmt.Errorf("failed to load metadata: %w", err)
 		} else {
 			meta = m
@@ -279,19 +329,39 @@ func Run(ctx *cli.Context) error {
 	proofFmt := ctx.String(RunProofFmtFlag.Name)
 	snapshotFmt := ctx.String(RunSnapshotFmtFlag.Name)
 
+	// avoid symbol lookups every instruction by preparing a matcher func
+	sleepCheck := meta.SymbolMatcher("runtime.notesleep")
+
 	stepFn := us.Step
+	// Fast-forwarding stops at the Go sleep, so the loop below detects the program getting stuck.
+	fastForwardFn := func(until uint64) error {
+		return us.FastForward(until, sleepCheck)
+	}
 	if po.cmd != nil {
 		stepFn = Guard(po.cmd.ProcessState, stepFn)
+		fastForwardFn = GuardFastForward(po.cmd.ProcessState, fastForwardFn)
+	}
+
+	// Steps between the steps that match any of the step patterns are executed in fast-forward mode,
+	// unless every step is profiled.
+	fastForward := true
+	if profPath := ctx.Path(RunPProfGuest.Name); profPath != "" {
+		fastForward = false
+		prof := profiler.NewProfiler(meta, ctx.Uint64(RunPProfGuestInterval.Name))
+		stepFn = prof.Wrap(state, stepFn)
+		defer func() {
+			if err := writeGuestProfile(l, prof, profPath); err != nil {
+				l.Error("failed to write guest profile", "err", err)
+			}
+		}()
 	}
 
 	start := time.Now()
 	startStep := state.Step
 
-	// avoid symbol lookups every instruction by preparing a matcher func
-	sleepCheck := meta.SymbolMatcher("runtime.notesleep")
-
 	for !state.Exited {
-		if state.Step%100 == 0 { // don't do the ctx err check (includes lock) too often
+		// don't do the ctx err check (includes lock) too often, unless many steps are fast-forwarded at once
+		if fastForward || state.Step%100 == 0 {
 			if err := ctx.Context.Err(); err != nil {
 				return err
 			}
@@ -321,7 +391,7 @@ func Run(ctx *cli.Context) error {
 		}
 
 		if snapshotAt(state) {
-			if err := writeJSON(fmt.Sprintf(snapshotFmt, step), state); err != nil {
+			if err := serialize.Write(fmt.Sprintf(snapshotFmt, step), state); err != nil {
 				return fmt.Errorf("failed to write state snapshot: %w", err)
 			}
 		}
@@ -339,21 +409,15 @@ func Run(ctx *cli.Context) error {
 			if err != nil {
 				return fmt.Errorf("failed to hash poststate witness: %w", err)
 			}
-			proof := &Proof{
-				Step:      step,
-				Pre:       preStateHash,
-				Post:      postStateHash,
-				StateData: witness.State,
-				ProofData: witness.MemProof,
-			}
-			if witness.HasPreimage() {
-				proof.OracleKey = witness.PreimageKey[:]
-				proof.OracleValue = witness.PreimageValue
-				proof.OracleOffset = witness.PreimageOffset
-			}
-			if err := writeJSON(fmt.Sprintf(proofFmt, step), proof); err != nil {
+			proof := NewProof(step, preStateHash, postStateHash, witness)
+			if err := serialize.Write(fmt.Sprintf(proofFmt, step), proof); err != nil {
 				return fmt.Errorf("failed to write proof data: %w", err)
 			}
+		} else if fastForward {
+			// Execute this step, and fast-forward until the next step that must be checked.
+			if err := fastForwardFn(nextCheck(step)); err != nil {
+				return fmt.Errorf("failed at step %d (PC: %08x) while fast-forwarding: %w", state.Step, state.PC, err)
+			}
 		} else {
 			_, err = stepFn(false)
 			if err != nil {
@@ -362,12 +426,43 @@ func Run(ctx *cli.Context) error {
 		}
 	}
 
-	if err := writeJSON(ctx.Path(RunOutputFlag.Name), state); err != nil {
+	if err := serialize.Write(ctx.Path(RunOutputFlag.Name), state); err != nil {
 		return fmt.Errorf("failed to write state output: %w", err)
 	}
 	return nil
 }
 
+// writeGuestProfile writes the pprof profile, and logs the hot symbols, syscalls and pre-image reads.
+func writeGuestProfile(l log.Logger, prof *profiler.Profiler, path string) error {
+	hot := prof.HotSymbols(0)
+	var total uint64
+	for _, s := range hot {
+		total += s.Steps
+	}
+	for i, s := range hot {
+		if i == 20 {
+			break
+		}
+		l.Info("hot symbol", "rank", i+1, "name", s.Name, "steps", s.Steps, "percent", fmt.Sprintf("%.2f", float64(s.Steps)*100/float64(total)))
+	}
+	for num, count := range prof.Syscalls() {
+		l.Info("syscall", "num", num, "calls", count)
+	}
+	for keyType, stats := range prof.PreimageReads() {
+		l.Info("pre-image reads", "type", profiler.KeyTypeName(keyType), "preimages", stats.Preimages, "reads", stats.Reads, "bytes", stats.Bytes)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create profile file: %w", err)
+	}
+	defer f.Close()
+	if err := prof.Profile().Write(f); err != nil {
+		return fmt.Errorf("failed to write profile: %w", err)
+	}
+	return f.Close()
+}
+
 var RunCommand = &cli.Command{
 	Name:        "run",
 	Usage:       "Run VM step(s) and generate proof data to replicate onchain.",
@@ -384,5 +479,7 @@ var RunCommand = &cli.Command{
 		RunMetaFlag,
 		RunInfoAtFlag,
 		RunPProfCPU,
+		RunPProfGuest,
+		RunPProfGuestInterval,
 	},
 }
@@ -5,13 +5,14 @@ import (
 	"os"
 
 	"github.com/ethereum-optimism/optimism/cannon/mipsevm"
+	"github.com/ethereum-optimism/optimism/cannon/serialize"
 	"github.com/urfave/cli/v2"
 )
 
 var (
 	WitnessInputFlag = &cli.PathFlag{
 		Name:      "input",
-		Usage:     "path of input JSON state.",
+		Usage:     "path of input state, either JSON or binary.",
 		TakesFile: true,
 		Required:  true,
 	}
@@ -25,7 +26,7 @@ var (
 func Witness(ctx *cli.Context) error {
 	input := ctx.Path(WitnessInputFlag.Name)
 	output := ctx.Path(WitnessOutputFlag.Name)
-	state, err := loadJSON[mipsevm.State](input)
+	state, err := serialize.Load[mipsevm.State](input)
 	if err != nil {
 		return fmt.Errorf("invalid input state (%v): %w", input, err)
 	}
@@ -45,8 +46,8 @@ func Witness(ctx *cli.Context) error {
 
 var WitnessCommand = &cli.Command{
 	Name:        "witness",
-	Usage:       "Convert a Cannon JSON state into a binary witness",
-	Description: "Convert a Cannon JSON state into a binary witness. The hash of the witness is written to stdout",
+	Usage:       "Convert a Cannon state into a binary witness",
+	Description: "Convert a Cannon JSON or binary state into a binary witness. The hash of the witness is written to stdout",
 	Action:      Witness,
 	Flags: []cli.Flag{
 		WitnessInputFlag,
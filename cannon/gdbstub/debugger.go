@@ -0,0 +1,246 @@
+package gdbstub
+
+import (
+	"fmt"
+
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm"
+)
+
+// Signals reported to the debugger client in stop replies.
+const (
+	sigINT  = 0x02
+	sigILL  = 0x04
+	sigTRAP = 0x05
+)
+
+// Syscall numbers with memory accesses that are visible to watchpoints, see mipsevm.
+const (
+	sysRead  = 4003
+	sysWrite = 4004
+)
+
+// WatchKind is the kind of memory access a watchpoint triggers on.
+type WatchKind uint8
+
+const (
+	WatchWrite WatchKind = iota
+	WatchRead
+	WatchAccess
+)
+
+func (k WatchKind) String() string {
+	switch k {
+	case WatchWrite:
+		return "watch"
+	case WatchRead:
+		return "rwatch"
+	case WatchAccess:
+		return "awatch"
+	default:
+		return fmt.Sprintf("WatchKind(%d)", uint8(k))
+	}
+}
+
+// Watchpoint stops execution at accesses of Size bytes of memory, starting at Addr.
+type Watchpoint struct {
+	Addr uint32
+	Size uint32
+	Kind WatchKind
+}
+
+func (w Watchpoint) matches(addr, size uint32, write bool) bool {
+	if addr >= w.Addr+w.Size || w.Addr >= addr+size {
+		return false
+	}
+	switch w.Kind {
+	case WatchWrite:
+		return write
+	case WatchRead:
+		return !write
+	default:
+		return true
+	}
+}
+
+// StepFn executes a single instruction, see mipsevm.InstrumentedState.Step.
+type StepFn func(proof bool) (*mipsevm.StepWitness, error)
+
+// StopReason describes why execution stopped.
+type StopReason struct {
+	// Signal is the signal to report, if the program did not exit.
+	Signal uint8
+	// Exited is true if the program exited, with ExitCode.
+	Exited   bool
+	ExitCode uint8
+	// Watch is the watchpoint that was hit, if any.
+	Watch     *Watchpoint
+	WatchAddr uint32
+	// Err is the error that stopped execution, if any.
+	Err error
+}
+
+// Debugger controls the execution of a VM state, and stops at breakpoints and watchpoints.
+type Debugger struct {
+	state *mipsevm.State
+	step  StepFn
+	meta  *mipsevm.Metadata
+
+	breakpoints       map[uint32]struct{}
+	symbolBreakpoints map[string]func(addr uint32) bool
+	watchpoints       []Watchpoint
+}
+
+func NewDebugger(state *mipsevm.State, step StepFn, meta *mipsevm.Metadata) *Debugger {
+	if meta == nil {
+		meta = &mipsevm.Metadata{}
+	}
+	return &Debugger{
+		state:             state,
+		step:              step,
+		meta:              meta,
+		breakpoints:       make(map[uint32]struct{}),
+		symbolBreakpoints: make(map[string]func(addr uint32) bool),
+	}
+}
+
+func (d *Debugger) State() *mipsevm.State {
+	return d.state
+}
+
+func (d *Debugger) AddBreakpoint(addr uint32) {
+	d.breakpoints[addr] = struct{}{}
+}
+
+func (d *Debugger) RemoveBreakpoint(addr uint32) {
+	delete(d.breakpoints, addr)
+}
+
+// AddSymbolBreakpoint stops execution at any instruction within the given symbol.
+// An error is returned if the symbol is not in the metadata.
+func (d *Debugger) AddSymbolBreakpoint(name string) error {
+	if !d.hasSymbol(name) {
+		return fmt.Errorf("unknown symbol %q", name)
+	}
+	d.symbolBreakpoints[name] = d.meta.SymbolMatcher(name)
+	return nil
+}
+
+func (d *Debugger) RemoveSymbolBreakpoint(name string) {
+	delete(d.symbolBreakpoints, name)
+}
+
+func (d *Debugger) hasSymbol(name string) bool {
+	for _, s := range d.meta.Symbols {
+		if s.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *Debugger) AddWatchpoint(addr, size uint32, kind WatchKind) {
+	d.watchpoints = append(d.watchpoints, Watchpoint{Addr: addr, Size: size, Kind: kind})
+}
+
+func (d *Debugger) RemoveWatchpoint(addr, size uint32, kind WatchKind) {
+	for i, w := range d.watchpoints {
+		if w.Addr == addr && w.Size == size && w.Kind == kind {
+			d.watchpoints = append(d.watchpoints[:i], d.watchpoints[i+1:]...)
+			return
+		}
+	}
+}
+
+// atBreakpoint returns true if the current instruction has a breakpoint.
+func (d *Debugger) atBreakpoint() bool {
+	if _, ok := d.breakpoints[d.state.PC]; ok {
+		return true
+	}
+	for _, match := range d.symbolBreakpoints {
+		if match(d.state.PC) {
+			return true
+		}
+	}
+	return false
+}
+
+// Step executes a single instruction.
+func (d *Debugger) Step() StopReason {
+	if d.state.Exited {
+		return d.exitReason()
+	}
+	watch, watchAddr := d.checkWatchpoints()
+	if _, err := d.step(false); err != nil {
+		return StopReason{Signal: sigILL, Err: fmt.Errorf("failed at step %d (PC: %08x): %w", d.state.Step, d.state.PC, err)}
+	}
+	if d.state.Exited {
+		return d.exitReason()
+	}
+	return StopReason{Signal: sigTRAP, Watch: watch, WatchAddr: watchAddr}
+}
+
+// Continue executes instructions until a breakpoint or watchpoint is hit, the program exits,
+// or interrupted returns true. A breakpoint at the current instruction is not hit,
+// so execution can continue after stopping at a breakpoint.
+func (d *Debugger) Continue(interrupted func() bool) StopReason {
+	for i := 0; ; i++ {
+		if i > 0 && d.atBreakpoint() {
+			return StopReason{Signal: sigTRAP}
+		}
+		if i%1000 == 0 && interrupted() {
+			return StopReason{Signal: sigINT}
+		}
+		reason := d.Step()
+		if reason.Signal != sigTRAP || reason.Watch != nil {
+			return reason
+		}
+	}
+}
+
+func (d *Debugger) exitReason() StopReason {
+	return StopReason{Exited: true, ExitCode: d.state.ExitCode}
+}
+
+// checkWatchpoints returns the first watchpoint that is triggered by the current instruction.
+func (d *Debugger) checkWatchpoints() (*Watchpoint, uint32) {
+	if len(d.watchpoints) == 0 {
+		return nil, 0
+	}
+	addr, size, write, ok := memAccess(d.state)
+	if !ok {
+		return nil, 0
+	}
+	for i := range d.watchpoints {
+		if d.watchpoints[i].matches(addr, size, write) {
+			w := d.watchpoints[i]
+			return &w, addr
+		}
+	}
+	return nil, 0
+}
+
+// memAccess decodes the memory access of the current instruction, like mipsevm executes it.
+// Only memory that is written or read by the program itself is considered.
+func memAccess(state *mipsevm.State) (addr uint32, size uint32, write bool, ok bool) {
+	insn := state.Memory.GetMemory(state.PC)
+	opcode := insn >> 26
+	if opcode >= 0x20 {
+		rs := state.Registers[(insn>>21)&0x1F]
+		addr = (rs + mipsevm.SE(insn&0xFFFF, 16)) & 0xFFFFFFFC
+		return addr, 4, opcode >= 0x28 && opcode != 0x30, true
+	}
+	if opcode == 0 && insn&0x3f == 0xC { // syscall
+		a0, a1, a2 := state.Registers[4], state.Registers[5], state.Registers[6]
+		switch state.Registers[2] {
+		case sysRead:
+			if a0 == 5 { // pre-image data is read into memory
+				return a1 & 0xFFFFFFFC, 4, true, true
+			}
+		case sysWrite:
+			if a2 > 0 {
+				return a1, a2, false, true
+			}
+		}
+	}
+	return 0, 0, false, false
+}
@@ -0,0 +1,421 @@
+package gdbstub
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// interruptByte is sent by the client, outside of a packet, to interrupt execution.
+const interruptByte = 0x03
+
+// maxPacketSize is the maximum packet size that is advertised to the client.
+const maxPacketSize = 0x4000
+
+// Register numbers of the MIPS target, in the order of the GDB g-packet.
+// Floating point registers are not supported, and omitted from the g-packet.
+const (
+	regSR    = 32
+	regLO    = 33
+	regHI    = 34
+	regBad   = 35
+	regCause = 36
+	regPC    = 37
+	numRegs  = 38
+)
+
+var (
+	errDetached = errors.New("debugger detached")
+	errKilled   = errors.New("program killed by debugger")
+)
+
+// Server serves the GDB remote serial protocol for a Debugger.
+// Only one client is served at a time.
+type Server struct {
+	log log.Logger
+	dbg *Debugger
+}
+
+func NewServer(logger log.Logger, dbg *Debugger) *Server {
+	return &Server{log: logger, dbg: dbg}
+}
+
+// ListenAndServe accepts clients on the given TCP address, until a client kills the program,
+// a client detaches after the program exited, or the context is done.
+func (s *Server) ListenAndServe(ctx context.Context, addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %v: %w", addr, err)
+	}
+	defer listener.Close()
+	go func() {
+		<-ctx.Done()
+		_ = listener.Close()
+	}()
+	s.log.Info("waiting for debugger to attach", "addr", listener.Addr())
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("failed to accept debugger connection: %w", err)
+		}
+		s.log.Info("debugger attached", "remote", conn.RemoteAddr())
+		err = s.ServeConn(ctx, conn)
+		if errors.Is(err, errKilled) {
+			s.log.Info("program killed by debugger")
+			return nil
+		}
+		if !errors.Is(err, errDetached) {
+			return err
+		}
+		if s.dbg.State().Exited {
+			s.log.Info("debugger detached after program exit")
+			return nil
+		}
+		s.log.Info("debugger detached, waiting for new connection")
+	}
+}
+
+// ServeConn serves a single client, until it detaches or kills the program.
+func (s *Server) ServeConn(ctx context.Context, rwc io.ReadWriteCloser) error {
+	defer rwc.Close()
+	c := newConn(rwc)
+	defer close(c.done)
+	go c.readLoop()
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = rwc.Close()
+		case <-c.done:
+		}
+	}()
+
+	for {
+		var pkt string
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-c.readErr:
+			if errors.Is(err, io.EOF) || errors.Is(err, net.ErrClosed) {
+				return errDetached
+			}
+			return fmt.Errorf("failed to read from debugger: %w", err)
+		case pkt = <-c.packets:
+		}
+		reply, err := s.handle(ctx, c, pkt)
+		if errors.Is(err, errKilled) {
+			return err // no reply is sent for kill requests
+		}
+		if sendErr := c.send(reply); sendErr != nil {
+			return fmt.Errorf("failed to write to debugger: %w", sendErr)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// handle processes a single packet, and returns the reply.
+// Unsupported packets get an empty reply, as specified by the protocol.
+func (s *Server) handle(ctx context.Context, c *conn, pkt string) (string, error) {
+	st := s.dbg.State()
+	switch {
+	case pkt == "?":
+		if st.Exited {
+			return fmt.Sprintf("W%02x", st.ExitCode), nil
+		}
+		return fmt.Sprintf("S%02x", sigTRAP), nil
+	case strings.HasPrefix(pkt, "qSupported"):
+		return fmt.Sprintf("PacketSize=%x;QStartNoAckMode+;swbreak+;hwbreak+", maxPacketSize), nil
+	case pkt == "QStartNoAckMode":
+		c.noAck.Store(true)
+		return "OK", nil
+	case pkt == "qAttached":
+		return "1", nil
+	case pkt == "qC":
+		return "QC1", nil
+	case pkt == "qfThreadInfo":
+		return "m1", nil
+	case pkt == "qsThreadInfo":
+		return "l", nil
+	case strings.HasPrefix(pkt, "H"):
+		return "OK", nil
+	case strings.HasPrefix(pkt, "T"): // thread alive
+		return "OK", nil
+	case strings.HasPrefix(pkt, "qRcmd,"):
+		return s.monitorCommand(c, pkt[len("qRcmd,"):])
+	case pkt == "g":
+		var out strings.Builder
+		for i := 0; i < numRegs; i++ {
+			out.WriteString(encodeReg(s.register(i)))
+		}
+		return out.String(), nil
+	case strings.HasPrefix(pkt, "p"):
+		n, err := strconv.ParseUint(pkt[1:], 16, 32)
+		if err != nil {
+			return "E01", nil
+		}
+		if n >= numRegs {
+			// Registers that are not available are reported as such, e.g. floating point registers.
+			return "xxxxxxxx", nil
+		}
+		return encodeReg(s.register(int(n))), nil
+	case strings.HasPrefix(pkt, "m"):
+		addr, length, err := parseAddrLen(pkt[1:])
+		if err != nil {
+			return "E01", nil
+		}
+		if length > maxPacketSize/2 {
+			length = maxPacketSize / 2
+		}
+		return hex.EncodeToString(s.readMemory(addr, length)), nil
+	case strings.HasPrefix(pkt, "s"):
+		if len(pkt) > 1 {
+			return "E01", nil // resuming at a different address is not supported
+		}
+		return s.stopReply(s.dbg.Step()), nil
+	case strings.HasPrefix(pkt, "c"):
+		if len(pkt) > 1 {
+			return "E01", nil
+		}
+		return s.stopReply(s.dbg.Continue(func() bool {
+			return ctx.Err() != nil || c.interrupted.Swap(false)
+		})), nil
+	case strings.HasPrefix(pkt, "Z") || strings.HasPrefix(pkt, "z"):
+		return s.handleBreakpoint(pkt), nil
+	case pkt == "D" || strings.HasPrefix(pkt, "D;"):
+		return "OK", errDetached
+	case pkt == "k":
+		return "", errKilled
+	default:
+		return "", nil
+	}
+}
+
+func (s *Server) register(n int) uint32 {
+	st := s.dbg.State()
+	switch {
+	case n < 32:
+		return st.Registers[n]
+	case n == regLO:
+		return st.LO
+	case n == regHI:
+		return st.HI
+	case n == regPC:
+		return st.PC
+	default: // regSR, regBad, regCause are not part of the VM
+		return 0
+	}
+}
+
+func (s *Server) readMemory(addr, length uint32) []byte {
+	mem := s.dbg.State().Memory
+	out := make([]byte, 0, length)
+	var word [4]byte
+	for i := uint32(0); i < length; i++ {
+		a := addr + i
+		if i == 0 || a&3 == 0 {
+			binary.BigEndian.PutUint32(word[:], mem.GetMemory(a&^3))
+		}
+		out = append(out, word[a&3])
+	}
+	return out
+}
+
+// handleBreakpoint processes Z (insert) and z (remove) packets: type,addr,kind.
+func (s *Server) handleBreakpoint(pkt string) string {
+	insert := pkt[0] == 'Z'
+	parts := strings.SplitN(pkt[1:], ",", 2)
+	if len(parts) != 2 {
+		return "E01"
+	}
+	addr, length, err := parseAddrLen(strings.SplitN(parts[1], ";", 2)[0])
+	if err != nil {
+		return "E01"
+	}
+	switch parts[0] {
+	case "0", "1": // software and hardware breakpoints are equivalent
+		if insert {
+			s.dbg.AddBreakpoint(addr)
+		} else {
+			s.dbg.RemoveBreakpoint(addr)
+		}
+	case "2", "3", "4":
+		kind := map[string]WatchKind{"2": WatchWrite, "3": WatchRead, "4": WatchAccess}[parts[0]]
+		if insert {
+			s.dbg.AddWatchpoint(addr, length, kind)
+		} else {
+			s.dbg.RemoveWatchpoint(addr, length, kind)
+		}
+	default:
+		return ""
+	}
+	return "OK"
+}
+
+// monitorCommand handles the commands sent with "monitor <cmd>" in gdb.
+func (s *Server) monitorCommand(c *conn, hexCmd string) (string, error) {
+	cmd, err := hex.DecodeString(hexCmd)
+	if err != nil {
+		return "E01", nil
+	}
+	args := strings.Fields(string(cmd))
+	var out string
+	switch {
+	case len(args) == 2 && args[0] == "break":
+		if err := s.dbg.AddSymbolBreakpoint(args[1]); err != nil {
+			out = err.Error() + "\n"
+		} else {
+			out = fmt.Sprintf("breakpoint at symbol %s\n", args[1])
+		}
+	case len(args) == 2 && args[0] == "delete":
+		s.dbg.RemoveSymbolBreakpoint(args[1])
+		out = fmt.Sprintf("deleted breakpoint at symbol %s\n", args[1])
+	case len(args) == 1 && args[0] == "info":
+		st := s.dbg.State()
+		out = fmt.Sprintf("step: %d\npc: %08x (%s)\nheap: %08x\npages: %d\n",
+			st.Step, st.PC, s.dbg.meta.LookupSymbol(st.PC), st.Heap, st.Memory.PageCount())
+	default:
+		out = "commands: break <symbol>, delete <symbol>, info\n"
+	}
+	if err := c.send("O" + hex.EncodeToString([]byte(out))); err != nil {
+		return "", err
+	}
+	return "OK", nil
+}
+
+func (s *Server) stopReply(reason StopReason) string {
+	if reason.Err != nil {
+		s.log.Error("execution stopped with error", "err", reason.Err)
+	}
+	if reason.Exited {
+		return fmt.Sprintf("W%02x", reason.ExitCode)
+	}
+	if reason.Watch != nil {
+		return fmt.Sprintf("T%02x%s:%x;", reason.Signal, reason.Watch.Kind, reason.WatchAddr)
+	}
+	return fmt.Sprintf("S%02x", reason.Signal)
+}
+
+func encodeReg(v uint32) string {
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], v) // MIPS target is big-endian
+	return hex.EncodeToString(b[:])
+}
+
+func parseAddrLen(s string) (uint32, uint32, error) {
+	addrStr, lenStr, ok := strings.Cut(s, ",")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid addr,length: %q", s)
+	}
+	addr, err := strconv.ParseUint(addrStr, 16, 32)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid address: %w", err)
+	}
+	length, err := strconv.ParseUint(lenStr, 16, 32)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid length: %w", err)
+	}
+	return uint32(addr), uint32(length), nil
+}
+
+// conn handles the packet framing of the protocol.
+type conn struct {
+	rw io.ReadWriter
+
+	packets     chan string
+	readErr     chan error
+	done        chan struct{}
+	interrupted atomic.Bool
+	noAck       atomic.Bool
+}
+
+func newConn(rw io.ReadWriter) *conn {
+	return &conn{
+		rw:      rw,
+		packets: make(chan string),
+		readErr: make(chan error, 1),
+		done:    make(chan struct{}),
+	}
+}
+
+// readLoop reads packets until the connection fails, so interrupts are seen while executing.
+func (c *conn) readLoop() {
+	r := bufio.NewReader(c.rw)
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			c.readErr <- err
+			return
+		}
+		switch b {
+		case interruptByte:
+			c.interrupted.Store(true)
+		case '$':
+			pkt, err := c.readPacket(r)
+			if err != nil {
+				c.readErr <- err
+				return
+			}
+			if pkt != nil {
+				select {
+				case c.packets <- *pkt:
+				case <-c.done:
+					return
+				}
+			}
+		default: // acks are not checked, the transport is reliable
+		}
+	}
+}
+
+// readPacket reads the packet data and checksum, after the '$'.
+// A nil packet is returned if the checksum is invalid, after requesting a retransmission.
+func (c *conn) readPacket(r *bufio.Reader) (*string, error) {
+	data, err := r.ReadString('#')
+	if err != nil {
+		return nil, err
+	}
+	data = data[:len(data)-1]
+	var sum [2]byte
+	if _, err := io.ReadFull(r, sum[:]); err != nil {
+		return nil, err
+	}
+	if c.noAck.Load() {
+		return &data, nil
+	}
+	expected, err := strconv.ParseUint(string(sum[:]), 16, 8)
+	if err != nil || uint8(expected) != checksum(data) {
+		_, err := c.rw.Write([]byte{'-'})
+		return nil, err
+	}
+	if _, err := c.rw.Write([]byte{'+'}); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (c *conn) send(data string) error {
+	_, err := fmt.Fprintf(c.rw, "$%s#%02x", data, checksum(data))
+	return err
+}
+
+func checksum(data string) uint8 {
+	var sum uint8
+	for i := 0; i < len(data); i++ {
+		sum += data[i]
+	}
+	return sum
+}
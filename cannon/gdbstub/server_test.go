@@ -0,0 +1,172 @@
+package gdbstub
+
+import (
+	"bufio"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const testProgramStart = 0x1000
+
+// testProgram increments t0 twice, stores it at 0x100, loads it back, and exits with code 3.
+var testProgram = []uint32{
+	0x25080001, // addiu $t0, $t0, 1
+	0x25080001, // addiu $t0, $t0, 1
+	0xac080100, // sw $t0, 0x100($zero)
+	0x8c090100, // lw $t1, 0x100($zero)
+	0x24021096, // addiu $v0, $zero, 4246 (exit_group)
+	0x24040003, // addiu $a0, $zero, 3
+	0x0000000c, // syscall
+}
+
+type testClient struct {
+	t *testing.T
+	r *bufio.Reader
+	w io.Writer
+}
+
+func (c *testClient) request(pkt string) string {
+	_, err := fmt.Fprintf(c.w, "$%s#%02x", pkt, checksum(pkt))
+	require.NoError(c.t, err)
+	ack, err := c.r.ReadByte()
+	require.NoError(c.t, err)
+	require.Equal(c.t, byte('+'), ack)
+	return c.readPacket()
+}
+
+// monitor sends a monitor command, and returns its output.
+func (c *testClient) monitor(cmd string) string {
+	out := c.request("qRcmd," + hex.EncodeToString([]byte(cmd)))
+	require.Equal(c.t, byte('O'), out[0])
+	require.Equal(c.t, "OK", c.readPacket())
+	text, err := hex.DecodeString(out[1:])
+	require.NoError(c.t, err)
+	return string(text)
+}
+
+func (c *testClient) readPacket() string {
+	_, err := c.r.ReadString('$')
+	require.NoError(c.t, err)
+	data, err := c.r.ReadString('#')
+	require.NoError(c.t, err)
+	data = data[:len(data)-1]
+	var sum [2]byte
+	_, err = io.ReadFull(c.r, sum[:])
+	require.NoError(c.t, err)
+	require.Equal(c.t, fmt.Sprintf("%02x", checksum(data)), string(sum[:]))
+	return data
+}
+
+func setupTest(t *testing.T, meta *mipsevm.Metadata) (*testClient, *mipsevm.State, chan error) {
+	state := &mipsevm.State{Memory: mipsevm.NewMemory(), PC: testProgramStart, NextPC: testProgramStart + 4}
+	for i, insn := range testProgram {
+		state.Memory.SetMemory(testProgramStart+uint32(i)*4, insn)
+	}
+	us := mipsevm.NewInstrumentedState(state, nil, io.Discard, io.Discard)
+	server := NewServer(testlog.Logger(t, log.LvlInfo), NewDebugger(state, us.Step, meta))
+
+	clientConn, serverConn := net.Pipe()
+	t.Cleanup(func() { _ = clientConn.Close() })
+	result := make(chan error, 1)
+	go func() {
+		result <- server.ServeConn(context.Background(), serverConn)
+	}()
+	return &testClient{t: t, r: bufio.NewReader(clientConn), w: clientConn}, state, result
+}
+
+func TestServer(t *testing.T) {
+	t.Run("StatusAndRegisters", func(t *testing.T) {
+		client, state, _ := setupTest(t, nil)
+		state.Registers[8] = 0xaabbccdd
+		state.HI = 7
+		require.Equal(t, "S05", client.request("?"))
+		regs := client.request("g")
+		require.Len(t, regs, numRegs*8)
+		require.Equal(t, "aabbccdd", regs[8*8:9*8])
+		require.Equal(t, "00000007", regs[regHI*8:(regHI+1)*8])
+		require.Equal(t, "00001000", regs[regPC*8:(regPC+1)*8])
+		require.Equal(t, "00001000", client.request("p25"))
+		require.Equal(t, "xxxxxxxx", client.request("p26"))
+		require.Equal(t, "", client.request("vMustReplyEmpty"))
+	})
+
+	t.Run("ReadMemory", func(t *testing.T) {
+		client, _, _ := setupTest(t, nil)
+		require.Equal(t, "2508000125080001", client.request("m1000,8"))
+		require.Equal(t, "0001250800", client.request("m1002,5"))
+		require.Equal(t, "00000000", client.request("m8000,4"), "unmapped memory reads as zero")
+		require.Equal(t, "E01", client.request("mxyz"))
+	})
+
+	t.Run("StepAndBreakpoint", func(t *testing.T) {
+		client, state, _ := setupTest(t, nil)
+		require.Equal(t, "S05", client.request("s"))
+		require.Equal(t, uint32(0x1004), state.PC)
+		require.Equal(t, uint32(1), state.Registers[8])
+
+		require.Equal(t, "OK", client.request("Z0,1010,4"))
+		require.Equal(t, "S05", client.request("c"))
+		require.Equal(t, uint32(0x1010), state.PC)
+
+		require.Equal(t, "OK", client.request("z0,1010,4"))
+		require.Equal(t, "W03", client.request("c"))
+		require.True(t, state.Exited)
+		require.Equal(t, "W03", client.request("?"))
+	})
+
+	t.Run("SymbolBreakpoint", func(t *testing.T) {
+		meta := &mipsevm.Metadata{Symbols: []mipsevm.Symbol{
+			{Name: "main.start", Start: 0x1000, Size: 8},
+			{Name: "main.store", Start: 0x1008, Size: 8},
+		}}
+		client, state, _ := setupTest(t, meta)
+		require.Equal(t, "breakpoint at symbol main.store\n", client.monitor("break main.store"))
+		require.Contains(t, client.monitor("break main.unknown"), "unknown symbol")
+		require.Equal(t, "S05", client.request("c"))
+		require.Equal(t, uint32(0x1008), state.PC)
+		require.Contains(t, client.monitor("info"), "pc: 00001008 (main.store)")
+
+		client.monitor("delete main.store")
+		require.Equal(t, "W03", client.request("c"))
+	})
+
+	t.Run("Watchpoints", func(t *testing.T) {
+		client, state, _ := setupTest(t, nil)
+		require.Equal(t, "OK", client.request("Z2,100,4"))
+		require.Equal(t, "T05watch:100;", client.request("c"))
+		require.Equal(t, uint32(0x100c), state.PC, "must stop after the store")
+		require.Equal(t, uint32(2), state.Memory.GetMemory(0x100))
+
+		require.Equal(t, "OK", client.request("z2,100,4"))
+		require.Equal(t, "OK", client.request("Z3,102,1"))
+		require.Equal(t, "T05rwatch:100;", client.request("c"))
+		require.Equal(t, uint32(0x1010), state.PC, "must stop after the load")
+		require.Equal(t, uint32(2), state.Registers[9])
+	})
+
+	t.Run("Detach", func(t *testing.T) {
+		client, _, result := setupTest(t, nil)
+		require.Equal(t, "OK", client.request("D"))
+		require.ErrorIs(t, <-result, errDetached)
+	})
+
+	t.Run("Kill", func(t *testing.T) {
+		client, _, result := setupTest(t, nil)
+		_, err := fmt.Fprintf(client.w, "$k#%02x", checksum("k"))
+		require.NoError(t, err)
+		ack, err := client.r.ReadByte()
+		require.NoError(t, err)
+		require.Equal(t, byte('+'), ack)
+		require.ErrorIs(t, <-result, errKilled)
+	})
+}
@@ -22,6 +22,8 @@ func main() {
 		cmd.LoadELFCommand,
 		cmd.WitnessCommand,
 		cmd.RunCommand,
+		cmd.DebugCommand,
+		cmd.DiffCommand,
 	}
 	ctx, cancel := context.WithCancel(context.Background())
 
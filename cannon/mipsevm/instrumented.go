@@ -53,6 +53,26 @@ func NewInstrumentedState(state *State, po PreimageOracle, stdOut, stdErr io.Wri
 	}
 }
 
+// FastForward executes steps until the state reaches the given step, or until the program exits.
+// If stopAt is not nil, it also stops before executing the instruction at any PC that stopAt matches,
+// so the caller can inspect the state there.
+// Unlike Step, no witness data is tracked, and the memory merkle cache is only invalidated
+// when the memory is merkleized next, e.g. for a proof or the state witness.
+func (m *InstrumentedState) FastForward(until uint64, stopAt func(pc uint32) bool) error {
+	m.memProofEnabled = false
+	m.state.Memory.SetLazyMerkleization(true)
+	defer m.state.Memory.SetLazyMerkleization(false)
+	for !m.state.Exited && m.state.Step < until {
+		if stopAt != nil && stopAt(m.state.PC) {
+			return nil
+		}
+		if err := m.mipsStep(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (m *InstrumentedState) Step(proof bool) (wit *StepWitness, err error) {
 	m.memProofEnabled = proof
 	m.lastMemAccess = ^uint32(0)
@@ -0,0 +1,72 @@
+package mipsevm
+
+import (
+	"debug/elf"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFastForward(t *testing.T) {
+	load := func() (*State, *InstrumentedState) {
+		elfProgram, err := elf.Open("../example/bin/hello.elf")
+		require.NoError(t, err, "open ELF file")
+		state, err := LoadELF(elfProgram)
+		require.NoError(t, err, "load ELF into state")
+		require.NoError(t, PatchGo(elfProgram, state), "apply Go runtime patches")
+		require.NoError(t, PatchStack(state), "add initial stack")
+		return state, NewInstrumentedState(state, nil, io.Discard, io.Discard)
+	}
+	stepped, steppedUS := load()
+	fast, fastUS := load()
+
+	for _, until := range []uint64{1, 1000, 12_345, 100_000, 400_000} {
+		for !stepped.Exited && stepped.Step < until {
+			_, err := steppedUS.Step(false)
+			require.NoError(t, err)
+		}
+		require.NoError(t, fastUS.FastForward(until, nil))
+		require.Equal(t, stepped.EncodeWitness(), fast.EncodeWitness(), "state witness at step %d", until)
+		if stepped.Exited {
+			break
+		}
+
+		// proofs of the step after fast-forwarding must match
+		steppedWit, err := steppedUS.Step(true)
+		require.NoError(t, err)
+		fastWit, err := fastUS.Step(true)
+		require.NoError(t, err)
+		require.Equal(t, steppedWit, fastWit, "witness of step %d", until)
+	}
+	require.True(t, fast.Exited, "must complete program")
+	require.Equal(t, uint8(0), fast.ExitCode, "exit with 0")
+}
+
+func TestFastForwardStopAt(t *testing.T) {
+	elfProgram, err := elf.Open("../example/bin/hello.elf")
+	require.NoError(t, err, "open ELF file")
+	state, err := LoadELF(elfProgram)
+	require.NoError(t, err, "load ELF into state")
+	require.NoError(t, PatchGo(elfProgram, state), "apply Go runtime patches")
+	require.NoError(t, PatchStack(state), "add initial stack")
+	us := NewInstrumentedState(state, nil, io.Discard, io.Discard)
+
+	require.NoError(t, us.FastForward(1000, nil))
+	stopPC := state.PC
+	require.NoError(t, us.FastForward(2000, nil))
+
+	// fast-forwarding from the start must stop before the first step at the PC
+	state, err = LoadELF(elfProgram)
+	require.NoError(t, err, "load ELF into state")
+	require.NoError(t, PatchGo(elfProgram, state), "apply Go runtime patches")
+	require.NoError(t, PatchStack(state), "add initial stack")
+	us = NewInstrumentedState(state, nil, io.Discard, io.Discard)
+	require.NoError(t, us.FastForward(2000, func(pc uint32) bool { return pc == stopPC }))
+	require.Equal(t, stopPC, state.PC)
+	require.LessOrEqual(t, state.Step, uint64(1000))
+	// it doesn't make progress while it's at the PC
+	step := state.Step
+	require.NoError(t, us.FastForward(2000, func(pc uint32) bool { return pc == stopPC }))
+	require.Equal(t, step, state.Step)
+}
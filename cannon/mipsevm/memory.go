@@ -50,6 +50,11 @@ type Memory struct {
 	// this prevents map lookups each instruction
 	lastPageKeys [2]uint32
 	lastPage     [2]*CachedPage
+
+	// lazy defers the invalidation of the merkle cache of written pages, see SetLazyMerkleization.
+	lazy bool
+	// dirtyPages are the written pages of which the merkle cache has not been invalidated yet.
+	dirtyPages []uint32
 }
 
 func NewMemory() *Memory {
@@ -99,7 +104,33 @@ func (m *Memory) Invalidate(addr uint32) {
 	}
 }
 
+// SetLazyMerkleization enables or disables lazy merkleization. When enabled, writes only mark
+// the page as dirty, and the merkle cache of dirty pages is invalidated in full before the next merkleization.
+// This is cheaper when a page is written many times between merkleizations,
+// but more expensive when merkleizing after every write.
+func (m *Memory) SetLazyMerkleization(lazy bool) {
+	m.lazy = lazy
+}
+
+// invalidateDirtyPages invalidates the merkle cache of the pages that were written lazily.
+func (m *Memory) invalidateDirtyPages() {
+	for _, pageIndex := range m.dirtyPages {
+		p := m.pages[pageIndex]
+		p.dirty = false
+		p.InvalidateFull()
+		gindex := (uint64(1) << PageKeySize) | uint64(pageIndex)
+		for gindex > 0 {
+			m.nodes[gindex] = nil
+			gindex >>= 1
+		}
+	}
+	m.dirtyPages = m.dirtyPages[:0]
+}
+
 func (m *Memory) MerkleizeSubtree(gindex uint64) [32]byte {
+	if len(m.dirtyPages) > 0 {
+		m.invalidateDirtyPages()
+	}
 	l := uint64(bits.Len64(gindex))
 	if l > 28 {
 		panic("gindex too deep")
@@ -133,6 +164,9 @@ func (m *Memory) MerkleizeSubtree(gindex uint64) [32]byte {
 }
 
 func (m *Memory) MerkleProof(addr uint32) (out [28 * 32]byte) {
+	if len(m.dirtyPages) > 0 {
+		m.invalidateDirtyPages()
+	}
 	proof := m.traverseBranch(1, addr, 0)
 	// encode the proof
 	for i := 0; i < 28; i++ {
@@ -199,6 +233,11 @@ func (m *Memory) SetMemory(addr uint32, v uint32) {
 		// allocate the page if we have not already.
 		// Go may mmap relatively large ranges, but we only allocate the pages just in time.
 		p = m.AllocPage(pageIndex)
+	} else if m.lazy {
+		if !p.dirty {
+			p.dirty = true
+			m.dirtyPages = append(m.dirtyPages, pageIndex)
+		}
 	} else {
 		m.Invalidate(addr) // invalidate this branch of memory, now that the value changed
 	}
@@ -258,6 +297,7 @@ func (m *Memory) UnmarshalJSON(data []byte) error {
 	m.pages = make(map[uint32]*CachedPage)
 	m.lastPageKeys = [2]uint32{^uint32(0), ^uint32(0)}
 	m.lastPage = [2]*CachedPage{nil, nil}
+	m.dirtyPages = nil
 	for i, p := range pages {
 		if _, ok := m.pages[p.Index]; ok {
 			return fmt.Errorf("cannot load duplicate page, entry %d, page index %d", i, p.Index)
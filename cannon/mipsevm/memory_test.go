@@ -110,6 +110,32 @@ func TestMemoryMerkleRoot(t *testing.T) {
 	})
 }
 
+func TestMemoryLazyMerkleization(t *testing.T) {
+	eager := NewMemory()
+	lazy := NewMemory()
+	lazy.SetLazyMerkleization(true)
+	var buf [8]byte
+	for round := 0; round < 5; round++ {
+		for i := 0; i < 200; i++ {
+			_, err := rand.Read(buf[:])
+			require.NoError(t, err)
+			// write to a few pages, to write to the same pages many times
+			addr := binary.BigEndian.Uint32(buf[:4])&0x3ffc | uint32(round%2)<<20
+			v := binary.BigEndian.Uint32(buf[4:])
+			eager.SetMemory(addr, v)
+			lazy.SetMemory(addr, v)
+		}
+		require.Equal(t, eager.MerkleRoot(), lazy.MerkleRoot(), "round %d", round)
+		for _, addr := range []uint32{0, 0x1004, 0x3ffc, 0x100000, 0x102000} {
+			require.Equal(t, eager.MerkleProof(addr), lazy.MerkleProof(addr), "round %d, addr %x", round, addr)
+		}
+	}
+	lazy.SetLazyMerkleization(false)
+	eager.SetMemory(0x2000, 42)
+	lazy.SetMemory(0x2000, 42)
+	require.Equal(t, eager.MerkleRoot(), lazy.MerkleRoot())
+}
+
 func TestMemoryReadWrite(t *testing.T) {
 
 	t.Run("large random", func(t *testing.T) {
@@ -68,6 +68,8 @@ type CachedPage struct {
 	Cache [PageSize / 32][32]byte
 	// true if the intermediate node is valid
 	Ok [PageSize / 32]bool
+	// true if the page was written while the memory merkleizes lazily, see Memory.SetLazyMerkleization
+	dirty bool
 }
 
 func (p *CachedPage) Invalidate(pageAddr uint32) {
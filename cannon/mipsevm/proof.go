@@ -0,0 +1,72 @@
+package mipsevm
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// Proof is the proof data of a single step, to replicate the step onchain.
+type Proof struct {
+	Step uint64 `json:"step"`
+
+	Pre  common.Hash `json:"pre"`
+	Post common.Hash `json:"post"`
+
+	StateData hexutil.Bytes `json:"state-data"`
+	ProofData hexutil.Bytes `json:"proof-data"`
+
+	OracleKey    hexutil.Bytes `json:"oracle-key,omitempty"`
+	OracleValue  hexutil.Bytes `json:"oracle-value,omitempty"`
+	OracleOffset uint32        `json:"oracle-offset,omitempty"`
+}
+
+// Serialize writes the binary serialization of the proof to w.
+//
+// The serialization is, with all integers in big-endian and all byte fields prefixed
+// with their uint32 length:
+//
+//	magic "CNPF" | version uint8 | step uint64 | pre [32]byte | post [32]byte |
+//	state data | proof data | oracle key | oracle value | oracle offset uint32
+func (p *Proof) Serialize(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	enc := &binaryEncoder{w: bw}
+	enc.header(proofBinaryMagic)
+	enc.uint64(p.Step)
+	enc.bytes(p.Pre[:])
+	enc.bytes(p.Post[:])
+	enc.varBytes(p.StateData)
+	enc.varBytes(p.ProofData)
+	enc.varBytes(p.OracleKey)
+	enc.varBytes(p.OracleValue)
+	enc.uint32(p.OracleOffset)
+	if enc.err != nil {
+		return fmt.Errorf("failed to serialize proof: %w", enc.err)
+	}
+	return bw.Flush()
+}
+
+// Deserialize reads the binary serialization of a proof, as written by Serialize, from r.
+func (p *Proof) Deserialize(r io.Reader) error {
+	dec := &binaryDecoder{r: r}
+	dec.header(proofBinaryMagic)
+	p.Step = dec.uint64()
+	dec.bytes(p.Pre[:])
+	dec.bytes(p.Post[:])
+	p.StateData = dec.varBytes()
+	p.ProofData = dec.varBytes()
+	p.OracleKey = dec.varBytes()
+	p.OracleValue = dec.varBytes()
+	p.OracleOffset = dec.uint32()
+	if len(p.OracleKey) == 0 {
+		// The oracle fields are optional, leave them unset like JSON does.
+		p.OracleKey, p.OracleValue = nil, nil
+	}
+	if dec.err != nil {
+		return fmt.Errorf("failed to deserialize proof: %w", dec.err)
+	}
+	return nil
+}
@@ -0,0 +1,273 @@
+package mipsevm
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// BinaryVersion is the version of the binary serialization of the State, Memory and Proof types.
+const BinaryVersion uint8 = 1
+
+// maxBinaryFieldSize limits the size of variable-length fields of the binary serialization,
+// so corrupt input can't trigger huge allocations.
+const maxBinaryFieldSize = 1 << 26
+
+var (
+	stateBinaryMagic = [4]byte{'C', 'N', 'S', 'T'}
+	proofBinaryMagic = [4]byte{'C', 'N', 'P', 'F'}
+)
+
+var ErrInvalidBinaryHeader = errors.New("invalid binary header")
+
+// Serialize writes the binary serialization of the state to w.
+// It is streamed, so the memory pages are not buffered.
+//
+// The serialization is, with all integers in big-endian:
+//
+//	magic "CNST" | version uint8 | memory | preimage key [32]byte | preimage offset uint32 |
+//	pc uint32 | next pc uint32 | lo uint32 | hi uint32 | heap uint32 | exit code uint8 | exited uint8 |
+//	step uint64 | registers [32]uint32 | last hint length uint32 | last hint
+func (s *State) Serialize(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	enc := &binaryEncoder{w: bw}
+	enc.header(stateBinaryMagic)
+	if enc.err == nil {
+		enc.err = s.Memory.Serialize(bw)
+	}
+	enc.bytes(s.PreimageKey[:])
+	enc.uint32(s.PreimageOffset)
+	enc.uint32(s.PC)
+	enc.uint32(s.NextPC)
+	enc.uint32(s.LO)
+	enc.uint32(s.HI)
+	enc.uint32(s.Heap)
+	enc.uint8(s.ExitCode)
+	enc.bool(s.Exited)
+	enc.uint64(s.Step)
+	for _, r := range s.Registers {
+		enc.uint32(r)
+	}
+	enc.varBytes(s.LastHint)
+	if enc.err != nil {
+		return fmt.Errorf("failed to serialize state: %w", enc.err)
+	}
+	return bw.Flush()
+}
+
+// Deserialize reads the binary serialization of a state, as written by Serialize, from r.
+func (s *State) Deserialize(r io.Reader) error {
+	dec := &binaryDecoder{r: bufio.NewReader(r)}
+	dec.header(stateBinaryMagic)
+	if dec.err == nil {
+		s.Memory = NewMemory()
+		dec.err = s.Memory.Deserialize(dec.r)
+	}
+	dec.bytes(s.PreimageKey[:])
+	s.PreimageOffset = dec.uint32()
+	s.PC = dec.uint32()
+	s.NextPC = dec.uint32()
+	s.LO = dec.uint32()
+	s.HI = dec.uint32()
+	s.Heap = dec.uint32()
+	s.ExitCode = dec.uint8()
+	s.Exited = dec.bool()
+	s.Step = dec.uint64()
+	for i := range s.Registers {
+		s.Registers[i] = dec.uint32()
+	}
+	s.LastHint = dec.varBytes()
+	if len(s.LastHint) == 0 {
+		s.LastHint = nil // omitted from JSON if empty, so consistently leave it unset
+	}
+	if dec.err != nil {
+		return fmt.Errorf("failed to deserialize state: %w", dec.err)
+	}
+	return nil
+}
+
+// Serialize writes the binary serialization of the memory to w: the number of pages as uint32,
+// followed by the index (uint32) and data of every page, in ascending order of the page index.
+func (m *Memory) Serialize(w io.Writer) error {
+	indices := make([]uint32, 0, len(m.pages))
+	for k := range m.pages {
+		indices = append(indices, k)
+	}
+	sort.Slice(indices, func(i, j int) bool { return indices[i] < indices[j] })
+
+	enc := &binaryEncoder{w: w}
+	enc.uint32(uint32(len(indices)))
+	for _, k := range indices {
+		enc.uint32(k)
+		enc.bytes(m.pages[k].Data[:])
+	}
+	return enc.err
+}
+
+// Deserialize reads the binary serialization of memory, as written by Serialize, from r.
+// Any existing pages are dropped.
+func (m *Memory) Deserialize(r io.Reader) error {
+	m.nodes = make(map[uint64]*[32]byte)
+	m.pages = make(map[uint32]*CachedPage)
+	m.lastPageKeys = [2]uint32{^uint32(0), ^uint32(0)}
+	m.lastPage = [2]*CachedPage{nil, nil}
+	m.dirtyPages = nil
+
+	dec := &binaryDecoder{r: r}
+	count := dec.uint32()
+	if dec.err == nil && count > MaxPageCount {
+		return fmt.Errorf("page count %d exceeds maximum page count %d", count, MaxPageCount)
+	}
+	for i := uint32(0); i < count && dec.err == nil; i++ {
+		index := dec.uint32()
+		if dec.err != nil {
+			break
+		}
+		if index > PageKeyMask {
+			return fmt.Errorf("invalid page index %d, entry %d", index, i)
+		}
+		if _, ok := m.pages[index]; ok {
+			return fmt.Errorf("cannot load duplicate page, entry %d, page index %d", i, index)
+		}
+		dec.bytes(m.AllocPage(index).Data[:])
+	}
+	return dec.err
+}
+
+// binaryEncoder writes big-endian values and keeps the first error,
+// so a sequence of writes only needs to be checked once.
+type binaryEncoder struct {
+	w   io.Writer
+	err error
+	buf [8]byte
+}
+
+func (e *binaryEncoder) bytes(b []byte) {
+	if e.err != nil {
+		return
+	}
+	_, e.err = e.w.Write(b)
+}
+
+func (e *binaryEncoder) header(magic [4]byte) {
+	e.bytes(magic[:])
+	e.uint8(BinaryVersion)
+}
+
+func (e *binaryEncoder) uint8(v uint8) {
+	e.buf[0] = v
+	e.bytes(e.buf[:1])
+}
+
+func (e *binaryEncoder) bool(v bool) {
+	if v {
+		e.uint8(1)
+	} else {
+		e.uint8(0)
+	}
+}
+
+func (e *binaryEncoder) uint32(v uint32) {
+	binary.BigEndian.PutUint32(e.buf[:4], v)
+	e.bytes(e.buf[:4])
+}
+
+func (e *binaryEncoder) uint64(v uint64) {
+	binary.BigEndian.PutUint64(e.buf[:8], v)
+	e.bytes(e.buf[:8])
+}
+
+// varBytes writes a uint32 length prefix followed by b.
+func (e *binaryEncoder) varBytes(b []byte) {
+	if e.err == nil && len(b) > maxBinaryFieldSize {
+		e.err = fmt.Errorf("field of %d bytes exceeds maximum size %d", len(b), maxBinaryFieldSize)
+		return
+	}
+	e.uint32(uint32(len(b)))
+	e.bytes(b)
+}
+
+// binaryDecoder reads the values written by binaryEncoder. After the first error,
+// all reads return zero values and the error is kept.
+type binaryDecoder struct {
+	r   io.Reader
+	err error
+	buf [8]byte
+}
+
+func (d *binaryDecoder) bytes(b []byte) {
+	if d.err != nil {
+		return
+	}
+	if _, err := io.ReadFull(d.r, b); err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		d.err = err
+	}
+}
+
+func (d *binaryDecoder) header(magic [4]byte) {
+	var m [4]byte
+	d.bytes(m[:])
+	if d.err == nil && m != magic {
+		d.err = fmt.Errorf("%w: expected magic %q but got %q", ErrInvalidBinaryHeader, magic[:], m[:])
+		return
+	}
+	if v := d.uint8(); d.err == nil && v != BinaryVersion {
+		d.err = fmt.Errorf("%w: unsupported version %d", ErrInvalidBinaryHeader, v)
+	}
+}
+
+func (d *binaryDecoder) uint8() uint8 {
+	d.bytes(d.buf[:1])
+	if d.err != nil {
+		return 0
+	}
+	return d.buf[0]
+}
+
+func (d *binaryDecoder) bool() bool {
+	v := d.uint8()
+	if d.err == nil && v > 1 {
+		d.err = fmt.Errorf("invalid boolean value %d", v)
+	}
+	return v == 1
+}
+
+func (d *binaryDecoder) uint32() uint32 {
+	d.bytes(d.buf[:4])
+	if d.err != nil {
+		return 0
+	}
+	return binary.BigEndian.Uint32(d.buf[:4])
+}
+
+func (d *binaryDecoder) uint64() uint64 {
+	d.bytes(d.buf[:8])
+	if d.err != nil {
+		return 0
+	}
+	return binary.BigEndian.Uint64(d.buf[:8])
+}
+
+// varBytes reads a uint32 length prefix and that many bytes.
+func (d *binaryDecoder) varBytes() []byte {
+	n := d.uint32()
+	if d.err != nil {
+		return nil
+	}
+	if n > maxBinaryFieldSize {
+		d.err = fmt.Errorf("field of %d bytes exceeds maximum size %d", n, maxBinaryFieldSize)
+		return nil
+	}
+	b := make([]byte, n)
+	d.bytes(b)
+	if d.err != nil {
+		return nil
+	}
+	return b
+}
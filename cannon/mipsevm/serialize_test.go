@@ -0,0 +1,131 @@
+package mipsevm
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"math/rand"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func randomState(rng *rand.Rand) *State {
+	state := &State{
+		Memory:         NewMemory(),
+		PreimageOffset: rng.Uint32(),
+		PC:             rng.Uint32() &^ 3,
+		NextPC:         rng.Uint32() &^ 3,
+		LO:             rng.Uint32(),
+		HI:             rng.Uint32(),
+		Heap:           rng.Uint32(),
+		ExitCode:       uint8(rng.Intn(256)),
+		Exited:         rng.Intn(2) == 1,
+		Step:           rng.Uint64(),
+	}
+	rng.Read(state.PreimageKey[:])
+	for i := range state.Registers {
+		state.Registers[i] = rng.Uint32()
+	}
+	for i := 0; i < 100; i++ {
+		state.Memory.SetMemory(rng.Uint32()&^3, rng.Uint32())
+	}
+	return state
+}
+
+func TestStateSerializeRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(1234))
+	for _, lastHint := range [][]byte{nil, {0, 0, 0, 1, 0xff}} {
+		state := randomState(rng)
+		state.LastHint = lastHint
+
+		var buf bytes.Buffer
+		require.NoError(t, state.Serialize(&buf))
+		var result State
+		require.NoError(t, result.Deserialize(&buf))
+
+		require.Equal(t, state.EncodeWitness(), result.EncodeWitness())
+		require.Equal(t, state.Memory.PageCount(), result.Memory.PageCount())
+		require.Equal(t, state.LastHint, result.LastHint)
+
+		// The binary and JSON serializations must be interchangeable.
+		expectedJSON, err := json.Marshal(state)
+		require.NoError(t, err)
+		resultJSON, err := json.Marshal(&result)
+		require.NoError(t, err)
+		require.JSONEq(t, string(expectedJSON), string(resultJSON))
+	}
+}
+
+func TestStateDeserializeErrors(t *testing.T) {
+	state := randomState(rand.New(rand.NewSource(5678)))
+	var buf bytes.Buffer
+	require.NoError(t, state.Serialize(&buf))
+	data := buf.Bytes()
+
+	t.Run("InvalidMagic", func(t *testing.T) {
+		invalid := bytes.Clone(data)
+		invalid[0] = 'X'
+		require.ErrorIs(t, new(State).Deserialize(bytes.NewReader(invalid)), ErrInvalidBinaryHeader)
+	})
+	t.Run("UnsupportedVersion", func(t *testing.T) {
+		invalid := bytes.Clone(data)
+		invalid[4] = BinaryVersion + 1
+		require.ErrorIs(t, new(State).Deserialize(bytes.NewReader(invalid)), ErrInvalidBinaryHeader)
+	})
+	t.Run("Truncated", func(t *testing.T) {
+		for _, size := range []int{0, 3, 10, len(data) / 2, len(data) - 1} {
+			err := new(State).Deserialize(bytes.NewReader(data[:size]))
+			require.ErrorIs(t, err, io.ErrUnexpectedEOF, "size %d", size)
+		}
+	})
+	t.Run("ProofIsNotState", func(t *testing.T) {
+		var proof bytes.Buffer
+		require.NoError(t, (&Proof{}).Serialize(&proof))
+		require.ErrorIs(t, new(State).Deserialize(&proof), ErrInvalidBinaryHeader)
+	})
+}
+
+func TestMemorySerializeDuplicatePage(t *testing.T) {
+	m := NewMemory()
+	m.SetMemory(0x1000, 1)
+	var buf bytes.Buffer
+	require.NoError(t, m.Serialize(&buf))
+	data := buf.Bytes()
+	// Claim two pages and repeat the single page.
+	data[3] = 2
+	data = append(data, data[4:]...)
+	require.ErrorContains(t, NewMemory().Deserialize(bytes.NewReader(data)), "duplicate page")
+}
+
+func TestProofSerializeRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(91011))
+	proofs := []*Proof{
+		{
+			Step:      rng.Uint64(),
+			Pre:       common.Hash{0x01},
+			Post:      common.Hash{0x02},
+			StateData: []byte{1, 2, 3},
+			ProofData: make([]byte, 28*32),
+		},
+		{
+			Step:         rng.Uint64(),
+			Pre:          common.Hash{0x03},
+			Post:         common.Hash{0x04},
+			StateData:    make([]byte, StateWitnessSize),
+			ProofData:    []byte{},
+			OracleKey:    common.Hash{0x05}.Bytes(),
+			OracleValue:  []byte("hello world"),
+			OracleOffset: 3,
+		},
+	}
+	for _, proof := range proofs {
+		rng.Read(proof.ProofData)
+		var buf bytes.Buffer
+		require.NoError(t, proof.Serialize(&buf))
+		var result Proof
+		require.NoError(t, result.Deserialize(&buf))
+		require.Equal(t, proof, &result)
+	}
+}
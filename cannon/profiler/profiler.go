@@ -0,0 +1,226 @@
+package profiler
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/pprof/profile"
+
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm"
+	preimage "github.com/ethereum-optimism/optimism/op-preimage"
+)
+
+// Syscall numbers and file descriptors of pre-image reads, see mipsevm.
+const (
+	sysRead        = 4003
+	fdPreimageRead = 5
+)
+
+// StepFn executes a single instruction, see mipsevm.InstrumentedState.Step.
+type StepFn func(proof bool) (*mipsevm.StepWitness, error)
+
+// SymbolStats are the sampled steps of a symbol.
+type SymbolStats struct {
+	Name    string
+	Samples uint64
+	// Steps is the estimated number of steps spent in the symbol: the samples times the sampling interval.
+	Steps uint64
+}
+
+// PreimageStats are the pre-image reads of a key type.
+type PreimageStats struct {
+	// Preimages is the number of pre-images that were read, starting at offset 0.
+	Preimages uint64
+	// Reads is the number of read syscalls.
+	Reads uint64
+	// Bytes is the number of bytes read, including the length prefix.
+	Bytes uint64
+}
+
+// Profiler profiles the MIPS program, by sampling the PC every interval steps.
+// Syscalls and pre-image reads are counted at every step.
+type Profiler struct {
+	meta     *mipsevm.Metadata
+	interval uint64
+	start    time.Time
+
+	samples       map[uint32]uint64
+	syscalls      map[uint32]uint64
+	preimageReads map[preimage.KeyType]*PreimageStats
+}
+
+func NewProfiler(meta *mipsevm.Metadata, interval uint64) *Profiler {
+	if meta == nil {
+		meta = &mipsevm.Metadata{}
+	}
+	if interval == 0 {
+		interval = 1
+	}
+	return &Profiler{
+		meta:          meta,
+		interval:      interval,
+		start:         time.Now(),
+		samples:       make(map[uint32]uint64),
+		syscalls:      make(map[uint32]uint64),
+		preimageReads: make(map[preimage.KeyType]*PreimageStats),
+	}
+}
+
+// Wrap returns a step function that profiles every step of fn, which must execute the given state.
+func (p *Profiler) Wrap(state *mipsevm.State, fn StepFn) StepFn {
+	return func(proof bool) (*mipsevm.StepWitness, error) {
+		if state.Exited {
+			return fn(proof)
+		}
+		pc := state.PC
+		if state.Step%p.interval == 0 {
+			p.samples[pc]++
+		}
+		insn := state.Memory.GetMemory(pc)
+		isSyscall := insn>>26 == 0 && insn&0x3f == 0xC
+		syscallNum, fd := state.Registers[2], state.Registers[4]
+		keyType := preimage.KeyType(state.PreimageKey[0])
+		newPreimage := state.PreimageOffset == 0
+
+		wit, err := fn(proof)
+		if err != nil || !isSyscall {
+			return wit, err
+		}
+		p.syscalls[syscallNum]++
+		if syscallNum == sysRead && fd == fdPreimageRead {
+			stats, ok := p.preimageReads[keyType]
+			if !ok {
+				stats = new(PreimageStats)
+				p.preimageReads[keyType] = stats
+			}
+			if newPreimage {
+				stats.Preimages++
+			}
+			stats.Reads++
+			stats.Bytes += uint64(state.Registers[2]) // v0 is the number of bytes read
+		}
+		return wit, nil
+	}
+}
+
+// HotSymbols returns the n symbols with the most samples, in descending order.
+// All symbols are returned if n is 0.
+func (p *Profiler) HotSymbols(n int) []SymbolStats {
+	bySymbol := make(map[string]uint64)
+	for pc, count := range p.samples {
+		bySymbol[p.meta.LookupSymbol(pc)] += count
+	}
+	out := make([]SymbolStats, 0, len(bySymbol))
+	for name, count := range bySymbol {
+		out = append(out, SymbolStats{Name: name, Samples: count, Steps: count * p.interval})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Samples != out[j].Samples {
+			return out[i].Samples > out[j].Samples
+		}
+		return out[i].Name < out[j].Name
+	})
+	if n > 0 && len(out) > n {
+		out = out[:n]
+	}
+	return out
+}
+
+// Syscalls returns the number of calls of every syscall number.
+func (p *Profiler) Syscalls() map[uint32]uint64 {
+	out := make(map[uint32]uint64, len(p.syscalls))
+	for k, v := range p.syscalls {
+		out[k] = v
+	}
+	return out
+}
+
+// PreimageReads returns the pre-image read statistics of every key type.
+func (p *Profiler) PreimageReads() map[preimage.KeyType]PreimageStats {
+	out := make(map[preimage.KeyType]PreimageStats, len(p.preimageReads))
+	for k, v := range p.preimageReads {
+		out[k] = *v
+	}
+	return out
+}
+
+// Profile returns the samples as pprof profile, with a location for every sampled PC,
+// and a function for every symbol. The syscall and pre-image statistics are added as comments.
+func (p *Profiler) Profile() *profile.Profile {
+	prof := &profile.Profile{
+		SampleType: []*profile.ValueType{
+			{Type: "samples", Unit: "count"},
+			{Type: "steps", Unit: "count"},
+		},
+		DefaultSampleType: "steps",
+		PeriodType:        &profile.ValueType{Type: "steps", Unit: "count"},
+		Period:            int64(p.interval),
+		TimeNanos:         p.start.UnixNano(),
+		DurationNanos:     time.Since(p.start).Nanoseconds(),
+	}
+
+	pcs := make([]uint32, 0, len(p.samples))
+	for pc := range p.samples {
+		pcs = append(pcs, pc)
+	}
+	sort.Slice(pcs, func(i, j int) bool { return pcs[i] < pcs[j] })
+
+	functions := make(map[string]*profile.Function)
+	for _, pc := range pcs {
+		name := p.meta.LookupSymbol(pc)
+		fn, ok := functions[name]
+		if !ok {
+			fn = &profile.Function{ID: uint64(len(prof.Function) + 1), Name: name, SystemName: name}
+			functions[name] = fn
+			prof.Function = append(prof.Function, fn)
+		}
+		loc := &profile.Location{
+			ID:      uint64(len(prof.Location) + 1),
+			Address: uint64(pc),
+			Line:    []profile.Line{{Function: fn}},
+		}
+		prof.Location = append(prof.Location, loc)
+		count := int64(p.samples[pc])
+		prof.Sample = append(prof.Sample, &profile.Sample{
+			Location: []*profile.Location{loc},
+			Value:    []int64{count, count * int64(p.interval)},
+		})
+	}
+
+	syscallNums := make([]uint32, 0, len(p.syscalls))
+	for num := range p.syscalls {
+		syscallNums = append(syscallNums, num)
+	}
+	sort.Slice(syscallNums, func(i, j int) bool { return syscallNums[i] < syscallNums[j] })
+	for _, num := range syscallNums {
+		prof.Comments = append(prof.Comments, fmt.Sprintf("syscall %d: %d calls", num, p.syscalls[num]))
+	}
+	keyTypes := make([]preimage.KeyType, 0, len(p.preimageReads))
+	for keyType := range p.preimageReads {
+		keyTypes = append(keyTypes, keyType)
+	}
+	sort.Slice(keyTypes, func(i, j int) bool { return keyTypes[i] < keyTypes[j] })
+	for _, keyType := range keyTypes {
+		stats := p.preimageReads[keyType]
+		prof.Comments = append(prof.Comments, fmt.Sprintf("pre-image key type %s: %d pre-images, %d reads, %d bytes",
+			KeyTypeName(keyType), stats.Preimages, stats.Reads, stats.Bytes))
+	}
+	return prof
+}
+
+// KeyTypeName returns a readable name of the pre-image key type.
+func KeyTypeName(keyType preimage.KeyType) string {
+	switch keyType {
+	case preimage.LocalKeyType:
+		return "local"
+	case preimage.Keccak256KeyType:
+		return "keccak256"
+	case preimage.Sha256KeyType:
+		return "sha256"
+	case preimage.BlobKeyType:
+		return "blob"
+	default:
+		return fmt.Sprintf("type-%d", uint8(keyType))
+	}
+}
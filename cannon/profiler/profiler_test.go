@@ -0,0 +1,118 @@
+package profiler
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/google/pprof/profile"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm"
+	preimage "github.com/ethereum-optimism/optimism/op-preimage"
+)
+
+type staticOracle []byte
+
+func (o staticOracle) Hint(v []byte) {}
+
+func (o staticOracle) GetPreimage(k [32]byte) []byte {
+	return o
+}
+
+const (
+	testStart = 0x1000
+	testSpin  = 90
+)
+
+// testProfileState spins for testSpin steps in main.spin, then reads 4 bytes of a keccak256
+// pre-image twice, and exits in main.exit.
+func testProfileState() *mipsevm.State {
+	var program []uint32
+	for i := 0; i < testSpin; i++ {
+		program = append(program, 0x25080001) // addiu $t0, $t0, 1
+	}
+	for i := 0; i < 2; i++ {
+		program = append(program,
+			0x24020fa3, // addiu $v0, $zero, 4003 (read)
+			0x24040005, // addiu $a0, $zero, 5 (pre-image fd)
+			0x24050100, // addiu $a1, $zero, 0x100
+			0x24060004, // addiu $a2, $zero, 4
+			0x0000000c, // syscall
+		)
+	}
+	program = append(program,
+		0x24021096, // addiu $v0, $zero, 4246 (exit_group)
+		0x24040000, // addiu $a0, $zero, 0
+		0x0000000c, // syscall
+	)
+	state := &mipsevm.State{
+		Memory:      mipsevm.NewMemory(),
+		PreimageKey: [32]byte{byte(preimage.Keccak256KeyType), 0xaa},
+		PC:          testStart,
+		NextPC:      testStart + 4,
+	}
+	for i, insn := range program {
+		state.Memory.SetMemory(testStart+uint32(i)*4, insn)
+	}
+	return state
+}
+
+var testProfileMeta = &mipsevm.Metadata{Symbols: []mipsevm.Symbol{
+	{Name: "main.spin", Start: testStart, Size: testSpin * 4},
+	{Name: "main.read", Start: testStart + testSpin*4, Size: 10 * 4},
+	{Name: "main.exit", Start: testStart + (testSpin+10)*4, Size: 3 * 4},
+}}
+
+func runProfiled(t *testing.T, interval uint64) *Profiler {
+	state := testProfileState()
+	us := mipsevm.NewInstrumentedState(state, staticOracle{0x11, 0x22, 0x33, 0x44}, io.Discard, io.Discard)
+	prof := NewProfiler(testProfileMeta, interval)
+	stepFn := prof.Wrap(state, us.Step)
+	for !state.Exited {
+		_, err := stepFn(false)
+		require.NoError(t, err)
+	}
+	return prof
+}
+
+func TestProfiler(t *testing.T) {
+	t.Run("HotSymbols", func(t *testing.T) {
+		prof := runProfiled(t, 10)
+		// 103 steps in total: the samples at steps 0, 10, ..., 80 are in main.spin,
+		// at step 90 in main.read, and at step 100 in main.exit.
+		require.Equal(t, []SymbolStats{
+			{Name: "main.spin", Samples: 9, Steps: 90},
+			{Name: "main.exit", Samples: 1, Steps: 10},
+		}, prof.HotSymbols(2))
+		require.Len(t, prof.HotSymbols(0), 3)
+	})
+
+	t.Run("SyscallsAndPreimages", func(t *testing.T) {
+		prof := runProfiled(t, 1000)
+		require.Equal(t, map[uint32]uint64{4003: 2, 4246: 1}, prof.Syscalls())
+		// The first read starts a new pre-image, and reads the first 4 bytes of the length prefix.
+		require.Equal(t, map[preimage.KeyType]PreimageStats{
+			preimage.Keccak256KeyType: {Preimages: 1, Reads: 2, Bytes: 8},
+		}, prof.PreimageReads())
+	})
+
+	t.Run("Profile", func(t *testing.T) {
+		prof := runProfiled(t, 1)
+		var buf bytes.Buffer
+		require.NoError(t, prof.Profile().Write(&buf))
+		parsed, err := profile.Parse(&buf)
+		require.NoError(t, err)
+		require.NoError(t, parsed.CheckValid())
+		require.Equal(t, int64(1), parsed.Period)
+		require.Len(t, parsed.Function, 3)
+
+		steps := make(map[string]int64)
+		for _, s := range parsed.Sample {
+			steps[s.Location[0].Line[0].Function.Name] += s.Value[1]
+		}
+		require.Equal(t, map[string]int64{"main.spin": 90, "main.read": 10, "main.exit": 3}, steps)
+		require.Contains(t, parsed.Comments, "syscall 4003: 2 calls")
+		require.Contains(t, parsed.Comments, "pre-image key type keccak256: 1 pre-images, 2 reads, 8 bytes")
+	})
+}
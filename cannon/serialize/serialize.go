@@ -0,0 +1,128 @@
+package serialize
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/ethereum-optimism/optimism/op-service/ioutil"
+)
+
+// Serializable is implemented by types with a compact binary serialization,
+// like the cannon state and proofs.
+type Serializable interface {
+	Serialize(w io.Writer) error
+	Deserialize(r io.Reader) error
+}
+
+// IsBinaryFile returns whether values are written to the file in binary instead of JSON,
+// which is the case for files with a .bin or .bin.gz extension.
+func IsBinaryFile(path string) bool {
+	return strings.HasSuffix(path, ".bin") || strings.HasSuffix(path, ".bin.gz")
+}
+
+// Load reads a value from the file, which is gzip decompressed if the file name ends in .gz.
+// The format is detected from the content: JSON or, if X is Serializable, binary.
+func Load[X any](inputPath string) (*X, error) {
+	if inputPath == "" {
+		return nil, errors.New("no path specified")
+	}
+	f, err := ioutil.OpenDecompressed(inputPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file %q: %w", inputPath, err)
+	}
+	defer f.Close()
+	var value X
+	if err := Read(f, &value); err != nil {
+		return nil, fmt.Errorf("failed to decode file %q: %w", inputPath, err)
+	}
+	return &value, nil
+}
+
+// Read decodes the value from r, in either the binary or the JSON format.
+func Read(r io.Reader, value any) error {
+	br := bufio.NewReader(r)
+	first, err := br.Peek(1)
+	if errors.Is(err, io.EOF) {
+		return io.ErrUnexpectedEOF
+	} else if err != nil {
+		return err
+	}
+	if !isJSONStart(first[0]) {
+		s, ok := value.(Serializable)
+		if !ok {
+			return fmt.Errorf("input is not JSON, and %T has no binary serialization", value)
+		}
+		return s.Deserialize(br)
+	}
+	return json.NewDecoder(br).Decode(value)
+}
+
+// isJSONStart returns whether b can be the first byte of a JSON document.
+// None of the binary serializations start with such a byte.
+func isJSONStart(b byte) bool {
+	return strings.IndexByte(" \t\r\n{[\"", b) >= 0
+}
+
+// Write writes the value to the file, as binary if IsBinaryFile(outputPath) and as JSON otherwise.
+// The output is gzip compressed if the file name ends in .gz.
+// Nothing is written if the path is empty, and the value is written to stdout if the path is "-".
+func Write[X any](outputPath string, value X) error {
+	if outputPath == "" {
+		return nil
+	}
+	var out io.Writer
+	finish := func() error { return nil }
+	if outputPath != "-" {
+		// Write to a tmp file but reserve the file extension if present
+		tmpPath := outputPath + "-tmp" + path.Ext(outputPath)
+		f, err := ioutil.OpenCompressed(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0755)
+		if err != nil {
+			return fmt.Errorf("failed to open output file: %w", err)
+		}
+		closed := false
+		// Close the file if the write fails before finishing, finish closes it otherwise.
+		defer func() {
+			if !closed {
+				_ = f.Close()
+			}
+		}()
+		out = f
+		finish = func() error {
+			closed = true
+			if err := f.Close(); err != nil {
+				return err
+			}
+			// Rename the file into place as atomically as the OS will allow
+			return os.Rename(tmpPath, outputPath)
+		}
+	} else {
+		out = os.Stdout
+	}
+	if IsBinaryFile(outputPath) {
+		s, ok := any(value).(Serializable)
+		if !ok {
+			return fmt.Errorf("%T has no binary serialization", value)
+		}
+		if err := s.Serialize(out); err != nil {
+			return fmt.Errorf("failed to serialize: %w", err)
+		}
+	} else {
+		enc := json.NewEncoder(out)
+		if err := enc.Encode(value); err != nil {
+			return fmt.Errorf("failed to encode to JSON: %w", err)
+		}
+		if _, err := out.Write([]byte{'\n'}); err != nil {
+			return fmt.Errorf("failed to append new-line: %w", err)
+		}
+	}
+	if err := finish(); err != nil {
+		return fmt.Errorf("failed to finish write: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package serialize
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm"
+)
+
+func TestRoundTripJSON(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "test.json")
+	data := &jsonTestData{A: "yay", B: 3}
+	err := Write(file, data)
+	require.NoError(t, err)
+
+	// Confirm the file is uncompressed
+	fileContent, err := os.ReadFile(file)
+	require.NoError(t, err)
+	err = json.Unmarshal(fileContent, &jsonTestData{})
+	require.NoError(t, err)
+
+	var result *jsonTestData
+	result, err = Load[jsonTestData](file)
+	require.NoError(t, err)
+	require.EqualValues(t, data, result)
+}
+
+func TestRoundTripJSONWithGzip(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "test.json.gz")
+	data := &jsonTestData{A: "yay", B: 3}
+	err := Write(file, data)
+	require.NoError(t, err)
+
+	// Confirm the file isn't raw JSON
+	fileContent, err := os.ReadFile(file)
+	require.NoError(t, err)
+	err = json.Unmarshal(fileContent, &jsonTestData{})
+	require.Error(t, err, "should not be able to decode without decompressing")
+
+	var result *jsonTestData
+	result, err = Load[jsonTestData](file)
+	require.NoError(t, err)
+	require.EqualValues(t, data, result)
+}
+
+func TestRoundTripBinary(t *testing.T) {
+	for _, name := range []string{"state.bin", "state.bin.gz"} {
+		t.Run(name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), name)
+			state := testState()
+			require.NoError(t, Write(file, state))
+
+			fileContent, err := os.ReadFile(file)
+			require.NoError(t, err)
+			require.Error(t, json.Unmarshal(fileContent, &mipsevm.State{}), "should not be JSON")
+
+			result, err := Load[mipsevm.State](file)
+			require.NoError(t, err)
+			require.Equal(t, state.EncodeWitness(), result.EncodeWitness())
+			require.Equal(t, state.LastHint, result.LastHint)
+		})
+	}
+}
+
+func TestLoadDetectsFormat(t *testing.T) {
+	dir := t.TempDir()
+	state := testState()
+	jsonFile := filepath.Join(dir, "state.json.gz")
+	binFile := filepath.Join(dir, "state.bin.gz")
+	require.NoError(t, Write(jsonFile, state))
+	require.NoError(t, Write(binFile, state))
+
+	// The format is detected from the content, not the file name.
+	renamed := filepath.Join(dir, "renamed.json.gz")
+	require.NoError(t, os.Rename(binFile, renamed))
+
+	fromJSON, err := Load[mipsevm.State](jsonFile)
+	require.NoError(t, err)
+	fromBinary, err := Load[mipsevm.State](renamed)
+	require.NoError(t, err)
+	require.Equal(t, fromJSON.EncodeWitness(), fromBinary.EncodeWitness())
+}
+
+func TestBinaryRequiresSerializable(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "test.bin")
+	require.ErrorContains(t, Write(file, &jsonTestData{A: "yay", B: 3}), "no binary serialization")
+
+	require.NoError(t, Write(file, testState()))
+	_, err := Load[jsonTestData](file)
+	require.ErrorContains(t, err, "no binary serialization")
+}
+
+func testState() *mipsevm.State {
+	state := &mipsevm.State{
+		Memory:         mipsevm.NewMemory(),
+		PreimageOffset: 8,
+		PC:             0x100,
+		NextPC:         0x104,
+		Heap:           0x2000_0000,
+		Step:           42,
+		LastHint:       []byte{0, 0, 0, 3, 'a', 'b', 'c'},
+	}
+	state.Memory.SetMemory(0x100, 0x0000_000c)
+	state.Memory.SetMemory(0x7fff_fff0, 0xdeadbeef)
+	state.Registers[2] = 4246
+	return state
+}
+
+type jsonTestData struct {
+	A string `json:"a"`
+	B int    `json:"b"`
+}
@@ -3,10 +3,15 @@ package batcher
 import (
 	"fmt"
 	"math"
+	"sort"
+	"time"
 
 	"github.com/ethereum-optimism/optimism/op-batcher/metrics"
+	"github.com/ethereum-optimism/optimism/op-batcher/rpc"
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
 	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
 	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/log"
 )
@@ -24,33 +29,160 @@ type channel struct {
 	pendingTransactions map[txID]txData
 	// Set of confirmed txID -> inclusion block. For determining if the channel is timed out
 	confirmedTransactions map[txID]eth.BlockID
+	// Published tx hashes of pending transactions, including fee-bumped replacements.
+	// For reconciling in-flight transactions against L1 after a restart
+	txHashes map[txID][]common.Hash
+
+	// total time spent compressing blocks into this channel
+	compressionTime time.Duration
 }
 
-func newChannel(log log.Logger, metr metrics.Metricer, cfg ChannelConfig) (*channel, error) {
-	cb, err := newChannelBuilder(cfg)
+func newChannel(log log.Logger, metr metrics.Metricer, cfg ChannelConfig, rollupCfg *rollup.Config, l1HeadTime uint64) (*channel, error) {
+	cb, err := newChannelBuilder(cfg, rollupCfg, l1HeadTime)
 	if err != nil {
 		return nil, fmt.Errorf("creating new channel: %w", err)
 	}
 	return &channel{
 		log:                   log,
 		metr:                  metr,
-		cfg:                   cfg,
+		cfg:                   cb.cfg,
 		channelBuilder:        cb,
 		pendingTransactions:   make(map[txID]txData),
 		confirmedTransactions: make(map[txID]eth.BlockID),
+		txHashes:              make(map[txID][]common.Hash),
 	}, nil
 }
 
+// restoreChannel restores a closed channel from its journal entry. The passed
+// blocks must be the blocks of the journaled channel. All frames that are not
+// part of a confirmed transaction are queued for submission again.
+func restoreChannel(log log.Logger, metr metrics.Metricer, cfg ChannelConfig, jc JournalChannel, blocks []*types.Block) *channel {
+	confirmed := make(map[txID]eth.BlockID)
+	confirmedFrames := make(map[uint16]bool)
+	for _, tx := range jc.Txs {
+		if tx.Inclusion == nil || len(tx.Frames) == 0 {
+			continue
+		}
+		confirmed[txID{chID: jc.ID, frameNumber: tx.Frames[0]}] = *tx.Inclusion
+		for _, fn := range tx.Frames {
+			confirmedFrames[fn] = true
+		}
+	}
+
+	var frames []frameData
+	for _, f := range jc.Frames {
+		if confirmedFrames[f.Number] {
+			continue
+		}
+		frames = append(frames, frameData{
+			id:   frameID{chID: jc.ID, frameNumber: f.Number},
+			data: f.Data,
+		})
+	}
+	sort.Slice(frames, func(i, j int) bool {
+		return frames[i].id.frameNumber < frames[j].id.frameNumber
+	})
+
+	return &channel{
+		log:                   log,
+		metr:                  metr,
+		cfg:                   cfg,
+		channelBuilder:        restoreChannelBuilder(cfg, jc.ID, blocks, frames, jc.TotalFrames),
+		pendingTransactions:   make(map[txID]txData),
+		confirmedTransactions: confirmed,
+		txHashes:              make(map[txID][]common.Hash),
+	}
+}
+
+// journal returns the journal entry of this channel, which records all frames
+// that are not confirmed yet, as well as all pending and confirmed transactions.
+// Confirmed transactions are only recorded by their first frame number.
+func (s *channel) journal() JournalChannel {
+	jc := JournalChannel{
+		ID:          s.ID(),
+		TotalFrames: s.TotalFrames(),
+	}
+	for _, block := range s.channelBuilder.Blocks() {
+		jc.Blocks = append(jc.Blocks, eth.ToBlockID(block))
+	}
+	for _, f := range s.channelBuilder.frames {
+		jc.Frames = append(jc.Frames, JournalFrame{Number: f.id.frameNumber, Data: f.data})
+	}
+	for id, txdata := range s.pendingTransactions {
+		// copy the hashes, since the journal is written after the lock is released
+		tx := JournalTx{Hashes: append([]common.Hash(nil), s.txHashes[id]...)}
+		for _, f := range txdata.Frames() {
+			tx.Frames = append(tx.Frames, f.id.frameNumber)
+			jc.Frames = append(jc.Frames, JournalFrame{Number: f.id.frameNumber, Data: f.data})
+		}
+		jc.Txs = append(jc.Txs, tx)
+	}
+	for id, inclusionBlock := range s.confirmedTransactions {
+		inclusionBlock := inclusionBlock
+		jc.Txs = append(jc.Txs, JournalTx{Frames: []uint16{id.frameNumber}, Inclusion: &inclusionBlock})
+	}
+	sort.Slice(jc.Frames, func(i, j int) bool {
+		return jc.Frames[i].Number < jc.Frames[j].Number
+	})
+	sort.Slice(jc.Txs, func(i, j int) bool {
+		return jc.Txs[i].Frames[0] < jc.Txs[j].Frames[0]
+	})
+	return jc
+}
+
+// state returns a snapshot of the channel for the admin API.
+func (s *channel) state() rpc.ChannelState {
+	cs := rpc.ChannelState{
+		ID:           s.ID(),
+		InputBytes:   s.InputBytes(),
+		OutputBytes:  s.OutputBytes(),
+		TotalFrames:  s.TotalFrames(),
+		QueuedFrames: len(s.channelBuilder.frames),
+		Deadline:     s.channelBuilder.Deadline(),
+	}
+	for _, block := range s.channelBuilder.Blocks() {
+		cs.Blocks = append(cs.Blocks, eth.ToBlockID(block))
+	}
+	if err := s.FullErr(); err != nil {
+		cs.FullReason = err.Error()
+	}
+	for id, txdata := range s.pendingTransactions {
+		tx := rpc.TxState{Hashes: s.txHashes[id]}
+		for _, f := range txdata.Frames() {
+			tx.Frames = append(tx.Frames, f.id.frameNumber)
+		}
+		cs.Txs = append(cs.Txs, tx)
+	}
+	for id, inclusionBlock := range s.confirmedTransactions {
+		inclusionBlock := inclusionBlock
+		cs.Txs = append(cs.Txs, rpc.TxState{Frames: []uint16{id.frameNumber}, Inclusion: &inclusionBlock})
+	}
+	sort.Slice(cs.Txs, func(i, j int) bool {
+		return cs.Txs[i].Frames[0] < cs.Txs[j].Frames[0]
+	})
+	return cs
+}
+
+// TxPublished records the hash of a published version of a pending transaction.
+func (s *channel) TxPublished(id txID, hash common.Hash) {
+	if _, ok := s.pendingTransactions[id]; !ok {
+		s.log.Warn("unknown transaction marked as published", "id", id, "tx", hash)
+		return
+	}
+	s.txHashes[id] = append(s.txHashes[id], hash)
+}
+
 // TxFailed records a transaction as failed. It will attempt to resubmit the data
 // in the failed transaction.
 func (s *channel) TxFailed(id txID) {
 	if data, ok := s.pendingTransactions[id]; ok {
 		s.log.Trace("marked transaction as failed", "id", id)
-		// Note: when the batcher is changed to send multiple frames per tx,
-		// this needs to be changed to iterate over all frames of the tx data
-		// and re-queue them.
-		s.channelBuilder.PushFrame(data.Frame())
+		// re-queue all frames of the failed transaction
+		for _, f := range data.Frames() {
+			s.channelBuilder.PushFrame(f)
+		}
 		delete(s.pendingTransactions, id)
+		delete(s.txHashes, id)
 	} else {
 		s.log.Warn("unknown transaction marked as failed", "id", id)
 	}
@@ -72,6 +204,7 @@ func (s *channel) TxConfirmed(id txID, inclusionBlock eth.BlockID) (bool, []*typ
 		return false, nil
 	}
 	delete(s.pendingTransactions, id)
+	delete(s.txHashes, id)
 	s.confirmedTransactions[id] = inclusionBlock
 	s.channelBuilder.FramePublished(inclusionBlock.Number)
 
@@ -92,6 +225,15 @@ func (s *channel) TxConfirmed(id txID, inclusionBlock eth.BlockID) (bool, []*typ
 	return false, nil
 }
 
+// isUrgent returns whether the frames of the channel must be submitted at the
+// given L1 head to meet the channel's deadline, which is the case from margin
+// blocks before the deadline on. Channels without a known deadline, like
+// restored channels, are always urgent.
+func (s *channel) isUrgent(l1Head uint64, margin uint64) bool {
+	deadline := s.channelBuilder.Deadline()
+	return deadline == 0 || l1Head+margin >= deadline
+}
+
 // pendingChannelIsTimedOut returns true if submitted channel has timed out.
 // A channel has timed out if the difference in L1 Inclusion blocks between
 // the first & last included block is greater than or equal to the channel timeout.
@@ -126,10 +268,14 @@ func (s *channel) ID() derive.ChannelID {
 	return s.channelBuilder.ID()
 }
 
+// NextTxData returns the next tx data packet, consisting of up to
+// [ChannelConfig.MaxFramesPerTx] frames.
+// HasFrame must be called prior to check if there's a next frame available.
 func (s *channel) NextTxData() txData {
-	frame := s.channelBuilder.NextFrame()
-
-	txdata := txData{frame}
+	txdata := txData{asBlob: s.cfg.UseBlobs}
+	for s.channelBuilder.HasFrame() && len(txdata.frames) < s.cfg.MaxFramesPerTx() {
+		txdata.frames = append(txdata.frames, s.channelBuilder.NextFrame())
+	}
 	id := txdata.ID()
 
 	s.log.Trace("returning next tx data", "id", id)
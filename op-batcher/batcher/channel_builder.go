@@ -8,7 +8,9 @@ import (
 	"math"
 
 	"github.com/ethereum-optimism/optimism/op-batcher/compressor"
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
 	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
 	"github.com/ethereum/go-ethereum/core/types"
 )
 
@@ -19,6 +21,7 @@ var (
 	ErrChannelTimeoutClose   = errors.New("close to channel timeout")
 	ErrSeqWindowClose        = errors.New("close to sequencer window timeout")
 	ErrTerminated            = errors.New("channel terminated")
+	ErrRestored              = errors.New("channel restored from journal")
 )
 
 type ChannelFullError struct {
@@ -55,9 +58,28 @@ type ChannelConfig struct {
 	SubSafetyMargin uint64
 	// The maximum byte-size a frame can have.
 	MaxFrameSize uint64
+	// MaxL1TxSize is the maximum size of a calldata batcher tx. Every frame is
+	// submitted in its own tx, prefixed with a version byte, so MaxFrameSize
+	// must not exceed it when blobs are not used.
+	//
+	// If 0, the frame size is not bounded by the tx size.
+	MaxL1TxSize uint64
+
+	// UseBlobs indicates that frames are submitted as blobs instead of calldata.
+	// Every frame is put into its own blob, so MaxFrameSize must not exceed the
+	// blob capacity.
+	UseBlobs bool
 
 	// CompressorConfig contains the configuration for creating new compressors.
 	CompressorConfig compressor.Config
+
+	// MaxOpenChannels is the maximum number of channels that are not fully
+	// submitted yet. A new channel is only opened once fewer channels are open,
+	// so it can be compressed while the frames of previous channels are
+	// being submitted.
+	//
+	// If 0, the number of open channels is not limited.
+	MaxOpenChannels int
 }
 
 // Check validates the [ChannelConfig] parameters.
@@ -83,9 +105,33 @@ func (cc *ChannelConfig) Check() error {
 		return fmt.Errorf("max frame size %d is less than the minimum 23", cc.MaxFrameSize)
 	}
 
+	if cc.MaxOpenChannels < 0 {
+		return fmt.Errorf("max open channels %d cannot be negative", cc.MaxOpenChannels)
+	}
+
+	// Every calldata tx carries a version byte in front of the frame.
+	if !cc.UseBlobs && cc.MaxL1TxSize != 0 && cc.MaxFrameSize > cc.MaxL1TxSize-1 {
+		return fmt.Errorf("max frame size %d exceeds the maximum calldata frame size %d", cc.MaxFrameSize, cc.MaxL1TxSize-1)
+	}
+
+	// Every blob carries a version byte in front of the frame.
+	if cc.UseBlobs && cc.MaxFrameSize > eth.MaxBlobDataSize-1 {
+		return fmt.Errorf("max frame size %d exceeds the maximum blob frame size %d", cc.MaxFrameSize, eth.MaxBlobDataSize-1)
+	}
+
 	return nil
 }
 
+// MaxFramesPerTx returns the maximum number of frames that are packed into a
+// single transaction: one per blob for blob transactions, and a single frame
+// for calldata transactions.
+func (cc *ChannelConfig) MaxFramesPerTx() int {
+	if cc.UseBlobs {
+		return eth.MaxBlobsPerBlobTx
+	}
+	return 1
+}
+
 type frameID struct {
 	chID        derive.ChannelID
 	frameNumber uint16
@@ -96,7 +142,23 @@ type frameData struct {
 	id   frameID
 }
 
-// channelBuilder uses a ChannelOut to create a channel with output frame
+// channelOut is the channel out that a channelBuilder compresses blocks with.
+// It is implemented by derive.ChannelOut, which adds every block as a
+// singular batch, and derive.SpanChannelOut, which adds all blocks of the
+// channel to a single span batch.
+type channelOut interface {
+	ID() derive.ChannelID
+	Reset() error
+	AddSingularBatch(batch *derive.SingularBatch, seqNum uint64) (uint64, error)
+	InputBytes() int
+	ReadyBytes() int
+	Flush() error
+	FullErr() error
+	Close() error
+	OutputFrame(w *bytes.Buffer, maxSize uint64) (uint16, error)
+}
+
+// channelBuilder uses a channelOut to create a channel with output frame
 // size approximation.
 type channelBuilder struct {
 	cfg ChannelConfig
@@ -109,12 +171,21 @@ type channelBuilder struct {
 	timeout uint64
 	// reason for currently set timeout
 	timeoutReason error
+	// L1 block number by which the channel must be fully submitted, combined of
+	// - sequencing window timeout,
+	// - consensus channel timeout, once a frame got published.
+	// 0 if not known yet.
+	deadline uint64
 
 	// Reason for the channel being full. Set by setFullErr so it's always
 	// guaranteed to be a ChannelFullError wrapping the specific reason.
 	fullErr error
-	// current channel
-	co *derive.ChannelOut
+	// current channel, nil if the channel was restored from the journal
+	co channelOut
+	// id of the current channel
+	id derive.ChannelID
+	// whether all frames of the channel have been created
+	closed bool
 	// list of blocks in the channel. Saved in case the channel must be rebuilt
 	blocks []*types.Block
 	// frames data queue, to be send as txs
@@ -126,13 +197,36 @@ type channelBuilder struct {
 }
 
 // newChannelBuilder creates a new channel builder or returns an error if the
-// channel out could not be created.
-func newChannelBuilder(cfg ChannelConfig) (*channelBuilder, error) {
-	c, err := cfg.CompressorConfig.NewCompressor()
+// channel out could not be created. Once the span batch upgrade is active at
+// the time of the current L1 head, the blocks of the channel are added to a
+// single span batch. Likewise, brotli and zstd compression are only used once
+// the channel compression upgrade is active, and channels are compressed with
+// zlib before. Frames are only put into blobs once blobs are enabled, and are
+// sized for calldata before. Derivation checks the upgrades against the time of
+// the L1 block that includes the channel, which is never earlier than the L1 head.
+func newChannelBuilder(cfg ChannelConfig, rollupCfg *rollup.Config, l1HeadTime uint64) (*channelBuilder, error) {
+	if cfg.UseBlobs && !rollupCfg.IsBlobsEnabled(l1HeadTime) {
+		cfg.UseBlobs = false
+		if cfg.MaxL1TxSize != 0 {
+			cfg.MaxFrameSize = cfg.MaxL1TxSize - 1 // subtract 1 byte for version
+			cfg.CompressorConfig.TargetFrameSize = min(cfg.CompressorConfig.TargetFrameSize, cfg.MaxFrameSize)
+		}
+	}
+	compressorCfg := cfg.CompressorConfig
+	if compressorCfg.CompressionAlgo.IsVersioned() && !rollupCfg.IsChannelCompression(l1HeadTime) {
+		compressorCfg.CompressionAlgo = derive.Zlib
+		compressorCfg.CompressionLevel = 0
+	}
+	c, err := compressorCfg.NewCompressor()
 	if err != nil {
 		return nil, err
 	}
-	co, err := derive.NewChannelOut(c)
+	var co channelOut
+	if rollupCfg.IsSpanBatch(l1HeadTime) {
+		co, err = derive.NewSpanChannelOut(c, cfg.CompressorConfig.TargetOutputSize(), rollupCfg.Genesis.L2Time, rollupCfg.L2ChainID)
+	} else {
+		co, err = derive.NewChannelOut(c)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -140,21 +234,45 @@ func newChannelBuilder(cfg ChannelConfig) (*channelBuilder, error) {
 	return &channelBuilder{
 		cfg: cfg,
 		co:  co,
+		id:  co.ID(),
 	}, nil
 }
 
+// restoreChannelBuilder creates a channel builder for a closed channel that is
+// restored from the journal. It has no channel out, since all frames of the
+// channel have already been created. The passed frames are the frames that
+// still need to be submitted.
+func restoreChannelBuilder(cfg ChannelConfig, id derive.ChannelID, blocks []*types.Block, frames []frameData, numFrames int) *channelBuilder {
+	c := &channelBuilder{
+		cfg:       cfg,
+		id:        id,
+		closed:    true,
+		blocks:    blocks,
+		frames:    frames,
+		numFrames: numFrames,
+	}
+	c.setFullErr(ErrRestored)
+	return c
+}
+
 func (c *channelBuilder) ID() derive.ChannelID {
-	return c.co.ID()
+	return c.id
 }
 
 // InputBytes returns the total amount of input bytes added to the channel.
 func (c *channelBuilder) InputBytes() int {
+	if c.co == nil {
+		return 0
+	}
 	return c.co.InputBytes()
 }
 
 // ReadyBytes returns the amount of bytes ready in the compression pipeline to
 // output into a frame.
 func (c *channelBuilder) ReadyBytes() int {
+	if c.co == nil {
+		return 0
+	}
 	return c.co.ReadyBytes()
 }
 
@@ -170,12 +288,21 @@ func (c *channelBuilder) Blocks() []*types.Block {
 
 // Reset resets the internal state of the channel builder so that it can be
 // reused. Note that a new channel id is also generated by Reset.
+// Channel builders restored from the journal have no channel out and cannot be
+// reset. Reset returns ErrRestored for them.
 func (c *channelBuilder) Reset() error {
+	if c.co == nil {
+		return ErrRestored
+	}
 	c.blocks = c.blocks[:0]
 	c.frames = c.frames[:0]
 	c.timeout = 0
+	c.deadline = 0
 	c.fullErr = nil
-	return c.co.Reset()
+	c.closed = false
+	err := c.co.Reset()
+	c.id = c.co.ID()
+	return err
 }
 
 // AddBlock adds a block to the channel compression pipeline. IsFull should be
@@ -199,7 +326,7 @@ func (c *channelBuilder) AddBlock(block *types.Block) (derive.L1BlockInfo, error
 		return l1info, fmt.Errorf("converting block to batch: %w", err)
 	}
 
-	if _, err = c.co.AddBatch(batch); errors.Is(err, derive.ErrTooManyRLPBytes) || errors.Is(err, derive.CompressorFullErr) {
+	if _, err = c.co.AddSingularBatch(&batch.SingularBatch, l1info.SequenceNumber); errors.Is(err, derive.ErrTooManyRLPBytes) || errors.Is(err, derive.CompressorFullErr) {
 		c.setFullErr(err)
 		return l1info, c.FullErr()
 	} else if err != nil {
@@ -233,6 +360,7 @@ func (c *channelBuilder) RegisterL1Block(l1BlockNum uint64) {
 func (c *channelBuilder) FramePublished(l1BlockNum uint64) {
 	timeout := l1BlockNum + c.cfg.ChannelTimeout - c.cfg.SubSafetyMargin
 	c.updateTimeout(timeout, ErrChannelTimeoutClose)
+	c.updateDeadline(timeout)
 }
 
 // updateDurationTimeout updates the block timeout with the channel duration
@@ -255,6 +383,7 @@ func (c *channelBuilder) updateDurationTimeout(l1BlockNum uint64) {
 func (c *channelBuilder) updateSwTimeout(batch *derive.BatchData) {
 	timeout := uint64(batch.EpochNum) + c.cfg.SeqWindowSize - c.cfg.SubSafetyMargin
 	c.updateTimeout(timeout, ErrSeqWindowClose)
+	c.updateDeadline(timeout)
 }
 
 // updateTimeout updates the timeout block to the given block number if it is
@@ -269,6 +398,21 @@ func (c *channelBuilder) updateTimeout(timeoutBlockNum uint64, reason error) {
 	}
 }
 
+// updateDeadline moves the submission deadline to the given block number if it
+// is earlier than the current deadline, or if it is still unset.
+func (c *channelBuilder) updateDeadline(blockNum uint64) {
+	if c.deadline == 0 || c.deadline > blockNum {
+		c.deadline = blockNum
+	}
+}
+
+// Deadline returns the L1 block number by which the channel must be fully
+// submitted. It is not changed by the max channel duration, which only closes
+// the channel. If no deadline is known yet, it returns 0.
+func (c *channelBuilder) Deadline() uint64 {
+	return c.deadline
+}
+
 // checkTimeout checks if the channel is timed out at the given block number and
 // in this case marks the channel as full, if it wasn't full already.
 func (c *channelBuilder) checkTimeout(blockNum uint64) {
@@ -302,7 +446,8 @@ func (c *channelBuilder) IsFull() bool {
 //   - ErrMaxDurationReached if the max channel duration got reached,
 //   - ErrChannelTimeoutClose if the consensus channel timeout got too close,
 //   - ErrSeqWindowClose if the end of the sequencer window got too close,
-//   - ErrTerminated if the channel was explicitly terminated.
+//   - ErrTerminated if the channel was explicitly terminated,
+//   - ErrRestored if the channel was restored from the journal.
 func (c *channelBuilder) FullErr() error {
 	return c.fullErr
 }
@@ -320,6 +465,9 @@ func (c *channelBuilder) setFullErr(err error) {
 // If it is full, the channel is closed and all remaining
 // frames will be created, possibly with a small leftover frame.
 func (c *channelBuilder) OutputFrames() error {
+	if c.closed {
+		return nil
+	}
 	if c.IsFull() {
 		return c.closeAndOutputAllFrames()
 	}
@@ -351,6 +499,7 @@ func (c *channelBuilder) closeAndOutputAllFrames() error {
 
 	for {
 		if err := c.outputFrame(); err == io.EOF {
+			c.closed = true
 			return nil
 		} else if err != nil {
 			return err
@@ -358,6 +507,11 @@ func (c *channelBuilder) closeAndOutputAllFrames() error {
 	}
 }
 
+// IsClosed returns whether the channel is closed and all of its frames have been created.
+func (c *channelBuilder) IsClosed() bool {
+	return c.closed
+}
+
 // outputFrame creates one new frame and adds it to the frames queue.
 // Note that compressed output data must be available on the underlying
 // ChannelOut, or an empty frame will be produced.
@@ -380,7 +534,7 @@ func (c *channelBuilder) outputFrame() error {
 	}
 
 	frame := frameData{
-		id:   frameID{chID: c.co.ID(), frameNumber: fn},
+		id:   frameID{chID: c.id, frameNumber: fn},
 		data: buf.Bytes(),
 	}
 	c.frames = append(c.frames, frame)
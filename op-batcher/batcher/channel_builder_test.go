@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"io"
 	"math"
 	"math/big"
 	"math/rand"
@@ -34,6 +35,11 @@ var defaultTestChannelConfig = ChannelConfig{
 	},
 }
 
+var defaultTestRollupConfig = rollup.Config{
+	Genesis:   rollup.Genesis{L2: eth.BlockID{Number: 0}},
+	L2ChainID: big.NewInt(1234),
+}
+
 // TestChannelConfig_Check tests the [ChannelConfig] [Check] function.
 func TestChannelConfig_Check(t *testing.T) {
 	type test struct {
@@ -47,6 +53,10 @@ func TestChannelConfig_Check(t *testing.T) {
 	timeoutChannelConfig := defaultTestChannelConfig
 	timeoutChannelConfig.ChannelTimeout = 0
 	timeoutChannelConfig.SubSafetyMargin = 1
+	calldataChannelConfig := defaultTestChannelConfig
+	calldataChannelConfig.MaxL1TxSize = 120000
+	blobChannelConfig := calldataChannelConfig
+	blobChannelConfig.UseBlobs = true
 	tests := []test{
 		{
 			input: defaultTestChannelConfig,
@@ -60,6 +70,19 @@ func TestChannelConfig_Check(t *testing.T) {
 				require.ErrorIs(t, output, ErrInvalidChannelTimeout)
 			},
 		},
+		{
+			input: calldataChannelConfig,
+			assertion: func(output error) {
+				require.EqualError(t, output, "max frame size 120000 exceeds the maximum calldata frame size 119999")
+			},
+		},
+		{
+			// frames in blobs are not bounded by the calldata tx size
+			input: blobChannelConfig,
+			assertion: func(output error) {
+				require.NoError(t, output)
+			},
+		},
 		{
 			input: zeroChannelConfig,
 			assertion: func(output error) {
@@ -178,7 +201,7 @@ func FuzzDurationTimeoutZeroMaxChannelDuration(f *testing.F) {
 	f.Fuzz(func(t *testing.T, l1BlockNum uint64) {
 		channelConfig := defaultTestChannelConfig
 		channelConfig.MaxChannelDuration = 0
-		cb, err := newChannelBuilder(channelConfig)
+		cb, err := newChannelBuilder(channelConfig, &defaultTestRollupConfig, 0)
 		require.NoError(t, err)
 		cb.timeout = 0
 		cb.updateDurationTimeout(l1BlockNum)
@@ -201,7 +224,7 @@ func FuzzChannelBuilder_DurationZero(f *testing.F) {
 		// Create the channel builder
 		channelConfig := defaultTestChannelConfig
 		channelConfig.MaxChannelDuration = maxChannelDuration
-		cb, err := newChannelBuilder(channelConfig)
+		cb, err := newChannelBuilder(channelConfig, &defaultTestRollupConfig, 0)
 		require.NoError(t, err)
 
 		// Whenever the timeout is set to 0, the channel builder should have a duration timeout
@@ -228,7 +251,7 @@ func FuzzDurationTimeoutMaxChannelDuration(f *testing.F) {
 		// Create the channel builder
 		channelConfig := defaultTestChannelConfig
 		channelConfig.MaxChannelDuration = maxChannelDuration
-		cb, err := newChannelBuilder(channelConfig)
+		cb, err := newChannelBuilder(channelConfig, &defaultTestRollupConfig, 0)
 		require.NoError(t, err)
 
 		// Whenever the timeout is greater than the l1BlockNum,
@@ -262,7 +285,7 @@ func FuzzChannelCloseTimeout(f *testing.F) {
 		channelConfig := defaultTestChannelConfig
 		channelConfig.ChannelTimeout = channelTimeout
 		channelConfig.SubSafetyMargin = subSafetyMargin
-		cb, err := newChannelBuilder(channelConfig)
+		cb, err := newChannelBuilder(channelConfig, &defaultTestRollupConfig, 0)
 		require.NoError(t, err)
 
 		// Check the timeout
@@ -290,7 +313,7 @@ func FuzzChannelZeroCloseTimeout(f *testing.F) {
 		channelConfig := defaultTestChannelConfig
 		channelConfig.ChannelTimeout = channelTimeout
 		channelConfig.SubSafetyMargin = subSafetyMargin
-		cb, err := newChannelBuilder(channelConfig)
+		cb, err := newChannelBuilder(channelConfig, &defaultTestRollupConfig, 0)
 		require.NoError(t, err)
 
 		// Check the timeout
@@ -317,7 +340,7 @@ func FuzzSeqWindowClose(f *testing.F) {
 		channelConfig := defaultTestChannelConfig
 		channelConfig.SeqWindowSize = seqWindowSize
 		channelConfig.SubSafetyMargin = subSafetyMargin
-		cb, err := newChannelBuilder(channelConfig)
+		cb, err := newChannelBuilder(channelConfig, &defaultTestRollupConfig, 0)
 		require.NoError(t, err)
 
 		// Check the timeout
@@ -349,7 +372,7 @@ func FuzzSeqWindowZeroTimeoutClose(f *testing.F) {
 		channelConfig := defaultTestChannelConfig
 		channelConfig.SeqWindowSize = seqWindowSize
 		channelConfig.SubSafetyMargin = subSafetyMargin
-		cb, err := newChannelBuilder(channelConfig)
+		cb, err := newChannelBuilder(channelConfig, &defaultTestRollupConfig, 0)
 		require.NoError(t, err)
 
 		// Check the timeout
@@ -372,7 +395,7 @@ func TestChannelBuilder_NextFrame(t *testing.T) {
 	channelConfig := defaultTestChannelConfig
 
 	// Create a new channel builder
-	cb, err := newChannelBuilder(channelConfig)
+	cb, err := newChannelBuilder(channelConfig, &defaultTestRollupConfig, 0)
 	require.NoError(t, err)
 
 	// Mock the internals of `channelBuilder.outputFrame`
@@ -412,7 +435,7 @@ func TestChannelBuilder_OutputWrongFramePanic(t *testing.T) {
 	channelConfig := defaultTestChannelConfig
 
 	// Construct a channel builder
-	cb, err := newChannelBuilder(channelConfig)
+	cb, err := newChannelBuilder(channelConfig, &defaultTestRollupConfig, 0)
 	require.NoError(t, err)
 
 	// Mock the internals of `channelBuilder.outputFrame`
@@ -445,7 +468,7 @@ func TestChannelBuilder_OutputFramesWorks(t *testing.T) {
 	channelConfig.MaxFrameSize = 24
 
 	// Construct the channel builder
-	cb, err := newChannelBuilder(channelConfig)
+	cb, err := newChannelBuilder(channelConfig, &defaultTestRollupConfig, 0)
 	require.NoError(t, err)
 	require.False(t, cb.IsFull())
 	require.Equal(t, 0, cb.PendingFrames())
@@ -490,7 +513,7 @@ func TestChannelBuilder_MaxRLPBytesPerChannel(t *testing.T) {
 	channelConfig.CompressorConfig.ApproxComprRatio = 1
 
 	// Construct the channel builder
-	cb, err := newChannelBuilder(channelConfig)
+	cb, err := newChannelBuilder(channelConfig, &defaultTestRollupConfig, 0)
 	require.NoError(t, err)
 
 	// Add a block that overflows the [ChannelOut]
@@ -510,7 +533,7 @@ func TestChannelBuilder_OutputFramesMaxFrameIndex(t *testing.T) {
 	// Continuously add blocks until the max frame index is reached
 	// This should cause the [channelBuilder.OutputFrames] function
 	// to error
-	cb, err := newChannelBuilder(channelConfig)
+	cb, err := newChannelBuilder(channelConfig, &defaultTestRollupConfig, 0)
 	require.NoError(t, err)
 	require.False(t, cb.IsFull())
 	require.Equal(t, 0, cb.PendingFrames())
@@ -552,7 +575,7 @@ func TestChannelBuilder_AddBlock(t *testing.T) {
 	channelConfig.CompressorConfig.ApproxComprRatio = 1
 
 	// Construct the channel builder
-	cb, err := newChannelBuilder(channelConfig)
+	cb, err := newChannelBuilder(channelConfig, &defaultTestRollupConfig, 0)
 	require.NoError(t, err)
 
 	// Add a nonsense block to the channel builder
@@ -570,6 +593,115 @@ func TestChannelBuilder_AddBlock(t *testing.T) {
 	require.ErrorIs(t, addMiniBlock(cb), derive.CompressorFullErr)
 }
 
+// TestChannelBuilder_SpanBatch tests that all blocks of a channel are added to
+// a single span batch once the span batch upgrade is active.
+func TestChannelBuilder_SpanBatch(t *testing.T) {
+	require := require.New(t)
+	channelConfig := defaultTestChannelConfig
+	rollupCfg := defaultTestRollupConfig
+	rollupCfg.SpanBatchTime = new(uint64)
+
+	cb, err := newChannelBuilder(channelConfig, &rollupCfg, 0)
+	require.NoError(err)
+	require.IsType(&derive.SpanChannelOut{}, cb.co)
+
+	const numBlocks = 5
+	parent := common.Hash{}
+	for i := 0; i < numBlocks; i++ {
+		block := newMiniL2BlockWithNumberParent(0, big.NewInt(int64(i)), parent)
+		_, err := cb.AddBlock(block)
+		require.NoError(err)
+		parent = block.Hash()
+	}
+	require.Len(cb.Blocks(), numBlocks)
+
+	// No frames can be output before the channel is closed, since the span
+	// batch is recompressed with every block.
+	require.NoError(cb.OutputFrames())
+	require.Zero(cb.PendingFrames())
+
+	cb.setFullErr(ErrTerminated)
+	require.NoError(cb.OutputFrames())
+	require.Equal(1, cb.PendingFrames())
+
+	frames, err := derive.ParseFrames(append([]byte{derive.DerivationVersion0}, cb.NextFrame().data...))
+	require.NoError(err)
+	require.Len(frames, 1)
+	require.True(frames[0].IsLast)
+
+	ch := derive.NewChannel(frames[0].ID, eth.L1BlockRef{})
+	require.NoError(ch.AddFrame(frames[0], eth.L1BlockRef{}))
+	readBatch, err := derive.BatchReader(&rollupCfg, ch.Reader(), eth.L1BlockRef{})
+	require.NoError(err)
+	batch, err := readBatch()
+	require.NoError(err)
+	require.Equal(derive.SpanBatchType, batch.Batch.BatchType)
+	spanBatch, err := batch.Batch.RawSpanBatch.Derive(rollupCfg.BlockTime, rollupCfg.Genesis.L2Time, rollupCfg.L2ChainID)
+	require.NoError(err)
+	require.Equal(numBlocks, spanBatch.GetBlockCount())
+	_, err = readBatch()
+	require.ErrorIs(err, io.EOF, "channel must hold a single span batch")
+}
+
+// TestChannelBuilder_CompressionAlgo tests that channels are only compressed with
+// brotli once the channel compression upgrade is active at the L1 head.
+func TestChannelBuilder_CompressionAlgo(t *testing.T) {
+	channelConfig := defaultTestChannelConfig
+	channelConfig.CompressorConfig.CompressionAlgo = derive.Brotli
+	channelConfig.CompressorConfig.CompressionLevel = 11
+	rollupCfg := defaultTestRollupConfig
+	activation := uint64(1000)
+	rollupCfg.ChannelCompressionTime = &activation
+
+	for _, tc := range []struct {
+		l1HeadTime uint64
+		version    byte
+	}{
+		{l1HeadTime: activation - 1, version: 0x78}, // zlib header
+		{l1HeadTime: activation, version: derive.ChannelVersionBrotli},
+	} {
+		cb, err := newChannelBuilder(channelConfig, &rollupCfg, tc.l1HeadTime)
+		require.NoError(t, err)
+		_, err = cb.AddBlock(newMiniL2Block(1))
+		require.NoError(t, err)
+		cb.setFullErr(ErrTerminated)
+		require.NoError(t, cb.OutputFrames())
+
+		frames, err := derive.ParseFrames(append([]byte{derive.DerivationVersion0}, cb.NextFrame().data...))
+		require.NoError(t, err)
+		require.Equal(t, tc.version, frames[0].Data[0], "l1 head time %d", tc.l1HeadTime)
+	}
+}
+
+// TestChannelBuilder_Blobs tests that frames are only sized for and put into
+// blobs once blobs are enabled at the L1 head.
+func TestChannelBuilder_Blobs(t *testing.T) {
+	channelConfig := defaultTestChannelConfig
+	channelConfig.MaxL1TxSize = 100_000
+	channelConfig.MaxFrameSize = eth.MaxBlobDataSize - 1
+	channelConfig.CompressorConfig.TargetFrameSize = eth.MaxBlobDataSize - 1
+	channelConfig.UseBlobs = true
+	rollupCfg := defaultTestRollupConfig
+	activation := uint64(1000)
+	rollupCfg.BlobsEnabledL1Timestamp = &activation
+
+	for _, tc := range []struct {
+		l1HeadTime   uint64
+		useBlobs     bool
+		maxFrameSize uint64
+	}{
+		{l1HeadTime: activation - 1, useBlobs: false, maxFrameSize: 100_000 - 1},
+		{l1HeadTime: activation, useBlobs: true, maxFrameSize: eth.MaxBlobDataSize - 1},
+	} {
+		cb, err := newChannelBuilder(channelConfig, &rollupCfg, tc.l1HeadTime)
+		require.NoError(t, err)
+		require.Equal(t, tc.useBlobs, cb.cfg.UseBlobs, "l1 head time %d", tc.l1HeadTime)
+		require.Equal(t, tc.maxFrameSize, cb.cfg.MaxFrameSize, "l1 head time %d", tc.l1HeadTime)
+		require.Equal(t, tc.maxFrameSize, cb.cfg.CompressorConfig.TargetFrameSize, "l1 head time %d", tc.l1HeadTime)
+		require.NoError(t, cb.cfg.Check())
+	}
+}
+
 // TestChannelBuilder_Reset tests the [Reset] function
 func TestChannelBuilder_Reset(t *testing.T) {
 	channelConfig := defaultTestChannelConfig
@@ -577,7 +709,7 @@ func TestChannelBuilder_Reset(t *testing.T) {
 	// Lower the max frame size so that we can batch
 	channelConfig.MaxFrameSize = 24
 
-	cb, err := newChannelBuilder(channelConfig)
+	cb, err := newChannelBuilder(channelConfig, &defaultTestRollupConfig, 0)
 	require.NoError(t, err)
 
 	// Add a nonsense block to the channel builder
@@ -615,6 +747,11 @@ func TestChannelBuilder_Reset(t *testing.T) {
 	require.NoError(t, cb.fullErr)
 	require.Equal(t, 0, cb.co.InputBytes())
 	require.Equal(t, 0, cb.co.ReadyBytes())
+
+	// Restored channel builders have no channel out to reset
+	restored := restoreChannelBuilder(channelConfig, cb.ID(), nil, nil, 1)
+	require.ErrorIs(t, restored.Reset(), ErrRestored)
+	require.True(t, restored.IsClosed())
 }
 
 // TestBuilderRegisterL1Block tests the RegisterL1Block function
@@ -622,7 +759,7 @@ func TestBuilderRegisterL1Block(t *testing.T) {
 	channelConfig := defaultTestChannelConfig
 
 	// Construct the channel builder
-	cb, err := newChannelBuilder(channelConfig)
+	cb, err := newChannelBuilder(channelConfig, &defaultTestRollupConfig, 0)
 	require.NoError(t, err)
 
 	// Assert params modified in RegisterL1Block
@@ -645,7 +782,7 @@ func TestBuilderRegisterL1BlockZeroMaxChannelDuration(t *testing.T) {
 	channelConfig.MaxChannelDuration = 0
 
 	// Construct the channel builder
-	cb, err := newChannelBuilder(channelConfig)
+	cb, err := newChannelBuilder(channelConfig, &defaultTestRollupConfig, 0)
 	require.NoError(t, err)
 
 	// Assert params modified in RegisterL1Block
@@ -666,7 +803,7 @@ func TestFramePublished(t *testing.T) {
 	channelConfig := defaultTestChannelConfig
 
 	// Construct the channel builder
-	cb, err := newChannelBuilder(channelConfig)
+	cb, err := newChannelBuilder(channelConfig, &defaultTestRollupConfig, 0)
 	require.NoError(t, err)
 
 	// Let's say the block number is fed in as 100
@@ -691,7 +828,7 @@ func TestChannelBuilder_PendingFrames_TotalFrames(t *testing.T) {
 	cfg.MaxFrameSize = 1000
 	cfg.CompressorConfig.TargetNumFrames = tnf
 	cfg.CompressorConfig.Kind = "shadow"
-	cb, err := newChannelBuilder(cfg)
+	cb, err := newChannelBuilder(cfg, &defaultTestRollupConfig, 0)
 	require.NoError(err)
 
 	// initial builder should be empty
@@ -751,7 +888,7 @@ func TestChannelBuilder_OutputBytes(t *testing.T) {
 	cfg.MaxFrameSize = 1000
 	cfg.CompressorConfig.TargetNumFrames = 16
 	cfg.CompressorConfig.ApproxComprRatio = 1.0
-	cb, err := newChannelBuilder(cfg)
+	cb, err := newChannelBuilder(cfg, &defaultTestRollupConfig, 0)
 	require.NoError(err, "newChannelBuilder")
 
 	require.Zero(cb.OutputBytes())
@@ -781,7 +918,7 @@ func TestChannelBuilder_OutputBytes(t *testing.T) {
 func defaultChannelBuilderSetup(t *testing.T) (*channelBuilder, ChannelConfig) {
 	t.Helper()
 	cfg := defaultTestChannelConfig
-	cb, err := newChannelBuilder(cfg)
+	cb, err := newChannelBuilder(cfg, &defaultTestRollupConfig, 0)
 	require.NoError(t, err, "newChannelBuilder")
 	return cb, cfg
 }
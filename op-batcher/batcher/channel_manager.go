@@ -5,8 +5,11 @@ import (
 	"fmt"
 	"io"
 	"sync"
+	"time"
 
 	"github.com/ethereum-optimism/optimism/op-batcher/metrics"
+	"github.com/ethereum-optimism/optimism/op-batcher/rpc"
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
 	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
 	"github.com/ethereum-optimism/optimism/op-service/eth"
 	"github.com/ethereum/go-ethereum/common"
@@ -14,20 +17,26 @@ import (
 	"github.com/ethereum/go-ethereum/log"
 )
 
-var ErrReorg = errors.New("block does not extend existing chain")
+var (
+	ErrReorg          = errors.New("block does not extend existing chain")
+	ErrNothingToFlush = errors.New("no pending blocks to flush")
+)
 
 // channelManager stores a contiguous set of blocks & turns them into channels.
 // Upon receiving tx confirmation (or a tx failure), it does channel error handling.
 //
-// For simplicity, it only creates a single pending channel at a time & waits for
-// the channel to either successfully be submitted or timeout before creating a new
-// channel.
+// Blocks are compressed into the current channel in a background goroutine, so
+// that the frames of previous channels can be submitted in the meantime. Once the
+// current channel is full, a new channel is opened, up to the configured maximum
+// number of open channels.
 // Public functions on channelManager are safe for concurrent access.
 type channelManager struct {
 	mu   sync.Mutex
 	log  log.Logger
 	metr metrics.Metricer
 	cfg  ChannelConfig
+	// rollup config, to select the batch type of new channels
+	rollupCfg *rollup.Config
 
 	// All blocks since the last request for new tx data.
 	blocks []*types.Block
@@ -43,22 +52,61 @@ type channelManager struct {
 
 	// if set to true, prevents production of any new channel frames
 	closed bool
+
+	// optional journal to persist closed channels to, nil if disabled
+	journal *journalWriter
+
+	// compressing is set while pending blocks are compressed into the current
+	// channel in the background. The compression run owns the current channel
+	// and the blocks at the front of the blocks queue until it's done.
+	compressing bool
+	// compressDone is signaled whenever a compression run is done. It uses mu as locker.
+	compressDone *sync.Cond
+	// error of the last compression run, returned by the next call to TxData
+	compressErr error
+
+	// fee policy that throttles submission, nil while the L1 base fee is below
+	// the policy's threshold
+	throttle *rpc.FeePolicy
 }
 
-func NewChannelManager(log log.Logger, metr metrics.Metricer, cfg ChannelConfig) *channelManager {
-	return &channelManager{
+func NewChannelManager(log log.Logger, metr metrics.Metricer, cfg ChannelConfig, rollupCfg *rollup.Config) *channelManager {
+	m := &channelManager{
 		log:        log,
 		metr:       metr,
 		cfg:        cfg,
+		rollupCfg:  rollupCfg,
 		txChannels: make(map[txID]*channel),
 	}
+	m.compressDone = sync.NewCond(&m.mu)
+	return m
+}
+
+// SetJournal sets the journal that closed channels are persisted to.
+// It must be called before the channel manager is used.
+func (s *channelManager) SetJournal(journal ChannelJournal) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.journal = newJournalWriter(s.log, journal)
+}
+
+// syncJournal writes the state that got persisted while holding the lock to the
+// journal, if enabled. It must be called after the lock is released, so that
+// writing the journal doesn't block the channel manager. Methods that persist
+// the state defer it before locking, so it runs after the deferred unlock.
+func (s *channelManager) syncJournal() {
+	s.journal.Sync()
 }
 
 // Clear clears the entire state of the channel manager.
 // It is intended to be used after an L2 reorg.
+// The journal is left untouched, so it can still be restored from on startup.
+// It gets overwritten as soon as the state changes again.
+// It waits for a running compression to finish first.
 func (s *channelManager) Clear() {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	s.waitCompression()
 	s.log.Trace("clearing channel manager state")
 	s.blocks = s.blocks[:0]
 	s.tip = common.Hash{}
@@ -66,13 +114,18 @@ func (s *channelManager) Clear() {
 	s.currentChannel = nil
 	s.channelQueue = nil
 	s.txChannels = make(map[txID]*channel)
+	s.compressErr = nil
+	s.metr.RecordOpenChannels(0)
 }
 
 // TxFailed records a transaction as failed. It will attempt to resubmit the data
 // in the failed transaction.
+// It waits for a running compression to finish first.
 func (s *channelManager) TxFailed(id txID) {
+	defer s.syncJournal()
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	s.waitCompression()
 	if channel, ok := s.txChannels[id]; ok {
 		delete(s.txChannels, id)
 		channel.TxFailed(id)
@@ -80,18 +133,38 @@ func (s *channelManager) TxFailed(id txID) {
 			s.log.Info("Channel has no submitted transactions, clearing for shutdown", "chID", channel.ID())
 			s.removePendingChannel(channel)
 		}
+		s.persist()
 	} else {
 		s.log.Warn("transaction from unknown channel marked as failed", "id", id)
 	}
 }
 
+// TxPublished records the hash of a published transaction, so that it can be
+// reconciled against L1 after a restart. It is called for every published
+// version of a transaction, including fee-bumped replacements.
+// The journal is written before it returns, but without holding the lock.
+func (s *channelManager) TxPublished(id txID, hash common.Hash) {
+	defer s.syncJournal()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if channel, ok := s.txChannels[id]; ok {
+		channel.TxPublished(id, hash)
+		s.persist()
+	} else {
+		s.log.Warn("transaction from unknown channel marked as published", "id", id, "tx", hash)
+	}
+}
+
 // TxConfirmed marks a transaction as confirmed on L1. Unfortunately even if all frames in
 // a channel have been marked as confirmed on L1 the channel may be invalid & need to be
 // resubmitted.
 // This function may reset the pending channel if the pending channel has timed out.
+// It waits for a running compression to finish first.
 func (s *channelManager) TxConfirmed(id txID, inclusionBlock eth.BlockID) {
+	defer s.syncJournal()
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	s.waitCompression()
 	if channel, ok := s.txChannels[id]; ok {
 		delete(s.txChannels, id)
 		done, blocks := channel.TxConfirmed(id, inclusionBlock)
@@ -99,6 +172,7 @@ func (s *channelManager) TxConfirmed(id txID, inclusionBlock eth.BlockID) {
 		if done {
 			s.removePendingChannel(channel)
 		}
+		s.persist()
 	} else {
 		s.log.Warn("transaction from unknown channel marked as confirmed", "id", id)
 	}
@@ -123,6 +197,7 @@ func (s *channelManager) removePendingChannel(channel *channel) {
 		return
 	}
 	s.channelQueue = append(s.channelQueue[:index], s.channelQueue[index+1:]...)
+	s.metr.RecordOpenChannels(len(s.channelQueue))
 }
 
 // nextTxData pops off s.datas & handles updating the internal state
@@ -136,66 +211,268 @@ func (s *channelManager) nextTxData(channel *channel) (txData, error) {
 	return tx, nil
 }
 
+// SetThrottle sets the fee policy that throttles submission. While set, TxData
+// holds back the frames of channels that are not urgent yet and new channels
+// are opened with the policy's widened channel duration. A nil policy disables
+// throttling.
+func (s *channelManager) SetThrottle(policy *rpc.FeePolicy) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.throttle = policy
+}
+
+// Config returns the config that new channels are opened with.
+func (s *channelManager) Config() ChannelConfig {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cfg
+}
+
+// UpdateConfig applies the update to the config that new channels are opened
+// with. The config is left unchanged if the updated config is invalid. Channels
+// that are already open keep their config.
+func (s *channelManager) UpdateConfig(update func(cfg *ChannelConfig)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cfg := s.cfg
+	update(&cfg)
+	if err := cfg.Check(); err != nil {
+		return fmt.Errorf("invalid channel config: %w", err)
+	}
+	s.cfg = cfg
+	return nil
+}
+
+// FlushChannel adds the pending blocks to the current channel, opening one if
+// needed, the same way TxData does. It then closes the channel and outputs all
+// its remaining frames, so that they are submitted with the next transactions.
+// Unlike Close, new channels are still opened afterwards. Pending blocks that
+// don't fit into the current channel are left for the next channel.
+// It returns ErrNothingToFlush if there are neither pending blocks nor blocks in
+// an open current channel.
+// It waits for a running compression to finish first.
+func (s *channelManager) FlushChannel(l1Head eth.L1BlockRef) error {
+	defer s.syncJournal()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.waitCompression()
+	if s.closed {
+		return ErrNothingToFlush
+	}
+	if err := s.takeCompressErr(); err != nil {
+		return err
+	}
+	if err := s.startCompression(l1Head); err != nil {
+		return err
+	}
+	s.waitCompression()
+	if err := s.takeCompressErr(); err != nil {
+		return err
+	}
+
+	ch := s.currentChannel
+	if ch == nil || ch.IsFull() || len(ch.channelBuilder.Blocks()) == 0 {
+		return ErrNothingToFlush
+	}
+
+	ch.Close()
+	closed, err := s.outputFrames(ch)
+	if err != nil {
+		return err
+	}
+	if closed {
+		s.channelClosed(ch)
+	}
+	return nil
+}
+
+// State returns a snapshot of the channel manager for the admin API.
+// It waits for a running compression to finish first.
+func (s *channelManager) State() rpc.ChannelManagerState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.waitCompression()
+	state := rpc.ChannelManagerState{
+		Closed:    s.closed,
+		Throttled: s.throttle != nil,
+		Tip:       s.tip,
+	}
+	for _, block := range s.blocks {
+		state.PendingBlocks = append(state.PendingBlocks, eth.ToBlockID(block))
+	}
+	for _, ch := range s.channelQueue {
+		cs := ch.state()
+		cs.Current = ch == s.currentChannel
+		state.Channels = append(state.Channels, cs)
+	}
+	return state
+}
+
 // TxData returns the next tx data that should be submitted to L1.
 //
-// It currently only uses one frame per transaction. If the pending channel is
-// full, it only returns the remaining frames of this channel until it got
-// successfully fully sent to L1. It returns io.EOF if there's no pending frame.
-func (s *channelManager) TxData(l1Head eth.BlockID) (txData, error) {
+// It packs up to [ChannelConfig.MaxFramesPerTx] frames into a transaction. If the
+// pending channel is full, it only returns the remaining frames of this channel
+// until it got successfully fully sent to L1. It returns io.EOF if there's no
+// pending frame.
+//
+// Pending blocks are compressed in the background. If there's no pending frame,
+// TxData waits for the compression to output new frames.
+//
+// While submission is throttled, frames are only returned once their channel
+// is urgent, unless the channel manager is closed.
+func (s *channelManager) TxData(l1Head eth.L1BlockRef) (txData, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	var firstWithFrame *channel
-	for _, ch := range s.channelQueue {
-		if ch.HasFrame() {
-			firstWithFrame = ch
-			break
+	if err := s.takeCompressErr(); err != nil {
+		return txData{}, err
+	}
+	firstWithFrame := s.firstChannelWithFrame()
+	// Short circuit if the channel manager is closed.
+	if s.closed {
+		return s.nextTxData(firstWithFrame)
+	}
+
+	firstWithFrame = s.holdBack(firstWithFrame, l1Head)
+	dataPending := firstWithFrame != nil
+	s.log.Debug("Requested tx data", "l1Head", l1Head, "data_pending", dataPending, "blocks_pending", len(s.blocks),
+		"compressing", s.compressing, "throttled", s.throttle != nil)
+
+	if dataPending {
+		// Take the frames before starting a compression, which may own the channel.
+		data, err := s.nextTxData(firstWithFrame)
+		if err != nil {
+			return txData{}, err
+		}
+		// Compress pending blocks in the background, while the frames are submitted.
+		// The tx data is already pending, so an error is returned by the next call.
+		if err := s.startCompression(l1Head); err != nil {
+			s.compressErr = err
 		}
+		return data, nil
 	}
 
-	dataPending := firstWithFrame != nil && firstWithFrame.HasFrame()
-	s.log.Debug("Requested tx data", "l1Head", l1Head, "data_pending", dataPending, "blocks_pending", len(s.blocks))
+	// No pending frame, so we have to wait for new blocks to be compressed.
+	if err := s.startCompression(l1Head); err != nil {
+		return txData{}, err
+	}
+	s.waitCompression()
+	if err := s.takeCompressErr(); err != nil {
+		return txData{}, err
+	}
+	return s.nextTxData(s.holdBack(s.firstChannelWithFrame(), l1Head))
+}
 
-	// Short circuit if there is a pending frame or the channel manager is closed.
-	if dataPending || s.closed {
-		return s.nextTxData(firstWithFrame)
+// holdBack returns nil if the frames of the given channel are held back because
+// submission is throttled and the channel is not urgent yet. Otherwise, it
+// returns the channel. Since channels are submitted in order, holding back the
+// first channel with a frame also holds back all later channels.
+func (s *channelManager) holdBack(ch *channel, l1Head eth.L1BlockRef) *channel {
+	if ch == nil || s.throttle == nil || ch.isUrgent(l1Head.Number, s.throttle.UrgencyMargin) {
+		return ch
 	}
+	s.log.Debug("Holding back frames, L1 base fee above threshold",
+		"id", ch.ID(), "l1Head", l1Head, "deadline", ch.channelBuilder.Deadline())
+	return nil
+}
 
-	// No pending frame, so we have to add new blocks to the channel
+// firstChannelWithFrame returns the first channel of the channel queue that has
+// a frame to submit. The current channel is skipped while it's being compressed.
+func (s *channelManager) firstChannelWithFrame() *channel {
+	for _, ch := range s.channelQueue {
+		if s.compressing && ch == s.currentChannel {
+			continue
+		}
+		if ch.HasFrame() {
+			return ch
+		}
+	}
+	return nil
+}
 
-	// If we have no saved blocks, we will not be able to create valid frames
-	if len(s.blocks) == 0 {
-		return txData{}, io.EOF
+// startCompression starts compressing the pending blocks into the current channel
+// in a background goroutine, opening a new channel if the current one is full.
+// It does nothing if a compression is already running, if there are no pending
+// blocks or if a new channel is needed but the maximum number of open channels
+// is reached.
+func (s *channelManager) startCompression(l1Head eth.L1BlockRef) error {
+	if s.compressing || len(s.blocks) == 0 {
+		return nil
+	}
+	if max := s.cfg.MaxOpenChannels; max > 0 && len(s.channelQueue) >= max &&
+		(s.currentChannel == nil || s.currentChannel.IsFull()) {
+		s.log.Debug("Max open channels reached, not opening new channel", "open_channels", len(s.channelQueue))
+		return nil
 	}
 
 	if err := s.ensureChannelWithSpace(l1Head); err != nil {
-		return txData{}, err
+		return err
 	}
 
-	if err := s.processBlocks(); err != nil {
-		return txData{}, err
+	s.compressing = true
+	go s.compress(s.currentChannel, s.blocks, l1Head.ID())
+	return nil
+}
+
+// compress adds the given blocks to the channel and creates frames from its
+// compressed data. It runs in the background without holding the lock, so it
+// must not access any other state than the passed channel. The blocks queue
+// only gets updated with the processed blocks once the compression is done.
+func (s *channelManager) compress(ch *channel, blocks []*types.Block, l1Head eth.BlockID) {
+	start := time.Now()
+	blocksAdded, err := s.processBlocks(ch, blocks)
+	closed := false
+	if err == nil {
+		// Register current L1 head only after all pending blocks have been
+		// processed. Even if a timeout will be triggered now, it is better to have
+		// all pending blocks be included in this channel for submission.
+		s.registerL1Block(ch, l1Head)
+		closed, err = s.outputFrames(ch)
 	}
+	elapsed := time.Since(start)
 
-	// Register current L1 head only after all pending blocks have been
-	// processed. Even if a timeout will be triggered now, it is better to have
-	// all pending blocks be included in this channel for submission.
-	s.registerL1Block(l1Head)
+	defer s.syncJournal()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ch.compressionTime += elapsed
+	// New blocks may have been appended to the queue in the meantime.
+	s.blocks = s.blocks[blocksAdded:]
+	s.compressing = false
+	s.compressErr = err
+	if closed {
+		s.channelClosed(ch)
+	}
+	s.compressDone.Broadcast()
+}
 
-	if err := s.outputFrames(); err != nil {
-		return txData{}, err
+// waitCompression waits until a running compression is done. It must be
+// called while holding the lock, which is released while waiting.
+func (s *channelManager) waitCompression() {
+	for s.compressing {
+		s.compressDone.Wait()
 	}
+}
 
-	return s.nextTxData(s.currentChannel)
+// takeCompressErr returns and resets the error of the last compression run.
+func (s *channelManager) takeCompressErr() error {
+	err := s.compressErr
+	s.compressErr = nil
+	return err
 }
 
 // ensureChannelWithSpace ensures currentChannel is populated with a channel that has
 // space for more data (i.e. channel.IsFull returns false). If currentChannel is nil
-// or full, a new channel is created.
-func (s *channelManager) ensureChannelWithSpace(l1Head eth.BlockID) error {
+// or full, a new channel is created, with a batch type that is valid from the L1 head on.
+func (s *channelManager) ensureChannelWithSpace(l1Head eth.L1BlockRef) error {
 	if s.currentChannel != nil && !s.currentChannel.IsFull() {
 		return nil
 	}
 
-	pc, err := newChannel(s.log, s.metr, s.cfg)
+	cfg := s.cfg
+	// Wider channels amortize the fixed costs of batcher txs while L1 is expensive.
+	if s.throttle != nil && cfg.MaxChannelDuration != 0 && s.throttle.ThrottledChannelDuration > cfg.MaxChannelDuration {
+		cfg.MaxChannelDuration = s.throttle.ThrottledChannelDuration
+	}
+	pc, err := newChannel(s.log, s.metr, cfg, s.rollupCfg, l1Head.Time)
 	if err != nil {
 		return fmt.Errorf("creating new channel: %w", err)
 	}
@@ -204,104 +481,147 @@ func (s *channelManager) ensureChannelWithSpace(l1Head eth.BlockID) error {
 	s.log.Info("Created channel",
 		"id", pc.ID(),
 		"l1Head", l1Head,
-		"blocks_pending", len(s.blocks))
+		"blocks_pending", len(s.blocks),
+		"open_channels", len(s.channelQueue))
 	s.metr.RecordChannelOpened(pc.ID(), len(s.blocks))
+	s.metr.RecordOpenChannels(len(s.channelQueue))
 
 	return nil
 }
 
-// registerL1Block registers the given block at the pending channel.
-func (s *channelManager) registerL1Block(l1Head eth.BlockID) {
-	s.currentChannel.RegisterL1Block(l1Head.Number)
+// registerL1Block registers the given block at the channel.
+func (s *channelManager) registerL1Block(ch *channel, l1Head eth.BlockID) {
+	ch.RegisterL1Block(l1Head.Number)
 	s.log.Debug("new L1-block registered at channel builder",
 		"l1Head", l1Head,
-		"channel_full", s.currentChannel.IsFull(),
-		"full_reason", s.currentChannel.FullErr(),
+		"channel_full", ch.IsFull(),
+		"full_reason", ch.FullErr(),
 	)
 }
 
-// processBlocks adds blocks from the blocks queue to the pending channel until
-// either the queue got exhausted or the channel is full.
-func (s *channelManager) processBlocks() error {
+// processBlocks adds the given blocks to the channel until either all blocks
+// got added or the channel is full. It returns the number of added blocks.
+func (s *channelManager) processBlocks(ch *channel, blocks []*types.Block) (int, error) {
 	var (
 		blocksAdded int
 		_chFullErr  *ChannelFullError // throw away, just for type checking
 		latestL2ref eth.L2BlockRef
 	)
-	for i, block := range s.blocks {
-		l1info, err := s.currentChannel.AddBlock(block)
+	for i, block := range blocks {
+		l1info, err := ch.AddBlock(block)
 		if errors.As(err, &_chFullErr) {
 			// current block didn't get added because channel is already full
 			break
 		} else if err != nil {
-			return fmt.Errorf("adding block[%d] to channel builder: %w", i, err)
+			return blocksAdded, fmt.Errorf("adding block[%d] to channel builder: %w", i, err)
 		}
-		s.log.Debug("Added block to channel", "channel", s.currentChannel.ID(), "block", block)
+		s.log.Debug("Added block to channel", "channel", ch.ID(), "block", block)
 
 		blocksAdded += 1
 		latestL2ref = l2BlockRefFromBlockAndL1Info(block, l1info)
 		s.metr.RecordL2BlockInChannel(block)
 		// current block got added but channel is now full
-		if s.currentChannel.IsFull() {
+		if ch.IsFull() {
 			break
 		}
 	}
 
-	if blocksAdded == len(s.blocks) {
-		// all blocks processed, reuse slice
-		s.blocks = s.blocks[:0]
-	} else {
-		// remove processed blocks
-		s.blocks = s.blocks[blocksAdded:]
-	}
-
 	s.metr.RecordL2BlocksAdded(latestL2ref,
 		blocksAdded,
-		len(s.blocks),
-		s.currentChannel.InputBytes(),
-		s.currentChannel.ReadyBytes())
+		len(blocks)-blocksAdded,
+		ch.InputBytes(),
+		ch.ReadyBytes())
 	s.log.Debug("Added blocks to channel",
 		"blocks_added", blocksAdded,
-		"blocks_pending", len(s.blocks),
-		"channel_full", s.currentChannel.IsFull(),
-		"input_bytes", s.currentChannel.InputBytes(),
-		"ready_bytes", s.currentChannel.ReadyBytes(),
+		"blocks_pending", len(blocks)-blocksAdded,
+		"channel_full", ch.IsFull(),
+		"input_bytes", ch.InputBytes(),
+		"ready_bytes", ch.ReadyBytes(),
 	)
-	return nil
+	return blocksAdded, nil
 }
 
-func (s *channelManager) outputFrames() error {
-	if err := s.currentChannel.OutputFrames(); err != nil {
-		return fmt.Errorf("creating frames with channel builder: %w", err)
-	}
-	if !s.currentChannel.IsFull() {
-		return nil
+// outputFrames creates new frames with the channel builder. It returns whether
+// the channel got closed by this call.
+func (s *channelManager) outputFrames(ch *channel) (bool, error) {
+	wasClosed := ch.channelBuilder.IsClosed()
+	if err := ch.OutputFrames(); err != nil {
+		return false, fmt.Errorf("creating frames with channel builder: %w", err)
 	}
+	return !wasClosed && ch.channelBuilder.IsClosed(), nil
+}
 
-	inBytes, outBytes := s.currentChannel.InputBytes(), s.currentChannel.OutputBytes()
+// channelClosed records a closed channel and persists it in the journal.
+func (s *channelManager) channelClosed(ch *channel) {
+	inBytes, outBytes := ch.InputBytes(), ch.OutputBytes()
 	s.metr.RecordChannelClosed(
-		s.currentChannel.ID(),
+		ch.ID(),
 		len(s.blocks),
-		s.currentChannel.TotalFrames(),
+		ch.TotalFrames(),
 		inBytes,
 		outBytes,
-		s.currentChannel.FullErr(),
+		ch.FullErr(),
 	)
+	s.metr.RecordChannelCompressionTime(ch.ID(), ch.compressionTime)
 
 	var comprRatio float64
 	if inBytes > 0 {
 		comprRatio = float64(outBytes) / float64(inBytes)
 	}
 	s.log.Info("Channel closed",
-		"id", s.currentChannel.ID(),
+		"id", ch.ID(),
 		"blocks_pending", len(s.blocks),
-		"num_frames", s.currentChannel.TotalFrames(),
+		"num_frames", ch.TotalFrames(),
 		"input_bytes", inBytes,
 		"output_bytes", outBytes,
-		"full_reason", s.currentChannel.FullErr(),
+		"full_reason", ch.FullErr(),
 		"compr_ratio", comprRatio,
+		"compression_time", ch.compressionTime,
 	)
-	return nil
+	s.persist()
+}
+
+// persist stores all closed channels of the channel queue in the journal, if enabled.
+// Channels that are still open are not journaled. Their blocks are loaded and
+// encoded into a new channel again after a restart.
+// The state is collected under the lock, and written to the journal by
+// syncJournal once the lock is released. Errors are only logged, since the
+// journal is an optimization and must not halt batch submission.
+func (s *channelManager) persist() {
+	if s.journal == nil {
+		return
+	}
+	state := &JournalState{}
+	for _, ch := range s.channelQueue {
+		// the current channel is never closed before its compression is done
+		if s.compressing && ch == s.currentChannel {
+			continue
+		}
+		if ch.channelBuilder.IsClosed() {
+			state.Channels = append(state.Channels, ch.journal())
+		}
+	}
+	s.journal.Store(state)
+}
+
+// restore adds channels that got restored from the journal to the channel
+// queue. tip must be the last block of the restored channels, so that only
+// blocks after it are added to the state.
+func (s *channelManager) restore(channels []*channel, tip common.Hash) {
+	defer s.syncJournal()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, ch := range channels {
+		s.log.Info("Restored channel from journal",
+			"id", ch.ID(),
+			"num_blocks", len(ch.channelBuilder.Blocks()),
+			"num_frames", ch.TotalFrames(),
+			"pending_frames", ch.PendingFrames(),
+			"confirmed_txs", len(ch.confirmedTransactions))
+		s.channelQueue = append(s.channelQueue, ch)
+	}
+	s.tip = tip
+	s.persist()
 }
 
 // AddL2Block adds an L2 block to the internal blocks queue. It returns ErrReorg
@@ -335,9 +655,12 @@ func l2BlockRefFromBlockAndL1Info(block *types.Block, l1info derive.L1BlockInfo)
 // Close closes the current pending channel, if one exists, outputs any remaining frames,
 // and prevents the creation of any new channels.
 // Any outputted frames still need to be published.
+// It waits for a running compression to finish first.
 func (s *channelManager) Close() error {
+	defer s.syncJournal()
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	s.waitCompression()
 	if s.closed {
 		return nil
 	}
@@ -357,5 +680,12 @@ func (s *channelManager) Close() error {
 
 	s.currentChannel.Close()
 
-	return s.outputFrames()
+	closed, err := s.outputFrames(s.currentChannel)
+	if err != nil {
+		return err
+	}
+	if closed {
+		s.channelClosed(s.currentChannel)
+	}
+	return nil
 }
@@ -9,6 +9,7 @@ import (
 
 	"github.com/ethereum-optimism/optimism/op-batcher/compressor"
 	"github.com/ethereum-optimism/optimism/op-batcher/metrics"
+	"github.com/ethereum-optimism/optimism/op-batcher/rpc"
 	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
 	derivetest "github.com/ethereum-optimism/optimism/op-node/rollup/derive/test"
 	"github.com/ethereum-optimism/optimism/op-service/eth"
@@ -23,7 +24,7 @@ import (
 // detects a reorg when it has cached L1 blocks.
 func TestChannelManagerReturnsErrReorg(t *testing.T) {
 	log := testlog.Logger(t, log.LvlCrit)
-	m := NewChannelManager(log, metrics.NoopMetrics, ChannelConfig{})
+	m := NewChannelManager(log, metrics.NoopMetrics, ChannelConfig{}, &defaultTestRollupConfig)
 
 	a := types.NewBlock(&types.Header{
 		Number: big.NewInt(0),
@@ -61,16 +62,16 @@ func TestChannelManagerReturnsErrReorgWhenDrained(t *testing.T) {
 				TargetNumFrames:  1,
 				ApproxComprRatio: 1.0,
 			},
-		})
+		}, &defaultTestRollupConfig)
 
 	a := newMiniL2Block(0)
 	x := newMiniL2BlockWithNumberParent(0, big.NewInt(1), common.Hash{0xff})
 
 	require.NoError(t, m.AddL2Block(a))
 
-	_, err := m.TxData(eth.BlockID{})
+	_, err := m.TxData(eth.L1BlockRef{})
 	require.NoError(t, err)
-	_, err = m.TxData(eth.BlockID{})
+	_, err = m.TxData(eth.L1BlockRef{})
 	require.ErrorIs(t, err, io.EOF)
 
 	require.ErrorIs(t, m.AddL2Block(x), ErrReorg)
@@ -96,7 +97,7 @@ func TestChannelManager_Clear(t *testing.T) {
 			TargetNumFrames:  1,
 			ApproxComprRatio: 1.0,
 		},
-	})
+	}, &defaultTestRollupConfig)
 
 	// Channel Manager state should be empty by default
 	require.Empty(m.blocks)
@@ -108,14 +109,14 @@ func TestChannelManager_Clear(t *testing.T) {
 	// Add a block to the channel manager
 	a, _ := derivetest.RandomL2Block(rng, 4)
 	newL1Tip := a.Hash()
-	l1BlockID := eth.BlockID{
+	l1Head := eth.L1BlockRef{
 		Hash:   a.Hash(),
 		Number: a.NumberU64(),
 	}
 	require.NoError(m.AddL2Block(a))
 
 	// Make sure there is a channel
-	require.NoError(m.ensureChannelWithSpace(l1BlockID))
+	require.NoError(m.ensureChannelWithSpace(l1Head))
 	require.NotNil(m.currentChannel)
 	require.Len(m.currentChannel.confirmedTransactions, 0)
 
@@ -123,10 +124,12 @@ func TestChannelManager_Clear(t *testing.T) {
 	// We should have a pending channel with 1 frame
 	// and no more blocks since processBlocks consumes
 	// the list
-	require.NoError(m.processBlocks())
+	blocksAdded, err := m.processBlocks(m.currentChannel, m.blocks)
+	require.NoError(err)
+	m.blocks = m.blocks[blocksAdded:]
 	require.NoError(m.currentChannel.channelBuilder.co.Flush())
 	require.NoError(m.currentChannel.OutputFrames())
-	_, err := m.nextTxData(m.currentChannel)
+	_, err = m.nextTxData(m.currentChannel)
 	require.NoError(err)
 	require.Len(m.blocks, 0)
 	require.Equal(newL1Tip, m.tip)
@@ -165,13 +168,13 @@ func TestChannelManager_TxResend(t *testing.T) {
 				TargetNumFrames:  1,
 				ApproxComprRatio: 1.0,
 			},
-		})
+		}, &defaultTestRollupConfig)
 
 	a, _ := derivetest.RandomL2Block(rng, 4)
 
 	require.NoError(m.AddL2Block(a))
 
-	txdata0, err := m.TxData(eth.BlockID{})
+	txdata0, err := m.TxData(eth.L1BlockRef{})
 	require.NoError(err)
 	txdata0bytes := txdata0.Bytes()
 	data0 := make([]byte, len(txdata0bytes))
@@ -179,13 +182,13 @@ func TestChannelManager_TxResend(t *testing.T) {
 	copy(data0, txdata0bytes)
 
 	// ensure channel is drained
-	_, err = m.TxData(eth.BlockID{})
+	_, err = m.TxData(eth.L1BlockRef{})
 	require.ErrorIs(err, io.EOF)
 
 	// requeue frame
 	m.TxFailed(txdata0.ID())
 
-	txdata1, err := m.TxData(eth.BlockID{})
+	txdata1, err := m.TxData(eth.L1BlockRef{})
 	require.NoError(err)
 
 	data1 := txdata1.Bytes()
@@ -209,7 +212,7 @@ func TestChannelManagerCloseBeforeFirstUse(t *testing.T) {
 				TargetFrameSize:  0,
 				ApproxComprRatio: 1.0,
 			},
-		})
+		}, &defaultTestRollupConfig)
 
 	a, _ := derivetest.RandomL2Block(rng, 4)
 
@@ -218,7 +221,7 @@ func TestChannelManagerCloseBeforeFirstUse(t *testing.T) {
 	err := m.AddL2Block(a)
 	require.NoError(err, "Failed to add L2 block")
 
-	_, err = m.TxData(eth.BlockID{})
+	_, err = m.TxData(eth.L1BlockRef{})
 	require.ErrorIs(err, io.EOF, "Expected closed channel manager to contain no tx data")
 }
 
@@ -237,19 +240,19 @@ func TestChannelManagerCloseNoPendingChannel(t *testing.T) {
 				TargetNumFrames:  1,
 				ApproxComprRatio: 1.0,
 			},
-		})
+		}, &defaultTestRollupConfig)
 	a := newMiniL2Block(0)
 	b := newMiniL2BlockWithNumberParent(0, big.NewInt(1), a.Hash())
 
 	err := m.AddL2Block(a)
 	require.NoError(err, "Failed to add L2 block")
 
-	txdata, err := m.TxData(eth.BlockID{})
+	txdata, err := m.TxData(eth.L1BlockRef{})
 	require.NoError(err, "Expected channel manager to return valid tx data")
 
 	m.TxConfirmed(txdata.ID(), eth.BlockID{})
 
-	_, err = m.TxData(eth.BlockID{})
+	_, err = m.TxData(eth.L1BlockRef{})
 	require.ErrorIs(err, io.EOF, "Expected channel manager to EOF")
 
 	m.Close()
@@ -257,7 +260,7 @@ func TestChannelManagerCloseNoPendingChannel(t *testing.T) {
 	err = m.AddL2Block(b)
 	require.NoError(err, "Failed to add L2 block")
 
-	_, err = m.TxData(eth.BlockID{})
+	_, err = m.TxData(eth.L1BlockRef{})
 	require.ErrorIs(err, io.EOF, "Expected closed channel manager to return no new tx data")
 }
 
@@ -276,7 +279,7 @@ func TestChannelManagerClosePendingChannel(t *testing.T) {
 				TargetFrameSize:  1000,
 				ApproxComprRatio: 1.0,
 			},
-		})
+		}, &defaultTestRollupConfig)
 
 	a := newMiniL2Block(50_000)
 	b := newMiniL2BlockWithNumberParent(10, big.NewInt(1), a.Hash())
@@ -284,25 +287,25 @@ func TestChannelManagerClosePendingChannel(t *testing.T) {
 	err := m.AddL2Block(a)
 	require.NoError(err, "Failed to add L2 block")
 
-	txdata, err := m.TxData(eth.BlockID{})
+	txdata, err := m.TxData(eth.L1BlockRef{})
 	require.NoError(err, "Expected channel manager to produce valid tx data")
 
 	m.TxConfirmed(txdata.ID(), eth.BlockID{})
 
 	m.Close()
 
-	txdata, err = m.TxData(eth.BlockID{})
+	txdata, err = m.TxData(eth.L1BlockRef{})
 	require.NoError(err, "Expected channel manager to produce tx data from remaining L2 block data")
 
 	m.TxConfirmed(txdata.ID(), eth.BlockID{})
 
-	_, err = m.TxData(eth.BlockID{})
+	_, err = m.TxData(eth.L1BlockRef{})
 	require.ErrorIs(err, io.EOF, "Expected channel manager to have no more tx data")
 
 	err = m.AddL2Block(b)
 	require.NoError(err, "Failed to add L2 block")
 
-	_, err = m.TxData(eth.BlockID{})
+	_, err = m.TxData(eth.L1BlockRef{})
 	require.ErrorIs(err, io.EOF, "Expected closed channel manager to produce no more tx data")
 }
 
@@ -321,27 +324,308 @@ func TestChannelManagerCloseAllTxsFailed(t *testing.T) {
 				TargetFrameSize:  1000,
 				ApproxComprRatio: 1.0,
 			},
-		})
+		}, &defaultTestRollupConfig)
 
 	a := newMiniL2Block(50_000)
 
 	err := m.AddL2Block(a)
 	require.NoError(err, "Failed to add L2 block")
 
-	txdata, err := m.TxData(eth.BlockID{})
+	txdata, err := m.TxData(eth.L1BlockRef{})
 	require.NoError(err, "Expected channel manager to produce valid tx data")
 
 	m.TxFailed(txdata.ID())
 
 	// Show that this data will continue to be emitted as long as the transaction
 	// fails and the channel manager is not closed
-	txdata, err = m.TxData(eth.BlockID{})
+	txdata, err = m.TxData(eth.L1BlockRef{})
 	require.NoError(err, "Expected channel manager to re-attempt the failed transaction")
 
 	m.TxFailed(txdata.ID())
 
 	m.Close()
 
-	_, err = m.TxData(eth.BlockID{})
+	_, err = m.TxData(eth.L1BlockRef{})
 	require.ErrorIs(err, io.EOF, "Expected closed channel manager to produce no more tx data")
 }
+
+// TestChannelManagerBackgroundCompression ensures that the channel manager
+// compresses pending blocks into a new channel while the frames of a full
+// channel are submitted.
+func TestChannelManagerBackgroundCompression(t *testing.T) {
+	require := require.New(t)
+	log := testlog.Logger(t, log.LvlCrit)
+	m := NewChannelManager(log, metrics.NoopMetrics,
+		ChannelConfig{
+			MaxFrameSize:   1000,
+			ChannelTimeout: 1000,
+			CompressorConfig: compressor.Config{
+				TargetNumFrames:  1,
+				TargetFrameSize:  1000,
+				ApproxComprRatio: 1.0,
+			},
+		}, &defaultTestRollupConfig)
+
+	// random block data doesn't compress, so it results in many frames
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	a, _ := derivetest.RandomL2Block(rng, 100)
+	b := newMiniL2BlockWithNumberParent(10, new(big.Int).Add(a.Number(), common.Big1), a.Hash())
+
+	require.NoError(m.AddL2Block(a))
+	txdata, err := m.TxData(eth.L1BlockRef{})
+	require.NoError(err)
+	require.Len(m.channelQueue, 1)
+	first := m.channelQueue[0]
+	require.True(first.IsFull())
+	require.True(first.HasFrame(), "expected full channel to have remaining frames")
+
+	// The remaining frames of the first channel are submitted while the next
+	// block is compressed into a new channel.
+	require.NoError(m.AddL2Block(b))
+	next, err := m.TxData(eth.L1BlockRef{})
+	require.NoError(err)
+	require.Equal(txdata.ID().chID, next.ID().chID)
+
+	m.mu.Lock()
+	m.waitCompression()
+	require.NoError(m.compressErr)
+	require.Empty(m.blocks)
+	require.Len(m.channelQueue, 2)
+	require.Same(first, m.channelQueue[0])
+	m.mu.Unlock()
+}
+
+// TestChannelManagerMaxOpenChannels ensures that the channel manager doesn't
+// open more than the configured maximum number of channels.
+func TestChannelManagerMaxOpenChannels(t *testing.T) {
+	require := require.New(t)
+	log := testlog.Logger(t, log.LvlCrit)
+	m := NewChannelManager(log, metrics.NoopMetrics,
+		ChannelConfig{
+			MaxFrameSize:    120_000,
+			ChannelTimeout:  1000,
+			MaxOpenChannels: 1,
+			CompressorConfig: compressor.Config{
+				TargetNumFrames:  1,
+				TargetFrameSize:  1,
+				ApproxComprRatio: 1.0,
+			},
+		}, &defaultTestRollupConfig)
+
+	a := newMiniL2Block(0)
+	b := newMiniL2BlockWithNumberParent(0, big.NewInt(1), a.Hash())
+	require.NoError(m.AddL2Block(a))
+	require.NoError(m.AddL2Block(b))
+
+	txdata, err := m.TxData(eth.L1BlockRef{})
+	require.NoError(err)
+	require.Len(m.channelQueue, 1)
+	require.True(m.currentChannel.IsFull())
+
+	// The first channel is still open, so no second channel is opened.
+	_, err = m.TxData(eth.L1BlockRef{})
+	require.ErrorIs(err, io.EOF)
+	require.Len(m.channelQueue, 1)
+	require.Len(m.blocks, 1)
+
+	// Once the first channel is fully submitted, the next channel is opened.
+	m.TxConfirmed(txdata.ID(), eth.BlockID{})
+	require.Empty(m.channelQueue)
+	next, err := m.TxData(eth.L1BlockRef{})
+	require.NoError(err)
+	require.NotEqual(txdata.ID().chID, next.ID().chID)
+	require.Empty(m.blocks)
+}
+
+// TestChannelManagerThrottle ensures that the channel manager holds back frames
+// while submission is throttled, until the channel's deadline approaches.
+func TestChannelManagerThrottle(t *testing.T) {
+	require := require.New(t)
+	log := testlog.Logger(t, log.LvlCrit)
+	m := NewChannelManager(log, metrics.NoopMetrics,
+		ChannelConfig{
+			MaxFrameSize:       120_000,
+			ChannelTimeout:     1000,
+			SeqWindowSize:      200,
+			MaxChannelDuration: 10,
+			CompressorConfig: compressor.Config{
+				TargetNumFrames:  1,
+				TargetFrameSize:  1,
+				ApproxComprRatio: 1.0,
+			},
+		}, &defaultTestRollupConfig)
+	m.SetThrottle(&rpc.FeePolicy{
+		MaxBaseFeeGwei:           100,
+		ThrottledChannelDuration: 50,
+		UrgencyMargin:            10,
+	})
+
+	// The L1 origin of the block is L1 block 100, so the channel's deadline is
+	// the end of the sequencing window at L1 block 300.
+	a := newMiniL2Block(0)
+	require.NoError(m.AddL2Block(a))
+	_, err := m.TxData(eth.L1BlockRef{Number: 100})
+	require.ErrorIs(err, io.EOF, "expected frames of non-urgent channel to be held back")
+	require.Len(m.channelQueue, 1)
+	ch := m.channelQueue[0]
+	require.True(ch.HasFrame())
+	require.EqualValues(300, ch.channelBuilder.Deadline())
+	require.EqualValues(50, ch.cfg.MaxChannelDuration, "expected widened channel duration")
+
+	_, err = m.TxData(eth.L1BlockRef{Number: 289})
+	require.ErrorIs(err, io.EOF)
+
+	txdata, err := m.TxData(eth.L1BlockRef{Number: 290})
+	require.NoError(err, "expected urgent channel to be submitted")
+	require.Equal(ch.ID(), txdata.ID().chID)
+
+	// Frames are submitted right away again once throttling is disabled.
+	b := newMiniL2BlockWithNumberParent(0, big.NewInt(1), a.Hash())
+	require.NoError(m.AddL2Block(b))
+	m.SetThrottle(nil)
+	_, err = m.TxData(eth.L1BlockRef{Number: 100})
+	require.NoError(err)
+}
+
+// TestChannelManagerFlushChannel ensures that flushing closes the current
+// channel, while new channels are still opened afterwards.
+func TestChannelManagerFlushChannel(t *testing.T) {
+	require := require.New(t)
+	log := testlog.Logger(t, log.LvlCrit)
+	m := NewChannelManager(log, metrics.NoopMetrics,
+		ChannelConfig{
+			MaxFrameSize:   120_000,
+			ChannelTimeout: 1000,
+			CompressorConfig: compressor.Config{
+				TargetNumFrames:  1,
+				TargetFrameSize:  120_000,
+				ApproxComprRatio: 1.0,
+			},
+		}, &defaultTestRollupConfig)
+
+	// Nothing to flush without a channel.
+	require.ErrorIs(m.FlushChannel(eth.L1BlockRef{}), ErrNothingToFlush)
+
+	a := newMiniL2Block(0)
+	require.NoError(m.AddL2Block(a))
+	_, err := m.TxData(eth.L1BlockRef{})
+	require.ErrorIs(err, io.EOF, "expected no frame of non-full channel")
+
+	state := m.State()
+	require.Empty(state.PendingBlocks)
+	require.Len(state.Channels, 1)
+	require.True(state.Channels[0].Current)
+	require.Equal([]eth.BlockID{eth.ToBlockID(a)}, state.Channels[0].Blocks)
+	require.Empty(state.Channels[0].FullReason)
+
+	require.NoError(m.FlushChannel(eth.L1BlockRef{}))
+	txdata, err := m.TxData(eth.L1BlockRef{})
+	require.NoError(err, "expected frame of flushed channel")
+
+	state = m.State()
+	require.Len(state.Channels, 1)
+	cs := state.Channels[0]
+	require.Equal(txdata.ID().chID, cs.ID)
+	require.Contains(cs.FullReason, ErrTerminated.Error())
+	require.Equal(1, cs.TotalFrames)
+	require.Zero(cs.QueuedFrames)
+	require.Equal([]rpc.TxState{{Frames: []uint16{0}}}, cs.Txs)
+
+	// The flushed channel has nothing left to flush.
+	require.ErrorIs(m.FlushChannel(eth.L1BlockRef{}), ErrNothingToFlush)
+
+	// A new channel is opened for the next block.
+	b := newMiniL2BlockWithNumberParent(0, big.NewInt(1), a.Hash())
+	require.NoError(m.AddL2Block(b))
+	_, err = m.TxData(eth.L1BlockRef{})
+	require.ErrorIs(err, io.EOF)
+	require.Len(m.channelQueue, 2)
+	require.False(m.closed)
+
+	// Pending blocks are added to the current channel before it's flushed.
+	c := newMiniL2BlockWithNumberParent(0, big.NewInt(2), b.Hash())
+	require.NoError(m.AddL2Block(c))
+	require.NoError(m.FlushChannel(eth.L1BlockRef{}))
+	state = m.State()
+	require.Empty(state.PendingBlocks)
+	require.Len(state.Channels, 2)
+	require.Equal([]eth.BlockID{eth.ToBlockID(b), eth.ToBlockID(c)}, state.Channels[1].Blocks)
+	require.Contains(state.Channels[1].FullReason, ErrTerminated.Error())
+
+	// Pending blocks of an empty channel manager are flushed into a new channel.
+	m.Clear()
+	d := newMiniL2Block(0)
+	require.NoError(m.AddL2Block(d))
+	require.NoError(m.FlushChannel(eth.L1BlockRef{}))
+	txdata, err = m.TxData(eth.L1BlockRef{})
+	require.NoError(err, "expected frame of flushed channel")
+	require.Len(m.channelQueue, 1)
+	require.Equal(m.channelQueue[0].ID(), txdata.ID().chID)
+}
+
+// TestChannelManagerUpdateConfig ensures that config updates only apply to
+// new channels and that invalid updates are rejected.
+func TestChannelManagerUpdateConfig(t *testing.T) {
+	require := require.New(t)
+	log := testlog.Logger(t, log.LvlCrit)
+	cfg := ChannelConfig{
+		MaxFrameSize:   120_000,
+		ChannelTimeout: 1000,
+		CompressorConfig: compressor.Config{
+			TargetNumFrames:  1,
+			TargetFrameSize:  120_000,
+			ApproxComprRatio: 1.0,
+		},
+	}
+	m := NewChannelManager(log, metrics.NoopMetrics, cfg, &defaultTestRollupConfig)
+
+	require.NoError(m.AddL2Block(newMiniL2Block(0)))
+	_, err := m.TxData(eth.L1BlockRef{})
+	require.ErrorIs(err, io.EOF)
+
+	require.NoError(m.UpdateConfig(func(cfg *ChannelConfig) {
+		cfg.MaxFrameSize = 1000
+		cfg.MaxChannelDuration = 5
+	}))
+	require.EqualValues(1000, m.Config().MaxFrameSize)
+	require.EqualValues(5, m.Config().MaxChannelDuration)
+	require.Equal(cfg, m.currentChannel.cfg, "expected open channel to keep its config")
+
+	require.Error(m.UpdateConfig(func(cfg *ChannelConfig) {
+		cfg.MaxFrameSize = 0
+	}))
+	require.EqualValues(1000, m.Config().MaxFrameSize)
+}
+
+// TestChannelManagerSpanBatchActivation ensures that the channel manager only
+// opens span batch channels once the span batch upgrade is active at the time
+// of the L1 head, so that the channels are accepted by derivation when they
+// get included in L1.
+func TestChannelManagerSpanBatchActivation(t *testing.T) {
+	require := require.New(t)
+	log := testlog.Logger(t, log.LvlCrit)
+	rollupCfg := defaultTestRollupConfig
+	activation := uint64(1000)
+	rollupCfg.SpanBatchTime = &activation
+	m := NewChannelManager(log, metrics.NoopMetrics, defaultTestChannelConfig, &rollupCfg)
+
+	for _, test := range []struct {
+		l1HeadTime uint64
+		spanBatch  bool
+	}{
+		{l1HeadTime: activation - 1, spanBatch: false},
+		{l1HeadTime: activation, spanBatch: true},
+		{l1HeadTime: activation + 1, spanBatch: true},
+	} {
+		if m.currentChannel != nil {
+			m.currentChannel.channelBuilder.setFullErr(ErrTerminated)
+		}
+		require.NoError(m.ensureChannelWithSpace(eth.L1BlockRef{Number: 1, Time: test.l1HeadTime}))
+		if test.spanBatch {
+			require.IsType(&derive.SpanChannelOut{}, m.currentChannel.channelBuilder.co, "L1 head time %d", test.l1HeadTime)
+		} else {
+			require.IsType(&derive.ChannelOut{}, m.currentChannel.channelBuilder.co, "L1 head time %d", test.l1HeadTime)
+		}
+	}
+	require.Len(m.channelQueue, 3)
+}
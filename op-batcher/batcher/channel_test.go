@@ -20,13 +20,13 @@ func TestChannelTimeout(t *testing.T) {
 	log := testlog.Logger(t, log.LvlCrit)
 	m := NewChannelManager(log, metrics.NoopMetrics, ChannelConfig{
 		ChannelTimeout: 100,
-	})
+	}, &defaultTestRollupConfig)
 
 	// Pending channel is nil so is cannot be timed out
 	require.Nil(t, m.currentChannel)
 
 	// Set the pending channel
-	require.NoError(t, m.ensureChannelWithSpace(eth.BlockID{}))
+	require.NoError(t, m.ensureChannelWithSpace(eth.L1BlockRef{}))
 	channel := m.currentChannel
 	require.NotNil(t, channel)
 
@@ -61,7 +61,7 @@ func TestChannelTimeout(t *testing.T) {
 // TestChannelNextTxData checks the nextTxData function.
 func TestChannelNextTxData(t *testing.T) {
 	log := testlog.Logger(t, log.LvlCrit)
-	m := NewChannelManager(log, metrics.NoopMetrics, ChannelConfig{})
+	m := NewChannelManager(log, metrics.NoopMetrics, ChannelConfig{}, &defaultTestRollupConfig)
 
 	// Nil pending channel should return EOF
 	returnedTxData, err := m.nextTxData(nil)
@@ -71,7 +71,7 @@ func TestChannelNextTxData(t *testing.T) {
 	// Set the pending channel
 	// The nextTxData function should still return EOF
 	// since the pending channel has no frames
-	require.NoError(t, m.ensureChannelWithSpace(eth.BlockID{}))
+	require.NoError(t, m.ensureChannelWithSpace(eth.L1BlockRef{}))
 	channel := m.currentChannel
 	require.NotNil(t, channel)
 	returnedTxData, err = m.nextTxData(channel)
@@ -92,7 +92,7 @@ func TestChannelNextTxData(t *testing.T) {
 
 	// Now the nextTxData function should return the frame
 	returnedTxData, err = m.nextTxData(channel)
-	expectedTxData := txData{frame}
+	expectedTxData := singleFrameTxData(frame)
 	expectedChannelID := expectedTxData.ID()
 	require.NoError(t, err)
 	require.Equal(t, expectedTxData, returnedTxData)
@@ -109,11 +109,11 @@ func TestChannelTxConfirmed(t *testing.T) {
 		// channels on confirmation. This would result in [TxConfirmed]
 		// clearing confirmed transactions, and reseting the pendingChannels map
 		ChannelTimeout: 10,
-	})
+	}, &defaultTestRollupConfig)
 
 	// Let's add a valid pending transaction to the channel manager
 	// So we can demonstrate that TxConfirmed's correctness
-	require.NoError(t, m.ensureChannelWithSpace(eth.BlockID{}))
+	require.NoError(t, m.ensureChannelWithSpace(eth.L1BlockRef{}))
 	channelID := m.currentChannel.ID()
 	frame := frameData{
 		data: []byte{},
@@ -125,7 +125,7 @@ func TestChannelTxConfirmed(t *testing.T) {
 	m.currentChannel.channelBuilder.PushFrame(frame)
 	require.Equal(t, 1, m.currentChannel.PendingFrames())
 	returnedTxData, err := m.nextTxData(m.currentChannel)
-	expectedTxData := txData{frame}
+	expectedTxData := singleFrameTxData(frame)
 	expectedChannelID := expectedTxData.ID()
 	require.NoError(t, err)
 	require.Equal(t, expectedTxData, returnedTxData)
@@ -157,11 +157,11 @@ func TestChannelTxConfirmed(t *testing.T) {
 func TestChannelTxFailed(t *testing.T) {
 	// Create a channel manager
 	log := testlog.Logger(t, log.LvlCrit)
-	m := NewChannelManager(log, metrics.NoopMetrics, ChannelConfig{})
+	m := NewChannelManager(log, metrics.NoopMetrics, ChannelConfig{}, &defaultTestRollupConfig)
 
 	// Let's add a valid pending transaction to the channel
 	// manager so we can demonstrate correctness
-	require.NoError(t, m.ensureChannelWithSpace(eth.BlockID{}))
+	require.NoError(t, m.ensureChannelWithSpace(eth.L1BlockRef{}))
 	channelID := m.currentChannel.ID()
 	frame := frameData{
 		data: []byte{},
@@ -173,7 +173,7 @@ func TestChannelTxFailed(t *testing.T) {
 	m.currentChannel.channelBuilder.PushFrame(frame)
 	require.Equal(t, 1, m.currentChannel.PendingFrames())
 	returnedTxData, err := m.nextTxData(m.currentChannel)
-	expectedTxData := txData{frame}
+	expectedTxData := singleFrameTxData(frame)
 	expectedChannelID := expectedTxData.ID()
 	require.NoError(t, err)
 	require.Equal(t, expectedTxData, returnedTxData)
@@ -194,3 +194,50 @@ func TestChannelTxFailed(t *testing.T) {
 	// There should be a frame in the pending channel now
 	require.Equal(t, 1, m.currentChannel.PendingFrames())
 }
+
+// TestChannelNextTxDataBlobs checks that frames are packed into blob transactions,
+// and that all frames of a failed blob transaction are re-queued.
+func TestChannelNextTxDataBlobs(t *testing.T) {
+	log := testlog.Logger(t, log.LvlCrit)
+	rollupCfg := defaultTestRollupConfig
+	rollupCfg.BlobsEnabledL1Timestamp = new(uint64)
+	m := NewChannelManager(log, metrics.NoopMetrics, ChannelConfig{
+		UseBlobs: true,
+	}, &rollupCfg)
+	require.NoError(t, m.ensureChannelWithSpace(eth.L1BlockRef{}))
+	channel := m.currentChannel
+
+	numFrames := eth.MaxBlobsPerBlobTx + 2
+	for i := 0; i < numFrames; i++ {
+		channel.channelBuilder.PushFrame(frameData{
+			data: []byte{byte(i)},
+			id:   frameID{chID: channel.ID(), frameNumber: uint16(i)},
+		})
+	}
+
+	txdata, err := m.nextTxData(channel)
+	require.NoError(t, err)
+	require.True(t, txdata.asBlob)
+	require.Len(t, txdata.Frames(), eth.MaxBlobsPerBlobTx)
+	require.Equal(t, frameID{chID: channel.ID(), frameNumber: 0}, txdata.ID())
+	require.Equal(t, 2, channel.PendingFrames())
+
+	// Every frame is encoded in its own blob, prefixed with the derivation version.
+	blobs, err := txdata.Blobs()
+	require.NoError(t, err)
+	require.Len(t, blobs, eth.MaxBlobsPerBlobTx)
+	for i, blob := range blobs {
+		data, err := blob.ToData()
+		require.NoError(t, err)
+		require.Equal(t, eth.Data{derive.DerivationVersion0, byte(i)}, data)
+	}
+
+	txdata2, err := m.nextTxData(channel)
+	require.NoError(t, err)
+	require.Len(t, txdata2.Frames(), 2)
+	require.Equal(t, 0, channel.PendingFrames())
+
+	m.TxFailed(txdata.ID())
+	require.Equal(t, eth.MaxBlobsPerBlobTx, channel.PendingFrames())
+	require.Len(t, channel.pendingTransactions, 1)
+}
@@ -1,12 +1,16 @@
 package batcher
 
 import (
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/urfave/cli/v2"
 
 	"github.com/ethereum-optimism/optimism/op-batcher/compressor"
 	"github.com/ethereum-optimism/optimism/op-batcher/flags"
+	"github.com/ethereum-optimism/optimism/op-batcher/rpc"
+	"github.com/ethereum-optimism/optimism/op-service/dial"
 	oplog "github.com/ethereum-optimism/optimism/op-service/log"
 	opmetrics "github.com/ethereum-optimism/optimism/op-service/metrics"
 	oppprof "github.com/ethereum-optimism/optimism/op-service/pprof"
@@ -47,22 +51,53 @@ type CLIConfig struct {
 	// transactions sent to the transaction manager (0 == no limit).
 	MaxPendingTransactions uint64
 
+	// MaxOpenChannels is the maximum number of channels that are open for
+	// submission at the same time (0 == no limit).
+	MaxOpenChannels int
+
 	// MaxL1TxSize is the maximum size of a batch tx submitted to L1.
 	MaxL1TxSize uint64
 
 	Stopped bool
 
+	// DataAvailabilityType is the type of L1 data availability the batch data
+	// is submitted to: calldata, blobs or DA commitments.
+	DataAvailabilityType flags.DataAvailabilityType
+
+	// DAServer is the DA server that batch data is stored on, if DA commitments are submitted.
+	DAServer string
+
+	// ChannelJournal is the file closed channels are persisted to, to resume
+	// their submission after a restart. Disabled if empty.
+	ChannelJournal string
+
+	// FeePolicy throttles batch submission while the L1 base fee is high.
+	FeePolicy rpc.FeePolicy
+
 	TxMgrConfig      txmgr.CLIConfig
 	LogConfig        oplog.CLIConfig
 	MetricsConfig    opmetrics.CLIConfig
 	PprofConfig      oppprof.CLIConfig
 	CompressorConfig compressor.CLIConfig
 	RPC              oprpc.CLIConfig
+	L1RPCConfig      dial.CLIConfig
 }
 
 func (c *CLIConfig) Check() error {
 	// TODO(7512): check the sanity of flags loaded directly https://github.com/ethereum-optimism/optimism/issues/7512
 
+	if !flags.ValidDataAvailabilityType(c.DataAvailabilityType) {
+		return fmt.Errorf("unknown data availability type: %q", c.DataAvailabilityType)
+	}
+	if c.DataAvailabilityType == flags.DACommitmentType && c.DAServer == "" {
+		return errors.New("the da-commitment data availability type requires a DA server")
+	}
+	if err := c.CompressorConfig.Check(); err != nil {
+		return err
+	}
+	if err := c.FeePolicy.Check(); err != nil {
+		return fmt.Errorf("invalid fee policy: %w", err)
+	}
 	if err := c.MetricsConfig.Check(); err != nil {
 		return err
 	}
@@ -75,6 +110,9 @@ func (c *CLIConfig) Check() error {
 	if err := c.RPC.Check(); err != nil {
 		return err
 	}
+	if err := c.L1RPCConfig.Check(); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -91,13 +129,23 @@ func NewConfig(ctx *cli.Context) *CLIConfig {
 		/* Optional Flags */
 		MaxPendingTransactions: ctx.Uint64(flags.MaxPendingTransactionsFlag.Name),
 		MaxChannelDuration:     ctx.Uint64(flags.MaxChannelDurationFlag.Name),
+		MaxOpenChannels:        ctx.Int(flags.MaxOpenChannelsFlag.Name),
 		MaxL1TxSize:            ctx.Uint64(flags.MaxL1TxSizeBytesFlag.Name),
 		Stopped:                ctx.Bool(flags.StoppedFlag.Name),
+		DataAvailabilityType:   flags.DataAvailabilityType(ctx.String(flags.DataAvailabilityTypeFlag.Name)),
+		DAServer:               ctx.String(flags.DAServerFlag.Name),
+		ChannelJournal:         ctx.String(flags.ChannelJournalFlag.Name),
 		TxMgrConfig:            txmgr.ReadCLIConfig(ctx),
 		LogConfig:              oplog.ReadCLIConfig(ctx),
 		MetricsConfig:          opmetrics.ReadCLIConfig(ctx),
 		PprofConfig:            oppprof.ReadCLIConfig(ctx),
 		CompressorConfig:       compressor.ReadCLIConfig(ctx),
 		RPC:                    oprpc.ReadCLIConfig(ctx),
+		L1RPCConfig:            dial.ReadCLIConfig(ctx),
+		FeePolicy: rpc.FeePolicy{
+			MaxBaseFeeGwei:           ctx.Uint64(flags.FeeThrottleMaxBaseFeeFlag.Name),
+			ThrottledChannelDuration: ctx.Uint64(flags.FeeThrottleChannelDurationFlag.Name),
+			UrgencyMargin:            ctx.Uint64(flags.FeeThrottleUrgencyMarginFlag.Name),
+		},
 	}
 }
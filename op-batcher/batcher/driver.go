@@ -10,13 +10,18 @@ import (
 	"sync"
 	"time"
 
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
 
 	"github.com/ethereum-optimism/optimism/op-batcher/metrics"
+	"github.com/ethereum-optimism/optimism/op-batcher/rpc"
 	"github.com/ethereum-optimism/optimism/op-node/rollup"
 	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
+	"github.com/ethereum-optimism/optimism/op-service/dacommit"
 	"github.com/ethereum-optimism/optimism/op-service/eth"
 	"github.com/ethereum-optimism/optimism/op-service/txmgr"
 )
@@ -25,6 +30,7 @@ var ErrBatcherNotRunning = errors.New("batcher is not running")
 
 type L1Client interface {
 	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
 }
 
 type L2Client interface {
@@ -35,6 +41,10 @@ type RollupClient interface {
 	SyncStatus(ctx context.Context) (*eth.SyncStatus, error)
 }
 
+type DAClient interface {
+	SetInput(ctx context.Context, data []byte) (dacommit.Commitment, error)
+}
+
 // DriverSetup is the collection of input/output interfaces and configuration that the driver operates on.
 type DriverSetup struct {
 	Log          log.Logger
@@ -46,6 +56,13 @@ type DriverSetup struct {
 	L2Client     L2Client
 	RollupClient RollupClient
 	Channel      ChannelConfig
+	// Journal is optional. If set, closed channels are persisted to it and restored on startup.
+	Journal ChannelJournal
+	// FeePolicy is the initial L1 fee policy. It can be changed at runtime with SetFeePolicy.
+	FeePolicy rpc.FeePolicy
+	// DAClient is optional. If set, batch data is stored on the DA server once DA commitments
+	// are active, and only the commitments to it are submitted to L1.
+	DAClient DAClient
 }
 
 // BatchSubmitter encapsulates a service responsible for submitting L2 tx
@@ -63,6 +80,10 @@ type BatchSubmitter struct {
 	mutex   sync.Mutex
 	running bool
 
+	feeMu        sync.Mutex
+	feePolicy    rpc.FeePolicy
+	feeThrottled bool
+
 	// lastStoredBlock is the last block loaded into `state`. If it is empty it should be set to the l2 safe head.
 	lastStoredBlock eth.BlockID
 	lastL1Tip       eth.L1BlockRef
@@ -72,10 +93,98 @@ type BatchSubmitter struct {
 
 // NewBatchSubmitter initializes the BatchSubmitter driver from a preconfigured DriverSetup
 func NewBatchSubmitter(setup DriverSetup) *BatchSubmitter {
+	state := NewChannelManager(setup.Log, setup.Metr, setup.Channel, setup.RollupCfg)
+	if setup.Journal != nil {
+		state.SetJournal(setup.Journal)
+	}
 	return &BatchSubmitter{
 		DriverSetup: setup,
-		state:       NewChannelManager(setup.Log, setup.Metr, setup.Channel),
+		state:       state,
+		feePolicy:   setup.FeePolicy,
+	}
+}
+
+// FlushChannel adds the pending blocks to the current channel and closes it, so
+// that its frames are submitted right away instead of waiting for the channel to
+// fill up. It returns ErrNothingToFlush if there are no blocks to flush.
+func (l *BatchSubmitter) FlushChannel(ctx context.Context) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if !l.running {
+		return ErrBatcherNotRunning
+	}
+	l1tip, _, err := l.l1Tip(ctx)
+	if err != nil {
+		return err
+	}
+	l.Log.Info("Flushing current channel", "l1Head", l1tip)
+	return l.state.FlushChannel(l1tip)
+}
+
+// ChannelManagerState returns a snapshot of the channel manager state.
+func (l *BatchSubmitter) ChannelManagerState() rpc.ChannelManagerState {
+	return l.state.State()
+}
+
+// GetChannelConfig returns the current values of the channel parameters that
+// can be changed at runtime.
+func (l *BatchSubmitter) GetChannelConfig() rpc.ChannelConfig {
+	cfg := l.state.Config()
+	return rpc.ChannelConfig{
+		MaxFrameSize:       &cfg.MaxFrameSize,
+		MaxChannelDuration: &cfg.MaxChannelDuration,
+		SubSafetyMargin:    &cfg.SubSafetyMargin,
+		MaxOpenChannels:    &cfg.MaxOpenChannels,
+	}
+}
+
+// SetChannelConfig changes the set channel parameters. They apply to all
+// channels that are opened afterwards. The compressor targets frames of the
+// new max frame size, if it is changed.
+func (l *BatchSubmitter) SetChannelConfig(update rpc.ChannelConfig) error {
+	err := l.state.UpdateConfig(func(cfg *ChannelConfig) {
+		if update.MaxFrameSize != nil {
+			cfg.MaxFrameSize = *update.MaxFrameSize
+			cfg.CompressorConfig.TargetFrameSize = *update.MaxFrameSize
+		}
+		if update.MaxChannelDuration != nil {
+			cfg.MaxChannelDuration = *update.MaxChannelDuration
+		}
+		if update.SubSafetyMargin != nil {
+			cfg.SubSafetyMargin = *update.SubSafetyMargin
+		}
+		if update.MaxOpenChannels != nil {
+			cfg.MaxOpenChannels = *update.MaxOpenChannels
+		}
+	})
+	if err != nil {
+		return err
+	}
+	cfg := l.state.Config()
+	l.Log.Info("Updated channel config", "max_frame_size", cfg.MaxFrameSize, "target_frame_size", cfg.CompressorConfig.TargetFrameSize,
+		"max_channel_duration", cfg.MaxChannelDuration, "sub_safety_margin", cfg.SubSafetyMargin, "max_open_channels", cfg.MaxOpenChannels)
+	return nil
+}
+
+// GetFeePolicy returns the current L1 fee policy.
+func (l *BatchSubmitter) GetFeePolicy() rpc.FeePolicy {
+	l.feeMu.Lock()
+	defer l.feeMu.Unlock()
+	return l.feePolicy
+}
+
+// SetFeePolicy replaces the L1 fee policy. It is applied with the next
+// transaction that is submitted. Invalid policies are rejected.
+func (l *BatchSubmitter) SetFeePolicy(policy rpc.FeePolicy) error {
+	if err := policy.Check(); err != nil {
+		return fmt.Errorf("invalid fee policy: %w", err)
 	}
+	l.feeMu.Lock()
+	defer l.feeMu.Unlock()
+	l.Log.Info("Setting L1 fee policy", "max_base_fee_gwei", policy.MaxBaseFeeGwei,
+		"throttled_channel_duration", policy.ThrottledChannelDuration, "urgency_margin", policy.UrgencyMargin)
+	l.feePolicy = policy
+	return nil
 }
 
 func (l *BatchSubmitter) StartBatchSubmitting() error {
@@ -250,6 +359,12 @@ func (l *BatchSubmitter) loop() {
 	receiptsCh := make(chan txmgr.TxReceipt[txData])
 	queue := txmgr.NewQueue[txData](l.killCtx, l.Txmgr, l.Cfg.MaxPendingTransactions)
 
+	if l.Journal != nil {
+		if err := l.restoreChannels(l.shutdownCtx); err != nil {
+			l.Log.Warn("Failed to restore channels from journal, starting at safe head", "err", err)
+		}
+	}
+
 	for {
 		select {
 		case <-ticker.C:
@@ -276,6 +391,125 @@ func (l *BatchSubmitter) loop() {
 	}
 }
 
+// restoreChannels restores the closed channels of the journal, so that their
+// submission is resumed instead of re-encoding and re-submitting their blocks.
+// In-flight transactions of the journal are reconciled against their L1 receipts,
+// so that frames that already landed on L1 are not submitted again.
+// Channels are restored in order, up to the first channel that cannot be
+// restored anymore, e.g. because of an L2 reorg or a channel timeout.
+func (l *BatchSubmitter) restoreChannels(ctx context.Context) error {
+	journal, err := l.Journal.Load()
+	if err != nil {
+		return err
+	}
+	if len(journal.Channels) == 0 {
+		return nil
+	}
+
+	tctx, cancel := context.WithTimeout(ctx, l.Cfg.NetworkTimeout)
+	syncStatus, err := l.RollupClient.SyncStatus(tctx)
+	cancel()
+	if err != nil {
+		return fmt.Errorf("failed to get sync status: %w", err)
+	}
+
+	var (
+		channels []*channel
+		last     eth.BlockID
+	)
+	next := syncStatus.SafeL2.Number + 1
+	for _, jc := range journal.Channels {
+		if len(jc.Blocks) == 0 {
+			l.Log.Warn("Dropping journaled channel without blocks", "id", jc.ID)
+			break
+		}
+		if jc.Blocks[len(jc.Blocks)-1].Number < next {
+			l.Log.Info("Journaled channel already safe", "id", jc.ID)
+			continue
+		}
+		if jc.Blocks[0].Number != next {
+			l.Log.Warn("Journaled channel does not continue the L2 chain, dropping remaining channels",
+				"id", jc.ID, "first_block", jc.Blocks[0], "expected_number", next)
+			break
+		}
+		blocks, err := l.loadJournaledBlocks(ctx, jc.Blocks)
+		if err != nil {
+			l.Log.Warn("Journaled channel cannot be restored, dropping remaining channels", "id", jc.ID, "err", err)
+			break
+		}
+		if err := l.reconcileJournaledTxs(ctx, &jc); err != nil {
+			return err
+		}
+
+		ch := restoreChannel(l.Log, l.Metr, l.Channel, jc, blocks)
+		if ch.isTimedOut() {
+			l.Log.Warn("Journaled channel timed out, dropping remaining channels", "id", jc.ID)
+			break
+		}
+		last = jc.Blocks[len(jc.Blocks)-1]
+		next = last.Number + 1
+		if ch.isFullySubmitted() {
+			l.Log.Info("Journaled channel is fully submitted", "id", jc.ID)
+			continue
+		}
+		channels = append(channels, ch)
+	}
+
+	if last == (eth.BlockID{}) {
+		return nil
+	}
+	l.state.restore(channels, last.Hash)
+	l.lastStoredBlock = last
+	return nil
+}
+
+// loadJournaledBlocks fetches the given blocks of a journaled channel and
+// verifies that they are still part of the canonical L2 chain.
+func (l *BatchSubmitter) loadJournaledBlocks(ctx context.Context, ids []eth.BlockID) ([]*types.Block, error) {
+	blocks := make([]*types.Block, 0, len(ids))
+	for _, id := range ids {
+		tctx, cancel := context.WithTimeout(ctx, l.Cfg.NetworkTimeout)
+		block, err := l.L2Client.BlockByNumber(tctx, new(big.Int).SetUint64(id.Number))
+		cancel()
+		if err != nil {
+			return nil, fmt.Errorf("getting L2 block %d: %w", id.Number, err)
+		}
+		if block.Hash() != id.Hash {
+			return nil, fmt.Errorf("L2 block %v got reorged out, canonical block is %v", id, eth.ToBlockID(block))
+		}
+		blocks = append(blocks, block)
+	}
+	return blocks, nil
+}
+
+// reconcileJournaledTxs looks up the L1 receipts of all in-flight transactions
+// of the journaled channel and marks the successfully included ones as confirmed.
+func (l *BatchSubmitter) reconcileJournaledTxs(ctx context.Context, jc *JournalChannel) error {
+	for i := range jc.Txs {
+		tx := &jc.Txs[i]
+		if tx.Inclusion != nil {
+			continue
+		}
+		for _, hash := range tx.Hashes {
+			tctx, cancel := context.WithTimeout(ctx, l.Cfg.NetworkTimeout)
+			receipt, err := l.L1Client.TransactionReceipt(tctx, hash)
+			cancel()
+			if errors.Is(err, ethereum.NotFound) {
+				continue
+			} else if err != nil {
+				return fmt.Errorf("getting receipt of journaled tx %v: %w", hash, err)
+			}
+			if receipt.Status != types.ReceiptStatusSuccessful {
+				continue
+			}
+			l.Log.Info("Journaled transaction was confirmed", "id", jc.ID, "frames", tx.Frames, "tx_hash", hash, "block_number", receipt.BlockNumber)
+			tx.Inclusion = &eth.BlockID{Number: receipt.BlockNumber.Uint64(), Hash: receipt.BlockHash}
+			break
+		}
+	}
+	return nil
+}
+
 // publishStateToL1 loops through the block data loaded into `state` and
 // submits the associated data to the L1 in the form of channel frames.
 func (l *BatchSubmitter) publishStateToL1(queue *txmgr.Queue[txData], receiptsCh chan txmgr.TxReceipt[txData], drain bool) {
@@ -313,15 +547,16 @@ func (l *BatchSubmitter) publishStateToL1(queue *txmgr.Queue[txData], receiptsCh
 // publishTxToL1 submits a single state tx to the L1
 func (l *BatchSubmitter) publishTxToL1(ctx context.Context, queue *txmgr.Queue[txData], receiptsCh chan txmgr.TxReceipt[txData]) error {
 	// send all available transactions
-	l1tip, err := l.l1Tip(ctx)
+	l1tip, baseFee, err := l.l1Tip(ctx)
 	if err != nil {
 		l.Log.Error("Failed to query L1 tip", "error", err)
 		return err
 	}
 	l.recordL1Tip(l1tip)
+	l.applyFeePolicy(baseFee)
 
 	// Collect next transaction data
-	txdata, err := l.state.TxData(l1tip.ID())
+	txdata, err := l.state.TxData(l1tip)
 	if err == io.EOF {
 		l.Log.Trace("no transaction data available")
 		return err
@@ -330,28 +565,83 @@ func (l *BatchSubmitter) publishTxToL1(ctx context.Context, queue *txmgr.Queue[t
 		return err
 	}
 
-	l.sendTransaction(txdata, queue, receiptsCh)
+	l.sendTransaction(txdata, l1tip, queue, receiptsCh)
 	return nil
 }
 
-// sendTransaction creates & submits a transaction to the batch inbox address with the given `data`.
+// sendTransaction creates & submits a transaction to the batch inbox address with the given `txData`.
+// The data is submitted as blobs if the tx data is meant to be sent as blobs, as a DA commitment if
+// DA commitments are active at the L1 tip, and as calldata otherwise. Blob tx data is only
+// submitted once blobs are enabled at the L1 tip, since derivation ignores blobs before.
 // It currently uses the underlying `txmgr` to handle transaction sending & price management.
 // This is a blocking method. It should not be called concurrently.
-func (l *BatchSubmitter) sendTransaction(txdata txData, queue *txmgr.Queue[txData], receiptsCh chan txmgr.TxReceipt[txData]) {
-	// Do the gas estimation offline. A value of 0 will cause the [txmgr] to estimate the gas limit.
-	data := txdata.Bytes()
-	intrinsicGas, err := core.IntrinsicGas(data, nil, false, true, true, false)
+func (l *BatchSubmitter) sendTransaction(txdata txData, l1tip eth.L1BlockRef, queue *txmgr.Queue[txData], receiptsCh chan txmgr.TxReceipt[txData]) {
+	var candidate *txmgr.TxCandidate
+	var err error
+	if txdata.asBlob && !l.RollupCfg.IsBlobsEnabled(l1tip.Time) {
+		err = fmt.Errorf("blobs are not enabled at L1 tip %s", l1tip)
+	} else if txdata.asBlob {
+		candidate, err = l.blobTxCandidate(txdata)
+	} else if l.DAClient != nil && l.RollupCfg.IsDACommitments(l1tip.Time) {
+		// The tx is included after the L1 tip, so derivation resolves the commitment.
+		candidate, err = l.daCommitmentTxCandidate(txdata)
+	} else {
+		candidate, err = l.calldataTxCandidate(txdata.Bytes())
+	}
 	if err != nil {
-		l.Log.Error("Failed to calculate intrinsic gas", "error", err)
+		l.Log.Error("Failed to create tx candidate", "error", err)
+		// the frames are put back into the channel, so they are retried with the next transaction
+		l.recordFailedTx(txdata.ID(), err)
 		return
 	}
+	candidate.OnPublished = func(tx *types.Transaction) {
+		l.state.TxPublished(txdata.ID(), tx.Hash())
+	}
+	queue.Send(txdata, *candidate, receiptsCh)
+}
 
-	candidate := txmgr.TxCandidate{
+func (l *BatchSubmitter) blobTxCandidate(data txData) (*txmgr.TxCandidate, error) {
+	blobs, err := data.Blobs()
+	if err != nil {
+		return nil, fmt.Errorf("generating blobs for tx data: %w", err)
+	}
+	// Blob gas is accounted for separately, so the regular gas only covers a plain tx without calldata.
+	intrinsicGas, err := core.IntrinsicGas(nil, nil, false, true, true, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate intrinsic gas: %w", err)
+	}
+	l.Log.Debug("sending blob tx", "id", data.ID(), "num_blobs", len(blobs))
+	return &txmgr.TxCandidate{
 		To:       &l.RollupCfg.BatchInboxAddress,
-		TxData:   data,
+		Blobs:    blobs,
 		GasLimit: intrinsicGas,
+	}, nil
+}
+
+// daCommitmentTxCandidate stores the tx data on the DA server, and returns a candidate
+// that submits the commitment to it as calldata.
+func (l *BatchSubmitter) daCommitmentTxCandidate(data txData) (*txmgr.TxCandidate, error) {
+	ctx, cancel := context.WithTimeout(l.killCtx, l.Cfg.NetworkTimeout)
+	defer cancel()
+	comm, err := l.DAClient.SetInput(ctx, data.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to store tx data on DA server: %w", err)
 	}
-	queue.Send(txdata, candidate, receiptsCh)
+	l.Log.Debug("stored tx data on DA server", "id", data.ID(), "commitment", comm)
+	return l.calldataTxCandidate(comm.TxData())
+}
+
+func (l *BatchSubmitter) calldataTxCandidate(data []byte) (*txmgr.TxCandidate, error) {
+	// Do the gas estimation offline. A value of 0 will cause the [txmgr] to estimate the gas limit.
+	intrinsicGas, err := core.IntrinsicGas(data, nil, false, true, true, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate intrinsic gas: %w", err)
+	}
+	return &txmgr.TxCandidate{
+		To:       &l.RollupCfg.BatchInboxAddress,
+		TxData:   data,
+		GasLimit: intrinsicGas,
+	}, nil
 }
 
 func (l *BatchSubmitter) handleReceipt(r txmgr.TxReceipt[txData]) {
@@ -384,14 +674,35 @@ func (l *BatchSubmitter) recordConfirmedTx(id txID, receipt *types.Receipt) {
 	l.state.TxConfirmed(id, l1block)
 }
 
-// l1Tip gets the current L1 tip as a L1BlockRef. The passed context is assumed
-// to be a lifetime context, so it is internally wrapped with a network timeout.
-func (l *BatchSubmitter) l1Tip(ctx context.Context) (eth.L1BlockRef, error) {
+// applyFeePolicy throttles submission while the L1 base fee is above the
+// threshold of the fee policy, so that only the frames of urgent channels are
+// submitted.
+func (l *BatchSubmitter) applyFeePolicy(baseFee *big.Int) {
+	l.feeMu.Lock()
+	defer l.feeMu.Unlock()
+	policy := l.feePolicy
+	maxBaseFee := new(big.Int).Mul(new(big.Int).SetUint64(policy.MaxBaseFeeGwei), big.NewInt(params.GWei))
+	throttled := policy.Enabled() && baseFee != nil && baseFee.Cmp(maxBaseFee) > 0
+	if throttled != l.feeThrottled {
+		l.Log.Info("L1 base fee crossed threshold", "throttled", throttled, "base_fee", baseFee, "max_base_fee", maxBaseFee)
+		l.feeThrottled = throttled
+	}
+	if throttled {
+		l.state.SetThrottle(&policy)
+	} else {
+		l.state.SetThrottle(nil)
+	}
+}
+
+// l1Tip gets the current L1 tip as a L1BlockRef, together with its base fee.
+// The passed context is assumed to be a lifetime context, so it is internally
+// wrapped with a network timeout.
+func (l *BatchSubmitter) l1Tip(ctx context.Context) (eth.L1BlockRef, *big.Int, error) {
 	tctx, cancel := context.WithTimeout(ctx, l.Cfg.NetworkTimeout)
 	defer cancel()
 	head, err := l.L1Client.HeaderByNumber(tctx, nil)
 	if err != nil {
-		return eth.L1BlockRef{}, fmt.Errorf("getting latest L1 block: %w", err)
+		return eth.L1BlockRef{}, nil, fmt.Errorf("getting latest L1 block: %w", err)
 	}
-	return eth.InfoToL1BlockRef(eth.HeaderBlockInfo(head)), nil
+	return eth.InfoToL1BlockRef(eth.HeaderBlockInfo(head)), head.BaseFee, nil
 }
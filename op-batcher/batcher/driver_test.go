@@ -0,0 +1,73 @@
+package batcher
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-batcher/metrics"
+	"github.com/ethereum-optimism/optimism/op-batcher/rpc"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+)
+
+func TestSetFeePolicy(t *testing.T) {
+	log := testlog.Logger(t, log.LvlError)
+	b := NewBatchSubmitter(DriverSetup{
+		Log:       log,
+		Metr:      metrics.NoopMetrics,
+		RollupCfg: &defaultTestRollupConfig,
+		FeePolicy: rpc.FeePolicy{MaxBaseFeeGwei: 50, ThrottledChannelDuration: 50, UrgencyMargin: 30},
+	})
+	api := rpc.NewAdminAPI(b, metrics.NoopMetrics, log)
+	initial := b.GetFeePolicy()
+
+	tests := []struct {
+		name   string
+		policy rpc.FeePolicy
+		err    error
+	}{
+		{"ZeroDuration", rpc.FeePolicy{MaxBaseFeeGwei: 100, UrgencyMargin: 10}, rpc.ErrZeroThrottledChannelDuration},
+		{"ZeroMargin", rpc.FeePolicy{MaxBaseFeeGwei: 100, ThrottledChannelDuration: 100}, rpc.ErrZeroUrgencyMargin},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			require.ErrorIs(t, api.SetFeePolicy(context.Background(), test.policy), test.err)
+			require.Equal(t, initial, b.GetFeePolicy(), "invalid policy must not be applied")
+		})
+	}
+
+	t.Run("Disabled", func(t *testing.T) {
+		require.NoError(t, api.SetFeePolicy(context.Background(), rpc.FeePolicy{}))
+		require.Equal(t, rpc.FeePolicy{}, b.GetFeePolicy())
+	})
+	t.Run("Valid", func(t *testing.T) {
+		policy := rpc.FeePolicy{MaxBaseFeeGwei: 100, ThrottledChannelDuration: 100, UrgencyMargin: 10}
+		require.NoError(t, api.SetFeePolicy(context.Background(), policy))
+		require.Equal(t, policy, b.GetFeePolicy())
+	})
+}
+
+func TestSetChannelConfig(t *testing.T) {
+	cfg := defaultTestChannelConfig
+	cfg.MaxL1TxSize = 120_000
+	cfg.MaxFrameSize = 119_999
+	b := NewBatchSubmitter(DriverSetup{
+		Log:       testlog.Logger(t, log.LvlError),
+		Metr:      metrics.NoopMetrics,
+		RollupCfg: &defaultTestRollupConfig,
+		Channel:   cfg,
+	})
+
+	frameSize := uint64(50_000)
+	require.NoError(t, b.SetChannelConfig(rpc.ChannelConfig{MaxFrameSize: &frameSize}))
+	require.Equal(t, frameSize, b.state.Config().MaxFrameSize)
+	require.Equal(t, frameSize, b.state.Config().CompressorConfig.TargetFrameSize)
+
+	frameSize = 120_000
+	require.ErrorContains(t, b.SetChannelConfig(rpc.ChannelConfig{MaxFrameSize: &frameSize}), "exceeds the maximum calldata frame size")
+	require.Equal(t, uint64(50_000), b.state.Config().MaxFrameSize, "invalid config must not be applied")
+	require.Equal(t, uint64(50_000), b.state.Config().CompressorConfig.TargetFrameSize)
+}
@@ -0,0 +1,178 @@
+package batcher
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+)
+
+// JournalState is the persisted state of all closed channels that are not fully
+// submitted yet. It contains everything that is needed to resume the submission
+// of these channels after a restart of the batcher.
+type JournalState struct {
+	Channels []JournalChannel `json:"channels"`
+}
+
+// JournalChannel is the persisted state of a single closed channel.
+type JournalChannel struct {
+	ID derive.ChannelID `json:"id"`
+	// Blocks are the L2 blocks that are part of the channel, in order.
+	// They are used to verify the channel against the canonical L2 chain, and
+	// to rebuild the channel if it times out.
+	Blocks []eth.BlockID `json:"blocks"`
+	// TotalFrames is the total number of frames of the channel.
+	TotalFrames int `json:"totalFrames"`
+	// Frames are all frames that are not confirmed on L1 yet, including frames of in-flight txs.
+	Frames []JournalFrame `json:"frames"`
+	// Txs are the in-flight and confirmed txs of the channel.
+	Txs []JournalTx `json:"txs"`
+}
+
+// JournalFrame is a persisted frame.
+type JournalFrame struct {
+	Number uint16        `json:"number"`
+	Data   hexutil.Bytes `json:"data"`
+}
+
+// JournalTx is a persisted batcher transaction, identified by the frame numbers it carries.
+type JournalTx struct {
+	Frames []uint16 `json:"frames"`
+	// Hashes are the hashes of all published versions of the tx, including fee-bumped replacements.
+	Hashes []common.Hash `json:"hashes,omitempty"`
+	// Inclusion is the L1 block the tx got included in. It is nil for in-flight txs.
+	Inclusion *eth.BlockID `json:"inclusion,omitempty"`
+}
+
+// ChannelJournal persists the channel state of the batcher.
+type ChannelJournal interface {
+	// Store replaces the persisted state with the given state.
+	Store(state *JournalState) error
+	// Load returns the persisted state. It returns an empty state if nothing was persisted yet.
+	Load() (*JournalState, error)
+}
+
+var _ ChannelJournal = (*FileChannelJournal)(nil)
+
+// FileChannelJournal persists the channel state as a JSON file.
+type FileChannelJournal struct {
+	lock sync.Mutex
+	file string
+}
+
+func NewFileChannelJournal(file string) *FileChannelJournal {
+	return &FileChannelJournal{file: file}
+}
+
+// Store writes the new state to the file as safely as possible.
+// The state is written to a temp file and synced to disk first, before it is renamed
+// into place, so a crash never leaves a partially written journal behind.
+func (j *FileChannelJournal) Store(state *JournalState) error {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("marshal channel journal: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(j.file), 0755); err != nil {
+		return fmt.Errorf("create channel journal dir (%v): %w", j.file, err)
+	}
+	tmpFile := j.file + ".tmp"
+	file, err := os.OpenFile(tmpFile, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return fmt.Errorf("open file (%v) for writing: %w", tmpFile, err)
+	}
+	defer file.Close() // Ensure file is closed even if write or sync fails
+	if _, err = file.Write(data); err != nil {
+		return fmt.Errorf("write channel journal to temp file (%v): %w", tmpFile, err)
+	}
+	if err := file.Sync(); err != nil {
+		return fmt.Errorf("sync channel journal temp file (%v): %w", tmpFile, err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("close channel journal temp file (%v): %w", tmpFile, err)
+	}
+	if err := os.Rename(tmpFile, j.file); err != nil {
+		return fmt.Errorf("rename temp channel journal to final destination: %w", err)
+	}
+	return nil
+}
+
+func (j *FileChannelJournal) Load() (*JournalState, error) {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	data, err := os.ReadFile(j.file)
+	if errors.Is(err, os.ErrNotExist) {
+		return &JournalState{}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("read channel journal (%v): %w", j.file, err)
+	}
+	var state JournalState
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&state); err != nil {
+		return nil, fmt.Errorf("invalid channel journal (%v): %w", j.file, err)
+	}
+	return &state, nil
+}
+
+// journalWriter writes the channel state of the channel manager to a ChannelJournal.
+// The state is collected under the channel manager lock with Store, and written
+// with Sync after the lock is released, so that writing the journal doesn't block
+// the channel manager. Since Sync writes synchronously, the state is on disk once
+// it returns, so callers never report progress that isn't persisted yet.
+type journalWriter struct {
+	log     log.Logger
+	journal ChannelJournal
+
+	// writeMu serializes writes, so that states are written in order
+	writeMu sync.Mutex
+
+	mu sync.Mutex
+	// pending is the latest state that is not written yet, nil if there is none
+	pending *JournalState
+}
+
+func newJournalWriter(log log.Logger, journal ChannelJournal) *journalWriter {
+	return &journalWriter{log: log, journal: journal}
+}
+
+// Store sets the state to be written by the next call to Sync. It replaces a
+// pending state that isn't written yet.
+func (w *journalWriter) Store(state *JournalState) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.pending = state
+}
+
+// Sync writes the pending state to the journal, if there is one. Once it
+// returns, all states that were stored before the call are written, possibly
+// by a concurrent call to Sync. It does nothing on a nil writer.
+// Errors are only logged, since the journal is an optimization.
+func (w *journalWriter) Sync() {
+	if w == nil {
+		return
+	}
+	w.writeMu.Lock()
+	defer w.writeMu.Unlock()
+	w.mu.Lock()
+	state := w.pending
+	w.pending = nil
+	w.mu.Unlock()
+	if state == nil {
+		return
+	}
+	if err := w.journal.Store(state); err != nil {
+		w.log.Error("Failed to store channel journal", "err", err)
+	}
+}
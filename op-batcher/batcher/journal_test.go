@@ -0,0 +1,175 @@
+package batcher
+
+import (
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-batcher/compressor"
+	"github.com/ethereum-optimism/optimism/op-batcher/metrics"
+	derivetest "github.com/ethereum-optimism/optimism/op-node/rollup/derive/test"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+)
+
+func TestFileChannelJournal(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "journal", "channels.json")
+	j := NewFileChannelJournal(file)
+
+	state, err := j.Load()
+	require.NoError(t, err)
+	require.Empty(t, state.Channels, "missing journal loads as empty state")
+
+	expected := &JournalState{Channels: []JournalChannel{{
+		ID:          [16]byte{0x01},
+		Blocks:      []eth.BlockID{{Hash: common.Hash{0xaa}, Number: 10}},
+		TotalFrames: 2,
+		Frames:      []JournalFrame{{Number: 1, Data: []byte{0x01, 0x02}}},
+		Txs: []JournalTx{
+			{Frames: []uint16{0}, Inclusion: &eth.BlockID{Hash: common.Hash{0xbb}, Number: 100}},
+			{Frames: []uint16{1}, Hashes: []common.Hash{{0xcc}, {0xdd}}},
+		},
+	}}}
+	require.NoError(t, j.Store(expected))
+	state, err = j.Load()
+	require.NoError(t, err)
+	require.Equal(t, expected, state)
+
+	require.NoError(t, os.WriteFile(file, []byte(`{"channels":[],"unknown":1}`), 0644))
+	_, err = j.Load()
+	require.ErrorContains(t, err, "invalid channel journal")
+}
+
+func TestChannelManagerJournalRestore(t *testing.T) {
+	require := require.New(t)
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	log := testlog.Logger(t, log.LvlError)
+	cfg := ChannelConfig{
+		MaxFrameSize:   120_000,
+		ChannelTimeout: 100,
+		CompressorConfig: compressor.Config{
+			TargetFrameSize:  1,
+			TargetNumFrames:  1,
+			ApproxComprRatio: 1.0,
+		},
+	}
+	journal := NewFileChannelJournal(filepath.Join(t.TempDir(), "channels.json"))
+	m := NewChannelManager(log, metrics.NoopMetrics, cfg, &defaultTestRollupConfig)
+	m.SetJournal(journal)
+
+	a, _ := derivetest.RandomL2Block(rng, 4)
+	require.NoError(m.AddL2Block(a))
+
+	txdata, err := m.TxData(eth.L1BlockRef{})
+	require.NoError(err)
+	txHash := common.Hash{0x01}
+	m.TxPublished(txdata.ID(), txHash)
+
+	state, err := journal.Load()
+	require.NoError(err)
+	require.Len(state.Channels, 1)
+	jc := state.Channels[0]
+	require.Equal(txdata.ID().chID, jc.ID)
+	require.Equal([]eth.BlockID{eth.ToBlockID(a)}, jc.Blocks)
+	require.Len(jc.Frames, 1)
+	require.Equal([]JournalTx{{Frames: []uint16{0}, Hashes: []common.Hash{txHash}}}, jc.Txs)
+
+	// the in-flight tx is not known on L1, so its frame must be submitted again
+	blocks := []*types.Block{a}
+	ch := restoreChannel(log, metrics.NoopMetrics, cfg, jc, blocks)
+	require.True(ch.IsFull())
+	require.ErrorIs(ch.FullErr(), ErrRestored)
+	require.False(ch.isFullySubmitted())
+	require.True(ch.HasFrame())
+	restored := ch.NextTxData()
+	require.Equal(txdata.Bytes(), restored.Bytes())
+
+	// the in-flight tx got included, so the channel is fully submitted
+	jc.Txs[0].Inclusion = &eth.BlockID{Hash: common.Hash{0x02}, Number: 1}
+	ch = restoreChannel(log, metrics.NoopMetrics, cfg, jc, blocks)
+	require.False(ch.HasFrame())
+	require.True(ch.isFullySubmitted())
+
+	// a confirmed channel gets removed from the journal
+	m.TxConfirmed(txdata.ID(), eth.BlockID{Hash: common.Hash{0x02}, Number: 1})
+	state, err = journal.Load()
+	require.NoError(err)
+	require.Empty(state.Channels)
+}
+
+// blockingJournal blocks every Store until it is released.
+type blockingJournal struct {
+	stored  chan *JournalState
+	release chan struct{}
+}
+
+func (j *blockingJournal) Store(state *JournalState) error {
+	j.stored <- state
+	<-j.release
+	return nil
+}
+
+func (j *blockingJournal) Load() (*JournalState, error) {
+	return &JournalState{}, nil
+}
+
+// TestChannelManagerTxPublishedWritesJournal checks that TxPublished, which is called
+// from the tx manager's publish callback, only returns once the tx hash is written
+// to the journal, but doesn't hold the channel manager lock while writing it.
+func TestChannelManagerTxPublishedWritesJournal(t *testing.T) {
+	require := require.New(t)
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	log := testlog.Logger(t, log.LvlError)
+	cfg := ChannelConfig{
+		MaxFrameSize:   120_000,
+		ChannelTimeout: 100,
+		CompressorConfig: compressor.Config{
+			TargetFrameSize:  1,
+			TargetNumFrames:  1,
+			ApproxComprRatio: 1.0,
+		},
+	}
+	journal := &blockingJournal{stored: make(chan *JournalState, 10), release: make(chan struct{})}
+	m := NewChannelManager(log, metrics.NoopMetrics, cfg, &defaultTestRollupConfig)
+	m.SetJournal(journal)
+
+	a, _ := derivetest.RandomL2Block(rng, 4)
+	require.NoError(m.AddL2Block(a))
+	txdata := make(chan txData)
+	go func() {
+		data, err := m.TxData(eth.L1BlockRef{})
+		require.NoError(err)
+		txdata <- data
+	}()
+	// the closed channel is written by the compression
+	<-journal.stored
+	journal.release <- struct{}{}
+	data := <-txdata
+
+	published := make(chan struct{})
+	go func() {
+		m.TxPublished(data.ID(), common.Hash{0x01})
+		close(published)
+	}()
+	state := <-journal.stored
+	require.Equal([]common.Hash{{0x01}}, state.Channels[0].Txs[0].Hashes)
+
+	// the channel manager isn't locked while the journal is written
+	require.Len(m.State().Channels, 1)
+	select {
+	case <-published:
+		t.Fatal("TxPublished returned before the journal was written")
+	default:
+	}
+
+	journal.release <- struct{}{}
+	<-published
+	require.Empty(journal.stored)
+}
@@ -11,14 +11,16 @@ import (
 	"sync/atomic"
 	"time"
 
-	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/log"
 
+	"github.com/ethereum-optimism/optimism/op-batcher/flags"
 	"github.com/ethereum-optimism/optimism/op-batcher/metrics"
 	"github.com/ethereum-optimism/optimism/op-batcher/rpc"
 	"github.com/ethereum-optimism/optimism/op-node/rollup"
 	"github.com/ethereum-optimism/optimism/op-service/cliapp"
+	"github.com/ethereum-optimism/optimism/op-service/dacommit"
 	"github.com/ethereum-optimism/optimism/op-service/dial"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
 	"github.com/ethereum-optimism/optimism/op-service/httputil"
 	opmetrics "github.com/ethereum-optimism/optimism/op-service/metrics"
 	oppprof "github.com/ethereum-optimism/optimism/op-service/pprof"
@@ -38,8 +40,8 @@ type BatcherConfig struct {
 type BatcherService struct {
 	Log        log.Logger
 	Metrics    metrics.Metricer
-	L1Client   *ethclient.Client
-	L2Client   *ethclient.Client
+	L1Client   *dial.EthClient
+	L2Client   *dial.EthClient
 	RollupNode *sources.RollupClient
 	TxManager  txmgr.TxManager
 
@@ -50,6 +52,15 @@ type BatcherService struct {
 	// Channel builder parameters
 	Channel ChannelConfig
 
+	// Journal to persist closed channels to, nil if disabled
+	Journal ChannelJournal
+
+	// Initial L1 fee policy of the driver
+	FeePolicy rpc.FeePolicy
+
+	// DA server to store batch data on, nil if batch data is submitted to L1
+	DAClient DAClient
+
 	driver *BatchSubmitter
 
 	Version string
@@ -80,6 +91,13 @@ func (bs *BatcherService) initFromCLIConfig(ctx context.Context, version string,
 	bs.Version = version
 	bs.Log = log
 	bs.NotSubmittingOnStart = cfg.Stopped
+	if cfg.ChannelJournal != "" {
+		bs.Journal = NewFileChannelJournal(cfg.ChannelJournal)
+	}
+	bs.FeePolicy = cfg.FeePolicy
+	if cfg.DataAvailabilityType == flags.DACommitmentType {
+		bs.DAClient = dacommit.NewDAClient(cfg.DAServer)
+	}
 
 	bs.initMetrics(cfg)
 
@@ -117,7 +135,7 @@ func (bs *BatcherService) initFromCLIConfig(ctx context.Context, version string,
 }
 
 func (bs *BatcherService) initRPCClients(ctx context.Context, cfg *CLIConfig) error {
-	l1Client, err := dial.DialEthClientWithTimeout(ctx, dial.DefaultDialTimeout, bs.Log, cfg.L1EthRpc)
+	l1Client, err := dial.DialMultiEthClientWithTimeout(ctx, dial.DefaultDialTimeout, bs.Log, cfg.L1EthRpc, cfg.L1RPCConfig.MultiRPCConfig(), bs.Metrics)
 	if err != nil {
 		return fmt.Errorf("failed to dial L1 RPC: %w", err)
 	}
@@ -149,7 +167,7 @@ func (bs *BatcherService) initMetrics(cfg *CLIConfig) {
 // initBalanceMonitor depends on Metrics, L1Client and TxManager to start background-monitoring of the batcher balance.
 func (bs *BatcherService) initBalanceMonitor(cfg *CLIConfig) {
 	if cfg.MetricsConfig.Enabled {
-		bs.balanceMetricer = bs.Metrics.StartBalanceMetrics(bs.Log, bs.L1Client, bs.TxManager.From())
+		bs.balanceMetricer = bs.Metrics.StartBalanceMetrics(bs.Log, bs.L1Client.Client, bs.TxManager.From())
 	}
 }
 
@@ -170,13 +188,40 @@ func (bs *BatcherService) initChannelConfig(cfg *CLIConfig) error {
 		SeqWindowSize:      bs.RollupConfig.SeqWindowSize,
 		ChannelTimeout:     bs.RollupConfig.ChannelTimeout,
 		MaxChannelDuration: cfg.MaxChannelDuration,
+		MaxOpenChannels:    cfg.MaxOpenChannels,
 		SubSafetyMargin:    cfg.SubSafetyMargin,
 		MaxFrameSize:       cfg.MaxL1TxSize - 1, // subtract 1 byte for version
+		MaxL1TxSize:        cfg.MaxL1TxSize,
 		CompressorConfig:   cfg.CompressorConfig.Config(),
 	}
+	switch cfg.DataAvailabilityType {
+	case flags.BlobsType:
+		// Until blobs are enabled, batch data is submitted as calldata.
+		if bs.RollupConfig.BlobsEnabledL1Timestamp == nil {
+			return errors.New("cannot submit blobs, blobs are not enabled in the rollup config")
+		}
+		// Every frame is submitted in its own blob, prefixed with a version byte.
+		bs.Channel.MaxFrameSize = eth.MaxBlobDataSize - 1
+		bs.Channel.CompressorConfig.TargetFrameSize = eth.MaxBlobDataSize - 1
+		bs.Channel.UseBlobs = true
+	case flags.CalldataType:
+	case flags.DACommitmentType:
+		// Until DA commitments activate, batch data is submitted as calldata.
+		if bs.RollupConfig.DACommitmentsL1Timestamp == nil {
+			return errors.New("cannot submit DA commitments, the DA commitments upgrade is not scheduled in the rollup config")
+		}
+	default:
+		return fmt.Errorf("unknown data availability type: %v", cfg.DataAvailabilityType)
+	}
+	// Until the channel compression upgrade activates, channels are compressed with zlib.
+	if algo := bs.Channel.CompressorConfig.CompressionAlgo; algo.IsVersioned() && bs.RollupConfig.ChannelCompressionTime == nil {
+		return fmt.Errorf("cannot use %s compression, the channel compression upgrade is not scheduled in the rollup config", algo)
+	}
 	if err := bs.Channel.Check(); err != nil {
 		return fmt.Errorf("invalid channel configuration: %w", err)
 	}
+	bs.Log.Info("Initialized channel-config", "use_blobs", bs.Channel.UseBlobs, "max_frame_size", bs.Channel.MaxFrameSize,
+		"compression_algo", bs.Channel.CompressorConfig.CompressionAlgo)
 	return nil
 }
 
@@ -233,6 +278,9 @@ func (bs *BatcherService) initDriver() {
 		L2Client:     bs.L2Client,
 		RollupClient: bs.RollupNode,
 		Channel:      bs.Channel,
+		Journal:      bs.Journal,
+		FeePolicy:    bs.FeePolicy,
+		DAClient:     bs.DAClient,
 	})
 }
 
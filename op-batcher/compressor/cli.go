@@ -1,10 +1,14 @@
 package compressor
 
 import (
+	"fmt"
 	"strings"
 
-	opservice "github.com/ethereum-optimism/optimism/op-service"
 	"github.com/urfave/cli/v2"
+
+	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
+	opservice "github.com/ethereum-optimism/optimism/op-service"
+	openum "github.com/ethereum-optimism/optimism/op-service/enum"
 )
 
 const (
@@ -12,6 +16,8 @@ const (
 	TargetNumFramesFlagName     = "target-num-frames"
 	ApproxComprRatioFlagName    = "approx-compr-ratio"
 	KindFlagName                = "compressor"
+	CompressionAlgoFlagName     = "compression-algo"
+	CompressionLevelFlagName    = "compression-level"
 )
 
 func CLIFlags(envPrefix string) []cli.Flag {
@@ -40,6 +46,22 @@ func CLIFlags(envPrefix string) []cli.Flag {
 			EnvVars: opservice.PrefixEnvVar(envPrefix, "COMPRESSOR"),
 			Value:   RatioKind,
 		},
+		&cli.GenericFlag{
+			Name: CompressionAlgoFlagName,
+			Usage: "The compression algorithm to compress channels with. Algorithms other than zlib require " +
+				"the channel compression upgrade to be scheduled, and channels are compressed with zlib until it is active. Valid options: " + openum.EnumString(derive.CompressionAlgos),
+			EnvVars: opservice.PrefixEnvVar(envPrefix, "COMPRESSION_ALGO"),
+			Value: func() *derive.CompressionAlgo {
+				out := derive.Zlib
+				return &out
+			}(),
+		},
+		&cli.IntFlag{
+			Name:    CompressionLevelFlagName,
+			Usage:   "The compression level of the compression algorithm. 0 selects the default level of the algorithm.",
+			EnvVars: opservice.PrefixEnvVar(envPrefix, "COMPRESSION_LEVEL"),
+			Value:   0,
+		},
 	}
 }
 
@@ -57,6 +79,17 @@ type CLIConfig struct {
 	ApproxComprRatio float64
 	// Type of compressor to use. Must be one of KindKeys.
 	Kind string
+	// CompressionAlgo to compress channels with.
+	CompressionAlgo derive.CompressionAlgo
+	// CompressionLevel of the compression algorithm, 0 for its default level.
+	CompressionLevel int
+}
+
+func (c *CLIConfig) Check() error {
+	if !derive.ValidCompressionAlgo(c.CompressionAlgo) {
+		return fmt.Errorf("unknown compression algo: %q", c.CompressionAlgo)
+	}
+	return c.CompressionAlgo.CheckLevel(c.CompressionLevel)
 }
 
 func (c *CLIConfig) Config() Config {
@@ -65,6 +98,8 @@ func (c *CLIConfig) Config() Config {
 		TargetNumFrames:  c.TargetNumFrames,
 		ApproxComprRatio: c.ApproxComprRatio,
 		Kind:             c.Kind,
+		CompressionAlgo:  c.CompressionAlgo,
+		CompressionLevel: c.CompressionLevel,
 	}
 }
 
@@ -74,5 +109,7 @@ func ReadCLIConfig(ctx *cli.Context) CLIConfig {
 		TargetL1TxSizeBytes: ctx.Uint64(TargetL1TxSizeBytesFlagName),
 		TargetNumFrames:     ctx.Int(TargetNumFramesFlagName),
 		ApproxComprRatio:    ctx.Float64(ApproxComprRatioFlagName),
+		CompressionAlgo:     derive.CompressionAlgo(ctx.String(CompressionAlgoFlagName)),
+		CompressionLevel:    ctx.Int(CompressionLevelFlagName),
 	}
 }
@@ -19,6 +19,26 @@ type Config struct {
 	// Kind of compressor to use. Must be one of KindKeys. If unset, NewCompressor
 	// will default to RatioKind.
 	Kind string
+	// CompressionAlgo to compress channels with. If unset, it defaults to zlib.
+	CompressionAlgo derive.CompressionAlgo
+	// CompressionLevel of the compression algorithm. 0 selects the default
+	// level of the algorithm.
+	CompressionLevel int
+}
+
+// NewChannelCompressor creates a channel compressor for the configured
+// compression algorithm and level.
+func (c Config) NewChannelCompressor() (derive.ChannelCompressor, error) {
+	algo := c.CompressionAlgo
+	if algo == "" {
+		algo = derive.Zlib
+	}
+	return derive.NewChannelCompressor(algo, c.CompressionLevel)
+}
+
+// TargetOutputSize returns the target size of the compressed output of a channel.
+func (c Config) TargetOutputSize() uint64 {
+	return c.TargetFrameSize * uint64(c.TargetNumFrames)
 }
 
 func (c Config) NewCompressor() (derive.Compressor, error) {
@@ -1,9 +1,6 @@
 package compressor
 
 import (
-	"bytes"
-	"compress/zlib"
-
 	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
 )
 
@@ -11,8 +8,7 @@ type RatioCompressor struct {
 	config Config
 
 	inputBytes int
-	buf        bytes.Buffer
-	compress   *zlib.Writer
+	compress   derive.ChannelCompressor
 }
 
 // NewRatioCompressor creates a new derive.Compressor implementation that uses the target
@@ -25,7 +21,7 @@ func NewRatioCompressor(config Config) (derive.Compressor, error) {
 		config: config,
 	}
 
-	compress, err := zlib.NewWriterLevel(&c.buf, zlib.BestCompression)
+	compress, err := config.NewChannelCompressor()
 	if err != nil {
 		return nil, err
 	}
@@ -47,17 +43,16 @@ func (t *RatioCompressor) Close() error {
 }
 
 func (t *RatioCompressor) Read(p []byte) (int, error) {
-	return t.buf.Read(p)
+	return t.compress.Read(p)
 }
 
 func (t *RatioCompressor) Reset() {
-	t.buf.Reset()
-	t.compress.Reset(&t.buf)
+	t.compress.Reset()
 	t.inputBytes = 0
 }
 
 func (t *RatioCompressor) Len() int {
-	return t.buf.Len()
+	return t.compress.Len()
 }
 
 func (t *RatioCompressor) Flush() error {
@@ -8,6 +8,8 @@ import (
 
 	"github.com/ethereum-optimism/optimism/op-batcher/compressor"
 	opservice "github.com/ethereum-optimism/optimism/op-service"
+	"github.com/ethereum-optimism/optimism/op-service/dial"
+	openum "github.com/ethereum-optimism/optimism/op-service/enum"
 	oplog "github.com/ethereum-optimism/optimism/op-service/log"
 	opmetrics "github.com/ethereum-optimism/optimism/op-service/metrics"
 	oppprof "github.com/ethereum-optimism/optimism/op-service/pprof"
@@ -25,7 +27,7 @@ var (
 	// Required flags
 	L1EthRpcFlag = &cli.StringFlag{
 		Name:    "l1-eth-rpc",
-		Usage:   "HTTP provider URL for L1",
+		Usage:   "HTTP provider URL for L1. Several comma-separated URLs can be listed, to fail over between",
 		EnvVars: prefixEnvVars("L1_ETH_RPC"),
 	}
 	L2EthRpcFlag = &cli.StringFlag{
@@ -65,12 +67,60 @@ var (
 		Value:   0,
 		EnvVars: prefixEnvVars("MAX_CHANNEL_DURATION"),
 	}
+	MaxOpenChannelsFlag = &cli.IntFlag{
+		Name: "max-open-channels",
+		Usage: "The maximum number of channels that are open for submission at the same time. " +
+			"The next channel is compressed in the background while previous channels are submitted. 0 for no limit.",
+		Value:   0,
+		EnvVars: prefixEnvVars("MAX_OPEN_CHANNELS"),
+	}
 	MaxL1TxSizeBytesFlag = &cli.Uint64Flag{
 		Name:    "max-l1-tx-size-bytes",
 		Usage:   "The maximum size of a batch tx submitted to L1.",
 		Value:   120_000,
 		EnvVars: prefixEnvVars("MAX_L1_TX_SIZE_BYTES"),
 	}
+	DataAvailabilityTypeFlag = &cli.GenericFlag{
+		Name: "data-availability-type",
+		Usage: "The data availability type to use for submitting batches to the L1. Valid options: " +
+			openum.EnumString(DataAvailabilityTypes),
+		Value: func() *DataAvailabilityType {
+			out := CalldataType
+			return &out
+		}(),
+		EnvVars: prefixEnvVars("DATA_AVAILABILITY_TYPE"),
+	}
+	DAServerFlag = &cli.StringFlag{
+		Name:    "da-server",
+		Usage:   "HTTP endpoint of the DA server to store batch data on. Required for the da-commitment data availability type.",
+		EnvVars: prefixEnvVars("DA_SERVER"),
+	}
+	ChannelJournalFlag = &cli.StringFlag{
+		Name: "channel-journal",
+		Usage: "Path to a file to persist closed channels and in-flight batcher txs to. " +
+			"Submission of these channels is resumed after a restart. Disabled if empty.",
+		EnvVars: prefixEnvVars("CHANNEL_JOURNAL"),
+	}
+	FeeThrottleMaxBaseFeeFlag = &cli.Uint64Flag{
+		Name: "fee-throttle-max-base-fee",
+		Usage: "The L1 base fee, in gwei, above which frames of channels that are not close to their " +
+			"submission deadline are held back. 0 to disable fee throttling.",
+		Value:   0,
+		EnvVars: prefixEnvVars("FEE_THROTTLE_MAX_BASE_FEE"),
+	}
+	FeeThrottleChannelDurationFlag = &cli.Uint64Flag{
+		Name: "fee-throttle-channel-duration",
+		Usage: "The maximum duration of L1-blocks to keep a channel open while fee throttling is active. " +
+			"Only widens the max channel duration.",
+		Value:   50,
+		EnvVars: prefixEnvVars("FEE_THROTTLE_CHANNEL_DURATION"),
+	}
+	FeeThrottleUrgencyMarginFlag = &cli.Uint64Flag{
+		Name:    "fee-throttle-urgency-margin",
+		Usage:   "The number of L1-blocks before a channel's submission deadline from which it is submitted regardless of the L1 base fee.",
+		Value:   30,
+		EnvVars: prefixEnvVars("FEE_THROTTLE_URGENCY_MARGIN"),
+	}
 	StoppedFlag = &cli.BoolFlag{
 		Name:    "stopped",
 		Usage:   "Initialize the batcher in a stopped state. The batcher can be started using the admin_startBatcher RPC",
@@ -91,8 +141,15 @@ var optionalFlags = []cli.Flag{
 	PollIntervalFlag,
 	MaxPendingTransactionsFlag,
 	MaxChannelDurationFlag,
+	MaxOpenChannelsFlag,
 	MaxL1TxSizeBytesFlag,
 	StoppedFlag,
+	DataAvailabilityTypeFlag,
+	DAServerFlag,
+	ChannelJournalFlag,
+	FeeThrottleMaxBaseFeeFlag,
+	FeeThrottleChannelDurationFlag,
+	FeeThrottleUrgencyMarginFlag,
 	SequencerHDPathFlag,
 }
 
@@ -103,6 +160,7 @@ func init() {
 	optionalFlags = append(optionalFlags, oppprof.CLIFlags(EnvVarPrefix)...)
 	optionalFlags = append(optionalFlags, txmgr.CLIFlags(EnvVarPrefix)...)
 	optionalFlags = append(optionalFlags, compressor.CLIFlags(EnvVarPrefix)...)
+	optionalFlags = append(optionalFlags, dial.CLIFlags(EnvVarPrefix)...)
 
 	Flags = append(requiredFlags, optionalFlags...)
 }
@@ -0,0 +1,55 @@
+package flags
+
+import (
+	"fmt"
+
+	"github.com/ethereum-optimism/optimism/op-service/cliapp"
+)
+
+// DataAvailabilityType is the type of L1 data availability the batcher submits batch data to.
+type DataAvailabilityType string
+
+const (
+	// CalldataType submits batch data as calldata of regular transactions.
+	CalldataType DataAvailabilityType = "calldata"
+	// BlobsType submits batch data as blobs of EIP-4844 blob transactions.
+	BlobsType DataAvailabilityType = "blobs"
+	// DACommitmentType stores batch data on a DA server, and submits only the
+	// commitments to the data as calldata of regular transactions.
+	// Before the DA commitments upgrade activates, batch data is submitted as calldata.
+	DACommitmentType DataAvailabilityType = "da-commitment"
+)
+
+var DataAvailabilityTypes = []DataAvailabilityType{
+	CalldataType,
+	BlobsType,
+	DACommitmentType,
+}
+
+func (kind DataAvailabilityType) String() string {
+	return string(kind)
+}
+
+func (kind *DataAvailabilityType) Set(value string) error {
+	if !ValidDataAvailabilityType(DataAvailabilityType(value)) {
+		return fmt.Errorf("unknown data-availability type: %q", value)
+	}
+	*kind = DataAvailabilityType(value)
+	return nil
+}
+
+func (kind *DataAvailabilityType) Clone() any {
+	cpy := *kind
+	return &cpy
+}
+
+var _ cliapp.CloneableGeneric = (*DataAvailabilityType)(nil)
+
+func ValidDataAvailabilityType(value DataAvailabilityType) bool {
+	for _, k := range DataAvailabilityTypes {
+		if k == value {
+			return true
+		}
+	}
+	return false
+}
@@ -2,6 +2,7 @@ package metrics
 
 import (
 	"io"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 
@@ -29,6 +30,7 @@ type Metricer interface {
 	txmetrics.TxMetricer
 
 	opmetrics.RPCMetricer
+	opmetrics.RPCEndpointMetricer
 
 	StartBalanceMetrics(l log.Logger, client *ethclient.Client, account common.Address) io.Closer
 
@@ -41,6 +43,8 @@ type Metricer interface {
 	RecordChannelClosed(id derive.ChannelID, numPendingBlocks int, numFrames int, inputBytes int, outputComprBytes int, reason error)
 	RecordChannelFullySubmitted(id derive.ChannelID)
 	RecordChannelTimedOut(id derive.ChannelID)
+	RecordChannelCompressionTime(id derive.ChannelID, d time.Duration)
+	RecordOpenChannels(numOpenChannels int)
 
 	RecordBatchTxSubmitted()
 	RecordBatchTxSuccess()
@@ -75,6 +79,8 @@ type Metrics struct {
 	channelClosedReason     prometheus.Gauge
 	channelNumFrames        prometheus.Gauge
 	channelComprRatio       prometheus.Histogram
+	channelComprTime        prometheus.Histogram
+	openChannels            prometheus.Gauge
 	channelInputBytesTotal  prometheus.Counter
 	channelOutputBytesTotal prometheus.Counter
 
@@ -171,6 +177,17 @@ func NewMetrics(procName string) *Metrics {
 			Help:      "Compression ratios of closed channel.",
 			Buckets:   append([]float64{0.1, 0.2}, prometheus.LinearBuckets(0.3, 0.05, 14)...),
 		}),
+		channelComprTime: factory.NewHistogram(prometheus.HistogramOpts{
+			Namespace: ns,
+			Name:      "channel_compression_seconds",
+			Help:      "Total time spent compressing the blocks of a closed channel.",
+			Buckets:   prometheus.ExponentialBuckets(0.005, 2, 14),
+		}),
+		openChannels: factory.NewGauge(prometheus.GaugeOpts{
+			Namespace: ns,
+			Name:      "open_channels",
+			Help:      "Number of channels that are not fully submitted yet.",
+		}),
 		channelInputBytesTotal: factory.NewCounter(prometheus.CounterOpts{
 			Namespace: ns,
 			Name:      "input_bytes_total",
@@ -267,6 +284,16 @@ func (m *Metrics) RecordChannelClosed(id derive.ChannelID, numPendingBlocks int,
 	m.channelClosedReason.Set(float64(ClosedReasonToNum(reason)))
 }
 
+// RecordChannelCompressionTime should be called when a channel got closed,
+// with the total time spent compressing its blocks.
+func (m *Metrics) RecordChannelCompressionTime(id derive.ChannelID, d time.Duration) {
+	m.channelComprTime.Observe(d.Seconds())
+}
+
+func (m *Metrics) RecordOpenChannels(numOpenChannels int) {
+	m.openChannels.Set(float64(numOpenChannels))
+}
+
 func (m *Metrics) RecordL2BlockInPendingQueue(block *types.Block) {
 	size := float64(estimateBatchSize(block))
 	m.pendingBlocksBytesTotal.Add(size)
@@ -13,6 +13,12 @@ import (
 type BatcherDriver interface {
 	StartBatchSubmitting() error
 	StopBatchSubmitting(ctx context.Context) error
+	FlushChannel(ctx context.Context) error
+	ChannelManagerState() ChannelManagerState
+	GetChannelConfig() ChannelConfig
+	SetChannelConfig(update ChannelConfig) error
+	GetFeePolicy() FeePolicy
+	SetFeePolicy(policy FeePolicy) error
 }
 
 type adminAPI struct {
@@ -41,3 +47,27 @@ func (a *adminAPI) StartBatcher(_ context.Context) error {
 func (a *adminAPI) StopBatcher(ctx context.Context) error {
 	return a.b.StopBatchSubmitting(ctx)
 }
+
+func (a *adminAPI) FlushChannel(ctx context.Context) error {
+	return a.b.FlushChannel(ctx)
+}
+
+func (a *adminAPI) ChannelManagerState(_ context.Context) (ChannelManagerState, error) {
+	return a.b.ChannelManagerState(), nil
+}
+
+func (a *adminAPI) GetChannelConfig(_ context.Context) (ChannelConfig, error) {
+	return a.b.GetChannelConfig(), nil
+}
+
+func (a *adminAPI) SetChannelConfig(_ context.Context, update ChannelConfig) error {
+	return a.b.SetChannelConfig(update)
+}
+
+func (a *adminAPI) GetFeePolicy(_ context.Context) (FeePolicy, error) {
+	return a.b.GetFeePolicy(), nil
+}
+
+func (a *adminAPI) SetFeePolicy(_ context.Context, policy FeePolicy) error {
+	return a.b.SetFeePolicy(policy)
+}
@@ -0,0 +1,99 @@
+package rpc
+
+import (
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+)
+
+// FeePolicy configures how the batcher throttles batch submission based on the
+// L1 base fee.
+type FeePolicy struct {
+	// MaxBaseFeeGwei is the L1 base fee, in gwei, above which the frames of
+	// channels that are not urgent yet are held back. 0 disables throttling.
+	MaxBaseFeeGwei uint64 `json:"maxBaseFeeGwei"`
+	// ThrottledChannelDuration is the max channel duration, in L1 blocks, of
+	// channels that are opened while submission is throttled, to amortize the
+	// fixed costs of batcher txs over more L2 blocks. It only ever widens the
+	// configured max channel duration.
+	ThrottledChannelDuration uint64 `json:"throttledChannelDuration"`
+	// UrgencyMargin is the number of L1 blocks before a channel's submission
+	// deadline from which its frames are submitted regardless of the L1 base fee.
+	UrgencyMargin uint64 `json:"urgencyMargin"`
+}
+
+var (
+	ErrZeroThrottledChannelDuration = errors.New("throttled channel duration must be greater than 0")
+	ErrZeroUrgencyMargin            = errors.New("urgency margin must be greater than 0")
+)
+
+// Enabled returns whether batch submission is throttled at all by this policy.
+func (p FeePolicy) Enabled() bool {
+	return p.MaxBaseFeeGwei != 0
+}
+
+// Check validates the policy. A disabled policy is always valid.
+func (p FeePolicy) Check() error {
+	if !p.Enabled() {
+		return nil
+	}
+	if p.ThrottledChannelDuration == 0 {
+		return ErrZeroThrottledChannelDuration
+	}
+	if p.UrgencyMargin == 0 {
+		return ErrZeroUrgencyMargin
+	}
+	return nil
+}
+
+// ChannelConfig contains the channel parameters that can be changed at runtime.
+// Nil fields are left unchanged when the config is set. Changes only apply to
+// channels that are opened afterwards.
+type ChannelConfig struct {
+	MaxFrameSize       *uint64 `json:"maxFrameSize,omitempty"`
+	MaxChannelDuration *uint64 `json:"maxChannelDuration,omitempty"`
+	SubSafetyMargin    *uint64 `json:"subSafetyMargin,omitempty"`
+	MaxOpenChannels    *int    `json:"maxOpenChannels,omitempty"`
+}
+
+// ChannelManagerState is a snapshot of the batcher's channel manager.
+type ChannelManagerState struct {
+	Closed    bool        `json:"closed"`
+	Throttled bool        `json:"throttled"`
+	Tip       common.Hash `json:"tip"`
+	// PendingBlocks are the L2 blocks that are not added to a channel yet.
+	PendingBlocks []eth.BlockID `json:"pendingBlocks"`
+	// Channels are all channels that are not fully submitted yet, in order.
+	Channels []ChannelState `json:"channels"`
+}
+
+// ChannelState is a snapshot of a channel that is not fully submitted yet.
+type ChannelState struct {
+	ID      derive.ChannelID `json:"id"`
+	Current bool             `json:"current"`
+	Blocks  []eth.BlockID    `json:"blocks"`
+	// FullReason is the reason why the channel is full. It is empty while more
+	// blocks can be added to the channel.
+	FullReason  string `json:"fullReason,omitempty"`
+	InputBytes  int    `json:"inputBytes"`
+	OutputBytes int    `json:"outputBytes"`
+	TotalFrames int    `json:"totalFrames"`
+	// QueuedFrames is the number of frames that are not sent in a tx yet.
+	QueuedFrames int `json:"queuedFrames"`
+	// Deadline is the L1 block number by which the channel must be fully
+	// submitted, 0 if not known yet.
+	Deadline uint64    `json:"deadline"`
+	Txs      []TxState `json:"txs"`
+}
+
+// TxState is a snapshot of an in-flight or confirmed batcher transaction.
+type TxState struct {
+	Frames []uint16 `json:"frames"`
+	// Hashes are the hashes of all published versions of an in-flight tx.
+	Hashes []common.Hash `json:"hashes,omitempty"`
+	// Inclusion is the L1 block a confirmed tx got included in, nil for in-flight txs.
+	Inclusion *eth.BlockID `json:"inclusion,omitempty"`
+}
@@ -120,6 +120,9 @@ type DeployConfig struct {
 	// L2GenesisSpanBatchTimeOffset is the number of seconds after genesis block that Span Batch hard fork activates.
 	// Set it to 0 to activate at genesis. Nil to disable SpanBatch.
 	L2GenesisSpanBatchTimeOffset *hexutil.Uint64 `json:"l2GenesisSpanBatchTimeOffset,omitempty"`
+	// L2GenesisChannelCompressionTimeOffset is the number of seconds after genesis block that brotli and zstd
+	// channel compression activates. Set it to 0 to activate at genesis. Nil to disable channel compression.
+	L2GenesisChannelCompressionTimeOffset *hexutil.Uint64 `json:"l2GenesisChannelCompressionTimeOffset,omitempty"`
 	// L2GenesisBlockExtraData is configurable extradata. Will default to []byte("BEDROCK") if left unspecified.
 	L2GenesisBlockExtraData []byte `json:"l2GenesisBlockExtraData"`
 	// ProxyAdminOwner represents the owner of the ProxyAdmin predeploy on L2.
@@ -474,6 +477,17 @@ func (d *DeployConfig) SpanBatchTime(genesisTime uint64) *uint64 {
 	return &v
 }
 
+func (d *DeployConfig) ChannelCompressionTime(genesisTime uint64) *uint64 {
+	if d.L2GenesisChannelCompressionTimeOffset == nil {
+		return nil
+	}
+	v := uint64(0)
+	if offset := *d.L2GenesisChannelCompressionTimeOffset; offset > 0 {
+		v = genesisTime + uint64(offset)
+	}
+	return &v
+}
+
 // RollupConfig converts a DeployConfig to a rollup.Config
 func (d *DeployConfig) RollupConfig(l1StartBlock *types.Block, l2GenesisBlockHash common.Hash, l2GenesisBlockNumber uint64) (*rollup.Config, error) {
 	if d.OptimismPortalProxy == (common.Address{}) {
@@ -513,6 +527,7 @@ func (d *DeployConfig) RollupConfig(l1StartBlock *types.Block, l2GenesisBlockHas
 		RegolithTime:           d.RegolithTime(l1StartBlock.Time()),
 		CanyonTime:             d.CanyonTime(l1StartBlock.Time()),
 		SpanBatchTime:          d.SpanBatchTime(l1StartBlock.Time()),
+		ChannelCompressionTime: d.ChannelCompressionTime(l1StartBlock.Time()),
 	}, nil
 }
 
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/urfave/cli/v2"
+
+	"github.com/ethereum-optimism/optimism/op-bindings/bindings"
+	"github.com/ethereum-optimism/optimism/op-challenger/flags"
+	"github.com/ethereum-optimism/optimism/op-service/txmgr"
+)
+
+var (
+	GameTypeFlag = &cli.UintFlag{
+		Name:  "game-type",
+		Usage: "Type of the game to create.",
+		Value: 0,
+	}
+	RootClaimFlag = &cli.StringFlag{
+		Name:  "root-claim",
+		Usage: "Root claim of the game to create, a hex encoded 32 byte hash.",
+	}
+	L2BlockNumberFlag = &cli.Uint64Flag{
+		Name:  "l2-block-number",
+		Usage: "L2 block number of the disputed output. Defaults to the block number of the latest output proposal in the L2 output oracle.",
+	}
+)
+
+// createdGame is the result of the create-game subcommand.
+type createdGame struct {
+	Game          common.Address `json:"game"`
+	GameType      uint8          `json:"gameType"`
+	RootClaim     common.Hash    `json:"rootClaim"`
+	L2BlockNumber *big.Int       `json:"l2BlockNumber"`
+	L1Checkpoint  *big.Int       `json:"l1Checkpoint"`
+}
+
+// gameCreator creates fault dispute games that dispute an output proposal of the L2 output oracle.
+type gameCreator struct {
+	logger      log.Logger
+	txMgr       txmgr.TxManager
+	client      bind.ContractCaller
+	factoryAddr common.Address
+}
+
+// Create checkpoints the L1 head in the block oracle, and creates the game with that checkpoint.
+// The output proposal of the latest L2 block in the L2 output oracle is disputed if l2BlockNumber is nil.
+func (c *gameCreator) Create(ctx context.Context, gameType uint8, rootClaim common.Hash, l2BlockNumber *big.Int) (*createdGame, error) {
+	opts := &bind.CallOpts{Context: ctx}
+	factory, err := bindings.NewDisputeGameFactoryCaller(c.factoryAddr, c.client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind the dispute game factory contract: %w", err)
+	}
+	implAddr, err := factory.GameImpls(opts, gameType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch implementation of game type %d: %w", gameType, err)
+	}
+	if implAddr == (common.Address{}) {
+		return nil, fmt.Errorf("no implementation of game type %d", gameType)
+	}
+	impl, err := bindings.NewFaultDisputeGameCaller(implAddr, c.client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind the fault dispute game implementation: %w", err)
+	}
+	blockOracleAddr, err := impl.BLOCKORACLE(opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch block oracle address: %w", err)
+	}
+	if l2BlockNumber == nil {
+		l2ooAddr, err := impl.L2OUTPUTORACLE(opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch L2 output oracle address: %w", err)
+		}
+		l2oo, err := bindings.NewL2OutputOracleCaller(l2ooAddr, c.client)
+		if err != nil {
+			return nil, fmt.Errorf("failed to bind the L2 output oracle contract: %w", err)
+		}
+		if l2BlockNumber, err = l2oo.LatestBlockNumber(opts); err != nil {
+			return nil, fmt.Errorf("failed to fetch latest L2 block number: %w", err)
+		}
+	}
+	c.logger.Info("Disputing output proposal", "l2BlockNumber", l2BlockNumber, "impl", implAddr)
+
+	l1Checkpoint, err := c.checkpoint(ctx, blockOracleAddr)
+	if err != nil {
+		return nil, err
+	}
+	c.logger.Info("Checkpointed L1 head", "blockOracle", blockOracleAddr, "l1Checkpoint", l1Checkpoint)
+
+	game, err := c.create(ctx, gameType, rootClaim, l2BlockNumber, l1Checkpoint)
+	if err != nil {
+		return nil, err
+	}
+	return &createdGame{
+		Game:          game,
+		GameType:      gameType,
+		RootClaim:     rootClaim,
+		L2BlockNumber: l2BlockNumber,
+		L1Checkpoint:  l1Checkpoint,
+	}, nil
+}
+
+// checkpoint records the current L1 head in the block oracle, and returns the checkpointed L1 block number.
+func (c *gameCreator) checkpoint(ctx context.Context, blockOracleAddr common.Address) (*big.Int, error) {
+	blockOracleAbi, err := bindings.BlockOracleMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	txData, err := blockOracleAbi.Pack("checkpoint")
+	if err != nil {
+		return nil, err
+	}
+	receipt, err := sendTx(ctx, c.txMgr, blockOracleAddr, txData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to checkpoint the block oracle: %w", err)
+	}
+	filterer, err := bindings.NewBlockOracleFilterer(blockOracleAddr, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range receipt.Logs {
+		if checkpoint, err := filterer.ParseCheckpoint(*l); err == nil {
+			return checkpoint.BlockNumber, nil
+		}
+	}
+	return nil, fmt.Errorf("no checkpoint event in receipt of tx %v", receipt.TxHash)
+}
+
+// create creates the game, and returns the address of the game proxy.
+func (c *gameCreator) create(ctx context.Context, gameType uint8, rootClaim common.Hash, l2BlockNumber *big.Int, l1Checkpoint *big.Int) (common.Address, error) {
+	factoryAbi, err := bindings.DisputeGameFactoryMetaData.GetAbi()
+	if err != nil {
+		return common.Address{}, err
+	}
+	txData, err := factoryAbi.Pack("create", gameType, rootClaim, faultGameExtraData(l2BlockNumber, l1Checkpoint))
+	if err != nil {
+		return common.Address{}, err
+	}
+	receipt, err := sendTx(ctx, c.txMgr, c.factoryAddr, txData)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to create game: %w", err)
+	}
+	filterer, err := bindings.NewDisputeGameFactoryFilterer(c.factoryAddr, nil)
+	if err != nil {
+		return common.Address{}, err
+	}
+	for _, l := range receipt.Logs {
+		if created, err := filterer.ParseDisputeGameCreated(*l); err == nil {
+			return created.DisputeProxy, nil
+		}
+	}
+	return common.Address{}, fmt.Errorf("no game created event in receipt of tx %v", receipt.TxHash)
+}
+
+// faultGameExtraData returns the extra data of a fault dispute game: abi.encode(l2BlockNumber, l1Checkpoint).
+func faultGameExtraData(l2BlockNumber *big.Int, l1Checkpoint *big.Int) []byte {
+	return append(common.BigToHash(l2BlockNumber).Bytes(), common.BigToHash(l1Checkpoint).Bytes()...)
+}
+
+func CreateGame(ctx *cli.Context) error {
+	if err := checkRequired(ctx, flags.L1EthRpcFlag, flags.FactoryAddressFlag, RootClaimFlag); err != nil {
+		return err
+	}
+	if err := checkOutputFormat(ctx); err != nil {
+		return err
+	}
+	factoryAddr, err := parseAddress(ctx, flags.FactoryAddressFlag)
+	if err != nil {
+		return err
+	}
+	rootClaim, err := parseHash(ctx, RootClaimFlag)
+	if err != nil {
+		return err
+	}
+	gameType := ctx.Uint(GameTypeFlag.Name)
+	if gameType > 255 {
+		return fmt.Errorf("invalid %s: %d", GameTypeFlag.Name, gameType)
+	}
+	var l2BlockNumber *big.Int
+	if ctx.IsSet(L2BlockNumberFlag.Name) {
+		l2BlockNumber = new(big.Int).SetUint64(ctx.Uint64(L2BlockNumberFlag.Name))
+	}
+
+	logger, l1Client, err := dialL1(ctx)
+	if err != nil {
+		return err
+	}
+	defer l1Client.Close()
+	txMgr, err := newTxManager(ctx, logger)
+	if err != nil {
+		return err
+	}
+
+	creator := &gameCreator{logger: logger, txMgr: txMgr, client: l1Client, factoryAddr: factoryAddr}
+	game, err := creator.Create(ctx.Context, uint8(gameType), rootClaim, l2BlockNumber)
+	if err != nil {
+		return err
+	}
+	return writeOutput(ctx, game, func(w io.Writer) error {
+		_, err := fmt.Fprintf(w, "Game:\t%v\nType:\t%d\nRoot claim:\t%v\nL2 block number:\t%v\nL1 checkpoint:\t%v\n",
+			game.Game, game.GameType, game.RootClaim, game.L2BlockNumber, game.L1Checkpoint)
+		return err
+	})
+}
+
+var CreateGameCommand = &cli.Command{
+	Name:  "create-game",
+	Usage: "Create a dispute game",
+	Description: "Creates a fault dispute game that disputes an output proposal of the L2 output oracle. " +
+		"This sends two transactions: the first checkpoints the L1 head in the block oracle, the second creates the game.",
+	Action: CreateGame,
+	Flags:  txFlags(flags.FactoryAddressFlag, GameTypeFlag, RootClaimFlag, L2BlockNumberFlag, OutputFlag),
+}
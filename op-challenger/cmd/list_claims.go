@@ -0,0 +1,192 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/urfave/cli/v2"
+
+	"github.com/ethereum-optimism/optimism/op-challenger/flags"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
+	gameTypes "github.com/ethereum-optimism/optimism/op-challenger/game/types"
+)
+
+// claimInfo describes a claim of a game, and the move that posted it.
+type claimInfo struct {
+	Index int `json:"index"`
+	// ParentIndex is the index of the countered claim, nil for the root claim.
+	ParentIndex *int `json:"parentIndex,omitempty"`
+	// Move is attack or defend, empty for the root claim.
+	Move      string      `json:"move,omitempty"`
+	Value     common.Hash `json:"value"`
+	Countered bool        `json:"countered"`
+	// Timestamp is when the claim was made, and ClockDuration the time its team had used by then, in seconds.
+	Timestamp     uint64   `json:"timestamp"`
+	ClockDuration uint64   `json:"clockDuration"`
+	Position      *big.Int `json:"position"`
+	Depth         int      `json:"depth"`
+	IndexAtDepth  *big.Int `json:"indexAtDepth"`
+	TraceIndex    *big.Int `json:"traceIndex"`
+}
+
+type gameClaims struct {
+	Game     common.Address `json:"game"`
+	Status   string         `json:"status"`
+	MaxDepth uint64         `json:"maxDepth"`
+	Claims   []claimInfo    `json:"claims"`
+}
+
+// claimLoader loads the claims of a single game, see [fault.NewLoaderFromBindings].
+type claimLoader interface {
+	GetGameStatus(ctx context.Context) (gameTypes.GameStatus, error)
+	FetchGameDepth(ctx context.Context) (uint64, error)
+	FetchClaims(ctx context.Context) ([]types.Claim, error)
+}
+
+// moveType returns whether the claim at the given position attacks or defends the parent claim.
+func moveType(parent types.Position, pos types.Position) string {
+	if parent.Attack().ToGIndex().Cmp(pos.ToGIndex()) == 0 {
+		return "attack"
+	}
+	return "defend"
+}
+
+func listClaims(ctx context.Context, game common.Address, l claimLoader) (*gameClaims, error) {
+	status, err := l.GetGameStatus(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch game status: %w", err)
+	}
+	maxDepth, err := l.FetchGameDepth(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch game depth: %w", err)
+	}
+	claims, err := l.FetchClaims(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch claims: %w", err)
+	}
+	infos := make([]claimInfo, len(claims))
+	for i, claim := range claims {
+		info := claimInfo{
+			Index:         claim.ContractIndex,
+			Value:         claim.Value,
+			Countered:     claim.Countered,
+			Timestamp:     claim.Clock.Timestamp,
+			ClockDuration: claim.Clock.Duration,
+			Position:      claim.ToGIndex(),
+			Depth:         claim.Depth(),
+			IndexAtDepth:  claim.IndexAtDepth(),
+			TraceIndex:    claim.TraceIndex(int(maxDepth)),
+		}
+		if !claim.IsRoot() && claim.ParentContractIndex >= 0 && claim.ParentContractIndex < len(claims) {
+			parentIdx := claim.ParentContractIndex
+			info.ParentIndex = &parentIdx
+			info.Move = moveType(claims[parentIdx].Position, claim.Position)
+		}
+		infos[i] = info
+	}
+	return &gameClaims{
+		Game:     game,
+		Status:   status.String(),
+		MaxDepth: maxDepth,
+		Claims:   infos,
+	}, nil
+}
+
+// writeClaimTree writes the claims as a tree, with every counter-claim below the claim that it counters.
+func writeClaimTree(w io.Writer, game *gameClaims) error {
+	if _, err := fmt.Fprintf(w, "Game: %v Status: %s Max depth: %d Claims: %d\n",
+		game.Game, game.Status, game.MaxDepth, len(game.Claims)); err != nil {
+		return err
+	}
+	children := make(map[int][]claimInfo)
+	var roots []claimInfo
+	for _, claim := range game.Claims {
+		if claim.ParentIndex == nil {
+			roots = append(roots, claim)
+		} else {
+			children[*claim.ParentIndex] = append(children[*claim.ParentIndex], claim)
+		}
+	}
+	var writeClaim func(claim claimInfo, prefix string, branch string, last bool) error
+	writeClaim = func(claim claimInfo, prefix string, branch string, last bool) error {
+		move := claim.Move
+		if move == "" {
+			move = "root"
+		}
+		countered := ""
+		if claim.Countered {
+			countered = " countered"
+		}
+		if _, err := fmt.Fprintf(w, "%s%s#%d %s %v depth: %d index: %v trace: %v%s\n",
+			prefix, branch, claim.Index, move, claim.Value, claim.Depth, claim.IndexAtDepth, claim.TraceIndex, countered); err != nil {
+			return err
+		}
+		switch {
+		case branch == "":
+		case last:
+			prefix += "    "
+		default:
+			prefix += "│   "
+		}
+		counters := children[claim.Index]
+		for i, child := range counters {
+			childLast := i == len(counters)-1
+			childBranch := "├── "
+			if childLast {
+				childBranch = "└── "
+			}
+			if err := writeClaim(child, prefix, childBranch, childLast); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for _, root := range roots {
+		if err := writeClaim(root, "", "", true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func ListClaims(ctx *cli.Context) error {
+	if err := checkRequired(ctx, flags.L1EthRpcFlag, GameAddressFlag); err != nil {
+		return err
+	}
+	if err := checkOutputFormat(ctx); err != nil {
+		return err
+	}
+	gameAddr, err := parseAddress(ctx, GameAddressFlag)
+	if err != nil {
+		return err
+	}
+	_, l1Client, err := dialL1(ctx)
+	if err != nil {
+		return err
+	}
+	defer l1Client.Close()
+
+	l, err := fault.NewLoaderFromBindings(gameAddr, l1Client)
+	if err != nil {
+		return fmt.Errorf("failed to bind the fault dispute game contract: %w", err)
+	}
+	game, err := listClaims(ctx.Context, gameAddr, l)
+	if err != nil {
+		return err
+	}
+	return writeOutput(ctx, game, func(w io.Writer) error {
+		return writeClaimTree(w, game)
+	})
+}
+
+var ListClaimsCommand = &cli.Command{
+	Name:        "list-claims",
+	Usage:       "List the claims of a dispute game",
+	Description: "Prints the claims of a fault dispute game. The table output renders the claims as a tree of moves, with the depth, index at depth and trace index of every claim's position.",
+	Action:      ListClaims,
+	Flags:       readOnlyFlags(GameAddressFlag, OutputFlag),
+}
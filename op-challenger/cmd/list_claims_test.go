@@ -0,0 +1,125 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
+	gameTypes "github.com/ethereum-optimism/optimism/op-challenger/game/types"
+)
+
+var (
+	testGameAddr = common.Address{0xbb}
+	errNoClaims  = errors.New("no claims")
+)
+
+type stubClaimLoader struct {
+	claims []types.Claim
+	err    error
+}
+
+func (s *stubClaimLoader) GetGameStatus(_ context.Context) (gameTypes.GameStatus, error) {
+	return gameTypes.GameStatusInProgress, nil
+}
+
+func (s *stubClaimLoader) FetchGameDepth(_ context.Context) (uint64, error) {
+	return 3, nil
+}
+
+func (s *stubClaimLoader) FetchClaims(_ context.Context) ([]types.Claim, error) {
+	return s.claims, s.err
+}
+
+func testClaim(idx int, parentIdx int, value byte, pos types.Position, countered bool) types.Claim {
+	return types.Claim{
+		ClaimData: types.ClaimData{
+			Value:    common.Hash{value},
+			Position: pos,
+		},
+		Countered:           countered,
+		ContractIndex:       idx,
+		ParentContractIndex: parentIdx,
+	}
+}
+
+// testClaims returns a game where the root claim is attacked, and that attack is both attacked and defended.
+func testClaims() []types.Claim {
+	root := types.NewPosition(0, big.NewInt(0))
+	attack := root.Attack()
+	defend := attack.Defend()
+	return []types.Claim{
+		testClaim(0, math.MaxUint32, 0xa0, root, true),
+		testClaim(1, 0, 0xa1, attack, true),
+		testClaim(2, 1, 0xa2, attack.Attack(), false),
+		testClaim(3, 1, 0xa3, defend, true),
+		testClaim(4, 3, 0xa4, defend.Attack(), false),
+	}
+}
+
+func TestListClaims(t *testing.T) {
+	t.Run("MovesAndPositions", func(t *testing.T) {
+		game, err := listClaims(context.Background(), testGameAddr, &stubClaimLoader{claims: testClaims()})
+		require.NoError(t, err)
+		require.Equal(t, testGameAddr, game.Game)
+		require.Equal(t, "In Progress", game.Status)
+		require.Equal(t, uint64(3), game.MaxDepth)
+		require.Len(t, game.Claims, 5)
+
+		root := game.Claims[0]
+		require.Nil(t, root.ParentIndex)
+		require.Empty(t, root.Move)
+		require.Equal(t, int64(7), root.TraceIndex.Int64())
+
+		expected := []struct {
+			parent     int
+			move       string
+			depth      int
+			index      int64
+			traceIndex int64
+		}{
+			{parent: 0, move: "attack", depth: 1, index: 0, traceIndex: 3},
+			{parent: 1, move: "attack", depth: 2, index: 0, traceIndex: 1},
+			{parent: 1, move: "defend", depth: 2, index: 2, traceIndex: 5},
+			{parent: 3, move: "attack", depth: 3, index: 4, traceIndex: 4},
+		}
+		for i, exp := range expected {
+			claim := game.Claims[i+1]
+			require.Equal(t, i+1, claim.Index)
+			require.Equal(t, exp.parent, *claim.ParentIndex, "claim %d", claim.Index)
+			require.Equal(t, exp.move, claim.Move, "claim %d", claim.Index)
+			require.Equal(t, exp.depth, claim.Depth, "claim %d", claim.Index)
+			require.Equal(t, exp.index, claim.IndexAtDepth.Int64(), "claim %d", claim.Index)
+			require.Equal(t, exp.traceIndex, claim.TraceIndex.Int64(), "claim %d", claim.Index)
+		}
+	})
+
+	t.Run("FetchError", func(t *testing.T) {
+		_, err := listClaims(context.Background(), testGameAddr, &stubClaimLoader{err: errNoClaims})
+		require.ErrorIs(t, err, errNoClaims)
+	})
+}
+
+func TestWriteClaimTree(t *testing.T) {
+	game, err := listClaims(context.Background(), testGameAddr, &stubClaimLoader{claims: testClaims()})
+	require.NoError(t, err)
+	var out bytes.Buffer
+	require.NoError(t, writeClaimTree(&out, game))
+
+	hash := func(b byte) string {
+		return common.Hash{b}.String()
+	}
+	expected := "Game: " + testGameAddr.String() + " Status: In Progress Max depth: 3 Claims: 5\n" +
+		"#0 root " + hash(0xa0) + " depth: 0 index: 0 trace: 7 countered\n" +
+		"└── #1 attack " + hash(0xa1) + " depth: 1 index: 0 trace: 3 countered\n" +
+		"    ├── #2 attack " + hash(0xa2) + " depth: 2 index: 0 trace: 1\n" +
+		"    └── #3 defend " + hash(0xa3) + " depth: 2 index: 2 trace: 5 countered\n" +
+		"        └── #4 attack " + hash(0xa4) + " depth: 3 index: 4 trace: 4\n"
+	require.Equal(t, expected, out.String())
+}
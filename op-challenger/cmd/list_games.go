@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/urfave/cli/v2"
+
+	"github.com/ethereum-optimism/optimism/op-bindings/bindings"
+	"github.com/ethereum-optimism/optimism/op-challenger/flags"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/loader"
+	gameTypes "github.com/ethereum-optimism/optimism/op-challenger/game/types"
+)
+
+// gameInfo describes a game created by the dispute game factory.
+type gameInfo struct {
+	Index     uint64         `json:"index"`
+	GameType  uint8          `json:"gameType"`
+	Timestamp uint64         `json:"timestamp"`
+	Proxy     common.Address `json:"proxy"`
+	Claims    uint64         `json:"claims"`
+	Status    string         `json:"status"`
+}
+
+// gameStatusReader reads the state of a single game, see [fault.NewLoaderFromBindings].
+type gameStatusReader interface {
+	GetGameStatus(ctx context.Context) (gameTypes.GameStatus, error)
+	GetClaimCount(ctx context.Context) (uint64, error)
+}
+
+// listGames fetches all games of the factory at the given block, oldest first.
+func listGames(ctx context.Context, games *loader.GameLoader, newReader func(common.Address) (gameStatusReader, error), blockNumber *big.Int) ([]gameInfo, error) {
+	all, err := games.FetchAllGamesAtBlock(ctx, 0, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	infos := make([]gameInfo, len(all))
+	for i, game := range all {
+		// The games are loaded newest first.
+		idx := len(all) - 1 - i
+		reader, err := newReader(game.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("failed to bind game %v: %w", game.Proxy, err)
+		}
+		status, err := reader.GetGameStatus(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch status of game %v: %w", game.Proxy, err)
+		}
+		claims, err := reader.GetClaimCount(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch claim count of game %v: %w", game.Proxy, err)
+		}
+		infos[idx] = gameInfo{
+			Index:     uint64(idx),
+			GameType:  game.GameType,
+			Timestamp: game.Timestamp,
+			Proxy:     game.Proxy,
+			Claims:    claims,
+			Status:    status.String(),
+		}
+	}
+	return infos, nil
+}
+
+func writeGamesTable(w io.Writer, games []gameInfo) error {
+	if _, err := fmt.Fprintln(w, "Idx\tGame\tType\tCreated\tClaims\tStatus"); err != nil {
+		return err
+	}
+	for _, game := range games {
+		created := time.Unix(int64(game.Timestamp), 0).UTC().Format(time.DateTime)
+		if _, err := fmt.Fprintf(w, "%d\t%v\t%d\t%s\t%d\t%s\n",
+			game.Index, game.Proxy, game.GameType, created, game.Claims, game.Status); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func ListGames(ctx *cli.Context) error {
+	if err := checkRequired(ctx, flags.L1EthRpcFlag, flags.FactoryAddressFlag); err != nil {
+		return err
+	}
+	if err := checkOutputFormat(ctx); err != nil {
+		return err
+	}
+	factoryAddr, err := parseAddress(ctx, flags.FactoryAddressFlag)
+	if err != nil {
+		return err
+	}
+	_, l1Client, err := dialL1(ctx)
+	if err != nil {
+		return err
+	}
+	defer l1Client.Close()
+
+	factory, err := bindings.NewDisputeGameFactoryCaller(factoryAddr, l1Client)
+	if err != nil {
+		return fmt.Errorf("failed to bind the dispute game factory contract: %w", err)
+	}
+	head, err := l1Client.BlockNumber(ctx.Context)
+	if err != nil {
+		return fmt.Errorf("failed to fetch L1 head: %w", err)
+	}
+	games, err := listGames(ctx.Context, loader.NewGameLoader(factory), func(addr common.Address) (gameStatusReader, error) {
+		return fault.NewLoaderFromBindings(addr, l1Client)
+	}, new(big.Int).SetUint64(head))
+	if err != nil {
+		return err
+	}
+	return writeOutput(ctx, games, func(w io.Writer) error {
+		return writeGamesTable(w, games)
+	})
+}
+
+var ListGamesCommand = &cli.Command{
+	Name:        "list-games",
+	Usage:       "List the games created by the dispute game factory",
+	Description: "Prints the games created by the dispute game factory, along with their claim count and current status.",
+	Action:      ListGames,
+	Flags:       readOnlyFlags(flags.FactoryAddressFlag, OutputFlag),
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-challenger/game/loader"
+	gameTypes "github.com/ethereum-optimism/optimism/op-challenger/game/types"
+)
+
+type stubFactory struct {
+	games []gameTypes.GameMetadata
+}
+
+func (s *stubFactory) GameCount(_ *bind.CallOpts) (*big.Int, error) {
+	return big.NewInt(int64(len(s.games))), nil
+}
+
+func (s *stubFactory) GameAtIndex(_ *bind.CallOpts, idx *big.Int) (struct {
+	GameType  uint8
+	Timestamp uint64
+	Proxy     common.Address
+}, error) {
+	game := s.games[idx.Uint64()]
+	return struct {
+		GameType  uint8
+		Timestamp uint64
+		Proxy     common.Address
+	}{GameType: game.GameType, Timestamp: game.Timestamp, Proxy: game.Proxy}, nil
+}
+
+type stubGameStatus struct {
+	status gameTypes.GameStatus
+	claims uint64
+}
+
+func (s *stubGameStatus) GetGameStatus(_ context.Context) (gameTypes.GameStatus, error) {
+	return s.status, nil
+}
+
+func (s *stubGameStatus) GetClaimCount(_ context.Context) (uint64, error) {
+	return s.claims, nil
+}
+
+func TestListGames(t *testing.T) {
+	factory := &stubFactory{games: []gameTypes.GameMetadata{
+		{GameType: 0, Timestamp: 1000, Proxy: common.Address{0xaa}},
+		{GameType: 1, Timestamp: 2000, Proxy: common.Address{0xbb}},
+	}}
+	readers := map[common.Address]gameStatusReader{
+		{0xaa}: &stubGameStatus{status: gameTypes.GameStatusChallengerWon, claims: 5},
+		{0xbb}: &stubGameStatus{status: gameTypes.GameStatusInProgress, claims: 1},
+	}
+	games, err := listGames(context.Background(), loader.NewGameLoader(factory), func(addr common.Address) (gameStatusReader, error) {
+		return readers[addr], nil
+	}, big.NewInt(100))
+	require.NoError(t, err)
+	require.Equal(t, []gameInfo{
+		{Index: 0, GameType: 0, Timestamp: 1000, Proxy: common.Address{0xaa}, Claims: 5, Status: "Challenger Won"},
+		{Index: 1, GameType: 1, Timestamp: 2000, Proxy: common.Address{0xbb}, Claims: 1, Status: "In Progress"},
+	}, games)
+
+	var out bytes.Buffer
+	require.NoError(t, writeGamesTable(&out, games))
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 3)
+	require.Contains(t, lines[1], common.Address{0xaa}.String())
+	require.Contains(t, lines[1], "1970-01-01 00:16:40")
+	require.Contains(t, lines[2], "In Progress")
+}
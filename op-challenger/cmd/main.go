@@ -55,6 +55,13 @@ func run(args []string, action ConfigAction) error {
 		}
 		return action(ctx.Context, logger, cfg)
 	}
+	app.Commands = []*cli.Command{
+		ListGamesCommand,
+		ListClaimsCommand,
+		CreateGameCommand,
+		MoveCommand,
+		ResolveCommand,
+	}
 	return app.Run(args)
 }
 
@@ -3,11 +3,14 @@ package main
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/ethereum-optimism/optimism/op-challenger/config"
 	"github.com/ethereum-optimism/optimism/op-node/chaincfg"
+	"github.com/ethereum-optimism/optimism/op-service/dial"
 	"github.com/ethereum-optimism/optimism/op-service/txmgr"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/log"
@@ -370,6 +373,104 @@ func TestGameWindow(t *testing.T) {
 	})
 }
 
+func TestMaxSpendPerGame(t *testing.T) {
+	t.Run("DefaultsToNoLimit", func(t *testing.T) {
+		cfg := configForArgs(t, addRequiredArgs(config.TraceTypeAlphabet))
+		require.Zero(t, cfg.MaxSpendPerGame)
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		cfg := configForArgs(t, addRequiredArgs(config.TraceTypeAlphabet, "--max-spend-per-game=1000000"))
+		require.Equal(t, uint64(1_000_000), cfg.MaxSpendPerGame)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		verifyArgsInvalid(t, "invalid value \"abc\" for flag -max-spend-per-game",
+			addRequiredArgs(config.TraceTypeAlphabet, "--max-spend-per-game=abc"))
+	})
+}
+
+func TestL1RPCConfig(t *testing.T) {
+	t.Run("UsesDefault", func(t *testing.T) {
+		cfg := configForArgs(t, addRequiredArgs(config.TraceTypeAlphabet))
+		require.Equal(t, dial.DefaultCLIConfig(), cfg.L1RPCConfig)
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		cfg := configForArgs(t, addRequiredArgs(config.TraceTypeAlphabet, "--l1-quorum=2", "--l1-max-lag-blocks=5"))
+		require.Equal(t, dial.CLIConfig{L1MaxLagBlocks: 5, L1Quorum: 2}, cfg.L1RPCConfig)
+	})
+}
+
+func TestChainsConfig(t *testing.T) {
+	writeChains := func(t *testing.T, content string) string {
+		path := filepath.Join(t.TempDir(), "chains.json")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		return path
+	}
+	chainsArgs := func(path string, args ...string) []string {
+		return append([]string{
+			"--agree-with-proposed-output=" + agreeWithProposedOutput,
+			"--l1-eth-rpc=" + l1EthRpc,
+			"--trace-type=" + config.TraceTypeCannon.String(),
+			"--datadir=" + datadir,
+			"--cannon-bin=" + cannonBin,
+			"--cannon-server=" + cannonServer,
+			"--chains-config=" + path,
+		}, args...)
+	}
+	validChains := `{"chains": [
+		{"name": "a", "gameFactoryAddress": "0xaa00000000000000000000000000000000000000", "cannonNetwork": "` + cannonNetwork + `",
+		 "cannonPrestate": "a.json", "cannonL2": "http://a:9545"},
+		{"name": "b", "gameFactoryAddress": "0xbb00000000000000000000000000000000000000", "cannonNetwork": "` + otherCannonNetwork + `",
+		 "cannonPrestate": "b.json", "cannonL2": "http://b:9545"}
+	]}`
+
+	t.Run("NotRequired", func(t *testing.T) {
+		cfg := configForArgs(t, addRequiredArgs(config.TraceTypeAlphabet))
+		require.Empty(t, cfg.Chains)
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		cfg := configForArgs(t, chainsArgs(writeChains(t, validChains)))
+		require.Equal(t, common.Address{}, cfg.GameFactoryAddress)
+		require.Len(t, cfg.Chains, 2)
+		require.Equal(t, "a", cfg.Chains[0].Name)
+		require.Equal(t, common.HexToAddress("0xaa00000000000000000000000000000000000000"), cfg.Chains[0].GameFactoryAddress)
+		require.Equal(t, "http://b:9545", cfg.Chains[1].CannonL2)
+	})
+
+	t.Run("CannonBinRequired", func(t *testing.T) {
+		args := []string{
+			"--agree-with-proposed-output=" + agreeWithProposedOutput,
+			"--l1-eth-rpc=" + l1EthRpc,
+			"--trace-type=" + config.TraceTypeCannon.String(),
+			"--datadir=" + datadir,
+			"--chains-config=" + writeChains(t, validChains),
+		}
+		verifyArgsInvalid(t, "flag cannon-bin is required", args)
+	})
+
+	t.Run("MustNotSpecifyChainFlags", func(t *testing.T) {
+		verifyArgsInvalid(t, "flag game-factory-address can not be used with chains-config",
+			chainsArgs(writeChains(t, validChains), "--game-factory-address="+gameFactoryAddressValue))
+		verifyArgsInvalid(t, "flag cannon-l2 can not be used with chains-config",
+			chainsArgs(writeChains(t, validChains), "--cannon-l2="+cannonL2))
+	})
+
+	t.Run("InvalidChain", func(t *testing.T) {
+		cfg := configForArgs(t, chainsArgs(writeChains(t, `{"chains": [
+			{"name": "b", "gameFactoryAddress": "0xbb00000000000000000000000000000000000000", "cannonNetwork": "`+cannonNetwork+`",
+			 "cannonL2": "http://b:9545"}
+		]}`)))
+		require.ErrorIs(t, cfg.Check(), config.ErrMissingCannonAbsolutePreState)
+	})
+
+	t.Run("MissingFile", func(t *testing.T) {
+		verifyArgsInvalid(t, "failed to read chains config", chainsArgs(filepath.Join(t.TempDir(), "missing.json")))
+	})
+}
+
 func TestRequireEitherCannonNetworkOrRollupAndGenesis(t *testing.T) {
 	verifyArgsInvalid(
 		t,
@@ -431,6 +532,34 @@ func TestCannonL2Genesis(t *testing.T) {
 	})
 }
 
+func TestSubcommandArgs(t *testing.T) {
+	gameAddr := "0xcc00000000000000000000000000000000000000"
+	claim := "0x0146381068b59d2098495baa72ed2f773c1e09458610a7a208984859dff73add"
+	tests := []struct {
+		name        string
+		args        []string
+		expectedErr string
+	}{
+		{name: "ListGamesRequiresL1", args: []string{"list-games", "--game-factory-address", gameFactoryAddressValue}, expectedErr: "flag l1-eth-rpc is required"},
+		{name: "ListGamesRequiresFactory", args: []string{"list-games", "--l1-eth-rpc", l1EthRpc}, expectedErr: "flag game-factory-address is required"},
+		{name: "ListGamesInvalidOutput", args: []string{"list-games", "--l1-eth-rpc", l1EthRpc, "--game-factory-address", gameFactoryAddressValue, "--output", "xml"}, expectedErr: "invalid output"},
+		{name: "ListClaimsRequiresGame", args: []string{"list-claims", "--l1-eth-rpc", l1EthRpc}, expectedErr: "flag game-address is required"},
+		{name: "ListClaimsInvalidGame", args: []string{"list-claims", "--l1-eth-rpc", l1EthRpc, "--game-address", "0x1234"}, expectedErr: "invalid game-address"},
+		{name: "CreateGameRequiresRootClaim", args: []string{"create-game", "--l1-eth-rpc", l1EthRpc, "--game-factory-address", gameFactoryAddressValue}, expectedErr: "flag root-claim is required"},
+		{name: "CreateGameInvalidRootClaim", args: []string{"create-game", "--l1-eth-rpc", l1EthRpc, "--game-factory-address", gameFactoryAddressValue, "--root-claim", "0x01"}, expectedErr: "invalid root-claim"},
+		{name: "MoveRequiresClaim", args: []string{"move", "--l1-eth-rpc", l1EthRpc, "--game-address", gameAddr, "--attack"}, expectedErr: "flag claim is required"},
+		{name: "MoveRequiresAttackOrDefend", args: []string{"move", "--l1-eth-rpc", l1EthRpc, "--game-address", gameAddr, "--claim", claim}, expectedErr: "exactly one of attack and defend must be set"},
+		{name: "MoveRejectsAttackAndDefend", args: []string{"move", "--l1-eth-rpc", l1EthRpc, "--game-address", gameAddr, "--claim", claim, "--attack", "--defend"}, expectedErr: "exactly one of attack and defend must be set"},
+		{name: "ResolveRequiresGame", args: []string{"resolve", "--l1-eth-rpc", l1EthRpc}, expectedErr: "flag game-address is required"},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			verifyArgsInvalid(t, test.expectedErr, test.args)
+		})
+	}
+}
+
 func verifyArgsInvalid(t *testing.T, messageContains string, cliArgs []string) {
 	_, _, err := runWithArgs(cliArgs)
 	require.ErrorContains(t, err, messageContains)
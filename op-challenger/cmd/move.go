@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/ethereum-optimism/optimism/op-challenger/flags"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/responder"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
+)
+
+const latestClaim = "latest"
+
+var (
+	AttackFlag = &cli.BoolFlag{
+		Name:  "attack",
+		Usage: "Attack the parent claim, i.e. disagree with the parent claim.",
+	}
+	DefendFlag = &cli.BoolFlag{
+		Name:  "defend",
+		Usage: "Defend the parent claim, i.e. agree with the parent claim.",
+	}
+	ParentIndexFlag = &cli.StringFlag{
+		Name:  "parent-index",
+		Usage: "Index of the claim to counter. Use " + latestClaim + " to counter the latest claim added to the game.",
+		Value: latestClaim,
+	}
+	ClaimFlag = &cli.StringFlag{
+		Name:  "claim",
+		Usage: "Claim of the move, a hex encoded 32 byte hash.",
+	}
+)
+
+// parseParentIndex returns the parent claim index of a move, which is either a claim index or latest.
+func parseParentIndex(ctx context.Context, parentIndex string, claimCount func(ctx context.Context) (uint64, error)) (uint64, error) {
+	if parentIndex != latestClaim {
+		idx, err := strconv.ParseUint(parentIndex, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid %s: %q, must be a claim index or %s", ParentIndexFlag.Name, parentIndex, latestClaim)
+		}
+		return idx, nil
+	}
+	count, err := claimCount(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch claim count: %w", err)
+	}
+	if count == 0 {
+		return 0, fmt.Errorf("game has no claims")
+	}
+	return count - 1, nil
+}
+
+func Move(ctx *cli.Context) error {
+	if err := checkRequired(ctx, flags.L1EthRpcFlag, GameAddressFlag, ClaimFlag); err != nil {
+		return err
+	}
+	attack, defend := ctx.Bool(AttackFlag.Name), ctx.Bool(DefendFlag.Name)
+	if attack == defend {
+		return fmt.Errorf("exactly one of %s and %s must be set", AttackFlag.Name, DefendFlag.Name)
+	}
+	gameAddr, err := parseAddress(ctx, GameAddressFlag)
+	if err != nil {
+		return err
+	}
+	claim, err := parseHash(ctx, ClaimFlag)
+	if err != nil {
+		return err
+	}
+
+	logger, l1Client, err := dialL1(ctx)
+	if err != nil {
+		return err
+	}
+	defer l1Client.Close()
+	txMgr, err := newTxManager(ctx, logger)
+	if err != nil {
+		return err
+	}
+	l, err := fault.NewLoaderFromBindings(gameAddr, l1Client)
+	if err != nil {
+		return fmt.Errorf("failed to bind the fault dispute game contract: %w", err)
+	}
+	parentIdx, err := parseParentIndex(ctx.Context, ctx.String(ParentIndexFlag.Name), l.GetClaimCount)
+	if err != nil {
+		return err
+	}
+	r, err := responder.NewFaultResponder(logger, txMgr, gameAddr)
+	if err != nil {
+		return fmt.Errorf("failed to create the responder: %w", err)
+	}
+
+	action := types.Action{
+		Type:      types.ActionTypeMove,
+		ParentIdx: int(parentIdx),
+		IsAttack:  attack,
+		Value:     claim,
+	}
+	logger.Info("Performing move", "game", gameAddr, "parent", parentIdx, "attack", attack, "claim", claim)
+	if err := r.PerformAction(ctx.Context, action); err != nil {
+		return fmt.Errorf("failed to perform move: %w", err)
+	}
+	return nil
+}
+
+var MoveCommand = &cli.Command{
+	Name:        "move",
+	Usage:       "Attack or defend a claim of a dispute game",
+	Description: "Posts a counter-claim that either attacks or defends a claim of a fault dispute game.",
+	Action:      Move,
+	Flags:       txFlags(GameAddressFlag, AttackFlag, DefendFlag, ParentIndexFlag, ClaimFlag),
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseParentIndex(t *testing.T) {
+	claimCount := func(count uint64) func(ctx context.Context) (uint64, error) {
+		return func(ctx context.Context) (uint64, error) {
+			return count, nil
+		}
+	}
+
+	tests := []struct {
+		name        string
+		parentIndex string
+		claimCount  uint64
+		expected    uint64
+		expectedErr string
+	}{
+		{name: "Index", parentIndex: "3", claimCount: 10, expected: 3},
+		{name: "Latest", parentIndex: "latest", claimCount: 10, expected: 9},
+		{name: "LatestWithoutClaims", parentIndex: "latest", claimCount: 0, expectedErr: "game has no claims"},
+		{name: "Invalid", parentIndex: "first", claimCount: 10, expectedErr: "invalid parent-index"},
+		{name: "Negative", parentIndex: "-1", claimCount: 10, expectedErr: "invalid parent-index"},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			idx, err := parseParentIndex(context.Background(), test.parentIndex, claimCount(test.claimCount))
+			if test.expectedErr != "" {
+				require.ErrorContains(t, err, test.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.expected, idx)
+		})
+	}
+}
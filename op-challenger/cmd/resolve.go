@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/ethereum-optimism/optimism/op-challenger/flags"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/responder"
+)
+
+func Resolve(ctx *cli.Context) error {
+	if err := checkRequired(ctx, flags.L1EthRpcFlag, GameAddressFlag); err != nil {
+		return err
+	}
+	gameAddr, err := parseAddress(ctx, GameAddressFlag)
+	if err != nil {
+		return err
+	}
+
+	logger, l1Client, err := dialL1(ctx)
+	if err != nil {
+		return err
+	}
+	defer l1Client.Close()
+	txMgr, err := newTxManager(ctx, logger)
+	if err != nil {
+		return err
+	}
+	r, err := responder.NewFaultResponder(logger, txMgr, gameAddr)
+	if err != nil {
+		return fmt.Errorf("failed to create the responder: %w", err)
+	}
+
+	// Check the game can be resolved before sending the transaction, this also provides the result.
+	status, err := r.CallResolve(ctx.Context)
+	if err != nil {
+		return fmt.Errorf("game can not be resolved: %w", err)
+	}
+	if err := r.Resolve(ctx.Context); err != nil {
+		return fmt.Errorf("failed to resolve game: %w", err)
+	}
+	_, err = fmt.Fprintf(ctx.App.Writer, "Result: %s\n", status)
+	return err
+}
+
+var ResolveCommand = &cli.Command{
+	Name:        "resolve",
+	Usage:       "Resolve a dispute game",
+	Description: "Resolves a fault dispute game and prints the result. Fails if the game is already resolved, or if further moves are still possible.",
+	Action:      Resolve,
+	Flags:       txFlags(GameAddressFlag),
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/urfave/cli/v2"
+
+	"github.com/ethereum-optimism/optimism/op-challenger/flags"
+	opservice "github.com/ethereum-optimism/optimism/op-service"
+	"github.com/ethereum-optimism/optimism/op-service/cliapp"
+	"github.com/ethereum-optimism/optimism/op-service/dial"
+	oplog "github.com/ethereum-optimism/optimism/op-service/log"
+	"github.com/ethereum-optimism/optimism/op-service/txmgr"
+	txmetrics "github.com/ethereum-optimism/optimism/op-service/txmgr/metrics"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+var (
+	GameAddressFlag = &cli.StringFlag{
+		Name:    "game-address",
+		Usage:   "Address of the fault dispute game contract.",
+		EnvVars: opservice.PrefixEnvVar(flags.EnvVarPrefix, "GAME_ADDRESS"),
+	}
+	OutputFlag = &cli.StringFlag{
+		Name:  "output",
+		Usage: "Output format. Valid options: " + outputTable + ", " + outputJSON,
+		Value: outputTable,
+	}
+)
+
+// readOnlyFlags returns the flags of subcommands that only read from L1, in addition to the given flags.
+func readOnlyFlags(cmdFlags ...cli.Flag) []cli.Flag {
+	f := append([]cli.Flag{flags.L1EthRpcFlag}, cmdFlags...)
+	f = append(f, dial.CLIFlags(flags.EnvVarPrefix)...)
+	return cliapp.ProtectFlags(append(f, oplog.CLIFlags(flags.EnvVarPrefix)...))
+}
+
+// txFlags returns the flags of subcommands that send transactions, in addition to the given flags.
+func txFlags(cmdFlags ...cli.Flag) []cli.Flag {
+	return readOnlyFlags(append(cmdFlags, txmgr.CLIFlagsWithDefaults(flags.EnvVarPrefix, txmgr.DefaultChallengerFlagValues)...)...)
+}
+
+func checkRequired(ctx *cli.Context, required ...cli.Flag) error {
+	for _, f := range required {
+		if !ctx.IsSet(f.Names()[0]) {
+			return fmt.Errorf("flag %s is required", f.Names()[0])
+		}
+	}
+	return nil
+}
+
+func parseAddress(ctx *cli.Context, f *cli.StringFlag) (common.Address, error) {
+	addr, err := opservice.ParseAddress(ctx.String(f.Name))
+	if err != nil {
+		return common.Address{}, fmt.Errorf("invalid %s: %w", f.Name, err)
+	}
+	return addr, nil
+}
+
+func parseHash(ctx *cli.Context, f *cli.StringFlag) (common.Hash, error) {
+	var h common.Hash
+	if err := h.UnmarshalText([]byte(ctx.String(f.Name))); err != nil {
+		return common.Hash{}, fmt.Errorf("invalid %s: %w", f.Name, err)
+	}
+	return h, nil
+}
+
+func checkOutputFormat(ctx *cli.Context) error {
+	switch format := ctx.String(OutputFlag.Name); format {
+	case outputTable, outputJSON:
+		return nil
+	default:
+		return fmt.Errorf("invalid %s: %q, must be %s or %s", OutputFlag.Name, format, outputTable, outputJSON)
+	}
+}
+
+// dialL1 sets up logging and dials the L1 RPC of a subcommand.
+func dialL1(ctx *cli.Context) (log.Logger, *dial.EthClient, error) {
+	logger, err := setupLogging(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	l1Cfg := dial.ReadCLIConfig(ctx)
+	if err := l1Cfg.Check(); err != nil {
+		return nil, nil, err
+	}
+	l1Client, err := dial.DialMultiEthClientWithTimeout(ctx.Context, dial.DefaultDialTimeout, logger, ctx.String(flags.L1EthRpcFlag.Name), l1Cfg.MultiRPCConfig(), nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to dial L1: %w", err)
+	}
+	return logger, l1Client, nil
+}
+
+func newTxManager(ctx *cli.Context, logger log.Logger) (txmgr.TxManager, error) {
+	txMgr, err := txmgr.NewSimpleTxManager("challenger", logger, &txmetrics.NoopTxMetrics{}, txmgr.ReadCLIConfig(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the transaction manager: %w", err)
+	}
+	return txMgr, nil
+}
+
+// sendTx sends the transaction and waits for its receipt. Unlike the responder, a reverted transaction is an error.
+func sendTx(ctx context.Context, txMgr txmgr.TxManager, to common.Address, txData []byte) (*ethtypes.Receipt, error) {
+	receipt, err := txMgr.Send(ctx, txmgr.TxCandidate{
+		To:     &to,
+		TxData: txData,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if receipt.Status == ethtypes.ReceiptStatusFailed {
+		return nil, fmt.Errorf("transaction %v reverted", receipt.TxHash)
+	}
+	return receipt, nil
+}
+
+// writeOutput writes v as JSON if the JSON output format is selected, or calls table with a tab-aligned writer otherwise.
+func writeOutput(ctx *cli.Context, v any, table func(w io.Writer) error) error {
+	out := ctx.App.Writer
+	if ctx.String(OutputFlag.Name) == outputJSON {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	if err := table(tw); err != nil {
+		return err
+	}
+	return tw.Flush()
+}
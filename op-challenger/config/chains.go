@@ -0,0 +1,119 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	ErrMissingChainName        = errors.New("missing chain name")
+	ErrDuplicateChainName      = errors.New("duplicate chain name")
+	ErrChainsAndFactoryAddress = errors.New("game factory address must be configured per chain when chains are configured")
+	ErrNoChains                = errors.New("no chains in chains config")
+)
+
+// DefaultChainName is the name of the chain configured by the top level options, when no chains are configured.
+const DefaultChainName = "default"
+
+// ChainConfig is the configuration of a single L2 chain, whose dispute games are played by the challenger.
+// The options that are not chain specific, like the L1 RPC, the transaction manager and the cannon executables,
+// are shared by all chains.
+type ChainConfig struct {
+	Name               string         `json:"name"`
+	GameFactoryAddress common.Address `json:"gameFactoryAddress"`
+
+	// Specific to the alphabet trace provider
+	AlphabetTrace string `json:"alphabet,omitempty"`
+
+	// Specific to the output cannon trace type
+	RollupRpc string `json:"rollupRpc,omitempty"`
+
+	// Specific to the cannon trace provider
+	CannonNetwork          string `json:"cannonNetwork,omitempty"`
+	CannonRollupConfigPath string `json:"cannonRollupConfig,omitempty"`
+	CannonL2GenesisPath    string `json:"cannonL2Genesis,omitempty"`
+	CannonAbsolutePreState string `json:"cannonPrestate,omitempty"`
+	CannonL2               string `json:"cannonL2,omitempty"`
+}
+
+// chainsFile is the format of the chains config file.
+type chainsFile struct {
+	Chains []ChainConfig `json:"chains"`
+}
+
+// LoadChainConfigs reads the chains from a JSON file of the form {"chains": [{"name": ..., ...}, ...]}.
+func LoadChainConfigs(path string) ([]ChainConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read chains config %v: %w", path, err)
+	}
+	var file chainsFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse chains config %v: %w", path, err)
+	}
+	if len(file.Chains) == 0 {
+		return nil, fmt.Errorf("%w: %v", ErrNoChains, path)
+	}
+	return file.Chains, nil
+}
+
+// ChainConfigs returns the configured chains, or the single chain configured by the top level options.
+func (c Config) ChainConfigs() []ChainConfig {
+	if len(c.Chains) > 0 {
+		return c.Chains
+	}
+	return []ChainConfig{{
+		Name:                   DefaultChainName,
+		GameFactoryAddress:     c.GameFactoryAddress,
+		AlphabetTrace:          c.AlphabetTrace,
+		RollupRpc:              c.RollupRpc,
+		CannonNetwork:          c.CannonNetwork,
+		CannonRollupConfigPath: c.CannonRollupConfigPath,
+		CannonL2GenesisPath:    c.CannonL2GenesisPath,
+		CannonAbsolutePreState: c.CannonAbsolutePreState,
+		CannonL2:               c.CannonL2,
+	}}
+}
+
+// ForChain returns a copy of the config with the chain specific options replaced by those of the chain,
+// for use by the components that play the games of that chain.
+func (c Config) ForChain(chain ChainConfig) Config {
+	c.Chains = nil
+	c.GameFactoryAddress = chain.GameFactoryAddress
+	c.AlphabetTrace = chain.AlphabetTrace
+	c.RollupRpc = chain.RollupRpc
+	c.CannonNetwork = chain.CannonNetwork
+	c.CannonRollupConfigPath = chain.CannonRollupConfigPath
+	c.CannonL2GenesisPath = chain.CannonL2GenesisPath
+	c.CannonAbsolutePreState = chain.CannonAbsolutePreState
+	c.CannonL2 = chain.CannonL2
+	return c
+}
+
+// checkChains checks the chain specific options of every chain.
+func (c Config) checkChains() error {
+	if len(c.Chains) == 0 {
+		return c.checkChain()
+	}
+	if c.GameFactoryAddress != (common.Address{}) {
+		return ErrChainsAndFactoryAddress
+	}
+	names := make(map[string]bool)
+	for i, chain := range c.Chains {
+		if chain.Name == "" {
+			return fmt.Errorf("chain %d: %w", i, ErrMissingChainName)
+		}
+		if names[chain.Name] {
+			return fmt.Errorf("%w: %v", ErrDuplicateChainName, chain.Name)
+		}
+		names[chain.Name] = true
+		if err := c.ForChain(chain).checkChain(); err != nil {
+			return fmt.Errorf("chain %v: %w", chain.Name, err)
+		}
+	}
+	return nil
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 
 	"github.com/ethereum-optimism/optimism/op-node/chaincfg"
+	"github.com/ethereum-optimism/optimism/op-service/dial"
 	opmetrics "github.com/ethereum-optimism/optimism/op-service/metrics"
 	oppprof "github.com/ethereum-optimism/optimism/op-service/pprof"
 	"github.com/ethereum-optimism/optimism/op-service/txmgr"
@@ -102,8 +103,10 @@ const (
 type Config struct {
 	L1EthRpc                string           // L1 RPC Url
 	GameFactoryAddress      common.Address   // Address of the dispute game factory
+	Chains                  []ChainConfig    // Chains to play games for, replacing the single chain options if set
 	GameAllowlist           []common.Address // Allowlist of fault game addresses
 	GameWindow              time.Duration    // Maximum time duration to look for games to progress
+	MaxSpendPerGame         uint64           // Maximum gwei to spend on transactions in a single game, 0 for no limit
 	AgreeWithProposedOutput bool             // Temporary config if we agree or disagree with the posted output
 	Datadir                 string           // Data Directory
 	MaxConcurrency          uint             // Maximum number of threads to use when progressing games
@@ -131,6 +134,7 @@ type Config struct {
 	TxMgrConfig   txmgr.CLIConfig
 	MetricsConfig opmetrics.CLIConfig
 	PprofConfig   oppprof.CLIConfig
+	L1RPCConfig   dial.CLIConfig
 }
 
 func NewConfig(
@@ -153,6 +157,7 @@ func NewConfig(
 		TxMgrConfig:   txmgr.NewCLIConfig(l1EthRpc, txmgr.DefaultChallengerFlagValues),
 		MetricsConfig: opmetrics.DefaultCLIConfig(),
 		PprofConfig:   oppprof.DefaultCLIConfig(),
+		L1RPCConfig:   dial.DefaultCLIConfig(),
 
 		Datadir: datadir,
 
@@ -170,9 +175,6 @@ func (c Config) Check() error {
 	if c.L1EthRpc == "" {
 		return ErrMissingL1EthRPC
 	}
-	if c.GameFactoryAddress == (common.Address{}) {
-		return ErrMissingGameFactoryAddress
-	}
 	if len(c.TraceTypes) == 0 {
 		return ErrMissingTraceType
 	}
@@ -182,11 +184,6 @@ func (c Config) Check() error {
 	if c.MaxConcurrency == 0 {
 		return ErrMaxConcurrencyZero
 	}
-	if c.TraceTypeEnabled(TraceTypeOutputCannon) {
-		if c.RollupRpc == "" {
-			return ErrMissingRollupRpc
-		}
-	}
 	if c.TraceTypeEnabled(TraceTypeCannon) || c.TraceTypeEnabled(TraceTypeOutputCannon) {
 		if c.CannonBin == "" {
 			return ErrMissingCannonBin
@@ -194,6 +191,42 @@ func (c Config) Check() error {
 		if c.CannonServer == "" {
 			return ErrMissingCannonServer
 		}
+		if c.CannonSnapshotFreq == 0 {
+			return ErrMissingCannonSnapshotFreq
+		}
+		if c.CannonInfoFreq == 0 {
+			return ErrMissingCannonInfoFreq
+		}
+	}
+	if err := c.checkChains(); err != nil {
+		return err
+	}
+	if err := c.TxMgrConfig.Check(); err != nil {
+		return err
+	}
+	if err := c.MetricsConfig.Check(); err != nil {
+		return err
+	}
+	if err := c.PprofConfig.Check(); err != nil {
+		return err
+	}
+	if err := c.L1RPCConfig.Check(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// checkChain checks the chain specific options.
+func (c Config) checkChain() error {
+	if c.GameFactoryAddress == (common.Address{}) {
+		return ErrMissingGameFactoryAddress
+	}
+	if c.TraceTypeEnabled(TraceTypeOutputCannon) {
+		if c.RollupRpc == "" {
+			return ErrMissingRollupRpc
+		}
+	}
+	if c.TraceTypeEnabled(TraceTypeCannon) || c.TraceTypeEnabled(TraceTypeOutputCannon) {
 		if c.CannonNetwork == "" {
 			if c.CannonRollupConfigPath == "" {
 				return ErrMissingCannonRollupConfig
@@ -218,24 +251,9 @@ func (c Config) Check() error {
 		if c.CannonL2 == "" {
 			return ErrMissingCannonL2
 		}
-		if c.CannonSnapshotFreq == 0 {
-			return ErrMissingCannonSnapshotFreq
-		}
-		if c.CannonInfoFreq == 0 {
-			return ErrMissingCannonInfoFreq
-		}
 	}
 	if c.TraceTypeEnabled(TraceTypeAlphabet) && c.AlphabetTrace == "" {
 		return ErrMissingAlphabetTrace
 	}
-	if err := c.TxMgrConfig.Check(); err != nil {
-		return err
-	}
-	if err := c.MetricsConfig.Check(); err != nil {
-		return err
-	}
-	if err := c.PprofConfig.Check(); err != nil {
-		return err
-	}
 	return nil
 }
@@ -1,6 +1,8 @@
 package config
 
 import (
+	"os"
+	"path/filepath"
 	"runtime"
 	"testing"
 
@@ -61,6 +63,14 @@ func TestTxMgrConfig(t *testing.T) {
 	})
 }
 
+func TestL1RPCConfig(t *testing.T) {
+	t.Run("Invalid", func(t *testing.T) {
+		config := validConfig(TraceTypeAlphabet)
+		config.L1RPCConfig.L1Quorum = -1
+		require.ErrorContains(t, config.Check(), "L1 quorum must not be negative")
+	})
+}
+
 func TestL1EthRpcRequired(t *testing.T) {
 	config := validConfig(TraceTypeCannon)
 	config.L1EthRpc = ""
@@ -217,3 +227,121 @@ func TestRequireConfigForAllSupportedTraceTypes(t *testing.T) {
 	cfg.AlphabetTrace = ""
 	require.ErrorIs(t, cfg.Check(), ErrMissingAlphabetTrace)
 }
+
+func validChainConfig(name string) ChainConfig {
+	return ChainConfig{
+		Name:                   name,
+		GameFactoryAddress:     validGameFactoryAddress,
+		RollupRpc:              validRollupRpc,
+		CannonNetwork:          validCannonNetwork,
+		CannonAbsolutePreState: validCannonAbsolutPreState,
+		CannonL2:               validCannonL2,
+	}
+}
+
+func TestChains(t *testing.T) {
+	multiChainConfig := func() Config {
+		cfg := validConfig(TraceTypeOutputCannon)
+		cfg.GameFactoryAddress = common.Address{}
+		cfg.RollupRpc = ""
+		cfg.CannonNetwork = ""
+		cfg.CannonAbsolutePreState = ""
+		cfg.CannonL2 = ""
+		cfg.Chains = []ChainConfig{validChainConfig("a"), validChainConfig("b")}
+		return cfg
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		require.NoError(t, multiChainConfig().Check())
+	})
+
+	t.Run("DefaultChain", func(t *testing.T) {
+		cfg := validConfig(TraceTypeOutputCannon)
+		chains := cfg.ChainConfigs()
+		require.Len(t, chains, 1)
+		require.Equal(t, DefaultChainName, chains[0].Name)
+		require.Equal(t, cfg, cfg.ForChain(chains[0]))
+	})
+
+	t.Run("ForChain", func(t *testing.T) {
+		cfg := multiChainConfig()
+		chain := cfg.Chains[1]
+		chain.CannonL2 = "http://other:9545"
+		chainCfg := cfg.ForChain(chain)
+		require.Nil(t, chainCfg.Chains)
+		require.Equal(t, validGameFactoryAddress, chainCfg.GameFactoryAddress)
+		require.Equal(t, "http://other:9545", chainCfg.CannonL2)
+		require.Equal(t, cfg.CannonBin, chainCfg.CannonBin)
+		require.NoError(t, chainCfg.Check())
+	})
+
+	t.Run("MustNotSpecifyTopLevelFactoryAddress", func(t *testing.T) {
+		cfg := multiChainConfig()
+		cfg.GameFactoryAddress = validGameFactoryAddress
+		require.ErrorIs(t, cfg.Check(), ErrChainsAndFactoryAddress)
+	})
+
+	t.Run("NameRequired", func(t *testing.T) {
+		cfg := multiChainConfig()
+		cfg.Chains[1].Name = ""
+		require.ErrorIs(t, cfg.Check(), ErrMissingChainName)
+	})
+
+	t.Run("NamesMustBeUnique", func(t *testing.T) {
+		cfg := multiChainConfig()
+		cfg.Chains[1].Name = cfg.Chains[0].Name
+		require.ErrorIs(t, cfg.Check(), ErrDuplicateChainName)
+	})
+
+	t.Run("CheckEachChain", func(t *testing.T) {
+		cfg := multiChainConfig()
+		cfg.Chains[1].GameFactoryAddress = common.Address{}
+		require.ErrorIs(t, cfg.Check(), ErrMissingGameFactoryAddress)
+
+		cfg = multiChainConfig()
+		cfg.Chains[0].CannonL2 = ""
+		require.ErrorIs(t, cfg.Check(), ErrMissingCannonL2)
+
+		cfg = multiChainConfig()
+		cfg.Chains[0].RollupRpc = ""
+		require.ErrorIs(t, cfg.Check(), ErrMissingRollupRpc)
+	})
+}
+
+func TestLoadChainConfigs(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		return path
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		path := write("valid.json", `{"chains": [
+			{"name": "a", "gameFactoryAddress": "0x2300000000000000000000000000000000000000", "rollupRpc": "http://localhost:8555",
+			 "cannonNetwork": "mainnet", "cannonPrestate": "pre.json", "cannonL2": "http://localhost:9545"},
+			{"name": "b", "gameFactoryAddress": "0x2400000000000000000000000000000000000000", "alphabet": "abcdefgh"}
+		]}`)
+		chains, err := LoadChainConfigs(path)
+		require.NoError(t, err)
+		require.Equal(t, []ChainConfig{
+			validChainConfig("a"),
+			{Name: "b", GameFactoryAddress: common.Address{0x24}, AlphabetTrace: validAlphabetTrace},
+		}, chains)
+	})
+
+	t.Run("NoChains", func(t *testing.T) {
+		_, err := LoadChainConfigs(write("empty.json", `{"chains": []}`))
+		require.ErrorIs(t, err, ErrNoChains)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := LoadChainConfigs(write("invalid.json", `{"chains": [`))
+		require.ErrorContains(t, err, "failed to parse chains config")
+	})
+
+	t.Run("Missing", func(t *testing.T) {
+		_, err := LoadChainConfigs(filepath.Join(dir, "missing.json"))
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
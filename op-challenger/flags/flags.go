@@ -12,6 +12,7 @@ import (
 	"github.com/ethereum-optimism/optimism/op-challenger/config"
 	"github.com/ethereum-optimism/optimism/op-node/chaincfg"
 	opservice "github.com/ethereum-optimism/optimism/op-service"
+	"github.com/ethereum-optimism/optimism/op-service/dial"
 	openum "github.com/ethereum-optimism/optimism/op-service/enum"
 	oplog "github.com/ethereum-optimism/optimism/op-service/log"
 	opmetrics "github.com/ethereum-optimism/optimism/op-service/metrics"
@@ -20,18 +21,18 @@ import (
 )
 
 const (
-	envVarPrefix = "OP_CHALLENGER"
+	EnvVarPrefix = "OP_CHALLENGER"
 )
 
 func prefixEnvVars(name string) []string {
-	return opservice.PrefixEnvVar(envVarPrefix, name)
+	return opservice.PrefixEnvVar(EnvVarPrefix, name)
 }
 
 var (
 	// Required Flags
 	L1EthRpcFlag = &cli.StringFlag{
 		Name:    "l1-eth-rpc",
-		Usage:   "HTTP provider URL for L1.",
+		Usage:   "HTTP provider URL for L1. Several comma-separated URLs can be listed, to fail over between.",
 		EnvVars: prefixEnvVars("L1_ETH_RPC"),
 	}
 	FactoryAddressFlag = &cli.StringFlag{
@@ -139,6 +140,19 @@ var (
 		EnvVars: prefixEnvVars("GAME_WINDOW"),
 		Value:   config.DefaultGameWindow,
 	}
+	ChainsConfigFlag = &cli.StringFlag{
+		Name: "chains-config",
+		Usage: "Path to a JSON file listing the L2 chains to play games for, each with its own name, game factory address, " +
+			"rollup rpc, alphabet and cannon network, rollup config, l2 genesis, prestate and l2 options. " +
+			"Replaces the single chain options.",
+		EnvVars: prefixEnvVars("CHAINS_CONFIG"),
+	}
+	MaxSpendPerGameFlag = &cli.Uint64Flag{
+		Name: "max-spend-per-game",
+		Usage: "Maximum amount in gwei to spend on transaction fees and bonds in a single game. " +
+			"Once reached, no further moves or steps are made in the game. 0 for no limit.",
+		EnvVars: prefixEnvVars("MAX_SPEND_PER_GAME"),
+	}
 )
 
 // requiredFlags are checked by [CheckRequired]
@@ -167,13 +181,28 @@ var optionalFlags = []cli.Flag{
 	CannonSnapshotFreqFlag,
 	CannonInfoFreqFlag,
 	GameWindowFlag,
+	ChainsConfigFlag,
+	MaxSpendPerGameFlag,
+}
+
+// chainFlags are the single chain options that are replaced by [ChainsConfigFlag]
+var chainFlags = []cli.Flag{
+	FactoryAddressFlag,
+	RollupRpcFlag,
+	AlphabetFlag,
+	CannonNetworkFlag,
+	CannonRollupConfigFlag,
+	CannonL2GenesisFlag,
+	CannonPreStateFlag,
+	CannonL2Flag,
 }
 
 func init() {
-	optionalFlags = append(optionalFlags, oplog.CLIFlags(envVarPrefix)...)
-	optionalFlags = append(optionalFlags, txmgr.CLIFlagsWithDefaults(envVarPrefix, txmgr.DefaultChallengerFlagValues)...)
-	optionalFlags = append(optionalFlags, opmetrics.CLIFlags(envVarPrefix)...)
-	optionalFlags = append(optionalFlags, oppprof.CLIFlags(envVarPrefix)...)
+	optionalFlags = append(optionalFlags, oplog.CLIFlags(EnvVarPrefix)...)
+	optionalFlags = append(optionalFlags, txmgr.CLIFlagsWithDefaults(EnvVarPrefix, txmgr.DefaultChallengerFlagValues)...)
+	optionalFlags = append(optionalFlags, opmetrics.CLIFlags(EnvVarPrefix)...)
+	optionalFlags = append(optionalFlags, oppprof.CLIFlags(EnvVarPrefix)...)
+	optionalFlags = append(optionalFlags, dial.CLIFlags(EnvVarPrefix)...)
 
 	Flags = append(requiredFlags, optionalFlags...)
 }
@@ -207,7 +236,36 @@ func CheckCannonFlags(ctx *cli.Context) error {
 	return nil
 }
 
+// CheckMultiChainFlags checks the flags when the chains are configured by [ChainsConfigFlag].
+// The chain specific options are checked by [config.Config.Check] once the chains are loaded.
+func CheckMultiChainFlags(ctx *cli.Context, traceTypes []config.TraceType) error {
+	for _, f := range requiredFlags {
+		if f != FactoryAddressFlag && !ctx.IsSet(f.Names()[0]) {
+			return fmt.Errorf("flag %s is required", f.Names()[0])
+		}
+	}
+	for _, f := range chainFlags {
+		if ctx.IsSet(f.Names()[0]) {
+			return fmt.Errorf("flag %v can not be used with %v", f.Names()[0], ChainsConfigFlag.Name)
+		}
+	}
+	for _, traceType := range traceTypes {
+		if traceType == config.TraceTypeCannon || traceType == config.TraceTypeOutputCannon {
+			if !ctx.IsSet(CannonBinFlag.Name) {
+				return fmt.Errorf("flag %s is required", CannonBinFlag.Name)
+			}
+			if !ctx.IsSet(CannonServerFlag.Name) {
+				return fmt.Errorf("flag %s is required", CannonServerFlag.Name)
+			}
+		}
+	}
+	return nil
+}
+
 func CheckRequired(ctx *cli.Context, traceTypes []config.TraceType) error {
+	if ctx.IsSet(ChainsConfigFlag.Name) {
+		return CheckMultiChainFlags(ctx, traceTypes)
+	}
 	for _, f := range requiredFlags {
 		if !ctx.IsSet(f.Names()[0]) {
 			return fmt.Errorf("flag %s is required", f.Names()[0])
@@ -260,9 +318,18 @@ func NewConfigFromCLI(ctx *cli.Context) (*config.Config, error) {
 	if err := CheckRequired(ctx, traceTypes); err != nil {
 		return nil, err
 	}
-	gameFactoryAddress, err := opservice.ParseAddress(ctx.String(FactoryAddressFlag.Name))
-	if err != nil {
-		return nil, err
+	var gameFactoryAddress common.Address
+	var chains []config.ChainConfig
+	if ctx.IsSet(ChainsConfigFlag.Name) {
+		chains, err = config.LoadChainConfigs(ctx.String(ChainsConfigFlag.Name))
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		gameFactoryAddress, err = opservice.ParseAddress(ctx.String(FactoryAddressFlag.Name))
+		if err != nil {
+			return nil, err
+		}
 	}
 	var allowedGames []common.Address
 	if ctx.StringSlice(GameAllowlistFlag.Name) != nil {
@@ -288,8 +355,10 @@ func NewConfigFromCLI(ctx *cli.Context) (*config.Config, error) {
 		L1EthRpc:                ctx.String(L1EthRpcFlag.Name),
 		TraceTypes:              traceTypes,
 		GameFactoryAddress:      gameFactoryAddress,
+		Chains:                  chains,
 		GameAllowlist:           allowedGames,
 		GameWindow:              ctx.Duration(GameWindowFlag.Name),
+		MaxSpendPerGame:         ctx.Uint64(MaxSpendPerGameFlag.Name),
 		MaxConcurrency:          maxConcurrency,
 		PollInterval:            ctx.Duration(HTTPPollInterval.Name),
 		RollupRpc:               ctx.String(RollupRpcFlag.Name),
@@ -308,5 +377,6 @@ func NewConfigFromCLI(ctx *cli.Context) (*config.Config, error) {
 		TxMgrConfig:             txMgrConfig,
 		MetricsConfig:           metricsConfig,
 		PprofConfig:             pprofConfig,
+		L1RPCConfig:             dial.ReadCLIConfig(ctx),
 	}, nil
 }
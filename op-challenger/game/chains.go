@@ -0,0 +1,54 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum-optimism/optimism/op-challenger/game/registry"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/scheduler"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/types"
+)
+
+var errUnknownChain = errors.New("unknown chain")
+
+// chainGameSource loads the games created by the dispute game factory of a single chain.
+type chainGameSource struct {
+	chain  string
+	source gameSource
+}
+
+// multiChainSource loads the games of all chains, so the games of every chain are scheduled together
+// and share the same worker pool.
+type multiChainSource []chainGameSource
+
+// FetchAllGamesAtBlock returns the games of all chains, each tagged with the name of its chain.
+// Fails if the games of any chain fail to load, as scheduling only the games of the other chains
+// would stop tracking the games of that chain and delete their data.
+func (s multiChainSource) FetchAllGamesAtBlock(ctx context.Context, earliest uint64, blockNumber *big.Int) ([]types.GameMetadata, error) {
+	var games []types.GameMetadata
+	for _, chain := range s {
+		chainGames, err := chain.source.FetchAllGamesAtBlock(ctx, earliest, blockNumber)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load games of chain %v: %w", chain.chain, err)
+		}
+		for _, game := range chainGames {
+			game.Chain = chain.chain
+			games = append(games, game)
+		}
+	}
+	return games, nil
+}
+
+// chainRegistries holds the game type registry of each chain, by chain name.
+type chainRegistries map[string]*registry.GameTypeRegistry
+
+// CreatePlayer creates a player for the game using the registry of the chain the game belongs to.
+func (r chainRegistries) CreatePlayer(game types.GameMetadata, dir string) (scheduler.GamePlayer, error) {
+	chainRegistry, ok := r[game.Chain]
+	if !ok {
+		return nil, fmt.Errorf("%w: %v", errUnknownChain, game.Chain)
+	}
+	return chainRegistry.CreatePlayer(game, dir)
+}
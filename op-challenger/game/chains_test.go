@@ -0,0 +1,87 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-challenger/game/registry"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/scheduler"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/scheduler/test"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/types"
+)
+
+func TestMultiChainSource(t *testing.T) {
+	ctx := context.Background()
+	gameA := newFDG(common.Address{0xaa}, 9999)
+	gameB1 := newFDG(common.Address{0xb1}, 9999)
+	gameB2 := newFDG(common.Address{0xb2}, 9999)
+
+	t.Run("TagGamesWithChain", func(t *testing.T) {
+		source := multiChainSource{
+			{chain: "a", source: &stubGameSource{games: []types.GameMetadata{gameA}}},
+			{chain: "b", source: &stubGameSource{games: []types.GameMetadata{gameB1, gameB2}}},
+			{chain: "c", source: &stubGameSource{}},
+		}
+		games, err := source.FetchAllGamesAtBlock(ctx, 0, big.NewInt(1))
+		require.NoError(t, err)
+		expectedA := gameA
+		expectedA.Chain = "a"
+		expectedB1 := gameB1
+		expectedB1.Chain = "b"
+		expectedB2 := gameB2
+		expectedB2.Chain = "b"
+		require.Equal(t, []types.GameMetadata{expectedA, expectedB1, expectedB2}, games)
+	})
+
+	t.Run("FailIfAnyChainFails", func(t *testing.T) {
+		err := errors.New("boom")
+		source := multiChainSource{
+			{chain: "a", source: &stubGameSource{games: []types.GameMetadata{gameA}}},
+			{chain: "b", source: &erroringGameSource{err: err}},
+		}
+		games, fetchErr := source.FetchAllGamesAtBlock(ctx, 0, big.NewInt(1))
+		require.ErrorIs(t, fetchErr, err)
+		require.Nil(t, games)
+	})
+}
+
+func TestChainRegistries(t *testing.T) {
+	playerA := &test.StubGamePlayer{}
+	playerB := &test.StubGamePlayer{}
+	registries := make(chainRegistries)
+	for chain, player := range map[string]*test.StubGamePlayer{"a": playerA, "b": playerB} {
+		player := player
+		chainRegistry := registry.NewGameTypeRegistry()
+		chainRegistry.RegisterGameType(0, func(game types.GameMetadata, dir string) (scheduler.GamePlayer, error) {
+			return player, nil
+		})
+		registries[chain] = chainRegistry
+	}
+
+	player, err := registries.CreatePlayer(types.GameMetadata{Chain: "b"}, "")
+	require.NoError(t, err)
+	require.Same(t, playerB, player)
+
+	player, err = registries.CreatePlayer(types.GameMetadata{Chain: "a"}, "")
+	require.NoError(t, err)
+	require.Same(t, playerA, player)
+
+	_, err = registries.CreatePlayer(types.GameMetadata{Chain: "c"}, "")
+	require.ErrorIs(t, err, errUnknownChain)
+
+	_, err = registries.CreatePlayer(types.GameMetadata{Chain: "a", GameType: 1}, "")
+	require.ErrorIs(t, err, registry.ErrUnsupportedGameType)
+}
+
+type erroringGameSource struct {
+	err error
+}
+
+func (s *erroringGameSource) FetchAllGamesAtBlock(ctx context.Context, earliest uint64, blockNumber *big.Int) ([]types.GameMetadata, error) {
+	return nil, s.err
+}
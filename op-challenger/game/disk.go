@@ -13,13 +13,20 @@ import (
 
 const gameDirPrefix = "game-"
 
+// sharedData is data stored outside the game directories that is shared by multiple games.
+type sharedData interface {
+	// RemoveUnreferenced removes the shared data that is not used by any of the games in gameDirs.
+	RemoveUnreferenced(gameDirs []string) error
+}
+
 // diskManager coordinates the storage of game data on disk.
 type diskManager struct {
 	datadir string
+	shared  sharedData
 }
 
-func newDiskManager(dir string) *diskManager {
-	return &diskManager{datadir: dir}
+func newDiskManager(dir string, shared sharedData) *diskManager {
+	return &diskManager{datadir: dir, shared: shared}
 }
 
 func (d *diskManager) DirForGame(addr common.Address) string {
@@ -51,5 +58,10 @@ func (d *diskManager) RemoveAllExcept(keep []common.Address) error {
 		}
 		errs = append(errs, os.RemoveAll(filepath.Join(d.datadir, entry.Name())))
 	}
+	keepDirs := make([]string, 0, len(keep))
+	for _, addr := range keep {
+		keepDirs = append(keepDirs, d.DirForGame(addr))
+	}
+	errs = append(errs, d.shared.RemoveUnreferenced(keepDirs))
 	return errors.Join(errs...)
 }
@@ -7,12 +7,14 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/trace/cannon"
 )
 
 func TestDiskManager_DirForGame(t *testing.T) {
 	baseDir := t.TempDir()
 	addr := common.Address{0x53}
-	disk := newDiskManager(baseDir)
+	disk := newDiskManager(baseDir, cannon.NewTraceCache(baseDir))
 	result := disk.DirForGame(addr)
 	require.Equal(t, filepath.Join(baseDir, gameDirPrefix+addr.Hex()), result)
 }
@@ -21,7 +23,7 @@ func TestDiskManager_RemoveAllExcept(t *testing.T) {
 	baseDir := t.TempDir()
 	keep := common.Address{0x53}
 	delete := common.Address{0xaa}
-	disk := newDiskManager(baseDir)
+	disk := newDiskManager(baseDir, cannon.NewTraceCache(baseDir))
 	keepDir := disk.DirForGame(keep)
 	deleteDir := disk.DirForGame(delete)
 
@@ -55,3 +57,25 @@ func TestDiskManager_RemoveAllExcept(t *testing.T) {
 	require.DirExists(t, unexpectedDir, "should not delete unexpected dir")
 	require.DirExists(t, invalidHexDir, "should not delete dir with invalid address")
 }
+
+func TestDiskManager_RemoveUnusedSharedTraces(t *testing.T) {
+	baseDir := t.TempDir()
+	traceCache := cannon.NewTraceCache(baseDir)
+	disk := newDiskManager(baseDir, traceCache)
+	keep := common.Address{0x53}
+	delete := common.Address{0xaa}
+	deleteOther := common.Address{0xbb}
+
+	sharedDir, _, err := traceCache.TraceDir(disk.DirForGame(keep), common.Hash{0x01})
+	require.NoError(t, err)
+	_, _, err = traceCache.TraceDir(disk.DirForGame(delete), common.Hash{0x01})
+	require.NoError(t, err)
+	unusedDir, _, err := traceCache.TraceDir(disk.DirForGame(deleteOther), common.Hash{0x02})
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(sharedDir, 0777))
+	require.NoError(t, os.MkdirAll(unusedDir, 0777))
+
+	require.NoError(t, disk.RemoveAllExcept([]common.Address{keep}))
+	require.DirExists(t, sharedDir, "should keep trace used by remaining game")
+	require.NoDirExists(t, unusedDir, "should delete trace no longer used by any game")
+}
@@ -24,6 +24,12 @@ type Responder interface {
 	PerformAction(ctx context.Context, action types.Action) error
 }
 
+// ActionPolicy selects which of the actions calculated by the solver to perform, see [policy.Policy].
+type ActionPolicy interface {
+	Select(ctx context.Context, game types.Game, actions []types.Action) []types.Action
+	WithinBudget() bool
+}
+
 type ClaimLoader interface {
 	FetchClaims(ctx context.Context) ([]types.Claim, error)
 }
@@ -31,6 +37,7 @@ type ClaimLoader interface {
 type Agent struct {
 	metrics                 metrics.Metricer
 	solver                  *solver.GameSolver
+	policy                  ActionPolicy
 	loader                  ClaimLoader
 	responder               Responder
 	updater                 types.OracleUpdater
@@ -39,10 +46,11 @@ type Agent struct {
 	log                     log.Logger
 }
 
-func NewAgent(m metrics.Metricer, loader ClaimLoader, maxDepth int, trace types.TraceProvider, responder Responder, updater types.OracleUpdater, agreeWithProposedOutput bool, log log.Logger) *Agent {
+func NewAgent(m metrics.Metricer, loader ClaimLoader, maxDepth int, trace types.TraceProvider, policy ActionPolicy, responder Responder, updater types.OracleUpdater, agreeWithProposedOutput bool, log log.Logger) *Agent {
 	return &Agent{
 		metrics:                 m,
 		solver:                  solver.NewGameSolver(maxDepth, trace),
+		policy:                  policy,
 		loader:                  loader,
 		responder:               responder,
 		updater:                 updater,
@@ -67,9 +75,14 @@ func (a *Agent) Act(ctx context.Context) error {
 	if err != nil {
 		log.Error("Failed to calculate all required moves", "err", err)
 	}
+	actions = a.policy.Select(ctx, game, actions)
 
 	// Perform the actions
-	for _, action := range actions {
+	for i, action := range actions {
+		if !a.policy.WithinBudget() {
+			a.log.Warn("Maximum spend for the game reached, skipping actions", "skipped", len(actions)-i)
+			break
+		}
 		log := a.log.New("action", action.Type, "is_attack", action.IsAttack, "parent", action.ParentIdx)
 		if action.Type == types.ActionTypeStep {
 			log = log.New("prestate", common.Bytes2Hex(action.PreState), "proof", common.Bytes2Hex(action.ProofData))
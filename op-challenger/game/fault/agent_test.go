@@ -108,6 +108,39 @@ func TestLoadClaimsWhenGameNotResolvable(t *testing.T) {
 	require.Zero(t, responder.resolveClaimCount, "should not send resolveClaim")
 }
 
+func TestApplyActionPolicy(t *testing.T) {
+	depth := 4
+	claimBuilder := test.NewClaimBuilder(t, depth, alphabet.NewTraceProvider("abcd", uint64(depth)))
+	rootClaim := claimBuilder.CreateRootClaim(false)
+
+	tests := []struct {
+		name            string
+		overBudget      bool
+		dropAll         bool
+		expectedActions int
+	}{
+		{name: "PerformSelected", expectedActions: 1},
+		{name: "SkipWhenNoneSelected", dropAll: true},
+		{name: "SkipWhenOverBudget", overBudget: true},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			// The agent agrees with the proposed output, so it attacks the incorrect root claim
+			agent, claimLoader, responder := setupTestAgent(t, true)
+			responder.callResolveErr = errors.New("game is not resolvable")
+			responder.callResolveClaimErr = errors.New("claim is not resolvable")
+			policy := &stubPolicy{overBudget: test.overBudget, dropAll: test.dropAll}
+			agent.policy = policy
+			claimLoader.claims = []types.Claim{rootClaim}
+
+			require.NoError(t, agent.Act(context.Background()))
+			require.Equal(t, 1, policy.candidates, "solver should offer an action to the policy")
+			require.Equal(t, test.expectedActions, responder.performActionCount)
+		})
+	}
+}
+
 func setupTestAgent(t *testing.T, agreeWithProposedOutput bool) (*Agent, *stubClaimLoader, *stubResponder) {
 	logger := testlog.Logger(t, log.LvlInfo)
 	claimLoader := &stubClaimLoader{}
@@ -115,10 +148,29 @@ func setupTestAgent(t *testing.T, agreeWithProposedOutput bool) (*Agent, *stubCl
 	trace := alphabet.NewTraceProvider("abcd", uint64(depth))
 	responder := &stubResponder{}
 	updater := &stubUpdater{}
-	agent := NewAgent(metrics.NoopMetrics, claimLoader, depth, trace, responder, updater, agreeWithProposedOutput, logger)
+	agent := NewAgent(metrics.NoopMetrics, claimLoader, depth, trace, &stubPolicy{}, responder, updater, agreeWithProposedOutput, logger)
 	return agent, claimLoader, responder
 }
 
+type stubPolicy struct {
+	overBudget bool
+	dropAll    bool
+
+	candidates int
+}
+
+func (s *stubPolicy) Select(ctx context.Context, game types.Game, actions []types.Action) []types.Action {
+	s.candidates += len(actions)
+	if s.dropAll {
+		return nil
+	}
+	return actions
+}
+
+func (s *stubPolicy) WithinBudget() bool {
+	return !s.overBudget
+}
+
 type stubClaimLoader struct {
 	callCount int
 	claims    []types.Claim
@@ -140,6 +192,8 @@ type stubResponder struct {
 	callResolveClaimCount int
 	callResolveClaimErr   error
 	resolveClaimCount     int
+
+	performActionCount int
 }
 
 func (s *stubResponder) CallResolve(ctx context.Context) (gameTypes.GameStatus, error) {
@@ -163,6 +217,7 @@ func (s *stubResponder) ResolveClaim(ctx context.Context, clainIdx uint64) error
 }
 
 func (s *stubResponder) PerformAction(ctx context.Context, response types.Action) error {
+	s.performActionCount++
 	return nil
 }
 
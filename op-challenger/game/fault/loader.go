@@ -25,6 +25,7 @@ type MinimalFaultDisputeGameCaller interface {
 	Status(opts *bind.CallOpts) (uint8, error)
 	ClaimDataLen(opts *bind.CallOpts) (*big.Int, error)
 	MAXGAMEDEPTH(opts *bind.CallOpts) (*big.Int, error)
+	GAMEDURATION(opts *bind.CallOpts) (uint64, error)
 	ABSOLUTEPRESTATE(opts *bind.CallOpts) ([32]byte, error)
 }
 
@@ -78,6 +79,15 @@ func (l *loader) FetchGameDepth(ctx context.Context) (uint64, error) {
 	return gameDepth.Uint64(), nil
 }
 
+// FetchGameDuration fetches the duration of the game in seconds, which is split evenly between the two teams.
+func (l *loader) FetchGameDuration(ctx context.Context) (uint64, error) {
+	callOpts := bind.CallOpts{
+		Context: ctx,
+	}
+
+	return l.caller.GAMEDURATION(&callOpts)
+}
+
 // fetchClaim fetches a single [Claim] with a hydrated parent.
 func (l *loader) fetchClaim(ctx context.Context, arrIndex uint64) (types.Claim, error) {
 	callOpts := bind.CallOpts{
@@ -95,7 +105,7 @@ func (l *loader) fetchClaim(ctx context.Context, arrIndex uint64) (types.Claim,
 			Position: types.NewPositionFromGIndex(fetchedClaim.Position),
 		},
 		Countered:           fetchedClaim.Countered,
-		Clock:               fetchedClaim.Clock.Uint64(),
+		Clock:               types.NewClockFromPacked(fetchedClaim.Clock),
 		ContractIndex:       int(arrIndex),
 		ParentContractIndex: int(fetchedClaim.ParentIndex),
 	}
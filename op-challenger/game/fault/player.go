@@ -4,17 +4,22 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"math/big"
 
 	"github.com/ethereum-optimism/optimism/op-bindings/bindings"
 	"github.com/ethereum-optimism/optimism/op-challenger/config"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/policy"
 	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/responder"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/store"
 	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
 	gameTypes "github.com/ethereum-optimism/optimism/op-challenger/game/types"
 	"github.com/ethereum-optimism/optimism/op-challenger/metrics"
+	"github.com/ethereum-optimism/optimism/op-service/clock"
 	"github.com/ethereum-optimism/optimism/op-service/txmgr"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
 )
 
 type actor func(ctx context.Context) error
@@ -32,7 +37,7 @@ type GamePlayer struct {
 	status                  gameTypes.GameStatus
 }
 
-type resourceCreator func(addr common.Address, gameDepth uint64, dir string) (types.TraceProvider, types.OracleUpdater, error)
+type resourceCreator func(addr common.Address, gameDepth uint64, dir string, txMgr txmgr.TxManager) (types.TraceProvider, types.OracleUpdater, error)
 
 func NewGamePlayer(
 	ctx context.Context,
@@ -77,22 +82,38 @@ func NewGamePlayer(
 		return nil, fmt.Errorf("failed to fetch the game depth: %w", err)
 	}
 
-	provider, updater, err := creator(addr, gameDepth, dir)
+	gameDuration, err := loader.FetchGameDuration(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch the game duration: %w", err)
+	}
+
+	gameStore, err := store.Open(dir, clock.SystemClock, store.DefaultPendingExpiry)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the game state: %w", err)
+	}
+	// Transactions for the game, including preimage uploads, count towards the maximum spend.
+	txMgr = policy.NewSpendTrackingTxManager(logger, txMgr, gameStore)
+
+	provider, updater, err := creator(addr, gameDepth, dir, txMgr)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create trace provider: %w", err)
 	}
 
-	if err := ValidateAbsolutePrestate(ctx, provider, loader); err != nil {
+	if err := validateAbsolutePrestateOnce(ctx, logger, provider, loader, gameStore); err != nil {
 		return nil, fmt.Errorf("failed to validate absolute prestate: %w", err)
 	}
+	provider = store.NewRecordingTraceProvider(logger, provider, gameStore)
 
-	responder, err := responder.NewFaultResponder(logger, txMgr, addr)
+	faultResponder, err := responder.NewFaultResponder(logger, txMgr, addr)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create the responder: %w", err)
 	}
+	recordingResponder := store.NewRecordingResponder(logger, faultResponder, gameStore)
+	maxSpend := new(big.Int).Mul(new(big.Int).SetUint64(cfg.MaxSpendPerGame), big.NewInt(params.GWei))
+	actionPolicy := policy.NewPolicy(logger, provider, clock.SystemClock, gameDuration, maxSpend, gameStore)
 
 	return &GamePlayer{
-		act:                     NewAgent(m, loader, int(gameDepth), provider, responder, updater, cfg.AgreeWithProposedOutput, logger).Act,
+		act:                     NewAgent(m, loader, int(gameDepth), provider, actionPolicy, recordingResponder, updater, cfg.AgreeWithProposedOutput, logger).Act,
 		agreeWithProposedOutput: cfg.AgreeWithProposedOutput,
 		loader:                  loader,
 		logger:                  logger,
@@ -147,6 +168,26 @@ func (g *GamePlayer) logGameStatus(ctx context.Context, status gameTypes.GameSta
 	}
 }
 
+// validateAbsolutePrestateOnce validates the absolute prestate, unless the onchain absolute prestate
+// was already validated before, e.g. prior to a restart.
+func validateAbsolutePrestateOnce(ctx context.Context, logger log.Logger, trace types.TraceProvider, loader PrestateLoader, gameStore *store.Store) error {
+	onchainPrestate, err := loader.FetchAbsolutePrestateHash(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get the onchain absolute prestate: %w", err)
+	}
+	if validated := gameStore.ValidatedPrestate(); validated != (common.Hash{}) && validated == onchainPrestate {
+		logger.Debug("Absolute prestate already validated", "prestate", onchainPrestate)
+		return nil
+	}
+	if err := ValidateAbsolutePrestate(ctx, trace, loader); err != nil {
+		return err
+	}
+	if err := gameStore.SetValidatedPrestate(onchainPrestate); err != nil {
+		logger.Warn("Failed to record validated absolute prestate", "err", err)
+	}
+	return nil
+}
+
 type PrestateLoader interface {
 	FetchAbsolutePrestateHash(ctx context.Context) (common.Hash, error)
 }
@@ -0,0 +1,131 @@
+package policy
+
+import (
+	"context"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
+	"github.com/ethereum-optimism/optimism/op-service/clock"
+)
+
+// SpendTracker tracks the amount of wei spent on transactions for a game.
+type SpendTracker interface {
+	Spent() *big.Int
+	AddSpent(cost *big.Int) error
+}
+
+// Policy decides which of the actions calculated by the [solver.GameSolver] are worth performing, and in which order.
+// The solver only considers the correctness of the game tree, while the policy also considers the cost of acting:
+//   - Moves against claims that were already countered by another honest actor are skipped.
+//   - Moves against claims whose clock has expired are skipped, as the contract would reject them.
+//   - Actions are ordered by the time remaining on the clock, so the most urgent claims are countered first.
+//   - Once the maximum spend for the game is reached, no further actions are performed.
+type Policy struct {
+	logger       log.Logger
+	trace        types.TraceProvider
+	clock        clock.Clock
+	gameDuration uint64
+	maxSpend     *big.Int
+	spent        SpendTracker
+}
+
+// NewPolicy creates a new [Policy] for a game.
+// The gameDuration is the total duration of the game in seconds, which is split evenly between the two teams.
+// A nil or zero maxSpend disables the spending limit.
+func NewPolicy(logger log.Logger, trace types.TraceProvider, cl clock.Clock, gameDuration uint64, maxSpend *big.Int, spent SpendTracker) *Policy {
+	return &Policy{
+		logger:       logger,
+		trace:        trace,
+		clock:        cl,
+		gameDuration: gameDuration,
+		maxSpend:     maxSpend,
+		spent:        spent,
+	}
+}
+
+// Select returns the actions to perform, ordered by urgency.
+func (p *Policy) Select(ctx context.Context, game types.Game, actions []types.Action) []types.Action {
+	type candidate struct {
+		action    types.Action
+		remaining int64
+	}
+	now := uint64(p.clock.Now().Unix())
+	var candidates []candidate
+	for _, action := range actions {
+		log := p.logger.New("action", action.Type, "is_attack", action.IsAttack, "parent", action.ParentIdx)
+		if action.ParentIdx < 0 || action.ParentIdx >= len(game.Claims()) {
+			// Let the responder report the invalid action.
+			candidates = append(candidates, candidate{action: action})
+			continue
+		}
+		remaining := p.remainingTime(game, action.ParentIdx, now)
+		if action.Type == types.ActionTypeMove {
+			if remaining <= 0 {
+				log.Warn("Skipping move, clock has expired", "overdue", -remaining)
+				continue
+			}
+			countered, err := p.counteredByHonestClaim(ctx, game, action.ParentIdx)
+			if err != nil {
+				log.Warn("Failed to check for existing honest counter claims", "err", err)
+			} else if countered {
+				log.Info("Skipping move, claim already countered by an honest claim")
+				continue
+			}
+		}
+		candidates = append(candidates, candidate{action: action, remaining: remaining})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].remaining < candidates[j].remaining
+	})
+	selected := make([]types.Action, len(candidates))
+	for i, c := range candidates {
+		selected[i] = c.action
+	}
+	return selected
+}
+
+// WithinBudget returns true if less than the maximum spend for the game has been spent so far.
+func (p *Policy) WithinBudget() bool {
+	if p.maxSpend == nil || p.maxSpend.Sign() == 0 {
+		return true
+	}
+	return p.spent.Spent().Cmp(p.maxSpend) < 0
+}
+
+// remainingTime returns the seconds left to counter the claim at the index, negative if the clock has already expired.
+// Countering a claim uses the clock of the claim's grandparent, which belongs to the countering team,
+// plus the time elapsed since the claim was made.
+func (p *Policy) remainingTime(game types.Game, claimIdx int, now uint64) int64 {
+	claim := game.Claims()[claimIdx]
+	var used uint64
+	if !claim.IsRoot() {
+		if parent, err := game.GetParent(claim); err == nil {
+			used = parent.Clock.Duration
+		}
+	}
+	if now > claim.Clock.Timestamp {
+		used += now - claim.Clock.Timestamp
+	}
+	return int64(p.gameDuration/2) - int64(used)
+}
+
+// counteredByHonestClaim returns true if the claim at the index already has a counter claim
+// that matches the trace, e.g. posted by another honest challenger.
+func (p *Policy) counteredByHonestClaim(ctx context.Context, game types.Game, claimIdx int) (bool, error) {
+	for _, claim := range game.Claims() {
+		if claim.IsRoot() || claim.ParentContractIndex != claimIdx {
+			continue
+		}
+		expected, err := p.trace.Get(ctx, claim.Position)
+		if err != nil {
+			return false, err
+		}
+		if expected == claim.Value {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,134 @@
+package policy
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/test"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
+	"github.com/ethereum-optimism/optimism/op-service/clock"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+)
+
+const (
+	maxDepth     = 4
+	gameDuration = 1000
+)
+
+func moveAgainst(claimIdx int) types.Action {
+	return types.Action{Type: types.ActionTypeMove, ParentIdx: claimIdx, IsAttack: true, Value: common.Hash{0xaa}}
+}
+
+func stepAgainst(claimIdx int) types.Action {
+	return types.Action{Type: types.ActionTypeStep, ParentIdx: claimIdx, IsAttack: true}
+}
+
+// setupGame creates a game with an incorrect root claim made at 1000, countered by two incorrect claims made at 1100 and 1050.
+func setupGame(t *testing.T) (*test.ClaimBuilder, []types.Claim) {
+	claimBuilder := test.NewAlphabetClaimBuilder(t, maxDepth)
+	root := claimBuilder.CreateRootClaim(false)
+	root.Clock = types.Clock{Timestamp: 1000}
+	first := claimBuilder.AttackClaim(root, false)
+	first.ContractIndex = 1
+	first.Clock = types.Clock{Duration: 100, Timestamp: 1100}
+	second := claimBuilder.AttackClaimWithValue(root, common.Hash{0xbb})
+	second.ContractIndex = 2
+	second.Clock = types.Clock{Duration: 50, Timestamp: 1050}
+	return claimBuilder, []types.Claim{root, first, second}
+}
+
+func newTestPolicy(t *testing.T, claimBuilder *test.ClaimBuilder, now int64, maxSpend *big.Int, spent SpendTracker) *Policy {
+	logger := testlog.Logger(t, log.LvlInfo)
+	cl := clock.NewDeterministicClock(time.Unix(now, 0))
+	return NewPolicy(logger, claimBuilder.CorrectTraceProvider(), cl, gameDuration, maxSpend, spent)
+}
+
+func TestSelect(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("OrderByRemainingTime", func(t *testing.T) {
+		claimBuilder, claims := setupGame(t)
+		game := types.NewGameState(true, claims, maxDepth)
+		policy := newTestPolicy(t, claimBuilder, 1200, nil, nil)
+		// Remaining: root 500-200=300, first 500-(0+100)=400, second 500-(0+150)=350
+		actions := policy.Select(ctx, game, []types.Action{moveAgainst(1), moveAgainst(2), moveAgainst(0)})
+		require.Equal(t, []types.Action{moveAgainst(0), moveAgainst(2), moveAgainst(1)}, actions)
+	})
+
+	t.Run("SkipMovesWithExpiredClock", func(t *testing.T) {
+		claimBuilder, claims := setupGame(t)
+		game := types.NewGameState(true, claims, maxDepth)
+		policy := newTestPolicy(t, claimBuilder, 1500, nil, nil)
+		actions := policy.Select(ctx, game, []types.Action{moveAgainst(0), moveAgainst(1), stepAgainst(0)})
+		require.Equal(t, []types.Action{stepAgainst(0), moveAgainst(1)}, actions)
+	})
+
+	t.Run("SkipClaimsCounteredByHonestClaim", func(t *testing.T) {
+		claimBuilder, claims := setupGame(t)
+		honest := claimBuilder.AttackClaim(claims[1], true)
+		honest.ContractIndex = 3
+		honest.Clock = types.Clock{Timestamp: 1150}
+		dishonest := claimBuilder.AttackClaim(claims[2], false)
+		dishonest.ContractIndex = 4
+		dishonest.Clock = types.Clock{Timestamp: 1150}
+		game := types.NewGameState(true, append(claims, honest, dishonest), maxDepth)
+		policy := newTestPolicy(t, claimBuilder, 1200, nil, nil)
+		actions := policy.Select(ctx, game, []types.Action{moveAgainst(1), moveAgainst(2)})
+		require.Equal(t, []types.Action{moveAgainst(2)}, actions)
+	})
+
+	t.Run("KeepInvalidParent", func(t *testing.T) {
+		claimBuilder, claims := setupGame(t)
+		game := types.NewGameState(true, claims, maxDepth)
+		policy := newTestPolicy(t, claimBuilder, 1200, nil, nil)
+		actions := policy.Select(ctx, game, []types.Action{moveAgainst(5)})
+		require.Equal(t, []types.Action{moveAgainst(5)}, actions)
+	})
+}
+
+func TestWithinBudget(t *testing.T) {
+	claimBuilder, _ := setupGame(t)
+	tests := []struct {
+		name     string
+		maxSpend *big.Int
+		spent    int64
+		expected bool
+	}{
+		{name: "NoLimit", maxSpend: nil, spent: 1000, expected: true},
+		{name: "ZeroLimit", maxSpend: big.NewInt(0), spent: 1000, expected: true},
+		{name: "BelowLimit", maxSpend: big.NewInt(100), spent: 99, expected: true},
+		{name: "AtLimit", maxSpend: big.NewInt(100), spent: 100, expected: false},
+		{name: "AboveLimit", maxSpend: big.NewInt(100), spent: 101, expected: false},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			spent := &stubSpendTracker{spent: big.NewInt(test.spent)}
+			policy := newTestPolicy(t, claimBuilder, 1200, test.maxSpend, spent)
+			require.Equal(t, test.expected, policy.WithinBudget())
+		})
+	}
+}
+
+type stubSpendTracker struct {
+	spent *big.Int
+	err   error
+}
+
+func (s *stubSpendTracker) Spent() *big.Int {
+	return s.spent
+}
+
+func (s *stubSpendTracker) AddSpent(cost *big.Int) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.spent = new(big.Int).Add(s.spent, cost)
+	return nil
+}
@@ -0,0 +1,55 @@
+package policy
+
+import (
+	"context"
+	"math/big"
+
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/ethereum-optimism/optimism/op-service/txmgr"
+)
+
+// SpendTrackingTxManager is a [txmgr.TxManager] that adds the cost of every included transaction,
+// the fees and the value sent (e.g. a bond), to the [SpendTracker] of a game.
+type SpendTrackingTxManager struct {
+	txmgr.TxManager
+	logger log.Logger
+	spent  SpendTracker
+}
+
+func NewSpendTrackingTxManager(logger log.Logger, txMgr txmgr.TxManager, spent SpendTracker) *SpendTrackingTxManager {
+	return &SpendTrackingTxManager{
+		TxManager: txMgr,
+		logger:    logger,
+		spent:     spent,
+	}
+}
+
+func (m *SpendTrackingTxManager) Send(ctx context.Context, candidate txmgr.TxCandidate) (*ethtypes.Receipt, error) {
+	receipt, err := m.TxManager.Send(ctx, candidate)
+	if err != nil {
+		return nil, err
+	}
+	cost := txCost(receipt, candidate.Value)
+	if err := m.spent.AddSpent(cost); err != nil {
+		m.logger.Warn("Failed to record transaction cost", "tx_hash", receipt.TxHash, "cost", cost, "err", err)
+	}
+	return receipt, nil
+}
+
+// txCost returns the fees paid for the transaction, plus the value if the transaction succeeded.
+func txCost(receipt *ethtypes.Receipt, value *big.Int) *big.Int {
+	cost := new(big.Int)
+	if receipt.EffectiveGasPrice != nil {
+		cost.Mul(new(big.Int).SetUint64(receipt.GasUsed), receipt.EffectiveGasPrice)
+	}
+	if receipt.BlobGasPrice != nil {
+		blobFee := new(big.Int).SetUint64(receipt.BlobGasUsed)
+		cost.Add(cost, blobFee.Mul(blobFee, receipt.BlobGasPrice))
+	}
+	if value != nil && receipt.Status == ethtypes.ReceiptStatusSuccessful {
+		cost.Add(cost, value)
+	}
+	return cost
+}
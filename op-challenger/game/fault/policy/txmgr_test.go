@@ -0,0 +1,77 @@
+package policy
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+	"github.com/ethereum-optimism/optimism/op-service/txmgr"
+)
+
+func TestSpendTrackingTxManager(t *testing.T) {
+	ctx := context.Background()
+	logger := testlog.Logger(t, log.LvlInfo)
+
+	t.Run("RecordFeesAndValue", func(t *testing.T) {
+		spent := &stubSpendTracker{spent: big.NewInt(5)}
+		inner := &stubTxManager{receipt: &ethtypes.Receipt{
+			Status:            ethtypes.ReceiptStatusSuccessful,
+			GasUsed:           100,
+			EffectiveGasPrice: big.NewInt(3),
+		}}
+		txMgr := NewSpendTrackingTxManager(logger, inner, spent)
+		receipt, err := txMgr.Send(ctx, txmgr.TxCandidate{Value: big.NewInt(1000)})
+		require.NoError(t, err)
+		require.Same(t, inner.receipt, receipt)
+		require.Equal(t, int64(5+300+1000), spent.spent.Int64())
+	})
+
+	t.Run("RecordOnlyFeesWhenReverted", func(t *testing.T) {
+		spent := &stubSpendTracker{spent: big.NewInt(0)}
+		inner := &stubTxManager{receipt: &ethtypes.Receipt{
+			Status:            ethtypes.ReceiptStatusFailed,
+			GasUsed:           100,
+			EffectiveGasPrice: big.NewInt(3),
+			BlobGasUsed:       10,
+			BlobGasPrice:      big.NewInt(2),
+		}}
+		txMgr := NewSpendTrackingTxManager(logger, inner, spent)
+		_, err := txMgr.Send(ctx, txmgr.TxCandidate{Value: big.NewInt(1000)})
+		require.NoError(t, err)
+		require.Equal(t, int64(300+20), spent.spent.Int64())
+	})
+
+	t.Run("NothingRecordedWhenSendFails", func(t *testing.T) {
+		spent := &stubSpendTracker{spent: big.NewInt(0)}
+		inner := &stubTxManager{err: errors.New("boom")}
+		txMgr := NewSpendTrackingTxManager(logger, inner, spent)
+		_, err := txMgr.Send(ctx, txmgr.TxCandidate{})
+		require.ErrorIs(t, err, inner.err)
+		require.Zero(t, spent.spent.Sign())
+	})
+
+	t.Run("ReturnReceiptWhenRecordingFails", func(t *testing.T) {
+		spent := &stubSpendTracker{spent: big.NewInt(0), err: errors.New("boom")}
+		inner := &stubTxManager{receipt: &ethtypes.Receipt{Status: ethtypes.ReceiptStatusSuccessful}}
+		txMgr := NewSpendTrackingTxManager(logger, inner, spent)
+		receipt, err := txMgr.Send(ctx, txmgr.TxCandidate{})
+		require.NoError(t, err)
+		require.Same(t, inner.receipt, receipt)
+	})
+}
+
+type stubTxManager struct {
+	txmgr.TxManager
+	receipt *ethtypes.Receipt
+	err     error
+}
+
+func (s *stubTxManager) Send(ctx context.Context, candidate txmgr.TxCandidate) (*ethtypes.Receipt, error) {
+	return s.receipt, s.err
+}
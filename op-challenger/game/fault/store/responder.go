@@ -0,0 +1,91 @@
+package store
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
+	gameTypes "github.com/ethereum-optimism/optimism/op-challenger/game/types"
+)
+
+// ErrResolutionPending is returned when checking if a claim can be resolved, while its resolution is pending.
+var ErrResolutionPending = errors.New("resolution pending")
+
+// Responder is the interface of the responder that sends the transactions, see [responder.FaultResponder].
+type Responder interface {
+	CallResolve(ctx context.Context) (gameTypes.GameStatus, error)
+	Resolve(ctx context.Context) error
+	CallResolveClaim(ctx context.Context, claimIdx uint64) error
+	ResolveClaim(ctx context.Context, claimIdx uint64) error
+	PerformAction(ctx context.Context, action types.Action) error
+}
+
+// RecordingResponder records the transactions of the wrapped [Responder] in the [Store],
+// and skips actions and resolutions that are still pending, e.g. from before a restart.
+// Failing to record is logged, but does not fail the transaction.
+type RecordingResponder struct {
+	Responder
+	logger log.Logger
+	store  *Store
+}
+
+func NewRecordingResponder(logger log.Logger, responder Responder, store *Store) *RecordingResponder {
+	return &RecordingResponder{
+		Responder: responder,
+		logger:    logger,
+		store:     store,
+	}
+}
+
+func (r *RecordingResponder) Resolve(ctx context.Context) error {
+	return r.resolve(nil, func() error {
+		return r.Responder.Resolve(ctx)
+	})
+}
+
+// CallResolveClaim reports claims with a pending resolution as not resolvable,
+// so they are not resolved again until the resolution expires.
+func (r *RecordingResponder) CallResolveClaim(ctx context.Context, claimIdx uint64) error {
+	if r.store.ResolvePending(&claimIdx) {
+		return ErrResolutionPending
+	}
+	return r.Responder.CallResolveClaim(ctx, claimIdx)
+}
+
+func (r *RecordingResponder) ResolveClaim(ctx context.Context, claimIdx uint64) error {
+	return r.resolve(&claimIdx, func() error {
+		return r.Responder.ResolveClaim(ctx, claimIdx)
+	})
+}
+
+func (r *RecordingResponder) resolve(claimIdx *uint64, fn func() error) error {
+	if r.store.ResolvePending(claimIdx) {
+		r.logger.Info("Skipping pending resolution", "resolution", resolveKey(claimIdx))
+		return nil
+	}
+	if err := r.store.StartResolve(claimIdx); err != nil {
+		r.logger.Warn("Failed to record resolution", "resolution", resolveKey(claimIdx), "err", err)
+	}
+	resolveErr := fn()
+	if err := r.store.CompleteResolve(claimIdx, resolveErr); err != nil {
+		r.logger.Warn("Failed to record completed resolution", "resolution", resolveKey(claimIdx), "err", err)
+	}
+	return resolveErr
+}
+
+func (r *RecordingResponder) PerformAction(ctx context.Context, action types.Action) error {
+	if r.store.Pending(action) {
+		r.logger.Info("Skipping pending action", "action", action.Type, "parent", action.ParentIdx, "is_attack", action.IsAttack)
+		return nil
+	}
+	if err := r.store.StartAction(action); err != nil {
+		r.logger.Warn("Failed to record action", "err", err)
+	}
+	actionErr := r.Responder.PerformAction(ctx, action)
+	if err := r.store.CompleteAction(action, actionErr); err != nil {
+		r.logger.Warn("Failed to record completed action", "err", err)
+	}
+	return actionErr
+}
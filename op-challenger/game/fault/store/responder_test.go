@@ -0,0 +1,138 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/trace/alphabet"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
+	gameTypes "github.com/ethereum-optimism/optimism/op-challenger/game/types"
+	"github.com/ethereum-optimism/optimism/op-service/clock"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+)
+
+type stubResponder struct {
+	actions       []types.Action
+	actionErr     error
+	resolveCount  int
+	resolvedClaim []uint64
+}
+
+func (s *stubResponder) CallResolve(ctx context.Context) (gameTypes.GameStatus, error) {
+	return gameTypes.GameStatusInProgress, nil
+}
+
+func (s *stubResponder) Resolve(ctx context.Context) error {
+	s.resolveCount++
+	return nil
+}
+
+func (s *stubResponder) CallResolveClaim(ctx context.Context, claimIdx uint64) error {
+	return nil
+}
+
+func (s *stubResponder) ResolveClaim(ctx context.Context, claimIdx uint64) error {
+	s.resolvedClaim = append(s.resolvedClaim, claimIdx)
+	return nil
+}
+
+func (s *stubResponder) PerformAction(ctx context.Context, action types.Action) error {
+	s.actions = append(s.actions, action)
+	return s.actionErr
+}
+
+func TestRecordingResponder(t *testing.T) {
+	ctx := context.Background()
+	logger := testlog.Logger(t, log.LvlInfo)
+
+	t.Run("SkipActionInFlightBeforeRestart", func(t *testing.T) {
+		dir := t.TempDir()
+		cl := clock.NewDeterministicClock(time.Unix(1000, 0))
+		// The previous process crashed while the move was in flight.
+		require.NoError(t, openTestStore(t, dir, cl).StartAction(testMove))
+
+		inner := &stubResponder{}
+		r := NewRecordingResponder(logger, inner, openTestStore(t, dir, cl))
+		require.NoError(t, r.PerformAction(ctx, testMove))
+		require.Empty(t, inner.actions, "should not repeat in-flight move")
+
+		require.NoError(t, r.PerformAction(ctx, testStep))
+		require.Equal(t, []types.Action{testStep}, inner.actions)
+
+		cl.AdvanceTime(testExpiry)
+		require.NoError(t, r.PerformAction(ctx, testMove))
+		require.Equal(t, []types.Action{testStep, testMove}, inner.actions, "should retry expired move")
+	})
+
+	t.Run("RetryFailedAction", func(t *testing.T) {
+		inner := &stubResponder{actionErr: errors.New("boom")}
+		r := NewRecordingResponder(logger, inner, openTestStore(t, t.TempDir(), clock.NewDeterministicClock(time.Unix(1000, 0))))
+		require.ErrorIs(t, r.PerformAction(ctx, testMove), inner.actionErr)
+		require.ErrorIs(t, r.PerformAction(ctx, testMove), inner.actionErr)
+		require.Len(t, inner.actions, 2)
+	})
+
+	t.Run("SkipResolutionInFlightBeforeRestart", func(t *testing.T) {
+		dir := t.TempDir()
+		cl := clock.NewDeterministicClock(time.Unix(1000, 0))
+		claimIdx := uint64(3)
+		prev := openTestStore(t, dir, cl)
+		require.NoError(t, prev.StartResolve(nil))
+		require.NoError(t, prev.StartResolve(&claimIdx))
+
+		inner := &stubResponder{}
+		s := openTestStore(t, dir, cl)
+		r := NewRecordingResponder(logger, inner, s)
+		require.NoError(t, r.Resolve(ctx))
+		require.Zero(t, inner.resolveCount)
+		require.ErrorIs(t, r.CallResolveClaim(ctx, claimIdx), ErrResolutionPending)
+		require.NoError(t, r.CallResolveClaim(ctx, claimIdx+1))
+		require.NoError(t, r.ResolveClaim(ctx, claimIdx+1))
+		require.Equal(t, []uint64{claimIdx + 1}, inner.resolvedClaim)
+		require.Equal(t, uint64(1), s.ResolveAttempts(ptr(claimIdx+1)).Attempts)
+	})
+}
+
+func TestRecordingTraceProvider(t *testing.T) {
+	ctx := context.Background()
+	logger := testlog.Logger(t, log.LvlInfo)
+	dir := t.TempDir()
+	pos := types.NewPosition(3, big.NewInt(2))
+	alphabetProvider := alphabet.NewTraceProvider("abcdefgh", 3)
+	expected, err := alphabetProvider.Get(ctx, pos)
+	require.NoError(t, err)
+
+	provider := NewRecordingTraceProvider(logger, alphabetProvider, openTestStore(t, dir, clock.SystemClock))
+	value, err := provider.Get(ctx, pos)
+	require.NoError(t, err)
+	require.Equal(t, expected, value)
+
+	// After a restart, the value is loaded from the store instead of the wrapped provider.
+	restarted := NewRecordingTraceProvider(logger, &erroringTraceProvider{alphabetProvider}, openTestStore(t, dir, clock.SystemClock))
+	value, err = restarted.Get(ctx, pos)
+	require.NoError(t, err)
+	require.Equal(t, expected, value)
+	_, err = restarted.Get(ctx, pos.MoveRight())
+	require.ErrorIs(t, err, errNotRecorded)
+}
+
+var errNotRecorded = errors.New("not recorded")
+
+type erroringTraceProvider struct {
+	types.TraceProvider
+}
+
+func (p *erroringTraceProvider) Get(ctx context.Context, pos types.Position) (common.Hash, error) {
+	return common.Hash{}, errNotRecorded
+}
+
+func ptr(v uint64) *uint64 {
+	return &v
+}
@@ -0,0 +1,289 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
+	"github.com/ethereum-optimism/optimism/op-service/clock"
+)
+
+const stateFile = "state.json"
+
+// DefaultPendingExpiry is the time after which an in-flight or recently completed action may be performed again.
+// Until then, the action is assumed to be pending inclusion, or not yet visible through the L1 RPC.
+const DefaultPendingExpiry = 10 * time.Minute
+
+// Response is a counter to a claim, that was performed by the challenger.
+type Response struct {
+	Type     types.ActionType `json:"type"`
+	IsAttack bool             `json:"isAttack"`
+	// Value is the claim of a move, and empty for a step.
+	Value common.Hash `json:"value"`
+	Time  time.Time   `json:"time"`
+}
+
+// ResolveAttempt records the attempts to resolve the game or one of its claims.
+type ResolveAttempt struct {
+	Attempts uint64    `json:"attempts"`
+	Last     time.Time `json:"last"`
+	// LastErr is the error of the last attempt, empty if it succeeded.
+	LastErr string `json:"lastErr,omitempty"`
+}
+
+// gameState is the persisted state of a single game.
+type gameState struct {
+	// ValidatedPrestate is the onchain absolute prestate that matched the trace provider's absolute prestate.
+	ValidatedPrestate common.Hash `json:"validatedPrestate"`
+	// Countered are the responses to claims, by contract index of the countered claim.
+	Countered map[int]Response `json:"countered"`
+	// InFlight are the actions and resolutions that were sent without completing yet, by key.
+	InFlight map[string]time.Time `json:"inFlight"`
+	// ResolveAttempts are the attempts to resolve the game or its claims, by key.
+	ResolveAttempts map[string]ResolveAttempt `json:"resolveAttempts"`
+	// TraceValues are the claim values of the trace provider, by generalized index of the position.
+	TraceValues map[string]common.Hash `json:"traceValues"`
+	// Spent is the total amount of wei spent on transactions for the game.
+	Spent *big.Int `json:"spent,omitempty"`
+}
+
+// init creates the maps that are missing in the persisted state.
+func (g *gameState) init() {
+	if g.Countered == nil {
+		g.Countered = make(map[int]Response)
+	}
+	if g.InFlight == nil {
+		g.InFlight = make(map[string]time.Time)
+	}
+	if g.ResolveAttempts == nil {
+		g.ResolveAttempts = make(map[string]ResolveAttempt)
+	}
+	if g.TraceValues == nil {
+		g.TraceValues = make(map[string]common.Hash)
+	}
+	if g.Spent == nil {
+		g.Spent = new(big.Int)
+	}
+}
+
+// Store is the local state of a game, persisted in the data directory of the game.
+// It allows restarts to resume without duplicating actions or recomputing the trace.
+// Store is safe for concurrent use.
+type Store struct {
+	mu     sync.Mutex
+	file   string
+	clock  clock.Clock
+	expiry time.Duration
+	state  gameState
+}
+
+// Open loads the state of the game in dir, or starts with an empty state if there is no state yet.
+func Open(dir string, cl clock.Clock, expiry time.Duration) (*Store, error) {
+	s := &Store{
+		file:   filepath.Join(dir, stateFile),
+		clock:  cl,
+		expiry: expiry,
+	}
+	data, err := os.ReadFile(s.file)
+	if errors.Is(err, os.ErrNotExist) {
+		s.state.init()
+		return s, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("read game state %v: %w", s.file, err)
+	}
+	if err := json.Unmarshal(data, &s.state); err != nil {
+		return nil, fmt.Errorf("decode game state %v: %w", s.file, err)
+	}
+	s.state.init()
+	return s, nil
+}
+
+// ValidatedPrestate returns the onchain absolute prestate that was validated, or an empty hash if not validated yet.
+func (s *Store) ValidatedPrestate() common.Hash {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state.ValidatedPrestate
+}
+
+// SetValidatedPrestate records that the onchain absolute prestate matched the trace provider.
+func (s *Store) SetValidatedPrestate(prestate common.Hash) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state.ValidatedPrestate = prestate
+	return s.persist()
+}
+
+// Countered returns the response to the claim at the contract index, if any.
+func (s *Store) Countered(claimIdx int) (Response, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.state.Countered[claimIdx]
+	return r, ok
+}
+
+// Pending returns true if the action was started less than the expiry ago without completing, or if the same
+// action completed less than the expiry ago.
+func (s *Store) Pending(action types.Action) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.clock.Now()
+	if started, ok := s.state.InFlight[actionKey(action)]; ok && now.Sub(started) < s.expiry {
+		return true
+	}
+	r, ok := s.state.Countered[action.ParentIdx]
+	return ok && r == newResponse(action, r.Time) && now.Sub(r.Time) < s.expiry
+}
+
+// StartAction records the action as in-flight.
+func (s *Store) StartAction(action types.Action) error {
+	return s.start(actionKey(action))
+}
+
+// CompleteAction removes the action from the in-flight actions, and records the countered claim if the action succeeded.
+func (s *Store) CompleteAction(action types.Action, actionErr error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.state.InFlight, actionKey(action))
+	if actionErr == nil {
+		s.state.Countered[action.ParentIdx] = newResponse(action, s.clock.Now())
+	}
+	return s.persist()
+}
+
+// ResolvePending returns true if the resolution of the game, or of the claim if claimIdx is not nil,
+// was started less than the expiry ago without completing.
+func (s *Store) ResolvePending(claimIdx *uint64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	started, ok := s.state.InFlight[resolveKey(claimIdx)]
+	return ok && s.clock.Now().Sub(started) < s.expiry
+}
+
+// StartResolve records an attempt to resolve the game, or the claim if claimIdx is not nil, as in-flight.
+func (s *Store) StartResolve(claimIdx *uint64) error {
+	return s.start(resolveKey(claimIdx))
+}
+
+// CompleteResolve removes the resolution from the in-flight actions, and records the result of the attempt.
+func (s *Store) CompleteResolve(claimIdx *uint64, resolveErr error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := resolveKey(claimIdx)
+	delete(s.state.InFlight, key)
+	attempt := s.state.ResolveAttempts[key]
+	attempt.Attempts++
+	attempt.Last = s.clock.Now()
+	attempt.LastErr = ""
+	if resolveErr != nil {
+		attempt.LastErr = resolveErr.Error()
+	}
+	s.state.ResolveAttempts[key] = attempt
+	return s.persist()
+}
+
+// ResolveAttempts returns the attempts to resolve the game, or the claim if claimIdx is not nil.
+func (s *Store) ResolveAttempts(claimIdx *uint64) ResolveAttempt {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state.ResolveAttempts[resolveKey(claimIdx)]
+}
+
+// TraceValue returns the recorded claim value of the trace provider at the position, if any.
+func (s *Store) TraceValue(pos types.Position) (common.Hash, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.state.TraceValues[pos.ToGIndex().String()]
+	return v, ok
+}
+
+// SetTraceValue records the claim value of the trace provider at the position.
+func (s *Store) SetTraceValue(pos types.Position, value common.Hash) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state.TraceValues[pos.ToGIndex().String()] = value
+	return s.persist()
+}
+
+// Spent returns the total amount of wei spent on transactions for the game.
+func (s *Store) Spent() *big.Int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return new(big.Int).Set(s.state.Spent)
+}
+
+// AddSpent adds the cost of a transaction, in wei, to the amount spent on the game.
+func (s *Store) AddSpent(cost *big.Int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state.Spent = new(big.Int).Add(s.state.Spent, cost)
+	return s.persist()
+}
+
+func (s *Store) start(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state.InFlight[key] = s.clock.Now()
+	return s.persist()
+}
+
+// persist writes the state to a temp file first, then renames it into place,
+// so a crash while writing does not corrupt the previous state.
+// The lock must be held by the caller.
+func (s *Store) persist() error {
+	data, err := json.Marshal(s.state)
+	if err != nil {
+		return fmt.Errorf("encode game state: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.file), 0755); err != nil {
+		return fmt.Errorf("create game dir: %w", err)
+	}
+	tmpFile := s.file + ".tmp"
+	file, err := os.OpenFile(tmpFile, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return fmt.Errorf("open game state temp file (%v): %w", tmpFile, err)
+	}
+	defer file.Close()
+	if _, err := file.Write(data); err != nil {
+		return fmt.Errorf("write game state temp file (%v): %w", tmpFile, err)
+	}
+	if err := file.Sync(); err != nil {
+		return fmt.Errorf("sync game state temp file (%v): %w", tmpFile, err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("close game state temp file (%v): %w", tmpFile, err)
+	}
+	if err := os.Rename(tmpFile, s.file); err != nil {
+		return fmt.Errorf("rename game state temp file: %w", err)
+	}
+	return nil
+}
+
+func newResponse(action types.Action, t time.Time) Response {
+	r := Response{Type: action.Type, IsAttack: action.IsAttack, Time: t}
+	if action.Type == types.ActionTypeMove {
+		r.Value = action.Value
+	}
+	return r
+}
+
+func actionKey(action types.Action) string {
+	if action.Type == types.ActionTypeMove {
+		return fmt.Sprintf("%v-%d-%v-%v", action.Type, action.ParentIdx, action.IsAttack, action.Value)
+	}
+	return fmt.Sprintf("%v-%d-%v", action.Type, action.ParentIdx, action.IsAttack)
+}
+
+func resolveKey(claimIdx *uint64) string {
+	if claimIdx == nil {
+		return "resolve"
+	}
+	return fmt.Sprintf("resolveClaim-%d", *claimIdx)
+}
@@ -0,0 +1,132 @@
+package store
+
+import (
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
+	"github.com/ethereum-optimism/optimism/op-service/clock"
+)
+
+const testExpiry = time.Minute
+
+var (
+	testMove = types.Action{Type: types.ActionTypeMove, ParentIdx: 2, IsAttack: true, Value: common.Hash{0xaa}}
+	testStep = types.Action{Type: types.ActionTypeStep, ParentIdx: 3, IsAttack: false, PreState: []byte{1}, ProofData: []byte{2}}
+)
+
+func openTestStore(t *testing.T, dir string, cl clock.Clock) *Store {
+	s, err := Open(dir, cl, testExpiry)
+	require.NoError(t, err)
+	return s
+}
+
+func TestStore(t *testing.T) {
+	t.Run("EmptyWhenNoState", func(t *testing.T) {
+		s := openTestStore(t, t.TempDir(), clock.NewDeterministicClock(time.Unix(1000, 0)))
+		require.Equal(t, common.Hash{}, s.ValidatedPrestate())
+		require.False(t, s.Pending(testMove))
+		require.False(t, s.ResolvePending(nil))
+		_, ok := s.Countered(testMove.ParentIdx)
+		require.False(t, ok)
+		require.Zero(t, s.Spent().Sign())
+	})
+
+	t.Run("PersistAcrossRestart", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "game")
+		cl := clock.NewDeterministicClock(time.Unix(1000, 0))
+		claimIdx := uint64(4)
+		pos := types.NewPosition(2, big.NewInt(3))
+
+		s := openTestStore(t, dir, cl)
+		require.NoError(t, s.SetValidatedPrestate(common.Hash{0x01}))
+		require.NoError(t, s.StartAction(testMove))
+		require.NoError(t, s.StartAction(testStep))
+		require.NoError(t, s.CompleteAction(testStep, nil))
+		require.NoError(t, s.StartResolve(&claimIdx))
+		require.NoError(t, s.SetTraceValue(pos, common.Hash{0xbb}))
+		require.NoError(t, s.AddSpent(big.NewInt(100)))
+		require.NoError(t, s.AddSpent(big.NewInt(20)))
+
+		reopened := openTestStore(t, dir, cl)
+		require.Equal(t, common.Hash{0x01}, reopened.ValidatedPrestate())
+		require.True(t, reopened.Pending(testMove), "in-flight move")
+		require.True(t, reopened.Pending(testStep), "completed step")
+		require.True(t, reopened.ResolvePending(&claimIdx))
+		require.False(t, reopened.ResolvePending(nil))
+		countered, ok := reopened.Countered(testStep.ParentIdx)
+		require.True(t, ok)
+		require.Equal(t, types.ActionTypeStep, countered.Type)
+		value, ok := reopened.TraceValue(pos)
+		require.True(t, ok)
+		require.Equal(t, common.Hash{0xbb}, value)
+		_, ok = reopened.TraceValue(pos.MoveRight())
+		require.False(t, ok)
+		require.Equal(t, int64(120), reopened.Spent().Int64())
+	})
+
+	t.Run("PendingExpires", func(t *testing.T) {
+		cl := clock.NewDeterministicClock(time.Unix(1000, 0))
+		s := openTestStore(t, t.TempDir(), cl)
+		require.NoError(t, s.StartAction(testMove))
+		require.NoError(t, s.StartResolve(nil))
+		cl.AdvanceTime(testExpiry - time.Second)
+		require.True(t, s.Pending(testMove))
+		require.True(t, s.ResolvePending(nil))
+		cl.AdvanceTime(time.Second)
+		require.False(t, s.Pending(testMove))
+		require.False(t, s.ResolvePending(nil))
+	})
+
+	t.Run("CompletedActionPendingUntilExpiry", func(t *testing.T) {
+		cl := clock.NewDeterministicClock(time.Unix(1000, 0))
+		s := openTestStore(t, t.TempDir(), cl)
+		require.NoError(t, s.StartAction(testMove))
+		require.NoError(t, s.CompleteAction(testMove, nil))
+		require.True(t, s.Pending(testMove))
+
+		otherValue := testMove
+		otherValue.Value = common.Hash{0xcc}
+		require.False(t, s.Pending(otherValue), "different move to the same claim")
+
+		cl.AdvanceTime(testExpiry)
+		require.False(t, s.Pending(testMove))
+	})
+
+	t.Run("FailedActionNotPending", func(t *testing.T) {
+		s := openTestStore(t, t.TempDir(), clock.NewDeterministicClock(time.Unix(1000, 0)))
+		require.NoError(t, s.StartAction(testMove))
+		require.NoError(t, s.CompleteAction(testMove, errors.New("boom")))
+		require.False(t, s.Pending(testMove))
+		_, ok := s.Countered(testMove.ParentIdx)
+		require.False(t, ok)
+	})
+
+	t.Run("ResolveAttempts", func(t *testing.T) {
+		cl := clock.NewDeterministicClock(time.Unix(1000, 0))
+		s := openTestStore(t, t.TempDir(), cl)
+		claimIdx := uint64(1)
+		require.NoError(t, s.StartResolve(&claimIdx))
+		require.NoError(t, s.CompleteResolve(&claimIdx, errors.New("boom")))
+		cl.AdvanceTime(time.Second)
+		require.NoError(t, s.StartResolve(&claimIdx))
+		require.NoError(t, s.CompleteResolve(&claimIdx, nil))
+		require.False(t, s.ResolvePending(&claimIdx))
+		require.Equal(t, ResolveAttempt{Attempts: 2, Last: cl.Now()}, s.ResolveAttempts(&claimIdx))
+		require.Equal(t, ResolveAttempt{}, s.ResolveAttempts(nil))
+	})
+
+	t.Run("InvalidState", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, stateFile), []byte("{"), 0644))
+		_, err := Open(dir, clock.SystemClock, testExpiry)
+		require.ErrorContains(t, err, "decode game state")
+	})
+}
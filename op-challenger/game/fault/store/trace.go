@@ -0,0 +1,40 @@
+package store
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
+)
+
+// RecordingTraceProvider records the claim values of the wrapped [types.TraceProvider] in the [Store],
+// so that the values are not recomputed after a restart, e.g. by executing cannon again.
+type RecordingTraceProvider struct {
+	types.TraceProvider
+	logger log.Logger
+	store  *Store
+}
+
+func NewRecordingTraceProvider(logger log.Logger, provider types.TraceProvider, store *Store) *RecordingTraceProvider {
+	return &RecordingTraceProvider{
+		TraceProvider: provider,
+		logger:        logger,
+		store:         store,
+	}
+}
+
+func (p *RecordingTraceProvider) Get(ctx context.Context, pos types.Position) (common.Hash, error) {
+	if value, ok := p.store.TraceValue(pos); ok {
+		return value, nil
+	}
+	value, err := p.TraceProvider.Get(ctx, pos)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if err := p.store.SetTraceValue(pos, value); err != nil {
+		p.logger.Warn("Failed to record trace value", "depth", pos.Depth(), "index", pos.IndexAtDepth(), "err", err)
+	}
+	return value, nil
+}
@@ -0,0 +1,170 @@
+package cannon
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"golang.org/x/exp/slices"
+
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm"
+)
+
+const (
+	// traceDirPrefix is the prefix of the shared trace directories in the data directory.
+	traceDirPrefix = "cannon-"
+	// traceRefFile is the file in a game directory that records the name of the shared trace directory it uses.
+	traceRefFile = "cannon-trace"
+)
+
+// TraceCache shares the cannon trace data, including snapshots, proofs and preimages, between games that have
+// the same local inputs and absolute pre-state. Such games have exactly the same trace, so it only needs to be
+// generated once.
+// Each game records the shared trace directory it uses in its own game directory. A shared trace directory is
+// removed once the directories of all games that use it have been removed.
+type TraceCache struct {
+	datadir string
+
+	lock  sync.Mutex
+	locks map[string]*sync.Mutex
+}
+
+func NewTraceCache(datadir string) *TraceCache {
+	return &TraceCache{
+		datadir: datadir,
+		locks:   make(map[string]*sync.Mutex),
+	}
+}
+
+// TraceDir returns the directory of the trace for the specified key and records that it is used by the game
+// with data in gameDir. The returned lock must be held while generating the trace, to avoid games with the same
+// trace executing cannon concurrently.
+// Games that already generated part of their trace in gameDir, before traces were shared, continue to use gameDir.
+func (c *TraceCache) TraceDir(gameDir string, key common.Hash) (string, *sync.Mutex, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if hasLocalTrace(gameDir) {
+		return gameDir, c.lockFor(gameDir), nil
+	}
+	name := traceDirPrefix + key.Hex()
+	if err := os.MkdirAll(gameDir, 0755); err != nil {
+		return "", nil, fmt.Errorf("failed to create game directory %v: %w", gameDir, err)
+	}
+	if err := writeTraceRef(gameDir, name); err != nil {
+		return "", nil, err
+	}
+	dir := filepath.Join(c.datadir, name)
+	return dir, c.lockFor(dir), nil
+}
+
+// RemoveUnreferenced removes the shared trace directories that are not used by any of the games in gameDirs.
+func (c *TraceCache) RemoveUnreferenced(gameDirs []string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	var used []string
+	for _, gameDir := range gameDirs {
+		name, err := readTraceRef(gameDir)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		} else if err != nil {
+			// Keep all traces if it isn't known which trace a game uses.
+			return err
+		}
+		used = append(used, name)
+	}
+	entries, err := os.ReadDir(c.datadir)
+	if err != nil {
+		return fmt.Errorf("failed to list directory: %w", err)
+	}
+	var errs []error
+	for _, entry := range entries {
+		if !entry.IsDir() || !strings.HasPrefix(entry.Name(), traceDirPrefix) || slices.Contains(used, entry.Name()) {
+			continue
+		}
+		dir := filepath.Join(c.datadir, entry.Name())
+		// The lock is kept, so a game that still holds it and a game that uses the trace again share it.
+		lock := c.lockFor(dir)
+		if !lock.TryLock() {
+			// The trace is still being generated, it is removed once that completes.
+			continue
+		}
+		errs = append(errs, os.RemoveAll(dir))
+		lock.Unlock()
+	}
+	return errors.Join(errs...)
+}
+
+func (c *TraceCache) lockFor(dir string) *sync.Mutex {
+	lock, ok := c.locks[dir]
+	if !ok {
+		lock = &sync.Mutex{}
+		c.locks[dir] = lock
+	}
+	return lock
+}
+
+// TraceKey returns the key identifying the trace of games with the specified local inputs and absolute pre-state.
+func TraceKey(inputs LocalGameInputs, prestate common.Hash) common.Hash {
+	return crypto.Keccak256Hash(
+		inputs.L1Head[:],
+		inputs.L2Head[:],
+		inputs.L2OutputRoot[:],
+		inputs.L2Claim[:],
+		common.BigToHash(inputs.L2BlockNumber).Bytes(),
+		prestate[:])
+}
+
+// prestateHash returns the state hash of the absolute pre-state at path.
+func prestateHash(path string) (common.Hash, error) {
+	state, err := parseState(path)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("cannot load absolute pre-state: %w", err)
+	}
+	hash, err := mipsevm.StateWitness(state.EncodeWitness()).StateHash()
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("cannot hash absolute pre-state: %w", err)
+	}
+	return hash, nil
+}
+
+// hasLocalTrace returns true if cannon has already been executed with gameDir as the trace directory.
+func hasLocalTrace(gameDir string) bool {
+	for _, dir := range []string{proofsDir, snapsDir} {
+		if _, err := os.Stat(filepath.Join(gameDir, dir)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+func readTraceRef(gameDir string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(gameDir, traceRefFile))
+	if err != nil {
+		return "", err
+	}
+	name := strings.TrimSpace(string(data))
+	if !strings.HasPrefix(name, traceDirPrefix) || strings.ContainsRune(name, filepath.Separator) {
+		return "", fmt.Errorf("invalid trace reference %q in %v", name, gameDir)
+	}
+	return name, nil
+}
+
+func writeTraceRef(gameDir string, name string) error {
+	path := filepath.Join(gameDir, traceRefFile)
+	if existing, err := readTraceRef(gameDir); err == nil && existing == name {
+		return nil
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(name), 0644); err != nil {
+		return fmt.Errorf("failed to write trace reference: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write trace reference: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package cannon
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTraceCache_TraceDir(t *testing.T) {
+	t.Run("ShareTraceWithSameKey", func(t *testing.T) {
+		datadir := t.TempDir()
+		cache := NewTraceCache(datadir)
+		dirA, lockA, err := cache.TraceDir(filepath.Join(datadir, "game-a"), common.Hash{0x01})
+		require.NoError(t, err)
+		dirB, lockB, err := cache.TraceDir(filepath.Join(datadir, "game-b"), common.Hash{0x01})
+		require.NoError(t, err)
+		require.Equal(t, dirA, dirB)
+		require.Same(t, lockA, lockB)
+		require.Equal(t, datadir, filepath.Dir(dirA))
+
+		dirC, lockC, err := cache.TraceDir(filepath.Join(datadir, "game-c"), common.Hash{0x02})
+		require.NoError(t, err)
+		require.NotEqual(t, dirA, dirC)
+		require.NotSame(t, lockA, lockC)
+	})
+
+	t.Run("RecordReferenceInGameDir", func(t *testing.T) {
+		datadir := t.TempDir()
+		cache := NewTraceCache(datadir)
+		gameDir := filepath.Join(datadir, "game-a")
+		dir, _, err := cache.TraceDir(gameDir, common.Hash{0x01})
+		require.NoError(t, err)
+		name, err := readTraceRef(gameDir)
+		require.NoError(t, err)
+		require.Equal(t, filepath.Base(dir), name)
+
+		// Reopening the game uses the same trace
+		reopened, _, err := NewTraceCache(datadir).TraceDir(gameDir, common.Hash{0x01})
+		require.NoError(t, err)
+		require.Equal(t, dir, reopened)
+	})
+
+	t.Run("UseExistingTraceInGameDir", func(t *testing.T) {
+		datadir := t.TempDir()
+		cache := NewTraceCache(datadir)
+		gameDir := filepath.Join(datadir, "game-a")
+		require.NoError(t, os.MkdirAll(filepath.Join(gameDir, snapsDir), 0o755))
+		dir, _, err := cache.TraceDir(gameDir, common.Hash{0x01})
+		require.NoError(t, err)
+		require.Equal(t, gameDir, dir)
+		require.NoFileExists(t, filepath.Join(gameDir, traceRefFile))
+	})
+}
+
+func TestTraceCache_RemoveUnreferenced(t *testing.T) {
+	datadir := t.TempDir()
+	cache := NewTraceCache(datadir)
+	gameA := filepath.Join(datadir, "game-a")
+	gameB := filepath.Join(datadir, "game-b")
+	gameC := filepath.Join(datadir, "game-c")
+	shared, _, err := cache.TraceDir(gameA, common.Hash{0x01})
+	require.NoError(t, err)
+	_, _, err = cache.TraceDir(gameB, common.Hash{0x01})
+	require.NoError(t, err)
+	unused, _, err := cache.TraceDir(gameC, common.Hash{0x02})
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Join(shared, proofsDir), 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(unused, proofsDir), 0o755))
+	unexpectedDir := filepath.Join(datadir, "notatrace")
+	require.NoError(t, os.MkdirAll(unexpectedDir, 0o755))
+
+	// Game A was removed but game B still uses the shared trace
+	require.NoError(t, cache.RemoveUnreferenced([]string{gameB}))
+	require.DirExists(t, shared)
+	require.NoDirExists(t, unused)
+	require.DirExists(t, unexpectedDir)
+
+	require.NoError(t, cache.RemoveUnreferenced(nil))
+	require.NoDirExists(t, shared)
+	require.DirExists(t, unexpectedDir)
+}
+
+func TestTraceCache_RemoveUnreferencedKeepsLocks(t *testing.T) {
+	datadir := t.TempDir()
+	cache := NewTraceCache(datadir)
+	gameA := filepath.Join(datadir, "game-a")
+	dir, lock, err := cache.TraceDir(gameA, common.Hash{0x01})
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, proofsDir), 0o755))
+
+	// A trace that is still being generated is not removed
+	lock.Lock()
+	require.NoError(t, cache.RemoveUnreferenced(nil))
+	require.DirExists(t, dir)
+
+	// A game that uses the trace again must wait for the same lock
+	_, reused, err := cache.TraceDir(filepath.Join(datadir, "game-b"), common.Hash{0x01})
+	require.NoError(t, err)
+	require.Same(t, lock, reused)
+	lock.Unlock()
+
+	require.NoError(t, cache.RemoveUnreferenced(nil))
+	require.NoDirExists(t, dir)
+	_, reused, err = cache.TraceDir(filepath.Join(datadir, "game-c"), common.Hash{0x01})
+	require.NoError(t, err)
+	require.Same(t, lock, reused)
+}
+
+func TestTraceKey(t *testing.T) {
+	inputs := LocalGameInputs{
+		L1Head:        common.Hash{0x11},
+		L2Head:        common.Hash{0x22},
+		L2OutputRoot:  common.Hash{0x33},
+		L2Claim:       common.Hash{0x44},
+		L2BlockNumber: big.NewInt(1234),
+	}
+	prestate := common.Hash{0xaa}
+	key := TraceKey(inputs, prestate)
+	require.Equal(t, key, TraceKey(inputs, prestate))
+	require.NotEqual(t, key, TraceKey(inputs, common.Hash{0xbb}))
+
+	modified := inputs
+	modified.L2BlockNumber = big.NewInt(1235)
+	require.NotEqual(t, key, TraceKey(modified, prestate))
+	modified = inputs
+	modified.L1Head = common.Hash{0x12}
+	require.NotEqual(t, key, TraceKey(modified, prestate))
+}
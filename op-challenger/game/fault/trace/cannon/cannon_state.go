@@ -1,13 +1,14 @@
 package cannon
 
 import (
-	"encoding/json"
 	"fmt"
 
 	"github.com/ethereum-optimism/optimism/cannon/mipsevm"
+	"github.com/ethereum-optimism/optimism/cannon/serialize"
 	"github.com/ethereum-optimism/optimism/op-service/ioutil"
 )
 
+// parseState loads a cannon state, in either the JSON or the binary format.
 func parseState(path string) (*mipsevm.State, error) {
 	file, err := ioutil.OpenDecompressed(path)
 	if err != nil {
@@ -15,7 +16,7 @@ func parseState(path string) (*mipsevm.State, error) {
 	}
 	defer file.Close()
 	var state mipsevm.State
-	err = json.NewDecoder(file).Decode(&state)
+	err = serialize.Read(file, &state)
 	if err != nil {
 		return nil, fmt.Errorf("invalid mipsevm state (%v): %w", path, err)
 	}
@@ -21,10 +21,13 @@ import (
 const (
 	snapsDir     = "snapshots"
 	preimagesDir = "preimages"
-	finalState   = "final.json.gz"
+	finalState   = "final.bin.gz"
+	// legacyFinalState is the final state written in JSON by earlier versions.
+	legacyFinalState = "final.json.gz"
 )
 
-var snapshotNameRegexp = regexp.MustCompile(`^[0-9]+\.json.gz$`)
+// snapshotNameRegexp matches binary snapshots, and JSON snapshots written by earlier versions.
+var snapshotNameRegexp = regexp.MustCompile(`^[0-9]+\.(bin|json)\.gz$`)
 
 type snapshotSelect func(logger log.Logger, dir string, absolutePreState string, i uint64) (string, error)
 type cmdExecutor func(ctx context.Context, l log.Logger, binary string, args ...string) error
@@ -83,9 +86,9 @@ func (e *Executor) GenerateProof(ctx context.Context, dir string, i uint64) erro
 		"--meta", "",
 		"--info-at", "%" + strconv.FormatUint(uint64(e.infoFreq), 10),
 		"--proof-at", "=" + strconv.FormatUint(i, 10),
-		"--proof-fmt", filepath.Join(proofDir, "%d.json.gz"),
+		"--proof-fmt", filepath.Join(proofDir, "%d"+proofExt),
 		"--snapshot-at", "%" + strconv.FormatUint(uint64(e.snapshotFreq), 10),
-		"--snapshot-fmt", filepath.Join(snapshotDir, "%d.json.gz"),
+		"--snapshot-fmt", filepath.Join(snapshotDir, "%d.bin.gz"),
 	}
 	if i < math.MaxUint64 {
 		args = append(args, "--stop-at", "="+strconv.FormatUint(i+1, 10))
@@ -152,6 +155,7 @@ func findStartingSnapshot(logger log.Logger, snapDir string, absolutePreState st
 		return "", fmt.Errorf("list snapshots in %v: %w", snapDir, err)
 	}
 	bestSnap := uint64(0)
+	bestSnapName := ""
 	for _, entry := range entries {
 		if entry.IsDir() {
 			logger.Warn("Unexpected directory in snapshots dir", "parent", snapDir, "child", entry.Name())
@@ -162,19 +166,20 @@ func findStartingSnapshot(logger log.Logger, snapDir string, absolutePreState st
 			logger.Warn("Unexpected file in snapshots dir", "parent", snapDir, "child", entry.Name())
 			continue
 		}
-		index, err := strconv.ParseUint(name[0:len(name)-len(".json.gz")], 10, 64)
+		index, err := strconv.ParseUint(name[0:strings.IndexByte(name, '.')], 10, 64)
 		if err != nil {
 			logger.Error("Unable to parse trace index of snapshot file", "parent", snapDir, "child", entry.Name())
 			continue
 		}
 		if index > bestSnap && index < traceIndex {
 			bestSnap = index
+			bestSnapName = name
 		}
 	}
 	if bestSnap == 0 {
 		return absolutePreState, nil
 	}
-	startFrom := fmt.Sprintf("%v/%v.json.gz", snapDir, bestSnap)
+	startFrom := fmt.Sprintf("%v/%v", snapDir, bestSnapName)
 
 	return startFrom, nil
 }
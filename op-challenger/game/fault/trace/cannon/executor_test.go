@@ -93,8 +93,8 @@ func TestGenerateProof(t *testing.T) {
 		require.Equal(t, cfg.L1EthRpc, args["--l1"])
 		require.Equal(t, cfg.CannonL2, args["--l2"])
 		require.Equal(t, filepath.Join(dir, preimagesDir), args["--datadir"])
-		require.Equal(t, filepath.Join(dir, proofsDir, "%d.json.gz"), args["--proof-fmt"])
-		require.Equal(t, filepath.Join(dir, snapsDir, "%d.json.gz"), args["--snapshot-fmt"])
+		require.Equal(t, filepath.Join(dir, proofsDir, "%d.bin.gz"), args["--proof-fmt"])
+		require.Equal(t, filepath.Join(dir, snapsDir, "%d.bin.gz"), args["--snapshot-fmt"])
 		require.Equal(t, cfg.CannonNetwork, args["--network"])
 		require.NotContains(t, args, "--rollup.config")
 		require.NotContains(t, args, "--l2.genesis")
@@ -198,6 +198,18 @@ func TestFindStartingSnapshot(t *testing.T) {
 		require.Equal(t, filepath.Join(dir, "250.json.gz"), snapshot)
 	})
 
+	t.Run("UseBinaryAndLegacyJSONSnapshots", func(t *testing.T) {
+		dir := withSnapshots(t, "100.json.gz", "123.bin.gz", "250.bin.gz")
+
+		snapshot, err := findStartingSnapshot(logger, dir, execTestCannonPrestate, 123)
+		require.NoError(t, err)
+		require.Equal(t, filepath.Join(dir, "100.json.gz"), snapshot)
+
+		snapshot, err = findStartingSnapshot(logger, dir, execTestCannonPrestate, 124)
+		require.NoError(t, err)
+		require.Equal(t, filepath.Join(dir, "123.bin.gz"), snapshot)
+	})
+
 	t.Run("IgnoreDirectories", func(t *testing.T) {
 		dir := withSnapshots(t, "100.json.gz")
 		require.NoError(t, os.Mkdir(filepath.Join(dir, "120.json.gz"), 0o777))
@@ -207,7 +219,7 @@ func TestFindStartingSnapshot(t *testing.T) {
 	})
 
 	t.Run("IgnoreUnexpectedFiles", func(t *testing.T) {
-		dir := withSnapshots(t, ".file", "100.json.gz", "foo", "bar.json.gz")
+		dir := withSnapshots(t, ".file", "100.json.gz", "foo", "bar.json.gz", "200.bin")
 		snapshot, err := findStartingSnapshot(logger, dir, execTestCannonPrestate, 150)
 		require.NoError(t, err)
 		require.Equal(t, filepath.Join(dir, "100.json.gz"), snapshot)
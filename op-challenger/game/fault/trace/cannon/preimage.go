@@ -0,0 +1,94 @@
+package cannon
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
+	preimage "github.com/ethereum-optimism/optimism/op-preimage"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+)
+
+var ErrInvalidBlobKeyPreimage = errors.New("invalid blob key pre-image")
+
+// blobKeyInputLength is the length of the keccak256 pre-image of a blob key: the KZG commitment
+// followed by the root of unity that the field element is the evaluation at.
+const blobKeyInputLength = 48 + 32
+
+type preimageSource func(key common.Hash) ([]byte, error)
+
+// preimageLoader loads the data required to load a pre-image into the on-chain oracle.
+type preimageLoader struct {
+	getPreimage preimageSource
+}
+
+func newPreimageLoader(getPreimage preimageSource) *preimageLoader {
+	return &preimageLoader{
+		getPreimage: getPreimage,
+	}
+}
+
+// LoadPreimage returns the oracle data for the pre-image read in proof, or nil if no pre-image is read.
+func (l *preimageLoader) LoadPreimage(proof *mipsevm.Proof) (*types.PreimageOracleData, error) {
+	if len(proof.OracleKey) == 0 {
+		return nil, nil
+	}
+	switch preimage.KeyType(proof.OracleKey[0]) {
+	case preimage.BlobKeyType:
+		return l.loadBlobPreimage(proof)
+	default:
+		// TODO(client-pod#104): Replace the LocalContext `0` argument below with the correct local context.
+		return types.NewPreimageOracleData(0, proof.OracleKey, proof.OracleValue, proof.OracleOffset), nil
+	}
+}
+
+// loadBlobPreimage loads the KZG commitment and point of the blob field element read in proof,
+// and computes the KZG proof that the on-chain oracle verifies the field element with.
+func (l *preimageLoader) loadBlobPreimage(proof *mipsevm.Proof) (*types.PreimageOracleData, error) {
+	// The blob key is the keccak256 hash of the commitment and point, so its keccak256 pre-image holds both.
+	inputsKey := preimage.Keccak256Key(proof.OracleKey).PreimageKey()
+	inputs, err := l.getPreimage(inputsKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get key preimage: %w", err)
+	}
+	if len(inputs) != blobKeyInputLength {
+		return nil, fmt.Errorf("%w, expected length %v but was %v", ErrInvalidBlobKeyPreimage, blobKeyInputLength, len(inputs))
+	}
+	var commitment kzg4844.Commitment
+	copy(commitment[:], inputs[:48])
+	var point kzg4844.Point
+	copy(point[:], inputs[48:])
+
+	// Reconstruct the full blob from its field elements to compute the proof.
+	var blob eth.Blob
+	keyInput := make([]byte, blobKeyInputLength)
+	copy(keyInput, commitment[:])
+	for i, root := range eth.BlobRootsOfUnity() {
+		copy(keyInput[48:], root[:])
+		key := preimage.BlobKey(crypto.Keccak256Hash(keyInput)).PreimageKey()
+		fieldElement, err := l.getPreimage(key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load blob field element %v: %w", i, err)
+		}
+		if len(fieldElement) != 32 {
+			return nil, fmt.Errorf("%w: field element %v has length %v", ErrInvalidBlobKeyPreimage, i, len(fieldElement))
+		}
+		copy(blob[i*32:], fieldElement)
+	}
+	kzgProof, claim, err := kzg4844.ComputeProof(*blob.KZGBlob(), point)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute kzg proof: %w", err)
+	}
+	if err := kzg4844.VerifyProof(commitment, point, claim, kzgProof); err != nil {
+		return nil, fmt.Errorf("%w: blob does not match its commitment: %w", ErrInvalidBlobKeyPreimage, err)
+	}
+	if len(proof.OracleValue) < 8 || !bytes.Equal(claim[:], proof.OracleValue[8:]) {
+		return nil, fmt.Errorf("%w: oracle value does not match the blob field element", ErrInvalidBlobKeyPreimage)
+	}
+	return types.NewPreimageOracleBlobData(proof.OracleKey, proof.OracleValue, proof.OracleOffset, point[:], commitment[:], kzgProof[:]), nil
+}
@@ -0,0 +1,124 @@
+package cannon
+
+import (
+	"encoding/binary"
+	"fmt"
+	"testing"
+
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
+	preimage "github.com/ethereum-optimism/optimism/op-preimage"
+	"github.com/ethereum-optimism/optimism/op-program/host/kvstore"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreimageLoader_NoPreimage(t *testing.T) {
+	loader := newPreimageLoader(kvstore.NewMemKV().Get)
+	actual, err := loader.LoadPreimage(&mipsevm.Proof{})
+	require.NoError(t, err)
+	require.Nil(t, actual)
+}
+
+func TestPreimageLoader_SimpleTypes(t *testing.T) {
+	tests := []preimage.Key{
+		preimage.LocalIndexKey(4),
+		preimage.Keccak256Key{0xaa, 0xbb},
+		preimage.Sha256Key{0xcc, 0xdd},
+	}
+	for _, key := range tests {
+		key := key
+		t.Run(fmt.Sprintf("%T", key), func(t *testing.T) {
+			loader := newPreimageLoader(kvstore.NewMemKV().Get)
+			proof := &mipsevm.Proof{
+				OracleKey:    common.Hash(key.PreimageKey()).Bytes(),
+				OracleValue:  []byte{1, 2, 3, 4, 5, 6},
+				OracleOffset: 3,
+			}
+			actual, err := loader.LoadPreimage(proof)
+			require.NoError(t, err)
+			expected := types.NewPreimageOracleData(0, proof.OracleKey, proof.OracleValue, proof.OracleOffset)
+			require.Equal(t, expected, actual)
+		})
+	}
+}
+
+func TestPreimageLoader_BlobPreimage(t *testing.T) {
+	var blob eth.Blob
+	require.NoError(t, blob.FromData([]byte("blob data")))
+	commitment, err := kzg4844.BlobToCommitment(*blob.KZGBlob())
+	require.NoError(t, err)
+
+	// Read the field element at index 1, which holds the start of the data.
+	const fieldIndex = 1
+	root := eth.BlobRootsOfUnity()[fieldIndex]
+	keyInput := append(append([]byte{}, commitment[:]...), root[:]...)
+	blobKey := crypto.Keccak256Hash(keyInput)
+	oracleValue := binary.BigEndian.AppendUint64(nil, 32)
+	oracleValue = append(oracleValue, blob[fieldIndex*32:(fieldIndex+1)*32]...)
+	proof := &mipsevm.Proof{
+		OracleKey:    common.Hash(preimage.BlobKey(blobKey).PreimageKey()).Bytes(),
+		OracleValue:  oracleValue,
+		OracleOffset: 4,
+	}
+
+	storeBlob := func(kv kvstore.KV, blob *eth.Blob) {
+		for i, root := range eth.BlobRootsOfUnity() {
+			key := preimage.BlobKey(crypto.Keccak256Hash(commitment[:], root[:])).PreimageKey()
+			require.NoError(t, kv.Put(key, blob[i*32:(i+1)*32]))
+		}
+	}
+
+	t.Run("NoKeyPreimage", func(t *testing.T) {
+		kv := kvstore.NewMemKV()
+		loader := newPreimageLoader(kv.Get)
+		_, err := loader.LoadPreimage(proof)
+		require.ErrorIs(t, err, kvstore.ErrNotFound)
+	})
+
+	t.Run("InvalidKeyPreimage", func(t *testing.T) {
+		kv := kvstore.NewMemKV()
+		require.NoError(t, kv.Put(preimage.Keccak256Key(blobKey).PreimageKey(), []byte{1, 2}))
+		loader := newPreimageLoader(kv.Get)
+		_, err := loader.LoadPreimage(proof)
+		require.ErrorIs(t, err, ErrInvalidBlobKeyPreimage)
+	})
+
+	t.Run("MissingBlobs", func(t *testing.T) {
+		kv := kvstore.NewMemKV()
+		require.NoError(t, kv.Put(preimage.Keccak256Key(blobKey).PreimageKey(), keyInput))
+		loader := newPreimageLoader(kv.Get)
+		_, err := loader.LoadPreimage(proof)
+		require.ErrorIs(t, err, kvstore.ErrNotFound)
+	})
+
+	t.Run("MismatchedBlob", func(t *testing.T) {
+		kv := kvstore.NewMemKV()
+		require.NoError(t, kv.Put(preimage.Keccak256Key(blobKey).PreimageKey(), keyInput))
+		var other eth.Blob
+		require.NoError(t, other.FromData([]byte("other data")))
+		storeBlob(kv, &other)
+		loader := newPreimageLoader(kv.Get)
+		_, err := loader.LoadPreimage(proof)
+		require.ErrorIs(t, err, ErrInvalidBlobKeyPreimage)
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		kv := kvstore.NewMemKV()
+		require.NoError(t, kv.Put(preimage.Keccak256Key(blobKey).PreimageKey(), keyInput))
+		storeBlob(kv, &blob)
+		loader := newPreimageLoader(kv.Get)
+		actual, err := loader.LoadPreimage(proof)
+		require.NoError(t, err)
+
+		kzgProof, claim, err := kzg4844.ComputeProof(*blob.KZGBlob(), kzg4844.Point(root))
+		require.NoError(t, err)
+		require.EqualValues(t, blob[fieldIndex*32:(fieldIndex+1)*32], claim[:])
+		expected := types.NewPreimageOracleBlobData(proof.OracleKey, proof.OracleValue, proof.OracleOffset, root[:], commitment[:], kzgProof[:])
+		require.Equal(t, expected, actual)
+		require.NoError(t, kzg4844.VerifyProof(commitment, kzg4844.Point(root), claim, kzgProof))
+	})
+}
@@ -7,10 +7,12 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 
 	"github.com/ethereum-optimism/optimism/op-bindings/bindings"
 	"github.com/ethereum-optimism/optimism/op-challenger/config"
 	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
+	"github.com/ethereum-optimism/optimism/op-program/host/kvstore"
 	"github.com/ethereum-optimism/optimism/op-service/ioutil"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
@@ -19,21 +21,18 @@ import (
 	"github.com/ethereum/go-ethereum/log"
 
 	"github.com/ethereum-optimism/optimism/cannon/mipsevm"
+	"github.com/ethereum-optimism/optimism/cannon/serialize"
 )
 
 const (
 	proofsDir      = "proofs"
 	diskStateCache = "state.json.gz"
-)
 
-type proofData struct {
-	ClaimValue   common.Hash   `json:"post"`
-	StateData    hexutil.Bytes `json:"state-data"`
-	ProofData    hexutil.Bytes `json:"proof-data"`
-	OracleKey    hexutil.Bytes `json:"oracle-key,omitempty"`
-	OracleValue  hexutil.Bytes `json:"oracle-value,omitempty"`
-	OracleOffset uint32        `json:"oracle-offset,omitempty"`
-}
+	// proofExt is the extension of the binary proofs written by cannon.
+	proofExt = ".bin.gz"
+	// legacyProofExt is the extension of the JSON proofs written by earlier versions.
+	legacyProofExt = ".json.gz"
+)
 
 type CannonMetricer interface {
 	RecordCannonExecutionTime(t float64)
@@ -51,12 +50,21 @@ type CannonTraceProvider struct {
 	generator ProofGenerator
 	gameDepth uint64
 
+	preimageLoader *preimageLoader
+
+	// genLock is held while loading or generating proofs, so games that share the trace in dir do not
+	// execute cannon concurrently.
+	genLock *sync.Mutex
+
 	// lastStep stores the last step in the actual trace if known. 0 indicates unknown.
 	// Cached as an optimisation to avoid repeatedly attempting to execute beyond the end of the trace.
 	lastStep uint64
 }
 
-func NewTraceProvider(ctx context.Context, logger log.Logger, m CannonMetricer, cfg *config.Config, l1Client bind.ContractCaller, dir string, gameAddr common.Address, gameDepth uint64) (*CannonTraceProvider, error) {
+// NewTraceProvider creates a trace provider for the game at gameAddr.
+// If cache is not nil, the trace is shared with other games with the same local inputs and absolute pre-state,
+// otherwise the trace is generated in dir.
+func NewTraceProvider(ctx context.Context, logger log.Logger, m CannonMetricer, cfg *config.Config, cache *TraceCache, l1Client bind.ContractCaller, dir string, gameAddr common.Address, gameDepth uint64) (*CannonTraceProvider, error) {
 	l2Client, err := ethclient.DialContext(ctx, cfg.CannonL2)
 	if err != nil {
 		return nil, fmt.Errorf("dial l2 client %v: %w", cfg.CannonL2, err)
@@ -70,7 +78,21 @@ func NewTraceProvider(ctx context.Context, logger log.Logger, m CannonMetricer,
 	if err != nil {
 		return nil, fmt.Errorf("fetch local game inputs: %w", err)
 	}
-	return NewTraceProviderFromInputs(logger, m, cfg, localInputs, dir, gameDepth), nil
+	if cache == nil {
+		return NewTraceProviderFromInputs(logger, m, cfg, localInputs, dir, gameDepth), nil
+	}
+	prestate, err := prestateHash(cfg.CannonAbsolutePreState)
+	if err != nil {
+		return nil, err
+	}
+	traceDir, genLock, err := cache.TraceDir(dir, TraceKey(localInputs, prestate))
+	if err != nil {
+		return nil, fmt.Errorf("find trace directory: %w", err)
+	}
+	logger.Debug("Using cannon trace directory", "dir", traceDir)
+	provider := NewTraceProviderFromInputs(logger, m, cfg, localInputs, traceDir, gameDepth)
+	provider.genLock = genLock
+	return provider, nil
 }
 
 func NewTraceProviderFromInputs(logger log.Logger, m CannonMetricer, cfg *config.Config, localInputs LocalGameInputs, dir string, gameDepth uint64) *CannonTraceProvider {
@@ -80,6 +102,9 @@ func NewTraceProviderFromInputs(logger log.Logger, m CannonMetricer, cfg *config
 		prestate:  cfg.CannonAbsolutePreState,
 		generator: NewExecutor(logger, m, cfg, localInputs),
 		gameDepth: gameDepth,
+		genLock:   &sync.Mutex{},
+
+		preimageLoader: newPreimageLoader(kvstore.NewDiskKV(filepath.Join(dir, preimagesDir)).Get),
 	}
 }
 
@@ -96,7 +121,7 @@ func (p *CannonTraceProvider) Get(ctx context.Context, pos types.Position) (comm
 	if err != nil {
 		return common.Hash{}, err
 	}
-	value := proof.ClaimValue
+	value := proof.Post
 
 	if value == (common.Hash{}) {
 		return common.Hash{}, errors.New("proof missing post hash")
@@ -121,10 +146,9 @@ func (p *CannonTraceProvider) GetStepData(ctx context.Context, pos types.Positio
 	if data == nil {
 		return nil, nil, nil, errors.New("proof missing proof data")
 	}
-	var oracleData *types.PreimageOracleData
-	if len(proof.OracleKey) > 0 {
-		// TODO(client-pod#104): Replace the LocalContext `0` argument below with the correct local context.
-		oracleData = types.NewPreimageOracleData(0, proof.OracleKey, proof.OracleValue, proof.OracleOffset)
+	oracleData, err := p.preimageLoader.LoadPreimage(proof)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to load preimage: %w", err)
 	}
 	return value, data, oracleData, nil
 }
@@ -151,7 +175,9 @@ func (p *CannonTraceProvider) AbsolutePreStateCommitment(ctx context.Context) (c
 
 // loadProof will attempt to load or generate the proof data at the specified index
 // If the requested index is beyond the end of the actual trace it is extended with no-op instructions.
-func (p *CannonTraceProvider) loadProof(ctx context.Context, i uint64) (*proofData, error) {
+func (p *CannonTraceProvider) loadProof(ctx context.Context, i uint64) (*mipsevm.Proof, error) {
+	p.genLock.Lock()
+	defer p.genLock.Unlock()
 	// Attempt to read the last step from disk cache
 	if p.lastStep == 0 {
 		step, err := readLastStep(p.dir)
@@ -165,17 +191,18 @@ func (p *CannonTraceProvider) loadProof(ctx context.Context, i uint64) (*proofDa
 	if p.lastStep != 0 && i > p.lastStep {
 		i = p.lastStep
 	}
-	path := filepath.Join(p.dir, proofsDir, fmt.Sprintf("%d.json.gz", i))
+	path := proofPath(p.dir, i)
 	file, err := ioutil.OpenDecompressed(path)
 	if errors.Is(err, os.ErrNotExist) {
 		if err := p.generator.GenerateProof(ctx, p.dir, i); err != nil {
 			return nil, fmt.Errorf("generate cannon trace with proof at %v: %w", i, err)
 		}
 		// Try opening the file again now and it should exist.
+		path = proofPath(p.dir, i)
 		file, err = ioutil.OpenDecompressed(path)
 		if errors.Is(err, os.ErrNotExist) {
 			// Expected proof wasn't generated, check if we reached the end of execution
-			state, err := parseState(filepath.Join(p.dir, finalState))
+			state, err := parseState(finalStatePath(p.dir))
 			if err != nil {
 				return nil, fmt.Errorf("cannot read final state: %w", err)
 			}
@@ -191,8 +218,8 @@ func (p *CannonTraceProvider) loadProof(ctx context.Context, i uint64) (*proofDa
 				if err != nil {
 					return nil, fmt.Errorf("cannot hash witness: %w", err)
 				}
-				proof := &proofData{
-					ClaimValue:   witnessHash,
+				proof := &mipsevm.Proof{
+					Post:         witnessHash,
 					StateData:    hexutil.Bytes(witness),
 					ProofData:    []byte{},
 					OracleKey:    nil,
@@ -212,14 +239,40 @@ func (p *CannonTraceProvider) loadProof(ctx context.Context, i uint64) (*proofDa
 		return nil, fmt.Errorf("cannot open proof file (%v): %w", path, err)
 	}
 	defer file.Close()
-	var proof proofData
-	err = json.NewDecoder(file).Decode(&proof)
+	var proof mipsevm.Proof
+	err = serialize.Read(file, &proof)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read proof (%v): %w", path, err)
 	}
 	return &proof, nil
 }
 
+// proofPath returns the path of the proof at step i. Proofs are generated in the binary format,
+// but a JSON proof that was generated by an earlier version is used if no binary proof exists.
+func proofPath(dir string, i uint64) string {
+	path := filepath.Join(dir, proofsDir, fmt.Sprintf("%d%s", i, proofExt))
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		legacyPath := filepath.Join(dir, proofsDir, fmt.Sprintf("%d%s", i, legacyProofExt))
+		if _, err := os.Stat(legacyPath); err == nil {
+			return legacyPath
+		}
+	}
+	return path
+}
+
+// finalStatePath returns the path of the final state. Like proofs, the final state is written in the binary
+// format, but a JSON final state that was written by an earlier version is used if no binary final state exists.
+func finalStatePath(dir string) string {
+	path := filepath.Join(dir, finalState)
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		legacyPath := filepath.Join(dir, legacyFinalState)
+		if _, err := os.Stat(legacyPath); err == nil {
+			return legacyPath
+		}
+	}
+	return path
+}
+
 type diskStateCacheObj struct {
 	Step uint64 `json:"step"`
 }
@@ -240,13 +293,13 @@ func readLastStep(dir string) (uint64, error) {
 }
 
 // writeLastStep writes the last step and proof to disk as a persistent cache.
-func writeLastStep(dir string, proof *proofData, step uint64) error {
+func writeLastStep(dir string, proof *mipsevm.Proof, step uint64) error {
 	state := diskStateCacheObj{Step: step}
 	lastStepFile := filepath.Join(dir, diskStateCache)
 	if err := ioutil.WriteCompressedJson(lastStepFile, state); err != nil {
 		return fmt.Errorf("failed to write last step to %v: %w", lastStepFile, err)
 	}
-	if err := ioutil.WriteCompressedJson(filepath.Join(dir, proofsDir, fmt.Sprintf("%d.json.gz", step)), proof); err != nil {
+	if err := serialize.Write(filepath.Join(dir, proofsDir, fmt.Sprintf("%d%s", step, proofExt)), proof); err != nil {
 		return fmt.Errorf("failed to write proof: %w", err)
 	}
 	return nil
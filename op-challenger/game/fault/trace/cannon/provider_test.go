@@ -4,16 +4,18 @@ import (
 	"context"
 	"embed"
 	_ "embed"
-	"encoding/json"
 	"fmt"
 	"math"
 	"math/big"
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
 
 	"github.com/ethereum-optimism/optimism/cannon/mipsevm"
+	"github.com/ethereum-optimism/optimism/cannon/serialize"
 	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
+	"github.com/ethereum-optimism/optimism/op-program/host/kvstore"
 	"github.com/ethereum-optimism/optimism/op-service/ioutil"
 	"github.com/ethereum-optimism/optimism/op-service/testlog"
 	"github.com/ethereum/go-ethereum/common"
@@ -110,8 +112,8 @@ func TestGetStepData(t *testing.T) {
 			Step:   10,
 			Exited: true,
 		}
-		generator.proof = &proofData{
-			ClaimValue:   common.Hash{0xaa},
+		generator.proof = &mipsevm.Proof{
+			Post:         common.Hash{0xaa},
 			StateData:    []byte{0xbb},
 			ProofData:    []byte{0xcc},
 			OracleKey:    common.Hash{0xdd}.Bytes(),
@@ -136,8 +138,8 @@ func TestGetStepData(t *testing.T) {
 			Step:   10,
 			Exited: true,
 		}
-		generator.proof = &proofData{
-			ClaimValue:   common.Hash{0xaa},
+		generator.proof = &mipsevm.Proof{
+			Post:         common.Hash{0xaa},
 			StateData:    []byte{0xbb},
 			ProofData:    []byte{0xcc},
 			OracleKey:    common.Hash{0xdd}.Bytes(),
@@ -154,6 +156,25 @@ func TestGetStepData(t *testing.T) {
 		require.Nil(t, data)
 	})
 
+	t.Run("ProofAfterEndOfTraceWithLegacyFinalState", func(t *testing.T) {
+		dataDir, prestate := setupTestData(t)
+		provider, generator := setupWithTestData(t, dataDir, prestate)
+		// The final state was written in JSON by an earlier version, and isn't generated again
+		state := &mipsevm.State{
+			Memory: &mipsevm.Memory{},
+			Step:   10,
+			Exited: true,
+		}
+		require.NoError(t, serialize.Write(filepath.Join(dataDir, legacyFinalState), state))
+		preimage, proof, data, err := provider.GetStepData(context.Background(), PositionFromTraceIndex(provider, big.NewInt(7000)))
+		require.NoError(t, err)
+		require.Contains(t, generator.generated, 7000, "should have tried to generate the proof")
+
+		require.EqualValues(t, state.EncodeWitness(), preimage)
+		require.Equal(t, []byte{}, proof)
+		require.Nil(t, data)
+	})
+
 	t.Run("ReadLastStepFromDisk", func(t *testing.T) {
 		dataDir, prestate := setupTestData(t)
 		provider, initGenerator := setupWithTestData(t, dataDir, prestate)
@@ -162,8 +183,8 @@ func TestGetStepData(t *testing.T) {
 			Step:   10,
 			Exited: true,
 		}
-		initGenerator.proof = &proofData{
-			ClaimValue:   common.Hash{0xaa},
+		initGenerator.proof = &mipsevm.Proof{
+			Post:         common.Hash{0xaa},
 			StateData:    []byte{0xbb},
 			ProofData:    []byte{0xcc},
 			OracleKey:    common.Hash{0xdd}.Bytes(),
@@ -180,10 +201,10 @@ func TestGetStepData(t *testing.T) {
 			Step:   10,
 			Exited: true,
 		}
-		generator.proof = &proofData{
-			ClaimValue: common.Hash{0xaa},
-			StateData:  []byte{0xbb},
-			ProofData:  []byte{0xcc},
+		generator.proof = &mipsevm.Proof{
+			Post:      common.Hash{0xaa},
+			StateData: []byte{0xbb},
+			ProofData: []byte{0xcc},
 		}
 		preimage, proof, data, err := provider.GetStepData(context.Background(), PositionFromTraceIndex(provider, big.NewInt(7000)))
 		require.NoError(t, err)
@@ -266,6 +287,8 @@ func setupPreState(t *testing.T, dataDir string, filename string) {
 	require.NoErrorf(t, err, "writing %v", path)
 }
 
+// setupTestData copies the test proofs into a new data dir. The test proofs are JSON proofs,
+// like the proofs that were generated by earlier versions.
 func setupTestData(t *testing.T) (string, string) {
 	srcDir := filepath.Join("test_data", "proofs")
 	entries, err := testData.ReadDir(srcDir)
@@ -290,32 +313,27 @@ func setupWithTestData(t *testing.T, dataDir string, prestate string) (*CannonTr
 		generator: generator,
 		prestate:  filepath.Join(dataDir, prestate),
 		gameDepth: 63,
+		genLock:   &sync.Mutex{},
+
+		preimageLoader: newPreimageLoader(kvstore.NewMemKV().Get),
 	}, generator
 }
 
 type stubGenerator struct {
 	generated  []int // Using int makes assertions easier
 	finalState *mipsevm.State
-	proof      *proofData
+	proof      *mipsevm.Proof
 }
 
 func (e *stubGenerator) GenerateProof(ctx context.Context, dir string, i uint64) error {
 	e.generated = append(e.generated, int(i))
 	if e.finalState != nil && e.finalState.Step <= i {
 		// Requesting a trace index past the end of the trace
-		data, err := json.Marshal(e.finalState)
-		if err != nil {
-			return err
-		}
-		return writeGzip(filepath.Join(dir, finalState), data)
+		return serialize.Write(filepath.Join(dir, finalState), e.finalState)
 	}
 	if e.proof != nil {
-		proofFile := filepath.Join(dir, proofsDir, fmt.Sprintf("%d.json.gz", i))
-		data, err := json.Marshal(e.proof)
-		if err != nil {
-			return err
-		}
-		return writeGzip(proofFile, data)
+		proofFile := filepath.Join(dir, proofsDir, fmt.Sprintf("%d%s", i, proofExt))
+		return serialize.Write(proofFile, e.proof)
 	}
 	return nil
 }
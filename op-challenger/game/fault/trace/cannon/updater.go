@@ -7,6 +7,7 @@ import (
 
 	"github.com/ethereum-optimism/optimism/op-bindings/bindings"
 	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
+	preimage "github.com/ethereum-optimism/optimism/op-preimage"
 	"github.com/ethereum-optimism/optimism/op-service/txmgr"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 
@@ -108,7 +109,7 @@ func (u *cannonUpdater) sendGlobalOracleData(ctx context.Context, data *types.Pr
 	if err != nil {
 		return fmt.Errorf("global oracle tx data build: %w", err)
 	}
-	return u.sendTxAndWait(ctx, u.fdgAddr, txData)
+	return u.sendTxAndWait(ctx, u.preimageOracleAddr, txData)
 }
 
 // BuildLocalOracleData takes the local preimage key and data
@@ -125,13 +126,32 @@ func (u *cannonUpdater) BuildLocalOracleData(data *types.PreimageOracleData) ([]
 
 // BuildGlobalOracleData takes the global preimage key and data
 // and creates tx data to load the key, data pair into the
-// PreimageOracle contract.
+// PreimageOracle contract. The loader used depends on the key type,
+// so the contract can verify the pre-image against its key.
 func (u *cannonUpdater) BuildGlobalOracleData(data *types.PreimageOracleData) ([]byte, error) {
-	return u.preimageOracleAbi.Pack(
-		"loadKeccak256PreimagePart",
-		big.NewInt(int64(data.OracleOffset)),
-		data.GetPreimageWithoutSize(),
-	)
+	switch preimage.KeyType(data.OracleKey[0]) {
+	case preimage.Sha256KeyType:
+		return u.preimageOracleAbi.Pack(
+			"loadSha256PreimagePart",
+			big.NewInt(int64(data.OracleOffset)),
+			data.GetPreimageWithoutSize(),
+		)
+	case preimage.BlobKeyType:
+		return u.preimageOracleAbi.Pack(
+			"loadBlobPreimagePart",
+			new(big.Int).SetBytes(data.BlobPoint),
+			new(big.Int).SetBytes(data.GetPreimageWithoutSize()),
+			data.BlobCommitment,
+			data.BlobProof,
+			big.NewInt(int64(data.OracleOffset)),
+		)
+	default:
+		return u.preimageOracleAbi.Pack(
+			"loadKeccak256PreimagePart",
+			big.NewInt(int64(data.OracleOffset)),
+			data.GetPreimageWithoutSize(),
+		)
+	}
 }
 
 // sendTxAndWait sends a transaction through the [txmgr] and waits for a receipt.
@@ -30,6 +30,7 @@ var (
 type mockTxManager struct {
 	from        common.Address
 	sends       int
+	sentTo      []common.Address
 	failedSends int
 	sendFails   bool
 }
@@ -40,6 +41,7 @@ func (m *mockTxManager) Send(ctx context.Context, candidate txmgr.TxCandidate) (
 		return nil, mockSendError
 	}
 	m.sends++
+	m.sentTo = append(m.sentTo, *candidate.To)
 	return ethtypes.NewReceipt(
 		[]byte{},
 		false,
@@ -80,6 +82,17 @@ func TestCannonUpdater_UpdateOracle(t *testing.T) {
 			OracleData: common.Hex2Bytes("cccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccc"),
 		}))
 		require.Equal(t, 1, mockTxMgr.sends)
+		require.Equal(t, []common.Address{mockPreimageOracleAddress}, mockTxMgr.sentTo)
+	})
+
+	t.Run("local data sent to game", func(t *testing.T) {
+		updater, mockTxMgr := newTestCannonUpdater(t, false)
+		require.NoError(t, updater.UpdateOracle(context.Background(), &types.PreimageOracleData{
+			IsLocal:    true,
+			OracleKey:  common.Hash{0x01}.Bytes(),
+			OracleData: common.Hex2Bytes("cccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccc"),
+		}))
+		require.Equal(t, []common.Address{mockFdgAddress}, mockTxMgr.sentTo)
 	})
 
 	t.Run("send fails", func(t *testing.T) {
@@ -144,3 +157,65 @@ func TestCannonUpdater_BuildGlobalOracleData(t *testing.T) {
 
 	require.Equal(t, expected, txData)
 }
+
+// TestCannonUpdater_BuildGlobalOracleData_Sha256 tests the [cannonUpdater]
+// loads sha256 pre-images with the sha256 loader.
+func TestCannonUpdater_BuildGlobalOracleData_Sha256(t *testing.T) {
+	updater, _ := newTestCannonUpdater(t, false)
+	oracleData := &types.PreimageOracleData{
+		OracleKey:    common.Hex2Bytes("04aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"),
+		OracleData:   common.Hex2Bytes("cccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccc"),
+		OracleOffset: 7,
+	}
+
+	txData, err := updater.BuildGlobalOracleData(oracleData)
+	require.NoError(t, err)
+
+	var loadSha256PreimagePartBytes4 = crypto.Keccak256([]byte("loadSha256PreimagePart(uint256,bytes)"))[:4]
+
+	// Pack the tx data manually.
+	var expected []byte
+	expected = append(expected, loadSha256PreimagePartBytes4...)
+	expected = append(expected, common.Hex2Bytes("0000000000000000000000000000000000000000000000000000000000000007")...)
+	expected = append(expected, common.Hex2Bytes("0000000000000000000000000000000000000000000000000000000000000040")...)
+	expected = append(expected, common.Hex2Bytes("0000000000000000000000000000000000000000000000000000000000000018")...)
+	expected = append(expected, common.Hex2Bytes("cccccccccccccccccccccccccccccccccccccccccccccccc0000000000000000")...)
+
+	require.Equal(t, expected, txData)
+}
+
+// TestCannonUpdater_BuildGlobalOracleData_Blob tests the [cannonUpdater]
+// loads blob field elements with the blob loader.
+func TestCannonUpdater_BuildGlobalOracleData_Blob(t *testing.T) {
+	updater, _ := newTestCannonUpdater(t, false)
+	oracleData := types.NewPreimageOracleBlobData(
+		common.Hex2Bytes("05aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"),
+		common.Hex2Bytes("0000000000000020cccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccc"),
+		7,
+		common.Hex2Bytes("dddddddddddddddddddddddddddddddddddddddddddddddddddddddddddddddd"),
+		common.Hex2Bytes("eeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee"),
+		common.Hex2Bytes("ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"),
+	)
+
+	txData, err := updater.BuildGlobalOracleData(oracleData)
+	require.NoError(t, err)
+
+	var loadBlobPreimagePartBytes4 = crypto.Keccak256([]byte("loadBlobPreimagePart(uint256,uint256,bytes,bytes,uint256)"))[:4]
+
+	// Pack the tx data manually.
+	var expected []byte
+	expected = append(expected, loadBlobPreimagePartBytes4...)
+	expected = append(expected, common.Hex2Bytes("dddddddddddddddddddddddddddddddddddddddddddddddddddddddddddddddd")...)
+	expected = append(expected, common.Hex2Bytes("cccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccc")...)
+	expected = append(expected, common.Hex2Bytes("00000000000000000000000000000000000000000000000000000000000000a0")...)
+	expected = append(expected, common.Hex2Bytes("0000000000000000000000000000000000000000000000000000000000000100")...)
+	expected = append(expected, common.Hex2Bytes("0000000000000000000000000000000000000000000000000000000000000007")...)
+	expected = append(expected, common.Hex2Bytes("0000000000000000000000000000000000000000000000000000000000000030")...)
+	expected = append(expected, common.Hex2Bytes("eeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee")...)
+	expected = append(expected, common.Hex2Bytes("eeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee00000000000000000000000000000000")...)
+	expected = append(expected, common.Hex2Bytes("0000000000000000000000000000000000000000000000000000000000000030")...)
+	expected = append(expected, common.Hex2Bytes("ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff")...)
+	expected = append(expected, common.Hex2Bytes("ffffffffffffffffffffffffffffffff00000000000000000000000000000000")...)
+
+	require.Equal(t, expected, txData)
+}
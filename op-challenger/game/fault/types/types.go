@@ -3,6 +3,7 @@ package types
 import (
 	"context"
 	"errors"
+	"math"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -20,6 +21,12 @@ type PreimageOracleData struct {
 	OracleKey    []byte
 	OracleData   []byte
 	OracleOffset uint32
+
+	// Blob pre-image data, only set for blob field element keys.
+	// The field element is the evaluation of the blob polynomial at BlobPoint.
+	BlobPoint      []byte
+	BlobCommitment []byte
+	BlobProof      []byte
 }
 
 // GetIdent returns the ident for the preimage oracle data.
@@ -43,6 +50,20 @@ func NewPreimageOracleData(lctx uint64, key []byte, data []byte, offset uint32)
 	}
 }
 
+// NewPreimageOracleBlobData creates a new [PreimageOracleData] instance for a blob field element,
+// along with the KZG commitment and proof to verify it against on chain.
+func NewPreimageOracleBlobData(key []byte, data []byte, offset uint32, point []byte, commitment []byte, proof []byte) *PreimageOracleData {
+	return &PreimageOracleData{
+		IsLocal:        false,
+		OracleKey:      key,
+		OracleData:     data,
+		OracleOffset:   offset,
+		BlobPoint:      point,
+		BlobCommitment: commitment,
+		BlobProof:      proof,
+	}
+}
+
 // StepCallData encapsulates the data needed to perform a step.
 type StepCallData struct {
 	ClaimIndex uint64
@@ -100,13 +121,29 @@ type Claim struct {
 	//       When caching is implemented for the Challenger, this will need
 	//       to be changed/removed to avoid invalid/stale contract state.
 	Countered bool
-	Clock     uint64
+	Clock     Clock
 	// Location of the claim & it's parent inside the contract. Does not exist
 	// for claims that have not made it to the contract.
 	ContractIndex       int
 	ParentContractIndex int
 }
 
+// Clock is the chess clock of a claim, packed onchain by LibClock as (duration << 64) | timestamp.
+type Clock struct {
+	// Duration is the time in seconds used by the claimant's team when the claim was made.
+	Duration uint64
+	// Timestamp is the time the claim was made, in seconds since the unix epoch.
+	Timestamp uint64
+}
+
+// NewClockFromPacked unpacks a [Clock] from the onchain representation.
+func NewClockFromPacked(packed *big.Int) Clock {
+	return Clock{
+		Duration:  new(big.Int).Rsh(packed, 64).Uint64(),
+		Timestamp: new(big.Int).And(packed, new(big.Int).SetUint64(math.MaxUint64)).Uint64(),
+	}
+}
+
 // IsRoot returns true if this claim is the root claim.
 func (c *Claim) IsRoot() bool {
 	return c.Position.IsRootPosition()
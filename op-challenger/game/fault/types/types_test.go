@@ -25,6 +25,24 @@ func TestNewPreimageOracleData(t *testing.T) {
 		require.Equal(t, []byte{4, 5, 6}, data.OracleData)
 		require.Equal(t, uint32(7), data.OracleOffset)
 	})
+
+	t.Run("BlobData", func(t *testing.T) {
+		data := NewPreimageOracleBlobData([]byte{5, 2, 3}, []byte{4, 5, 6}, 7, []byte{8}, []byte{9}, []byte{10})
+		require.False(t, data.IsLocal)
+		require.Equal(t, []byte{5, 2, 3}, data.OracleKey)
+		require.Equal(t, []byte{4, 5, 6}, data.OracleData)
+		require.Equal(t, uint32(7), data.OracleOffset)
+		require.Equal(t, []byte{8}, data.BlobPoint)
+		require.Equal(t, []byte{9}, data.BlobCommitment)
+		require.Equal(t, []byte{10}, data.BlobProof)
+	})
+}
+
+func TestNewClockFromPacked(t *testing.T) {
+	packed := new(big.Int).Lsh(big.NewInt(300), 64)
+	packed.Or(packed, big.NewInt(1_700_000_000))
+	require.Equal(t, Clock{Duration: 300, Timestamp: 1_700_000_000}, NewClockFromPacked(packed))
+	require.Equal(t, Clock{}, NewClockFromPacked(big.NewInt(0)))
 }
 
 func TestIsRootPosition(t *testing.T) {
@@ -63,7 +63,11 @@ func (l *GameLoader) FetchAllGamesAtBlock(ctx context.Context, earliestTimestamp
 		if game.Timestamp < earliestTimestamp {
 			break
 		}
-		games = append(games, game)
+		games = append(games, types.GameMetadata{
+			GameType:  game.GameType,
+			Timestamp: game.Timestamp,
+			Proxy:     game.Proxy,
+		})
 	}
 
 	return games, nil
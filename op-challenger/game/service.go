@@ -8,6 +8,7 @@ import (
 	"github.com/ethereum-optimism/optimism/op-bindings/bindings"
 	"github.com/ethereum-optimism/optimism/op-challenger/config"
 	"github.com/ethereum-optimism/optimism/op-challenger/game/fault"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/trace/cannon"
 	"github.com/ethereum-optimism/optimism/op-challenger/game/loader"
 	"github.com/ethereum-optimism/optimism/op-challenger/game/registry"
 	"github.com/ethereum-optimism/optimism/op-challenger/game/scheduler"
@@ -28,6 +29,8 @@ type Service struct {
 	monitor *gameMonitor
 	sched   *scheduler.Scheduler
 
+	l1Client *dial.EthClient
+
 	pprofSrv   *httputil.HTTPServer
 	metricsSrv *httputil.HTTPServer
 }
@@ -43,6 +46,9 @@ func (s *Service) Stop(ctx context.Context) error {
 	if s.metricsSrv != nil {
 		result = errors.Join(result, s.metricsSrv.Stop(ctx))
 	}
+	if s.l1Client != nil {
+		s.l1Client.Close()
+	}
 	return result
 }
 
@@ -55,14 +61,15 @@ func NewService(ctx context.Context, logger log.Logger, cfg *config.Config) (*Se
 		return nil, fmt.Errorf("failed to create the transaction manager: %w", err)
 	}
 
-	l1Client, err := dial.DialEthClientWithTimeout(ctx, dial.DefaultDialTimeout, logger, cfg.L1EthRpc)
+	l1Client, err := dial.DialMultiEthClientWithTimeout(ctx, dial.DefaultDialTimeout, logger, cfg.L1EthRpc, cfg.L1RPCConfig.MultiRPCConfig(), m)
 	if err != nil {
 		return nil, fmt.Errorf("failed to dial L1: %w", err)
 	}
 
 	s := &Service{
-		logger:  logger,
-		metrics: m,
+		logger:   logger,
+		metrics:  m,
+		l1Client: l1Client,
 	}
 
 	pprofConfig := cfg.PprofConfig
@@ -85,31 +92,45 @@ func NewService(ctx context.Context, logger log.Logger, cfg *config.Config) (*Se
 		}
 		logger.Info("started metrics server", "addr", metricsSrv.Addr())
 		s.metricsSrv = metricsSrv
-		m.StartBalanceMetrics(ctx, logger, l1Client, txMgr.From())
+		m.StartBalanceMetrics(ctx, logger, l1Client.Client, txMgr.From())
 	}
 
-	factoryContract, err := bindings.NewDisputeGameFactory(cfg.GameFactoryAddress, l1Client)
-	if err != nil {
-		return nil, errors.Join(fmt.Errorf("failed to bind the fault dispute game factory contract: %w", err), s.Stop(ctx))
-	}
-	loader := loader.NewGameLoader(factoryContract)
+	// Games of all chains share the scheduler and its worker pool. Game addresses are unique across chains
+	// since all the dispute game factories are on the same L1, so they also share the data directory.
+	// Cannon traces are shared between all games with the same inputs, including games of different chains.
+	traceCache := cannon.NewTraceCache(cfg.Datadir)
+	var sources multiChainSource
+	registries := make(chainRegistries)
+	for _, chain := range cfg.ChainConfigs() {
+		chainCfg := cfg.ForChain(chain)
+		chainLogger := logger
+		if len(cfg.Chains) > 0 {
+			chainLogger = logger.New("chain", chain.Name)
+		}
+		factoryContract, err := bindings.NewDisputeGameFactory(chain.GameFactoryAddress, l1Client)
+		if err != nil {
+			return nil, errors.Join(fmt.Errorf("failed to bind the fault dispute game factory contract of chain %v: %w", chain.Name, err), s.Stop(ctx))
+		}
+		sources = append(sources, chainGameSource{chain: chain.Name, source: loader.NewGameLoader(factoryContract)})
 
-	gameTypeRegistry := registry.NewGameTypeRegistry()
-	fault.RegisterGameTypes(gameTypeRegistry, ctx, logger, m, cfg, txMgr, l1Client)
+		gameTypeRegistry := registry.NewGameTypeRegistry()
+		fault.RegisterGameTypes(gameTypeRegistry, ctx, chainLogger, m.ForChain(chain.Name), &chainCfg, traceCache, txMgr, l1Client)
+		registries[chain.Name] = gameTypeRegistry
+	}
 
-	disk := newDiskManager(cfg.Datadir)
+	disk := newDiskManager(cfg.Datadir, traceCache)
 	s.sched = scheduler.NewScheduler(
 		logger,
 		m,
 		disk,
 		cfg.MaxConcurrency,
-		gameTypeRegistry.CreatePlayer)
+		registries.CreatePlayer)
 
-	pollClient, err := opClient.NewRPCWithClient(ctx, logger, cfg.L1EthRpc, opClient.NewBaseRPCClient(l1Client.Client()), cfg.PollInterval)
+	pollClient, err := opClient.NewRPCWithClient(ctx, logger, cfg.L1EthRpc, opClient.NewBaseRPCClient(l1Client.Client.Client()), cfg.PollInterval)
 	if err != nil {
 		return nil, errors.Join(fmt.Errorf("failed to create RPC client: %w", err), s.Stop(ctx))
 	}
-	s.monitor = newGameMonitor(logger, cl, loader, s.sched, cfg.GameWindow, l1Client.BlockNumber, cfg.GameAllowlist, pollClient)
+	s.monitor = newGameMonitor(logger, cl, sources, s.sched, cfg.GameWindow, l1Client.BlockNumber, cfg.GameAllowlist, pollClient)
 
 	m.RecordInfo(version.SimpleWithMeta)
 	m.RecordUp()
@@ -40,4 +40,6 @@ type GameMetadata struct {
 	GameType  uint8
 	Timestamp uint64
 	Proxy     common.Address
+	// Chain is the name of the chain whose dispute game factory created the game.
+	Chain string
 }
@@ -22,6 +22,8 @@ type Metricer interface {
 	// Record Tx metrics
 	txmetrics.TxMetricer
 
+	opmetrics.RPCEndpointMetricer
+
 	RecordGameStep()
 	RecordGameMove()
 	RecordCannonExecutionTime(t float64)
@@ -43,16 +45,17 @@ type Metrics struct {
 	factory  opmetrics.Factory
 
 	txmetrics.TxMetrics
+	opmetrics.RPCMetrics
 
 	info prometheus.GaugeVec
 	up   prometheus.Gauge
 
 	executors prometheus.GaugeVec
 
-	moves prometheus.Counter
-	steps prometheus.Counter
+	moves prometheus.CounterVec
+	steps prometheus.CounterVec
 
-	cannonExecutionTime prometheus.Histogram
+	cannonExecutionTime prometheus.HistogramVec
 
 	trackedGames  prometheus.GaugeVec
 	inflightGames prometheus.Gauge
@@ -69,7 +72,8 @@ func NewMetrics() *Metrics {
 		registry: registry,
 		factory:  factory,
 
-		TxMetrics: txmetrics.MakeTxMetrics(Namespace, factory),
+		TxMetrics:  txmetrics.MakeTxMetrics(Namespace, factory),
+		RPCMetrics: opmetrics.MakeRPCMetrics(Namespace, factory),
 
 		info: *factory.NewGaugeVec(prometheus.GaugeOpts{
 			Namespace: Namespace,
@@ -90,23 +94,29 @@ func NewMetrics() *Metrics {
 		}, []string{
 			"status",
 		}),
-		moves: factory.NewCounter(prometheus.CounterOpts{
+		moves: *factory.NewCounterVec(prometheus.CounterOpts{
 			Namespace: Namespace,
 			Name:      "moves",
 			Help:      "Number of game moves made by the challenge agent",
+		}, []string{
+			"chain",
 		}),
-		steps: factory.NewCounter(prometheus.CounterOpts{
+		steps: *factory.NewCounterVec(prometheus.CounterOpts{
 			Namespace: Namespace,
 			Name:      "steps",
 			Help:      "Number of game steps made by the challenge agent",
+		}, []string{
+			"chain",
 		}),
-		cannonExecutionTime: factory.NewHistogram(prometheus.HistogramOpts{
+		cannonExecutionTime: *factory.NewHistogramVec(prometheus.HistogramOpts{
 			Namespace: Namespace,
 			Name:      "cannon_execution_time",
 			Help:      "Time (in seconds) to execute cannon",
 			Buckets: append(
 				[]float64{1.0, 10.0},
 				prometheus.ExponentialBuckets(30.0, 2.0, 14)...),
+		}, []string{
+			"chain",
 		}),
 		trackedGames: *factory.NewGaugeVec(prometheus.GaugeOpts{
 			Namespace: Namespace,
@@ -157,16 +167,41 @@ func (m *Metrics) Document() []opmetrics.DocumentedMetric {
 	return m.factory.Document()
 }
 
+// RecordGameMove records a move, without a chain label.
+// Use [Metrics.ForChain] to record the moves of a specific chain.
 func (m *Metrics) RecordGameMove() {
-	m.moves.Add(1)
+	m.moves.WithLabelValues("").Add(1)
 }
 
 func (m *Metrics) RecordGameStep() {
-	m.steps.Add(1)
+	m.steps.WithLabelValues("").Add(1)
 }
 
 func (m *Metrics) RecordCannonExecutionTime(t float64) {
-	m.cannonExecutionTime.Observe(t)
+	m.cannonExecutionTime.WithLabelValues("").Observe(t)
+}
+
+// chainMetrics labels the metrics of playing games with the chain the games belong to.
+type chainMetrics struct {
+	*Metrics
+	chain string
+}
+
+// ForChain returns a [Metricer] that labels the moves, steps and cannon executions with the chain name.
+func (m *Metrics) ForChain(chain string) Metricer {
+	return &chainMetrics{Metrics: m, chain: chain}
+}
+
+func (m *chainMetrics) RecordGameMove() {
+	m.moves.WithLabelValues(m.chain).Add(1)
+}
+
+func (m *chainMetrics) RecordGameStep() {
+	m.steps.WithLabelValues(m.chain).Add(1)
+}
+
+func (m *chainMetrics) RecordCannonExecutionTime(t float64) {
+	m.cannonExecutionTime.WithLabelValues(m.chain).Observe(t)
 }
 
 func (m *Metrics) IncActiveExecutors() {
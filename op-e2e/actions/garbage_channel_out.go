@@ -64,6 +64,9 @@ type ChannelOutIface interface {
 // Compile-time check for ChannelOutIface interface implementation for the ChannelOut type.
 var _ ChannelOutIface = (*derive.ChannelOut)(nil)
 
+// Compile-time check for ChannelOutIface interface implementation for the SpanChannelOut type.
+var _ ChannelOutIface = (*derive.SpanChannelOut)(nil)
+
 // Compile-time check for ChannelOutIface interface implementation for the GarbageChannelOut type.
 var _ ChannelOutIface = (*GarbageChannelOut)(nil)
 
@@ -19,6 +19,7 @@ import (
 	"github.com/ethereum-optimism/optimism/op-batcher/compressor"
 	"github.com/ethereum-optimism/optimism/op-node/rollup"
 	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
+	"github.com/ethereum-optimism/optimism/op-service/dacommit"
 	"github.com/ethereum-optimism/optimism/op-service/eth"
 )
 
@@ -44,6 +45,10 @@ type BatcherCfg struct {
 	BatcherKey *ecdsa.PrivateKey
 
 	GarbageCfg *GarbageChannelCfg
+
+	// DAClient is optional. If set, frames are stored on the DA server once DA commitments are active,
+	// and only the commitments to them are submitted.
+	DAClient *dacommit.DAClient
 }
 
 // L2Batcher buffers and submits L2 batches to L1.
@@ -134,13 +139,20 @@ func (s *L2Batcher) Buffer(t Testing) error {
 		if s.l2BatcherCfg.GarbageCfg != nil {
 			ch, err = NewGarbageChannelOut(s.l2BatcherCfg.GarbageCfg)
 		} else {
-			c, e := compressor.NewRatioCompressor(compressor.Config{
+			cfg := compressor.Config{
 				TargetFrameSize:  s.l2BatcherCfg.MaxL1TxSize,
 				TargetNumFrames:  1,
 				ApproxComprRatio: 1,
-			})
+			}
+			c, e := compressor.NewRatioCompressor(cfg)
 			require.NoError(t, e, "failed to create compressor")
-			ch, err = derive.NewChannelOut(c)
+			// Like the op-batcher, batch all blocks of the channel into a span batch
+			// once the upgrade is active at the time of the L1 head.
+			if s.rollupCfg.IsSpanBatch(syncStatus.HeadL1.Time) {
+				ch, err = derive.NewSpanChannelOut(c, cfg.TargetOutputSize(), s.rollupCfg.Genesis.L2Time, s.rollupCfg.L2ChainID)
+			} else {
+				ch, err = derive.NewChannelOut(c)
+			}
 		}
 		require.NoError(t, err, "failed to create channel")
 		s.l2ChannelOut = ch
@@ -187,6 +199,17 @@ func (s *L2Batcher) ActL2BatchSubmit(t Testing, txOpts ...func(tx *types.Dynamic
 		s.l2Submitting = false
 		t.Fatalf("failed to output channel data to frame: %v", err)
 	}
+	txData := data.Bytes()
+	if s.l2BatcherCfg.DAClient != nil {
+		// Like the batcher, only submit commitments once DA commitments are active at the L1 head.
+		l1Head, err := s.l1.HeaderByNumber(t.Ctx(), nil)
+		require.NoError(t, err, "need l1 head to check DA commitments activation")
+		if s.rollupCfg.IsDACommitments(l1Head.Time) {
+			comm, err := s.l2BatcherCfg.DAClient.SetInput(t.Ctx(), txData)
+			require.NoError(t, err, "need to store frame on DA server")
+			txData = comm.TxData()
+		}
+	}
 
 	nonce, err := s.l1.PendingNonceAt(t.Ctx(), s.batcherAddr)
 	require.NoError(t, err, "need batcher nonce")
@@ -202,7 +225,7 @@ func (s *L2Batcher) ActL2BatchSubmit(t Testing, txOpts ...func(tx *types.Dynamic
 		To:        &s.rollupCfg.BatchInboxAddress,
 		GasTipCap: gasTipCap,
 		GasFeeCap: gasFeeCap,
-		Data:      data.Bytes(),
+		Data:      txData,
 	}
 	for _, opt := range txOpts {
 		opt(rawTx)
@@ -1,12 +1,14 @@
 package actions
 
 import (
+	"context"
 	"crypto/rand"
 	"errors"
 	"math/big"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/log"
@@ -16,6 +18,7 @@ import (
 	"github.com/ethereum-optimism/optimism/op-e2e/e2eutils"
 	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
 	"github.com/ethereum-optimism/optimism/op-node/rollup/sync"
+	"github.com/ethereum-optimism/optimism/op-service/dacommit"
 	"github.com/ethereum-optimism/optimism/op-service/eth"
 	"github.com/ethereum-optimism/optimism/op-service/testlog"
 )
@@ -97,6 +100,151 @@ func TestBatcher(gt *testing.T) {
 	require.NotNil(t, vTx)
 }
 
+// TestSpanBatchDerivation tests that the verifier derives the L2 chain from a span batch of the batcher,
+// which spans L2 blocks with transactions and multiple L1 origins.
+func TestSpanBatchDerivation(gt *testing.T) {
+	t := NewDefaultTesting(gt)
+	dp := e2eutils.MakeDeployParams(t, defaultRollupTestParams)
+	spanBatchTimeOffset := hexutil.Uint64(0)
+	dp.DeployConfig.L2GenesisSpanBatchTimeOffset = &spanBatchTimeOffset
+	sd := e2eutils.Setup(t, dp, defaultAlloc)
+	log := testlog.Logger(t, log.LvlDebug)
+	miner, seqEngine, sequencer := setupSequencerTest(t, sd, log)
+	verifEngine, verifier := setupVerifier(t, sd, log, miner.L1Client(t, sd.RollupCfg), &sync.Config{})
+
+	batcher := NewL2Batcher(log, sd.RollupCfg, &BatcherCfg{
+		MinL1TxSize: 0,
+		MaxL1TxSize: 128_000,
+		BatcherKey:  dp.Secrets.Batcher,
+	}, sequencer.RollupClient(), miner.EthClient(), seqEngine.EthClient())
+
+	sequencer.ActL2PipelineFull(t)
+	verifier.ActL2PipelineFull(t)
+
+	cl := seqEngine.EthClient()
+	signer := types.LatestSigner(sd.L2Cfg.Config)
+	var txs []*types.Transaction
+	// Build L2 blocks with transactions on top of two L1 origins.
+	for i := 0; i < 2; i++ {
+		miner.ActEmptyBlock(t)
+		sequencer.ActL1HeadSignal(t)
+		for sequencer.SyncStatus().UnsafeL2.L1Origin.Number < miner.l1Chain.CurrentBlock().Number.Uint64() {
+			n, err := cl.PendingNonceAt(t.Ctx(), dp.Addresses.Alice)
+			require.NoError(t, err)
+			tx := types.MustSignNewTx(dp.Secrets.Alice, signer, &types.DynamicFeeTx{
+				ChainID:   sd.L2Cfg.Config.ChainID,
+				Nonce:     n,
+				GasTipCap: big.NewInt(2 * params.GWei),
+				GasFeeCap: new(big.Int).Add(miner.l1Chain.CurrentBlock().BaseFee, big.NewInt(2*params.GWei)),
+				Gas:       params.TxGas,
+				To:        &dp.Addresses.Bob,
+				Value:     e2eutils.Ether(1),
+			})
+			require.NoError(t, cl.SendTransaction(t.Ctx(), tx))
+			txs = append(txs, tx)
+
+			sequencer.ActL2StartBlock(t)
+			seqEngine.ActL2IncludeTx(dp.Addresses.Alice)(t)
+			sequencer.ActL2EndBlock(t)
+		}
+	}
+	unsafeHead := sequencer.SyncStatus().UnsafeL2
+	require.Equal(t, uint64(2), unsafeHead.L1Origin.Number)
+
+	// Batch all L2 blocks into a single span batch.
+	batcher.ActBufferAll(t)
+	require.IsType(t, &derive.SpanChannelOut{}, batcher.l2ChannelOut)
+	batcher.ActL2ChannelClose(t)
+	batcher.ActL2BatchSubmit(t)
+	require.Nil(t, batcher.l2ChannelOut, "span batch must fit into a single frame")
+
+	miner.ActL1StartBlock(12)(t)
+	miner.ActL1IncludeTx(dp.Addresses.Batcher)(t)
+	miner.ActL1EndBlock(t)
+
+	verifier.ActL1HeadSignal(t)
+	verifier.ActL2PipelineFull(t)
+	require.Equal(t, unsafeHead, verifier.SyncStatus().SafeL2, "verifier must derive all blocks of the span batch")
+
+	verifCl := verifEngine.EthClient()
+	for _, tx := range txs {
+		vTx, isPending, err := verifCl.TransactionByHash(t.Ctx(), tx.Hash())
+		require.NoError(t, err)
+		require.False(t, isPending)
+		require.NotNil(t, vTx)
+	}
+}
+
+// TestDACommitments runs batch data through the DA server, from the batcher to the derivation of the verifier.
+func TestDACommitments(gt *testing.T) {
+	t := NewDefaultTesting(gt)
+	dp := e2eutils.MakeDeployParams(t, defaultRollupTestParams)
+	sd := e2eutils.Setup(t, dp, defaultAlloc)
+	log := testlog.Logger(t, log.LvlDebug)
+	miner, seqEngine, sequencer := setupSequencerTest(t, sd, log)
+	// DA commitments activate with the second L1 block.
+	activation := miner.l1Chain.CurrentBlock().Time + 24
+	sd.RollupCfg.DACommitmentsL1Timestamp = &activation
+
+	store, err := dacommit.NewFileStore(t.TempDir())
+	require.NoError(t, err)
+	server := dacommit.NewDAServer(log, store)
+	require.NoError(t, server.Start("127.0.0.1:0"))
+	t.Cleanup(func() {
+		_ = server.Stop(context.Background())
+	})
+	daClient := dacommit.NewDAClient("http://" + server.Addr())
+
+	verifEngine := NewL2Engine(t, log, sd.L2Cfg, sd.RollupCfg.Genesis.L1, e2eutils.WriteDefaultJWT(t))
+	verifier := NewL2Verifier(t, log, miner.L1Client(t, sd.RollupCfg), daClient, verifEngine.EngineClient(t, sd.RollupCfg), sd.RollupCfg, &sync.Config{})
+
+	batcher := NewL2Batcher(log, sd.RollupCfg, &BatcherCfg{
+		MinL1TxSize: 0,
+		MaxL1TxSize: 128_000,
+		BatcherKey:  dp.Secrets.Batcher,
+		DAClient:    daClient,
+	}, sequencer.RollupClient(), miner.EthClient(), seqEngine.EthClient())
+
+	sequencer.ActL2PipelineFull(t)
+	verifier.ActL2PipelineFull(t)
+
+	// submit the batch data, and return the data of the submitted batcher tx
+	submit := func() []byte {
+		miner.ActEmptyBlock(t)
+		sequencer.ActL1HeadSignal(t)
+		// The sequencer has no DA fetcher, so it builds the blocks without running its derivation pipeline.
+		sequencer.ActBuildToL1HeadUnsafe(t)
+		batcher.ActSubmitAll(t)
+		miner.ActL1StartBlock(12)(t)
+		miner.ActL1IncludeTx(dp.Addresses.Batcher)(t)
+		miner.ActL1EndBlock(t)
+
+		verifier.ActL1HeadSignal(t)
+		verifier.ActL2PipelineFull(t)
+		require.Equal(t, sequencer.SyncStatus().UnsafeL2, verifier.SyncStatus().SafeL2, "verifier must derive the submitted blocks")
+
+		block := miner.l1Chain.CurrentBlock()
+		txs := miner.l1Chain.GetBlockByHash(block.Hash()).Transactions()
+		require.Len(t, txs, 1)
+		return txs[0].Data()
+	}
+
+	// The L1 head is before the activation, so the batcher submits the frame as calldata.
+	// It is included in the first L1 block with DA commitments, which passes through plain batcher data.
+	data := submit()
+	require.Equal(t, derive.DerivationVersion0, data[0])
+	require.True(t, sd.RollupCfg.IsDACommitments(miner.l1Chain.CurrentBlock().Time))
+
+	// With DA commitments active at the L1 head, the batcher stores the frame on the DA server.
+	data = submit()
+	require.Equal(t, dacommit.TxDataVersion, data[0])
+	comm, err := dacommit.DecodeCommitment(data)
+	require.NoError(t, err)
+	stored, err := store.Get(t.Ctx(), comm)
+	require.NoError(t, err)
+	require.Equal(t, derive.DerivationVersion0, stored[0])
+}
+
 func TestL2Finalization(gt *testing.T) {
 	t := NewDefaultTesting(gt)
 	dp := e2eutils.MakeDeployParams(t, defaultRollupTestParams)
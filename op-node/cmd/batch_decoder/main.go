@@ -2,14 +2,19 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
 	"os"
 	"time"
 
+	"github.com/ethereum-optimism/optimism/op-node/chaincfg"
 	"github.com/ethereum-optimism/optimism/op-node/cmd/batch_decoder/fetch"
 	"github.com/ethereum-optimism/optimism/op-node/cmd/batch_decoder/reassemble"
+	"github.com/ethereum-optimism/optimism/op-node/cmd/batch_decoder/validate"
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
 	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
+	oplog "github.com/ethereum-optimism/optimism/op-service/log"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/urfave/cli/v2"
@@ -120,6 +125,132 @@ func main() {
 				return nil
 			},
 		},
+		{
+			Name:  "reference",
+			Usage: "Records the L1 & L2 reference chain to validate batches against",
+			Flags: []cli.Flag{
+				&cli.Uint64Flag{
+					Name:     "l2-start",
+					Required: true,
+					Usage:    "L2 safe head (inclusive) to start validating batches from",
+				},
+				&cli.Uint64Flag{
+					Name:     "l2-end",
+					Required: true,
+					Usage:    "Last L2 block (inclusive) to record",
+				},
+				&cli.Uint64Flag{
+					Name:     "l1-end",
+					Required: true,
+					Usage:    "Last L1 block (inclusive) to record",
+				},
+				&cli.StringFlag{
+					Name:  "rollup-config",
+					Usage: "Rollup config file. Defaults to the OP Mainnet rollup config",
+				},
+				&cli.StringFlag{
+					Name:  "out",
+					Value: "/tmp/batch_decoder/reference.json",
+					Usage: "File to write the reference chain to",
+				},
+				&cli.StringFlag{
+					Name:     "l1",
+					Required: true,
+					Usage:    "L1 RPC URL",
+					EnvVars:  []string{"L1_RPC"},
+				},
+				&cli.StringFlag{
+					Name:     "l2",
+					Required: true,
+					Usage:    "L2 RPC URL",
+					EnvVars:  []string{"L2_RPC"},
+				},
+			},
+			Action: func(cliCtx *cli.Context) error {
+				rollupCfg, err := loadRollupConfig(cliCtx.String("rollup-config"))
+				if err != nil {
+					log.Fatal(err)
+				}
+				l1Client, err := ethclient.Dial(cliCtx.String("l1"))
+				if err != nil {
+					log.Fatal(err)
+				}
+				l2Client, err := ethclient.Dial(cliCtx.String("l2"))
+				if err != nil {
+					log.Fatal(err)
+				}
+				ref, err := validate.RecordReference(cliCtx.Context, rollupCfg, l1Client, l2Client,
+					cliCtx.Uint64("l2-start"), cliCtx.Uint64("l2-end"), cliCtx.Uint64("l1-end"))
+				if err != nil {
+					log.Fatal(err)
+				}
+				if err := validate.WriteReference(cliCtx.String("out"), ref); err != nil {
+					log.Fatal(err)
+				}
+				fmt.Printf("Recorded %v L1 blocks & %v L2 blocks to %v\n", len(ref.L1), len(ref.L2), cliCtx.String("out"))
+				return nil
+			},
+		},
+		{
+			Name:  "validate",
+			Usage: "Validates fetched batches by replaying them through derivation against a reference chain",
+			Flags: append([]cli.Flag{
+				&cli.StringFlag{
+					Name:  "inbox",
+					Value: "0xff00000000000000000000000000000000000420",
+					Usage: "Batch Inbox Address",
+				},
+				&cli.StringFlag{
+					Name:  "in",
+					Value: "/tmp/batch_decoder/transactions_cache",
+					Usage: "Cache directory for the found transactions",
+				},
+				&cli.StringFlag{
+					Name:  "reference",
+					Value: "/tmp/batch_decoder/reference.json",
+					Usage: "Reference chain file, as recorded by the reference command",
+				},
+				&cli.StringFlag{
+					Name:  "rollup-config",
+					Usage: "Rollup config file. Defaults to the OP Mainnet rollup config",
+				},
+				&cli.StringFlag{
+					Name:  "out",
+					Value: "/tmp/batch_decoder/validation.json",
+					Usage: "File to write the validation results to",
+				},
+			}, oplog.CLIFlags("BATCH_DECODER")...),
+			Action: func(cliCtx *cli.Context) error {
+				rollupCfg, err := loadRollupConfig(cliCtx.String("rollup-config"))
+				if err != nil {
+					log.Fatal(err)
+				}
+				ref, err := validate.LoadReference(cliCtx.String("reference"))
+				if err != nil {
+					log.Fatal(err)
+				}
+				config := validate.Config{
+					BatchInbox:  common.HexToAddress(cliCtx.String("inbox")),
+					InDirectory: cliCtx.String("in"),
+					RollupCfg:   rollupCfg,
+					Reference:   ref,
+				}
+				logger := oplog.NewLogger(os.Stderr, oplog.ReadCLIConfig(cliCtx))
+				res, err := validate.Batches(cliCtx.Context, logger, config)
+				if err != nil {
+					log.Fatal(err)
+				}
+				if err := validate.WriteResult(cliCtx.String("out"), res); err != nil {
+					log.Fatal(err)
+				}
+				fmt.Printf("Validated %v batches in %v channels up to safe head %v at L1 origin %v\n", len(res.Batches), len(res.Channels), res.SafeHead, res.Origin)
+				if res.Error != "" {
+					fmt.Printf("Derivation stopped early: %v\n", res.Error)
+				}
+				fmt.Printf("Wrote validation results to %v\n", cliCtx.String("out"))
+				return nil
+			},
+		},
 		{
 			Name:  "force-close",
 			Usage: "Create the tx data which will force close a channel",
@@ -166,3 +297,19 @@ func main() {
 		log.Fatal(err)
 	}
 }
+
+func loadRollupConfig(file string) (*rollup.Config, error) {
+	if file == "" {
+		return chaincfg.Mainnet, nil
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var cfg rollup.Config
+	if err := json.NewDecoder(f).Decode(&cfg); err != nil {
+		return nil, fmt.Errorf("failed to decode rollup config %v: %w", file, err)
+	}
+	return &cfg, cfg.Check()
+}
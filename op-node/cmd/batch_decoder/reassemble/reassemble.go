@@ -42,6 +42,12 @@ type Config struct {
 }
 
 func LoadFrames(directory string, inbox common.Address) []FrameWithMetadata {
+	return transactionsToFrames(LoadTransactions(directory, inbox))
+}
+
+// LoadTransactions loads all transactions from valid senders from the given directory.
+// If inbox is not the zero address, only transactions sent to the inbox are loaded.
+func LoadTransactions(directory string, inbox common.Address) []fetch.TransactionWithMetadata {
 	txns := loadTransactions(directory, inbox)
 	// Sort first by block number then by transaction index inside the block number range.
 	// This is to match the order they are processed in derivation.
@@ -53,7 +59,7 @@ func LoadFrames(directory string, inbox common.Address) []FrameWithMetadata {
 		}
 
 	})
-	return transactionsToFrames(txns)
+	return txns
 }
 
 // Channels loads all transactions from the given input directory that are submitted to the
@@ -107,7 +113,10 @@ func processFrames(cfg *rollup.Config, id derive.ChannelID, frames []FrameWithMe
 	var batches []derive.SingularBatch
 	invalidBatches := false
 	if ch.IsReady() {
-		br, err := derive.BatchReader(cfg, ch.Reader(), eth.L1BlockRef{})
+		// The channel is read when its last frame is included, which determines the accepted batch and compression types.
+		last := frames[len(frames)-1]
+		inclusionBlock := eth.L1BlockRef{Hash: last.BlockHash, Number: last.InclusionBlock, Time: last.Timestamp}
+		br, err := derive.BatchReader(cfg, ch.Reader(), inclusionBlock)
 		if err == nil {
 			for batch, err := br(); err != io.EOF; batch, err = br() {
 				if err != nil {
@@ -0,0 +1,103 @@
+package validate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+)
+
+// ReferenceChain is a recorded section of the L1 and L2 chains that batches are validated against.
+// The first L2 block is the safe head that derivation starts from. The L1 blocks are consecutive and
+// start early enough to contain the opening frames of all channels that may still be read at that safe head.
+type ReferenceChain struct {
+	L1 []eth.L1BlockRef `json:"l1"`
+	L2 []eth.L2BlockRef `json:"l2"`
+}
+
+// RecordReference records the L2 blocks in the given range (inclusive) and the L1 blocks they were
+// derived from, up to the given L1 end block (inclusive).
+func RecordReference(ctx context.Context, cfg *rollup.Config, l1, l2 *ethclient.Client, l2Start, l2End, l1End uint64) (*ReferenceChain, error) {
+	var ref ReferenceChain
+	for i := l2Start; i <= l2End; i++ {
+		block, err := l2.BlockByNumber(ctx, new(big.Int).SetUint64(i))
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch L2 block %d: %w", i, err)
+		}
+		l2Ref, err := derive.L2BlockToBlockRef(block, &cfg.Genesis)
+		if err != nil {
+			return nil, fmt.Errorf("invalid L2 block %d: %w", i, err)
+		}
+		ref.L2 = append(ref.L2, l2Ref)
+	}
+	// Channels may have been opened up to a channel timeout before the origin of the safe head.
+	l1Start := ref.L2[0].L1Origin.Number
+	if l1Start > cfg.Genesis.L1.Number+cfg.ChannelTimeout {
+		l1Start -= cfg.ChannelTimeout
+	} else {
+		l1Start = cfg.Genesis.L1.Number
+	}
+	if l1End < ref.L2[len(ref.L2)-1].L1Origin.Number {
+		return nil, fmt.Errorf("L1 end block %d is before the L1 origin of the last L2 block %s", l1End, ref.L2[len(ref.L2)-1])
+	}
+	for i := l1Start; i <= l1End; i++ {
+		header, err := l1.HeaderByNumber(ctx, new(big.Int).SetUint64(i))
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch L1 block %d: %w", i, err)
+		}
+		ref.L1 = append(ref.L1, eth.InfoToL1BlockRef(eth.HeaderBlockInfo(header)))
+	}
+	return &ref, nil
+}
+
+// Check verifies that the reference chain is non-empty and consecutive.
+func (r *ReferenceChain) Check() error {
+	if len(r.L1) == 0 || len(r.L2) == 0 {
+		return fmt.Errorf("reference chain needs at least one L1 and one L2 block")
+	}
+	for i := 1; i < len(r.L1); i++ {
+		if r.L1[i].ParentHash != r.L1[i-1].Hash || r.L1[i].Number != r.L1[i-1].Number+1 {
+			return fmt.Errorf("L1 block %s does not build on %s", r.L1[i], r.L1[i-1])
+		}
+	}
+	for i := 1; i < len(r.L2); i++ {
+		if r.L2[i].ParentHash != r.L2[i-1].Hash || r.L2[i].Number != r.L2[i-1].Number+1 {
+			return fmt.Errorf("L2 block %s does not build on %s", r.L2[i], r.L2[i-1])
+		}
+	}
+	if origin := r.L2[0].L1Origin.Number; origin < r.L1[0].Number || origin > r.L1[len(r.L1)-1].Number {
+		return fmt.Errorf("L1 origin %d of the safe head is not part of the L1 reference chain", origin)
+	}
+	return nil
+}
+
+func LoadReference(file string) (*ReferenceChain, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var ref ReferenceChain
+	if err := json.NewDecoder(f).Decode(&ref); err != nil {
+		return nil, fmt.Errorf("failed to decode reference chain %v: %w", file, err)
+	}
+	return &ref, ref.Check()
+}
+
+func WriteReference(file string, ref *ReferenceChain) error {
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	return enc.Encode(ref)
+}
@@ -0,0 +1,379 @@
+package validate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/ethereum-optimism/optimism/op-node/cmd/batch_decoder/fetch"
+	"github.com/ethereum-optimism/optimism/op-node/cmd/batch_decoder/reassemble"
+	"github.com/ethereum-optimism/optimism/op-node/metrics"
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+)
+
+type Config struct {
+	BatchInbox  common.Address
+	InDirectory string
+	RollupCfg   *rollup.Config
+	Reference   *ReferenceChain
+}
+
+// Result is the outcome of replaying the fetched batcher transactions through derivation.
+type Result struct {
+	// Batches are all batches that were read from channels, in the order they were first checked.
+	Batches []BatchResult `json:"batches"`
+	// Channels are all channels that frames were found for, in the order they were opened.
+	Channels []ChannelResult `json:"channels"`
+	// GeneratedBatches is the number of empty batches that derivation generated
+	// because the sequence window expired.
+	GeneratedBatches int `json:"generated_batches"`
+	// SafeHead is the last reference L2 block that was derived.
+	SafeHead eth.L2BlockRef `json:"safe_head"`
+	// Origin is the L1 block that derivation stopped at.
+	Origin eth.L1BlockRef `json:"origin"`
+	// Error is set if derivation stopped because of an error, or because it diverged from the reference chain.
+	Error string `json:"error,omitempty"`
+}
+
+type BatchResult struct {
+	BatchType      string      `json:"batch_type"`
+	Timestamp      uint64      `json:"timestamp"`
+	ParentHash     common.Hash `json:"parent_hash"`
+	Epoch          eth.BlockID `json:"epoch"`
+	Transactions   int         `json:"transactions"`
+	SpanBlocks     int         `json:"span_blocks,omitempty"`
+	InclusionBlock eth.BlockID `json:"inclusion_block"`
+	// Checks is the number of times the batch queue checked the batch.
+	Checks int `json:"checks"`
+	// SafeHead is the L2 safe head of the last check.
+	SafeHead eth.BlockID `json:"safe_head"`
+	// Verdict is the validity of the batch in the last check.
+	Verdict string `json:"verdict"`
+	// Rule is the batch validity rule that decided the verdict of the last check.
+	Rule derive.BatchRule `json:"rule"`
+	// ReferenceMismatch describes how an accepted batch differs from the reference chain, if it does.
+	ReferenceMismatch string `json:"reference_mismatch,omitempty"`
+}
+
+type ChannelResult struct {
+	ID derive.ChannelID `json:"id"`
+	// Frames is the number of frames that were submitted for the channel.
+	Frames int `json:"frames"`
+	// OpenBlock is the L1 block the first frame of the channel was included in.
+	OpenBlock uint64 `json:"open_block"`
+	// LastBlock is the L1 block the last frame of the channel was included in.
+	LastBlock uint64 `json:"last_block"`
+	// TimeoutBlock is the last L1 block that frames of the channel are accepted in.
+	TimeoutBlock uint64 `json:"timeout_block"`
+	IsReady      bool   `json:"is_ready"`
+	// TimedOut is set if frames were included after the channel timeout,
+	// or the channel was not ready before the end of the reference chain passed the timeout.
+	TimedOut          bool    `json:"timed_out"`
+	InvalidFrames     bool    `json:"invalid_frames"`
+	InvalidBatches    bool    `json:"invalid_batches"`
+	Batches           int     `json:"batches"`
+	CompressedBytes   int     `json:"compressed_bytes"`
+	UncompressedBytes int     `json:"uncompressed_bytes"`
+	CompressionRatio  float64 `json:"compression_ratio"`
+}
+
+// Batches replays all fetched transactions through the frame queue, channel bank, channel reader
+// and batch queue stages of the derivation pipeline, starting at the first L2 block of the reference chain.
+// Every batch that is accepted is compared to the next block of the reference chain, which then becomes
+// the new safe head. Derivation stops at the end of either reference chain, or if it diverges.
+func Batches(ctx context.Context, logger log.Logger, config Config) (*Result, error) {
+	cfg, ref := config.RollupCfg, config.Reference
+	if err := ref.Check(); err != nil {
+		return nil, err
+	}
+	txs := reassemble.LoadTransactions(config.InDirectory, config.BatchInbox)
+
+	res := &Result{Channels: channelStats(cfg, ref, txs)}
+	results := make(map[*derive.BatchData]int)
+	// lastAccepted is the result of the last accepted batch. The batch queue returns
+	// the blocks of an accepted span batch one by one as new singular batches.
+	lastAccepted := -1
+	observe := func(batch *derive.BatchWithL1InclusionBlock, validity derive.BatchValidity, rule derive.BatchRule) {
+		i, ok := results[batch.Batch]
+		if !ok {
+			i = len(res.Batches)
+			results[batch.Batch] = i
+			res.Batches = append(res.Batches, newBatchResult(batch))
+		}
+		r := &res.Batches[i]
+		r.Checks++
+		r.SafeHead = res.SafeHead.ID()
+		r.Verdict = validity.String()
+		r.Rule = rule
+		if validity == derive.BatchAccept {
+			lastAccepted = i
+		}
+	}
+
+	traversal := &referenceTraversal{l1: ref.L1, sysCfg: cfg.Genesis.SystemConfig}
+	l1Src := derive.NewL1Retrieval(logger, newCalldataSource(txs), traversal)
+	frameQueue := derive.NewFrameQueue(logger, l1Src)
+	bank := derive.NewChannelBank(logger, cfg, frameQueue, nil, metrics.NoopMetrics)
+	chInReader := derive.NewChannelInReader(cfg, logger, bank, metrics.NoopMetrics)
+	bq := derive.NewBatchQueue(logger, cfg, chInReader, unrecordedPayloads{})
+	bq.SetCheckObserver(observe)
+
+	base := ref.L1[0]
+	for _, stage := range []derive.ResettableStage{l1Src, frameQueue, bank, chInReader, bq} {
+		if err := stage.Reset(ctx, base, traversal.sysCfg); err != io.EOF {
+			return nil, fmt.Errorf("failed to reset derivation stages: %w", err)
+		}
+	}
+
+	res.SafeHead = ref.L2[0]
+	for next := 1; next < len(ref.L2); {
+		batch, err := bq.NextBatch(ctx, res.SafeHead)
+		if err == io.EOF {
+			if !traversal.advance() {
+				break
+			}
+			continue
+		} else if errors.Is(err, derive.NotEnoughData) || errors.Is(err, derive.ErrTemporary) {
+			continue
+		} else if err != nil {
+			res.Error = fmt.Sprintf("derivation failed at L1 origin %s: %v", bq.Origin(), err)
+			break
+		}
+
+		expected := ref.L2[next]
+		mismatch := ""
+		if batch.Timestamp != expected.Time {
+			mismatch = fmt.Sprintf("batch timestamp %d does not match reference block %s with timestamp %d", batch.Timestamp, expected, expected.Time)
+		} else if batch.ParentHash != expected.ParentHash {
+			mismatch = fmt.Sprintf("batch parent %s does not match parent of reference block %s", batch.ParentHash, expected)
+		} else if uint64(batch.EpochNum) != expected.L1Origin.Number {
+			mismatch = fmt.Sprintf("batch epoch %d does not match L1 origin of reference block %s", batch.EpochNum, expected)
+		}
+		if i, ok := results[batch]; ok {
+			res.Batches[i].ReferenceMismatch = mismatch
+		} else if i, ok := spanResult(res, lastAccepted, cfg.BlockTime, batch.Timestamp); ok {
+			if res.Batches[i].ReferenceMismatch == "" {
+				res.Batches[i].ReferenceMismatch = mismatch
+			}
+		} else {
+			res.GeneratedBatches++
+		}
+		if mismatch != "" {
+			res.Error = mismatch
+			break
+		}
+		res.SafeHead = expected
+		next++
+	}
+	res.Origin = traversal.Origin()
+	return res, nil
+}
+
+func WriteResult(file string, res *Result) error {
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	return enc.Encode(res)
+}
+
+func newBatchResult(batch *derive.BatchWithL1InclusionBlock) BatchResult {
+	b := batch.Batch
+	r := BatchResult{
+		BatchType:      "singular",
+		Timestamp:      b.Timestamp,
+		ParentHash:     b.ParentHash,
+		Epoch:          b.Epoch(),
+		Transactions:   len(b.Transactions),
+		InclusionBlock: batch.L1InclusionBlock.ID(),
+	}
+	if b.BatchType == derive.SpanBatchType {
+		r.BatchType = "span"
+		r.ParentHash = common.Hash{}
+		r.Epoch = eth.BlockID{}
+		r.Transactions = 0
+		// The batch queue sets the derived span batch, unless the span batch is invalid.
+		if span := batch.SpanBatch; span != nil {
+			r.Timestamp = span.GetTimestamp()
+			r.Epoch = eth.BlockID{Number: uint64(span.GetStartEpochNum())}
+			r.SpanBlocks = span.GetBlockCount()
+			for i := 0; i < r.SpanBlocks; i++ {
+				r.Transactions += len(span.GetBlockTransactions(i))
+			}
+		}
+	}
+	return r
+}
+
+// spanResult returns the result of the accepted span batch at index i, if the span batch contains the block with the given timestamp.
+func spanResult(res *Result, i int, blockTime uint64, timestamp uint64) (int, bool) {
+	if i < 0 {
+		return 0, false
+	}
+	r := &res.Batches[i]
+	if r.SpanBlocks == 0 || timestamp < r.Timestamp || timestamp > r.Timestamp+uint64(r.SpanBlocks-1)*blockTime {
+		return 0, false
+	}
+	return i, true
+}
+
+// channelStats reassembles all channels like the channel bank does, to report channel timeouts and compression.
+func channelStats(cfg *rollup.Config, ref *ReferenceChain, txs []fetch.TransactionWithMetadata) []ChannelResult {
+	l1End := ref.L1[len(ref.L1)-1].Number
+	channels := make(map[derive.ChannelID]*derive.Channel)
+	var results []ChannelResult
+	index := make(map[derive.ChannelID]int)
+	for _, tx := range txs {
+		inclusion := eth.L1BlockRef{Hash: tx.BlockHash, Number: tx.BlockNumber, Time: tx.BlockTime}
+		for _, frame := range tx.Frames {
+			ch, ok := channels[frame.ID]
+			if !ok {
+				ch = derive.NewChannel(frame.ID, inclusion)
+				channels[frame.ID] = ch
+				index[frame.ID] = len(results)
+				results = append(results, ChannelResult{
+					ID:           frame.ID,
+					OpenBlock:    tx.BlockNumber,
+					TimeoutBlock: tx.BlockNumber + cfg.ChannelTimeout,
+				})
+			}
+			r := &results[index[frame.ID]]
+			r.Frames++
+			r.LastBlock = tx.BlockNumber
+			if tx.BlockNumber > r.TimeoutBlock {
+				r.TimedOut = true
+				continue
+			}
+			wasReady := ch.IsReady()
+			if err := ch.AddFrame(frame, inclusion); err != nil {
+				r.InvalidFrames = true
+				continue
+			}
+			r.CompressedBytes += len(frame.Data)
+			if !wasReady && ch.IsReady() {
+				readChannel(cfg, ch, inclusion, r)
+			}
+		}
+	}
+	for i := range results {
+		r := &results[i]
+		r.IsReady = channels[r.ID].IsReady()
+		if !r.IsReady && l1End > r.TimeoutBlock {
+			r.TimedOut = true
+		}
+		if r.UncompressedBytes > 0 {
+			r.CompressionRatio = float64(r.CompressedBytes) / float64(r.UncompressedBytes)
+		}
+	}
+	return results
+}
+
+// readChannel reads all batches of the ready channel, to count them and measure the uncompressed size.
+func readChannel(cfg *rollup.Config, ch *derive.Channel, inclusion eth.L1BlockRef, r *ChannelResult) {
+	br, err := derive.BatchReader(cfg, ch.Reader(), inclusion)
+	if err != nil {
+		r.InvalidBatches = true
+		return
+	}
+	for {
+		batch, err := br()
+		if err == io.EOF {
+			return
+		} else if err != nil {
+			r.InvalidBatches = true
+			return
+		}
+		r.Batches++
+		// the channel data is the concatenation of the RLP encoded batches
+		if data, err := rlp.EncodeToBytes(batch.Batch); err == nil {
+			r.UncompressedBytes += len(data)
+		}
+	}
+}
+
+// referenceTraversal is the L1 traversal stage of the replay, which traverses the L1 reference chain.
+type referenceTraversal struct {
+	l1     []eth.L1BlockRef
+	index  int
+	done   bool
+	sysCfg eth.SystemConfig
+}
+
+var _ derive.NextBlockProvider = (*referenceTraversal)(nil)
+
+func (t *referenceTraversal) NextL1Block(_ context.Context) (eth.L1BlockRef, error) {
+	if !t.done {
+		t.done = true
+		return t.Origin(), nil
+	}
+	return eth.L1BlockRef{}, io.EOF
+}
+
+func (t *referenceTraversal) Origin() eth.L1BlockRef {
+	return t.l1[t.index]
+}
+
+// SystemConfig returns the genesis system config. System config updates are not replayed,
+// instead the fetched transactions have already been filtered by their sender.
+func (t *referenceTraversal) SystemConfig() eth.SystemConfig {
+	return t.sysCfg
+}
+
+// advance moves to the next L1 block, and returns false if the end of the reference chain is reached.
+func (t *referenceTraversal) advance() bool {
+	if t.index+1 >= len(t.l1) {
+		return false
+	}
+	t.index++
+	t.done = false
+	return true
+}
+
+// unrecordedPayloads is the source of the safe L2 blocks that span batches may overlap with.
+// The reference chain only records block references, so span batches that overlap the safe chain
+// stay undecided with the BatchRuleL2Unavailable rule.
+type unrecordedPayloads struct{}
+
+func (unrecordedPayloads) PayloadByNumber(_ context.Context, n uint64) (*eth.ExecutionPayload, error) {
+	return nil, fmt.Errorf("payload of L2 block %d is not recorded in the reference chain", n)
+}
+
+// calldataSource serves the calldata of the fetched batcher transactions, by L1 block hash.
+type calldataSource map[common.Hash][]eth.Data
+
+func newCalldataSource(txs []fetch.TransactionWithMetadata) calldataSource {
+	src := make(calldataSource)
+	for _, tx := range txs {
+		src[tx.BlockHash] = append(src[tx.BlockHash], tx.Tx.Data())
+	}
+	return src
+}
+
+func (s calldataSource) OpenData(_ context.Context, ref eth.L1BlockRef, _ common.Address) derive.DataIter {
+	return &calldataIter{data: s[ref.Hash]}
+}
+
+type calldataIter struct {
+	data []eth.Data
+}
+
+func (it *calldataIter) Next(_ context.Context) (eth.Data, error) {
+	if len(it.data) == 0 {
+		return nil, io.EOF
+	}
+	data := it.data[0]
+	it.data = it.data[1:]
+	return data, nil
+}
@@ -0,0 +1,277 @@
+package validate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-node/cmd/batch_decoder/fetch"
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+	"github.com/ethereum-optimism/optimism/op-service/testutils"
+)
+
+// testCompressor is a channel compressor that never becomes full.
+type testCompressor struct {
+	derive.ChannelCompressor
+}
+
+func (testCompressor) FullErr() error {
+	return nil
+}
+
+// fixture is a reference chain of an L1 block every 12 seconds and an L2 block every 2 seconds,
+// and a directory of fetched batcher transactions that are submitted on top of it.
+type fixture struct {
+	cfg *rollup.Config
+	l1  []eth.L1BlockRef
+	l2  []eth.L2BlockRef
+	dir string
+	txs uint64
+}
+
+func newFixture(t *testing.T, spanBatches bool) *fixture {
+	rng := rand.New(rand.NewSource(1234))
+	var l1 []eth.L1BlockRef
+	for i := uint64(0); i < 8; i++ {
+		ref := eth.L1BlockRef{Hash: testutils.RandomHash(rng), Number: i, Time: 100 + 12*i}
+		if i > 0 {
+			ref.ParentHash = l1[i-1].Hash
+		}
+		l1 = append(l1, ref)
+	}
+	var l2 []eth.L2BlockRef
+	for i := uint64(0); i < 7; i++ {
+		ref := eth.L2BlockRef{Hash: testutils.RandomHash(rng), Number: i, Time: 100 + 2*i}
+		origin := l1[(ref.Time-100)/12]
+		ref.L1Origin = origin.ID()
+		if i > 0 {
+			ref.ParentHash = l2[i-1].Hash
+			if l2[i-1].L1Origin == ref.L1Origin {
+				ref.SequenceNumber = l2[i-1].SequenceNumber + 1
+			}
+		}
+		l2 = append(l2, ref)
+	}
+	cfg := &rollup.Config{
+		Genesis: rollup.Genesis{
+			L1:     l1[0].ID(),
+			L2:     l2[0].ID(),
+			L2Time: l2[0].Time,
+		},
+		BlockTime:         2,
+		MaxSequencerDrift: 600,
+		SeqWindowSize:     4,
+		ChannelTimeout:    10,
+		L1ChainID:         big.NewInt(900),
+		L2ChainID:         big.NewInt(901),
+		BatchInboxAddress: common.Address{0xff},
+	}
+	if spanBatches {
+		spanBatchTime := uint64(0)
+		cfg.SpanBatchTime = &spanBatchTime
+	}
+	return &fixture{cfg: cfg, l1: l1, l2: l2, dir: t.TempDir()}
+}
+
+// batch returns the singular batch of the L2 block with the given number.
+func (f *fixture) batch(n int) *derive.SingularBatch {
+	block := f.l2[n]
+	return &derive.SingularBatch{
+		ParentHash: block.ParentHash,
+		EpochNum:   rollup.Epoch(block.L1Origin.Number),
+		EpochHash:  block.L1Origin.Hash,
+		Timestamp:  block.Time,
+	}
+}
+
+func (f *fixture) spanBatch(t *testing.T, batches ...*derive.SingularBatch) *derive.BatchData {
+	raw, err := derive.NewSpanBatch(batches).ToRawSpanBatch(0, f.cfg.Genesis.L2Time, f.cfg.L2ChainID)
+	require.NoError(t, err)
+	return derive.NewSpanBatchData(*raw)
+}
+
+// submit writes a fetched batcher transaction that is included in the given L1 block,
+// with the frames of a channel of the given batches.
+func (f *fixture) submit(t *testing.T, l1Block int, batches ...*derive.BatchData) {
+	c, err := derive.NewChannelCompressor(derive.Zlib, 0)
+	require.NoError(t, err)
+	co, err := derive.NewChannelOut(testCompressor{c})
+	require.NoError(t, err)
+	for _, batch := range batches {
+		_, err := co.AddBatch(batch)
+		require.NoError(t, err)
+	}
+	require.NoError(t, co.Close())
+	data := []byte{derive.DerivationVersion0}
+	for {
+		var buf bytes.Buffer
+		_, err := co.OutputFrame(&buf, 100_000)
+		data = append(data, buf.Bytes()...)
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+	}
+	frames, err := derive.ParseFrames(data)
+	require.NoError(t, err)
+
+	inclusion := f.l1[l1Block]
+	txm := fetch.TransactionWithMetadata{
+		TxIndex:     f.txs,
+		InboxAddr:   f.cfg.BatchInboxAddress,
+		BlockNumber: inclusion.Number,
+		BlockHash:   inclusion.Hash,
+		BlockTime:   inclusion.Time,
+		ChainId:     f.cfg.L1ChainID.Uint64(),
+		ValidSender: true,
+		Frames:      frames,
+		ValidFrames: true,
+		Tx: types.NewTx(&types.DynamicFeeTx{
+			ChainID:   f.cfg.L1ChainID,
+			Nonce:     f.txs,
+			GasTipCap: big.NewInt(1),
+			GasFeeCap: big.NewInt(1),
+			To:        &f.cfg.BatchInboxAddress,
+			Value:     new(big.Int),
+			Data:      data,
+		}),
+	}
+	f.txs++
+	file, err := os.Create(filepath.Join(f.dir, fmt.Sprintf("%d.json", txm.TxIndex)))
+	require.NoError(t, err)
+	defer file.Close()
+	require.NoError(t, json.NewEncoder(file).Encode(txm))
+}
+
+func (f *fixture) validate(t *testing.T, ref *ReferenceChain) *Result {
+	res, err := Batches(context.Background(), testlog.Logger(t, log.LvlCrit), Config{
+		BatchInbox:  f.cfg.BatchInboxAddress,
+		InDirectory: f.dir,
+		RollupCfg:   f.cfg,
+		Reference:   ref,
+	})
+	require.NoError(t, err)
+	return res
+}
+
+func TestBatchesSingular(t *testing.T) {
+	f := newFixture(t, false)
+	badParent := f.batch(2)
+	badParent.ParentHash = common.Hash{0xaa}
+	batches := []*derive.BatchData{derive.NewSingularBatchData(*f.batch(1)), derive.NewSingularBatchData(*badParent)}
+	for i := 2; i < len(f.l2); i++ {
+		batches = append(batches, derive.NewSingularBatchData(*f.batch(i)))
+	}
+	f.submit(t, 1, batches...)
+
+	res := f.validate(t, &ReferenceChain{L1: f.l1, L2: f.l2})
+	require.Empty(t, res.Error)
+	require.Equal(t, f.l2[len(f.l2)-1], res.SafeHead)
+	require.Zero(t, res.GeneratedBatches)
+	require.Len(t, res.Channels, 1)
+	require.True(t, res.Channels[0].IsReady)
+	require.Equal(t, len(batches), res.Channels[0].Batches)
+
+	require.Len(t, res.Batches, len(batches))
+	for i, r := range res.Batches {
+		require.Equal(t, "singular", r.BatchType)
+		require.Empty(t, r.ReferenceMismatch)
+		if i == 1 {
+			require.Equal(t, "drop", r.Verdict)
+			require.Equal(t, derive.BatchRuleParentHash, r.Rule)
+			require.Equal(t, f.l2[1].ID(), r.SafeHead)
+			continue
+		}
+		require.Equal(t, "accept", r.Verdict)
+		require.Equal(t, derive.BatchRuleValid, r.Rule)
+	}
+}
+
+func TestBatchesSpan(t *testing.T) {
+	f := newFixture(t, true)
+	var blocks []*derive.SingularBatch
+	for i := 1; i < len(f.l2); i++ {
+		blocks = append(blocks, f.batch(i))
+	}
+	f.submit(t, 1, f.spanBatch(t, blocks...))
+
+	res := f.validate(t, &ReferenceChain{L1: f.l1, L2: f.l2})
+	require.Empty(t, res.Error)
+	require.Equal(t, f.l2[len(f.l2)-1], res.SafeHead)
+	require.Zero(t, res.GeneratedBatches, "blocks of the span batch must not be reported as generated")
+	require.Len(t, res.Batches, 1)
+	r := res.Batches[0]
+	require.Equal(t, "span", r.BatchType)
+	require.Equal(t, len(blocks), r.SpanBlocks)
+	require.Equal(t, f.l2[1].Time, r.Timestamp)
+	require.Equal(t, "accept", r.Verdict)
+	require.Equal(t, derive.BatchRuleValid, r.Rule)
+	require.Empty(t, r.ReferenceMismatch)
+}
+
+func TestBatchesSpanReferenceMismatch(t *testing.T) {
+	f := newFixture(t, true)
+	var blocks []*derive.SingularBatch
+	for i := 1; i < len(f.l2); i++ {
+		blocks = append(blocks, f.batch(i))
+	}
+	// The last block sticks to the L1 origin of its parent, which is valid, but not what the reference chain did.
+	last := blocks[len(blocks)-1]
+	last.EpochNum = blocks[0].EpochNum
+	last.EpochHash = blocks[0].EpochHash
+	f.submit(t, 1, f.spanBatch(t, blocks...))
+
+	res := f.validate(t, &ReferenceChain{L1: f.l1, L2: f.l2})
+	require.Equal(t, f.l2[len(f.l2)-2], res.SafeHead)
+	require.Len(t, res.Batches, 1)
+	r := res.Batches[0]
+	require.Equal(t, "accept", r.Verdict)
+	require.Equal(t, derive.BatchRuleValid, r.Rule)
+	require.Contains(t, r.ReferenceMismatch, "does not match L1 origin of reference block")
+	require.Equal(t, r.ReferenceMismatch, res.Error)
+}
+
+func TestBatchesSpanOverlap(t *testing.T) {
+	f := newFixture(t, true)
+	var blocks []*derive.SingularBatch
+	for i := 1; i < len(f.l2); i++ {
+		blocks = append(blocks, f.batch(i))
+	}
+	f.submit(t, 1, f.spanBatch(t, blocks...))
+
+	// The safe head of the reference chain is within the span batch, and the reference chain
+	// ends before the sequence window of the span batch expires.
+	res := f.validate(t, &ReferenceChain{L1: f.l1[:3], L2: f.l2[2:]})
+	require.Empty(t, res.Error)
+	require.Equal(t, f.l2[2], res.SafeHead)
+	require.Len(t, res.Batches, 1)
+	r := res.Batches[0]
+	require.Equal(t, "span", r.BatchType)
+	require.Equal(t, "undecided", r.Verdict)
+	require.Equal(t, derive.BatchRuleL2Unavailable, r.Rule)
+}
+
+func TestBatchesSpanBeforeUpgrade(t *testing.T) {
+	f := newFixture(t, false)
+	f.submit(t, 1, f.spanBatch(t, f.batch(1), f.batch(2)))
+
+	res := f.validate(t, &ReferenceChain{L1: f.l1[:3], L2: f.l2})
+	require.Empty(t, res.Batches, "span batches must not be read before the upgrade")
+	require.Len(t, res.Channels, 1)
+	require.True(t, res.Channels[0].InvalidBatches)
+}
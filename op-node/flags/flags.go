@@ -25,7 +25,7 @@ var (
 	/* Required Flags */
 	L1NodeAddr = &cli.StringFlag{
 		Name:    "l1",
-		Usage:   "Address of L1 User JSON-RPC endpoint to use (eth namespace required)",
+		Usage:   "Address of L1 User JSON-RPC endpoint to use (eth namespace required). Several comma-separated endpoints can be listed, to fail over between",
 		Value:   "http://127.0.0.1:8545",
 		EnvVars: prefixEnvVars("L1_ETH_RPC"),
 	}
@@ -67,6 +67,21 @@ var (
 		Usage:   "File path used to persist state changes made via the admin API so they persist across restarts. Disabled if not set.",
 		EnvVars: prefixEnvVars("RPC_ADMIN_STATE"),
 	}
+	SafeDBPath = &cli.StringFlag{
+		Name:    "safedb.path",
+		Usage:   "File path used to persist safe head update data. Disabled if not set.",
+		EnvVars: prefixEnvVars("SAFEDB_PATH"),
+	}
+	BeaconAddr = &cli.StringFlag{
+		Name:    "l1.beacon",
+		Usage:   "Address of L1 Beacon-node HTTP endpoint to use. Required if blobs data-availability is enabled.",
+		EnvVars: prefixEnvVars("L1_BEACON"),
+	}
+	DAServerAddr = &cli.StringFlag{
+		Name:    "da.server",
+		Usage:   "Address of the DA server HTTP endpoint to resolve DA commitments with. Required if DA commitments are enabled.",
+		EnvVars: prefixEnvVars("DA_SERVER"),
+	}
 	L1TrustRPC = &cli.BoolFlag{
 		Name:    "l1.trustrpc",
 		Usage:   "Trust the L1 RPC, sync faster at risk of malicious/buggy RPC providing bad or inconsistent L1 data",
@@ -100,6 +115,29 @@ var (
 		EnvVars: prefixEnvVars("L1_HTTP_POLL_INTERVAL"),
 		Value:   time.Second * 12,
 	}
+	L1RPCMaxLagBlocks = &cli.Uint64Flag{
+		Name:    "l1.max-lag-blocks",
+		Usage:   "Number of blocks an L1 endpoint may lag behind the other L1 endpoints, before requests fail over to another endpoint. 0 disables the lag check. Only used with several L1 endpoints.",
+		EnvVars: prefixEnvVars("L1_MAX_LAG_BLOCKS"),
+		Value:   10,
+	}
+	L1RPCQuorum = &cli.IntFlag{
+		Name:    "l1.quorum",
+		Usage:   "Number of L1 endpoints that must agree on the hash of L1 blocks fetched by number, and of L1 receipts. Only used with several L1 endpoints.",
+		EnvVars: prefixEnvVars("L1_QUORUM"),
+		Value:   1,
+	}
+	L1CachePath = &cli.StringFlag{
+		Name:    "l1.cache-path",
+		Usage:   "File path used to persist fetched L1 headers, transactions and receipts, to not fetch them again after a restart. Disabled if not set.",
+		EnvVars: prefixEnvVars("L1_CACHE_PATH"),
+	}
+	L1CacheSizeMB = &cli.Uint64Flag{
+		Name:    "l1.cache-size-mb",
+		Usage:   "Maximum size of the persisted L1 data in MB, the oldest data is removed first. Only used if l1.cache-path is set.",
+		EnvVars: prefixEnvVars("L1_CACHE_SIZE_MB"),
+		Value:   1024,
+	}
 	L2EngineJWTSecret = &cli.StringFlag{
 		Name:        "l2.jwt-secret",
 		Usage:       "Path to JWT secret key. Keys are 32 bytes, hex encoded in a file. A new key will be generated if left empty.",
@@ -132,6 +170,37 @@ var (
 		Required: false,
 		Value:    0,
 	}
+	ConductorEnabledFlag = &cli.BoolFlag{
+		Name:    "conductor.enabled",
+		Usage:   "Enable sequencer high-availability: sequence new blocks only while this node is the leader of a raft cluster of sequencers, and commit each new block to the cluster before publishing it.",
+		EnvVars: prefixEnvVars("CONDUCTOR_ENABLED"),
+	}
+	ConductorServerIDFlag = &cli.StringFlag{
+		Name:    "conductor.server-id",
+		Usage:   "Unique ID of this node in the sequencer cluster.",
+		EnvVars: prefixEnvVars("CONDUCTOR_SERVER_ID"),
+	}
+	ConductorRaftAddrFlag = &cli.StringFlag{
+		Name:    "conductor.raft-addr",
+		Usage:   "Address (host:port) to listen on for raft traffic, and at which other nodes of the sequencer cluster reach this node.",
+		EnvVars: prefixEnvVars("CONDUCTOR_RAFT_ADDR"),
+	}
+	ConductorStorageDirFlag = &cli.StringFlag{
+		Name:    "conductor.storage-dir",
+		Usage:   "Directory to persist the raft log and snapshots of the sequencer cluster in.",
+		EnvVars: prefixEnvVars("CONDUCTOR_STORAGE_DIR"),
+	}
+	ConductorPeersFlag = &cli.StringSliceFlag{
+		Name:    "conductor.peers",
+		Usage:   "Comma separated list of <server-id>=<host:port> of every node in the initial sequencer cluster, including this node. Only used to bootstrap a new cluster.",
+		EnvVars: prefixEnvVars("CONDUCTOR_PEERS"),
+	}
+	ConductorCommitTimeoutFlag = &cli.DurationFlag{
+		Name:    "conductor.commit-timeout",
+		Usage:   "Maximum time to wait for a new block to be committed by the sequencer cluster.",
+		EnvVars: prefixEnvVars("CONDUCTOR_COMMIT_TIMEOUT"),
+		Value:   time.Second,
+	}
 	SequencerL1Confs = &cli.Uint64Flag{
 		Name:     "sequencer.l1-confs",
 		Usage:    "Number of L1 blocks to keep distance from the L1 head as a sequencer for picking an L1 origin.",
@@ -270,21 +339,34 @@ var optionalFlags = []cli.Flag{
 	RPCListenPort,
 	RollupConfig,
 	Network,
+	BeaconAddr,
+	DAServerAddr,
 	L1TrustRPC,
 	L1RPCProviderKind,
 	L1RPCRateLimit,
 	L1RPCMaxBatchSize,
 	L1HTTPPollInterval,
+	L1RPCMaxLagBlocks,
+	L1RPCQuorum,
 	L2EngineJWTSecret,
 	VerifierL1Confs,
 	SequencerEnabledFlag,
 	SequencerStoppedFlag,
 	SequencerMaxSafeLagFlag,
 	SequencerL1Confs,
+	ConductorEnabledFlag,
+	ConductorServerIDFlag,
+	ConductorRaftAddrFlag,
+	ConductorStorageDirFlag,
+	ConductorPeersFlag,
+	ConductorCommitTimeoutFlag,
 	L1EpochPollIntervalFlag,
 	RuntimeConfigReloadIntervalFlag,
 	RPCEnableAdmin,
 	RPCAdminPersistence,
+	SafeDBPath,
+	L1CachePath,
+	L1CacheSizeMB,
 	MetricsEnabledFlag,
 	MetricsAddrFlag,
 	MetricsPortFlag,
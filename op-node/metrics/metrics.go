@@ -84,6 +84,7 @@ type Metrics struct {
 
 	L1SourceCache *metrics.CacheMetrics
 	L2SourceCache *metrics.CacheMetrics
+	L1DiskCache   *metrics.CacheMetrics
 
 	DerivationIdle prometheus.Gauge
 
@@ -179,6 +180,7 @@ func NewMetrics(procName string) *Metrics {
 
 		L1SourceCache: metrics.NewCacheMetrics(factory, ns, "l1_source_cache", "L1 Source cache"),
 		L2SourceCache: metrics.NewCacheMetrics(factory, ns, "l2_source_cache", "L2 Source cache"),
+		L1DiskCache:   metrics.NewCacheMetrics(factory, ns, "l1_disk_cache", "L1 disk cache"),
 
 		DerivationIdle: factory.NewGauge(prometheus.GaugeOpts{
 			Namespace: ns,
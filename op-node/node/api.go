@@ -4,9 +4,11 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/log"
+	gethrpc "github.com/ethereum/go-ethereum/rpc"
 
 	"github.com/ethereum-optimism/optimism/op-node/rollup"
 	"github.com/ethereum-optimism/optimism/op-node/version"
@@ -30,6 +32,12 @@ type driverClient interface {
 	StartSequencer(ctx context.Context, blockHash common.Hash) error
 	StopSequencer(context.Context) (common.Hash, error)
 	SequencerActive(context.Context) (bool, error)
+	// SubscribeSyncStatus sends the sync status to ch whenever it changes.
+	SubscribeSyncStatus(ch chan<- *eth.SyncStatus) ethereum.Subscription
+}
+
+type SafeDBReader interface {
+	SafeHeadAtL1(ctx context.Context, l1BlockNum uint64) (l1 eth.BlockID, safeHead eth.BlockID, err error)
 }
 
 type adminAPI struct {
@@ -72,15 +80,17 @@ type nodeAPI struct {
 	config *rollup.Config
 	client l2EthClient
 	dr     driverClient
+	safeDB SafeDBReader
 	log    log.Logger
 	m      metrics.RPCMetricer
 }
 
-func NewNodeAPI(config *rollup.Config, l2Client l2EthClient, dr driverClient, log log.Logger, m metrics.RPCMetricer) *nodeAPI {
+func NewNodeAPI(config *rollup.Config, l2Client l2EthClient, dr driverClient, safeDB SafeDBReader, log log.Logger, m metrics.RPCMetricer) *nodeAPI {
 	return &nodeAPI{
 		config: config,
 		client: l2Client,
 		dr:     dr,
+		safeDB: safeDB,
 		log:    log,
 		m:      m,
 	}
@@ -109,6 +119,20 @@ func (n *nodeAPI) OutputAtBlock(ctx context.Context, number hexutil.Uint64) (*et
 	}, nil
 }
 
+// SafeHeadAtL1Block returns the L2 safe head that was fully derived from the L1 chain up to the specified L1 block.
+func (n *nodeAPI) SafeHeadAtL1Block(ctx context.Context, number hexutil.Uint64) (*eth.SafeHeadResponse, error) {
+	recordDur := n.m.RecordRPCServerRequest("optimism_safeHeadAtL1Block")
+	defer recordDur()
+	l1Block, safeHead, err := n.safeDB.SafeHeadAtL1(ctx, uint64(number))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get safe head at L1 block %d: %w", number, err)
+	}
+	return &eth.SafeHeadResponse{
+		L1Block:  l1Block,
+		SafeHead: safeHead,
+	}, nil
+}
+
 func (n *nodeAPI) SyncStatus(ctx context.Context) (*eth.SyncStatus, error) {
 	recordDur := n.m.RecordRPCServerRequest("optimism_syncStatus")
 	defer recordDur()
@@ -126,3 +150,85 @@ func (n *nodeAPI) Version(ctx context.Context) (string, error) {
 	defer recordDur()
 	return version.Version + "-" + version.Meta, nil
 }
+
+// subscriptionsAPI serves the optimism_subscribe subscriptions, which push sync status changes to websocket clients.
+type subscriptionsAPI struct {
+	dr  driverClient
+	log log.Logger
+	m   metrics.RPCMetricer
+}
+
+func NewSubscriptionsAPI(dr driverClient, log log.Logger, m metrics.RPCMetricer) *subscriptionsAPI {
+	return &subscriptionsAPI{
+		dr:  dr,
+		log: log,
+		m:   m,
+	}
+}
+
+// SyncStatus sends the sync status whenever it changes.
+func (s *subscriptionsAPI) SyncStatus(ctx context.Context) (*gethrpc.Subscription, error) {
+	return s.subscribe(ctx, "syncStatus", func(status *eth.SyncStatus) any { return *status })
+}
+
+// UnsafeHead sends the unsafe L2 head whenever it changes.
+func (s *subscriptionsAPI) UnsafeHead(ctx context.Context) (*gethrpc.Subscription, error) {
+	return s.subscribe(ctx, "unsafeHead", func(status *eth.SyncStatus) any { return status.UnsafeL2 })
+}
+
+// SafeHead sends the safe L2 head whenever it changes.
+func (s *subscriptionsAPI) SafeHead(ctx context.Context) (*gethrpc.Subscription, error) {
+	return s.subscribe(ctx, "safeHead", func(status *eth.SyncStatus) any { return status.SafeL2 })
+}
+
+// FinalizedHead sends the finalized L2 head whenever it changes.
+func (s *subscriptionsAPI) FinalizedHead(ctx context.Context) (*gethrpc.Subscription, error) {
+	return s.subscribe(ctx, "finalizedHead", func(status *eth.SyncStatus) any { return status.FinalizedL2 })
+}
+
+// subscribe creates a subscription that sends the current value of the topic, selected from the sync status,
+// and then every changed value.
+func (s *subscriptionsAPI) subscribe(ctx context.Context, topic string, selectFn func(status *eth.SyncStatus) any) (*gethrpc.Subscription, error) {
+	recordDur := s.m.RecordRPCServerRequest("optimism_subscribe")
+	defer recordDur()
+	notifier, supported := gethrpc.NotifierFromContext(ctx)
+	if !supported {
+		return nil, gethrpc.ErrNotificationsUnsupported
+	}
+	// Subscribe before reading the current status, so that no change can be missed in between.
+	updates := make(chan *eth.SyncStatus, 10)
+	sub := s.dr.SubscribeSyncStatus(updates)
+	status, err := s.dr.SyncStatus(ctx)
+	if err != nil {
+		sub.Unsubscribe()
+		return nil, fmt.Errorf("failed to get sync status: %w", err)
+	}
+	rpcSub := notifier.CreateSubscription()
+	go func() {
+		defer sub.Unsubscribe()
+		last := selectFn(status)
+		if err := notifier.Notify(rpcSub.ID, last); err != nil {
+			s.log.Debug("Failed to send subscription update", "topic", topic, "err", err)
+			return
+		}
+		for {
+			select {
+			case status := <-updates:
+				value := selectFn(status)
+				if value == last {
+					continue
+				}
+				last = value
+				if err := notifier.Notify(rpcSub.ID, value); err != nil {
+					s.log.Debug("Failed to send subscription update", "topic", topic, "err", err)
+					return
+				}
+			case <-rpcSub.Err():
+				return
+			case <-sub.Err():
+				return
+			}
+		}
+	}()
+	return rpcSub, nil
+}
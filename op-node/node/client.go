@@ -8,6 +8,7 @@ import (
 
 	"github.com/ethereum-optimism/optimism/op-node/rollup"
 	"github.com/ethereum-optimism/optimism/op-service/client"
+	opmetrics "github.com/ethereum-optimism/optimism/op-service/metrics"
 	"github.com/ethereum-optimism/optimism/op-service/sources"
 
 	"github.com/ethereum/go-ethereum/log"
@@ -32,7 +33,14 @@ type L1EndpointSetup interface {
 	// Setup a RPC client to a L1 node to pull rollup input-data from.
 	// The results of the RPC client may be trusted for faster processing, or strictly validated.
 	// The kind of the RPC may be non-basic, to optimize RPC usage.
-	Setup(ctx context.Context, log log.Logger, rollupCfg *rollup.Config) (cl client.RPC, rpcCfg *sources.L1ClientConfig, err error)
+	// The metrics record the health of the L1 endpoints, if several endpoints are configured.
+	Setup(ctx context.Context, log log.Logger, rollupCfg *rollup.Config, metrics opmetrics.RPCEndpointMetricer) (cl client.RPC, rpcCfg *sources.L1ClientConfig, err error)
+	Check() error
+}
+
+type L1BeaconEndpointSetup interface {
+	// Setup a HTTP client to a L1 beacon node, to fetch blobs with.
+	Setup(ctx context.Context, log log.Logger) (cl client.HTTP, err error)
 	Check() error
 }
 
@@ -134,7 +142,9 @@ func (cfg *PreparedL2SyncEndpoint) Check() error {
 }
 
 type L1EndpointConfig struct {
-	L1NodeAddr string // Address of L1 User JSON-RPC endpoint to use (eth namespace required)
+	// Address of L1 User JSON-RPC endpoint to use (eth namespace required).
+	// Several comma-separated endpoints can be listed, to fail over between.
+	L1NodeAddr string
 
 	// L1TrustRPC: if we trust the L1 RPC we do not have to validate L1 response contents like headers
 	// against block hashes, or cached transaction sender addresses.
@@ -156,6 +166,15 @@ type L1EndpointConfig struct {
 	// It is recommended to use websockets or IPC for efficient following of the changing block.
 	// Setting this to 0 disables polling.
 	HttpPollInterval time.Duration
+
+	// MaxLagBlocks is the number of blocks an L1 endpoint may lag behind the other endpoints,
+	// before requests fail over to another endpoint. 0 disables the lag check.
+	// Only used if several endpoints are configured.
+	MaxLagBlocks uint64
+
+	// Quorum is the number of L1 endpoints that must agree on the block hash of L1 blocks fetched by number,
+	// and of L1 receipts. Only used if several endpoints are configured, 0 is the same as 1.
+	Quorum int
 }
 
 var _ L1EndpointSetup = (*L1EndpointConfig)(nil)
@@ -167,13 +186,30 @@ func (cfg *L1EndpointConfig) Check() error {
 	if cfg.RateLimit < 0 {
 		return fmt.Errorf("rate limit cannot be negative")
 	}
+	if addrs := client.SplitAddrs(cfg.L1NodeAddr); len(addrs) > 1 {
+		multiCfg := cfg.multiRPCConfig()
+		if err := multiCfg.Check(len(addrs)); err != nil {
+			return fmt.Errorf("invalid L1 endpoints config: %w", err)
+		}
+	}
 	return nil
 }
 
-func (cfg *L1EndpointConfig) Setup(ctx context.Context, log log.Logger, rollupCfg *rollup.Config) (client.RPC, *sources.L1ClientConfig, error) {
+func (cfg *L1EndpointConfig) multiRPCConfig() client.MultiRPCConfig {
+	multiCfg := client.DefaultMultiRPCConfig
+	multiCfg.MaxLagBlocks = cfg.MaxLagBlocks
+	if cfg.Quorum != 0 {
+		multiCfg.Quorum = cfg.Quorum
+	}
+	return multiCfg
+}
+
+func (cfg *L1EndpointConfig) Setup(ctx context.Context, log log.Logger, rollupCfg *rollup.Config, metrics opmetrics.RPCEndpointMetricer) (client.RPC, *sources.L1ClientConfig, error) {
 	opts := []client.RPCOption{
 		client.WithHttpPollInterval(cfg.HttpPollInterval),
 		client.WithDialBackoff(10),
+		client.WithMultiRPCConfig(cfg.multiRPCConfig()),
+		client.WithRPCEndpointMetrics(metrics),
 	}
 	if cfg.RateLimit != 0 {
 		opts = append(opts, client.WithRateLimit(cfg.RateLimit, cfg.BatchSize))
@@ -197,7 +233,7 @@ type PreparedL1Endpoint struct {
 
 var _ L1EndpointSetup = (*PreparedL1Endpoint)(nil)
 
-func (p *PreparedL1Endpoint) Setup(ctx context.Context, log log.Logger, rollupCfg *rollup.Config) (client.RPC, *sources.L1ClientConfig, error) {
+func (p *PreparedL1Endpoint) Setup(ctx context.Context, log log.Logger, rollupCfg *rollup.Config, metrics opmetrics.RPCEndpointMetricer) (client.RPC, *sources.L1ClientConfig, error) {
 	return p.Client, sources.L1ClientDefaultConfig(rollupCfg, p.TrustRPC, p.RPCProviderKind), nil
 }
 
@@ -208,3 +244,20 @@ func (cfg *PreparedL1Endpoint) Check() error {
 
 	return nil
 }
+
+type L1BeaconEndpointConfig struct {
+	BeaconAddr string // Address of L1 User Beacon-API endpoint to use (beacon namespace required)
+}
+
+var _ L1BeaconEndpointSetup = (*L1BeaconEndpointConfig)(nil)
+
+func (cfg *L1BeaconEndpointConfig) Setup(ctx context.Context, log log.Logger) (cl client.HTTP, err error) {
+	return client.NewBasicHTTPClient(cfg.BeaconAddr, log), nil
+}
+
+func (cfg *L1BeaconEndpointConfig) Check() error {
+	if cfg.BeaconAddr == "" {
+		return errors.New("expected beacon address, but got none")
+	}
+	return nil
+}
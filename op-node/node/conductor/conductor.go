@@ -0,0 +1,295 @@
+package conductor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/raft"
+	raftboltdb "github.com/hashicorp/raft-boltdb/v2"
+
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+)
+
+var (
+	ErrNotLeader = errors.New("sequencer is not the cluster leader")
+	errClosed    = errors.New("conductor closed")
+)
+
+const (
+	raftDBFile        = "raft.db"
+	snapshotsRetained = 2
+	maxPool           = 3
+	transportTimeout  = 10 * time.Second
+)
+
+// RaftConductor coordinates a cluster of sequencers with raft, so that only the leader sequences new blocks.
+// Each block sealed by the leader is committed to the raft log before it is published, so that a newly elected
+// leader resumes sequencing from the last committed unsafe head.
+type RaftConductor struct {
+	log       log.Logger
+	cfg       *Config
+	fsm       *unsafeHeadFSM
+	raftCfg   *raft.Config
+	transport raft.Transport
+	logs      raft.LogStore
+	stable    raft.StableStore
+	snapshots raft.SnapshotStore
+	closeFn   func() error
+
+	// onCommitted is passed the blocks committed by other leaders.
+	onCommitted func(payload *eth.ExecutionPayload)
+
+	raft *raft.Raft // set by Start
+
+	notifyCh chan bool
+	updates  chan struct{}
+	// ready is true while this node is the raft leader and has applied all blocks committed by previous leaders.
+	ready atomic.Bool
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewRaftConductor creates a conductor that persists the raft state in cfg.StorageDir and communicates with the other
+// nodes of the cluster over TCP. onCommitted is passed the blocks committed by other leaders, in order, so that this
+// node can take over from the last committed block.
+func NewRaftConductor(logger log.Logger, cfg *Config, onCommitted func(payload *eth.ExecutionPayload)) (*RaftConductor, error) {
+	raftLogger := newRaftLogger(logger)
+	if err := os.MkdirAll(cfg.StorageDir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create storage dir: %w", err)
+	}
+	store, err := raftboltdb.New(raftboltdb.Options{Path: filepath.Join(cfg.StorageDir, raftDBFile)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open raft log: %w", err)
+	}
+	snapshots, err := raft.NewFileSnapshotStoreWithLogger(cfg.StorageDir, snapshotsRetained, raftLogger)
+	if err != nil {
+		_ = store.Close()
+		return nil, fmt.Errorf("failed to open raft snapshots: %w", err)
+	}
+	addr, err := net.ResolveTCPAddr("tcp", cfg.RaftAddr)
+	if err != nil {
+		_ = store.Close()
+		return nil, fmt.Errorf("invalid raft address %v: %w", cfg.RaftAddr, err)
+	}
+	transport, err := raft.NewTCPTransportWithLogger(cfg.RaftAddr, addr, maxPool, transportTimeout, raftLogger)
+	if err != nil {
+		_ = store.Close()
+		return nil, fmt.Errorf("failed to start raft transport: %w", err)
+	}
+	closeFn := func() error {
+		return errors.Join(transport.Close(), store.Close())
+	}
+	return newRaftConductor(logger, cfg, transport, store, store, snapshots, onCommitted, closeFn), nil
+}
+
+func newRaftConductor(logger log.Logger, cfg *Config, transport raft.Transport, logs raft.LogStore, stable raft.StableStore, snapshots raft.SnapshotStore, onCommitted func(payload *eth.ExecutionPayload), closeFn func() error) *RaftConductor {
+	c := &RaftConductor{
+		log:         logger,
+		cfg:         cfg,
+		transport:   transport,
+		logs:        logs,
+		stable:      stable,
+		snapshots:   snapshots,
+		closeFn:     closeFn,
+		onCommitted: onCommitted,
+		// Raft overwrites a pending notification rather than blocking when the buffer is full.
+		notifyCh: make(chan bool, 1),
+		updates:  make(chan struct{}, 1),
+		done:     make(chan struct{}),
+	}
+	c.fsm = newUnsafeHeadFSM(logger, c.forwardCommitted)
+	c.raftCfg = raft.DefaultConfig()
+	c.raftCfg.LocalID = raft.ServerID(cfg.ServerID)
+	c.raftCfg.NotifyCh = c.notifyCh
+	c.raftCfg.Logger = newRaftLogger(logger)
+	return c
+}
+
+// Start joins the raft cluster, bootstrapping it with the configured peers if there is no existing raft state.
+func (c *RaftConductor) Start() error {
+	hasState, err := raft.HasExistingState(c.logs, c.stable, c.snapshots)
+	if err != nil {
+		return fmt.Errorf("failed to check for existing raft state: %w", err)
+	}
+	r, err := raft.NewRaft(c.raftCfg, c.fsm, c.logs, c.stable, c.snapshots, c.transport)
+	if err != nil {
+		return fmt.Errorf("failed to start raft: %w", err)
+	}
+	c.raft = r
+	if !hasState && len(c.cfg.Peers) > 0 {
+		var servers []raft.Server
+		for _, peer := range c.cfg.Peers {
+			servers = append(servers, raft.Server{
+				Suffrage: raft.Voter,
+				ID:       raft.ServerID(peer.ID),
+				Address:  raft.ServerAddress(peer.Addr),
+			})
+		}
+		// Every node is bootstrapped with the same configuration, so it doesn't matter which node is first.
+		if err := r.BootstrapCluster(raft.Configuration{Servers: servers}).Error(); err != nil && !errors.Is(err, raft.ErrCantBootstrap) {
+			return fmt.Errorf("failed to bootstrap raft cluster: %w", err)
+		}
+	}
+	c.wg.Add(1)
+	go c.observeLeadership()
+	c.log.Info("Started sequencer conductor", "id", c.cfg.ServerID, "addr", c.transport.LocalAddr(), "bootstrapped", !hasState)
+	return nil
+}
+
+func (c *RaftConductor) observeLeadership() {
+	defer c.wg.Done()
+	for {
+		select {
+		case leader := <-c.notifyCh:
+			c.ready.Store(false)
+			if leader {
+				// Wait until the blocks committed by previous leaders are applied, so we resume from the latest one.
+				if err := c.wait(c.raft.Barrier(c.cfg.CommitTimeout)); err != nil {
+					c.log.Error("Failed to apply committed blocks", "err", err)
+					if c.raft.State() == raft.Leader {
+						// Let another node take over, rather than staying the leader without sequencing.
+						if err := c.wait(c.raft.LeadershipTransfer()); err != nil {
+							c.log.Warn("Failed to transfer leadership", "err", err)
+						}
+					}
+				} else {
+					c.ready.Store(true)
+				}
+			}
+			c.log.Info("Sequencer leadership changed", "leader", c.ready.Load(), "unsafe_head", c.CommittedUnsafeHead())
+			select {
+			case c.updates <- struct{}{}:
+			default:
+			}
+		case <-c.done:
+			return
+		}
+	}
+}
+
+// wait waits for the raft operation to complete, unless the conductor is closed first.
+func (c *RaftConductor) wait(future raft.Future) error {
+	result := make(chan error, 1)
+	go func() {
+		result <- future.Error()
+	}()
+	select {
+	case err := <-result:
+		return err
+	case <-c.done:
+		return errClosed
+	}
+}
+
+func (c *RaftConductor) forwardCommitted(payload *eth.ExecutionPayload) {
+	// The leader already has the blocks it committed itself. Blocks committed by previous leaders are still forwarded
+	// while the new leader applies them.
+	if c.ready.Load() {
+		return
+	}
+	c.onCommitted(payload)
+}
+
+// Leader returns true if this node is the cluster leader, and has applied all blocks committed by previous leaders.
+func (c *RaftConductor) Leader() bool {
+	return c.ready.Load()
+}
+
+// LeaderUpdates is signalled whenever the result of Leader may have changed.
+func (c *RaftConductor) LeaderUpdates() <-chan struct{} {
+	return c.updates
+}
+
+// CommittedUnsafeHead returns the latest block committed by the cluster, or a zero block ID if there is none.
+func (c *RaftConductor) CommittedUnsafeHead() eth.BlockID {
+	head := c.fsm.Head()
+	if head == nil {
+		return eth.BlockID{}
+	}
+	return head.ID()
+}
+
+// CommitUnsafePayload commits a block sealed by this node to the raft log.
+// Returns ErrNotLeader if this node is not the leader.
+func (c *RaftConductor) CommitUnsafePayload(ctx context.Context, payload *eth.ExecutionPayload) error {
+	if !c.Leader() {
+		return ErrNotLeader
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode block: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, c.cfg.CommitTimeout)
+	defer cancel()
+	future := c.raft.Apply(data, c.cfg.CommitTimeout)
+	result := make(chan error, 1)
+	go func() {
+		if err := future.Error(); err != nil {
+			result <- err
+			return
+		}
+		err, _ := future.Response().(error)
+		result <- err
+	}()
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("failed to commit block %v: %w", payload.ID(), ctx.Err())
+	case err := <-result:
+		if err != nil {
+			return fmt.Errorf("failed to commit block %v: %w", payload.ID(), err)
+		}
+		return nil
+	}
+}
+
+// Close leaves the cluster. If this node is the leader, leadership is handed over to another node first.
+func (c *RaftConductor) Close() error {
+	var result error
+	if c.raft != nil {
+		if c.raft.State() == raft.Leader {
+			if err := c.raft.LeadershipTransfer().Error(); err != nil {
+				c.log.Warn("Failed to transfer leadership before shutdown", "err", err)
+			}
+		}
+		close(c.done)
+		if err := c.raft.Shutdown().Error(); err != nil {
+			result = fmt.Errorf("failed to shutdown raft: %w", err)
+		}
+		c.wg.Wait()
+	}
+	if c.closeFn != nil {
+		result = errors.Join(result, c.closeFn())
+	}
+	return result
+}
+
+// newRaftLogger creates a raft logger that writes to logger.
+func newRaftLogger(logger log.Logger) hclog.Logger {
+	return hclog.New(&hclog.LoggerOptions{
+		Name:        "raft",
+		Level:       hclog.Info,
+		Output:      &logWriter{log: logger},
+		DisableTime: true,
+	})
+}
+
+type logWriter struct {
+	log log.Logger
+}
+
+func (w *logWriter) Write(p []byte) (int, error) {
+	w.log.Info(strings.TrimSpace(string(p)))
+	return len(p), nil
+}
@@ -0,0 +1,168 @@
+package conductor
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/hashicorp/raft"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+)
+
+func TestRaftConductor_LeaderCommitsBlocks(t *testing.T) {
+	nodes := setupCluster(t, 3)
+	leader := waitForLeader(t, nodes)
+	require.Len(t, leader.conductor.LeaderUpdates(), 1)
+	require.Equal(t, eth.BlockID{}, leader.conductor.CommittedUnsafeHead())
+
+	block1 := payload(1, common.Hash{})
+	block2 := payload(2, block1.BlockHash)
+	require.NoError(t, leader.conductor.CommitUnsafePayload(context.Background(), block1))
+	require.NoError(t, leader.conductor.CommitUnsafePayload(context.Background(), block2))
+	require.Equal(t, block2.ID(), leader.conductor.CommittedUnsafeHead())
+	require.Empty(t, leader.Committed(), "should not pass the blocks it committed itself back to the leader")
+
+	for _, node := range nodes {
+		if node == leader {
+			continue
+		}
+		require.Eventually(t, func() bool {
+			return node.conductor.CommittedUnsafeHead() == block2.ID()
+		}, 10*time.Second, 10*time.Millisecond)
+		require.Equal(t, []eth.BlockID{block1.ID(), block2.ID()}, node.Committed())
+		require.ErrorIs(t, node.conductor.CommitUnsafePayload(context.Background(), payload(3, block2.BlockHash)), ErrNotLeader)
+	}
+}
+
+func TestRaftConductor_NewLeaderResumesFromCommittedHead(t *testing.T) {
+	nodes := setupCluster(t, 3)
+	oldLeader := waitForLeader(t, nodes)
+	block1 := payload(1, common.Hash{})
+	require.NoError(t, oldLeader.conductor.CommitUnsafePayload(context.Background(), block1))
+
+	oldLeader.Close(t)
+	var remaining []*testNode
+	for _, node := range nodes {
+		if node != oldLeader {
+			remaining = append(remaining, node)
+		}
+	}
+	newLeader := waitForLeader(t, remaining)
+	require.Equal(t, block1.ID(), newLeader.conductor.CommittedUnsafeHead())
+	require.Equal(t, []eth.BlockID{block1.ID()}, newLeader.Committed())
+
+	block2 := payload(2, block1.BlockHash)
+	require.NoError(t, newLeader.conductor.CommitUnsafePayload(context.Background(), block2))
+	for _, node := range remaining {
+		if node == newLeader {
+			continue
+		}
+		require.Eventually(t, func() bool {
+			return node.conductor.CommittedUnsafeHead() == block2.ID()
+		}, 10*time.Second, 10*time.Millisecond)
+		require.Equal(t, []eth.BlockID{block1.ID(), block2.ID()}, node.Committed())
+	}
+}
+
+type testNode struct {
+	conductor *RaftConductor
+	closed    bool
+
+	lock      sync.Mutex
+	committed []eth.BlockID
+}
+
+func (n *testNode) onCommitted(payload *eth.ExecutionPayload) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	n.committed = append(n.committed, payload.ID())
+}
+
+func (n *testNode) Committed() []eth.BlockID {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	return append([]eth.BlockID(nil), n.committed...)
+}
+
+func (n *testNode) Close(t *testing.T) {
+	if n.closed {
+		return
+	}
+	n.closed = true
+	require.NoError(t, n.conductor.Close())
+}
+
+// setupCluster starts an in-process cluster of count nodes, connected by in-memory transports.
+func setupCluster(t *testing.T, count int) []*testNode {
+	var peers []Peer
+	var transports []*raft.InmemTransport
+	for i := 0; i < count; i++ {
+		addr, transport := raft.NewInmemTransport("")
+		peers = append(peers, Peer{ID: string(rune('a' + i)), Addr: string(addr)})
+		transports = append(transports, transport)
+	}
+	for _, a := range transports {
+		for _, b := range transports {
+			if a != b {
+				a.Connect(b.LocalAddr(), b)
+			}
+		}
+	}
+
+	nodes := make([]*testNode, 0, count)
+	for i, transport := range transports {
+		cfg := &Config{
+			Enabled:       true,
+			ServerID:      peers[i].ID,
+			RaftAddr:      peers[i].Addr,
+			Peers:         peers,
+			CommitTimeout: 5 * time.Second,
+		}
+		logger := testlog.Logger(t, log.LvlInfo).New("node", cfg.ServerID)
+		store := raft.NewInmemStore()
+		node := &testNode{}
+		node.conductor = newRaftConductor(logger, cfg, transport, store, store, raft.NewInmemSnapshotStore(), node.onCommitted, transport.Close)
+		node.conductor.raftCfg.HeartbeatTimeout = 50 * time.Millisecond
+		node.conductor.raftCfg.ElectionTimeout = 50 * time.Millisecond
+		node.conductor.raftCfg.LeaderLeaseTimeout = 50 * time.Millisecond
+		node.conductor.raftCfg.CommitTimeout = 5 * time.Millisecond
+		require.NoError(t, node.conductor.Start())
+		t.Cleanup(func() {
+			node.Close(t)
+		})
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
+
+// waitForLeader waits until exactly one of nodes is ready to sequence as the leader.
+func waitForLeader(t *testing.T, nodes []*testNode) *testNode {
+	var leader *testNode
+	require.Eventually(t, func() bool {
+		leader = nil
+		for _, node := range nodes {
+			if node.conductor.Leader() {
+				if leader != nil {
+					return false
+				}
+				leader = node
+			}
+		}
+		return leader != nil
+	}, 10*time.Second, 10*time.Millisecond)
+	return leader
+}
+
+func payload(num uint64, parent common.Hash) *eth.ExecutionPayload {
+	return &eth.ExecutionPayload{
+		ParentHash:  parent,
+		BlockNumber: eth.Uint64Quantity(num),
+		BlockHash:   common.Hash{0xaa, byte(num)},
+	}
+}
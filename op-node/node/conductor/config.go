@@ -0,0 +1,79 @@
+package conductor
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type Config struct {
+	// Enabled is true when the sequencer is part of a high-availability cluster of sequencers, of which only the
+	// leader sequences new blocks.
+	Enabled bool
+
+	// ServerID uniquely identifies this node in the cluster.
+	ServerID string
+
+	// RaftAddr is the host:port the raft transport listens on. Other nodes in the cluster reach this node at the
+	// same address.
+	RaftAddr string
+
+	// StorageDir is the directory the raft log and snapshots are persisted in.
+	StorageDir string
+
+	// Peers lists every node of the initial cluster, including this node.
+	// The cluster is only bootstrapped with these peers if there is no existing raft state in StorageDir.
+	Peers []Peer
+
+	// CommitTimeout is the maximum time to wait for a sequenced block to be committed by the cluster.
+	CommitTimeout time.Duration
+}
+
+type Peer struct {
+	ID   string
+	Addr string
+}
+
+func (c *Config) Check() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.ServerID == "" {
+		return errors.New("missing server ID")
+	}
+	if c.RaftAddr == "" {
+		return errors.New("missing raft address")
+	}
+	if c.StorageDir == "" {
+		return errors.New("missing storage dir")
+	}
+	if c.CommitTimeout <= 0 {
+		return errors.New("commit timeout must be positive")
+	}
+	if len(c.Peers) > 0 {
+		found := false
+		for _, peer := range c.Peers {
+			if peer.ID == c.ServerID {
+				found = true
+			}
+		}
+		if !found {
+			return fmt.Errorf("peers do not include this server %v", c.ServerID)
+		}
+	}
+	return nil
+}
+
+// ParsePeers parses peers in the form <server-id>=<host:port>.
+func ParsePeers(values []string) ([]Peer, error) {
+	peers := make([]Peer, 0, len(values))
+	for _, value := range values {
+		id, addr, ok := strings.Cut(value, "=")
+		if !ok || id == "" || addr == "" {
+			return nil, fmt.Errorf("invalid peer %q, expected <server-id>=<host:port>", value)
+		}
+		peers = append(peers, Peer{ID: id, Addr: addr})
+	}
+	return peers, nil
+}
@@ -0,0 +1,105 @@
+package conductor
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/hashicorp/raft"
+
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+)
+
+// unsafeHeadFSM is the raft state machine of the cluster. Each log entry is a block sealed by the leader, and the
+// state is the latest committed block, which the next leader resumes sequencing from.
+type unsafeHeadFSM struct {
+	log  log.Logger
+	lock sync.RWMutex
+	head *eth.ExecutionPayload
+
+	// onCommitted is called with each block applied from the raft log, in order, and with the block restored from a
+	// snapshot.
+	onCommitted func(payload *eth.ExecutionPayload)
+}
+
+var _ raft.FSM = (*unsafeHeadFSM)(nil)
+
+func newUnsafeHeadFSM(logger log.Logger, onCommitted func(payload *eth.ExecutionPayload)) *unsafeHeadFSM {
+	return &unsafeHeadFSM{
+		log:         logger,
+		onCommitted: onCommitted,
+	}
+}
+
+func (f *unsafeHeadFSM) Apply(l *raft.Log) interface{} {
+	var payload eth.ExecutionPayload
+	if err := json.Unmarshal(l.Data, &payload); err != nil {
+		f.log.Error("Failed to decode committed block", "index", l.Index, "err", err)
+		return fmt.Errorf("failed to decode committed block: %w", err)
+	}
+	f.lock.Lock()
+	f.head = &payload
+	f.lock.Unlock()
+	f.log.Debug("Applied committed block", "index", l.Index, "id", payload.ID())
+	f.onCommitted(&payload)
+	return nil
+}
+
+func (f *unsafeHeadFSM) Snapshot() (raft.FSMSnapshot, error) {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+	if f.head == nil {
+		return &unsafeHeadSnapshot{}, nil
+	}
+	data, err := json.Marshal(f.head)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode unsafe head: %w", err)
+	}
+	return &unsafeHeadSnapshot{data: data}, nil
+}
+
+func (f *unsafeHeadFSM) Restore(snapshot io.ReadCloser) error {
+	defer snapshot.Close()
+	data, err := io.ReadAll(snapshot)
+	if err != nil {
+		return fmt.Errorf("failed to read snapshot: %w", err)
+	}
+	var head *eth.ExecutionPayload
+	if len(data) > 0 {
+		head = new(eth.ExecutionPayload)
+		if err := json.Unmarshal(data, head); err != nil {
+			return fmt.Errorf("failed to decode snapshot: %w", err)
+		}
+	}
+	f.lock.Lock()
+	f.head = head
+	f.lock.Unlock()
+	// A lagging node may be sent a snapshot instead of the blocks committed since, so pass on the latest block.
+	if head != nil {
+		f.onCommitted(head)
+	}
+	return nil
+}
+
+// Head returns the latest committed block, or nil if no block was committed yet.
+func (f *unsafeHeadFSM) Head() *eth.ExecutionPayload {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+	return f.head
+}
+
+type unsafeHeadSnapshot struct {
+	data []byte
+}
+
+func (s *unsafeHeadSnapshot) Persist(sink raft.SnapshotSink) error {
+	if _, err := sink.Write(s.data); err != nil {
+		_ = sink.Cancel()
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	return sink.Close()
+}
+
+func (s *unsafeHeadSnapshot) Release() {}
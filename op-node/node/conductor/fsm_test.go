@@ -0,0 +1,80 @@
+package conductor
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/hashicorp/raft"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+)
+
+func TestUnsafeHeadFSM_Apply(t *testing.T) {
+	var committed []eth.BlockID
+	fsm := newUnsafeHeadFSM(testlog.Logger(t, log.LvlInfo), func(payload *eth.ExecutionPayload) {
+		committed = append(committed, payload.ID())
+	})
+	require.Nil(t, fsm.Head())
+
+	block := payload(1, common.Hash{})
+	require.Nil(t, fsm.Apply(logEntry(t, 1, block)))
+	require.Equal(t, block.ID(), fsm.Head().ID())
+	require.Equal(t, []eth.BlockID{block.ID()}, committed)
+
+	err, ok := fsm.Apply(&raft.Log{Index: 2, Data: []byte("invalid")}).(error)
+	require.True(t, ok)
+	require.Error(t, err)
+	require.Equal(t, block.ID(), fsm.Head().ID())
+}
+
+func TestUnsafeHeadFSM_SnapshotRestore(t *testing.T) {
+	logger := testlog.Logger(t, log.LvlInfo)
+	store := raft.NewInmemSnapshotStore()
+	persist := func(fsm *unsafeHeadFSM) string {
+		snapshot, err := fsm.Snapshot()
+		require.NoError(t, err)
+		sink, err := store.Create(raft.SnapshotVersionMax, 1, 1, raft.Configuration{}, 1, nil)
+		require.NoError(t, err)
+		require.NoError(t, snapshot.Persist(sink))
+		return sink.ID()
+	}
+	restore := func(id string, fsm *unsafeHeadFSM) {
+		_, data, err := store.Open(id)
+		require.NoError(t, err)
+		require.NoError(t, fsm.Restore(data))
+	}
+
+	t.Run("Empty", func(t *testing.T) {
+		id := persist(newUnsafeHeadFSM(logger, func(*eth.ExecutionPayload) {}))
+		restored := newUnsafeHeadFSM(logger, func(*eth.ExecutionPayload) {
+			t.Fatal("should not pass on a block")
+		})
+		restore(id, restored)
+		require.Nil(t, restored.Head())
+	})
+
+	t.Run("WithHead", func(t *testing.T) {
+		fsm := newUnsafeHeadFSM(logger, func(*eth.ExecutionPayload) {})
+		block := payload(5, common.Hash{0x04})
+		require.Nil(t, fsm.Apply(logEntry(t, 1, block)))
+		id := persist(fsm)
+
+		var committed []eth.BlockID
+		restored := newUnsafeHeadFSM(logger, func(payload *eth.ExecutionPayload) {
+			committed = append(committed, payload.ID())
+		})
+		restore(id, restored)
+		require.Equal(t, block.ID(), restored.Head().ID())
+		require.Equal(t, []eth.BlockID{block.ID()}, committed)
+	})
+}
+
+func logEntry(t *testing.T, index uint64, payload *eth.ExecutionPayload) *raft.Log {
+	data, err := json.Marshal(payload)
+	require.NoError(t, err)
+	return &raft.Log{Index: index, Type: raft.LogCommand, Data: data}
+}
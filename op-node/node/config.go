@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/ethereum-optimism/optimism/op-node/flags"
+	"github.com/ethereum-optimism/optimism/op-node/node/conductor"
 	"github.com/ethereum-optimism/optimism/op-node/p2p"
 	"github.com/ethereum-optimism/optimism/op-node/rollup"
 	"github.com/ethereum-optimism/optimism/op-node/rollup/driver"
@@ -21,6 +22,14 @@ type Config struct {
 	L2     L2EndpointSetup
 	L2Sync L2SyncEndpointSetup
 
+	// Beacon is the L1 beacon-node endpoint to fetch blobs from.
+	// Optional, but required if blobs data-availability is enabled in the rollup config.
+	Beacon L1BeaconEndpointSetup
+
+	// DAServer is the DA server endpoint to resolve DA commitments with.
+	// Optional, but required if DA commitments are enabled in the rollup config.
+	DAServer string
+
 	Driver driver.Config
 
 	Rollup rollup.Config
@@ -42,6 +51,20 @@ type Config struct {
 
 	ConfigPersistence ConfigPersistence
 
+	// Conductor configures the high-availability cluster of sequencers this node is part of.
+	// Disabled unless Conductor.Enabled is set.
+	Conductor conductor.Config
+
+	// SafeDBPath is the path of the database recording the L1 block each L2 block was made safe at.
+	// Disabled if empty.
+	SafeDBPath string
+
+	// L1CachePath is the path of the disk cache of L1 headers, transactions and receipts,
+	// which speeds up syncing again after a restart. Disabled if empty.
+	L1CachePath string
+	// L1CacheSize is the maximum size of the L1 disk cache in bytes.
+	L1CacheSize uint64
+
 	// RuntimeConfigReloadInterval defines the interval between runtime config reloads.
 	// Disabled if <= 0.
 	// Runtime config changes should be picked up from log-events,
@@ -125,6 +148,16 @@ func (cfg *Config) Check() error {
 	if err := cfg.Rollup.Check(); err != nil {
 		return fmt.Errorf("rollup config error: %w", err)
 	}
+	if cfg.Beacon != nil {
+		if err := cfg.Beacon.Check(); err != nil {
+			return fmt.Errorf("beacon endpoint config error: %w", err)
+		}
+	} else if cfg.Rollup.BlobsEnabledL1Timestamp != nil {
+		return errors.New("blobs data-availability is enabled in the rollup config, but no L1 beacon endpoint is configured")
+	}
+	if cfg.DAServer == "" && cfg.Rollup.DACommitmentsL1Timestamp != nil {
+		return errors.New("DA commitments are enabled in the rollup config, but no DA server is configured")
+	}
 	if err := cfg.Metrics.Check(); err != nil {
 		return fmt.Errorf("metrics config error: %w", err)
 	}
@@ -136,6 +169,12 @@ func (cfg *Config) Check() error {
 			return fmt.Errorf("p2p config error: %w", err)
 		}
 	}
+	if err := cfg.Conductor.Check(); err != nil {
+		return fmt.Errorf("sequencer conductor config error: %w", err)
+	}
+	if cfg.Conductor.Enabled && !cfg.Driver.SequencerEnabled {
+		return errors.New("sequencer conductor is enabled, but sequencing is not enabled")
+	}
 	if !(cfg.RollupHalt == "" || cfg.RollupHalt == "major" || cfg.RollupHalt == "minor" || cfg.RollupHalt == "patch") {
 		return fmt.Errorf("invalid rollup halting option: %q", cfg.RollupHalt)
 	}
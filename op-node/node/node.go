@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"net"
 	"strconv"
 	"sync/atomic"
@@ -20,16 +21,27 @@ import (
 
 	"github.com/ethereum-optimism/optimism/op-node/heartbeat"
 	"github.com/ethereum-optimism/optimism/op-node/metrics"
+	"github.com/ethereum-optimism/optimism/op-node/node/conductor"
+	"github.com/ethereum-optimism/optimism/op-node/node/safedb"
 	"github.com/ethereum-optimism/optimism/op-node/p2p"
+	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
 	"github.com/ethereum-optimism/optimism/op-node/rollup/driver"
 	"github.com/ethereum-optimism/optimism/op-node/version"
 	"github.com/ethereum-optimism/optimism/op-service/client"
+	"github.com/ethereum-optimism/optimism/op-service/dacommit"
 	"github.com/ethereum-optimism/optimism/op-service/eth"
 	oppprof "github.com/ethereum-optimism/optimism/op-service/pprof"
 	"github.com/ethereum-optimism/optimism/op-service/retry"
 	"github.com/ethereum-optimism/optimism/op-service/sources"
+	"github.com/ethereum-optimism/optimism/op-service/sources/caching"
 )
 
+type closableSafeDB interface {
+	derive.SafeHeadListener
+	SafeDBReader
+	io.Closer
+}
+
 type OpNode struct {
 	log        log.Logger
 	appVersion string
@@ -39,15 +51,21 @@ type OpNode struct {
 	l1SafeSub      ethereum.Subscription // Subscription to get L1 safe blocks, a.k.a. justified data (polling)
 	l1FinalizedSub ethereum.Subscription // Subscription to get L1 safe blocks, a.k.a. justified data (polling)
 
-	l1Source  *sources.L1Client     // L1 Client to fetch data from
-	l2Driver  *driver.Driver        // L2 Engine to Sync
-	l2Source  *sources.EngineClient // L2 Execution Engine RPC bindings
-	rpcSync   *sources.SyncClient   // Alt-sync RPC client, optional (may be nil)
-	server    *rpcServer            // RPC server hosting the rollup-node API
-	p2pNode   *p2p.NodeP2P          // P2P node functionality
-	p2pSigner p2p.Signer            // p2p gogssip application messages will be signed with this signer
-	tracer    Tracer                // tracer to get events for testing/debugging
-	runCfg    *RuntimeConfig        // runtime configurables
+	l1Source  *sources.L1Client       // L1 Client to fetch data from
+	l1Cache   *caching.DiskCache      // L1 data persisted by the L1 client, optional (may be nil)
+	beacon    *sources.L1BeaconClient // L1 Beacon-API client to fetch blobs from, optional (may be nil)
+	l2Driver  *driver.Driver          // L2 Engine to Sync
+	l2Source  *sources.EngineClient   // L2 Execution Engine RPC bindings
+	rpcSync   *sources.SyncClient     // Alt-sync RPC client, optional (may be nil)
+	server    *rpcServer              // RPC server hosting the rollup-node API
+	p2pNode   *p2p.NodeP2P            // P2P node functionality
+	p2pSigner p2p.Signer              // p2p gogssip application messages will be signed with this signer
+	tracer    Tracer                  // tracer to get events for testing/debugging
+	runCfg    *RuntimeConfig          // runtime configurables
+
+	safeDB closableSafeDB // records the L1 block each L2 block was made safe at, may be safedb.Disabled
+
+	conductor *conductor.RaftConductor // coordinates sequencing with a high-availability cluster, optional (may be nil)
 
 	rollupHalt string // when to halt the rollup, disabled if empty
 
@@ -110,6 +128,9 @@ func (n *OpNode) init(ctx context.Context, cfg *Config, snapshotLog log.Logger)
 	if err := n.initL1(ctx, cfg); err != nil {
 		return fmt.Errorf("failed to init L1: %w", err)
 	}
+	if err := n.initL1BeaconAPI(ctx, cfg); err != nil {
+		return fmt.Errorf("failed to init L1 Beacon API client: %w", err)
+	}
 	if err := n.initL2(ctx, cfg, snapshotLog); err != nil {
 		return fmt.Errorf("failed to init L2: %w", err)
 	}
@@ -151,11 +172,20 @@ func (n *OpNode) initTracer(ctx context.Context, cfg *Config) error {
 }
 
 func (n *OpNode) initL1(ctx context.Context, cfg *Config) error {
-	l1Node, rpcCfg, err := cfg.L1.Setup(ctx, n.log, &cfg.Rollup)
+	l1Node, rpcCfg, err := cfg.L1.Setup(ctx, n.log, &cfg.Rollup, n.metrics)
 	if err != nil {
 		return fmt.Errorf("failed to get L1 RPC client: %w", err)
 	}
 
+	if cfg.L1CachePath != "" {
+		n.log.Info("L1 disk cache enabled", "path", cfg.L1CachePath, "size", cfg.L1CacheSize)
+		n.l1Cache, err = caching.NewDiskCache(n.metrics.L1DiskCache, cfg.L1CachePath, cfg.L1CacheSize)
+		if err != nil {
+			return fmt.Errorf("failed to create L1 disk cache at %v: %w", cfg.L1CachePath, err)
+		}
+		rpcCfg.DiskCache = n.l1Cache
+	}
+
 	n.l1Source, err = sources.NewL1Client(
 		client.NewInstrumentedRPC(l1Node, n.metrics), n.log, n.metrics.L1SourceCache, rpcCfg)
 	if err != nil {
@@ -190,6 +220,23 @@ func (n *OpNode) initL1(ctx context.Context, cfg *Config) error {
 	return nil
 }
 
+func (n *OpNode) initL1BeaconAPI(ctx context.Context, cfg *Config) error {
+	if cfg.Beacon == nil {
+		n.log.Warn("No beacon endpoint configured. Configuration is mandatory once blobs data-availability is enabled")
+		return nil
+	}
+	httpClient, err := cfg.Beacon.Setup(ctx, n.log)
+	if err != nil {
+		return fmt.Errorf("failed to setup L1 beacon client: %w", err)
+	}
+	n.beacon = sources.NewL1BeaconClient(httpClient)
+	// Fail early if the beacon node is not reachable, rather than on the first blob retrieval.
+	if _, err := n.beacon.GetTimeToSlotFn(ctx); err != nil {
+		return fmt.Errorf("failed to check L1 beacon API: %w", err)
+	}
+	return nil
+}
+
 func (n *OpNode) initRuntimeConfig(ctx context.Context, cfg *Config) error {
 	// attempt to load runtime config, repeat N times
 	n.runCfg = NewRuntimeConfig(n.log, n.l1Source, &cfg.Rollup)
@@ -301,7 +348,34 @@ func (n *OpNode) initL2(ctx context.Context, cfg *Config, snapshotLog log.Logger
 		return err
 	}
 
-	n.l2Driver = driver.NewDriver(&cfg.Driver, &cfg.Rollup, n.l2Source, n.l1Source, n, n, n.log, snapshotLog, n.metrics, cfg.ConfigPersistence, &cfg.Sync)
+	var l1Blobs derive.L1BlobsFetcher
+	if n.beacon != nil { // avoid a typed nil interface value
+		l1Blobs = n.beacon
+	}
+	var daFetcher derive.DAFetcher
+	if cfg.DAServer != "" {
+		daFetcher = dacommit.NewDAClient(cfg.DAServer)
+	}
+	if cfg.SafeDBPath != "" {
+		n.log.Info("Safe head database enabled", "path", cfg.SafeDBPath)
+		safeDB, err := safedb.NewSafeDB(n.log, cfg.SafeDBPath)
+		if err != nil {
+			return fmt.Errorf("failed to create safe head database at %v: %w", cfg.SafeDBPath, err)
+		}
+		n.safeDB = safeDB
+	} else {
+		n.safeDB = safedb.Disabled
+	}
+	var seqConductor driver.SequencerConductor
+	if cfg.Conductor.Enabled {
+		n.log.Info("Sequencer conductor enabled", "id", cfg.Conductor.ServerID, "addr", cfg.Conductor.RaftAddr)
+		n.conductor, err = conductor.NewRaftConductor(n.log, &cfg.Conductor, n.onCommittedUnsafePayload)
+		if err != nil {
+			return fmt.Errorf("failed to create sequencer conductor: %w", err)
+		}
+		seqConductor = n.conductor // avoid a typed nil interface value
+	}
+	n.l2Driver = driver.NewDriver(&cfg.Driver, &cfg.Rollup, n.l2Source, n.l1Source, l1Blobs, daFetcher, n, n, n.log, snapshotLog, n.metrics, cfg.ConfigPersistence, n.safeDB, seqConductor, &cfg.Sync)
 
 	return nil
 }
@@ -323,7 +397,7 @@ func (n *OpNode) initRPCSync(ctx context.Context, cfg *Config) error {
 }
 
 func (n *OpNode) initRPCServer(ctx context.Context, cfg *Config) error {
-	server, err := newRPCServer(ctx, &cfg.RPC, &cfg.Rollup, n.l2Source.L2Client, n.l2Driver, n.log, n.appVersion, n.metrics)
+	server, err := newRPCServer(ctx, &cfg.RPC, &cfg.Rollup, n.l2Source.L2Client, n.l2Driver, n.safeDB, n.log, n.appVersion, n.metrics)
 	if err != nil {
 		return err
 	}
@@ -423,6 +497,14 @@ func (n *OpNode) initP2PSigner(ctx context.Context, cfg *Config) error {
 }
 
 func (n *OpNode) Start(ctx context.Context) error {
+	// Join the sequencer cluster before sequencing, blocks committed by the cluster are passed to the driver once started
+	if n.conductor != nil {
+		if err := n.conductor.Start(); err != nil {
+			n.log.Error("Could not start the sequencer conductor", "err", err)
+			return err
+		}
+	}
+
 	n.log.Info("Starting execution engine driver")
 
 	// start driving engine: sync blocks by deriving them from L1 and driving them into the engine
@@ -518,6 +600,18 @@ func (n *OpNode) OnUnsafeL2Payload(ctx context.Context, from peer.ID, payload *e
 	return nil
 }
 
+// onCommittedUnsafePayload passes the blocks committed by the leader of the sequencer cluster to the driver,
+// so this node is ready to take over sequencing from the last committed block.
+func (n *OpNode) onCommittedUnsafePayload(payload *eth.ExecutionPayload) {
+	n.log.Info("Received execution payload committed by sequencer cluster", "id", payload.ID())
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+	if err := n.l2Driver.OnUnsafeL2Payload(ctx, payload); err != nil {
+		n.log.Warn("failed to notify engine driver of committed L2 payload", "err", err, "id", payload.ID())
+	}
+}
+
 func (n *OpNode) RequestL2Range(ctx context.Context, start, end eth.L2BlockRef) error {
 	if n.rpcSync != nil {
 		return n.rpcSync.RequestL2Range(ctx, start, end)
@@ -580,6 +674,13 @@ func (n *OpNode) Stop(ctx context.Context) error {
 		n.l1HeadsSub.Unsubscribe()
 	}
 
+	// leave the sequencer cluster, handing over leadership, before the driver stops processing committed blocks
+	if n.conductor != nil {
+		if err := n.conductor.Close(); err != nil {
+			result = multierror.Append(result, fmt.Errorf("failed to close sequencer conductor: %w", err))
+		}
+	}
+
 	// close L2 driver
 	if n.l2Driver != nil {
 		if err := n.l2Driver.Close(); err != nil {
@@ -594,6 +695,13 @@ func (n *OpNode) Stop(ctx context.Context) error {
 		}
 	}
 
+	// close the safe head database, once the driver no longer updates it
+	if n.safeDB != nil {
+		if err := n.safeDB.Close(); err != nil {
+			result = multierror.Append(result, fmt.Errorf("failed to close safe head db: %w", err))
+		}
+	}
+
 	// Wait for the runtime config loader to be done using the data sources before closing them
 	if n.runtimeConfigReloaderDone != nil {
 		<-n.runtimeConfigReloaderDone
@@ -609,6 +717,13 @@ func (n *OpNode) Stop(ctx context.Context) error {
 		n.l1Source.Close()
 	}
 
+	// close the L1 disk cache, once the L1 data source no longer uses it
+	if n.l1Cache != nil {
+		if err := n.l1Cache.Close(); err != nil {
+			result = multierror.Append(result, fmt.Errorf("failed to close L1 disk cache: %w", err))
+		}
+	}
+
 	if result == nil { // mark as closed if we successfully fully closed
 		n.closed.Store(true)
 	}
@@ -0,0 +1,28 @@
+package safedb
+
+import (
+	"context"
+
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+)
+
+// DisabledDB is used when the safe head database is not enabled. Updates are ignored and queries return ErrNotEnabled.
+type DisabledDB struct{}
+
+var Disabled = &DisabledDB{}
+
+func (d *DisabledDB) SafeHeadUpdated(_ eth.L2BlockRef, _ eth.BlockID) error {
+	return nil
+}
+
+func (d *DisabledDB) SafeHeadReset(_ eth.L2BlockRef) error {
+	return nil
+}
+
+func (d *DisabledDB) SafeHeadAtL1(_ context.Context, _ uint64) (l1 eth.BlockID, safeHead eth.BlockID, err error) {
+	return eth.BlockID{}, eth.BlockID{}, ErrNotEnabled
+}
+
+func (d *DisabledDB) Close() error {
+	return nil
+}
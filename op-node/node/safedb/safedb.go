@@ -0,0 +1,224 @@
+package safedb
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+)
+
+var (
+	ErrNotFound     = errors.New("no safe head found")
+	ErrNotEnabled   = errors.New("safe head database not enabled")
+	ErrInvalidEntry = errors.New("invalid safe head entry")
+)
+
+const (
+	// safeByL1BlockNumPrefix prefixes the keys of the safe head entries, which are followed by the L1 block number.
+	safeByL1BlockNumPrefix byte = 0
+	keyLen                      = 1 + 8
+	// valueLen is the length of the L1 block hash, followed by the L2 safe head hash and number.
+	valueLen = 32 + 32 + 8
+)
+
+// endKey is the exclusive upper bound of all safe head entry keys.
+var endKey = []byte{safeByL1BlockNumPrefix + 1}
+
+type entry struct {
+	l1 eth.BlockID
+	l2 eth.BlockID
+}
+
+// SafeDB records the L2 safe head at each L1 block, so it can later be determined which L2 blocks
+// were fully derivable from the L1 chain up to a given L1 block.
+// Only L1 blocks at which the safe head advanced are recorded.
+type SafeDB struct {
+	log log.Logger
+	m   sync.Mutex
+	db  *pebble.DB
+}
+
+func NewSafeDB(logger log.Logger, path string) (*SafeDB, error) {
+	db, err := pebble.Open(path, &pebble.Options{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open safe head database %v: %w", path, err)
+	}
+	return &SafeDB{
+		log: logger,
+		db:  db,
+	}, nil
+}
+
+// SafeHeadUpdated records that safeHead was fully derived from the L1 chain up to l1Head.
+// Nothing is recorded if the latest recorded safe head already includes safeHead, e.g. when the L1 origin advances
+// without deriving new L2 blocks or when blocks are derived again after a reset. Any entries for l1Head and later
+// L1 blocks are replaced, as they were recorded on an L1 chain that has since been reorged.
+func (d *SafeDB) SafeHeadUpdated(safeHead eth.L2BlockRef, l1Head eth.BlockID) error {
+	d.m.Lock()
+	defer d.m.Unlock()
+	last, err := d.lastEntry()
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+	if err == nil && last.l2.Number >= safeHead.Number {
+		return nil
+	}
+	d.log.Debug("Record safe head", "l2", safeHead.ID(), "l1", l1Head)
+	batch := d.db.NewBatch()
+	defer batch.Close()
+	if err := batch.DeleteRange(key(l1Head.Number), endKey, pebble.Sync); err != nil {
+		return fmt.Errorf("failed to remove replaced safe head entries: %w", err)
+	}
+	if err := batch.Set(key(l1Head.Number), value(entry{l1: l1Head, l2: safeHead.ID()}), pebble.Sync); err != nil {
+		return fmt.Errorf("failed to record safe head: %w", err)
+	}
+	if err := batch.Commit(pebble.Sync); err != nil {
+		return fmt.Errorf("failed to commit safe head update: %w", err)
+	}
+	return nil
+}
+
+// SafeHeadReset removes the entries for the L2 blocks after resetSafeHead, as the derivation pipeline was reset and
+// will derive those blocks again.
+// If resetSafeHead was derived part way through the first removed L1 block, an entry recording resetSafeHead at that
+// L1 block is kept instead.
+func (d *SafeDB) SafeHeadReset(resetSafeHead eth.L2BlockRef) error {
+	d.m.Lock()
+	defer d.m.Unlock()
+	iter, err := d.db.NewIter(&pebble.IterOptions{
+		LowerBound: key(0),
+		UpperBound: endKey,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create iterator: %w", err)
+	}
+	defer iter.Close()
+	var firstRemoved *entry
+	var previous *entry
+	for valid := iter.Last(); valid; valid = iter.Prev() {
+		e, err := decode(iter.Key(), iter.Value())
+		if err != nil {
+			return err
+		}
+		if e.l2.Number <= resetSafeHead.Number {
+			previous = &e
+			break
+		}
+		firstRemoved = &e
+	}
+	if err := iter.Error(); err != nil {
+		return fmt.Errorf("failed to read safe head entries: %w", err)
+	}
+	if firstRemoved == nil {
+		return nil
+	}
+	d.log.Info("Removing safe head entries after reset", "safeHead", resetSafeHead.ID(), "l1", firstRemoved.l1)
+	batch := d.db.NewBatch()
+	defer batch.Close()
+	if err := batch.DeleteRange(key(firstRemoved.l1.Number), endKey, pebble.Sync); err != nil {
+		return fmt.Errorf("failed to remove safe head entries: %w", err)
+	}
+	if previous == nil || previous.l2.Number < resetSafeHead.Number {
+		reset := entry{l1: firstRemoved.l1, l2: resetSafeHead.ID()}
+		if err := batch.Set(key(reset.l1.Number), value(reset), pebble.Sync); err != nil {
+			return fmt.Errorf("failed to record reset safe head: %w", err)
+		}
+	}
+	if err := batch.Commit(pebble.Sync); err != nil {
+		return fmt.Errorf("failed to commit safe head reset: %w", err)
+	}
+	return nil
+}
+
+// SafeHeadAtL1 returns the L2 safe head that was fully derived from the L1 chain up to l1BlockNum, along with the
+// L1 block it was recorded at, which is the latest recorded L1 block not after l1BlockNum.
+// Returns ErrNotFound if no safe head was recorded at or before l1BlockNum.
+func (d *SafeDB) SafeHeadAtL1(ctx context.Context, l1BlockNum uint64) (l1Block eth.BlockID, safeHead eth.BlockID, err error) {
+	d.m.Lock()
+	defer d.m.Unlock()
+	iter, err := d.db.NewIterWithContext(ctx, &pebble.IterOptions{
+		LowerBound: key(0),
+		UpperBound: upperBound(l1BlockNum),
+	})
+	if err != nil {
+		return eth.BlockID{}, eth.BlockID{}, fmt.Errorf("failed to create iterator: %w", err)
+	}
+	defer iter.Close()
+	if !iter.Last() {
+		if err := iter.Error(); err != nil {
+			return eth.BlockID{}, eth.BlockID{}, fmt.Errorf("failed to read safe head entries: %w", err)
+		}
+		return eth.BlockID{}, eth.BlockID{}, ErrNotFound
+	}
+	e, err := decode(iter.Key(), iter.Value())
+	if err != nil {
+		return eth.BlockID{}, eth.BlockID{}, err
+	}
+	return e.l1, e.l2, nil
+}
+
+func (d *SafeDB) Close() error {
+	return d.db.Close()
+}
+
+// lastEntry returns the entry for the latest L1 block, or ErrNotFound if there are no entries.
+func (d *SafeDB) lastEntry() (entry, error) {
+	iter, err := d.db.NewIter(&pebble.IterOptions{
+		LowerBound: key(0),
+		UpperBound: endKey,
+	})
+	if err != nil {
+		return entry{}, fmt.Errorf("failed to create iterator: %w", err)
+	}
+	defer iter.Close()
+	if !iter.Last() {
+		if err := iter.Error(); err != nil {
+			return entry{}, fmt.Errorf("failed to read safe head entries: %w", err)
+		}
+		return entry{}, ErrNotFound
+	}
+	return decode(iter.Key(), iter.Value())
+}
+
+func key(l1BlockNum uint64) []byte {
+	k := make([]byte, keyLen)
+	k[0] = safeByL1BlockNumPrefix
+	binary.BigEndian.PutUint64(k[1:], l1BlockNum)
+	return k
+}
+
+// upperBound returns the exclusive upper bound of the keys for L1 blocks up to and including l1BlockNum.
+func upperBound(l1BlockNum uint64) []byte {
+	if l1BlockNum == math.MaxUint64 {
+		return endKey
+	}
+	return key(l1BlockNum + 1)
+}
+
+func value(e entry) []byte {
+	v := make([]byte, 0, valueLen)
+	v = append(v, e.l1.Hash[:]...)
+	v = append(v, e.l2.Hash[:]...)
+	return binary.BigEndian.AppendUint64(v, e.l2.Number)
+}
+
+func decode(k []byte, v []byte) (entry, error) {
+	if len(k) != keyLen || k[0] != safeByL1BlockNumPrefix {
+		return entry{}, fmt.Errorf("%w: invalid key %x", ErrInvalidEntry, k)
+	}
+	if len(v) != valueLen {
+		return entry{}, fmt.Errorf("%w: invalid value length %v", ErrInvalidEntry, len(v))
+	}
+	return entry{
+		l1: eth.BlockID{Hash: common.Hash(v[:32]), Number: binary.BigEndian.Uint64(k[1:])},
+		l2: eth.BlockID{Hash: common.Hash(v[32:64]), Number: binary.BigEndian.Uint64(v[64:])},
+	}, nil
+}
@@ -0,0 +1,185 @@
+package safedb
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+)
+
+func TestStoreSafeHeads(t *testing.T) {
+	logger := testlog.Logger(t, log.LvlInfo)
+	dir := t.TempDir()
+	db, err := NewSafeDB(logger, dir)
+	require.NoError(t, err)
+	l2a := l2Ref(1)
+	l2b := l2Ref(2)
+	l1a := l1ID(10)
+	l1b := l1ID(15)
+
+	require.NoError(t, db.SafeHeadUpdated(l2a, l1a))
+	require.NoError(t, db.SafeHeadUpdated(l2b, l1b))
+
+	verifySafeHeads := func(db *SafeDB) {
+		_, _, err := db.SafeHeadAtL1(context.Background(), l1a.Number-1)
+		require.ErrorIs(t, err, ErrNotFound)
+
+		actualL1, actualL2, err := db.SafeHeadAtL1(context.Background(), l1a.Number)
+		require.NoError(t, err)
+		require.Equal(t, l1a, actualL1)
+		require.Equal(t, l2a.ID(), actualL2)
+
+		actualL1, actualL2, err = db.SafeHeadAtL1(context.Background(), l1b.Number-1)
+		require.NoError(t, err)
+		require.Equal(t, l1a, actualL1)
+		require.Equal(t, l2a.ID(), actualL2)
+
+		actualL1, actualL2, err = db.SafeHeadAtL1(context.Background(), l1b.Number)
+		require.NoError(t, err)
+		require.Equal(t, l1b, actualL1)
+		require.Equal(t, l2b.ID(), actualL2)
+
+		actualL1, actualL2, err = db.SafeHeadAtL1(context.Background(), math.MaxUint64)
+		require.NoError(t, err)
+		require.Equal(t, l1b, actualL1)
+		require.Equal(t, l2b.ID(), actualL2)
+	}
+	verifySafeHeads(db)
+
+	// Data is persisted across restarts
+	require.NoError(t, db.Close())
+	db, err = NewSafeDB(logger, dir)
+	require.NoError(t, err)
+	defer db.Close()
+	verifySafeHeads(db)
+}
+
+func TestSafeHeadUpdated(t *testing.T) {
+	t.Run("UpdateSafeHeadAtSameL1", func(t *testing.T) {
+		db := newTestDB(t)
+		require.NoError(t, db.SafeHeadUpdated(l2Ref(1), l1ID(10)))
+		require.NoError(t, db.SafeHeadUpdated(l2Ref(2), l1ID(10)))
+		requireSafeHeadAt(t, db, 10, l1ID(10), l2Ref(2))
+	})
+
+	t.Run("IgnoreUnchangedSafeHead", func(t *testing.T) {
+		db := newTestDB(t)
+		require.NoError(t, db.SafeHeadUpdated(l2Ref(1), l1ID(10)))
+		require.NoError(t, db.SafeHeadUpdated(l2Ref(1), l1ID(11)))
+		requireSafeHeadAt(t, db, 11, l1ID(10), l2Ref(1))
+	})
+
+	t.Run("IgnoreOlderSafeHead", func(t *testing.T) {
+		db := newTestDB(t)
+		require.NoError(t, db.SafeHeadUpdated(l2Ref(5), l1ID(10)))
+		// Safe head already recorded at a later L1 block is not recorded at an earlier L1 block
+		require.NoError(t, db.SafeHeadUpdated(l2Ref(5), l1ID(8)))
+		require.NoError(t, db.SafeHeadUpdated(l2Ref(4), l1ID(8)))
+		_, _, err := db.SafeHeadAtL1(context.Background(), 9)
+		require.ErrorIs(t, err, ErrNotFound)
+		requireSafeHeadAt(t, db, 10, l1ID(10), l2Ref(5))
+	})
+
+	t.Run("ReplaceEntriesFromLaterL1Blocks", func(t *testing.T) {
+		db := newTestDB(t)
+		require.NoError(t, db.SafeHeadUpdated(l2Ref(1), l1ID(10)))
+		require.NoError(t, db.SafeHeadUpdated(l2Ref(2), l1ID(12)))
+		reorgedL1 := eth.BlockID{Hash: common.Hash{0xbb}, Number: 11}
+		require.NoError(t, db.SafeHeadUpdated(l2Ref(3), reorgedL1))
+		requireSafeHeadAt(t, db, 11, reorgedL1, l2Ref(3))
+		requireSafeHeadAt(t, db, 12, reorgedL1, l2Ref(3))
+	})
+}
+
+func TestSafeHeadReset(t *testing.T) {
+	t.Run("RemoveLaterEntries", func(t *testing.T) {
+		db := newTestDB(t)
+		require.NoError(t, db.SafeHeadUpdated(l2Ref(1), l1ID(10)))
+		require.NoError(t, db.SafeHeadUpdated(l2Ref(2), l1ID(11)))
+		require.NoError(t, db.SafeHeadUpdated(l2Ref(3), l1ID(12)))
+
+		require.NoError(t, db.SafeHeadReset(l2Ref(2)))
+		requireSafeHeadAt(t, db, 11, l1ID(11), l2Ref(2))
+		requireSafeHeadAt(t, db, 12, l1ID(11), l2Ref(2))
+
+		// Derivation after the reset records the safe heads again
+		require.NoError(t, db.SafeHeadUpdated(l2Ref(2), l1ID(9)))
+		require.NoError(t, db.SafeHeadUpdated(l2Ref(3), l1ID(12)))
+		requireSafeHeadAt(t, db, 9, eth.BlockID{}, eth.L2BlockRef{})
+		requireSafeHeadAt(t, db, 11, l1ID(11), l2Ref(2))
+		requireSafeHeadAt(t, db, 12, l1ID(12), l2Ref(3))
+	})
+
+	t.Run("ResetToBlockWithinL1Block", func(t *testing.T) {
+		db := newTestDB(t)
+		require.NoError(t, db.SafeHeadUpdated(l2Ref(1), l1ID(10)))
+		require.NoError(t, db.SafeHeadUpdated(l2Ref(5), l1ID(11)))
+		require.NoError(t, db.SafeHeadUpdated(l2Ref(8), l1ID(12)))
+
+		// Block 3 was derived from L1 block 11, so is recorded as safe there
+		require.NoError(t, db.SafeHeadReset(l2Ref(3)))
+		requireSafeHeadAt(t, db, 10, l1ID(10), l2Ref(1))
+		requireSafeHeadAt(t, db, 11, l1ID(11), l2Ref(3))
+		requireSafeHeadAt(t, db, 12, l1ID(11), l2Ref(3))
+	})
+
+	t.Run("ResetBeforeFirstEntry", func(t *testing.T) {
+		db := newTestDB(t)
+		require.NoError(t, db.SafeHeadUpdated(l2Ref(5), l1ID(10)))
+		require.NoError(t, db.SafeHeadUpdated(l2Ref(8), l1ID(12)))
+
+		require.NoError(t, db.SafeHeadReset(l2Ref(3)))
+		requireSafeHeadAt(t, db, 10, l1ID(10), l2Ref(3))
+		requireSafeHeadAt(t, db, 12, l1ID(10), l2Ref(3))
+	})
+
+	t.Run("NothingToRemove", func(t *testing.T) {
+		db := newTestDB(t)
+		require.NoError(t, db.SafeHeadReset(l2Ref(3)))
+		require.NoError(t, db.SafeHeadUpdated(l2Ref(1), l1ID(10)))
+		require.NoError(t, db.SafeHeadReset(l2Ref(3)))
+		requireSafeHeadAt(t, db, 10, l1ID(10), l2Ref(1))
+	})
+}
+
+func TestDisabled(t *testing.T) {
+	require.NoError(t, Disabled.SafeHeadUpdated(l2Ref(1), l1ID(10)))
+	require.NoError(t, Disabled.SafeHeadReset(l2Ref(1)))
+	_, _, err := Disabled.SafeHeadAtL1(context.Background(), 10)
+	require.ErrorIs(t, err, ErrNotEnabled)
+}
+
+func newTestDB(t *testing.T) *SafeDB {
+	db, err := NewSafeDB(testlog.Logger(t, log.LvlInfo), t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, db.Close())
+	})
+	return db
+}
+
+// requireSafeHeadAt checks the safe head at l1BlockNum, or that there is none if expectedL2 is zero.
+func requireSafeHeadAt(t *testing.T, db *SafeDB, l1BlockNum uint64, expectedL1 eth.BlockID, expectedL2 eth.L2BlockRef) {
+	actualL1, actualL2, err := db.SafeHeadAtL1(context.Background(), l1BlockNum)
+	if expectedL2 == (eth.L2BlockRef{}) {
+		require.ErrorIs(t, err, ErrNotFound)
+		return
+	}
+	require.NoError(t, err)
+	require.Equal(t, expectedL1, actualL1)
+	require.Equal(t, expectedL2.ID(), actualL2)
+}
+
+func l1ID(num uint64) eth.BlockID {
+	return eth.BlockID{Hash: common.Hash{0x01, byte(num)}, Number: num}
+}
+
+func l2Ref(num uint64) eth.L2BlockRef {
+	return eth.L2BlockRef{Hash: common.Hash{0x02, byte(num)}, Number: num}
+}
@@ -6,6 +6,7 @@ import (
 	"net"
 	"net/http"
 	"strconv"
+	"strings"
 
 	ophttp "github.com/ethereum-optimism/optimism/op-service/httputil"
 	"github.com/ethereum/go-ethereum/log"
@@ -21,14 +22,16 @@ import (
 type rpcServer struct {
 	endpoint   string
 	apis       []rpc.API
+	srv        *rpc.Server
 	httpServer *ophttp.HTTPServer
 	appVersion string
 	log        log.Logger
 	sources.L2Client
 }
 
-func newRPCServer(ctx context.Context, rpcCfg *RPCConfig, rollupCfg *rollup.Config, l2Client l2EthClient, dr driverClient, log log.Logger, appVersion string, m metrics.Metricer) (*rpcServer, error) {
-	api := NewNodeAPI(rollupCfg, l2Client, dr, log.New("rpc", "node"), m)
+func newRPCServer(ctx context.Context, rpcCfg *RPCConfig, rollupCfg *rollup.Config, l2Client l2EthClient, dr driverClient, safeDB SafeDBReader, log log.Logger, appVersion string, m metrics.Metricer) (*rpcServer, error) {
+	api := NewNodeAPI(rollupCfg, l2Client, dr, safeDB, log.New("rpc", "node"), m)
+	subscriptions := NewSubscriptionsAPI(dr, log.New("rpc", "subscriptions"), m)
 	// TODO: extend RPC config with options for WS, IPC and HTTP RPC connections
 	endpoint := net.JoinHostPort(rpcCfg.ListenAddr, strconv.Itoa(rpcCfg.ListenPort))
 	r := &rpcServer{
@@ -37,6 +40,10 @@ func newRPCServer(ctx context.Context, rpcCfg *RPCConfig, rollupCfg *rollup.Conf
 			Namespace:     "optimism",
 			Service:       api,
 			Authenticated: false,
+		}, {
+			Namespace:     "optimism",
+			Service:       subscriptions,
+			Authenticated: false,
 		}},
 		appVersion: appVersion,
 		log:        log,
@@ -73,27 +80,45 @@ func (s *rpcServer) Start() error {
 	// defaults to localhost, which will prevent containers from
 	// calling into the opnode without an "invalid host" error.
 	nodeHandler := node.NewHTTPHandlerStack(srv, []string{"*"}, []string{"*"}, nil)
+	// Websocket connections are served on the same port, for the optimism_subscribe subscriptions.
+	wsHandler := node.NewWSHandlerStack(srv.WebsocketHandler([]string{"*"}), nil)
 
 	mux := http.NewServeMux()
-	mux.Handle("/", nodeHandler)
+	mux.Handle("/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isWebsocket(r) {
+			wsHandler.ServeHTTP(w, r)
+			return
+		}
+		nodeHandler.ServeHTTP(w, r)
+	}))
 	mux.HandleFunc("/healthz", healthzHandler(s.appVersion))
 
 	hs, err := ophttp.StartHTTPServer(s.endpoint, mux)
 	if err != nil {
 		return fmt.Errorf("failed to start HTTP RPC server: %w", err)
 	}
+	s.srv = srv
 	s.httpServer = hs
 	return nil
 }
 
 func (r *rpcServer) Stop(ctx context.Context) error {
-	return r.httpServer.Stop(ctx)
+	err := r.httpServer.Stop(ctx)
+	// Close the websocket connections, which are not closed by the HTTP server, ending their subscriptions.
+	r.srv.Stop()
+	return err
 }
 
 func (r *rpcServer) Addr() net.Addr {
 	return r.httpServer.Addr()
 }
 
+// isWebsocket checks whether the request is a websocket upgrade request.
+func isWebsocket(r *http.Request) bool {
+	return strings.EqualFold(r.Header.Get("Upgrade"), "websocket") &&
+		strings.Contains(strings.ToLower(r.Header.Get("Connection")), "upgrade")
+}
+
 func healthzHandler(appVersion string) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		_, _ = w.Write([]byte(appVersion))
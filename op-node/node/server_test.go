@@ -5,16 +5,22 @@ import (
 	"encoding/json"
 	"math/rand"
 	"testing"
+	"time"
 
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/log"
+	gethrpc "github.com/ethereum/go-ethereum/rpc"
 
 	"github.com/ethereum-optimism/optimism/op-node/metrics"
+	"github.com/ethereum-optimism/optimism/op-node/node/safedb"
 	"github.com/ethereum-optimism/optimism/op-node/rollup"
 	"github.com/ethereum-optimism/optimism/op-node/version"
 	rpcclient "github.com/ethereum-optimism/optimism/op-service/client"
@@ -101,7 +107,7 @@ func TestOutputAtBlock(t *testing.T) {
 	status := randomSyncStatus(rand.New(rand.NewSource(123)))
 	drClient.ExpectBlockRefWithStatus(0xdcdc89, ref, status, nil)
 
-	server, err := newRPCServer(context.Background(), rpcCfg, rollupCfg, l2Client, drClient, log, "0.0", metrics.NoopMetrics)
+	server, err := newRPCServer(context.Background(), rpcCfg, rollupCfg, l2Client, drClient, safedb.Disabled, log, "0.0", metrics.NoopMetrics)
 	require.NoError(t, err)
 	require.NoError(t, server.Start())
 	defer func() {
@@ -135,7 +141,7 @@ func TestVersion(t *testing.T) {
 	rollupCfg := &rollup.Config{
 		// ignore other rollup config info in this test
 	}
-	server, err := newRPCServer(context.Background(), rpcCfg, rollupCfg, l2Client, drClient, log, "0.0", metrics.NoopMetrics)
+	server, err := newRPCServer(context.Background(), rpcCfg, rollupCfg, l2Client, drClient, safedb.Disabled, log, "0.0", metrics.NoopMetrics)
 	assert.NoError(t, err)
 	assert.NoError(t, server.Start())
 	defer func() {
@@ -181,7 +187,7 @@ func TestSyncStatus(t *testing.T) {
 	rollupCfg := &rollup.Config{
 		// ignore other rollup config info in this test
 	}
-	server, err := newRPCServer(context.Background(), rpcCfg, rollupCfg, l2Client, drClient, log, "0.0", metrics.NoopMetrics)
+	server, err := newRPCServer(context.Background(), rpcCfg, rollupCfg, l2Client, drClient, safedb.Disabled, log, "0.0", metrics.NoopMetrics)
 	assert.NoError(t, err)
 	assert.NoError(t, server.Start())
 	defer func() {
@@ -197,8 +203,134 @@ func TestSyncStatus(t *testing.T) {
 	assert.Equal(t, status, out)
 }
 
+func TestSubscriptions(t *testing.T) {
+	log := testlog.Logger(t, log.LvlError)
+	l2Client := &testutils.MockL2Client{}
+	drClient := &mockDriverClient{}
+	rng := rand.New(rand.NewSource(1234))
+	status := randomSyncStatus(rng)
+	drClient.On("SyncStatus").Return(status)
+
+	rpcCfg := &RPCConfig{
+		ListenAddr: "localhost",
+		ListenPort: 0,
+	}
+	rollupCfg := &rollup.Config{
+		// ignore other rollup config info in this test
+	}
+	server, err := newRPCServer(context.Background(), rpcCfg, rollupCfg, l2Client, drClient, safedb.Disabled, log, "0.0", metrics.NoopMetrics)
+	require.NoError(t, err)
+	require.NoError(t, server.Start())
+	defer func() {
+		require.NoError(t, server.Stop(context.Background()))
+	}()
+
+	client, err := rpcclient.NewRPC(context.Background(), log, "ws://"+server.Addr().String(), rpcclient.WithDialBackoff(3))
+	require.NoError(t, err)
+	defer client.Close()
+
+	statuses := make(chan *eth.SyncStatus, 10)
+	statusSub, err := client.Subscribe(context.Background(), "optimism", statuses, "syncStatus")
+	require.NoError(t, err)
+	defer statusSub.Unsubscribe()
+	unsafeHeads := make(chan eth.L2BlockRef, 10)
+	unsafeSub, err := client.Subscribe(context.Background(), "optimism", unsafeHeads, "unsafeHead")
+	require.NoError(t, err)
+	defer unsafeSub.Unsubscribe()
+
+	// The current values are sent first
+	require.Equal(t, status, receive(t, statuses))
+	require.Equal(t, status.UnsafeL2, receive(t, unsafeHeads))
+
+	// Only changes of the unsafe head are sent to the unsafe head subscription
+	safeChanged := *status
+	safeChanged.SafeL2 = testutils.RandomL2BlockRef(rng)
+	unsafeChanged := safeChanged
+	unsafeChanged.UnsafeL2 = testutils.RandomL2BlockRef(rng)
+	require.Equal(t, 2, drClient.syncStatusFeed.Send(&safeChanged))
+	require.Equal(t, 2, drClient.syncStatusFeed.Send(&unsafeChanged))
+	require.Equal(t, &safeChanged, receive(t, statuses))
+	require.Equal(t, &unsafeChanged, receive(t, statuses))
+	require.Equal(t, unsafeChanged.UnsafeL2, receive(t, unsafeHeads))
+
+	// Subscriptions are not supported over HTTP
+	httpClient, err := rpcclient.NewRPC(context.Background(), log, "http://"+server.Addr().String(), rpcclient.WithDialBackoff(3))
+	require.NoError(t, err)
+	_, err = httpClient.Subscribe(context.Background(), "optimism", statuses, "syncStatus")
+	require.ErrorIs(t, err, gethrpc.ErrNotificationsUnsupported)
+}
+
+func receive[T any](t *testing.T, ch <-chan T) T {
+	select {
+	case v := <-ch:
+		return v
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for subscription update")
+		panic("unreachable")
+	}
+}
+
+func TestSafeHeadAtL1Block(t *testing.T) {
+	log := testlog.Logger(t, log.LvlError)
+	l2Client := &testutils.MockL2Client{}
+	drClient := &mockDriverClient{}
+	safeDB := &mockSafeDBReader{}
+	l1Block := eth.BlockID{Hash: common.Hash{0x11}, Number: 20}
+	safeHead := eth.BlockID{Hash: common.Hash{0x22}, Number: 300}
+	safeDB.ExpectSafeHeadAtL1(25, l1Block, safeHead, nil)
+
+	rpcCfg := &RPCConfig{
+		ListenAddr: "localhost",
+		ListenPort: 0,
+	}
+	rollupCfg := &rollup.Config{
+		// ignore other rollup config info in this test
+	}
+	server, err := newRPCServer(context.Background(), rpcCfg, rollupCfg, l2Client, drClient, safeDB, log, "0.0", metrics.NoopMetrics)
+	require.NoError(t, err)
+	require.NoError(t, server.Start())
+	defer func() {
+		require.NoError(t, server.Stop(context.Background()))
+	}()
+
+	client, err := rpcclient.NewRPC(context.Background(), log, "http://"+server.Addr().String(), rpcclient.WithDialBackoff(3))
+	require.NoError(t, err)
+
+	var out *eth.SafeHeadResponse
+	err = client.CallContext(context.Background(), &out, "optimism_safeHeadAtL1Block", hexutil.Uint64(25))
+	require.NoError(t, err)
+	require.Equal(t, &eth.SafeHeadResponse{L1Block: l1Block, SafeHead: safeHead}, out)
+	safeDB.Mock.AssertExpectations(t)
+
+	// Disabled when the safe head database is not configured
+	disabledServer, err := newRPCServer(context.Background(), rpcCfg, rollupCfg, l2Client, drClient, safedb.Disabled, log, "0.0", metrics.NoopMetrics)
+	require.NoError(t, err)
+	require.NoError(t, disabledServer.Start())
+	defer func() {
+		require.NoError(t, disabledServer.Stop(context.Background()))
+	}()
+	disabledClient, err := rpcclient.NewRPC(context.Background(), log, "http://"+disabledServer.Addr().String(), rpcclient.WithDialBackoff(3))
+	require.NoError(t, err)
+	err = disabledClient.CallContext(context.Background(), &out, "optimism_safeHeadAtL1Block", hexutil.Uint64(25))
+	require.ErrorContains(t, err, safedb.ErrNotEnabled.Error())
+}
+
+type mockSafeDBReader struct {
+	mock.Mock
+}
+
+func (m *mockSafeDBReader) ExpectSafeHeadAtL1(l1BlockNum uint64, l1 eth.BlockID, safeHead eth.BlockID, err error) {
+	m.Mock.On("SafeHeadAtL1", l1BlockNum).Return(l1, safeHead, &err)
+}
+
+func (m *mockSafeDBReader) SafeHeadAtL1(ctx context.Context, l1BlockNum uint64) (eth.BlockID, eth.BlockID, error) {
+	r := m.Mock.MethodCalled("SafeHeadAtL1", l1BlockNum)
+	return r[0].(eth.BlockID), r[1].(eth.BlockID), *r[2].(*error)
+}
+
 type mockDriverClient struct {
 	mock.Mock
+	syncStatusFeed event.Feed
 }
 
 func (c *mockDriverClient) ExpectBlockRefWithStatus(num uint64, ref eth.L2BlockRef, status *eth.SyncStatus, err error) {
@@ -229,3 +361,7 @@ func (c *mockDriverClient) StopSequencer(ctx context.Context) (common.Hash, erro
 func (c *mockDriverClient) SequencerActive(ctx context.Context) (bool, error) {
 	return c.Mock.MethodCalled("SequencerActive").Get(0).(bool), nil
 }
+
+func (c *mockDriverClient) SubscribeSyncStatus(ch chan<- *eth.SyncStatus) ethereum.Subscription {
+	return c.syncStatusFeed.Subscribe(ch)
+}
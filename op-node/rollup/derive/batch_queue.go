@@ -40,26 +40,86 @@ type BatchQueue struct {
 	prev   NextBatchProvider
 	origin eth.L1BlockRef
 
+	// l1Blocks contains consecutive eth.L1BlockRef sorted by time.
+	// Every L1 origin of unsafe L2 blocks must be eventually included in l1Blocks.
+	// Batch queue's job is to ensure below two rules:
+	//  If every L2 block corresponding to single L1 block becomes safe, it will be popped from l1Blocks.
+	//  If new L2 block's L1 origin is not included in l1Blocks, fetch and push to l1Blocks.
+	// length of l1Blocks never exceeds SequencerWindowSize
 	l1Blocks []eth.L1BlockRef
 
-	// batches in order of when we've first seen them, grouped by L2 timestamp
-	batches map[uint64][]*BatchWithL1InclusionBlock
+	// batches in order of when we've first seen them
+	batches []*BatchWithL1InclusionBlock
+
+	// nextSpan is cached SingularBatches derived from SpanBatch
+	nextSpan []*SingularBatch
+
+	l2 SafeBlockFetcher
+
+	// optional observer of all batch validity checks
+	onCheck BatchCheckFn
 }
 
+// BatchCheckFn is called with the result of every batch validity check of the
+// batch queue, including the rule that decided the validity of the batch.
+type BatchCheckFn func(batch *BatchWithL1InclusionBlock, validity BatchValidity, rule BatchRule)
+
 // NewBatchQueue creates a BatchQueue, which should be Reset(origin) before use.
-func NewBatchQueue(log log.Logger, cfg *rollup.Config, prev NextBatchProvider) *BatchQueue {
+func NewBatchQueue(log log.Logger, cfg *rollup.Config, prev NextBatchProvider, l2 SafeBlockFetcher) *BatchQueue {
 	return &BatchQueue{
 		log:    log,
 		config: cfg,
 		prev:   prev,
+		l2:     l2,
 	}
 }
 
+// SetCheckObserver sets a function that observes all batch validity checks.
+// It is used by tooling to inspect derivation, and is not set by the node.
+func (bq *BatchQueue) SetCheckObserver(fn BatchCheckFn) {
+	bq.onCheck = fn
+}
+
 func (bq *BatchQueue) Origin() eth.L1BlockRef {
 	return bq.prev.Origin()
 }
 
+func (bq *BatchQueue) popNextBatch(safeL2Head eth.L2BlockRef) *BatchData {
+	nextBatch := bq.nextSpan[0]
+	bq.nextSpan = bq.nextSpan[1:]
+	// Must set ParentHash before return. we can use safeL2Head because the parentCheck is verified in CheckSpanBatchRule().
+	nextBatch.ParentHash = safeL2Head.Hash
+	return NewSingularBatchData(*nextBatch)
+}
+
 func (bq *BatchQueue) NextBatch(ctx context.Context, safeL2Head eth.L2BlockRef) (*BatchData, error) {
+	if len(bq.nextSpan) > 0 {
+		// If there are cached singular batches, pop first one and return.
+		if bq.nextSpan[0].Timestamp == safeL2Head.Time+bq.config.BlockTime {
+			return bq.popNextBatch(safeL2Head), nil
+		}
+		// The safe head does not match the next cached batch, so a previously returned batch was invalid.
+		// Drop the cached batches and find another batch.
+		bq.log.Warn("safe head does not match the next cached span batch block, dropping cached batches",
+			"safe_head", safeL2Head.ID(), "next_batch_timestamp", bq.nextSpan[0].Timestamp)
+		bq.nextSpan = bq.nextSpan[:0]
+	}
+
+	// If the epoch is advanced, update bq.l1Blocks.
+	// Advancing the epoch must be done after the pipeline applied the entire span batch to the chain,
+	// because a span batch can be reverted while it is processed, and then we must preserve the existing l1Blocks
+	// to verify the epochs of the next candidate batch.
+	if len(bq.l1Blocks) > 0 && safeL2Head.L1Origin.Number > bq.l1Blocks[0].Number {
+		for i, l1Block := range bq.l1Blocks {
+			if safeL2Head.L1Origin.Number == l1Block.Number {
+				bq.l1Blocks = bq.l1Blocks[i:]
+				bq.log.Debug("Advancing internal L1 blocks", "next_epoch", bq.l1Blocks[0].ID(), "next_epoch_time", bq.l1Blocks[0].Time)
+				break
+			}
+		}
+		// If we can't find the origin of the safe head, the origin of this stage is behind and advances below.
+	}
+
 	// Note: We use the origin that we will have to determine if it's behind. This is important
 	// because it's the future origin that gets saved into the l1Blocks array.
 	// We always update the origin of this stage if it is not the same so after the update code
@@ -89,7 +149,7 @@ func (bq *BatchQueue) NextBatch(ctx context.Context, safeL2Head eth.L2BlockRef)
 	} else if err != nil {
 		return nil, err
 	} else if !originBehind {
-		bq.AddBatch(batch, safeL2Head)
+		bq.AddBatch(ctx, batch, safeL2Head)
 	}
 
 	// Skip adding data unless we are up to date with the origin, but do fully
@@ -111,14 +171,24 @@ func (bq *BatchQueue) NextBatch(ctx context.Context, safeL2Head eth.L2BlockRef)
 	} else if err != nil {
 		return nil, err
 	}
-	return batch, nil
+	if batch.SpanBatch == nil {
+		return batch.Batch, nil
+	}
+	singularBatches, err := batch.SpanBatch.GetSingularBatches(bq.l1Blocks, safeL2Head)
+	if err != nil {
+		return nil, NewCriticalError(err)
+	}
+	bq.nextSpan = singularBatches
+	// span batches that are accepted have new blocks, so the below pop is safe.
+	return bq.popNextBatch(safeL2Head), nil
 }
 
 func (bq *BatchQueue) Reset(ctx context.Context, base eth.L1BlockRef, _ eth.SystemConfig) error {
 	// Copy over the Origin from the next stage
 	// It is set in the engine queue (two stages away) such that the L2 Safe Head origin is the progress
 	bq.origin = base
-	bq.batches = make(map[uint64][]*BatchWithL1InclusionBlock)
+	bq.batches = []*BatchWithL1InclusionBlock{}
+	bq.nextSpan = bq.nextSpan[:0]
 	// Include the new origin as an origin to build on
 	// Note: This is only for the initialization case. During normal resets we will later
 	// throw out this block.
@@ -127,7 +197,7 @@ func (bq *BatchQueue) Reset(ctx context.Context, base eth.L1BlockRef, _ eth.Syst
 	return io.EOF
 }
 
-func (bq *BatchQueue) AddBatch(batch *BatchData, l2SafeHead eth.L2BlockRef) {
+func (bq *BatchQueue) AddBatch(ctx context.Context, batch *BatchData, l2SafeHead eth.L2BlockRef) {
 	if len(bq.l1Blocks) == 0 {
 		panic(fmt.Errorf("cannot add batch with timestamp %d, no origin was prepared", batch.Timestamp))
 	}
@@ -135,19 +205,53 @@ func (bq *BatchQueue) AddBatch(batch *BatchData, l2SafeHead eth.L2BlockRef) {
 		L1InclusionBlock: bq.origin,
 		Batch:            batch,
 	}
-	validity := CheckBatch(bq.config, bq.log, bq.l1Blocks, l2SafeHead, &data)
+	if batch.BatchType == SpanBatchType {
+		spanBatch, err := batch.RawSpanBatch.Derive(bq.config.BlockTime, bq.config.Genesis.L2Time, bq.config.L2ChainID)
+		if err != nil {
+			bq.log.Warn("dropping invalid span batch", "err", err)
+			bq.observe(&data, BatchDrop, BatchRuleInvalidSpanBatch)
+			return
+		}
+		if spanBatch.GetBlockCount() == 0 {
+			bq.log.Warn("dropping empty span batch")
+			bq.observe(&data, BatchDrop, BatchRuleInvalidSpanBatch)
+			return
+		}
+		data.SpanBatch = spanBatch
+	}
+	validity := bq.checkBatch(ctx, bq.log, l2SafeHead, &data)
 	if validity == BatchDrop {
 		return // if we do drop the batch, CheckBatch will log the drop reason with WARN level.
 	}
-	bq.log.Debug("Adding batch", "batch_timestamp", batch.Timestamp, "parent_hash", batch.ParentHash, "batch_epoch", batch.Epoch(), "txs", len(batch.Transactions))
-	bq.batches[batch.Timestamp] = append(bq.batches[batch.Timestamp], &data)
+	data.logContext(bq.log).Debug("Adding batch")
+	bq.batches = append(bq.batches, &data)
+}
+
+// checkBatch checks the validity of the batch against the current L1 blocks and
+// notifies the check observer, if set.
+func (bq *BatchQueue) checkBatch(ctx context.Context, log log.Logger, l2SafeHead eth.L2BlockRef, batch *BatchWithL1InclusionBlock) BatchValidity {
+	var validity BatchValidity
+	var rule BatchRule
+	if batch.SpanBatch != nil {
+		validity, rule = CheckSpanBatchRule(ctx, bq.config, log, bq.l1Blocks, l2SafeHead, batch.SpanBatch, batch.L1InclusionBlock, bq.l2)
+	} else {
+		validity, rule = CheckBatchRule(bq.config, log, bq.l1Blocks, l2SafeHead, batch)
+	}
+	bq.observe(batch, validity, rule)
+	return validity
+}
+
+func (bq *BatchQueue) observe(batch *BatchWithL1InclusionBlock, validity BatchValidity, rule BatchRule) {
+	if bq.onCheck != nil {
+		bq.onCheck(batch, validity, rule)
+	}
 }
 
 // deriveNextBatch derives the next batch to apply on top of the current L2 safe head,
 // following the validity rules imposed on consecutive batches,
 // based on currently available buffered batch and L1 origin information.
 // If no batch can be derived yet, then (nil, io.EOF) is returned.
-func (bq *BatchQueue) deriveNextBatch(ctx context.Context, outOfData bool, l2SafeHead eth.L2BlockRef) (*BatchData, error) {
+func (bq *BatchQueue) deriveNextBatch(ctx context.Context, outOfData bool, l2SafeHead eth.L2BlockRef) (*BatchWithL1InclusionBlock, error) {
 	if len(bq.l1Blocks) == 0 {
 		return nil, NewCriticalError(errors.New("cannot derive next batch, no origin was prepared"))
 	}
@@ -170,19 +274,15 @@ func (bq *BatchQueue) deriveNextBatch(ctx context.Context, outOfData bool, l2Saf
 	// Go over all batches, in order of inclusion, and find the first batch we can accept.
 	// We filter in-place by only remembering the batches that may be processed in the future, or those we are undecided on.
 	var remaining []*BatchWithL1InclusionBlock
-	candidates := bq.batches[nextTimestamp]
 batchLoop:
-	for i, batch := range candidates {
-		validity := CheckBatch(bq.config, bq.log.New("batch_index", i), bq.l1Blocks, l2SafeHead, batch)
+	for i, batch := range bq.batches {
+		validity := bq.checkBatch(ctx, bq.log.New("batch_index", i), l2SafeHead, batch)
 		switch validity {
 		case BatchFuture:
-			return nil, NewCriticalError(fmt.Errorf("found batch with timestamp %d marked as future batch, but expected timestamp %d", batch.Batch.Timestamp, nextTimestamp))
+			remaining = append(remaining, batch)
+			continue
 		case BatchDrop:
-			bq.log.Warn("dropping batch",
-				"batch_timestamp", batch.Batch.Timestamp,
-				"parent_hash", batch.Batch.ParentHash,
-				"batch_epoch", batch.Batch.Epoch(),
-				"txs", len(batch.Batch.Transactions),
+			batch.logContext(bq.log).Warn("dropping batch",
 				"l2_safe_head", l2SafeHead.ID(),
 				"l2_safe_head_time", l2SafeHead.Time,
 			)
@@ -191,30 +291,21 @@ batchLoop:
 			nextBatch = batch
 			// don't keep the current batch in the remaining items since we are processing it now,
 			// but retain every batch we didn't get to yet.
-			remaining = append(remaining, candidates[i+1:]...)
+			remaining = append(remaining, bq.batches[i+1:]...)
 			break batchLoop
 		case BatchUndecided:
-			remaining = append(remaining, batch)
-			bq.batches[nextTimestamp] = remaining
+			remaining = append(remaining, bq.batches[i:]...)
+			bq.batches = remaining
 			return nil, io.EOF
 		default:
 			return nil, NewCriticalError(fmt.Errorf("unknown batch validity type: %d", validity))
 		}
 	}
-	// clean up if we remove the final batch for this timestamp
-	if len(remaining) == 0 {
-		delete(bq.batches, nextTimestamp)
-	} else {
-		bq.batches[nextTimestamp] = remaining
-	}
+	bq.batches = remaining
 
 	if nextBatch != nil {
-		// advance epoch if necessary
-		if nextBatch.Batch.EpochNum == rollup.Epoch(epoch.Number)+1 {
-			bq.l1Blocks = bq.l1Blocks[1:]
-		}
-		bq.log.Info("Found next batch", "epoch", epoch, "batch_epoch", nextBatch.Batch.EpochNum, "batch_timestamp", nextBatch.Batch.Timestamp)
-		return nextBatch.Batch, nil
+		nextBatch.logContext(bq.log).Info("Found next batch", "epoch", epoch)
+		return nextBatch, nil
 	}
 
 	// If the current epoch is too old compared to the L1 block we are at,
@@ -243,15 +334,17 @@ batchLoop:
 	// batch to ensure that we at least have one batch per epoch.
 	if nextTimestamp < nextEpoch.Time || firstOfEpoch {
 		bq.log.Info("Generating next batch", "epoch", epoch, "timestamp", nextTimestamp)
-		return NewSingularBatchData(
-			SingularBatch{
-				ParentHash:   l2SafeHead.Hash,
-				EpochNum:     rollup.Epoch(epoch.Number),
-				EpochHash:    epoch.Hash,
-				Timestamp:    nextTimestamp,
-				Transactions: nil,
-			},
-		), nil
+		return &BatchWithL1InclusionBlock{
+			Batch: NewSingularBatchData(
+				SingularBatch{
+					ParentHash:   l2SafeHead.Hash,
+					EpochNum:     rollup.Epoch(epoch.Number),
+					EpochHash:    epoch.Hash,
+					Timestamp:    nextTimestamp,
+					Transactions: nil,
+				},
+			),
+		}, nil
 	}
 
 	// At this point we have auto generated every batch for the current epoch
@@ -4,9 +4,11 @@ import (
 	"context"
 	"encoding/binary"
 	"io"
+	"math/big"
 	"math/rand"
 	"testing"
 
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/log"
@@ -102,7 +104,7 @@ func TestBatchQueueNewOrigin(t *testing.T) {
 		origin:  l1[0],
 	}
 
-	bq := NewBatchQueue(log, cfg, input)
+	bq := NewBatchQueue(log, cfg, input, nil)
 	_ = bq.Reset(context.Background(), l1[0], eth.SystemConfig{})
 	require.Equal(t, []eth.L1BlockRef{l1[0]}, bq.l1Blocks)
 
@@ -164,7 +166,7 @@ func TestBatchQueueEager(t *testing.T) {
 		origin:  l1[0],
 	}
 
-	bq := NewBatchQueue(log, cfg, input)
+	bq := NewBatchQueue(log, cfg, input, nil)
 	_ = bq.Reset(context.Background(), l1[0], eth.SystemConfig{})
 	// Advance the origin
 	input.origin = l1[1]
@@ -214,7 +216,7 @@ func TestBatchQueueInvalidInternalAdvance(t *testing.T) {
 		origin:  l1[0],
 	}
 
-	bq := NewBatchQueue(log, cfg, input)
+	bq := NewBatchQueue(log, cfg, input, nil)
 	_ = bq.Reset(context.Background(), l1[0], eth.SystemConfig{})
 
 	// Load continuous batches for epoch 0
@@ -308,7 +310,7 @@ func TestBatchQueueMissing(t *testing.T) {
 		origin:  l1[0],
 	}
 
-	bq := NewBatchQueue(log, cfg, input)
+	bq := NewBatchQueue(log, cfg, input, nil)
 	_ = bq.Reset(context.Background(), l1[0], eth.SystemConfig{})
 
 	for i := 0; i < len(batches); i++ {
@@ -370,3 +372,218 @@ func TestBatchQueueMissing(t *testing.T) {
 	require.Empty(t, b.SingularBatch.Transactions)
 	require.Equal(t, rollup.Epoch(1), b.EpochNum)
 }
+
+type fakeSafeBlockFetcher map[uint64]*eth.ExecutionPayload
+
+func (f fakeSafeBlockFetcher) PayloadByNumber(_ context.Context, n uint64) (*eth.ExecutionPayload, error) {
+	payload, ok := f[n]
+	if !ok {
+		return nil, ethereum.NotFound
+	}
+	return payload, nil
+}
+
+func spanBatchData(t *testing.T, cfg *rollup.Config, batches []*SingularBatch) *BatchData {
+	raw, err := NewSpanBatch(batches).ToRawSpanBatch(0, cfg.Genesis.L2Time, cfg.L2ChainID)
+	require.NoError(t, err)
+	return NewSpanBatchData(*raw)
+}
+
+// consecutiveSingularBatches creates count batches with transactions that build on top of the parent block and stick to its L1 origin.
+func consecutiveSingularBatches(rng *rand.Rand, cfg *rollup.Config, parent eth.L2BlockRef, origin eth.L1BlockRef, count int) []*SingularBatch {
+	var batches []*SingularBatch
+	for i := 0; i < count; i++ {
+		batch := RandomSingularBatch(rng, 1+rng.Intn(3), cfg.L2ChainID)
+		batch.ParentHash = parent.Hash
+		batch.EpochNum = rollup.Epoch(origin.Number)
+		batch.EpochHash = origin.Hash
+		batch.Timestamp = parent.Time + cfg.BlockTime
+		batches = append(batches, batch)
+		parent = eth.L2BlockRef{Hash: mockHash(batch.Timestamp, 2), Number: parent.Number + 1, ParentHash: parent.Hash, Time: batch.Timestamp, L1Origin: origin.ID()}
+	}
+	return batches
+}
+
+// safeBlockPayload creates the payload of a safe block derived from the given batch.
+func safeBlockPayload(t *testing.T, number uint64, batch *SingularBatch, origin eth.L1BlockRef) *eth.ExecutionPayload {
+	l1Info := &testutils.MockBlockInfo{InfoHash: origin.Hash, InfoNum: origin.Number, InfoTime: origin.Time, InfoBaseFee: big.NewInt(7)}
+	deposit, err := L1InfoDepositBytes(number, l1Info, eth.SystemConfig{}, true)
+	require.NoError(t, err)
+	txs := []eth.Data{deposit}
+	for _, tx := range batch.Transactions {
+		txs = append(txs, eth.Data(tx))
+	}
+	return &eth.ExecutionPayload{
+		ParentHash:   batch.ParentHash,
+		BlockNumber:  eth.Uint64Quantity(number),
+		BlockHash:    mockHash(batch.Timestamp, 2),
+		Timestamp:    eth.Uint64Quantity(batch.Timestamp),
+		Transactions: txs,
+	}
+}
+
+// TestBatchQueueSpanBatch asserts that the blocks of a span batch are returned one by one as singular batches.
+func TestBatchQueueSpanBatch(t *testing.T) {
+	log := testlog.Logger(t, log.LvlCrit)
+	rng := rand.New(rand.NewSource(1234))
+	l1 := L1Chain([]uint64{10, 20, 30})
+	safeHead := eth.L2BlockRef{
+		Hash:           mockHash(10, 2),
+		Number:         0,
+		ParentHash:     common.Hash{},
+		Time:           10,
+		L1Origin:       l1[0].ID(),
+		SequenceNumber: 0,
+	}
+	cfg := &rollup.Config{
+		Genesis: rollup.Genesis{
+			L2Time: 10,
+		},
+		BlockTime:         2,
+		MaxSequencerDrift: 600,
+		SeqWindowSize:     30,
+		L2ChainID:         big.NewInt(901),
+	}
+	batches := consecutiveSingularBatches(rng, cfg, safeHead, l1[0], 4)
+
+	var checks []BatchRule
+	input := &fakeBatchQueueInput{
+		batches: []*BatchData{spanBatchData(t, cfg, batches)},
+		errors:  []error{nil},
+		origin:  l1[0],
+	}
+	bq := NewBatchQueue(log, cfg, input, nil)
+	bq.SetCheckObserver(func(_ *BatchWithL1InclusionBlock, _ BatchValidity, rule BatchRule) {
+		checks = append(checks, rule)
+	})
+	_ = bq.Reset(context.Background(), l1[0], eth.SystemConfig{})
+	input.origin = l1[1]
+
+	for _, expected := range batches {
+		b, err := bq.NextBatch(context.Background(), safeHead)
+		require.NoError(t, err)
+		require.Equal(t, NewSingularBatchData(*expected), b)
+		safeHead = eth.L2BlockRef{Hash: mockHash(b.Timestamp, 2), Number: safeHead.Number + 1, ParentHash: safeHead.Hash, Time: b.Timestamp, L1Origin: b.Epoch()}
+	}
+	require.Equal(t, []BatchRule{BatchRuleValid, BatchRuleValid}, checks)
+
+	_, err := bq.NextBatch(context.Background(), safeHead)
+	require.ErrorIs(t, err, io.EOF)
+}
+
+// TestBatchQueueSpanBatchOverlap asserts that a span batch that overlaps the safe chain is accepted if the overlap
+// matches the safe blocks, and that only the blocks after the safe head are returned.
+func TestBatchQueueSpanBatchOverlap(t *testing.T) {
+	log := testlog.Logger(t, log.LvlCrit)
+	l1 := L1Chain([]uint64{10, 20, 30})
+	genesis := eth.L2BlockRef{
+		Hash:     mockHash(10, 2),
+		Number:   0,
+		Time:     10,
+		L1Origin: l1[0].ID(),
+	}
+	cfg := &rollup.Config{
+		Genesis: rollup.Genesis{
+			L1:     l1[0].ID(),
+			L2:     genesis.ID(),
+			L2Time: 10,
+		},
+		BlockTime:         2,
+		MaxSequencerDrift: 600,
+		SeqWindowSize:     30,
+		L2ChainID:         big.NewInt(901),
+	}
+
+	for _, tc := range []struct {
+		name     string
+		mismatch bool
+	}{
+		{name: "matching overlap"},
+		{name: "mismatching overlap", mismatch: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			rng := rand.New(rand.NewSource(1234))
+			batches := consecutiveSingularBatches(rng, cfg, genesis, l1[0], 4)
+			fetcher := fakeSafeBlockFetcher{
+				0: {BlockHash: genesis.Hash, BlockNumber: 0, Timestamp: eth.Uint64Quantity(genesis.Time)},
+				1: safeBlockPayload(t, 1, batches[0], l1[0]),
+				2: safeBlockPayload(t, 2, batches[1], l1[0]),
+			}
+			if tc.mismatch {
+				fetcher[2].Transactions[1] = fetcher[1].Transactions[1]
+			}
+			safeHead := eth.L2BlockRef{Hash: mockHash(batches[1].Timestamp, 2), Number: 2, ParentHash: batches[1].ParentHash, Time: batches[1].Timestamp, L1Origin: l1[0].ID(), SequenceNumber: 2}
+
+			var checks []BatchRule
+			input := &fakeBatchQueueInput{
+				batches: []*BatchData{spanBatchData(t, cfg, batches)},
+				errors:  []error{nil},
+				origin:  l1[0],
+			}
+			bq := NewBatchQueue(log, cfg, input, fetcher)
+			bq.SetCheckObserver(func(_ *BatchWithL1InclusionBlock, _ BatchValidity, rule BatchRule) {
+				checks = append(checks, rule)
+			})
+			_ = bq.Reset(context.Background(), l1[0], eth.SystemConfig{})
+			input.origin = l1[1]
+
+			if tc.mismatch {
+				_, err := bq.NextBatch(context.Background(), safeHead)
+				require.ErrorIs(t, err, NotEnoughData)
+				require.Equal(t, []BatchRule{BatchRuleOverlapMismatch}, checks)
+				return
+			}
+			for _, expected := range batches[2:] {
+				b, err := bq.NextBatch(context.Background(), safeHead)
+				require.NoError(t, err)
+				expected.ParentHash = safeHead.Hash
+				require.Equal(t, NewSingularBatchData(*expected), b)
+				safeHead = eth.L2BlockRef{Hash: mockHash(b.Timestamp, 2), Number: safeHead.Number + 1, ParentHash: safeHead.Hash, Time: b.Timestamp, L1Origin: b.Epoch()}
+			}
+			require.Equal(t, []BatchRule{BatchRuleValid, BatchRuleValid}, checks)
+		})
+	}
+}
+
+// TestBatchQueueSpanBatchReverted asserts that the remaining blocks of a span batch are dropped
+// if the safe head does not build on the previously returned block of the span batch.
+func TestBatchQueueSpanBatchReverted(t *testing.T) {
+	log := testlog.Logger(t, log.LvlCrit)
+	rng := rand.New(rand.NewSource(1234))
+	l1 := L1Chain([]uint64{10, 20, 30})
+	safeHead := eth.L2BlockRef{
+		Hash:     mockHash(10, 2),
+		Number:   0,
+		Time:     10,
+		L1Origin: l1[0].ID(),
+	}
+	cfg := &rollup.Config{
+		Genesis: rollup.Genesis{
+			L2Time: 10,
+		},
+		BlockTime:         2,
+		MaxSequencerDrift: 600,
+		SeqWindowSize:     30,
+		L2ChainID:         big.NewInt(901),
+	}
+	batches := consecutiveSingularBatches(rng, cfg, safeHead, l1[0], 4)
+
+	input := &fakeBatchQueueInput{
+		batches: []*BatchData{spanBatchData(t, cfg, batches)},
+		errors:  []error{nil},
+		origin:  l1[0],
+	}
+	bq := NewBatchQueue(log, cfg, input, nil)
+	_ = bq.Reset(context.Background(), l1[0], eth.SystemConfig{})
+	input.origin = l1[1]
+
+	b, err := bq.NextBatch(context.Background(), safeHead)
+	require.NoError(t, err)
+	require.Equal(t, NewSingularBatchData(*batches[0]), b)
+	require.Len(t, bq.nextSpan, 3)
+
+	// the first block of the span batch was not applied, so the cached blocks do not build on the safe head anymore
+	_, err = bq.NextBatch(context.Background(), safeHead)
+	require.ErrorIs(t, err, io.EOF)
+	require.Empty(t, bq.nextSpan)
+}
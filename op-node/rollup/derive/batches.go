@@ -1,8 +1,13 @@
 package derive
 
 import (
+	"bytes"
+	"context"
+	"fmt"
+
 	"github.com/ethereum-optimism/optimism/op-node/rollup"
 	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/log"
 )
@@ -10,6 +15,20 @@ import (
 type BatchWithL1InclusionBlock struct {
 	L1InclusionBlock eth.L1BlockRef
 	Batch            *BatchData
+	// SpanBatch is the derived form of Batch, if Batch is a span batch.
+	SpanBatch *SpanBatch
+}
+
+func (b *BatchWithL1InclusionBlock) logContext(log log.Logger) log.Logger {
+	if b.SpanBatch != nil {
+		return b.SpanBatch.LogContext(log)
+	}
+	return b.Batch.SingularBatch.LogContext(log)
+}
+
+// SafeBlockFetcher fetches the safe L2 blocks that span batches may overlap with.
+type SafeBlockFetcher interface {
+	PayloadByNumber(context.Context, uint64) (*eth.ExecutionPayload, error)
 }
 
 type BatchValidity uint8
@@ -25,10 +44,60 @@ const (
 	BatchFuture
 )
 
+func (v BatchValidity) String() string {
+	switch v {
+	case BatchDrop:
+		return "drop"
+	case BatchAccept:
+		return "accept"
+	case BatchUndecided:
+		return "undecided"
+	case BatchFuture:
+		return "future"
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(v))
+	}
+}
+
+// BatchRule identifies the batch validity rule that decided the validity of a batch.
+type BatchRule string
+
+const (
+	BatchRuleMissingL1Blocks       BatchRule = "missing_l1_blocks"
+	BatchRuleFutureTimestamp       BatchRule = "future_timestamp"
+	BatchRuleOldTimestamp          BatchRule = "old_timestamp"
+	BatchRuleParentHash            BatchRule = "parent_hash_mismatch"
+	BatchRuleSeqWindowExpired      BatchRule = "sequence_window_expired"
+	BatchRuleEpochTooOld           BatchRule = "epoch_too_old"
+	BatchRuleNextEpochUnknown      BatchRule = "next_epoch_unknown"
+	BatchRuleEpochTooNew           BatchRule = "epoch_too_new"
+	BatchRuleEpochHash             BatchRule = "epoch_hash_mismatch"
+	BatchRuleTimestampBeforeOrigin BatchRule = "timestamp_before_l1_origin"
+	BatchRuleTimeDriftNextOrigin   BatchRule = "time_drift_next_origin_unknown"
+	BatchRuleTimeDriftAdoptOrigin  BatchRule = "time_drift_next_origin_adoptable"
+	BatchRuleTimeDriftExceeded     BatchRule = "time_drift_exceeded"
+	BatchRuleEmptyTransaction      BatchRule = "empty_transaction"
+	BatchRuleDepositTransaction    BatchRule = "deposit_transaction"
+	BatchRuleInvalidSpanBatch      BatchRule = "invalid_span_batch"
+	BatchRuleNoNewBlocks           BatchRule = "no_new_blocks"
+	BatchRuleMisalignedTimestamp   BatchRule = "misaligned_timestamp"
+	BatchRuleL2Unavailable         BatchRule = "l2_block_unavailable"
+	BatchRuleOriginsUnknown        BatchRule = "l1_origins_unknown"
+	BatchRuleOverlapMismatch       BatchRule = "overlap_mismatch"
+	BatchRuleValid                 BatchRule = "valid"
+)
+
 // CheckBatch checks if the given batch can be applied on top of the given l2SafeHead, given the contextual L1 blocks the batch was included in.
 // The first entry of the l1Blocks should match the origin of the l2SafeHead. One or more consecutive l1Blocks should be provided.
 // In case of only a single L1 block, the decision whether a batch is valid may have to stay undecided.
 func CheckBatch(cfg *rollup.Config, log log.Logger, l1Blocks []eth.L1BlockRef, l2SafeHead eth.L2BlockRef, batch *BatchWithL1InclusionBlock) BatchValidity {
+	validity, _ := CheckBatchRule(cfg, log, l1Blocks, l2SafeHead, batch)
+	return validity
+}
+
+// CheckBatchRule is like CheckBatch, but additionally returns the rule that decided the validity of the batch.
+// Only singular batches are checked, span batches are checked with CheckSpanBatchRule.
+func CheckBatchRule(cfg *rollup.Config, log log.Logger, l1Blocks []eth.L1BlockRef, l2SafeHead eth.L2BlockRef, batch *BatchWithL1InclusionBlock) (BatchValidity, BatchRule) {
 	// add details to the log
 	log = log.New(
 		"batch_timestamp", batch.Batch.Timestamp,
@@ -40,30 +109,30 @@ func CheckBatch(cfg *rollup.Config, log log.Logger, l1Blocks []eth.L1BlockRef, l
 	// sanity check we have consistent inputs
 	if len(l1Blocks) == 0 {
 		log.Warn("missing L1 block input, cannot proceed with batch checking")
-		return BatchUndecided
+		return BatchUndecided, BatchRuleMissingL1Blocks
 	}
 	epoch := l1Blocks[0]
 
 	nextTimestamp := l2SafeHead.Time + cfg.BlockTime
 	if batch.Batch.Timestamp > nextTimestamp {
 		log.Trace("received out-of-order batch for future processing after next batch", "next_timestamp", nextTimestamp)
-		return BatchFuture
+		return BatchFuture, BatchRuleFutureTimestamp
 	}
 	if batch.Batch.Timestamp < nextTimestamp {
 		log.Warn("dropping batch with old timestamp", "min_timestamp", nextTimestamp)
-		return BatchDrop
+		return BatchDrop, BatchRuleOldTimestamp
 	}
 
 	// dependent on above timestamp check. If the timestamp is correct, then it must build on top of the safe head.
 	if batch.Batch.ParentHash != l2SafeHead.Hash {
 		log.Warn("ignoring batch with mismatching parent hash", "current_safe_head", l2SafeHead.Hash)
-		return BatchDrop
+		return BatchDrop, BatchRuleParentHash
 	}
 
 	// Filter out batches that were included too late.
 	if uint64(batch.Batch.EpochNum)+cfg.SeqWindowSize < batch.L1InclusionBlock.Number {
 		log.Warn("batch was included too late, sequence window expired")
-		return BatchDrop
+		return BatchDrop, BatchRuleSeqWindowExpired
 	}
 
 	// Check the L1 origin of the batch
@@ -71,7 +140,7 @@ func CheckBatch(cfg *rollup.Config, log log.Logger, l1Blocks []eth.L1BlockRef, l
 	if uint64(batch.Batch.EpochNum) < epoch.Number {
 		log.Warn("dropped batch, epoch is too old", "minimum", epoch.ID())
 		// batch epoch too old
-		return BatchDrop
+		return BatchDrop, BatchRuleEpochTooOld
 	} else if uint64(batch.Batch.EpochNum) == epoch.Number {
 		// Batch is sticking to the current epoch, continue.
 	} else if uint64(batch.Batch.EpochNum) == epoch.Number+1 {
@@ -82,22 +151,22 @@ func CheckBatch(cfg *rollup.Config, log log.Logger, l1Blocks []eth.L1BlockRef, l
 		// algorithm.
 		if len(l1Blocks) < 2 {
 			log.Info("eager batch wants to advance epoch, but could not without more L1 blocks", "current_epoch", epoch.ID())
-			return BatchUndecided
+			return BatchUndecided, BatchRuleNextEpochUnknown
 		}
 		batchOrigin = l1Blocks[1]
 	} else {
 		log.Warn("batch is for future epoch too far ahead, while it has the next timestamp, so it must be invalid", "current_epoch", epoch.ID())
-		return BatchDrop
+		return BatchDrop, BatchRuleEpochTooNew
 	}
 
 	if batch.Batch.EpochHash != batchOrigin.Hash {
 		log.Warn("batch is for different L1 chain, epoch hash does not match", "expected", batchOrigin.ID())
-		return BatchDrop
+		return BatchDrop, BatchRuleEpochHash
 	}
 
 	if batch.Batch.Timestamp < batchOrigin.Time {
 		log.Warn("batch timestamp is less than L1 origin timestamp", "l2_timestamp", batch.Batch.Timestamp, "l1_timestamp", batchOrigin.Time, "origin", batchOrigin.ID())
-		return BatchDrop
+		return BatchDrop, BatchRuleTimestampBeforeOrigin
 	}
 
 	// Check if we ran out of sequencer time drift
@@ -109,12 +178,12 @@ func CheckBatch(cfg *rollup.Config, log log.Logger, l1Blocks []eth.L1BlockRef, l
 			if epoch.Number == batchOrigin.Number {
 				if len(l1Blocks) < 2 {
 					log.Info("without the next L1 origin we cannot determine yet if this empty batch that exceeds the time drift is still valid")
-					return BatchUndecided
+					return BatchUndecided, BatchRuleTimeDriftNextOrigin
 				}
 				nextOrigin := l1Blocks[1]
 				if batch.Batch.Timestamp >= nextOrigin.Time { // check if the next L1 origin could have been adopted
 					log.Info("batch exceeded sequencer time drift without adopting next origin, and next L1 origin would have been valid")
-					return BatchDrop
+					return BatchDrop, BatchRuleTimeDriftAdoptOrigin
 				} else {
 					log.Info("continuing with empty batch before late L1 block to preserve L2 time invariant")
 				}
@@ -123,7 +192,7 @@ func CheckBatch(cfg *rollup.Config, log log.Logger, l1Blocks []eth.L1BlockRef, l
 			// If the sequencer is ignoring the time drift rule, then drop the batch and force an empty batch instead,
 			// as the sequencer is not allowed to include anything past this point without moving to the next epoch.
 			log.Warn("batch exceeded sequencer time drift, sequencer must adopt new L1 origin to include transactions again", "max_time", max)
-			return BatchDrop
+			return BatchDrop, BatchRuleTimeDriftExceeded
 		}
 	}
 
@@ -131,13 +200,214 @@ func CheckBatch(cfg *rollup.Config, log log.Logger, l1Blocks []eth.L1BlockRef, l
 	for i, txBytes := range batch.Batch.Transactions {
 		if len(txBytes) == 0 {
 			log.Warn("transaction data must not be empty, but found empty tx", "tx_index", i)
-			return BatchDrop
+			return BatchDrop, BatchRuleEmptyTransaction
 		}
 		if txBytes[0] == types.DepositTxType {
 			log.Warn("sequencers may not embed any deposits into batch data, but found tx that has one", "tx_index", i)
-			return BatchDrop
+			return BatchDrop, BatchRuleDepositTransaction
+		}
+	}
+
+	return BatchAccept, BatchRuleValid
+}
+
+// CheckSpanBatchRule checks if the given span batch can be applied on top of the given l2SafeHead, like CheckBatchRule.
+// The span batch may overlap with the safe chain, in which case the overlapping blocks are fetched with l2Fetcher
+// and have to match the span batch. The blocks after the safe head are checked like singular batches.
+func CheckSpanBatchRule(ctx context.Context, cfg *rollup.Config, log log.Logger, l1Blocks []eth.L1BlockRef, l2SafeHead eth.L2BlockRef,
+	batch *SpanBatch, l1InclusionBlock eth.L1BlockRef, l2Fetcher SafeBlockFetcher) (BatchValidity, BatchRule) {
+	// add details to the log
+	log = batch.LogContext(log)
+
+	// sanity check we have consistent inputs
+	if len(l1Blocks) == 0 {
+		log.Warn("missing L1 block input, cannot proceed with batch checking")
+		return BatchUndecided, BatchRuleMissingL1Blocks
+	}
+	epoch := l1Blocks[0]
+
+	nextTimestamp := l2SafeHead.Time + cfg.BlockTime
+	if batch.GetTimestamp() > nextTimestamp {
+		log.Trace("received out-of-order batch for future processing after next batch", "next_timestamp", nextTimestamp)
+		return BatchFuture, BatchRuleFutureTimestamp
+	}
+	if batch.GetBlockTimestamp(batch.GetBlockCount()-1) < nextTimestamp {
+		log.Warn("span batch has no new blocks after safe head")
+		return BatchDrop, BatchRuleNoNewBlocks
+	}
+
+	// Find the parent block of the span batch.
+	// If the span batch does not overlap the safe chain, the parent is the safe head.
+	parentBlock := l2SafeHead
+	if batch.GetTimestamp() < nextTimestamp {
+		if batch.GetTimestamp() > l2SafeHead.Time {
+			log.Warn("batch has misaligned timestamp, block time is too short")
+			return BatchDrop, BatchRuleMisalignedTimestamp
+		}
+		if (l2SafeHead.Time-batch.GetTimestamp())%cfg.BlockTime != 0 {
+			log.Warn("batch has misaligned timestamp, not overlapped exactly")
+			return BatchDrop, BatchRuleMisalignedTimestamp
+		}
+		overlap := (l2SafeHead.Time-batch.GetTimestamp())/cfg.BlockTime + 1
+		if overlap > l2SafeHead.Number-cfg.Genesis.L2.Number {
+			log.Warn("batch starts before the L2 genesis block")
+			return BatchDrop, BatchRuleOldTimestamp
+		}
+		parentNum := l2SafeHead.Number - overlap
+		payload, err := l2Fetcher.PayloadByNumber(ctx, parentNum)
+		if err != nil {
+			log.Warn("failed to fetch L2 block", "number", parentNum, "err", err)
+			// unable to validate the batch for now, retry later.
+			return BatchUndecided, BatchRuleL2Unavailable
+		}
+		parentBlock, err = PayloadToBlockRef(payload, &cfg.Genesis)
+		if err != nil {
+			log.Warn("failed to read L2 block", "number", parentNum, "err", err)
+			return BatchUndecided, BatchRuleL2Unavailable
+		}
+	}
+	if !batch.CheckParentHash(parentBlock.Hash) {
+		log.Warn("ignoring batch with mismatching parent hash", "parent_block", parentBlock.Hash)
+		return BatchDrop, BatchRuleParentHash
+	}
+
+	// Filter out batches that were included too late.
+	startEpochNum := uint64(batch.GetStartEpochNum())
+	if startEpochNum+cfg.SeqWindowSize < l1InclusionBlock.Number {
+		log.Warn("batch was included too late, sequence window expired")
+		return BatchDrop, BatchRuleSeqWindowExpired
+	}
+
+	// Check the L1 origin of the batch
+	if startEpochNum > parentBlock.L1Origin.Number+1 {
+		log.Warn("batch is for future epoch too far ahead, while it has the next timestamp, so it must be invalid", "current_epoch", epoch.ID())
+		return BatchDrop, BatchRuleEpochTooNew
+	}
+
+	endEpochNum := batch.GetBlockEpochNum(batch.GetBlockCount() - 1)
+	originChecked := false
+	for _, l1Block := range l1Blocks {
+		if l1Block.Number == endEpochNum {
+			if !batch.CheckOriginHash(l1Block.Hash) {
+				log.Warn("batch is for different L1 chain, epoch hash does not match", "expected", l1Block.ID())
+				return BatchDrop, BatchRuleEpochHash
+			}
+			originChecked = true
+			break
+		}
+	}
+	if !originChecked {
+		log.Info("need more L1 blocks to check the L1 origins of the span batch")
+		return BatchUndecided, BatchRuleOriginsUnknown
+	}
+
+	if startEpochNum < parentBlock.L1Origin.Number {
+		log.Warn("dropped batch, epoch is too old", "minimum", parentBlock.ID())
+		return BatchDrop, BatchRuleEpochTooOld
+	}
+
+	originIdx := 0
+	originAdvanced := startEpochNum == parentBlock.L1Origin.Number+1
+	for i := 0; i < batch.GetBlockCount(); i++ {
+		if i > 0 {
+			originAdvanced = batch.GetBlockEpochNum(i) > batch.GetBlockEpochNum(i-1)
+		}
+		if batch.GetBlockTimestamp(i) <= l2SafeHead.Time {
+			continue
+		}
+		var l1Origin eth.L1BlockRef
+		for j := originIdx; j < len(l1Blocks); j++ {
+			if batch.GetBlockEpochNum(i) == l1Blocks[j].Number {
+				l1Origin = l1Blocks[j]
+				originIdx = j
+				break
+			}
+		}
+		if l1Origin == (eth.L1BlockRef{}) {
+			log.Warn("batch block has an L1 origin that is not in the L1 chain", "block_index", i, "epoch", batch.GetBlockEpochNum(i))
+			return BatchDrop, BatchRuleEpochTooOld
+		}
+		blockTimestamp := batch.GetBlockTimestamp(i)
+		if blockTimestamp < l1Origin.Time {
+			log.Warn("block timestamp is less than L1 origin timestamp", "l2_timestamp", blockTimestamp, "l1_timestamp", l1Origin.Time, "origin", l1Origin.ID())
+			return BatchDrop, BatchRuleTimestampBeforeOrigin
+		}
+
+		// Check if we ran out of sequencer time drift
+		if max := l1Origin.Time + cfg.MaxSequencerDrift; blockTimestamp > max {
+			if len(batch.GetBlockTransactions(i)) == 0 {
+				// If the sequencer is co-operating by producing an empty block,
+				// then allow the block if it was the right thing to do to maintain the L2 time >= L1 time invariant.
+				// We only check blocks that do not advance the epoch, to ensure epoch advancement regardless of time drift is allowed.
+				if !originAdvanced {
+					if originIdx+1 >= len(l1Blocks) {
+						log.Info("without the next L1 origin we cannot determine yet if this empty batch that exceeds the time drift is still valid")
+						return BatchUndecided, BatchRuleTimeDriftNextOrigin
+					}
+					if blockTimestamp >= l1Blocks[originIdx+1].Time { // check if the next L1 origin could have been adopted
+						log.Info("batch exceeded sequencer time drift without adopting next origin, and next L1 origin would have been valid")
+						return BatchDrop, BatchRuleTimeDriftAdoptOrigin
+					} else {
+						log.Info("continuing with empty batch before late L1 block to preserve L2 time invariant")
+					}
+				}
+			} else {
+				// If the sequencer is ignoring the time drift rule, then drop the batch and force an empty batch instead,
+				// as the sequencer is not allowed to include anything past this point without moving to the next epoch.
+				log.Warn("batch exceeded sequencer time drift, sequencer must adopt new L1 origin to include transactions again", "max_time", max)
+				return BatchDrop, BatchRuleTimeDriftExceeded
+			}
+		}
+
+		for j, txBytes := range batch.GetBlockTransactions(i) {
+			if len(txBytes) == 0 {
+				log.Warn("transaction data must not be empty, but found empty tx", "block_index", i, "tx_index", j)
+				return BatchDrop, BatchRuleEmptyTransaction
+			}
+			if txBytes[0] == types.DepositTxType {
+				log.Warn("sequencers may not embed any deposits into batch data, but found tx that has one", "block_index", i, "tx_index", j)
+				return BatchDrop, BatchRuleDepositTransaction
+			}
+		}
+	}
+
+	// The blocks that overlap with the safe chain must match it.
+	for i := 0; i < batch.GetBlockCount() && batch.GetBlockTimestamp(i) <= l2SafeHead.Time; i++ {
+		safeBlockNum := parentBlock.Number + uint64(i) + 1
+		payload, err := l2Fetcher.PayloadByNumber(ctx, safeBlockNum)
+		if err != nil {
+			log.Warn("failed to fetch L2 block", "number", safeBlockNum, "err", err)
+			// unable to validate the batch for now, retry later.
+			return BatchUndecided, BatchRuleL2Unavailable
+		}
+		// the safe block has deposit transactions, but the batch does not
+		var safeTxs []hexutil.Bytes
+		for _, tx := range payload.Transactions {
+			if len(tx) > 0 && tx[0] != types.DepositTxType {
+				safeTxs = append(safeTxs, hexutil.Bytes(tx))
+			}
+		}
+		batchTxs := batch.GetBlockTransactions(i)
+		if len(safeTxs) != len(batchTxs) {
+			log.Warn("overlapped block's tx count does not match", "safe_block_num", safeBlockNum, "safe_txs", len(safeTxs), "batch_txs", len(batchTxs))
+			return BatchDrop, BatchRuleOverlapMismatch
+		}
+		for j := range batchTxs {
+			if !bytes.Equal(safeTxs[j], batchTxs[j]) {
+				log.Warn("overlapped block's transaction does not match", "safe_block_num", safeBlockNum, "tx_index", j)
+				return BatchDrop, BatchRuleOverlapMismatch
+			}
+		}
+		safeBlock, err := PayloadToBlockRef(payload, &cfg.Genesis)
+		if err != nil {
+			log.Warn("failed to read L2 block", "number", safeBlockNum, "err", err)
+			return BatchUndecided, BatchRuleL2Unavailable
+		}
+		if safeBlock.L1Origin.Number != batch.GetBlockEpochNum(i) {
+			log.Warn("overlapped block's L1 origin number does not match", "safe_block_num", safeBlockNum, "safe_origin", safeBlock.L1Origin, "batch_origin", batch.GetBlockEpochNum(i))
+			return BatchDrop, BatchRuleOverlapMismatch
 		}
 	}
 
-	return BatchAccept
+	return BatchAccept, BatchRuleValid
 }
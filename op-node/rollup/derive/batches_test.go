@@ -1,6 +1,8 @@
 package derive
 
 import (
+	"context"
+	"math/big"
 	"math/rand"
 	"testing"
 
@@ -22,6 +24,8 @@ type ValidBatchTestCase struct {
 	L2SafeHead eth.L2BlockRef
 	Batch      BatchWithL1InclusionBlock
 	Expected   BatchValidity
+	// ExpectedRule is the rule that decides the expected validity
+	ExpectedRule BatchRule
 }
 
 var HashA = common.Hash{0x0a}
@@ -182,7 +186,8 @@ func TestValidBatch(t *testing.T) {
 					Transactions: nil,
 				}),
 			},
-			Expected: BatchUndecided,
+			Expected:     BatchUndecided,
+			ExpectedRule: BatchRuleMissingL1Blocks,
 		},
 		{
 			Name:       "future timestamp",
@@ -198,7 +203,8 @@ func TestValidBatch(t *testing.T) {
 					Transactions: nil,
 				}),
 			},
-			Expected: BatchFuture,
+			Expected:     BatchFuture,
+			ExpectedRule: BatchRuleFutureTimestamp,
 		},
 		{
 			Name:       "old timestamp",
@@ -214,7 +220,8 @@ func TestValidBatch(t *testing.T) {
 					Transactions: nil,
 				}),
 			},
-			Expected: BatchDrop,
+			Expected:     BatchDrop,
+			ExpectedRule: BatchRuleOldTimestamp,
 		},
 		{
 			Name:       "misaligned timestamp",
@@ -230,7 +237,8 @@ func TestValidBatch(t *testing.T) {
 					Transactions: nil,
 				}),
 			},
-			Expected: BatchDrop,
+			Expected:     BatchDrop,
+			ExpectedRule: BatchRuleOldTimestamp,
 		},
 		{
 			Name:       "invalid parent block hash",
@@ -246,7 +254,8 @@ func TestValidBatch(t *testing.T) {
 					Transactions: nil,
 				}),
 			},
-			Expected: BatchDrop,
+			Expected:     BatchDrop,
+			ExpectedRule: BatchRuleParentHash,
 		},
 		{
 			Name:       "sequence window expired",
@@ -262,7 +271,8 @@ func TestValidBatch(t *testing.T) {
 					Transactions: nil,
 				}),
 			},
-			Expected: BatchDrop,
+			Expected:     BatchDrop,
+			ExpectedRule: BatchRuleSeqWindowExpired,
 		},
 		{
 			Name:       "epoch too old, but good parent hash and timestamp", // repeat of now outdated l2A3 data
@@ -278,7 +288,8 @@ func TestValidBatch(t *testing.T) {
 					Transactions: nil,
 				}),
 			},
-			Expected: BatchDrop,
+			Expected:     BatchDrop,
+			ExpectedRule: BatchRuleEpochTooOld,
 		},
 		{
 			Name:       "insufficient L1 info for eager derivation",
@@ -294,7 +305,8 @@ func TestValidBatch(t *testing.T) {
 					Transactions: nil,
 				}),
 			},
-			Expected: BatchUndecided,
+			Expected:     BatchUndecided,
+			ExpectedRule: BatchRuleNextEpochUnknown,
 		},
 		{
 			Name:       "epoch too new",
@@ -310,7 +322,8 @@ func TestValidBatch(t *testing.T) {
 					Transactions: nil,
 				}),
 			},
-			Expected: BatchDrop,
+			Expected:     BatchDrop,
+			ExpectedRule: BatchRuleEpochTooNew,
 		},
 		{
 			Name:       "epoch hash wrong",
@@ -326,7 +339,8 @@ func TestValidBatch(t *testing.T) {
 					Transactions: nil,
 				}),
 			},
-			Expected: BatchDrop,
+			Expected:     BatchDrop,
+			ExpectedRule: BatchRuleEpochHash,
 		},
 		{
 			Name:       "sequencer time drift on same epoch with non-empty txs",
@@ -342,7 +356,8 @@ func TestValidBatch(t *testing.T) {
 					Transactions: []hexutil.Bytes{[]byte("sequencer should not include this tx")},
 				}),
 			},
-			Expected: BatchDrop,
+			Expected:     BatchDrop,
+			ExpectedRule: BatchRuleTimeDriftExceeded,
 		},
 		{
 			Name:       "sequencer time drift on changing epoch with non-empty txs",
@@ -358,7 +373,8 @@ func TestValidBatch(t *testing.T) {
 					Transactions: []hexutil.Bytes{[]byte("sequencer should not include this tx")},
 				}),
 			},
-			Expected: BatchDrop,
+			Expected:     BatchDrop,
+			ExpectedRule: BatchRuleTimeDriftExceeded,
 		},
 		{
 			Name:       "sequencer time drift on same epoch with empty txs and late next epoch",
@@ -374,7 +390,8 @@ func TestValidBatch(t *testing.T) {
 					Transactions: nil,
 				}),
 			},
-			Expected: BatchAccept, // accepted because empty & preserving L2 time invariant
+			Expected:     BatchAccept, // accepted because empty & preserving L2 time invariant
+			ExpectedRule: BatchRuleValid,
 		},
 		{
 			Name:       "sequencer time drift on changing epoch with empty txs",
@@ -390,7 +407,8 @@ func TestValidBatch(t *testing.T) {
 					Transactions: nil,
 				}),
 			},
-			Expected: BatchAccept, // accepted because empty & still advancing epoch
+			Expected:     BatchAccept, // accepted because empty & still advancing epoch
+			ExpectedRule: BatchRuleValid,
 		},
 		{
 			Name:       "sequencer time drift on same epoch with empty txs and no next epoch in sight yet",
@@ -406,7 +424,8 @@ func TestValidBatch(t *testing.T) {
 					Transactions: nil,
 				}),
 			},
-			Expected: BatchUndecided, // we have to wait till the next epoch is in sight to check the time
+			Expected:     BatchUndecided, // we have to wait till the next epoch is in sight to check the time
+			ExpectedRule: BatchRuleTimeDriftNextOrigin,
 		},
 		{
 			Name:       "sequencer time drift on same epoch with empty txs and but in-sight epoch that invalidates it",
@@ -422,7 +441,8 @@ func TestValidBatch(t *testing.T) {
 					Transactions: nil,
 				}),
 			},
-			Expected: BatchDrop, // dropped because it could have advanced the epoch to B
+			Expected:     BatchDrop, // dropped because it could have advanced the epoch to B
+			ExpectedRule: BatchRuleTimeDriftAdoptOrigin,
 		},
 		{
 			Name:       "empty tx included",
@@ -440,7 +460,8 @@ func TestValidBatch(t *testing.T) {
 					},
 				}),
 			},
-			Expected: BatchDrop,
+			Expected:     BatchDrop,
+			ExpectedRule: BatchRuleEmptyTransaction,
 		},
 		{
 			Name:       "deposit tx included",
@@ -458,7 +479,8 @@ func TestValidBatch(t *testing.T) {
 					},
 				}),
 			},
-			Expected: BatchDrop,
+			Expected:     BatchDrop,
+			ExpectedRule: BatchRuleDepositTransaction,
 		},
 		{
 			Name:       "valid batch same epoch",
@@ -477,7 +499,8 @@ func TestValidBatch(t *testing.T) {
 					},
 				}),
 			},
-			Expected: BatchAccept,
+			Expected:     BatchAccept,
+			ExpectedRule: BatchRuleValid,
 		},
 		{
 			Name:       "valid batch changing epoch",
@@ -496,7 +519,8 @@ func TestValidBatch(t *testing.T) {
 					},
 				}),
 			},
-			Expected: BatchAccept,
+			Expected:     BatchAccept,
+			ExpectedRule: BatchRuleValid,
 		},
 		{
 			Name:       "batch with L2 time before L1 time",
@@ -512,7 +536,8 @@ func TestValidBatch(t *testing.T) {
 					Transactions: nil,
 				}),
 			},
-			Expected: BatchDrop,
+			Expected:     BatchDrop,
+			ExpectedRule: BatchRuleTimestampBeforeOrigin,
 		},
 	}
 
@@ -521,8 +546,382 @@ func TestValidBatch(t *testing.T) {
 
 	for _, testCase := range testCases {
 		t.Run(testCase.Name, func(t *testing.T) {
-			validity := CheckBatch(&conf, logger, testCase.L1Blocks, testCase.L2SafeHead, &testCase.Batch)
+			validity, rule := CheckBatchRule(&conf, logger, testCase.L1Blocks, testCase.L2SafeHead, &testCase.Batch)
 			require.Equal(t, testCase.Expected, validity, "batch check must return expected validity level")
+			require.Equal(t, testCase.ExpectedRule, rule, "batch check must return the deciding rule")
+		})
+	}
+}
+
+type ValidSpanBatchTestCase struct {
+	Name             string
+	L1Blocks         []eth.L1BlockRef
+	L2SafeHead       eth.L2BlockRef
+	Batch            []*SingularBatch
+	L1InclusionBlock eth.L1BlockRef
+	// SafeBlocks are the safe L2 blocks that the span batch may overlap with, by number
+	SafeBlocks fakeSafeBlockFetcher
+	Expected   BatchValidity
+	// ExpectedRule is the rule that decides the expected validity
+	ExpectedRule BatchRule
+}
+
+func TestValidSpanBatch(t *testing.T) {
+	rng := rand.New(rand.NewSource(1234))
+	l1A := eth.L1BlockRef{Hash: testutils.RandomHash(rng), Number: 10, ParentHash: testutils.RandomHash(rng), Time: 1000}
+	l1B := eth.L1BlockRef{Hash: testutils.RandomHash(rng), Number: l1A.Number + 1, ParentHash: l1A.Hash, Time: l1A.Time + 7}
+	l1C := eth.L1BlockRef{Hash: testutils.RandomHash(rng), Number: l1B.Number + 1, ParentHash: l1B.Hash, Time: l1B.Time + 7}
+	l1Late := eth.L1BlockRef{Hash: testutils.RandomHash(rng), Number: l1A.Number + 5, ParentHash: testutils.RandomHash(rng), Time: l1A.Time + 60}
+
+	conf := rollup.Config{
+		Genesis: rollup.Genesis{
+			L1:     l1A.ID(),
+			L2:     eth.BlockID{Hash: testutils.RandomHash(rng), Number: 100},
+			L2Time: l1A.Time,
+		},
+		BlockTime:         2,
+		SeqWindowSize:     4,
+		MaxSequencerDrift: 6,
+		// other config fields are ignored and can be left empty.
+	}
+
+	// l2Block creates the L2 block after parent with the given L1 origin.
+	l2Block := func(parent eth.L2BlockRef, origin eth.L1BlockRef) eth.L2BlockRef {
+		ref := eth.L2BlockRef{
+			Hash:       testutils.RandomHash(rng),
+			Number:     parent.Number + 1,
+			ParentHash: parent.Hash,
+			Time:       parent.Time + conf.BlockTime,
+			L1Origin:   origin.ID(),
+		}
+		if parent.L1Origin == ref.L1Origin {
+			ref.SequenceNumber = parent.SequenceNumber + 1
+		}
+		return ref
+	}
+	l2A0 := eth.L2BlockRef{Hash: conf.Genesis.L2.Hash, Number: conf.Genesis.L2.Number, Time: conf.Genesis.L2Time, L1Origin: l1A.ID()}
+	l2A1 := l2Block(l2A0, l1A)
+	l2A2 := l2Block(l2A1, l1A)
+	l2A3 := l2Block(l2A2, l1A)
+	l2B0 := l2Block(l2A3, l1B) // 1 second after the time of its origin
+	l2B1 := l2Block(l2B0, l1B)
+	l2A4 := l2Block(l2A3, l1A) // exceeds the sequencer time drift of its origin
+
+	txsA1 := []hexutil.Bytes{{0x02, 0x42, 0x13, 0x37}}
+	txsB0 := []hexutil.Bytes{{0x02, 0xde, 0xad, 0xbe, 0xef}, {0x02, 0x01}}
+
+	batch := func(ref eth.L2BlockRef, origin eth.L1BlockRef, txs ...hexutil.Bytes) *SingularBatch {
+		return &SingularBatch{
+			ParentHash:   ref.ParentHash,
+			EpochNum:     rollup.Epoch(origin.Number),
+			EpochHash:    origin.Hash,
+			Timestamp:    ref.Time,
+			Transactions: txs,
+		}
+	}
+	batchA1 := batch(l2A1, l1A, txsA1...)
+	batchA2 := batch(l2A2, l1A)
+	batchA3 := batch(l2A3, l1A)
+	batchB0 := batch(l2B0, l1B, txsB0...)
+	batchB1 := batch(l2B1, l1B)
+
+	// payload creates the payload of a safe block, with the L1 info deposit of its origin.
+	payload := func(ref eth.L2BlockRef, origin eth.L1BlockRef, txs ...hexutil.Bytes) *eth.ExecutionPayload {
+		l1Info := &testutils.MockBlockInfo{InfoHash: origin.Hash, InfoNum: origin.Number, InfoTime: origin.Time, InfoBaseFee: big.NewInt(7)}
+		deposit, err := L1InfoDepositBytes(ref.SequenceNumber, l1Info, eth.SystemConfig{}, true)
+		require.NoError(t, err)
+		payloadTxs := []eth.Data{deposit}
+		for _, tx := range txs {
+			payloadTxs = append(payloadTxs, eth.Data(tx))
+		}
+		return &eth.ExecutionPayload{
+			ParentHash:   ref.ParentHash,
+			BlockNumber:  eth.Uint64Quantity(ref.Number),
+			BlockHash:    ref.Hash,
+			Timestamp:    eth.Uint64Quantity(ref.Time),
+			Transactions: payloadTxs,
+		}
+	}
+	safeBlocks := fakeSafeBlockFetcher{
+		l2A0.Number: payload(l2A0, l1A),
+		l2A1.Number: payload(l2A1, l1A, txsA1...),
+		l2A2.Number: payload(l2A2, l1A),
+		l2A3.Number: payload(l2A3, l1A),
+	}
+
+	withParent := func(b *SingularBatch, parent common.Hash) *SingularBatch {
+		c := *b
+		c.ParentHash = parent
+		return &c
+	}
+	withOrigin := func(b *SingularBatch, origin eth.L1BlockRef) *SingularBatch {
+		c := *b
+		c.EpochNum = rollup.Epoch(origin.Number)
+		c.EpochHash = origin.Hash
+		return &c
+	}
+	withTimestamp := func(b *SingularBatch, timestamp uint64) *SingularBatch {
+		c := *b
+		c.Timestamp = timestamp
+		return &c
+	}
+	withTxs := func(b *SingularBatch, txs ...hexutil.Bytes) *SingularBatch {
+		c := *b
+		c.Transactions = txs
+		return &c
+	}
+
+	testCases := []ValidSpanBatchTestCase{
+		{
+			Name:             "missing L1 info",
+			L1Blocks:         []eth.L1BlockRef{},
+			L2SafeHead:       l2A0,
+			Batch:            []*SingularBatch{batchA1, batchA2},
+			L1InclusionBlock: l1B,
+			Expected:         BatchUndecided,
+			ExpectedRule:     BatchRuleMissingL1Blocks,
+		},
+		{
+			Name:             "future timestamp",
+			L1Blocks:         []eth.L1BlockRef{l1A, l1B, l1C},
+			L2SafeHead:       l2A0,
+			Batch:            []*SingularBatch{batchA2, batchA3},
+			L1InclusionBlock: l1B,
+			Expected:         BatchFuture,
+			ExpectedRule:     BatchRuleFutureTimestamp,
+		},
+		{
+			Name:             "no new blocks",
+			L1Blocks:         []eth.L1BlockRef{l1A, l1B, l1C},
+			L2SafeHead:       l2A3,
+			Batch:            []*SingularBatch{batchA1, batchA2},
+			L1InclusionBlock: l1B,
+			SafeBlocks:       safeBlocks,
+			Expected:         BatchDrop,
+			ExpectedRule:     BatchRuleNoNewBlocks,
+		},
+		{
+			Name:       "misaligned timestamp after safe head",
+			L1Blocks:   []eth.L1BlockRef{l1A, l1B, l1C},
+			L2SafeHead: l2A2,
+			Batch: []*SingularBatch{
+				withTimestamp(batchA3, l2A2.Time+1),
+				withTimestamp(batchB0, l2A2.Time+3),
+			},
+			L1InclusionBlock: l1B,
+			SafeBlocks:       safeBlocks,
+			Expected:         BatchDrop,
+			ExpectedRule:     BatchRuleMisalignedTimestamp,
+		},
+		{
+			Name:       "misaligned overlapping timestamp",
+			L1Blocks:   []eth.L1BlockRef{l1A, l1B, l1C},
+			L2SafeHead: l2A2,
+			Batch: []*SingularBatch{
+				withTimestamp(batchA2, l2A2.Time-1),
+				withTimestamp(batchA3, l2A2.Time+1),
+				withTimestamp(batchB0, l2A2.Time+3),
+			},
+			L1InclusionBlock: l1B,
+			SafeBlocks:       safeBlocks,
+			Expected:         BatchDrop,
+			ExpectedRule:     BatchRuleMisalignedTimestamp,
+		},
+		{
+			Name:       "starts before genesis",
+			L1Blocks:   []eth.L1BlockRef{l1A, l1B, l1C},
+			L2SafeHead: l2A1,
+			Batch: []*SingularBatch{
+				withTimestamp(batchA1, l2A0.Time),
+				batchA1,
+				batchA2,
+			},
+			L1InclusionBlock: l1B,
+			SafeBlocks:       safeBlocks,
+			Expected:         BatchDrop,
+			ExpectedRule:     BatchRuleOldTimestamp,
+		},
+		{
+			Name:             "overlapped block unavailable",
+			L1Blocks:         []eth.L1BlockRef{l1A, l1B, l1C},
+			L2SafeHead:       l2A2,
+			Batch:            []*SingularBatch{batchA1, batchA2, batchA3},
+			L1InclusionBlock: l1B,
+			SafeBlocks:       fakeSafeBlockFetcher{},
+			Expected:         BatchUndecided,
+			ExpectedRule:     BatchRuleL2Unavailable,
+		},
+		{
+			Name:             "parent hash mismatch",
+			L1Blocks:         []eth.L1BlockRef{l1A, l1B, l1C},
+			L2SafeHead:       l2A0,
+			Batch:            []*SingularBatch{withParent(batchA1, testutils.RandomHash(rng)), batchA2},
+			L1InclusionBlock: l1B,
+			Expected:         BatchDrop,
+			ExpectedRule:     BatchRuleParentHash,
+		},
+		{
+			Name:             "overlapped parent hash mismatch",
+			L1Blocks:         []eth.L1BlockRef{l1A, l1B, l1C},
+			L2SafeHead:       l2A2,
+			Batch:            []*SingularBatch{withParent(batchA2, testutils.RandomHash(rng)), batchA3},
+			L1InclusionBlock: l1B,
+			SafeBlocks:       safeBlocks,
+			Expected:         BatchDrop,
+			ExpectedRule:     BatchRuleParentHash,
+		},
+		{
+			Name:             "sequence window expired",
+			L1Blocks:         []eth.L1BlockRef{l1A, l1B, l1C},
+			L2SafeHead:       l2A0,
+			Batch:            []*SingularBatch{batchA1, batchA2},
+			L1InclusionBlock: l1Late,
+			Expected:         BatchDrop,
+			ExpectedRule:     BatchRuleSeqWindowExpired,
+		},
+		{
+			Name:             "epoch too new",
+			L1Blocks:         []eth.L1BlockRef{l1A, l1B, l1C},
+			L2SafeHead:       l2A0,
+			Batch:            []*SingularBatch{withOrigin(batchA1, l1C)},
+			L1InclusionBlock: l1C,
+			Expected:         BatchDrop,
+			ExpectedRule:     BatchRuleEpochTooNew,
+		},
+		{
+			Name:             "L1 origins unknown",
+			L1Blocks:         []eth.L1BlockRef{l1A},
+			L2SafeHead:       l2A3,
+			Batch:            []*SingularBatch{batchB0, batchB1},
+			L1InclusionBlock: l1B,
+			Expected:         BatchUndecided,
+			ExpectedRule:     BatchRuleOriginsUnknown,
+		},
+		{
+			Name:       "epoch hash mismatch",
+			L1Blocks:   []eth.L1BlockRef{l1A, l1B, l1C},
+			L2SafeHead: l2A3,
+			Batch: []*SingularBatch{
+				batchB0,
+				withOrigin(batchB1, eth.L1BlockRef{Hash: testutils.RandomHash(rng), Number: l1B.Number}),
+			},
+			L1InclusionBlock: l1C,
+			Expected:         BatchDrop,
+			ExpectedRule:     BatchRuleEpochHash,
+		},
+		{
+			Name:             "epoch too old",
+			L1Blocks:         []eth.L1BlockRef{l1A, l1B, l1C},
+			L2SafeHead:       l2B0,
+			Batch:            []*SingularBatch{withOrigin(batchB1, l1A)},
+			L1InclusionBlock: l1C,
+			Expected:         BatchDrop,
+			ExpectedRule:     BatchRuleEpochTooOld,
+		},
+		{
+			Name:             "L2 time before L1 time",
+			L1Blocks:         []eth.L1BlockRef{l1A, l1B, l1C},
+			L2SafeHead:       l2A2,
+			Batch:            []*SingularBatch{withOrigin(batchA3, l1B)},
+			L1InclusionBlock: l1C,
+			Expected:         BatchDrop,
+			ExpectedRule:     BatchRuleTimestampBeforeOrigin,
+		},
+		{
+			Name:             "sequencer time drift with transactions",
+			L1Blocks:         []eth.L1BlockRef{l1A, l1B, l1C},
+			L2SafeHead:       l2A3,
+			Batch:            []*SingularBatch{batch(l2A4, l1A, txsA1...)},
+			L1InclusionBlock: l1C,
+			Expected:         BatchDrop,
+			ExpectedRule:     BatchRuleTimeDriftExceeded,
+		},
+		{
+			Name:             "empty block exceeding time drift without next L1 origin",
+			L1Blocks:         []eth.L1BlockRef{l1A},
+			L2SafeHead:       l2A3,
+			Batch:            []*SingularBatch{batch(l2A4, l1A)},
+			L1InclusionBlock: l1B,
+			Expected:         BatchUndecided,
+			ExpectedRule:     BatchRuleTimeDriftNextOrigin,
+		},
+		{
+			Name:             "empty block exceeding time drift without adopting next L1 origin",
+			L1Blocks:         []eth.L1BlockRef{l1A, l1B, l1C},
+			L2SafeHead:       l2A3,
+			Batch:            []*SingularBatch{batch(l2A4, l1A)},
+			L1InclusionBlock: l1C,
+			Expected:         BatchDrop,
+			ExpectedRule:     BatchRuleTimeDriftAdoptOrigin,
+		},
+		{
+			Name:             "empty transaction",
+			L1Blocks:         []eth.L1BlockRef{l1A, l1B, l1C},
+			L2SafeHead:       l2A0,
+			Batch:            []*SingularBatch{withTxs(batchA1, hexutil.Bytes{}), batchA2},
+			L1InclusionBlock: l1B,
+			Expected:         BatchDrop,
+			ExpectedRule:     BatchRuleEmptyTransaction,
+		},
+		{
+			Name:             "deposit transaction",
+			L1Blocks:         []eth.L1BlockRef{l1A, l1B, l1C},
+			L2SafeHead:       l2A0,
+			Batch:            []*SingularBatch{batchA1, withTxs(batchA2, hexutil.Bytes{types.DepositTxType, 0x01})},
+			L1InclusionBlock: l1B,
+			Expected:         BatchDrop,
+			ExpectedRule:     BatchRuleDepositTransaction,
+		},
+		{
+			Name:             "overlapped block transactions mismatch",
+			L1Blocks:         []eth.L1BlockRef{l1A, l1B, l1C},
+			L2SafeHead:       l2A2,
+			Batch:            []*SingularBatch{withTxs(batchA1, txsB0...), batchA2, batchA3},
+			L1InclusionBlock: l1B,
+			SafeBlocks:       safeBlocks,
+			Expected:         BatchDrop,
+			ExpectedRule:     BatchRuleOverlapMismatch,
+		},
+		{
+			Name:             "overlapped block missing transaction",
+			L1Blocks:         []eth.L1BlockRef{l1A, l1B, l1C},
+			L2SafeHead:       l2A2,
+			Batch:            []*SingularBatch{withTxs(batchA1), batchA2, batchA3},
+			L1InclusionBlock: l1B,
+			SafeBlocks:       safeBlocks,
+			Expected:         BatchDrop,
+			ExpectedRule:     BatchRuleOverlapMismatch,
+		},
+		{
+			Name:             "valid overlapping batch",
+			L1Blocks:         []eth.L1BlockRef{l1A, l1B, l1C},
+			L2SafeHead:       l2A2,
+			Batch:            []*SingularBatch{batchA1, batchA2, batchA3, batchB0},
+			L1InclusionBlock: l1C,
+			SafeBlocks:       safeBlocks,
+			Expected:         BatchAccept,
+			ExpectedRule:     BatchRuleValid,
+		},
+		{
+			Name:             "valid batch changing epoch",
+			L1Blocks:         []eth.L1BlockRef{l1A, l1B, l1C},
+			L2SafeHead:       l2A0,
+			Batch:            []*SingularBatch{batchA1, batchA2, batchA3, batchB0, batchB1},
+			L1InclusionBlock: l1C,
+			Expected:         BatchAccept,
+			ExpectedRule:     BatchRuleValid,
+		},
+	}
+
+	// Log level can be increased for debugging purposes
+	logger := testlog.Logger(t, log.LvlError)
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			spanBatch := NewSpanBatch(testCase.Batch)
+			validity, rule := CheckSpanBatchRule(context.Background(), &conf, logger, testCase.L1Blocks, testCase.L2SafeHead, spanBatch, testCase.L1InclusionBlock, testCase.SafeBlocks)
+			require.Equal(t, testCase.Expected, validity, "span batch check must return expected validity level")
+			require.Equal(t, testCase.ExpectedRule, rule, "span batch check must return the deciding rule")
 		})
 	}
 }
@@ -0,0 +1,150 @@
+package derive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+)
+
+// blobOrCalldata is the batch data of a single batcher transaction:
+// either calldata, or the index of a blob in the list of fetched blobs.
+type blobOrCalldata struct {
+	// calldata is nil if the data is in a blob
+	calldata *eth.Data
+	// blobIndex is the index of the blob in the list of hashes that are fetched
+	blobIndex int
+}
+
+// BlobDataSource fetches both call-data (backwards compatibility) and blobs and tracks them.
+// Like the DataSource, it is fault tolerant: it re-attempts any failed fetching on the next call to Next.
+type BlobDataSource struct {
+	data []eth.Data
+	open bool
+
+	ref          eth.L1BlockRef
+	batcherAddr  common.Address
+	cfg          *rollup.Config
+	fetcher      L1TransactionFetcher
+	blobsFetcher L1BlobsFetcher
+	log          log.Logger
+}
+
+// NewBlobDataSource creates a new blob data source.
+// Fetching is deferred to the first call to Next, so it can be retried if it fails.
+func NewBlobDataSource(ctx context.Context, log log.Logger, cfg *rollup.Config, fetcher L1TransactionFetcher, blobsFetcher L1BlobsFetcher, ref eth.L1BlockRef, batcherAddr common.Address) DataIter {
+	return &BlobDataSource{
+		ref:          ref,
+		batcherAddr:  batcherAddr,
+		cfg:          cfg,
+		fetcher:      fetcher,
+		blobsFetcher: blobsFetcher,
+		log:          log.New("origin", ref),
+	}
+}
+
+// Next returns the next piece of batcher data, or an io.EOF error if no data remains.
+// It returns a ResetError if it cannot find the referenced block or a referenced blob,
+// or a TemporaryError for any other failure to fetch a block or blob.
+func (ds *BlobDataSource) Next(ctx context.Context) (eth.Data, error) {
+	if !ds.open {
+		data, err := ds.fetchData(ctx)
+		if err != nil {
+			return nil, err
+		}
+		ds.data = data
+		ds.open = true
+	}
+	if len(ds.data) == 0 {
+		return nil, io.EOF
+	}
+	data := ds.data[0]
+	ds.data = ds.data[1:]
+	return data, nil
+}
+
+// fetchData fetches the transactions and blobs of the L1 block, and decodes them into batcher data.
+func (ds *BlobDataSource) fetchData(ctx context.Context) ([]eth.Data, error) {
+	_, txs, err := ds.fetcher.InfoAndTxsByHash(ctx, ds.ref.Hash)
+	if err != nil {
+		if errors.Is(err, ethereum.NotFound) {
+			return nil, NewResetError(fmt.Errorf("failed to open blob data source: %w", err))
+		}
+		return nil, NewTemporaryError(fmt.Errorf("failed to open blob data source: %w", err))
+	}
+
+	data, hashes := dataAndHashesFromTxs(txs, ds.cfg, ds.batcherAddr, ds.log)
+
+	var blobs []*eth.Blob
+	if len(hashes) > 0 {
+		// download the actual blob bodies corresponding to the indexed blob hashes
+		blobs, err = ds.blobsFetcher.GetBlobs(ctx, ds.ref, hashes)
+		if errors.Is(err, ethereum.NotFound) {
+			// If the L1 block was available, then the blobs should be available too,
+			// unless the blob retention window of the beacon node has expired.
+			return nil, NewResetError(fmt.Errorf("failed to fetch blobs: %w", err))
+		} else if err != nil {
+			return nil, NewTemporaryError(fmt.Errorf("failed to fetch blobs: %w", err))
+		}
+	}
+
+	// go over the data in transaction order, and decode the blob data of each blob-type transaction
+	var out []eth.Data
+	for _, d := range data {
+		if d.calldata != nil {
+			out = append(out, *d.calldata)
+			continue
+		}
+		blobData, err := blobs[d.blobIndex].ToData()
+		if err != nil {
+			// Blob data is committed to by the batcher, so malformed blobs can only be ignored.
+			ds.log.Warn("ignoring blob due to parse failure", "blobIndex", d.blobIndex, "err", err)
+			continue
+		}
+		out = append(out, blobData)
+	}
+	return out, nil
+}
+
+// dataAndHashesFromTxs extracts calldata and blob hashes from the batcher transactions, in transaction order.
+// For each returned blob hash it creates a blobOrCalldata placeholder, which points to the blob by its position in the hashes.
+func dataAndHashesFromTxs(txs types.Transactions, cfg *rollup.Config, batcherAddr common.Address, log log.Logger) ([]blobOrCalldata, []eth.IndexedBlobHash) {
+	var data []blobOrCalldata
+	var hashes []eth.IndexedBlobHash
+	l1Signer := cfg.L1Signer()
+	blobIndex := 0 // index of each blob in the block's blob sidecar
+	for _, tx := range txs {
+		// skip any non-batcher transactions
+		if !isValidBatchTx(tx, l1Signer, cfg.BatchInboxAddress, batcherAddr, log) {
+			blobIndex += len(tx.BlobHashes())
+			continue
+		}
+		// handle non-blob batcher transactions by extracting their calldata
+		if tx.Type() != types.BlobTxType {
+			calldata := eth.Data(tx.Data())
+			data = append(data, blobOrCalldata{calldata: &calldata})
+			continue
+		}
+		// handle blob batcher transactions by extracting their blob hashes, ignoring any calldata.
+		if len(tx.Data()) > 0 {
+			log.Warn("blob tx has calldata, which will be ignored", "txhash", tx.Hash())
+		}
+		for _, h := range tx.BlobHashes() {
+			data = append(data, blobOrCalldata{blobIndex: len(hashes)})
+			hashes = append(hashes, eth.IndexedBlobHash{
+				Index: uint64(blobIndex),
+				Hash:  h,
+			})
+			blobIndex += 1
+		}
+	}
+	return data, hashes
+}
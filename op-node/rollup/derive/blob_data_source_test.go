@@ -0,0 +1,177 @@
+package derive
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"io"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+	"github.com/ethereum-optimism/optimism/op-service/testutils"
+)
+
+// fakeBlobsFetcher serves blobs by versioned hash, and fails for unknown hashes.
+type fakeBlobsFetcher struct {
+	blobs map[common.Hash]*eth.Blob
+	err   error
+}
+
+func (f *fakeBlobsFetcher) GetBlobs(ctx context.Context, ref eth.L1BlockRef, hashes []eth.IndexedBlobHash) ([]*eth.Blob, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	out := make([]*eth.Blob, len(hashes))
+	for i, h := range hashes {
+		b, ok := f.blobs[h.Hash]
+		if !ok {
+			return nil, ethereum.NotFound
+		}
+		out[i] = b
+	}
+	return out, nil
+}
+
+func createBlobTx(t *testing.T, signer types.Signer, key *ecdsa.PrivateKey, to common.Address, blobHashes []common.Hash) *types.Transaction {
+	out, err := types.SignNewTx(key, signer, &types.BlobTx{
+		ChainID:    uint256.MustFromBig(signer.ChainID()),
+		Nonce:      0,
+		GasTipCap:  uint256.NewInt(2 * params.GWei),
+		GasFeeCap:  uint256.NewInt(30 * params.GWei),
+		Gas:        100_000,
+		To:         to,
+		BlobFeeCap: uint256.NewInt(params.GWei),
+		BlobHashes: blobHashes,
+	})
+	require.NoError(t, err)
+	return out
+}
+
+func TestBlobDataSource(t *testing.T) {
+	rng := rand.New(rand.NewSource(1234))
+	batcherPriv := testutils.RandomKey()
+	batcherAddr := crypto.PubkeyToAddress(batcherPriv.PublicKey)
+	altAuthor := testutils.RandomKey()
+	cfg := &rollup.Config{
+		L1ChainID:         big.NewInt(100),
+		BatchInboxAddress: testutils.RandomAddress(rng),
+	}
+	signer := cfg.L1Signer()
+	logger := testlog.Logger(t, log.LvlCrit)
+
+	blobsFetcher := &fakeBlobsFetcher{blobs: make(map[common.Hash]*eth.Blob)}
+	newBlob := func(data []byte) common.Hash {
+		var b eth.Blob
+		require.NoError(t, b.FromData(data))
+		h := testutils.RandomHash(rng)
+		h[0] = eth.VersionedHashVersionKZG
+		blobsFetcher.blobs[h] = &b
+		return h
+	}
+
+	calldataTx := (&testTx{to: &cfg.BatchInboxAddress, dataLen: 100, author: batcherPriv}).Create(t, signer, rng)
+	unauthorizedTx := (&testTx{to: &cfg.BatchInboxAddress, dataLen: 100, author: altAuthor}).Create(t, signer, rng)
+	blobData0, blobData1, blobData2 := testutils.RandomData(rng, 1000), testutils.RandomData(rng, 2000), testutils.RandomData(rng, 3000)
+	// a blob tx that is not from the batcher still occupies blob indices in the block
+	unrelatedBlobTx := createBlobTx(t, signer, altAuthor, cfg.BatchInboxAddress, []common.Hash{newBlob(testutils.RandomData(rng, 10))})
+	blobTx := createBlobTx(t, signer, batcherPriv, cfg.BatchInboxAddress, []common.Hash{newBlob(blobData0), newBlob(blobData1)})
+	blobTx2 := createBlobTx(t, signer, batcherPriv, cfg.BatchInboxAddress, []common.Hash{newBlob(blobData2)})
+
+	txs := types.Transactions{unrelatedBlobTx, calldataTx, unauthorizedTx, blobTx, blobTx2}
+
+	t.Run("data and hashes", func(t *testing.T) {
+		data, hashes := dataAndHashesFromTxs(txs, cfg, batcherAddr, logger)
+		require.Len(t, data, 4)
+		require.Equal(t, eth.Data(calldataTx.Data()), *data[0].calldata)
+		require.Equal(t, []eth.IndexedBlobHash{
+			{Index: 1, Hash: blobTx.BlobHashes()[0]},
+			{Index: 2, Hash: blobTx.BlobHashes()[1]},
+			{Index: 3, Hash: blobTx2.BlobHashes()[0]},
+		}, hashes)
+	})
+
+	t.Run("read data", func(t *testing.T) {
+		ref := eth.L1BlockRef{Hash: testutils.RandomHash(rng), Number: 10}
+		l1F := &testutils.MockL1Source{}
+		l1F.ExpectInfoAndTxsByHash(ref.Hash, testutils.RandomBlockInfo(rng), txs, nil)
+		src := NewBlobDataSource(context.Background(), logger, cfg, l1F, blobsFetcher, ref, batcherAddr)
+		for _, expected := range [][]byte{calldataTx.Data(), blobData0, blobData1, blobData2} {
+			data, err := src.Next(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, eth.Data(expected), data)
+		}
+		_, err := src.Next(context.Background())
+		require.ErrorIs(t, err, io.EOF)
+		l1F.AssertExpectations(t)
+	})
+
+	t.Run("missing block", func(t *testing.T) {
+		ref := eth.L1BlockRef{Hash: testutils.RandomHash(rng), Number: 11}
+		l1F := &testutils.MockL1Source{}
+		l1F.ExpectInfoAndTxsByHash(ref.Hash, testutils.RandomBlockInfo(rng), nil, ethereum.NotFound)
+		src := NewBlobDataSource(context.Background(), logger, cfg, l1F, blobsFetcher, ref, batcherAddr)
+		_, err := src.Next(context.Background())
+		require.ErrorIs(t, err, ErrReset)
+	})
+
+	t.Run("missing blobs", func(t *testing.T) {
+		ref := eth.L1BlockRef{Hash: testutils.RandomHash(rng), Number: 12}
+		l1F := &testutils.MockL1Source{}
+		unknownBlobTx := createBlobTx(t, signer, batcherPriv, cfg.BatchInboxAddress, []common.Hash{testutils.RandomHash(rng)})
+		l1F.ExpectInfoAndTxsByHash(ref.Hash, testutils.RandomBlockInfo(rng), types.Transactions{unknownBlobTx}, nil)
+		src := NewBlobDataSource(context.Background(), logger, cfg, l1F, blobsFetcher, ref, batcherAddr)
+		_, err := src.Next(context.Background())
+		require.ErrorIs(t, err, ErrReset)
+	})
+
+	t.Run("temporary blob fetching error", func(t *testing.T) {
+		ref := eth.L1BlockRef{Hash: testutils.RandomHash(rng), Number: 13}
+		l1F := &testutils.MockL1Source{}
+		l1F.ExpectInfoAndTxsByHash(ref.Hash, testutils.RandomBlockInfo(rng), txs, nil)
+		failing := &fakeBlobsFetcher{err: io.ErrUnexpectedEOF}
+		src := NewBlobDataSource(context.Background(), logger, cfg, l1F, failing, ref, batcherAddr)
+		_, err := src.Next(context.Background())
+		require.ErrorIs(t, err, ErrTemporary)
+
+		// once the beacon node recovers, the data source retries fetching
+		l1F.ExpectInfoAndTxsByHash(ref.Hash, testutils.RandomBlockInfo(rng), txs, nil)
+		failing.err = nil
+		failing.blobs = blobsFetcher.blobs
+		data, err := src.Next(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, eth.Data(calldataTx.Data()), data)
+	})
+}
+
+func TestDataSourceFactoryBlobsActivation(t *testing.T) {
+	rng := rand.New(rand.NewSource(1234))
+	blobsTime := uint64(1000)
+	cfg := &rollup.Config{
+		L1ChainID:               big.NewInt(100),
+		BlobsEnabledL1Timestamp: &blobsTime,
+	}
+	logger := testlog.Logger(t, log.LvlCrit)
+	l1F := &testutils.MockL1Source{}
+
+	l1F.ExpectInfoAndTxsByHash(common.Hash{0xaa}, testutils.RandomBlockInfo(rng), nil, nil)
+	factory := NewDataSourceFactory(logger, cfg, l1F, &fakeBlobsFetcher{}, nil)
+	require.IsType(t, &DataSource{}, factory.OpenData(context.Background(), eth.L1BlockRef{Hash: common.Hash{0xaa}, Time: blobsTime - 1}, common.Address{}))
+	require.IsType(t, &BlobDataSource{}, factory.OpenData(context.Background(), eth.L1BlockRef{Time: blobsTime}, common.Address{}))
+
+	noBlobs := NewDataSourceFactory(logger, cfg, l1F, nil, nil)
+	_, err := noBlobs.OpenData(context.Background(), eth.L1BlockRef{Time: blobsTime}, common.Address{}).Next(context.Background())
+	require.ErrorIs(t, err, ErrCritical)
+}
@@ -23,22 +23,59 @@ type L1TransactionFetcher interface {
 	InfoAndTxsByHash(ctx context.Context, hash common.Hash) (eth.BlockInfo, types.Transactions, error)
 }
 
+type L1BlobsFetcher interface {
+	// GetBlobs fetches blobs that were confirmed in the given L1 block with the given indexed hashes.
+	GetBlobs(ctx context.Context, ref eth.L1BlockRef, hashes []eth.IndexedBlobHash) ([]*eth.Blob, error)
+}
+
 // DataSourceFactory readers raw transactions from a given block & then filters for
 // batch submitter transactions.
 // This is not a stage in the pipeline, but a wrapper for another stage in the pipeline
 type DataSourceFactory struct {
-	log     log.Logger
-	cfg     *rollup.Config
-	fetcher L1TransactionFetcher
+	log          log.Logger
+	cfg          *rollup.Config
+	fetcher      L1TransactionFetcher
+	blobsFetcher L1BlobsFetcher
+	daFetcher    DAFetcher
 }
 
-func NewDataSourceFactory(log log.Logger, cfg *rollup.Config, fetcher L1TransactionFetcher) *DataSourceFactory {
-	return &DataSourceFactory{log: log, cfg: cfg, fetcher: fetcher}
+// NewDataSourceFactory creates a new DataSourceFactory.
+// The blobsFetcher may be nil if the rollup config does not enable blobs data-availability,
+// and the daFetcher may be nil if the rollup config does not enable DA commitments.
+func NewDataSourceFactory(log log.Logger, cfg *rollup.Config, fetcher L1TransactionFetcher, blobsFetcher L1BlobsFetcher, daFetcher DAFetcher) *DataSourceFactory {
+	return &DataSourceFactory{log: log, cfg: cfg, fetcher: fetcher, blobsFetcher: blobsFetcher, daFetcher: daFetcher}
 }
 
 // OpenData returns a DataIter. This struct implements the `Next` function.
-func (ds *DataSourceFactory) OpenData(ctx context.Context, id eth.BlockID, batcherAddr common.Address) DataIter {
-	return NewDataSource(ctx, ds.log, ds.cfg, ds.fetcher, id, batcherAddr)
+// Once blobs are enabled for the given L1 block, the data is read from both calldata and blobs.
+// Once DA commitments are enabled for the given L1 block, the commitments in the data are resolved
+// with the DA fetcher.
+func (ds *DataSourceFactory) OpenData(ctx context.Context, ref eth.L1BlockRef, batcherAddr common.Address) DataIter {
+	var src DataIter
+	if ds.cfg.IsBlobsEnabled(ref.Time) {
+		if ds.blobsFetcher == nil {
+			return &failingDataSource{err: NewCriticalError(fmt.Errorf("blobs are enabled at L1 block %s, but no L1 blobs fetcher is available", ref))}
+		}
+		src = NewBlobDataSource(ctx, ds.log, ds.cfg, ds.fetcher, ds.blobsFetcher, ref, batcherAddr)
+	} else {
+		src = NewDataSource(ctx, ds.log, ds.cfg, ds.fetcher, ref.ID(), batcherAddr)
+	}
+	if ds.cfg.IsDACommitments(ref.Time) {
+		if ds.daFetcher == nil {
+			return &failingDataSource{err: NewCriticalError(fmt.Errorf("DA commitments are enabled at L1 block %s, but no DA fetcher is available", ref))}
+		}
+		return NewDACommitmentSource(ds.log.New("origin", ref), src, ds.daFetcher)
+	}
+	return src
+}
+
+// failingDataSource is a DataIter that always returns the same error.
+type failingDataSource struct {
+	err error
+}
+
+func (ds *failingDataSource) Next(ctx context.Context) (eth.Data, error) {
+	return nil, ds.err
 }
 
 // DataSource is a fault tolerant approach to fetching data.
@@ -108,19 +145,28 @@ func DataFromEVMTransactions(config *rollup.Config, batcherAddr common.Address,
 	var out []eth.Data
 	l1Signer := config.L1Signer()
 	for j, tx := range txs {
-		if to := tx.To(); to != nil && *to == config.BatchInboxAddress {
-			seqDataSubmitter, err := l1Signer.Sender(tx) // optimization: only derive sender if To is correct
-			if err != nil {
-				log.Warn("tx in inbox with invalid signature", "index", j, "err", err)
-				continue // bad signature, ignore
-			}
-			// some random L1 user might have sent a transaction to our batch inbox, ignore them
-			if seqDataSubmitter != batcherAddr {
-				log.Warn("tx in inbox with unauthorized submitter", "index", j, "err", err)
-				continue // not an authorized batch submitter, ignore
-			}
+		if isValidBatchTx(tx, l1Signer, config.BatchInboxAddress, batcherAddr, log.New("index", j)) {
 			out = append(out, tx.Data())
 		}
 	}
 	return out
 }
+
+// isValidBatchTx returns true if the transaction is sent to the batch inbox address by the batch sender address.
+func isValidBatchTx(tx *types.Transaction, l1Signer types.Signer, batchInboxAddr, batcherAddr common.Address, log log.Logger) bool {
+	to := tx.To()
+	if to == nil || *to != batchInboxAddr {
+		return false
+	}
+	seqDataSubmitter, err := l1Signer.Sender(tx) // optimization: only derive sender if To is correct
+	if err != nil {
+		log.Warn("tx in inbox with invalid signature", "hash", tx.Hash(), "err", err)
+		return false // bad signature, ignore
+	}
+	// some random L1 user might have sent a transaction to our batch inbox, ignore them
+	if seqDataSubmitter != batcherAddr {
+		log.Warn("tx in inbox with unauthorized submitter", "addr", seqDataSubmitter, "hash", tx.Hash())
+		return false // not an authorized batch submitter, ignore
+	}
+	return true
+}
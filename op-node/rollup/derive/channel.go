@@ -2,7 +2,6 @@ package derive
 
 import (
 	"bytes"
-	"compress/zlib"
 	"fmt"
 	"io"
 
@@ -148,7 +147,7 @@ func (ch *Channel) Reader() io.Reader {
 // The L1Inclusion block is also provided at creation time.
 func BatchReader(cfg *rollup.Config, r io.Reader, l1InclusionBlock eth.L1BlockRef) (func() (BatchWithL1InclusionBlock, error), error) {
 	// Setup decompressor stage + RLP reader
-	zr, err := zlib.NewReader(r)
+	zr, err := channelDecompressor(r, cfg.IsChannelCompression(l1InclusionBlock.Time))
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,235 @@
+package derive
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+)
+
+// CompressionAlgo is the algorithm that the data of a channel is compressed with.
+type CompressionAlgo string
+
+const (
+	// Zlib compressed channels have no version byte. They are identified by the
+	// deflate compression method of the zlib header.
+	Zlib CompressionAlgo = "zlib"
+	// Brotli compressed channels are prefixed with ChannelVersionBrotli.
+	Brotli CompressionAlgo = "brotli"
+	// Zstd compressed channels are prefixed with ChannelVersionZstd.
+	Zstd CompressionAlgo = "zstd"
+)
+
+var CompressionAlgos = []CompressionAlgo{
+	Zlib,
+	Brotli,
+	Zstd,
+}
+
+const (
+	// ChannelVersionBrotli is the version byte of brotli compressed channels.
+	ChannelVersionBrotli byte = 0x01
+	// ChannelVersionZstd is the version byte of zstd compressed channels.
+	ChannelVersionZstd byte = 0x02
+
+	// zlibCMDeflate and zlibCMReserved are the two compression methods that can
+	// appear in the lower nibble of the first byte of a zlib stream. They never
+	// collide with the channel version bytes.
+	zlibCMDeflate  = 8
+	zlibCMReserved = 15
+)
+
+func (algo CompressionAlgo) String() string {
+	return string(algo)
+}
+
+func (algo *CompressionAlgo) Set(value string) error {
+	if !ValidCompressionAlgo(CompressionAlgo(value)) {
+		return fmt.Errorf("unknown compression algo: %q", value)
+	}
+	*algo = CompressionAlgo(value)
+	return nil
+}
+
+func (algo *CompressionAlgo) Clone() any {
+	cpy := *algo
+	return &cpy
+}
+
+// IsVersioned returns whether channels compressed with this algorithm are
+// prefixed with a channel version byte. Versioned channels are only accepted
+// once the channel compression upgrade is active.
+func (algo CompressionAlgo) IsVersioned() bool {
+	return algo == Brotli || algo == Zstd
+}
+
+// DefaultLevel returns the compression level that is used if none is configured.
+func (algo CompressionAlgo) DefaultLevel() int {
+	switch algo {
+	case Brotli:
+		return 10
+	case Zstd:
+		return 19
+	default:
+		return zlib.BestCompression
+	}
+}
+
+// CheckLevel returns an error if the given level is not a valid compression
+// level for this algorithm. Level 0 always selects the default level.
+func (algo CompressionAlgo) CheckLevel(level int) error {
+	var min, max int
+	switch algo {
+	case Zlib:
+		min, max = zlib.HuffmanOnly, zlib.BestCompression
+	case Brotli:
+		min, max = brotli.BestSpeed, brotli.BestCompression
+	case Zstd:
+		min, max = 1, 22
+	default:
+		return fmt.Errorf("unknown compression algo: %q", algo)
+	}
+	if level != 0 && (level < min || level > max) {
+		return fmt.Errorf("invalid %s compression level %d, must be in range [%d, %d]", algo, level, min, max)
+	}
+	return nil
+}
+
+func ValidCompressionAlgo(value CompressionAlgo) bool {
+	for _, k := range CompressionAlgos {
+		if k == value {
+			return true
+		}
+	}
+	return false
+}
+
+// ChannelCompressor is a streaming compressor of channel data. The compressed
+// output is prefixed with the channel version byte of the compression algorithm,
+// if it has one.
+type ChannelCompressor interface {
+	// Write writes uncompressed data to the compressor.
+	io.Writer
+	// Close flushes all remaining data to the compressed output.
+	io.Closer
+	// Read reads compressed data, including the version byte.
+	io.Reader
+	// Reset resets the compressor to compress a new channel.
+	Reset()
+	// Len returns the length of the currently available compressed data.
+	Len() int
+	// Flush flushes all buffered data to the compressed output, which worsens
+	// the compression ratio.
+	Flush() error
+}
+
+// compressWriter is the interface shared by all supported compression writers.
+type compressWriter interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+type channelCompressor struct {
+	version  []byte // version byte, empty for zlib
+	buf      bytes.Buffer
+	compress compressWriter
+}
+
+// NewChannelCompressor creates a ChannelCompressor for the given algorithm and
+// level. A level of 0 selects the default level of the algorithm.
+func NewChannelCompressor(algo CompressionAlgo, level int) (ChannelCompressor, error) {
+	if err := algo.CheckLevel(level); err != nil {
+		return nil, err
+	}
+	if level == 0 {
+		level = algo.DefaultLevel()
+	}
+	c := &channelCompressor{}
+	switch algo {
+	case Zlib:
+		w, err := zlib.NewWriterLevel(&c.buf, level)
+		if err != nil {
+			return nil, err
+		}
+		c.compress = w
+	case Brotli:
+		c.version = []byte{ChannelVersionBrotli}
+		c.compress = brotli.NewWriterLevel(&c.buf, level)
+	case Zstd:
+		c.version = []byte{ChannelVersionZstd}
+		w, err := zstd.NewWriter(&c.buf,
+			zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(level)),
+			zstd.WithEncoderConcurrency(1))
+		if err != nil {
+			return nil, err
+		}
+		c.compress = w
+	}
+	c.buf.Write(c.version)
+	return c, nil
+}
+
+func (c *channelCompressor) Write(p []byte) (int, error) {
+	return c.compress.Write(p)
+}
+
+func (c *channelCompressor) Close() error {
+	return c.compress.Close()
+}
+
+func (c *channelCompressor) Read(p []byte) (int, error) {
+	return c.buf.Read(p)
+}
+
+func (c *channelCompressor) Reset() {
+	c.buf.Reset()
+	c.buf.Write(c.version)
+	c.compress.Reset(&c.buf)
+}
+
+func (c *channelCompressor) Len() int {
+	return c.buf.Len()
+}
+
+func (c *channelCompressor) Flush() error {
+	return c.compress.Flush()
+}
+
+// channelDecompressor returns a reader of the decompressed channel data, which
+// detects the compression algorithm from the first byte of the channel.
+// Versioned channels are only accepted if allowVersioned is set.
+func channelDecompressor(r io.Reader, allowVersioned bool) (io.Reader, error) {
+	br := bufio.NewReader(r)
+	header, err := br.Peek(1)
+	if err != nil {
+		return nil, fmt.Errorf("reading channel version: %w", err)
+	}
+	version := header[0]
+	switch {
+	case version&0x0F == zlibCMDeflate || version&0x0F == zlibCMReserved:
+		return zlib.NewReader(br)
+	case version == ChannelVersionBrotli || version == ChannelVersionZstd:
+		if !allowVersioned {
+			return nil, fmt.Errorf("cannot accept channel with version %d before channel compression upgrade", version)
+		}
+		// skip version byte
+		if _, err := br.Discard(1); err != nil {
+			return nil, err
+		}
+		if version == ChannelVersionBrotli {
+			return brotli.NewReader(br), nil
+		}
+		zr, err := zstd.NewReader(br, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, err
+		}
+		return zr.IOReadCloser(), nil
+	default:
+		return nil, fmt.Errorf("unknown channel version: %d", version)
+	}
+}
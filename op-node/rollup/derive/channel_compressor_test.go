@@ -0,0 +1,66 @@
+package derive
+
+import (
+	"bytes"
+	"io"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestChannelCompressorRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(1234))
+	data := make([]byte, 10_000)
+	rng.Read(data[:5000]) // leave the second half compressible
+
+	for _, algo := range CompressionAlgos {
+		algo := algo
+		t.Run(algo.String(), func(t *testing.T) {
+			c, err := NewChannelCompressor(algo, 0)
+			require.NoError(t, err)
+
+			// compress twice, to also cover resetting the compressor
+			for i := 0; i < 2; i++ {
+				c.Reset()
+				_, err = c.Write(data)
+				require.NoError(t, err)
+				require.NoError(t, c.Close())
+				compressed, err := io.ReadAll(c)
+				require.NoError(t, err)
+				require.Less(t, len(compressed), len(data))
+
+				if algo.IsVersioned() {
+					_, err = channelDecompressor(bytes.NewReader(compressed), false)
+					require.ErrorContains(t, err, "before channel compression upgrade")
+				}
+				r, err := channelDecompressor(bytes.NewReader(compressed), true)
+				require.NoError(t, err)
+				decompressed, err := io.ReadAll(r)
+				require.NoError(t, err)
+				require.Equal(t, data, decompressed)
+			}
+		})
+	}
+}
+
+func TestChannelDecompressorUnknownVersion(t *testing.T) {
+	_, err := channelDecompressor(bytes.NewReader([]byte{0x03, 0x00}), true)
+	require.ErrorContains(t, err, "unknown channel version")
+	_, err = channelDecompressor(bytes.NewReader(nil), true)
+	require.Error(t, err)
+}
+
+func TestCompressionAlgoCheckLevel(t *testing.T) {
+	require.NoError(t, Zlib.CheckLevel(0))
+	require.NoError(t, Zlib.CheckLevel(9))
+	require.Error(t, Zlib.CheckLevel(10))
+	require.NoError(t, Brotli.CheckLevel(11))
+	require.Error(t, Brotli.CheckLevel(12))
+	require.NoError(t, Zstd.CheckLevel(22))
+	require.Error(t, Zstd.CheckLevel(-1))
+	require.Error(t, CompressionAlgo("lz4").CheckLevel(0))
+
+	_, err := NewChannelCompressor(Brotli, 12)
+	require.Error(t, err)
+}
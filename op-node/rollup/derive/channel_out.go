@@ -106,6 +106,13 @@ func (co *ChannelOut) AddBlock(block *types.Block) (uint64, error) {
 	return co.AddBatch(batch)
 }
 
+// AddSingularBatch adds a singular batch to the channel. The sequence number
+// is unused, it's only accepted for parity with SpanChannelOut.AddSingularBatch.
+// See AddBatch for the returned values.
+func (co *ChannelOut) AddSingularBatch(batch *SingularBatch, _ uint64) (uint64, error) {
+	return co.AddBatch(NewSingularBatchData(*batch))
+}
+
 // AddBatch adds a batch to the channel. It returns the RLP encoded byte size
 // and an error if there is a problem adding the batch. The only sentinel error
 // that it returns is ErrTooManyRLPBytes. If this error is returned, the channel
@@ -0,0 +1,68 @@
+package derive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/ethereum-optimism/optimism/op-service/dacommit"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+)
+
+// DAFetcher resolves DA commitments into the batcher data they commit to.
+type DAFetcher interface {
+	// GetInput returns the data of the commitment, or dacommit.ErrNotFound if it is unknown.
+	GetInput(ctx context.Context, comm dacommit.Commitment) ([]byte, error)
+}
+
+// DACommitmentSource wraps the batcher data of an L1 block, and resolves every DA commitment
+// into the data it commits to. Batcher data without a DA commitment is passed through unchanged.
+// The DA server is trusted to serve all committed data: derivation does not proceed past a
+// commitment until its data is available.
+type DACommitmentSource struct {
+	log     log.Logger
+	src     DataIter
+	fetcher DAFetcher
+
+	// comm is the commitment that is currently being resolved, if any
+	comm *dacommit.Commitment
+}
+
+func NewDACommitmentSource(log log.Logger, src DataIter, fetcher DAFetcher) *DACommitmentSource {
+	return &DACommitmentSource{
+		log:     log,
+		src:     src,
+		fetcher: fetcher,
+	}
+}
+
+// Next returns the next piece of batcher data. It returns a TemporaryError if a commitment
+// cannot be resolved yet, and resolves the same commitment again on the next call.
+func (s *DACommitmentSource) Next(ctx context.Context) (eth.Data, error) {
+	for s.comm == nil {
+		data, err := s.src.Next(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if len(data) == 0 || data[0] != dacommit.TxDataVersion {
+			return data, nil
+		}
+		comm, err := dacommit.DecodeCommitment(data)
+		if err != nil {
+			s.log.Warn("Skipping invalid DA commitment", "err", err)
+			continue
+		}
+		s.comm = &comm
+	}
+	data, err := s.fetcher.GetInput(ctx, *s.comm)
+	if errors.Is(err, dacommit.ErrNotFound) {
+		return nil, NewTemporaryError(fmt.Errorf("DA server does not have the data of commitment %s yet: %w", s.comm, err))
+	} else if err != nil {
+		return nil, NewTemporaryError(fmt.Errorf("failed to fetch data of DA commitment %s: %w", s.comm, err))
+	}
+	s.log.Debug("Resolved DA commitment", "commitment", s.comm, "size", len(data))
+	s.comm = nil
+	return data, nil
+}
@@ -0,0 +1,81 @@
+package derive
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-service/dacommit"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+)
+
+type fakeDAFetcher struct {
+	inputs map[dacommit.Commitment][]byte
+	err    error
+}
+
+func (f *fakeDAFetcher) GetInput(_ context.Context, comm dacommit.Commitment) ([]byte, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	data, ok := f.inputs[comm]
+	if !ok {
+		return nil, dacommit.ErrNotFound
+	}
+	return data, nil
+}
+
+type sliceDataIter []eth.Data
+
+func (it *sliceDataIter) Next(_ context.Context) (eth.Data, error) {
+	if len(*it) == 0 {
+		return nil, io.EOF
+	}
+	data := (*it)[0]
+	*it = (*it)[1:]
+	return data, nil
+}
+
+func TestDACommitmentSource(t *testing.T) {
+	ctx := context.Background()
+	committed := []byte{DerivationVersion0, 0x01, 0x02}
+	comm := dacommit.ComputeCommitment(committed)
+	missing := dacommit.ComputeCommitment([]byte{DerivationVersion0, 0x03})
+	plain := eth.Data{DerivationVersion0, 0x04}
+	src := &sliceDataIter{
+		comm.TxData(),
+		{dacommit.TxDataVersion, 0x05}, // invalid commitment, skipped
+		plain,
+		missing.TxData(),
+	}
+	fetcher := &fakeDAFetcher{inputs: map[dacommit.Commitment][]byte{comm: committed}}
+	ds := NewDACommitmentSource(testlog.Logger(t, log.LvlInfo), src, fetcher)
+
+	data, err := ds.Next(ctx)
+	require.NoError(t, err)
+	require.Equal(t, eth.Data(committed), data)
+
+	data, err = ds.Next(ctx)
+	require.NoError(t, err)
+	require.Equal(t, plain, data)
+
+	// the commitment is resolved again once the DA server has the data
+	_, err = ds.Next(ctx)
+	require.ErrorIs(t, err, ErrTemporary)
+	fetcher.err = errors.New("connection refused")
+	_, err = ds.Next(ctx)
+	require.ErrorIs(t, err, ErrTemporary)
+	fetcher.err = nil
+	fetcher.inputs[missing] = []byte{DerivationVersion0, 0x03}
+	data, err = ds.Next(ctx)
+	require.NoError(t, err)
+	require.Equal(t, eth.Data{DerivationVersion0, 0x03}, data)
+
+	_, err = ds.Next(ctx)
+	require.ErrorIs(t, err, io.EOF)
+}
@@ -93,6 +93,16 @@ type FinalityData struct {
 	L1Block eth.BlockID
 }
 
+// SafeHeadListener is notified of changes to the safe head, e.g. to record which L1 block each L2 block was
+// derived from.
+type SafeHeadListener interface {
+	// SafeHeadUpdated indicates that the safe head was fully derived from the L1 chain up to and including l1Block.
+	SafeHeadUpdated(newSafeHead eth.L2BlockRef, l1Block eth.BlockID) error
+	// SafeHeadReset indicates that the derivation pipeline was reset and the blocks after resetSafeHead
+	// will be derived again.
+	SafeHeadReset(resetSafeHead eth.L2BlockRef) error
+}
+
 // EngineQueue queues up payload attributes to consolidate or process with the provided Engine
 type EngineQueue struct {
 	log log.Logger
@@ -139,12 +149,16 @@ type EngineQueue struct {
 	l1Fetcher L1Fetcher
 
 	syncCfg *sync.Config
+
+	// safeHeadNotifs is notified of safe head changes, optional (may be nil)
+	safeHeadNotifs SafeHeadListener
 }
 
 var _ EngineControl = (*EngineQueue)(nil)
 
 // NewEngineQueue creates a new EngineQueue, which should be Reset(origin) before use.
-func NewEngineQueue(log log.Logger, cfg *rollup.Config, engine Engine, metrics Metrics, prev NextAttributesProvider, l1Fetcher L1Fetcher, syncCfg *sync.Config) *EngineQueue {
+// The safeHeadListener is optional and may be nil.
+func NewEngineQueue(log log.Logger, cfg *rollup.Config, engine Engine, metrics Metrics, prev NextAttributesProvider, l1Fetcher L1Fetcher, syncCfg *sync.Config, safeHeadListener SafeHeadListener) *EngineQueue {
 	return &EngineQueue{
 		log:            log,
 		cfg:            cfg,
@@ -155,6 +169,7 @@ func NewEngineQueue(log log.Logger, cfg *rollup.Config, engine Engine, metrics M
 		prev:           prev,
 		l1Fetcher:      l1Fetcher,
 		syncCfg:        syncCfg,
+		safeHeadNotifs: safeHeadListener,
 	}
 }
 
@@ -172,6 +187,15 @@ func (eq *EngineQueue) SetUnsafeHead(head eth.L2BlockRef) {
 	eq.metrics.RecordL2Ref("l2_unsafe", head)
 }
 
+// RewindUnsafeHead reorgs the unsafe chain back to the given block, which must not be older than the safe head.
+// The engine adopts the new forkchoice state with the next step.
+func (eq *EngineQueue) RewindUnsafeHead(head eth.L2BlockRef) {
+	eq.log.Warn("Rewinding unsafe head", "from", eq.unsafeHead, "to", head)
+	eq.SetUnsafeHead(head)
+	eq.SetEngineSyncTarget(head)
+	eq.needForkchoiceUpdate = true
+}
+
 func (eq *EngineQueue) SetEngineSyncTarget(head eth.L2BlockRef) {
 	eq.engineSyncTarget = head
 	eq.metrics.RecordL2Ref("l2_engineSyncTarget", head)
@@ -269,7 +293,10 @@ func (eq *EngineQueue) Step(ctx context.Context) error {
 		return err
 	}
 	eq.origin = newOrigin
-	eq.postProcessSafeL2() // make sure we track the last L2 safe head for every new L1 block
+	// make sure we track the last L2 safe head for every new L1 block
+	if err := eq.postProcessSafeL2(); err != nil {
+		return err
+	}
 	// try to finalize the L2 blocks we have synced so far (no-op if L1 finality is behind)
 	if err := eq.tryFinalizePastL2Blocks(ctx); err != nil {
 		return err
@@ -381,7 +408,15 @@ func (eq *EngineQueue) tryFinalizeL2() {
 
 // postProcessSafeL2 buffers the L1 block the safe head was fully derived from,
 // to finalize it once the L1 block, or later, finalizes.
-func (eq *EngineQueue) postProcessSafeL2() {
+// The safe head listener, if any, is notified of the L1 block the safe head was derived from.
+func (eq *EngineQueue) postProcessSafeL2() error {
+	if eq.safeHeadNotifs != nil {
+		if err := eq.safeHeadNotifs.SafeHeadUpdated(eq.safeHead, eq.origin.ID()); err != nil {
+			// The safe head was already updated in the engine, so reset the pipeline to roll back the safe head
+			// and derive the block again, which retries notifying the listener.
+			return NewResetError(fmt.Errorf("failed to notify safe head listener: %w", err))
+		}
+	}
 	// prune finality data if necessary
 	if len(eq.finalityData) >= finalityLookback {
 		eq.finalityData = append(eq.finalityData[:0], eq.finalityData[1:finalityLookback]...)
@@ -403,6 +438,7 @@ func (eq *EngineQueue) postProcessSafeL2() {
 			eq.log.Debug("updated finality-data", "last_l1", last.L1Block, "last_l2", last.L2Block)
 		}
 	}
+	return nil
 }
 
 func (eq *EngineQueue) logSyncProgress(reason string) {
@@ -609,7 +645,9 @@ func (eq *EngineQueue) consolidateNextSafeAttributes(ctx context.Context) error
 	eq.metrics.RecordL2Ref("l2_safe", ref)
 	// unsafe head stays the same, we did not reorg the chain.
 	eq.safeAttributes = nil
-	eq.postProcessSafeL2()
+	if err := eq.postProcessSafeL2(); err != nil {
+		return err
+	}
 	eq.logSyncProgress("reconciled with L1")
 
 	return nil
@@ -718,7 +756,9 @@ func (eq *EngineQueue) ConfirmPayload(ctx context.Context) (out *eth.ExecutionPa
 
 	if eq.buildingSafe {
 		eq.safeHead = ref
-		eq.postProcessSafeL2()
+		if err := eq.postProcessSafeL2(); err != nil {
+			return nil, BlockInsertPayloadErr, err
+		}
 		eq.metrics.RecordL2Ref("l2_safe", ref)
 	}
 	eq.resetBuildingState()
@@ -793,6 +833,11 @@ func (eq *EngineQueue) Reset(ctx context.Context, _ eth.L1BlockRef, _ eth.System
 	if err != nil {
 		return NewTemporaryError(fmt.Errorf("failed to fetch L1 config of L2 block %s: %w", pipelineL2.ID(), err))
 	}
+	if eq.safeHeadNotifs != nil {
+		if err := eq.safeHeadNotifs.SafeHeadReset(safe); err != nil {
+			return NewTemporaryError(fmt.Errorf("failed to reset safe head listener: %w", err))
+		}
+	}
 	eq.log.Debug("Reset engine queue", "safeHead", safe, "unsafe", unsafe, "safe_timestamp", safe.Time, "unsafe_timestamp", unsafe.Time, "l1Origin", l1Origin)
 	eq.unsafeHead = unsafe
 	eq.engineSyncTarget = unsafe
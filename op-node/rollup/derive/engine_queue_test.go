@@ -247,8 +247,10 @@ func TestEngineQueue_Finalize(t *testing.T) {
 
 	prev := &fakeAttributesQueue{}
 
-	eq := NewEngineQueue(logger, cfg, eng, metrics, prev, l1F, &sync.Config{})
+	safeHeadListener := &stubSafeHeadListener{}
+	eq := NewEngineQueue(logger, cfg, eng, metrics, prev, l1F, &sync.Config{}, safeHeadListener)
 	require.ErrorIs(t, eq.Reset(context.Background(), eth.L1BlockRef{}, eth.SystemConfig{}), io.EOF)
+	require.Equal(t, []eth.L2BlockRef{refB1}, safeHeadListener.resets, "should notify listener of reset safe head")
 
 	require.Equal(t, refB1, eq.SafeL2Head(), "L2 reset should go back to sequence window ago: blocks with origin E and D are not safe until we reconcile, C is extra, and B1 is the end we look for")
 	require.Equal(t, refB, eq.Origin(), "Expecting to be set back derivation L1 progress to B")
@@ -258,13 +260,15 @@ func TestEngineQueue_Finalize(t *testing.T) {
 	eq.origin = refD
 	prev.origin = refD
 	eq.safeHead = refC1
-	eq.postProcessSafeL2()
+	require.NoError(t, eq.postProcessSafeL2())
 
 	// now say D0 was included in E and became the new safe head
 	eq.origin = refE
 	prev.origin = refE
 	eq.safeHead = refD0
-	eq.postProcessSafeL2()
+	require.NoError(t, eq.postProcessSafeL2())
+	require.Equal(t, []safeHeadUpdate{{refC1, refD.ID()}, {refD0, refE.ID()}}, safeHeadListener.updates,
+		"should notify listener of the L1 block each safe head was derived from")
 
 	// let's finalize D (current L1), from which we fully derived C1 (it was safe head), but not D0 (included in E)
 	eq.Finalize(refD)
@@ -481,7 +485,7 @@ func TestEngineQueue_ResetWhenUnsafeOriginNotCanonical(t *testing.T) {
 
 	prev := &fakeAttributesQueue{origin: refE}
 
-	eq := NewEngineQueue(logger, cfg, eng, metrics, prev, l1F, &sync.Config{})
+	eq := NewEngineQueue(logger, cfg, eng, metrics, prev, l1F, &sync.Config{}, nil)
 	require.ErrorIs(t, eq.Reset(context.Background(), eth.L1BlockRef{}, eth.SystemConfig{}), io.EOF)
 
 	require.Equal(t, refB1, eq.SafeL2Head(), "L2 reset should go back to sequence window ago: blocks with origin E and D are not safe until we reconcile, C is extra, and B1 is the end we look for")
@@ -812,7 +816,7 @@ func TestVerifyNewL1Origin(t *testing.T) {
 			}, nil)
 
 			prev := &fakeAttributesQueue{origin: refE}
-			eq := NewEngineQueue(logger, cfg, eng, metrics, prev, l1F, &sync.Config{})
+			eq := NewEngineQueue(logger, cfg, eng, metrics, prev, l1F, &sync.Config{}, nil)
 			require.ErrorIs(t, eq.Reset(context.Background(), eth.L1BlockRef{}, eth.SystemConfig{}), io.EOF)
 
 			require.Equal(t, refB1, eq.SafeL2Head(), "L2 reset should go back to sequence window ago: blocks with origin E and D are not safe until we reconcile, C is extra, and B1 is the end we look for")
@@ -910,7 +914,7 @@ func TestBlockBuildingRace(t *testing.T) {
 	}
 
 	prev := &fakeAttributesQueue{origin: refA, attrs: attrs}
-	eq := NewEngineQueue(logger, cfg, eng, metrics, prev, l1F, &sync.Config{})
+	eq := NewEngineQueue(logger, cfg, eng, metrics, prev, l1F, &sync.Config{}, nil)
 	require.ErrorIs(t, eq.Reset(context.Background(), eth.L1BlockRef{}, eth.SystemConfig{}), io.EOF)
 
 	id := eth.PayloadID{0xff}
@@ -1080,7 +1084,7 @@ func TestResetLoop(t *testing.T) {
 
 	prev := &fakeAttributesQueue{origin: refA, attrs: attrs}
 
-	eq := NewEngineQueue(logger, cfg, eng, metrics.NoopMetrics, prev, l1F, &sync.Config{})
+	eq := NewEngineQueue(logger, cfg, eng, metrics.NoopMetrics, prev, l1F, &sync.Config{}, nil)
 	eq.unsafeHead = refA2
 	eq.engineSyncTarget = refA2
 	eq.safeHead = refA1
@@ -1178,7 +1182,7 @@ func TestEngineQueue_StepPopOlderUnsafe(t *testing.T) {
 
 	prev := &fakeAttributesQueue{origin: refA}
 
-	eq := NewEngineQueue(logger, cfg, eng, metrics.NoopMetrics, prev, l1F, &sync.Config{})
+	eq := NewEngineQueue(logger, cfg, eng, metrics.NoopMetrics, prev, l1F, &sync.Config{}, nil)
 	eq.unsafeHead = refA2
 	eq.safeHead = refA0
 	eq.finalized = refA0
@@ -1194,3 +1198,23 @@ func TestEngineQueue_StepPopOlderUnsafe(t *testing.T) {
 	l1F.AssertExpectations(t)
 	eng.AssertExpectations(t)
 }
+
+type safeHeadUpdate struct {
+	safeHead eth.L2BlockRef
+	l1Block  eth.BlockID
+}
+
+type stubSafeHeadListener struct {
+	updates []safeHeadUpdate
+	resets  []eth.L2BlockRef
+}
+
+func (s *stubSafeHeadListener) SafeHeadUpdated(newSafeHead eth.L2BlockRef, l1Block eth.BlockID) error {
+	s.updates = append(s.updates, safeHeadUpdate{newSafeHead, l1Block})
+	return nil
+}
+
+func (s *stubSafeHeadListener) SafeHeadReset(resetSafeHead eth.L2BlockRef) error {
+	s.resets = append(s.resets, resetSafeHead)
+	return nil
+}
@@ -66,7 +66,7 @@ func (bp *spanBatchPayload) decodeOriginBits(r *bytes.Reader) error {
 	originBits := new(big.Int)
 	for i := 0; i < int(bp.blockCount); i += 8 {
 		end := i + 8
-		if end < int(bp.blockCount) {
+		if end > int(bp.blockCount) {
 			end = int(bp.blockCount)
 		}
 		bits := originBitBuffer[i/8]
@@ -272,7 +272,7 @@ func (bp *spanBatchPayload) encodeOriginBits(w io.Writer) error {
 	originBitBuffer := make([]byte, originBitBufferLen)
 	for i := 0; i < int(bp.blockCount); i += 8 {
 		end := i + 8
-		if end < int(bp.blockCount) {
+		if end > int(bp.blockCount) {
 			end = int(bp.blockCount)
 		}
 		var bits uint = 0
@@ -359,9 +359,9 @@ func (b *RawSpanBatch) encodeBytes() ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
-// derive converts RawSpanBatch into SpanBatch, which has a list of spanBatchElement.
+// Derive converts RawSpanBatch into SpanBatch, which has a list of spanBatchElement.
 // We need chain config constants to derive values for making payload attributes.
-func (b *RawSpanBatch) derive(blockTime, genesisTimestamp uint64, chainID *big.Int) (*SpanBatch, error) {
+func (b *RawSpanBatch) Derive(blockTime, genesisTimestamp uint64, chainID *big.Int) (*SpanBatch, error) {
 	blockOriginNums := make([]uint64, b.blockCount)
 	l1OriginBlockNumber := b.l1OriginNum
 	for i := int(b.blockCount) - 1; i >= 0; i-- {
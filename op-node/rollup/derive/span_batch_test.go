@@ -286,7 +286,7 @@ func TestSpanBatchDerive(t *testing.T) {
 		rawSpanBatch, err := spanBatch.ToRawSpanBatch(originChangedBit, genesisTimeStamp, chainID)
 		assert.NoError(t, err)
 
-		spanBatchDerived, err := rawSpanBatch.derive(l2BlockTime, genesisTimeStamp, chainID)
+		spanBatchDerived, err := rawSpanBatch.Derive(l2BlockTime, genesisTimeStamp, chainID)
 		assert.NoError(t, err)
 
 		blockCount := len(singularBatches)
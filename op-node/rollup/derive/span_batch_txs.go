@@ -45,7 +45,7 @@ func (btx *spanBatchTxs) encodeContractCreationBits(w io.Writer) error {
 	contractCreationBitBuffer := make([]byte, contractCreationBitBufferLen)
 	for i := 0; i < int(btx.totalBlockTxCount); i += 8 {
 		end := i + 8
-		if end < int(btx.totalBlockTxCount) {
+		if end > int(btx.totalBlockTxCount) {
 			end = int(btx.totalBlockTxCount)
 		}
 		var bits uint = 0
@@ -78,7 +78,7 @@ func (btx *spanBatchTxs) decodeContractCreationBits(r *bytes.Reader) error {
 	contractCreationBits := new(big.Int)
 	for i := 0; i < int(btx.totalBlockTxCount); i += 8 {
 		end := i + 8
-		if end < int(btx.totalBlockTxCount) {
+		if end > int(btx.totalBlockTxCount) {
 			end = int(btx.totalBlockTxCount)
 		}
 		bits := contractCreationBitBuffer[i/8]
@@ -114,7 +114,7 @@ func (btx *spanBatchTxs) encodeYParityBits(w io.Writer) error {
 	yParityBitBuffer := make([]byte, yParityBitBufferLen)
 	for i := 0; i < int(btx.totalBlockTxCount); i += 8 {
 		end := i + 8
-		if end < int(btx.totalBlockTxCount) {
+		if end > int(btx.totalBlockTxCount) {
 			end = int(btx.totalBlockTxCount)
 		}
 		var bits uint = 0
@@ -201,7 +201,7 @@ func (btx *spanBatchTxs) decodeYParityBits(r *bytes.Reader) error {
 	yParityBits := new(big.Int)
 	for i := 0; i < int(btx.totalBlockTxCount); i += 8 {
 		end := i + 8
-		if end < int(btx.totalBlockTxCount) {
+		if end > int(btx.totalBlockTxCount) {
 			end = int(btx.totalBlockTxCount)
 		}
 		bits := yParityBitBuffer[i/8]
@@ -418,22 +418,36 @@ func convertVToYParity(v uint64, txType int) uint {
 }
 
 func newSpanBatchTxs(txs [][]byte, chainID *big.Int) (*spanBatchTxs, error) {
-	totalBlockTxCount := uint64(len(txs))
-	var txSigs []spanBatchSignature
-	var txTos []common.Address
-	var txNonces []uint64
-	var txGases []uint64
-	var txDatas []hexutil.Bytes
-	var txTypes []int
-	contractCreationBits := new(big.Int)
-	yParityBits := new(big.Int)
-	for idx := 0; idx < int(totalBlockTxCount); idx++ {
+	btx := &spanBatchTxs{
+		contractCreationBits: new(big.Int),
+		yParityBits:          new(big.Int),
+	}
+	if err := btx.appendTxs(txs, chainID); err != nil {
+		return nil, err
+	}
+	return btx, nil
+}
+
+// appendTxs decodes the given transactions and appends them to btx.
+// btx is not modified if any of the transactions is invalid.
+func (btx *spanBatchTxs) appendTxs(txs [][]byte, chainID *big.Int) error {
+	totalBlockTxCount := btx.totalBlockTxCount
+	txSigs := btx.txSigs
+	txTos := btx.txTos
+	txNonces := btx.txNonces
+	txGases := btx.txGases
+	txDatas := btx.txDatas
+	txTypes := btx.txTypes
+	contractCreationBits := new(big.Int).Set(btx.contractCreationBits)
+	yParityBits := new(big.Int).Set(btx.yParityBits)
+	for _, txBytes := range txs {
+		idx := int(totalBlockTxCount)
 		var tx types.Transaction
-		if err := tx.UnmarshalBinary(txs[idx]); err != nil {
-			return nil, errors.New("failed to decode tx")
+		if err := tx.UnmarshalBinary(txBytes); err != nil {
+			return errors.New("failed to decode tx")
 		}
 		if tx.Protected() && tx.ChainId().Cmp(chainID) != 0 {
-			return nil, fmt.Errorf("protected tx has chain ID %d, but expected chain ID %d", tx.ChainId(), chainID)
+			return fmt.Errorf("protected tx has chain ID %d, but expected chain ID %d", tx.ChainId(), chainID)
 		}
 		var txSig spanBatchSignature
 		v, r, s := tx.RawSignatureValues()
@@ -455,24 +469,24 @@ func newSpanBatchTxs(txs [][]byte, chainID *big.Int) (*spanBatchTxs, error) {
 		txGases = append(txGases, tx.Gas())
 		stx, err := newSpanBatchTx(tx)
 		if err != nil {
-			return nil, err
+			return err
 		}
 		txData, err := stx.MarshalBinary()
 		if err != nil {
-			return nil, err
+			return err
 		}
 		txDatas = append(txDatas, txData)
 		txTypes = append(txTypes, int(tx.Type()))
+		totalBlockTxCount++
 	}
-	return &spanBatchTxs{
-		totalBlockTxCount:    totalBlockTxCount,
-		contractCreationBits: contractCreationBits,
-		yParityBits:          yParityBits,
-		txSigs:               txSigs,
-		txNonces:             txNonces,
-		txGases:              txGases,
-		txTos:                txTos,
-		txDatas:              txDatas,
-		txTypes:              txTypes,
-	}, nil
+	btx.totalBlockTxCount = totalBlockTxCount
+	btx.contractCreationBits = contractCreationBits
+	btx.yParityBits = yParityBits
+	btx.txSigs = txSigs
+	btx.txNonces = txNonces
+	btx.txGases = txGases
+	btx.txTos = txTos
+	btx.txDatas = txDatas
+	btx.txTypes = txTypes
+	return nil
 }
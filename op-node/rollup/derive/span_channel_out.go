@@ -0,0 +1,376 @@
+package derive
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// SpanChannelOut is a channel out that accumulates all blocks of the channel
+// into a single span batch.
+//
+// The encoding of a span batch changes as a whole with every added block, so
+// the span batch is kept in its raw form and only the new block is encoded
+// when it is added. The size of the encoding is tracked as blocks are added,
+// and the span batch is only RLP encoded and compressed once the compressed
+// data may reach the target size of the channel. No frames can be output
+// before the channel is closed.
+type SpanChannelOut struct {
+	id ChannelID
+	// Frame ID of the next frame to emit. Increment after emitting
+	frame uint64
+	// rlpLength is the uncompressed size of the channel. Must be less than MAX_RLP_BYTES_PER_CHANNEL
+	rlpLength int
+
+	// Compressor stage. The full span batch is written to it when it is compressed.
+	compress Compressor
+	// target is the compressed size at which the channel is considered full
+	target uint64
+	// compressedRLPLength is the RLP encoded size of the span batch when it was last compressed
+	compressedRLPLength int
+
+	genesisTimestamp uint64
+	chainID          *big.Int
+	// batches are all batches of the span batch, to rebuild it without the last batch
+	batches []*SingularBatch
+	// raw is the span batch of the channel, nil until the first batch is added
+	raw *RawSpanBatch
+	// txsLength is the encoded size of all transactions of the span batch,
+	// excluding the bit lists that are sized by the transaction count
+	txsLength int
+	// blockTxCountsLength is the encoded size of the transaction counts of the blocks
+	blockTxCountsLength int
+
+	// fullErr is set once a batch didn't fit into the channel anymore
+	fullErr error
+	closed  bool
+}
+
+// NewSpanChannelOut creates a channel out that accumulates the added blocks
+// into a single span batch. The span batch is only compressed once the
+// compressed data may reach the target size, which should be the size at
+// which the compressor becomes full.
+func NewSpanChannelOut(compress Compressor, target uint64, genesisTimestamp uint64, chainID *big.Int) (*SpanChannelOut, error) {
+	c := &SpanChannelOut{
+		compress:         compress,
+		target:           target,
+		genesisTimestamp: genesisTimestamp,
+		chainID:          chainID,
+	}
+	if _, err := rand.Read(c.id[:]); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (co *SpanChannelOut) ID() ChannelID {
+	return co.id
+}
+
+func (co *SpanChannelOut) Reset() error {
+	co.frame = 0
+	co.compress.Reset()
+	co.resetSpanBatch()
+	co.fullErr = nil
+	co.closed = false
+	_, err := rand.Read(co.id[:])
+	return err
+}
+
+func (co *SpanChannelOut) resetSpanBatch() {
+	co.rlpLength = 0
+	co.compressedRLPLength = 0
+	co.batches = nil
+	co.raw = nil
+	co.txsLength = 0
+	co.blockTxCountsLength = 0
+}
+
+// AddBlock adds a block to the span batch of the channel. See AddSingularBatch
+// for the returned values.
+func (co *SpanChannelOut) AddBlock(block *types.Block) (uint64, error) {
+	if co.closed {
+		return 0, errors.New("already closed")
+	}
+
+	batch, l1Info, err := BlockToBatch(block)
+	if err != nil {
+		return 0, err
+	}
+	return co.AddSingularBatch(&batch.SingularBatch, l1Info.SequenceNumber)
+}
+
+// AddSingularBatch adds a batch to the span batch of the channel. The sequence
+// number of the batch's block within its epoch is used to determine whether
+// the first block of the span changed the L1 origin.
+//
+// It returns the RLP encoded byte size of the whole span batch. It returns
+// ErrTooManyRLPBytes or CompressorFullErr if the batch doesn't fit into the
+// channel anymore. The batch is not added in this case, the channel should be
+// closed and a new one should be made.
+//
+// The first batch is always added, even if it alone fills the compressor.
+func (co *SpanChannelOut) AddSingularBatch(batch *SingularBatch, seqNum uint64) (uint64, error) {
+	if co.closed {
+		return 0, errors.New("already closed")
+	}
+	if co.fullErr != nil {
+		return 0, co.fullErr
+	}
+
+	txs := make([][]byte, 0, len(batch.Transactions))
+	for _, tx := range batch.Transactions {
+		txs = append(txs, tx)
+	}
+	var stxs *spanBatchTxs
+	if co.raw == nil {
+		var err error
+		if stxs, err = newSpanBatchTxs(txs, co.chainID); err != nil {
+			return 0, fmt.Errorf("failed to convert batch into span batch txs: %w", err)
+		}
+	} else {
+		stxs = co.raw.txs
+		// the span batch txs are only modified if all transactions are valid
+		if err := stxs.appendTxs(txs, co.chainID); err != nil {
+			return 0, fmt.Errorf("failed to convert batch into span batch txs: %w", err)
+		}
+	}
+	firstTx := len(stxs.txSigs) - len(txs)
+	firstTo := len(stxs.txTos)
+	for i := firstTx; i < len(stxs.txSigs); i++ {
+		if stxs.contractCreationBits.Bit(i) == 0 {
+			firstTo--
+		}
+	}
+	txsLength := co.txsLength + txsEncodedLength(stxs, firstTx, firstTo)
+	blockTxCountsLength := co.blockTxCountsLength + uvarintLength(uint64(len(txs)))
+
+	if co.raw == nil {
+		// The first block of an epoch changes the L1 origin of its parent.
+		originChangedBit := uint(0)
+		if seqNum == 0 {
+			originChangedBit = 1
+		}
+		co.raw = &RawSpanBatch{
+			spanBatchPrefix: spanBatchPrefix{
+				relTimestamp: batch.Timestamp - co.genesisTimestamp,
+				parentCheck:  append([]byte{}, batch.ParentHash.Bytes()[:20]...),
+			},
+			spanBatchPayload: spanBatchPayload{
+				originBits: new(big.Int).SetBit(new(big.Int), 0, originChangedBit),
+				txs:        stxs,
+			},
+		}
+	} else if uint64(batch.EpochNum) > co.raw.l1OriginNum {
+		co.raw.originBits.SetBit(co.raw.originBits, int(co.raw.blockCount), 1)
+	}
+	co.raw.l1OriginNum = uint64(batch.EpochNum)
+	co.raw.l1OriginCheck = append([]byte{}, batch.EpochHash.Bytes()[:20]...)
+	co.raw.blockCount++
+	co.raw.blockTxCounts = append(co.raw.blockTxCounts, uint64(len(txs)))
+	co.batches = append(co.batches, batch)
+	co.txsLength = txsLength
+	co.blockTxCountsLength = blockTxCountsLength
+
+	rlpLength := co.encodedLength()
+	if rlpLength > MaxRLPBytesPerChannel {
+		err := fmt.Errorf("could not add %d bytes to channel of %d bytes, max is %d. err: %w",
+			rlpLength-co.rlpLength, co.rlpLength, MaxRLPBytesPerChannel, ErrTooManyRLPBytes)
+		if len(co.batches) == 1 {
+			// Nothing was added to the channel yet, so it doesn't become full.
+			co.resetSpanBatch()
+			return 0, err
+		}
+		co.fullErr = err
+		return 0, co.revertLastBatch(err)
+	}
+	co.rlpLength = rlpLength
+
+	// If the compressed data plus the growth of the span batch since it was last
+	// compressed is below the target, the compressor can't be full yet.
+	if uint64(co.compress.Len()+co.rlpLength-co.compressedRLPLength) < co.target {
+		return uint64(co.rlpLength), nil
+	}
+	if err := co.compressRaw(); err != nil {
+		return 0, err
+	}
+	if co.compress.FullErr() != nil && len(co.batches) > 1 {
+		// The batch overflowed the compressor, so compress the span batch without it.
+		// No more batches can be added after this.
+		co.fullErr = CompressorFullErr
+		return 0, co.revertLastBatch(co.fullErr)
+	}
+	return uint64(co.rlpLength), nil
+}
+
+// revertLastBatch rebuilds and compresses the span batch without the last
+// batch, after it didn't fit into the channel. It returns fullErr, unless the
+// span batch can't be rebuilt.
+func (co *SpanChannelOut) revertLastBatch(fullErr error) error {
+	co.batches = co.batches[:len(co.batches)-1]
+	originChangedBit := co.raw.originBits.Bit(0)
+	raw, err := NewSpanBatch(co.batches).ToRawSpanBatch(originChangedBit, co.genesisTimestamp, co.chainID)
+	if err != nil {
+		return fmt.Errorf("failed to rebuild span batch: %w", err)
+	}
+	co.raw = raw
+	if err := co.compressRaw(); err != nil {
+		return err
+	}
+	return fullErr
+}
+
+// compressRaw encodes the span batch and replaces the compressed data with its compression.
+func (co *SpanChannelOut) compressRaw() error {
+	var buf bytes.Buffer
+	if err := rlp.Encode(&buf, NewSpanBatchData(*co.raw)); err != nil {
+		return err
+	}
+	co.rlpLength = buf.Len()
+	co.compressedRLPLength = buf.Len()
+	co.compress.Reset()
+	// avoid using io.Copy here, because we need all or nothing
+	if _, err := co.compress.Write(buf.Bytes()); err != nil && !errors.Is(err, CompressorFullErr) {
+		return fmt.Errorf("failed to compress span batch: %w", err)
+	}
+	return nil
+}
+
+// encodedLength returns the size of the RLP encoding of the span batch.
+func (co *SpanChannelOut) encodedLength() int {
+	raw := co.raw
+	txCount := raw.txs.totalBlockTxCount
+	length := uvarintLength(raw.relTimestamp) + uvarintLength(raw.l1OriginNum) + 20 + 20 +
+		uvarintLength(raw.blockCount) + bitListLength(raw.blockCount) + co.blockTxCountsLength +
+		2*bitListLength(txCount) + co.txsLength
+	// batch type byte
+	length++
+	// RLP string header
+	if length > 55 {
+		return length + 1 + bigEndianLength(uint64(length))
+	}
+	return length + 1
+}
+
+// txsEncodedLength returns the encoded size of the transactions of btx,
+// starting at the transaction index firstTx and the to-address index firstTo,
+// excluding the bit lists.
+func txsEncodedLength(btx *spanBatchTxs, firstTx int, firstTo int) int {
+	length := 64*(len(btx.txSigs)-firstTx) + 20*(len(btx.txTos)-firstTo)
+	for i := firstTx; i < len(btx.txSigs); i++ {
+		length += len(btx.txDatas[i]) + uvarintLength(btx.txNonces[i]) + uvarintLength(btx.txGases[i])
+	}
+	return length
+}
+
+// bitListLength returns the size of a bit list of n bits, right-padded to a multiple of 8 bits.
+func bitListLength(n uint64) int {
+	return int((n + 7) / 8)
+}
+
+func uvarintLength(v uint64) int {
+	var buf [binary.MaxVarintLen64]byte
+	return binary.PutUvarint(buf[:], v)
+}
+
+// bigEndianLength returns the number of bytes of the big-endian encoding of v without leading zeros.
+func bigEndianLength(v uint64) int {
+	n := 0
+	for ; v > 0; v >>= 8 {
+		n++
+	}
+	return n
+}
+
+// InputBytes returns the RLP-encoded size of the span batch.
+func (co *SpanChannelOut) InputBytes() int {
+	return co.rlpLength
+}
+
+// ReadyBytes returns the number of bytes that the channel out can immediately output into a frame.
+// Since the compressed data is replaced whenever the span batch is compressed, it is always 0 until the channel is closed.
+func (co *SpanChannelOut) ReadyBytes() int {
+	if !co.closed {
+		return 0
+	}
+	return co.compress.Len()
+}
+
+// Flush is a no-op, since no data can be output before the channel is closed.
+func (co *SpanChannelOut) Flush() error {
+	return nil
+}
+
+func (co *SpanChannelOut) FullErr() error {
+	if co.fullErr != nil {
+		return co.fullErr
+	}
+	return co.compress.FullErr()
+}
+
+func (co *SpanChannelOut) Close() error {
+	if co.closed {
+		return errors.New("already closed")
+	}
+	co.closed = true
+	// compress the blocks that were added since the span batch was last compressed
+	if co.raw != nil && co.compressedRLPLength != co.rlpLength {
+		if err := co.compressRaw(); err != nil {
+			return err
+		}
+	}
+	return co.compress.Close()
+}
+
+// OutputFrame writes a frame to w with a given max size and returns the frame
+// number. The channel must be closed before frames can be output.
+// Returns an error if the `maxSize` < FrameV0OverHeadSize.
+// Returns io.EOF when there are no more frames.
+// Returns nil if there is still more buffered data.
+// Returns an error if it ran into an error during processing.
+func (co *SpanChannelOut) OutputFrame(w *bytes.Buffer, maxSize uint64) (uint16, error) {
+	if !co.closed {
+		return 0, errors.New("span channel must be closed before outputting frames")
+	}
+	f := Frame{
+		ID:          co.id,
+		FrameNumber: uint16(co.frame),
+	}
+
+	// Check that the maxSize is large enough for the frame overhead size.
+	if maxSize < FrameV0OverHeadSize {
+		return 0, ErrMaxFrameSizeTooSmall
+	}
+
+	// Copy data from the local buffer into the frame data buffer
+	maxDataSize := maxSize - FrameV0OverHeadSize
+	if maxDataSize >= uint64(co.compress.Len()) {
+		maxDataSize = uint64(co.compress.Len())
+		// If we will not spill past the current frame, mark it is the final frame of the channel.
+		f.IsLast = true
+	}
+	f.Data = make([]byte, maxDataSize)
+
+	if _, err := io.ReadFull(co.compress, f.Data); err != nil {
+		return 0, err
+	}
+
+	if err := f.MarshalBinary(w); err != nil {
+		return 0, err
+	}
+
+	co.frame += 1
+	fn := f.FrameNumber
+	if f.IsLast {
+		return fn, io.EOF
+	} else {
+		return fn, nil
+	}
+}
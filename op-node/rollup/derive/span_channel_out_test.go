@@ -0,0 +1,176 @@
+package derive
+
+import (
+	"bytes"
+	"io"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/require"
+)
+
+// limitCompressor is a nonCompressor that reports to be full once it holds
+// more than limit bytes.
+type limitCompressor struct {
+	nonCompressor
+	limit int
+}
+
+func (c *limitCompressor) FullErr() error {
+	if c.Len() > c.limit {
+		return CompressorFullErr
+	}
+	return nil
+}
+
+func readSpanChannel(t *testing.T, co *SpanChannelOut) []byte {
+	var data []byte
+	for {
+		var buf bytes.Buffer
+		_, err := co.OutputFrame(&buf, 1000)
+		frames, perr := ParseFrames(append([]byte{DerivationVersion0}, buf.Bytes()...))
+		require.NoError(t, perr)
+		require.Len(t, frames, 1)
+		require.Equal(t, co.ID(), frames[0].ID)
+		data = append(data, frames[0].Data...)
+		if err == io.EOF {
+			require.True(t, frames[0].IsLast)
+			return data
+		}
+		require.NoError(t, err)
+	}
+}
+
+func TestSpanChannelOutRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(0x5a11))
+	chainID := big.NewInt(rng.Int63n(1000))
+	batches := RandomValidConsecutiveSingularBatches(rng, chainID)
+	genesisTimestamp := batches[0].Timestamp - 256
+
+	co, err := NewSpanChannelOut(&nonCompressor{}, MaxRLPBytesPerChannel, genesisTimestamp, chainID)
+	require.NoError(t, err)
+	for i, batch := range batches {
+		_, err := co.AddSingularBatch(batch, uint64(i))
+		require.NoError(t, err)
+		require.Zero(t, co.ReadyBytes(), "no data must be ready before closing")
+	}
+	require.NoError(t, co.Close())
+	require.Equal(t, co.InputBytes(), co.ReadyBytes())
+
+	stream := rlp.NewStream(bytes.NewReader(readSpanChannel(t, co)), MaxRLPBytesPerChannel)
+	var batchData BatchData
+	require.NoError(t, stream.Decode(&batchData))
+	require.Equal(t, SpanBatchType, batchData.BatchType)
+	require.ErrorIs(t, stream.Decode(&BatchData{}), io.EOF, "channel must hold a single span batch")
+
+	spanBatch, err := batchData.RawSpanBatch.Derive(2, genesisTimestamp, chainID)
+	require.NoError(t, err)
+	require.Equal(t, len(batches), spanBatch.GetBlockCount())
+	for i, batch := range batches {
+		require.Equal(t, batch.Timestamp, spanBatch.GetBlockTimestamp(i))
+		require.Equal(t, uint64(batch.EpochNum), spanBatch.GetBlockEpochNum(i))
+		require.Equal(t, batch.Transactions, spanBatch.GetBlockTransactions(i))
+	}
+	require.True(t, spanBatch.CheckParentHash(batches[0].ParentHash))
+	require.True(t, spanBatch.CheckOriginHash(batches[len(batches)-1].EpochHash))
+}
+
+// TestSpanChannelOutEncodedLength asserts that the size of the span batch, which is tracked
+// as batches are added, matches the size of the encoding of the span batch of all added batches.
+func TestSpanChannelOutEncodedLength(t *testing.T) {
+	rng := rand.New(rand.NewSource(0x5a15))
+	chainID := big.NewInt(rng.Int63n(1000))
+	batches := RandomValidConsecutiveSingularBatches(rng, chainID)
+	// add contract creations and empty blocks
+	batches[0].Transactions = append(batches[0].Transactions, RandomSingularBatch(rng, 1, chainID).Transactions...)
+	batches[len(batches)-1].Transactions = nil
+	genesisTimestamp := batches[0].Timestamp - 256
+
+	co, err := NewSpanChannelOut(&nonCompressor{}, MaxRLPBytesPerChannel, genesisTimestamp, chainID)
+	require.NoError(t, err)
+	for i, batch := range batches {
+		size, err := co.AddSingularBatch(batch, uint64(i))
+		require.NoError(t, err)
+		raw, err := NewSpanBatch(batches[:i+1]).ToRawSpanBatch(1, genesisTimestamp, chainID)
+		require.NoError(t, err)
+		data, err := rlp.EncodeToBytes(NewSpanBatchData(*raw))
+		require.NoError(t, err)
+		require.Equal(t, uint64(len(data)), size, "size after %d batches", i+1)
+		require.Equal(t, *raw, *co.raw)
+	}
+	require.Zero(t, co.compress.Len(), "span batch must not be compressed below the target")
+	require.NoError(t, co.Close())
+	require.Equal(t, co.InputBytes(), co.ReadyBytes())
+}
+
+func TestSpanChannelOutOriginChangedBit(t *testing.T) {
+	rng := rand.New(rand.NewSource(0x5a12))
+	chainID := big.NewInt(rng.Int63n(1000))
+	batch := RandomSingularBatch(rng, 1, chainID)
+
+	for seqNum, expected := range []uint{1, 0} {
+		co, err := NewSpanChannelOut(&nonCompressor{}, 0, 0, chainID)
+		require.NoError(t, err)
+		_, err = co.AddSingularBatch(batch, uint64(seqNum))
+		require.NoError(t, err)
+		require.Equal(t, expected, co.raw.originBits.Bit(0))
+	}
+}
+
+func TestSpanChannelOutCompressorFull(t *testing.T) {
+	rng := rand.New(rand.NewSource(0x5a13))
+	chainID := big.NewInt(rng.Int63n(1000))
+	batches := RandomValidConsecutiveSingularBatches(rng, chainID)
+	for len(batches) < 4 {
+		batches = RandomValidConsecutiveSingularBatches(rng, chainID)
+	}
+
+	// Find the size of the span batch of the first two batches, so the third one overflows.
+	co, err := NewSpanChannelOut(&nonCompressor{}, 0, 0, chainID)
+	require.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		_, err := co.AddSingularBatch(batches[i], uint64(i))
+		require.NoError(t, err)
+	}
+	limit := co.InputBytes()
+
+	co, err = NewSpanChannelOut(&limitCompressor{limit: limit}, uint64(limit), 0, chainID)
+	require.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		_, err := co.AddSingularBatch(batches[i], uint64(i))
+		require.NoError(t, err)
+	}
+	require.NoError(t, co.FullErr())
+
+	_, err = co.AddSingularBatch(batches[2], 2)
+	require.ErrorIs(t, err, CompressorFullErr)
+	require.ErrorIs(t, co.FullErr(), CompressorFullErr)
+	require.Equal(t, limit, co.InputBytes(), "overflowing batch must be removed")
+	_, err = co.AddSingularBatch(batches[3], 3)
+	require.ErrorIs(t, err, CompressorFullErr)
+
+	require.NoError(t, co.Close())
+	var batchData BatchData
+	require.NoError(t, rlp.DecodeBytes(readSpanChannel(t, co), &batchData))
+	spanBatch, err := batchData.RawSpanBatch.Derive(2, 0, chainID)
+	require.NoError(t, err)
+	require.Equal(t, 2, spanBatch.GetBlockCount())
+
+	require.NoError(t, co.Reset())
+	require.NoError(t, co.FullErr())
+	require.Zero(t, co.InputBytes())
+}
+
+func TestSpanChannelOutFirstBatchOverLimit(t *testing.T) {
+	rng := rand.New(rand.NewSource(0x5a14))
+	chainID := big.NewInt(rng.Int63n(1000))
+
+	co, err := NewSpanChannelOut(&limitCompressor{limit: 1}, 1, 0, chainID)
+	require.NoError(t, err)
+	_, err = co.AddSingularBatch(RandomSingularBatch(rng, 4, chainID), 0)
+	require.NoError(t, err, "first batch must always be added")
+	require.ErrorIs(t, co.FullErr(), CompressorFullErr)
+	require.NotZero(t, co.InputBytes())
+}
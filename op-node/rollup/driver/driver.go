@@ -54,6 +54,7 @@ type DerivationPipeline interface {
 	Reset()
 	Step(ctx context.Context) error
 	AddUnsafePayload(payload *eth.ExecutionPayload)
+	RewindUnsafeHead(head eth.L2BlockRef)
 	UnsafeL2SyncTarget() eth.L2BlockRef
 	Finalize(ref eth.L1BlockRef)
 	FinalizedL1() eth.L1BlockRef
@@ -113,14 +114,26 @@ type SequencerStateListener interface {
 	SequencerStopped() error
 }
 
+// SequencerConductor coordinates a cluster of sequencers, of which only the leader sequences new blocks.
+type SequencerConductor interface {
+	// Leader returns true if this sequencer may sequence new blocks.
+	Leader() bool
+	// LeaderUpdates is signalled whenever the result of Leader may have changed.
+	LeaderUpdates() <-chan struct{}
+	// CommittedUnsafeHead returns the latest block committed by the cluster, or a zero block ID if there is none.
+	CommittedUnsafeHead() eth.BlockID
+	// CommitUnsafePayload commits a newly sealed block to the cluster. The block is only published once committed.
+	CommitUnsafePayload(ctx context.Context, payload *eth.ExecutionPayload) error
+}
+
 // NewDriver composes an events handler that tracks L1 state, triggers L2 derivation, and optionally sequences new L2 blocks.
-func NewDriver(driverCfg *Config, cfg *rollup.Config, l2 L2Chain, l1 L1Chain, altSync AltSync, network Network, log log.Logger, snapshotLog log.Logger, metrics Metrics, sequencerStateListener SequencerStateListener, syncCfg *sync.Config) *Driver {
+func NewDriver(driverCfg *Config, cfg *rollup.Config, l2 L2Chain, l1 L1Chain, l1Blobs derive.L1BlobsFetcher, daFetcher derive.DAFetcher, altSync AltSync, network Network, log log.Logger, snapshotLog log.Logger, metrics Metrics, sequencerStateListener SequencerStateListener, safeHeadListener derive.SafeHeadListener, conductor SequencerConductor, syncCfg *sync.Config) *Driver {
 	l1 = NewMeteredL1Fetcher(l1, metrics)
 	l1State := NewL1State(log, metrics)
 	sequencerConfDepth := NewConfDepth(driverCfg.SequencerConfDepth, l1State.L1Head, l1)
 	findL1Origin := NewL1OriginSelector(log, cfg, sequencerConfDepth)
 	verifConfDepth := NewConfDepth(driverCfg.VerifierConfDepth, l1State.L1Head, l1)
-	derivationPipeline := derive.NewDerivationPipeline(log, cfg, verifConfDepth, l2, metrics, syncCfg)
+	derivationPipeline := derive.NewDerivationPipeline(log, cfg, verifConfDepth, l1Blobs, daFetcher, l2, metrics, syncCfg, safeHeadListener)
 	attrBuilder := derive.NewFetchingAttributesBuilder(cfg, l1, l2)
 	engine := derivationPipeline
 	meteredEngine := NewMeteredEngine(cfg, engine, metrics, log)
@@ -144,11 +157,13 @@ func NewDriver(driverCfg *Config, cfg *rollup.Config, l2 L2Chain, l1 L1Chain, al
 		l2:               l2,
 		sequencer:        sequencer,
 		network:          network,
+		conductor:        conductor,
 		metrics:          metrics,
 		l1HeadSig:        make(chan eth.L1BlockRef, 10),
 		l1SafeSig:        make(chan eth.L1BlockRef, 10),
 		l1FinalizedSig:   make(chan eth.L1BlockRef, 10),
 		unsafeL2Payloads: make(chan *eth.ExecutionPayload, 10),
+		syncStatusSubs:   make(map[chan *eth.SyncStatus]struct{}),
 		altSync:          altSync,
 	}
 }
@@ -10,7 +10,9 @@ import (
 	gosync "sync"
 	"time"
 
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/log"
 
 	"github.com/ethereum-optimism/optimism/op-node/rollup"
@@ -82,6 +84,17 @@ type Driver struct {
 	l2        L2Chain
 	sequencer SequencerIface
 	network   Network // may be nil, network for is optional
+	// may be nil, sequencing is only coordinated with other sequencers in a high-availability cluster
+	conductor SequencerConductor
+
+	// uncommittedPayload is the last sealed block that could not be committed by the conductor, and was thus not
+	// published. It is committed again before sequencing on top of it, if this sequencer is still the leader.
+	uncommittedPayload *eth.ExecutionPayload
+
+	// syncStatusSubs holds the sync status that is not delivered yet to each subscription.
+	// Statuses are replaced rather than queued, so slow subscribers never block the event loop.
+	syncStatusSubs   map[chan *eth.SyncStatus]struct{}
+	syncStatusSubsMu gosync.Mutex
 
 	metrics     Metrics
 	log         log.Logger
@@ -229,12 +242,26 @@ func (s *Driver) eventLoop() {
 	defer altSyncTicker.Stop()
 	lastUnsafeL2 := s.derivation.UnsafeL2Head()
 
+	var lastSyncStatus eth.SyncStatus
+
 	for {
+		// Publish the sync status if the previous event changed it.
+		if status := s.syncStatus(); *status != lastSyncStatus {
+			lastSyncStatus = *status
+			s.publishSyncStatus(status)
+		}
+
+		// If the cluster leader has a conflicting unsafe head, reorg to the committed chain before sequencing on it.
+		if s.driverConfig.SequencerEnabled && !s.driverConfig.SequencerStopped && s.derivation.EngineReady() &&
+			s.conductor != nil && s.conductor.Leader() && s.reorgToCommittedHead(ctx) {
+			reqStep() // apply the new forkchoice state
+		}
+
 		// If we are sequencing, and the L1 state is ready, update the trigger for the next sequencer action.
 		// This may adjust at any time based on fork-choice changes or previous errors.
 		// And avoid sequencing if the derivation pipeline indicates the engine is not ready.
 		if s.driverConfig.SequencerEnabled && !s.driverConfig.SequencerStopped &&
-			s.l1State.L1Head() != (eth.L1BlockRef{}) && s.derivation.EngineReady() {
+			s.l1State.L1Head() != (eth.L1BlockRef{}) && s.derivation.EngineReady() && s.conductorAllowsSequencing() {
 			if s.driverConfig.SequencerMaxSafeLag > 0 && s.derivation.SafeL2Head().Number+s.driverConfig.SequencerMaxSafeLag <= s.derivation.UnsafeL2Head().Number {
 				// If the safe head has fallen behind by a significant number of blocks, delay creating new blocks
 				// until the safe lag is below SequencerMaxSafeLag.
@@ -266,7 +293,7 @@ func (s *Driver) eventLoop() {
 
 		select {
 		case <-sequencerCh:
-			payload, err := s.sequencer.RunNextSequencerAction(ctx)
+			payload, err := s.sequenceBlock(ctx)
 			if err != nil {
 				s.log.Error("Sequencer critical error", "err", err)
 				return
@@ -388,12 +415,145 @@ func (s *Driver) eventLoop() {
 			}
 		case respCh := <-s.sequencerActive:
 			respCh <- !s.driverConfig.SequencerStopped
+		case <-s.conductorLeaderUpdates():
+			if s.handleLeaderUpdate(ctx) {
+				reqStep() // apply the new forkchoice state
+			}
 		case <-s.done:
 			return
 		}
 	}
 }
 
+// sequenceBlock runs the next sequencer action, and returns the sealed block to publish, if any.
+// With a conductor, a sealed block is only returned once it is committed to the cluster. A block that fails
+// to commit is kept, and committed again instead of sequencing a new block, as long as it is the unsafe head.
+func (s *Driver) sequenceBlock(ctx context.Context) (*eth.ExecutionPayload, error) {
+	payload := s.uncommittedPayload
+	s.uncommittedPayload = nil
+	if payload == nil || payload.ID() != s.derivation.UnsafeL2Head().ID() {
+		var err error
+		payload, err = s.sequencer.RunNextSequencerAction(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if s.conductor != nil && payload != nil && s.conductor.CommittedUnsafeHead() != payload.ID() {
+		// Only publish blocks committed by the cluster, so that a new leader can take over from them.
+		if err := s.conductor.CommitUnsafePayload(ctx, payload); err != nil {
+			s.log.Warn("failed to commit newly created block", "id", payload.ID(), "err", err)
+			s.uncommittedPayload = payload
+			return nil, nil
+		}
+	}
+	return payload, nil
+}
+
+// handleLeaderUpdate handles a leadership change of the conductor. When this sequencer loses the leadership,
+// the block it sealed but could not commit is dropped from the unsafe chain: the new leader sequences on top of
+// the committed chain instead. It returns true if the unsafe head was rewound.
+func (s *Driver) handleLeaderUpdate(ctx context.Context) bool {
+	if s.conductor.Leader() {
+		s.log.Info("Sequencer is the cluster leader", "committed_unsafe_head", s.conductor.CommittedUnsafeHead())
+		return false
+	}
+	s.log.Info("Sequencer is no longer the cluster leader")
+	// Stop building a block that could not be committed anymore.
+	s.sequencer.CancelBuildingBlock(ctx)
+	return s.dropUncommittedPayload(ctx)
+}
+
+// reorgToCommittedHead reorgs the unsafe chain of the cluster leader back to the committed chain, if the unsafe head
+// conflicts with the committed head. This is the case if the uncommitted block is not on top of the committed head
+// anymore, or if the unsafe head is past a committed block that is known locally, e.g. after a restart.
+// Otherwise, sequencing waits for the committed blocks to be processed. It returns true if the unsafe head was rewound.
+func (s *Driver) reorgToCommittedHead(ctx context.Context) bool {
+	committed := s.conductor.CommittedUnsafeHead()
+	unsafeHead := s.derivation.UnsafeL2Head()
+	if committed == (eth.BlockID{}) || committed == unsafeHead.ID() {
+		return false
+	}
+	if s.uncommittedPayload != nil && s.uncommittedPayload.ID() == unsafeHead.ID() {
+		if s.uncommittedPayload.ParentID() == committed {
+			return false
+		}
+		s.log.Warn("Uncommitted block conflicts with the committed chain", "uncommitted", unsafeHead, "committed", committed)
+		s.sequencer.CancelBuildingBlock(ctx)
+		return s.dropUncommittedPayload(ctx)
+	}
+	if committed.Number >= unsafeHead.Number {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(ctx, time.Second*2)
+	defer cancel()
+	ref, err := s.l2.L2BlockRefByHash(ctx, committed.Hash)
+	if errors.Is(err, ethereum.NotFound) {
+		return false // the committed chain is not known locally yet
+	} else if err != nil {
+		s.log.Warn("Failed to look up committed block", "committed", committed, "err", err)
+		return false
+	}
+	s.log.Warn("Unsafe head is past the committed chain", "unsafe", unsafeHead, "committed", committed)
+	s.sequencer.CancelBuildingBlock(ctx)
+	s.uncommittedPayload = nil
+	return s.rewindUnsafeHead(ref)
+}
+
+// dropUncommittedPayload forgets the block that could not be committed, and rewinds the unsafe head to its parent
+// if the block is the unsafe head. It returns true if the unsafe head was rewound.
+func (s *Driver) dropUncommittedPayload(ctx context.Context) bool {
+	payload := s.uncommittedPayload
+	s.uncommittedPayload = nil
+	if payload == nil || payload.ID() != s.derivation.UnsafeL2Head().ID() {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(ctx, time.Second*2)
+	defer cancel()
+	parent, err := s.l2.L2BlockRefByHash(ctx, payload.ParentHash)
+	if err != nil {
+		s.log.Error("Failed to look up parent of uncommitted block", "uncommitted", payload.ID(), "err", err)
+		return false
+	}
+	return s.rewindUnsafeHead(parent)
+}
+
+// rewindUnsafeHead rewinds the unsafe head to the given block, unless the block is older than the safe head.
+func (s *Driver) rewindUnsafeHead(head eth.L2BlockRef) bool {
+	if safe := s.derivation.SafeL2Head(); head.Number < safe.Number {
+		s.log.Error("Cannot rewind unsafe head past the safe head", "target", head, "safe", safe)
+		return false
+	}
+	s.derivation.RewindUnsafeHead(head)
+	return true
+}
+
+// conductorAllowsSequencing returns true if there is no conductor, or if this sequencer is the cluster leader and
+// resumes from the last block committed by the cluster. If the unsafe head is not the committed block yet, sequencing
+// waits until the committed blocks are processed, unless the unsafe head is the uncommitted block on top of it.
+func (s *Driver) conductorAllowsSequencing() bool {
+	if s.conductor == nil {
+		return true
+	}
+	if !s.conductor.Leader() {
+		return false
+	}
+	committed := s.conductor.CommittedUnsafeHead()
+	unsafeHead := s.derivation.UnsafeL2Head()
+	if committed == (eth.BlockID{}) || committed == unsafeHead.ID() {
+		return true
+	}
+	return s.uncommittedPayload != nil && s.uncommittedPayload.ID() == unsafeHead.ID() &&
+		s.uncommittedPayload.ParentID() == committed
+}
+
+// conductorLeaderUpdates returns the leadership updates of the conductor, or nil if there is no conductor.
+func (s *Driver) conductorLeaderUpdates() <-chan struct{} {
+	if s.conductor == nil {
+		return nil
+	}
+	return s.conductor.LeaderUpdates()
+}
+
 // ResetDerivationPipeline forces a reset of the derivation pipeline.
 // It waits for the reset to occur. It simply unblocks the caller rather
 // than fully cancelling the reset request upon a context cancellation.
@@ -469,6 +629,48 @@ func (s *Driver) SequencerActive(ctx context.Context) (bool, error) {
 	}
 }
 
+// SubscribeSyncStatus subscribes to the sync status, which is sent to ch whenever it changes.
+// Intermediate statuses are skipped if a subscriber falls behind, but the latest status is always sent.
+func (s *Driver) SubscribeSyncStatus(ch chan<- *eth.SyncStatus) ethereum.Subscription {
+	latest := make(chan *eth.SyncStatus, 1)
+	s.syncStatusSubsMu.Lock()
+	s.syncStatusSubs[latest] = struct{}{}
+	s.syncStatusSubsMu.Unlock()
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer func() {
+			s.syncStatusSubsMu.Lock()
+			delete(s.syncStatusSubs, latest)
+			s.syncStatusSubsMu.Unlock()
+		}()
+		for {
+			select {
+			case status := <-latest:
+				select {
+				case ch <- status:
+				case <-quit:
+					return nil
+				}
+			case <-quit:
+				return nil
+			}
+		}
+	})
+}
+
+// publishSyncStatus replaces the status that is not delivered yet to each subscription with the given status.
+// It never blocks on subscribers.
+func (s *Driver) publishSyncStatus(status *eth.SyncStatus) {
+	s.syncStatusSubsMu.Lock()
+	defer s.syncStatusSubsMu.Unlock()
+	for latest := range s.syncStatusSubs {
+		select {
+		case <-latest:
+		default:
+		}
+		latest <- status // only sent to with the lock held, so there is room after draining
+	}
+}
+
 // syncStatus returns the current sync status, and should only be called synchronously with
 // the driver event loop to avoid retrieval of an inconsistent status.
 func (s *Driver) syncStatus() *eth.SyncStatus {
@@ -0,0 +1,267 @@
+package driver
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+)
+
+// fakeL2Chain holds the L2 blocks known to the engine, by hash.
+type fakeL2Chain struct {
+	L2Chain
+	blocks map[common.Hash]eth.L2BlockRef
+}
+
+func (c *fakeL2Chain) L2BlockRefByHash(_ context.Context, hash common.Hash) (eth.L2BlockRef, error) {
+	ref, ok := c.blocks[hash]
+	if !ok {
+		return eth.L2BlockRef{}, ethereum.NotFound
+	}
+	return ref, nil
+}
+
+// add makes a new block on top of parent known to the engine. The tag distinguishes blocks of different sequencers.
+func (c *fakeL2Chain) add(parent eth.L2BlockRef, tag byte) eth.L2BlockRef {
+	ref := eth.L2BlockRef{
+		Hash:       common.Hash{byte(parent.Number + 1), tag},
+		Number:     parent.Number + 1,
+		ParentHash: parent.Hash,
+	}
+	c.blocks[ref.Hash] = ref
+	return ref
+}
+
+type fakeDerivationPipeline struct {
+	DerivationPipeline
+	safe   eth.L2BlockRef
+	unsafe eth.L2BlockRef
+}
+
+func (d *fakeDerivationPipeline) SafeL2Head() eth.L2BlockRef {
+	return d.safe
+}
+
+func (d *fakeDerivationPipeline) UnsafeL2Head() eth.L2BlockRef {
+	return d.unsafe
+}
+
+func (d *fakeDerivationPipeline) RewindUnsafeHead(head eth.L2BlockRef) {
+	d.unsafe = head
+}
+
+// fakeSequencer seals a new block on top of the unsafe head with every sequencer action.
+type fakeSequencer struct {
+	SequencerIface
+	chain      *fakeL2Chain
+	derivation *fakeDerivationPipeline
+	sealed     int
+	cancelled  int
+}
+
+func (s *fakeSequencer) RunNextSequencerAction(_ context.Context) (*eth.ExecutionPayload, error) {
+	ref := s.chain.add(s.derivation.unsafe, 'A')
+	s.derivation.unsafe = ref
+	s.sealed++
+	return &eth.ExecutionPayload{
+		BlockHash:   ref.Hash,
+		ParentHash:  ref.ParentHash,
+		BlockNumber: hexutil.Uint64(ref.Number),
+	}, nil
+}
+
+func (s *fakeSequencer) CancelBuildingBlock(_ context.Context) {
+	s.cancelled++
+}
+
+type fakeConductor struct {
+	leader    bool
+	committed eth.BlockID
+	commitErr error
+}
+
+func (c *fakeConductor) Leader() bool {
+	return c.leader
+}
+
+func (c *fakeConductor) LeaderUpdates() <-chan struct{} {
+	return nil
+}
+
+func (c *fakeConductor) CommittedUnsafeHead() eth.BlockID {
+	return c.committed
+}
+
+func (c *fakeConductor) CommitUnsafePayload(_ context.Context, payload *eth.ExecutionPayload) error {
+	if c.commitErr != nil {
+		return c.commitErr
+	}
+	c.committed = payload.ID()
+	return nil
+}
+
+func setupConductorTest(t *testing.T) (*Driver, *fakeL2Chain, *fakeDerivationPipeline, *fakeSequencer, *fakeConductor) {
+	genesis := eth.L2BlockRef{Hash: common.Hash{0x00, 'G'}}
+	chain := &fakeL2Chain{blocks: map[common.Hash]eth.L2BlockRef{genesis.Hash: genesis}}
+	derivation := &fakeDerivationPipeline{safe: genesis, unsafe: genesis}
+	sequencer := &fakeSequencer{chain: chain, derivation: derivation}
+	conductor := &fakeConductor{leader: true, committed: genesis.ID()}
+	s := &Driver{
+		derivation: derivation,
+		l2:         chain,
+		sequencer:  sequencer,
+		conductor:  conductor,
+		log:        testlog.Logger(t, log.LvlInfo),
+	}
+	return s, chain, derivation, sequencer, conductor
+}
+
+func TestDriverConductor(t *testing.T) {
+	ctx := context.Background()
+	s, chain, derivation, sequencer, conductor := setupConductorTest(t)
+	genesis := derivation.unsafe
+
+	// A block is only returned to publish once it is committed.
+	payload, err := s.sequenceBlock(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, payload)
+	a := derivation.unsafe
+	require.Equal(t, a.ID(), conductor.committed)
+
+	// If the commit fails, the block is kept and sequencing continues on top of the committed head.
+	conductor.commitErr = errors.New("no quorum")
+	payload, err = s.sequenceBlock(ctx)
+	require.NoError(t, err)
+	require.Nil(t, payload, "uncommitted block must not be published")
+	b := derivation.unsafe
+	require.Equal(t, a.ID(), conductor.committed)
+	require.True(t, s.conductorAllowsSequencing())
+	require.False(t, s.reorgToCommittedHead(ctx), "uncommitted block is on top of the committed head")
+
+	// The uncommitted block is committed again, instead of sealing a new block.
+	conductor.commitErr = nil
+	payload, err = s.sequenceBlock(ctx)
+	require.NoError(t, err)
+	require.Equal(t, b.ID(), payload.ID())
+	require.Equal(t, b.ID(), conductor.committed)
+	require.Equal(t, 2, sequencer.sealed)
+
+	// When the leadership is lost, the uncommitted block is dropped from the unsafe chain.
+	conductor.commitErr = errors.New("not leader")
+	payload, err = s.sequenceBlock(ctx)
+	require.NoError(t, err)
+	require.Nil(t, payload)
+	conductor.leader = false
+	require.True(t, s.handleLeaderUpdate(ctx))
+	require.Equal(t, b, derivation.unsafe, "must rewind to the committed head")
+	require.Nil(t, s.uncommittedPayload)
+	require.Equal(t, 1, sequencer.cancelled)
+	require.False(t, s.conductorAllowsSequencing())
+
+	// The new leader commits a block on top of the committed head, which is processed like any unsafe block.
+	c := chain.add(b, 'B')
+	conductor.committed = c.ID()
+	derivation.unsafe = c
+
+	// On re-election, sequencing resumes from the block committed by the previous leader.
+	conductor.leader = true
+	conductor.commitErr = nil
+	require.False(t, s.handleLeaderUpdate(ctx))
+	require.False(t, s.reorgToCommittedHead(ctx))
+	require.True(t, s.conductorAllowsSequencing())
+	payload, err = s.sequenceBlock(ctx)
+	require.NoError(t, err)
+	require.Equal(t, c.Hash, payload.ParentHash)
+	require.Equal(t, payload.ID(), conductor.committed)
+	require.Equal(t, genesis, derivation.safe)
+}
+
+func TestDriverConductorCommittedConflict(t *testing.T) {
+	ctx := context.Background()
+	s, chain, derivation, _, conductor := setupConductorTest(t)
+	genesis := derivation.unsafe
+
+	// The commit of the sealed block fails, while the cluster briefly elects another leader,
+	// which commits a conflicting block before this sequencer is the leader again.
+	conductor.commitErr = errors.New("leadership lost")
+	payload, err := s.sequenceBlock(ctx)
+	require.NoError(t, err)
+	require.Nil(t, payload)
+	other := eth.L2BlockRef{Hash: common.Hash{0x01, 'B'}, Number: 1, ParentHash: genesis.Hash}
+	conductor.committed = other.ID()
+	conductor.commitErr = nil
+	require.False(t, s.handleLeaderUpdate(ctx))
+	require.False(t, s.conductorAllowsSequencing())
+
+	// The leader reorgs back to the committed chain, and waits for the committed block.
+	require.True(t, s.reorgToCommittedHead(ctx))
+	require.Equal(t, genesis, derivation.unsafe)
+	require.Nil(t, s.uncommittedPayload)
+	require.False(t, s.reorgToCommittedHead(ctx), "committed block is not known locally yet")
+	require.False(t, s.conductorAllowsSequencing())
+
+	chain.blocks[other.Hash] = other
+	derivation.unsafe = other
+	require.True(t, s.conductorAllowsSequencing())
+}
+
+func TestDriverConductorUnsafeHeadPastCommitted(t *testing.T) {
+	ctx := context.Background()
+	s, chain, derivation, sequencer, conductor := setupConductorTest(t)
+
+	// The unsafe head is past the committed head with blocks that were never committed,
+	// e.g. because the sequencer restarted after sealing them.
+	committed := chain.add(derivation.unsafe, 'A')
+	conductor.committed = committed.ID()
+	derivation.unsafe = chain.add(chain.add(committed, 'A'), 'A')
+	require.False(t, s.conductorAllowsSequencing())
+
+	require.True(t, s.reorgToCommittedHead(ctx))
+	require.Equal(t, committed, derivation.unsafe)
+	require.Equal(t, 1, sequencer.cancelled)
+	require.True(t, s.conductorAllowsSequencing())
+
+	// The unsafe head is never rewound past the safe head.
+	derivation.safe = derivation.unsafe
+	derivation.unsafe = chain.add(derivation.safe, 'A')
+	conductor.committed = chain.blocks[derivation.safe.ParentHash].ID()
+	require.False(t, s.reorgToCommittedHead(ctx))
+	require.Equal(t, derivation.safe.Number+1, derivation.unsafe.Number)
+}
+
+func TestDriverSyncStatusSubscription(t *testing.T) {
+	s := &Driver{syncStatusSubs: make(map[chan *eth.SyncStatus]struct{})}
+	statuses := make(chan *eth.SyncStatus)
+	sub := s.SubscribeSyncStatus(statuses)
+
+	// Publishing never blocks on a subscriber that does not receive.
+	var last *eth.SyncStatus
+	for i := uint64(1); i <= 10; i++ {
+		last = &eth.SyncStatus{UnsafeL2: eth.L2BlockRef{Number: i}}
+		s.publishSyncStatus(last)
+	}
+
+	// Intermediate statuses are skipped, but the latest status is sent.
+	received := 0
+	for {
+		status := <-statuses
+		received++
+		if status == last {
+			break
+		}
+	}
+	require.LessOrEqual(t, received, 2)
+
+	sub.Unsubscribe()
+	s.syncStatusSubsMu.Lock()
+	defer s.syncStatusSubsMu.Unlock()
+	require.Empty(t, s.syncStatusSubs)
+}
@@ -81,6 +81,23 @@ type Config struct {
 
 	SpanBatchTime *uint64 `json:"span_batch_time,omitempty"`
 
+	// ChannelCompressionTime sets the activation time of versioned channel compression,
+	// which adds brotli and zstd compressed channels next to zlib compressed ones.
+	// Like span batches, it is compared against the L1 inclusion block timestamp of a channel.
+	// Active if ChannelCompressionTime != nil && L1 block timestamp >= *ChannelCompressionTime, inactive otherwise.
+	ChannelCompressionTime *uint64 `json:"channel_compression_time,omitempty"`
+
+	// BlobsEnabledL1Timestamp sets the L1 block timestamp from which batch data is also read from blobs.
+	// Unlike the L2 network upgrades above, this is compared against the L1 origin timestamp.
+	// Active if BlobsEnabledL1Timestamp != nil && L1 block timestamp >= *BlobsEnabledL1Timestamp, inactive otherwise.
+	BlobsEnabledL1Timestamp *uint64 `json:"blobs_data_time,omitempty"`
+
+	// DACommitmentsL1Timestamp sets the L1 block timestamp from which batcher transactions may carry
+	// commitments to batch data that is stored on an off-chain DA server, instead of the batch data itself.
+	// Like blobs data-availability, this is compared against the L1 origin timestamp.
+	// Active if DACommitmentsL1Timestamp != nil && L1 block timestamp >= *DACommitmentsL1Timestamp, inactive otherwise.
+	DACommitmentsL1Timestamp *uint64 `json:"da_commitments_time,omitempty"`
+
 	// Note: below addresses are part of the block-derivation process,
 	// and required to be the same network-wide to stay in consensus.
 
@@ -261,7 +278,7 @@ func (cfg *Config) Check() error {
 }
 
 func (c *Config) L1Signer() types.Signer {
-	return types.NewLondonSigner(c.L1ChainID)
+	return types.NewCancunSigner(c.L1ChainID)
 }
 
 // IsRegolith returns true if the Regolith hardfork is active at or past the given timestamp.
@@ -278,6 +295,23 @@ func (c *Config) IsSpanBatch(timestamp uint64) bool {
 	return c.SpanBatchTime != nil && timestamp >= *c.SpanBatchTime
 }
 
+// IsChannelCompression returns true if brotli and zstd compressed channels are accepted
+// for channels included in an L1 block at the given timestamp.
+func (c *Config) IsChannelCompression(l1Timestamp uint64) bool {
+	return c.ChannelCompressionTime != nil && l1Timestamp >= *c.ChannelCompressionTime
+}
+
+// IsBlobsEnabled returns true if batch data should be read from blobs for the L1 block at the given timestamp.
+func (c *Config) IsBlobsEnabled(l1Timestamp uint64) bool {
+	return c.BlobsEnabledL1Timestamp != nil && l1Timestamp >= *c.BlobsEnabledL1Timestamp
+}
+
+// IsDACommitments returns true if DA commitments in batcher transactions of the L1 block at the
+// given timestamp should be resolved into batch data.
+func (c *Config) IsDACommitments(l1Timestamp uint64) bool {
+	return c.DACommitmentsL1Timestamp != nil && l1Timestamp >= *c.DACommitmentsL1Timestamp
+}
+
 // Description outputs a banner describing the important parts of rollup configuration in a human-readable form.
 // Optionally provide a mapping of L2 chain IDs to network names to label the L2 chain with if not unknown.
 // The config should be config.Check()-ed before creating a description.
@@ -307,6 +341,9 @@ func (c *Config) Description(l2Chains map[string]string) string {
 	banner += fmt.Sprintf("  - Regolith: %s\n", fmtForkTimeOrUnset(c.RegolithTime))
 	banner += fmt.Sprintf("  - Canyon: %s\n", fmtForkTimeOrUnset(c.CanyonTime))
 	banner += fmt.Sprintf("  - SpanBatch: %s\n", fmtForkTimeOrUnset(c.SpanBatchTime))
+	banner += fmt.Sprintf("  - ChannelCompression: %s\n", fmtForkTimeOrUnset(c.ChannelCompressionTime))
+	banner += fmt.Sprintf("L1 blobs data-availability (L1 timestamp based): %s\n", fmtForkTimeOrUnset(c.BlobsEnabledL1Timestamp))
+	banner += fmt.Sprintf("DA commitments (L1 timestamp based): %s\n", fmtForkTimeOrUnset(c.DACommitmentsL1Timestamp))
 	// Report the protocol version
 	banner += fmt.Sprintf("Node supports up to OP-Stack Protocol Version: %s\n", OPStackSupport)
 	return banner
@@ -334,6 +371,9 @@ func (c *Config) LogDescription(log log.Logger, l2Chains map[string]string) {
 		"l1_block_number", c.Genesis.L1.Number, "regolith_time", fmtForkTimeOrUnset(c.RegolithTime),
 		"canyon_time", fmtForkTimeOrUnset(c.CanyonTime),
 		"span_batch_time", fmtForkTimeOrUnset(c.SpanBatchTime),
+		"channel_compression_time", fmtForkTimeOrUnset(c.ChannelCompressionTime),
+		"blobs_enabled_l1_time", fmtForkTimeOrUnset(c.BlobsEnabledL1Timestamp),
+		"da_commitments_l1_time", fmtForkTimeOrUnset(c.DACommitmentsL1Timestamp),
 	)
 }
 
@@ -197,6 +197,18 @@ func TestRegolithActivation(t *testing.T) {
 	require.True(t, config.IsRegolith(124))
 }
 
+func TestChannelCompressionActivation(t *testing.T) {
+	config := randConfig()
+	config.ChannelCompressionTime = nil
+	require.False(t, config.IsChannelCompression(0), "false if nil time, even if checking 0")
+	require.False(t, config.IsChannelCompression(123456), "false if nil time")
+	x := uint64(123)
+	config.ChannelCompressionTime = &x
+	require.False(t, config.IsChannelCompression(122))
+	require.True(t, config.IsChannelCompression(123))
+	require.True(t, config.IsChannelCompression(124))
+}
+
 type mockL2Client struct {
 	chainID *big.Int
 	Hash    common.Hash
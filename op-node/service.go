@@ -19,6 +19,7 @@ import (
 
 	"github.com/ethereum-optimism/optimism/op-node/flags"
 	"github.com/ethereum-optimism/optimism/op-node/node"
+	"github.com/ethereum-optimism/optimism/op-node/node/conductor"
 	p2pcli "github.com/ethereum-optimism/optimism/op-node/p2p/cli"
 	"github.com/ethereum-optimism/optimism/op-node/rollup"
 	"github.com/ethereum-optimism/optimism/op-node/rollup/driver"
@@ -66,6 +67,11 @@ func NewConfig(ctx *cli.Context, log log.Logger) (*node.Config, error) {
 
 	syncConfig := NewSyncConfig(ctx)
 
+	conductorConfig, err := NewConductorConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load sequencer conductor config: %w", err)
+	}
+
 	haltOption := ctx.String(flags.RollupHalt.Name)
 	if haltOption == "none" {
 		haltOption = ""
@@ -75,6 +81,7 @@ func NewConfig(ctx *cli.Context, log log.Logger) (*node.Config, error) {
 		L1:     l1Endpoint,
 		L2:     l2Endpoint,
 		L2Sync: l2SyncEndpoint,
+		Beacon: NewBeaconEndpointConfig(ctx),
 		Rollup: *rollupConfig,
 		Driver: *driverConfig,
 		RPC: node.RPCConfig{
@@ -102,8 +109,13 @@ func NewConfig(ctx *cli.Context, log log.Logger) (*node.Config, error) {
 			URL:     ctx.String(flags.HeartbeatURLFlag.Name),
 		},
 		ConfigPersistence: configPersistence,
+		Conductor:         *conductorConfig,
+		SafeDBPath:        ctx.String(flags.SafeDBPath.Name),
+		L1CachePath:       ctx.String(flags.L1CachePath.Name),
+		L1CacheSize:       ctx.Uint64(flags.L1CacheSizeMB.Name) * 1024 * 1024,
 		Sync:              *syncConfig,
 		RollupHalt:        haltOption,
+		DAServer:          ctx.String(flags.DAServerAddr.Name),
 	}
 
 	if err := cfg.LoadPersisted(log); err != nil {
@@ -124,6 +136,19 @@ func NewL1EndpointConfig(ctx *cli.Context) *node.L1EndpointConfig {
 		RateLimit:        ctx.Float64(flags.L1RPCRateLimit.Name),
 		BatchSize:        ctx.Int(flags.L1RPCMaxBatchSize.Name),
 		HttpPollInterval: ctx.Duration(flags.L1HTTPPollInterval.Name),
+		MaxLagBlocks:     ctx.Uint64(flags.L1RPCMaxLagBlocks.Name),
+		Quorum:           ctx.Int(flags.L1RPCQuorum.Name),
+	}
+}
+
+// NewBeaconEndpointConfig returns the L1 beacon endpoint config, or nil if no beacon endpoint is configured.
+func NewBeaconEndpointConfig(ctx *cli.Context) node.L1BeaconEndpointSetup {
+	addr := ctx.String(flags.BeaconAddr.Name)
+	if addr == "" {
+		return nil
+	}
+	return &node.L1BeaconEndpointConfig{
+		BeaconAddr: addr,
 	}
 }
 
@@ -184,6 +209,21 @@ func NewDriverConfig(ctx *cli.Context) *driver.Config {
 	}
 }
 
+func NewConductorConfig(ctx *cli.Context) (*conductor.Config, error) {
+	peers, err := conductor.ParsePeers(ctx.StringSlice(flags.ConductorPeersFlag.Name))
+	if err != nil {
+		return nil, err
+	}
+	return &conductor.Config{
+		Enabled:       ctx.Bool(flags.ConductorEnabledFlag.Name),
+		ServerID:      ctx.String(flags.ConductorServerIDFlag.Name),
+		RaftAddr:      ctx.String(flags.ConductorRaftAddrFlag.Name),
+		StorageDir:    ctx.String(flags.ConductorStorageDirFlag.Name),
+		Peers:         peers,
+		CommitTimeout: ctx.Duration(flags.ConductorCommitTimeoutFlag.Name),
+	}, nil
+}
+
 func NewRollupConfig(log log.Logger, ctx *cli.Context) (*rollup.Config, error) {
 	network := ctx.String(flags.Network.Name)
 	rollupConfigPath := ctx.String(flags.RollupConfig.Name)
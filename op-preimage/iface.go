@@ -34,6 +34,13 @@ const (
 	LocalKeyType KeyType = 1
 	// Keccak256KeyType is for keccak256 pre-images, for any global shared pre-images.
 	Keccak256KeyType KeyType = 2
+	// Sha256KeyType is for sha256 pre-images, like the KZG commitment of an EIP-4844 blob,
+	// which is the pre-image of the versioned hash of the blob.
+	Sha256KeyType KeyType = 4
+	// BlobKeyType is for EIP-4844 blob field elements, keyed by the keccak256 hash of the KZG
+	// commitment of the blob and the root of unity the field element is the evaluation at.
+	// The pre-image is the 32 byte field element.
+	BlobKeyType KeyType = 5
 )
 
 // LocalIndexKey is a key local to the program, indexing a special program input.
@@ -62,6 +69,41 @@ func (k Keccak256Key) TerminalString() string {
 	return "0x" + hex.EncodeToString(k[:])
 }
 
+// Sha256Key wraps a sha256 hash to use it as a typed pre-image key.
+type Sha256Key [32]byte
+
+func (k Sha256Key) PreimageKey() (out [32]byte) {
+	out = k                      // copy the sha256 hash
+	out[0] = byte(Sha256KeyType) // apply prefix
+	return
+}
+
+func (k Sha256Key) String() string {
+	return "0x" + hex.EncodeToString(k[:])
+}
+
+func (k Sha256Key) TerminalString() string {
+	return "0x" + hex.EncodeToString(k[:])
+}
+
+// BlobKey wraps the keccak256 hash of a KZG commitment and a root of unity, to use it as the
+// typed pre-image key of the blob field element at that root of unity.
+type BlobKey [32]byte
+
+func (k BlobKey) PreimageKey() (out [32]byte) {
+	out = k                    // copy the keccak hash
+	out[0] = byte(BlobKeyType) // apply prefix
+	return
+}
+
+func (k BlobKey) String() string {
+	return "0x" + hex.EncodeToString(k[:])
+}
+
+func (k BlobKey) TerminalString() string {
+	return "0x" + hex.EncodeToString(k[:])
+}
+
 // Hint is an interface to enable any program type to function as a hint,
 // when passed to the Hinter interface, returning a string representation
 // of what data the host should prepare pre-images for.
@@ -0,0 +1,38 @@
+package l1
+
+import (
+	"context"
+
+	"github.com/ethereum-optimism/optimism/op-service/dacommit"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+)
+
+// BlobFetcher implements the derivation pipeline L1BlobsFetcher with blobs from the pre-image oracle.
+type BlobFetcher struct {
+	oracle Oracle
+}
+
+func NewBlobFetcher(oracle Oracle) *BlobFetcher {
+	return &BlobFetcher{oracle: oracle}
+}
+
+func (b *BlobFetcher) GetBlobs(ctx context.Context, ref eth.L1BlockRef, hashes []eth.IndexedBlobHash) ([]*eth.Blob, error) {
+	blobs := make([]*eth.Blob, len(hashes))
+	for i, h := range hashes {
+		blobs[i] = b.oracle.GetBlob(ref, h)
+	}
+	return blobs, nil
+}
+
+// DAFetcher implements the derivation pipeline DAFetcher with DA inputs from the pre-image oracle.
+type DAFetcher struct {
+	oracle Oracle
+}
+
+func NewDAFetcher(oracle Oracle) *DAFetcher {
+	return &DAFetcher{oracle: oracle}
+}
+
+func (d *DAFetcher) GetInput(ctx context.Context, comm dacommit.Commitment) ([]byte, error) {
+	return d.oracle.GetDAInput(comm), nil
+}
@@ -0,0 +1,174 @@
+package l1
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"io"
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
+	preimage "github.com/ethereum-optimism/optimism/op-preimage"
+	"github.com/ethereum-optimism/optimism/op-program/client/l1/test"
+	"github.com/ethereum-optimism/optimism/op-service/dacommit"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+	"github.com/ethereum-optimism/optimism/op-service/testutils"
+)
+
+var (
+	_ derive.L1BlobsFetcher = (*BlobFetcher)(nil)
+	_ derive.DAFetcher      = (*DAFetcher)(nil)
+)
+
+// TestDataAcrossActivation reads the batcher data of L1 blocks before and after blobs data-availability
+// and DA commitments activate, with blobs and DA inputs served by the pre-image oracle.
+func TestDataAcrossActivation(t *testing.T) {
+	batcherKey := testutils.RandomKey()
+	batcherAddr := crypto.PubkeyToAddress(batcherKey.PublicKey)
+	blobsActivation := uint64(1012)
+	daActivation := uint64(1024)
+	cfg := &rollup.Config{
+		L1ChainID:                big.NewInt(100),
+		BatchInboxAddress:        common.Address{0xba},
+		BlobsEnabledL1Timestamp:  &blobsActivation,
+		DACommitmentsL1Timestamp: &daActivation,
+	}
+	signer := cfg.L1Signer()
+
+	stub := test.NewStubOracle(t)
+	preimages := make(map[[32]byte][]byte)
+	oracle := NewPreimageOracle(
+		preimage.OracleFn(func(key preimage.Key) []byte {
+			data, ok := preimages[key.PreimageKey()]
+			require.True(t, ok, "unknown pre-image %x", key.PreimageKey())
+			return data
+		}),
+		preimage.HinterFn(func(v preimage.Hint) {}),
+	)
+	addBlock := func(time uint64, txs ...*types.Transaction) eth.L1BlockRef {
+		hash := common.Hash{byte(time), byte(time >> 8)}
+		stub.Blocks[hash] = &testutils.MockBlockInfo{InfoHash: hash, InfoNum: time / 12, InfoTime: time}
+		stub.Txs[hash] = txs
+		return eth.L1BlockRef{Hash: hash, Number: time / 12, Time: time}
+	}
+
+	// Before the activation, the data is read from calldata.
+	preBlobsData := []byte("calldata batch")
+	preBlobs := addBlock(1000, createTx(t, signer, batcherKey, cfg.BatchInboxAddress, preBlobsData))
+
+	// With blobs data-availability, the data of blob transactions is read from blobs.
+	blobData := []byte("blob batch")
+	var blob eth.Blob
+	require.NoError(t, blob.FromData(blobData))
+	commitment, err := kzg4844.BlobToCommitment(*blob.KZGBlob())
+	require.NoError(t, err)
+	blobHash := eth.KZGToVersionedHash(commitment)
+	addBlobPreimages(preimages, blobHash, commitment, &blob)
+	blobs := addBlock(1012, createBlobTx(t, signer, batcherKey, cfg.BatchInboxAddress, blobHash))
+
+	// With DA commitments, the commitments are resolved to the data they commit to.
+	daData := []byte("DA batch")
+	comm := dacommit.ComputeCommitment(daData)
+	preimages[preimage.Keccak256Key(comm).PreimageKey()] = daData
+	daComms := addBlock(1024, createTx(t, signer, batcherKey, cfg.BatchInboxAddress, comm.TxData()))
+
+	logger := testlog.Logger(t, log.LvlDebug)
+	factory := derive.NewDataSourceFactory(logger, cfg, NewOracleL1Client(logger, stub, daComms.Hash), NewBlobFetcher(oracle), NewDAFetcher(oracle))
+	for _, tc := range []struct {
+		ref      eth.L1BlockRef
+		expected []byte
+	}{
+		{preBlobs, preBlobsData},
+		{blobs, blobData},
+		{daComms, daData},
+	} {
+		src := factory.OpenData(context.Background(), tc.ref, batcherAddr)
+		data, err := src.Next(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, eth.Data(tc.expected), data)
+		_, err = src.Next(context.Background())
+		require.ErrorIs(t, err, io.EOF)
+	}
+}
+
+func TestGetBlobVerifiesCommitment(t *testing.T) {
+	var blob eth.Blob
+	require.NoError(t, blob.FromData([]byte("blob batch")))
+	commitment, err := kzg4844.BlobToCommitment(*blob.KZGBlob())
+	require.NoError(t, err)
+	blobHash := eth.KZGToVersionedHash(commitment)
+
+	newOracle := func(preimages map[[32]byte][]byte) *PreimageOracle {
+		return NewPreimageOracle(
+			preimage.OracleFn(func(key preimage.Key) []byte {
+				data, ok := preimages[key.PreimageKey()]
+				require.True(t, ok, "unknown pre-image %x", key.PreimageKey())
+				return data
+			}),
+			preimage.HinterFn(func(v preimage.Hint) {}),
+		)
+	}
+	ref := eth.L1BlockRef{Time: 1000}
+	indexed := eth.IndexedBlobHash{Hash: blobHash}
+
+	preimages := make(map[[32]byte][]byte)
+	addBlobPreimages(preimages, blobHash, commitment, &blob)
+	result := newOracle(preimages).GetBlob(ref, indexed)
+	require.Equal(t, blob, *result)
+
+	// The sha256 pre-image key does not include the version byte of the versioned hash.
+	otherVersion := blobHash
+	otherVersion[0] = 0x02
+	require.Panics(t, func() {
+		newOracle(preimages).GetBlob(ref, eth.IndexedBlobHash{Hash: otherVersion})
+	}, "commitment must match the version of the versioned hash")
+}
+
+// addBlobPreimages adds the commitment and the field elements of the blob as the pre-images that
+// the client reads the blob with.
+func addBlobPreimages(preimages map[[32]byte][]byte, blobHash common.Hash, commitment kzg4844.Commitment, blob *eth.Blob) {
+	preimages[preimage.Sha256Key(blobHash).PreimageKey()] = commitment[:]
+	for i, root := range eth.BlobRootsOfUnity() {
+		key := preimage.BlobKey(crypto.Keccak256Hash(commitment[:], root[:]))
+		preimages[key.PreimageKey()] = blob[i*32 : (i+1)*32]
+	}
+}
+
+func createTx(t *testing.T, signer types.Signer, key *ecdsa.PrivateKey, to common.Address, data []byte) *types.Transaction {
+	tx, err := types.SignNewTx(key, signer, &types.DynamicFeeTx{
+		ChainID:   signer.ChainID(),
+		GasTipCap: big.NewInt(2 * params.GWei),
+		GasFeeCap: big.NewInt(30 * params.GWei),
+		Gas:       100_000,
+		To:        &to,
+		Data:      data,
+	})
+	require.NoError(t, err)
+	return tx
+}
+
+func createBlobTx(t *testing.T, signer types.Signer, key *ecdsa.PrivateKey, to common.Address, blobHashes ...common.Hash) *types.Transaction {
+	tx, err := types.SignNewTx(key, signer, &types.BlobTx{
+		ChainID:    uint256.MustFromBig(signer.ChainID()),
+		GasTipCap:  uint256.NewInt(2 * params.GWei),
+		GasFeeCap:  uint256.NewInt(30 * params.GWei),
+		Gas:        100_000,
+		To:         to,
+		BlobFeeCap: uint256.NewInt(params.GWei),
+		BlobHashes: blobHashes,
+	})
+	require.NoError(t, err)
+	return tx
+}
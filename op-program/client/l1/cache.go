@@ -6,6 +6,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 
+	"github.com/ethereum-optimism/optimism/op-service/dacommit"
 	"github.com/ethereum-optimism/optimism/op-service/eth"
 )
 
@@ -63,3 +64,13 @@ func (o *CachingOracle) ReceiptsByBlockHash(blockHash common.Hash) (eth.BlockInf
 	o.rcpts.Add(blockHash, rcpts)
 	return block, rcpts
 }
+
+// GetBlob is not cached: blobs are large, and are only read once when the data of an L1 block is opened.
+func (o *CachingOracle) GetBlob(ref eth.L1BlockRef, blobHash eth.IndexedBlobHash) *eth.Blob {
+	return o.oracle.GetBlob(ref, blobHash)
+}
+
+// GetDAInput is not cached: like blobs, DA inputs are only read once when the data of an L1 block is opened.
+func (o *CachingOracle) GetDAInput(comm dacommit.Commitment) []byte {
+	return o.oracle.GetDAInput(comm)
+}
@@ -2,6 +2,7 @@ package l1
 
 import (
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 
 	preimage "github.com/ethereum-optimism/optimism/op-preimage"
 )
@@ -10,6 +11,8 @@ const (
 	HintL1BlockHeader  = "l1-block-header"
 	HintL1Transactions = "l1-transactions"
 	HintL1Receipts     = "l1-receipts"
+	HintL1Blob         = "l1-blob"
+	HintL1DAInput      = "l1-da-input"
 )
 
 type BlockHeaderHint common.Hash
@@ -35,3 +38,22 @@ var _ preimage.Hint = ReceiptsHint{}
 func (l ReceiptsHint) Hint() string {
 	return HintL1Receipts + " " + (common.Hash)(l).String()
 }
+
+// BlobHint is the versioned hash of a blob, followed by its big-endian index in the block
+// and the big-endian timestamp of the L1 block that includes it.
+// The host needs the timestamp to locate the blob on the beacon node.
+type BlobHint []byte
+
+var _ preimage.Hint = BlobHint{}
+
+func (l BlobHint) Hint() string {
+	return HintL1Blob + " " + hexutil.Encode(l)
+}
+
+type DAInputHint common.Hash
+
+var _ preimage.Hint = DAInputHint{}
+
+func (l DAInputHint) Hint() string {
+	return HintL1DAInput + " " + (common.Hash)(l).String()
+}
@@ -1,14 +1,18 @@
 package l1
 
 import (
+	"encoding/binary"
 	"fmt"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
 	"github.com/ethereum/go-ethereum/rlp"
 
 	preimage "github.com/ethereum-optimism/optimism/op-preimage"
 	"github.com/ethereum-optimism/optimism/op-program/client/mpt"
+	"github.com/ethereum-optimism/optimism/op-service/dacommit"
 	"github.com/ethereum-optimism/optimism/op-service/eth"
 )
 
@@ -21,6 +25,12 @@ type Oracle interface {
 
 	// ReceiptsByBlockHash retrieves the receipts from the block with the given hash.
 	ReceiptsByBlockHash(blockHash common.Hash) (eth.BlockInfo, types.Receipts)
+
+	// GetBlob retrieves the blob with the given indexed hash, that was confirmed in the given L1 block.
+	GetBlob(ref eth.L1BlockRef, blobHash eth.IndexedBlobHash) *eth.Blob
+
+	// GetDAInput retrieves the batcher data that the given DA commitment commits to.
+	GetDAInput(comm dacommit.Commitment) []byte
 }
 
 // PreimageOracle implements Oracle using by interfacing with the pure preimage.Oracle
@@ -86,3 +96,39 @@ func (p *PreimageOracle) ReceiptsByBlockHash(blockHash common.Hash) (eth.BlockIn
 
 	return info, receipts
 }
+
+func (p *PreimageOracle) GetBlob(ref eth.L1BlockRef, blobHash eth.IndexedBlobHash) *eth.Blob {
+	hint := make(BlobHint, 32+8+8)
+	copy(hint[:32], blobHash.Hash[:])
+	binary.BigEndian.PutUint64(hint[32:40], blobHash.Index)
+	binary.BigEndian.PutUint64(hint[40:], ref.Time)
+	p.hint.Hint(hint)
+
+	// The versioned hash is the sha256 hash of the KZG commitment, with the version byte as prefix.
+	commitment := p.oracle.Get(preimage.Sha256Key(blobHash.Hash))
+	if len(commitment) != len(kzg4844.Commitment{}) {
+		panic(fmt.Errorf("invalid blob %s commitment length %d", blobHash.Hash, len(commitment)))
+	}
+	// The pre-image key type replaces the version byte, so it must be checked separately.
+	if hash := eth.KZGToVersionedHash(kzg4844.Commitment(commitment)); hash != blobHash.Hash {
+		panic(fmt.Errorf("blob commitment %x does not match versioned hash %s", commitment, blobHash.Hash))
+	}
+
+	// Every field element is read separately, keyed by the commitment and the root of unity it is
+	// the evaluation at, so that it can be verified against the commitment with a KZG proof on chain.
+	var blob eth.Blob
+	for i, root := range eth.BlobRootsOfUnity() {
+		elem := p.oracle.Get(preimage.BlobKey(crypto.Keccak256Hash(commitment, root[:])))
+		if len(elem) != 32 {
+			panic(fmt.Errorf("invalid blob %s field element %d length %d", blobHash.Hash, i, len(elem)))
+		}
+		copy(blob[i*32:], elem)
+	}
+	return &blob
+}
+
+func (p *PreimageOracle) GetDAInput(comm dacommit.Commitment) []byte {
+	p.hint.Hint(DAInputHint(comm))
+	// The DA commitment is the keccak256 hash of the data, so the pre-image oracle verifies it.
+	return p.oracle.Get(preimage.Keccak256Key(comm))
+}
@@ -3,6 +3,7 @@ package test
 import (
 	"testing"
 
+	"github.com/ethereum-optimism/optimism/op-service/dacommit"
 	"github.com/ethereum-optimism/optimism/op-service/eth"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -19,14 +20,22 @@ type StubOracle struct {
 
 	// Rcpts maps Block hash to receipts
 	Rcpts map[common.Hash]types.Receipts
+
+	// Blobs maps blob versioned hash to blob
+	Blobs map[common.Hash]*eth.Blob
+
+	// DAInputs maps DA commitment to the data it commits to
+	DAInputs map[dacommit.Commitment][]byte
 }
 
 func NewStubOracle(t *testing.T) *StubOracle {
 	return &StubOracle{
-		t:      t,
-		Blocks: make(map[common.Hash]eth.BlockInfo),
-		Txs:    make(map[common.Hash]types.Transactions),
-		Rcpts:  make(map[common.Hash]types.Receipts),
+		t:        t,
+		Blocks:   make(map[common.Hash]eth.BlockInfo),
+		Txs:      make(map[common.Hash]types.Transactions),
+		Rcpts:    make(map[common.Hash]types.Receipts),
+		Blobs:    make(map[common.Hash]*eth.Blob),
+		DAInputs: make(map[dacommit.Commitment][]byte),
 	}
 }
 func (o StubOracle) HeaderByBlockHash(blockHash common.Hash) eth.BlockInfo {
@@ -52,3 +61,19 @@ func (o StubOracle) ReceiptsByBlockHash(blockHash common.Hash) (eth.BlockInfo, t
 	}
 	return o.HeaderByBlockHash(blockHash), rcpts
 }
+
+func (o StubOracle) GetBlob(ref eth.L1BlockRef, blobHash eth.IndexedBlobHash) *eth.Blob {
+	blob, ok := o.Blobs[blobHash.Hash]
+	if !ok {
+		o.t.Fatalf("unknown blob %s", blobHash.Hash)
+	}
+	return blob
+}
+
+func (o StubOracle) GetDAInput(comm dacommit.Commitment) []byte {
+	data, ok := o.DAInputs[comm]
+	if !ok {
+		o.t.Fatalf("unknown DA input %s", comm)
+	}
+	return data
+}
@@ -188,6 +188,18 @@ func TestL1(t *testing.T) {
 	require.Equal(t, expected, cfg.L1URL)
 }
 
+func TestL1Beacon(t *testing.T) {
+	expected := "https://example.com:5052"
+	cfg := configForArgs(t, addRequiredArgs("--l1.beacon", expected))
+	require.Equal(t, expected, cfg.L1BeaconURL)
+}
+
+func TestDAServer(t *testing.T) {
+	expected := "https://example.com:3100"
+	cfg := configForArgs(t, addRequiredArgs("--da.server", expected))
+	require.Equal(t, expected, cfg.DAServerURL)
+}
+
 func TestL1TrustRPC(t *testing.T) {
 	t.Run("DefaultFalse", func(t *testing.T) {
 		cfg := configForArgs(t, addRequiredArgs())
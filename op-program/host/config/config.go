@@ -30,6 +30,8 @@ var (
 	ErrInvalidL2ClaimBlock = errors.New("invalid l2 claim block number")
 	ErrDataDirRequired     = errors.New("datadir must be specified when in non-fetching mode")
 	ErrNoExecInServerMode  = errors.New("exec command must not be set when in server mode")
+	ErrMissingL1Beacon     = errors.New("l1 beacon address must be specified when fetching with blobs data-availability enabled")
+	ErrMissingDAServer     = errors.New("da server address must be specified when fetching with DA commitments enabled")
 )
 
 type Config struct {
@@ -43,6 +45,10 @@ type Config struct {
 	L1URL      string
 	L1TrustRPC bool
 	L1RPCKind  sources.RPCProviderKind
+	// L1BeaconURL is the beacon node to fetch blobs from, when blobs data-availability is enabled
+	L1BeaconURL string
+	// DAServerURL is the DA server to fetch DA inputs from, when DA commitments are enabled
+	DAServerURL string
 
 	// L2Head is the l2 block hash contained in the L2 Output referenced by the L2OutputRoot
 	// TODO(inphi): This can be made optional with hardcoded rollup configs and output oracle addresses by searching the oracle for the l2 output root
@@ -103,6 +109,12 @@ func (c *Config) Check() error {
 	if c.ServerMode && c.ExecCmd != "" {
 		return ErrNoExecInServerMode
 	}
+	if c.FetchingEnabled() && c.Rollup.BlobsEnabledL1Timestamp != nil && c.L1BeaconURL == "" {
+		return ErrMissingL1Beacon
+	}
+	if c.FetchingEnabled() && c.Rollup.DACommitmentsL1Timestamp != nil && c.DAServerURL == "" {
+		return ErrMissingDAServer
+	}
 	return nil
 }
 
@@ -194,6 +206,8 @@ func NewConfigFromCLI(log log.Logger, ctx *cli.Context) (*Config, error) {
 		L1URL:               ctx.String(flags.L1NodeAddr.Name),
 		L1TrustRPC:          ctx.Bool(flags.L1TrustRPC.Name),
 		L1RPCKind:           sources.RPCProviderKind(ctx.String(flags.L1RPCProviderKind.Name)),
+		L1BeaconURL:         ctx.String(flags.L1BeaconAddr.Name),
+		DAServerURL:         ctx.String(flags.DAServerAddr.Name),
 		ExecCmd:             ctx.String(flags.Exec.Name),
 		ServerMode:          ctx.Bool(flags.Server.Name),
 		IsCustomChainConfig: isCustomConfig,
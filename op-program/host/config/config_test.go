@@ -160,6 +160,38 @@ func TestRejectExecAndServerMode(t *testing.T) {
 	require.ErrorIs(t, err, ErrNoExecInServerMode)
 }
 
+func TestRequireL1BeaconWhenFetchingBlobs(t *testing.T) {
+	cfg := validConfig()
+	rollupCfg := *cfg.Rollup
+	activation := uint64(1000)
+	rollupCfg.BlobsEnabledL1Timestamp = &activation
+	cfg.Rollup = &rollupCfg
+	require.NoError(t, cfg.Check(), "offline mode does not fetch blobs")
+
+	cfg.L1URL = "https://example.com:1234"
+	cfg.L2URL = "https://example.com:5678"
+	require.ErrorIs(t, cfg.Check(), ErrMissingL1Beacon)
+
+	cfg.L1BeaconURL = "https://example.com:5052"
+	require.NoError(t, cfg.Check())
+}
+
+func TestRequireDAServerWhenFetchingDAInputs(t *testing.T) {
+	cfg := validConfig()
+	rollupCfg := *cfg.Rollup
+	activation := uint64(1000)
+	rollupCfg.DACommitmentsL1Timestamp = &activation
+	cfg.Rollup = &rollupCfg
+	require.NoError(t, cfg.Check(), "offline mode does not fetch DA inputs")
+
+	cfg.L1URL = "https://example.com:1234"
+	cfg.L2URL = "https://example.com:5678"
+	require.ErrorIs(t, cfg.Check(), ErrMissingDAServer)
+
+	cfg.DAServerURL = "https://example.com:3100"
+	require.NoError(t, cfg.Check())
+}
+
 func TestIsCustomChainConfig(t *testing.T) {
 	t.Run("nonCustom", func(t *testing.T) {
 		cfg := validConfig()
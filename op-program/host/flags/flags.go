@@ -75,6 +75,16 @@ var (
 		Usage:   "Address of L1 JSON-RPC endpoint to use (eth namespace required)",
 		EnvVars: prefixEnvVars("L1_RPC"),
 	}
+	L1BeaconAddr = &cli.StringFlag{
+		Name:    "l1.beacon",
+		Usage:   "Address of L1 Beacon-node HTTP endpoint to use. Required to fetch blobs if blobs data-availability is enabled.",
+		EnvVars: prefixEnvVars("L1_BEACON_API"),
+	}
+	DAServerAddr = &cli.StringFlag{
+		Name:    "da.server",
+		Usage:   "Address of the DA server HTTP endpoint to use. Required to fetch DA inputs if DA commitments are enabled.",
+		EnvVars: prefixEnvVars("DA_SERVER"),
+	}
 	L1TrustRPC = &cli.BoolFlag{
 		Name:    "l1.trustrpc",
 		Usage:   "Trust the L1 RPC, sync faster at risk of malicious/buggy RPC providing bad or inconsistent L1 data",
@@ -119,6 +129,8 @@ var programFlags = []cli.Flag{
 	L2NodeAddr,
 	L2GenesisPath,
 	L1NodeAddr,
+	L1BeaconAddr,
+	DAServerAddr,
 	L1TrustRPC,
 	L1RPCProviderKind,
 	Exec,
@@ -20,6 +20,7 @@ import (
 	oppio "github.com/ethereum-optimism/optimism/op-program/io"
 	opservice "github.com/ethereum-optimism/optimism/op-service"
 	"github.com/ethereum-optimism/optimism/op-service/client"
+	"github.com/ethereum-optimism/optimism/op-service/dacommit"
 	"github.com/ethereum-optimism/optimism/op-service/sources"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/log"
@@ -209,8 +210,18 @@ func makePrefetcher(ctx context.Context, logger log.Logger, kv kvstore.KV, cfg *
 	if err != nil {
 		return nil, fmt.Errorf("failed to create L2 client: %w", err)
 	}
+	var l1BlobCl prefetcher.L1BlobSource
+	if cfg.L1BeaconURL != "" {
+		logger.Info("Connecting to L1 beacon node", "l1.beacon", cfg.L1BeaconURL)
+		l1BlobCl = sources.NewL1BeaconClient(client.NewBasicHTTPClient(cfg.L1BeaconURL, logger))
+	}
+	var daCl prefetcher.DASource
+	if cfg.DAServerURL != "" {
+		logger.Info("Connecting to DA server", "da.server", cfg.DAServerURL)
+		daCl = dacommit.NewDAClient(cfg.DAServerURL)
+	}
 	l2DebugCl := &L2Source{L2Client: l2Cl, DebugClient: sources.NewDebugClient(l2RPC.CallContext)}
-	return prefetcher.NewPrefetcher(logger, l1Cl, l2DebugCl, kv), nil
+	return prefetcher.NewPrefetcher(logger, l1Cl, l1BlobCl, daCl, l2DebugCl, kv), nil
 }
 
 func routeHints(logger log.Logger, hHostRW io.ReadWriter, hinter preimage.HintHandler) chan error {
@@ -2,6 +2,7 @@ package prefetcher
 
 import (
 	"context"
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"strings"
@@ -11,11 +12,13 @@ import (
 	"github.com/ethereum-optimism/optimism/op-program/client/l2"
 	"github.com/ethereum-optimism/optimism/op-program/client/mpt"
 	"github.com/ethereum-optimism/optimism/op-program/host/kvstore"
+	"github.com/ethereum-optimism/optimism/op-service/dacommit"
 	"github.com/ethereum-optimism/optimism/op-service/eth"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
 	"github.com/ethereum/go-ethereum/log"
 )
 
@@ -25,6 +28,14 @@ type L1Source interface {
 	FetchReceipts(ctx context.Context, blockHash common.Hash) (eth.BlockInfo, types.Receipts, error)
 }
 
+type L1BlobSource interface {
+	GetBlobSidecars(ctx context.Context, ref eth.L1BlockRef, hashes []eth.IndexedBlobHash) ([]*eth.BlobSidecar, error)
+}
+
+type DASource interface {
+	GetInput(ctx context.Context, comm dacommit.Commitment) ([]byte, error)
+}
+
 type L2Source interface {
 	InfoAndTxsByHash(ctx context.Context, blockHash common.Hash) (eth.BlockInfo, types.Transactions, error)
 	NodeByHash(ctx context.Context, hash common.Hash) ([]byte, error)
@@ -33,20 +44,32 @@ type L2Source interface {
 }
 
 type Prefetcher struct {
-	logger    log.Logger
-	l1Fetcher L1Source
-	l2Fetcher L2Source
-	lastHint  string
-	kvStore   kvstore.KV
+	logger        log.Logger
+	l1Fetcher     L1Source
+	l1BlobFetcher L1BlobSource
+	daFetcher     DASource
+	l2Fetcher     L2Source
+	lastHint      string
+	kvStore       kvstore.KV
 }
 
-func NewPrefetcher(logger log.Logger, l1Fetcher L1Source, l2Fetcher L2Source, kvStore kvstore.KV) *Prefetcher {
-	return &Prefetcher{
+// NewPrefetcher creates a new Prefetcher.
+// The l1BlobFetcher and daFetcher may be nil if the rollup config does not enable
+// blobs data-availability or DA commitments respectively.
+func NewPrefetcher(logger log.Logger, l1Fetcher L1Source, l1BlobFetcher L1BlobSource, daFetcher DASource, l2Fetcher L2Source, kvStore kvstore.KV) *Prefetcher {
+	p := &Prefetcher{
 		logger:    logger,
 		l1Fetcher: NewRetryingL1Source(logger, l1Fetcher),
 		l2Fetcher: NewRetryingL2Source(logger, l2Fetcher),
 		kvStore:   kvStore,
 	}
+	if l1BlobFetcher != nil {
+		p.l1BlobFetcher = NewRetryingL1BlobSource(logger, l1BlobFetcher)
+	}
+	if daFetcher != nil {
+		p.daFetcher = NewRetryingDASource(logger, daFetcher)
+	}
+	return p
 }
 
 func (p *Prefetcher) Hint(hint string) error {
@@ -75,11 +98,18 @@ func (p *Prefetcher) GetPreimage(ctx context.Context, key common.Hash) ([]byte,
 }
 
 func (p *Prefetcher) prefetch(ctx context.Context, hint string) error {
-	hintType, hash, err := parseHint(hint)
+	hintType, hintBytes, err := parseHint(hint)
 	if err != nil {
 		return err
 	}
-	p.logger.Debug("Prefetching", "type", hintType, "hash", hash)
+	p.logger.Debug("Prefetching", "type", hintType, "bytes", hexutil.Bytes(hintBytes))
+	if hintType == l1.HintL1Blob {
+		return p.prefetchBlob(ctx, hintBytes)
+	}
+	if len(hintBytes) != common.HashLength {
+		return fmt.Errorf("invalid hash: %x", hintBytes)
+	}
+	hash := common.Hash(hintBytes)
 	switch hintType {
 	case l1.HintL1BlockHeader:
 		header, err := p.l1Fetcher.InfoByHash(ctx, hash)
@@ -135,10 +165,71 @@ func (p *Prefetcher) prefetch(ctx context.Context, hint string) error {
 			return fmt.Errorf("failed to fetch L2 output root %s: %w", hash, err)
 		}
 		return p.kvStore.Put(preimage.Keccak256Key(hash).PreimageKey(), output.Marshal())
+	case l1.HintL1DAInput:
+		if p.daFetcher == nil {
+			return fmt.Errorf("cannot fetch DA input %s: no DA server configured", hash)
+		}
+		data, err := p.daFetcher.GetInput(ctx, dacommit.Commitment(hash))
+		if err != nil {
+			return fmt.Errorf("failed to fetch DA input %s: %w", hash, err)
+		}
+		return p.kvStore.Put(preimage.Keccak256Key(hash).PreimageKey(), data)
 	}
 	return fmt.Errorf("unknown hint type: %v", hintType)
 }
 
+// prefetchBlob fetches the blob of a l1.BlobHint. It stores the KZG commitment of the blob as the
+// sha256 pre-image of the versioned hash, and every field element of the blob under the blob key of
+// the commitment and the root of unity it is the evaluation at. The keccak256 pre-image of every blob
+// key, the commitment followed by the root of unity, is stored too, so that the challenger can
+// compute the KZG proof that the field element is loaded with into the on-chain pre-image oracle.
+func (p *Prefetcher) prefetchBlob(ctx context.Context, hint []byte) error {
+	if len(hint) != common.HashLength+8+8 {
+		return fmt.Errorf("invalid blob hint: %x", hint)
+	}
+	blobHash := eth.IndexedBlobHash{
+		Hash:  common.Hash(hint[:32]),
+		Index: binary.BigEndian.Uint64(hint[32:40]),
+	}
+	// The beacon node only needs the timestamp of the L1 block to find the blob.
+	ref := eth.L1BlockRef{Time: binary.BigEndian.Uint64(hint[40:])}
+	if p.l1BlobFetcher == nil {
+		return fmt.Errorf("cannot fetch blob %s: no L1 beacon node configured", blobHash.Hash)
+	}
+	sidecars, err := p.l1BlobFetcher.GetBlobSidecars(ctx, ref, []eth.IndexedBlobHash{blobHash})
+	if err != nil {
+		return fmt.Errorf("failed to fetch blob %s: %w", blobHash.Hash, err)
+	}
+	if len(sidecars) != 1 {
+		return fmt.Errorf("expected 1 sidecar for blob %s, got %d", blobHash.Hash, len(sidecars))
+	}
+	sidecar := sidecars[0]
+	commitment := kzg4844.Commitment(sidecar.KZGCommitment)
+	if hash := eth.KZGToVersionedHash(commitment); hash != blobHash.Hash {
+		return fmt.Errorf("blob %s sidecar has commitment %x of versioned hash %s", blobHash.Hash, commitment, hash)
+	}
+	// The client only receives single field elements, so the blob is verified here, against its proof.
+	if err := eth.VerifyBlobProof(&sidecar.Blob, commitment, kzg4844.Proof(sidecar.KZGProof)); err != nil {
+		return fmt.Errorf("blob %s does not match its commitment: %w", blobHash.Hash, err)
+	}
+
+	if err := p.kvStore.Put(preimage.Sha256Key(blobHash.Hash).PreimageKey(), commitment[:]); err != nil {
+		return err
+	}
+	for i, root := range eth.BlobRootsOfUnity() {
+		blobKeyInput := make([]byte, 0, len(commitment)+len(root))
+		blobKeyInput = append(append(blobKeyInput, commitment[:]...), root[:]...)
+		blobKey := crypto.Keccak256Hash(blobKeyInput)
+		if err := p.kvStore.Put(preimage.Keccak256Key(blobKey).PreimageKey(), blobKeyInput); err != nil {
+			return err
+		}
+		if err := p.kvStore.Put(preimage.BlobKey(blobKey).PreimageKey(), sidecar.Blob[i*32:(i+1)*32]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (p *Prefetcher) storeReceipts(receipts types.Receipts) error {
 	opaqueReceipts, err := eth.EncodeReceipts(receipts)
 	if err != nil {
@@ -166,15 +257,15 @@ func (p *Prefetcher) storeTrieNodes(values []hexutil.Bytes) error {
 	return nil
 }
 
-// parseHint parses a hint string in wire protocol. Returns the hint type, requested hash and error (if any).
-func parseHint(hint string) (string, common.Hash, error) {
-	hintType, hashStr, found := strings.Cut(hint, " ")
+// parseHint parses a hint string in wire protocol. Returns the hint type, requested hint bytes and error (if any).
+func parseHint(hint string) (string, []byte, error) {
+	hintType, bytesStr, found := strings.Cut(hint, " ")
 	if !found {
-		return "", common.Hash{}, fmt.Errorf("unsupported hint: %s", hint)
+		return "", nil, fmt.Errorf("unsupported hint: %s", hint)
 	}
-	hash := common.HexToHash(hashStr)
-	if hash == (common.Hash{}) {
-		return "", common.Hash{}, fmt.Errorf("invalid hash: %s", hashStr)
+	hintBytes, err := hexutil.Decode(bytesStr)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid bytes: %s", bytesStr)
 	}
-	return hintType, hash, nil
+	return hintType, hintBytes, nil
 }
@@ -2,12 +2,15 @@ package prefetcher
 
 import (
 	"context"
+	"encoding/binary"
 	"math/rand"
 	"testing"
 
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/stretchr/testify/require"
@@ -17,6 +20,7 @@ import (
 	"github.com/ethereum-optimism/optimism/op-program/client/l2"
 	"github.com/ethereum-optimism/optimism/op-program/client/mpt"
 	"github.com/ethereum-optimism/optimism/op-program/host/kvstore"
+	"github.com/ethereum-optimism/optimism/op-service/dacommit"
 	"github.com/ethereum-optimism/optimism/op-service/eth"
 	"github.com/ethereum-optimism/optimism/op-service/testlog"
 	"github.com/ethereum-optimism/optimism/op-service/testutils"
@@ -153,6 +157,80 @@ func TestFetchL1Receipts(t *testing.T) {
 	})
 }
 
+func TestFetchL1Blob(t *testing.T) {
+	var blob eth.Blob
+	require.NoError(t, blob.FromData(eth.Data("batcher data")))
+	commitment, err := kzg4844.BlobToCommitment(*blob.KZGBlob())
+	require.NoError(t, err)
+	proof, err := kzg4844.ComputeBlobProof(*blob.KZGBlob(), commitment)
+	require.NoError(t, err)
+	blobHash := eth.IndexedBlobHash{Index: 3, Hash: eth.KZGToVersionedHash(commitment)}
+	ref := eth.L1BlockRef{Hash: common.Hash{0xaa}, Number: 10, Time: 1000}
+	sidecar := &eth.BlobSidecar{
+		Blob:          blob,
+		Index:         eth.Uint64String(blobHash.Index),
+		KZGCommitment: eth.Bytes48(commitment),
+		KZGProof:      eth.Bytes48(proof),
+	}
+
+	t.Run("Unknown", func(t *testing.T) {
+		blobs := &stubBlobSource{sidecars: map[common.Hash]*eth.BlobSidecar{blobHash.Hash: sidecar}}
+		kv := kvstore.NewMemKV()
+		prefetcher := NewPrefetcher(testlog.Logger(t, log.LvlDebug), new(testutils.MockL1Source), blobs, nil, nil, kv)
+
+		oracle := l1.NewPreimageOracle(asOracleFn(t, prefetcher), asHinter(t, prefetcher))
+		result := oracle.GetBlob(ref, blobHash)
+		require.Equal(t, blob, *result)
+		require.Equal(t, ref.Time, blobs.lastRef.Time, "must locate the blob by the L1 block time")
+
+		// The inputs of the blob keys are stored for the challenger to compute KZG proofs.
+		root := eth.BlobRootsOfUnity()[1]
+		keyInput := append(commitment[:], root[:]...)
+		stored, err := kv.Get(preimage.Keccak256Key(crypto.Keccak256Hash(keyInput)).PreimageKey())
+		require.NoError(t, err)
+		require.Equal(t, keyInput, stored)
+	})
+
+	t.Run("MismatchedBlob", func(t *testing.T) {
+		var other eth.Blob
+		require.NoError(t, other.FromData(eth.Data("other data")))
+		mismatched := *sidecar
+		mismatched.Blob = other
+		blobs := &stubBlobSource{sidecars: map[common.Hash]*eth.BlobSidecar{blobHash.Hash: &mismatched}}
+		prefetcher := NewPrefetcher(testlog.Logger(t, log.LvlDebug), new(testutils.MockL1Source), blobs, nil, nil, kvstore.NewMemKV())
+
+		require.NoError(t, prefetcher.Hint(blobHint(ref, blobHash).Hint()))
+		_, err := prefetcher.GetPreimage(context.Background(), preimage.Sha256Key(blobHash.Hash).PreimageKey())
+		require.ErrorContains(t, err, "does not match its commitment")
+	})
+
+	t.Run("NoBeaconNode", func(t *testing.T) {
+		prefetcher, _, _, _ := createPrefetcher(t)
+		require.NoError(t, prefetcher.Hint(blobHint(ref, blobHash).Hint()))
+		_, err := prefetcher.GetPreimage(context.Background(), preimage.Sha256Key(blobHash.Hash).PreimageKey())
+		require.ErrorContains(t, err, "no L1 beacon node configured")
+	})
+}
+
+func blobHint(ref eth.L1BlockRef, blobHash eth.IndexedBlobHash) l1.BlobHint {
+	hint := make(l1.BlobHint, 32+8+8)
+	copy(hint[:32], blobHash.Hash[:])
+	binary.BigEndian.PutUint64(hint[32:40], blobHash.Index)
+	binary.BigEndian.PutUint64(hint[40:], ref.Time)
+	return hint
+}
+
+func TestFetchDAInput(t *testing.T) {
+	data := []byte("batcher data")
+	comm := dacommit.ComputeCommitment(data)
+
+	das := stubDASource{comm: data}
+	prefetcher := NewPrefetcher(testlog.Logger(t, log.LvlDebug), new(testutils.MockL1Source), nil, das, nil, kvstore.NewMemKV())
+
+	oracle := l1.NewPreimageOracle(asOracleFn(t, prefetcher), asHinter(t, prefetcher))
+	require.Equal(t, data, oracle.GetDAInput(comm))
+}
+
 func TestFetchL2Block(t *testing.T) {
 	rng := rand.New(rand.NewSource(123))
 	block, rcpts := testutils.RandomBlock(rng, 10)
@@ -277,7 +355,7 @@ func TestBadHints(t *testing.T) {
 
 		// But it will fail to prefetch when the pre-image isn't available
 		pre, err := prefetcher.GetPreimage(context.Background(), hash)
-		require.ErrorContains(t, err, "invalid hash")
+		require.ErrorContains(t, err, "invalid bytes")
 		require.Nil(t, pre)
 	})
 
@@ -314,7 +392,7 @@ func TestRetryWhenNotAvailableAfterPrefetching(t *testing.T) {
 	_, l1Source, l2Cl, kv := createPrefetcher(t)
 	putsToIgnore := 2
 	kv = &unreliableKvStore{KV: kv, putsToIgnore: putsToIgnore}
-	prefetcher := NewPrefetcher(testlog.Logger(t, log.LvlInfo), l1Source, l2Cl, kv)
+	prefetcher := NewPrefetcher(testlog.Logger(t, log.LvlInfo), l1Source, nil, nil, l2Cl, kv)
 
 	// Expect one call for each ignored put, plus one more request for when the put succeeds
 	for i := 0; i < putsToIgnore+1; i++ {
@@ -365,7 +443,7 @@ func createPrefetcher(t *testing.T) (*Prefetcher, *testutils.MockL1Source, *l2Cl
 		MockDebugClient: new(testutils.MockDebugClient),
 	}
 
-	prefetcher := NewPrefetcher(logger, l1Source, l2Source, kv)
+	prefetcher := NewPrefetcher(logger, l1Source, nil, nil, l2Source, kv)
 	return prefetcher, l1Source, l2Source, kv
 }
 
@@ -425,3 +503,31 @@ func assertReceiptsEqual(t *testing.T, expectedRcpt types.Receipts, actualRcpt t
 		require.Equal(t, expected, actual)
 	}
 }
+
+type stubBlobSource struct {
+	sidecars map[common.Hash]*eth.BlobSidecar
+	lastRef  eth.L1BlockRef
+}
+
+func (s *stubBlobSource) GetBlobSidecars(ctx context.Context, ref eth.L1BlockRef, hashes []eth.IndexedBlobHash) ([]*eth.BlobSidecar, error) {
+	s.lastRef = ref
+	out := make([]*eth.BlobSidecar, len(hashes))
+	for i, h := range hashes {
+		sidecar, ok := s.sidecars[h.Hash]
+		if !ok {
+			return nil, ethereum.NotFound
+		}
+		out[i] = sidecar
+	}
+	return out, nil
+}
+
+type stubDASource map[dacommit.Commitment][]byte
+
+func (s stubDASource) GetInput(ctx context.Context, comm dacommit.Commitment) ([]byte, error) {
+	data, ok := s[comm]
+	if !ok {
+		return nil, dacommit.ErrNotFound
+	}
+	return data, nil
+}
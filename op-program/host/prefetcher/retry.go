@@ -4,6 +4,7 @@ import (
 	"context"
 	"math"
 
+	"github.com/ethereum-optimism/optimism/op-service/dacommit"
 	"github.com/ethereum-optimism/optimism/op-service/eth"
 	"github.com/ethereum-optimism/optimism/op-service/retry"
 	"github.com/ethereum/go-ethereum/common"
@@ -60,6 +61,58 @@ func (s *RetryingL1Source) FetchReceipts(ctx context.Context, blockHash common.H
 
 var _ L1Source = (*RetryingL1Source)(nil)
 
+type RetryingL1BlobSource struct {
+	logger   log.Logger
+	source   L1BlobSource
+	strategy retry.Strategy
+}
+
+func NewRetryingL1BlobSource(logger log.Logger, source L1BlobSource) *RetryingL1BlobSource {
+	return &RetryingL1BlobSource{
+		logger:   logger,
+		source:   source,
+		strategy: retry.Exponential(),
+	}
+}
+
+func (s *RetryingL1BlobSource) GetBlobSidecars(ctx context.Context, ref eth.L1BlockRef, hashes []eth.IndexedBlobHash) ([]*eth.BlobSidecar, error) {
+	return retry.Do(ctx, maxAttempts, s.strategy, func() ([]*eth.BlobSidecar, error) {
+		sidecars, err := s.source.GetBlobSidecars(ctx, ref, hashes)
+		if err != nil {
+			s.logger.Warn("Failed to retrieve blob sidecars", "ref", ref, "err", err)
+		}
+		return sidecars, err
+	})
+}
+
+var _ L1BlobSource = (*RetryingL1BlobSource)(nil)
+
+type RetryingDASource struct {
+	logger   log.Logger
+	source   DASource
+	strategy retry.Strategy
+}
+
+func NewRetryingDASource(logger log.Logger, source DASource) *RetryingDASource {
+	return &RetryingDASource{
+		logger:   logger,
+		source:   source,
+		strategy: retry.Exponential(),
+	}
+}
+
+func (s *RetryingDASource) GetInput(ctx context.Context, comm dacommit.Commitment) ([]byte, error) {
+	return retry.Do(ctx, maxAttempts, s.strategy, func() ([]byte, error) {
+		data, err := s.source.GetInput(ctx, comm)
+		if err != nil {
+			s.logger.Warn("Failed to retrieve DA input", "commitment", comm, "err", err)
+		}
+		return data, err
+	})
+}
+
+var _ DASource = (*RetryingDASource)(nil)
+
 type RetryingL2Source struct {
 	logger   log.Logger
 	source   L2Source
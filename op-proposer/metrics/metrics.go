@@ -27,6 +27,8 @@ type Metricer interface {
 	// Record Tx metrics
 	txmetrics.TxMetricer
 
+	opmetrics.RPCEndpointMetricer
+
 	RecordL2BlocksProposed(l2ref eth.L2BlockRef)
 }
 
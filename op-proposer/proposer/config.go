@@ -10,6 +10,7 @@ import (
 	"github.com/ethereum-optimism/optimism/op-proposer/flags"
 	"github.com/ethereum-optimism/optimism/op-service/sources"
 
+	"github.com/ethereum-optimism/optimism/op-service/dial"
 	oplog "github.com/ethereum-optimism/optimism/op-service/log"
 	opmetrics "github.com/ethereum-optimism/optimism/op-service/metrics"
 	oppprof "github.com/ethereum-optimism/optimism/op-service/pprof"
@@ -61,6 +62,8 @@ type CLIConfig struct {
 	MetricsConfig opmetrics.CLIConfig
 
 	PprofConfig oppprof.CLIConfig
+
+	L1RPCConfig dial.CLIConfig
 }
 
 func (c CLIConfig) Check() error {
@@ -76,6 +79,9 @@ func (c CLIConfig) Check() error {
 	if err := c.TxMgrConfig.Check(); err != nil {
 		return err
 	}
+	if err := c.L1RPCConfig.Check(); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -94,5 +100,6 @@ func NewConfig(ctx *cli.Context) CLIConfig {
 		LogConfig:         oplog.ReadCLIConfig(ctx),
 		MetricsConfig:     opmetrics.ReadCLIConfig(ctx),
 		PprofConfig:       oppprof.ReadCLIConfig(ctx),
+		L1RPCConfig:       dial.ReadCLIConfig(ctx),
 	}
 }
@@ -176,7 +176,7 @@ func NewL2OutputSubmitterConfigFromCLIConfig(cfg CLIConfig, l log.Logger, m metr
 	}
 
 	// Connect to L1 and L2 providers. Perform these last since they are the most expensive.
-	l1Client, err := dial.DialEthClientWithTimeout(context.Background(), dial.DefaultDialTimeout, l, cfg.L1EthRpc)
+	l1Client, err := dial.DialMultiEthClientWithTimeout(context.Background(), dial.DefaultDialTimeout, l, cfg.L1EthRpc, cfg.L1RPCConfig.MultiRPCConfig(), m)
 	if err != nil {
 		return nil, err
 	}
@@ -190,7 +190,7 @@ func NewL2OutputSubmitterConfigFromCLIConfig(cfg CLIConfig, l log.Logger, m metr
 		L2OutputOracleAddr: l2ooAddress,
 		PollInterval:       cfg.PollInterval,
 		NetworkTimeout:     cfg.TxMgrConfig.NetworkTimeout,
-		L1Client:           l1Client,
+		L1Client:           l1Client.Client,
 		RollupClient:       rollupClient,
 		AllowNonFinalized:  cfg.AllowNonFinalized,
 		TxManager:          txManager,
@@ -0,0 +1,149 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// NewGethRPCClient returns a go-ethereum *rpc.Client that sends its requests to cl, for APIs that need a
+// *rpc.Client, like ethclient. Subscriptions are not supported, like with an HTTP connection.
+// Errors of cl that are not JSON-RPC errors are returned as they are, so they can be checked with errors.Is.
+// Closing the returned client does not close cl.
+func NewGethRPCClient(cl RPC) (*rpc.Client, error) {
+	return rpc.DialOptions(context.Background(), "http://client-rpc", rpc.WithHTTPClient(&http.Client{
+		Transport: &rpcTransport{rpc: cl},
+	}))
+}
+
+type jsonrpcMessage struct {
+	Version string            `json:"jsonrpc,omitempty"`
+	ID      json.RawMessage   `json:"id,omitempty"`
+	Method  string            `json:"method,omitempty"`
+	Params  []json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage   `json:"result,omitempty"`
+	Error   *jsonError        `json:"error,omitempty"`
+}
+
+type jsonError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    any    `json:"data,omitempty"`
+}
+
+// rpcTransport serves the JSON-RPC over HTTP requests of a *rpc.Client with a client.RPC, without any network I/O.
+// The requests are served with the context of the caller.
+type rpcTransport struct {
+	rpc RPC
+}
+
+func (t *rpcTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := io.ReadAll(req.Body)
+	_ = req.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request: %w", err)
+	}
+	var out []byte
+	if body = bytes.TrimSpace(body); len(body) > 0 && body[0] == '[' {
+		var msgs []*jsonrpcMessage
+		if err := json.Unmarshal(body, &msgs); err != nil {
+			return nil, fmt.Errorf("failed to decode batch request: %w", err)
+		}
+		elems := make([]rpc.BatchElem, 0, len(msgs))
+		for _, msg := range msgs {
+			if !isSubscriptionMethod(msg.Method) {
+				elems = append(elems, rpc.BatchElem{Method: msg.Method, Args: params(msg.Params), Result: new(json.RawMessage)})
+			}
+		}
+		if len(elems) > 0 {
+			if err := t.rpc.BatchCallContext(req.Context(), elems); err != nil {
+				return nil, err
+			}
+		}
+		resps := make([]*jsonrpcMessage, len(msgs))
+		for i, msg := range msgs {
+			if isSubscriptionMethod(msg.Method) {
+				resps[i] = response(msg, nil, rpc.ErrNotificationsUnsupported)
+				continue
+			}
+			elem := elems[0]
+			elems = elems[1:]
+			resps[i] = response(msg, *elem.Result.(*json.RawMessage), elem.Error)
+		}
+		out, err = json.Marshal(resps)
+	} else {
+		var msg jsonrpcMessage
+		if err := json.Unmarshal(body, &msg); err != nil {
+			return nil, fmt.Errorf("failed to decode request: %w", err)
+		}
+		var result json.RawMessage
+		var callErr error
+		if isSubscriptionMethod(msg.Method) {
+			callErr = rpc.ErrNotificationsUnsupported
+		} else {
+			callErr = t.rpc.CallContext(req.Context(), &result, msg.Method, params(msg.Params)...)
+		}
+		var rpcErr rpc.Error
+		if callErr != nil && !errors.As(callErr, &rpcErr) {
+			return nil, callErr
+		}
+		out, err = json.Marshal(response(&msg, result, callErr))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode response: %w", err)
+	}
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(out)),
+		ContentLength: int64(len(out)),
+		Request:       req,
+	}, nil
+}
+
+// isSubscriptionMethod returns whether the method manages a subscription, like eth_subscribe.
+// The *rpc.Client already rejects its own subscriptions, but the methods may still be called directly.
+func isSubscriptionMethod(method string) bool {
+	return strings.HasSuffix(method, "_subscribe") || strings.HasSuffix(method, "_unsubscribe")
+}
+
+// params passes on the encoded parameters of a request as they are.
+func params(raw []json.RawMessage) []any {
+	args := make([]any, len(raw))
+	for i, p := range raw {
+		args[i] = p
+	}
+	return args
+}
+
+func response(req *jsonrpcMessage, result json.RawMessage, err error) *jsonrpcMessage {
+	resp := &jsonrpcMessage{Version: "2.0", ID: req.ID}
+	if err == nil {
+		if len(result) == 0 {
+			result = json.RawMessage("null")
+		}
+		resp.Result = result
+		return resp
+	}
+	resp.Error = &jsonError{Code: -32000, Message: err.Error()}
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) {
+		resp.Error.Code = rpcErr.ErrorCode()
+	}
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) {
+		resp.Error.Data = dataErr.ErrorData()
+	}
+	return resp
+}
@@ -0,0 +1,54 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// HTTP is a minimal HTTP client, used for REST-style APIs such as the beacon-API.
+type HTTP interface {
+	Get(ctx context.Context, path string, query url.Values, headers http.Header) (*http.Response, error)
+}
+
+type BasicHTTPClient struct {
+	endpoint string
+	log      log.Logger
+	client   *http.Client
+}
+
+func NewBasicHTTPClient(endpoint string, log log.Logger) *BasicHTTPClient {
+	// Make sure the endpoint ends in trailing slash
+	trimmedEndpoint := strings.TrimSuffix(endpoint, "/") + "/"
+	return &BasicHTTPClient{
+		endpoint: trimmedEndpoint,
+		log:      log,
+		client:   &http.Client{},
+	}
+}
+
+func (cl *BasicHTTPClient) Get(ctx context.Context, p string, query url.Values, headers http.Header) (*http.Response, error) {
+	u, err := url.JoinPath(cl.endpoint, p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to join path: %w", err)
+	}
+	reqURL, err := url.Parse(u)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse url: %w", err)
+	}
+	reqURL.RawQuery = query.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to construct request: %w", err)
+	}
+	for k, values := range headers {
+		for _, v := range values {
+			req.Header.Add(k, v)
+		}
+	}
+	return cl.client.Do(req)
+}
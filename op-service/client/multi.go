@@ -0,0 +1,488 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	opmetrics "github.com/ethereum-optimism/optimism/op-service/metrics"
+)
+
+var (
+	ErrNoQuorum       = errors.New("RPC endpoints did not reach quorum")
+	ErrNoRPCEndpoints = errors.New("no RPC endpoints")
+)
+
+// MultiRPCConfig configures the failover and quorum behavior of a MultiRPC.
+type MultiRPCConfig struct {
+	// MaxLagBlocks is the number of blocks an endpoint may lag behind the highest head of all endpoints,
+	// before requests fail over to other endpoints. 0 disables the lag check.
+	MaxLagBlocks uint64
+	// Quorum is the number of endpoints that must agree on the block hash of block-by-number and receipts requests.
+	// 1 disables the quorum check.
+	Quorum int
+	// HealthCheckInterval is the minimum interval between checks of the head of every endpoint.
+	// The endpoints are checked in the background of requests, so an idle MultiRPC does not make requests.
+	HealthCheckInterval time.Duration
+}
+
+var DefaultMultiRPCConfig = MultiRPCConfig{
+	MaxLagBlocks:        10,
+	Quorum:              1,
+	HealthCheckInterval: 12 * time.Second,
+}
+
+func (c *MultiRPCConfig) Check(endpoints int) error {
+	if c.Quorum < 1 {
+		return fmt.Errorf("quorum must be at least 1, got %d", c.Quorum)
+	}
+	if c.Quorum > endpoints {
+		return fmt.Errorf("quorum of %d exceeds the number of endpoints (%d)", c.Quorum, endpoints)
+	}
+	if c.HealthCheckInterval <= 0 {
+		return errors.New("health check interval must be positive")
+	}
+	return nil
+}
+
+// SplitAddrs splits a comma-separated list of RPC endpoint addresses.
+func SplitAddrs(addr string) []string {
+	var addrs []string
+	for _, a := range strings.Split(addr, ",") {
+		if a = strings.TrimSpace(a); a != "" {
+			addrs = append(addrs, a)
+		}
+	}
+	return addrs
+}
+
+type rpcEndpoint struct {
+	// name identifies the endpoint in logs and metrics, without revealing the address, which may contain an API key.
+	name    string
+	rpc     RPC
+	healthy atomic.Bool
+	head    atomic.Uint64
+}
+
+// MultiRPC is a client.RPC that serves requests from several endpoints of the same chain.
+// Requests go to the first healthy endpoint, in the configured order, and fail over to the next endpoint on
+// transport errors. An endpoint is unhealthy after a failed request, or when it lags more than MaxLagBlocks behind
+// the other endpoints, until the next health check succeeds. Health checks run in the background of requests,
+// at most once every HealthCheckInterval.
+// Block-by-number and receipts requests can also require a quorum of endpoints to agree on the block hash.
+type MultiRPC struct {
+	log       log.Logger
+	cfg       MultiRPCConfig
+	m         opmetrics.RPCEndpointMetricer
+	endpoints []*rpcEndpoint
+
+	// preferred is the index of the endpoint that requests are sent to first.
+	preferred atomic.Int32
+
+	// lastHealthCheck is the time, in unix nanoseconds, at which the last health check started.
+	lastHealthCheck atomic.Int64
+	checkingHealth  atomic.Bool
+}
+
+var _ RPC = (*MultiRPC)(nil)
+
+// NewMultiRPC creates a MultiRPC over the given endpoints, in order of preference.
+// It takes ownership of the endpoints, and closes them when closed.
+func NewMultiRPC(lgr log.Logger, endpoints []RPC, cfg MultiRPCConfig, m opmetrics.RPCEndpointMetricer) (*MultiRPC, error) {
+	if len(endpoints) == 0 {
+		return nil, ErrNoRPCEndpoints
+	}
+	if err := cfg.Check(len(endpoints)); err != nil {
+		return nil, fmt.Errorf("invalid multi-RPC config: %w", err)
+	}
+	if m == nil {
+		m = &opmetrics.NoopRPCMetrics{}
+	}
+	mr := &MultiRPC{
+		log: lgr,
+		cfg: cfg,
+		m:   m,
+	}
+	for i, cl := range endpoints {
+		e := &rpcEndpoint{name: strconv.Itoa(i), rpc: cl}
+		e.healthy.Store(true)
+		mr.endpoints = append(mr.endpoints, e)
+	}
+	return mr, nil
+}
+
+func (mr *MultiRPC) Close() {
+	for _, e := range mr.endpoints {
+		e.rpc.Close()
+	}
+}
+
+func (mr *MultiRPC) CallContext(ctx context.Context, result any, method string, args ...any) error {
+	if mr.cfg.Quorum > 1 && needsQuorum(method, args) {
+		return mr.quorumCall(ctx, result, method, args)
+	}
+	return mr.failover(ctx, func(cl RPC) error {
+		return cl.CallContext(ctx, result, method, args...)
+	})
+}
+
+func (mr *MultiRPC) BatchCallContext(ctx context.Context, b []rpc.BatchElem) error {
+	if mr.cfg.Quorum > 1 {
+		for _, elem := range b {
+			if needsQuorum(elem.Method, elem.Args) {
+				return mr.quorumBatchCall(ctx, b)
+			}
+		}
+	}
+	return mr.failover(ctx, func(cl RPC) error {
+		return cl.BatchCallContext(ctx, b)
+	})
+}
+
+func (mr *MultiRPC) EthSubscribe(ctx context.Context, channel any, args ...any) (sub ethereum.Subscription, err error) {
+	err = mr.failover(ctx, func(cl RPC) error {
+		sub, err = cl.EthSubscribe(ctx, channel, args...)
+		return err
+	})
+	return sub, err
+}
+
+func (mr *MultiRPC) Subscribe(ctx context.Context, namespace string, channel any, args ...any) (sub ethereum.Subscription, err error) {
+	err = mr.failover(ctx, func(cl RPC) error {
+		sub, err = cl.Subscribe(ctx, namespace, channel, args...)
+		return err
+	})
+	return sub, err
+}
+
+// failover runs fn against the endpoints in order of preference, until it succeeds or fails with an error that
+// another endpoint would return too.
+func (mr *MultiRPC) failover(ctx context.Context, fn func(cl RPC) error) error {
+	mr.maybeCheckHealth()
+	var err error
+	for _, e := range mr.ordered() {
+		err = fn(e.rpc)
+		if err == nil || !shouldFailover(ctx, err) {
+			return err
+		}
+		mr.markUnhealthy(e, err)
+	}
+	return err
+}
+
+// ordered returns the healthy endpoints in order of preference, followed by the unhealthy endpoints,
+// which are only used as a last resort.
+func (mr *MultiRPC) ordered() []*rpcEndpoint {
+	out := make([]*rpcEndpoint, 0, len(mr.endpoints))
+	for _, e := range mr.endpoints {
+		if e.healthy.Load() {
+			out = append(out, e)
+		}
+	}
+	for _, e := range mr.endpoints {
+		if !e.healthy.Load() {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// shouldFailover returns true if the request may succeed on another endpoint.
+// Errors returned by the RPC server, like an unknown block, are not retried.
+func shouldFailover(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var rpcErr rpc.Error
+	return !errors.As(err, &rpcErr)
+}
+
+func (mr *MultiRPC) markUnhealthy(e *rpcEndpoint, err error) {
+	if e.healthy.CompareAndSwap(true, false) {
+		mr.log.Warn("RPC endpoint failed, failing over to the next endpoint", "endpoint", e.name, "err", err)
+		mr.m.RecordRPCClientEndpointHealth(e.name, false, e.head.Load())
+		mr.updatePreferred()
+	}
+}
+
+// updatePreferred records a failover if the endpoint that requests are sent to first changed.
+func (mr *MultiRPC) updatePreferred() {
+	next := int32(0)
+	for i, e := range mr.endpoints {
+		if e.healthy.Load() {
+			next = int32(i)
+			break
+		}
+	}
+	if prev := mr.preferred.Swap(next); prev != next {
+		mr.log.Info("Switched preferred RPC endpoint", "from", mr.endpoints[prev].name, "to", mr.endpoints[next].name)
+		mr.m.RecordRPCClientFailover(mr.endpoints[prev].name)
+	}
+}
+
+// maybeCheckHealth starts a health check in the background, if the last one is older than the health check interval.
+func (mr *MultiRPC) maybeCheckHealth() {
+	now := time.Now().UnixNano()
+	if now-mr.lastHealthCheck.Load() < int64(mr.cfg.HealthCheckInterval) || !mr.checkingHealth.CompareAndSwap(false, true) {
+		return
+	}
+	mr.lastHealthCheck.Store(now)
+	go func() {
+		defer mr.checkingHealth.Store(false)
+		ctx, cancel := context.WithTimeout(context.Background(), mr.cfg.HealthCheckInterval)
+		defer cancel()
+		mr.checkHealth(ctx)
+	}()
+}
+
+// checkHealth fetches the head of every endpoint, and marks the endpoints that failed or lag too far behind the
+// highest head as unhealthy.
+func (mr *MultiRPC) checkHealth(ctx context.Context) {
+	errs := make([]error, len(mr.endpoints))
+	var wg sync.WaitGroup
+	for i, e := range mr.endpoints {
+		wg.Add(1)
+		go func(i int, e *rpcEndpoint) {
+			defer wg.Done()
+			var head hexutil.Uint64
+			if errs[i] = e.rpc.CallContext(ctx, &head, "eth_blockNumber"); errs[i] == nil {
+				e.head.Store(uint64(head))
+			}
+		}(i, e)
+	}
+	wg.Wait()
+
+	var highest uint64
+	for i, e := range mr.endpoints {
+		if errs[i] == nil && e.head.Load() > highest {
+			highest = e.head.Load()
+		}
+	}
+	for i, e := range mr.endpoints {
+		head := e.head.Load()
+		healthy := errs[i] == nil && (mr.cfg.MaxLagBlocks == 0 || highest-head <= mr.cfg.MaxLagBlocks)
+		if prev := e.healthy.Swap(healthy); prev != healthy {
+			mr.log.Warn("RPC endpoint health changed", "endpoint", e.name, "healthy", healthy, "head", head, "highest", highest, "err", errs[i])
+		}
+		mr.m.RecordRPCClientEndpointHealth(e.name, healthy, head)
+	}
+	mr.updatePreferred()
+}
+
+// quorumEndpoints returns the first Quorum healthy endpoints in order of preference.
+func (mr *MultiRPC) quorumEndpoints() ([]*rpcEndpoint, error) {
+	var out []*rpcEndpoint
+	for _, e := range mr.endpoints {
+		if e.healthy.Load() {
+			out = append(out, e)
+			if len(out) == mr.cfg.Quorum {
+				return out, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("%w: only %d of %d required endpoints are healthy", ErrNoQuorum, len(out), mr.cfg.Quorum)
+}
+
+func (mr *MultiRPC) quorumCall(ctx context.Context, result any, method string, args []any) error {
+	mr.maybeCheckHealth()
+	endpoints, err := mr.quorumEndpoints()
+	if err != nil {
+		return err
+	}
+	results := make([]json.RawMessage, len(endpoints))
+	errs := make([]error, len(endpoints))
+	var wg sync.WaitGroup
+	for i, e := range endpoints {
+		wg.Add(1)
+		go func(i int, e *rpcEndpoint) {
+			defer wg.Done()
+			errs[i] = e.rpc.CallContext(ctx, &results[i], method, args...)
+		}(i, e)
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			if shouldFailover(ctx, err) {
+				mr.markUnhealthy(endpoints[i], err)
+			}
+			return err
+		}
+	}
+	if err := mr.checkAgreement(endpoints, method, results); err != nil {
+		return err
+	}
+	return json.Unmarshal(results[0], result)
+}
+
+func (mr *MultiRPC) quorumBatchCall(ctx context.Context, b []rpc.BatchElem) error {
+	mr.maybeCheckHealth()
+	endpoints, err := mr.quorumEndpoints()
+	if err != nil {
+		return err
+	}
+	batches := make([][]rpc.BatchElem, len(endpoints))
+	results := make([][]json.RawMessage, len(endpoints))
+	errs := make([]error, len(endpoints))
+	var wg sync.WaitGroup
+	for i, e := range endpoints {
+		batches[i] = make([]rpc.BatchElem, len(b))
+		results[i] = make([]json.RawMessage, len(b))
+		for j, elem := range b {
+			batches[i][j] = rpc.BatchElem{Method: elem.Method, Args: elem.Args, Result: &results[i][j]}
+		}
+		wg.Add(1)
+		go func(i int, e *rpcEndpoint) {
+			defer wg.Done()
+			errs[i] = e.rpc.BatchCallContext(ctx, batches[i])
+		}(i, e)
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			if shouldFailover(ctx, err) {
+				mr.markUnhealthy(endpoints[i], err)
+			}
+			return err
+		}
+	}
+	for j := range b {
+		elemResults := make([]json.RawMessage, len(endpoints))
+		for i := range endpoints {
+			if err := batches[i][j].Error; err != nil {
+				b[j].Error = err
+				break
+			}
+			elemResults[i] = results[i][j]
+		}
+		if b[j].Error != nil {
+			continue
+		}
+		if needsQuorum(b[j].Method, b[j].Args) {
+			if err := mr.checkAgreement(endpoints, b[j].Method, elemResults); err != nil {
+				b[j].Error = err
+				continue
+			}
+		}
+		b[j].Error = json.Unmarshal(elemResults[0], b[j].Result)
+	}
+	return nil
+}
+
+// checkAgreement returns an error if the results of the endpoints differ in the block they describe.
+func (mr *MultiRPC) checkAgreement(endpoints []*rpcEndpoint, method string, results []json.RawMessage) error {
+	var first quorumKey
+	for i, res := range results {
+		key, err := quorumMethods[method](res)
+		if err != nil {
+			return fmt.Errorf("failed to decode %s result of endpoint %s: %w", method, endpoints[i].name, err)
+		}
+		if i == 0 {
+			first = key
+		} else if key != first {
+			mr.log.Warn("RPC endpoints disagree", "method", method,
+				"endpoint", endpoints[0].name, "block", first.hash, "count", first.count,
+				"other", endpoints[i].name, "other_block", key.hash, "other_count", key.count)
+			return fmt.Errorf("%w: endpoints %s and %s disagree on the %s result", ErrNoQuorum, endpoints[0].name, endpoints[i].name, method)
+		}
+	}
+	return nil
+}
+
+// quorumKey identifies the block that a result describes, and the number of receipts if it is a list of receipts.
+type quorumKey struct {
+	hash  common.Hash
+	count int
+}
+
+// quorumMethods maps the methods that are checked for quorum to the function that reads the block of their result.
+// This covers the block-by-number and receipts fetching methods that are used by the L1 client.
+var quorumMethods = map[string]func(res json.RawMessage) (quorumKey, error){
+	"eth_getBlockByNumber":               blockResultKey,
+	"eth_getTransactionReceipt":          receiptResultKey,
+	"eth_getBlockReceipts":               receiptsResultKey,
+	"parity_getBlockReceipts":            receiptsResultKey,
+	"erigon_getBlockReceiptsByBlockHash": receiptsResultKey,
+	"alchemy_getTransactionReceipts": func(res json.RawMessage) (quorumKey, error) {
+		var wrapped struct {
+			Receipts json.RawMessage `json:"receipts"`
+		}
+		if err := unmarshalNullable(res, &wrapped); err != nil {
+			return quorumKey{}, err
+		}
+		return receiptsResultKey(wrapped.Receipts)
+	},
+}
+
+// needsQuorum returns true if the request is checked for quorum.
+// Blocks requested by label, like "latest", are not: endpoints commonly differ by a block at the tip.
+func needsQuorum(method string, args []any) bool {
+	if _, ok := quorumMethods[method]; !ok {
+		return false
+	}
+	if method == "eth_getBlockByNumber" && len(args) > 0 {
+		switch num := args[0].(type) {
+		case string:
+			return strings.HasPrefix(num, "0x")
+		case json.RawMessage: // forwarded by an rpc.Client, see NewGethRPCClient
+			return bytes.HasPrefix(num, []byte(`"0x`))
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func blockResultKey(res json.RawMessage) (quorumKey, error) {
+	var block struct {
+		Hash common.Hash `json:"hash"`
+	}
+	err := unmarshalNullable(res, &block)
+	return quorumKey{hash: block.Hash}, err
+}
+
+func receiptResultKey(res json.RawMessage) (quorumKey, error) {
+	var receipt struct {
+		BlockHash common.Hash `json:"blockHash"`
+	}
+	err := unmarshalNullable(res, &receipt)
+	return quorumKey{hash: receipt.BlockHash}, err
+}
+
+func receiptsResultKey(res json.RawMessage) (quorumKey, error) {
+	var receipts []struct {
+		BlockHash common.Hash `json:"blockHash"`
+	}
+	if err := unmarshalNullable(res, &receipts); err != nil {
+		return quorumKey{}, err
+	}
+	key := quorumKey{count: len(receipts)}
+	for i, r := range receipts {
+		if i == 0 {
+			key.hash = r.BlockHash
+		} else if r.BlockHash != key.hash {
+			return quorumKey{}, fmt.Errorf("receipt %d is of block %s, expected %s", i, r.BlockHash, key.hash)
+		}
+	}
+	return key, nil
+}
+
+// unmarshalNullable decodes res into v, leaving v unchanged if res is empty or null.
+func unmarshalNullable(res json.RawMessage, v any) error {
+	if len(res) == 0 || bytes.Equal(res, []byte("null")) {
+		return nil
+	}
+	return json.Unmarshal(res, v)
+}
@@ -0,0 +1,350 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+)
+
+var errUnavailable = errors.New("connection refused")
+
+// fakeEndpoint serves the results of its handlers, encoded to JSON like a real RPC.
+type fakeEndpoint struct {
+	lock     sync.Mutex
+	head     uint64
+	err      error
+	handlers map[string]func(args []any) (any, error)
+	calls    map[string]int
+}
+
+func newFakeEndpoint(head uint64) *fakeEndpoint {
+	return &fakeEndpoint{head: head, handlers: make(map[string]func(args []any) (any, error)), calls: make(map[string]int)}
+}
+
+func (f *fakeEndpoint) Handle(method string, fn func(args []any) (any, error)) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.handlers[method] = fn
+}
+
+func (f *fakeEndpoint) SetErr(err error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.err = err
+}
+
+func (f *fakeEndpoint) Calls(method string) int {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return f.calls[method]
+}
+
+func (f *fakeEndpoint) Close() {}
+
+func (f *fakeEndpoint) CallContext(ctx context.Context, result any, method string, args ...any) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.calls[method]++
+	if f.err != nil {
+		return f.err
+	}
+	var res any
+	if method == "eth_blockNumber" {
+		res = hexutil.Uint64(f.head)
+	} else if fn, ok := f.handlers[method]; ok {
+		var err error
+		if res, err = fn(args); err != nil {
+			return err
+		}
+	} else {
+		return &rpcError{code: -32601, msg: "method not found"}
+	}
+	data, err := json.Marshal(res)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, result)
+}
+
+func (f *fakeEndpoint) BatchCallContext(ctx context.Context, b []rpc.BatchElem) error {
+	for i := range b {
+		if err := f.CallContext(ctx, b[i].Result, b[i].Method, b[i].Args...); err != nil {
+			if errors.Is(err, f.err) {
+				return err
+			}
+			b[i].Error = err
+		}
+	}
+	return nil
+}
+
+func (f *fakeEndpoint) EthSubscribe(ctx context.Context, channel any, args ...any) (ethereum.Subscription, error) {
+	return nil, rpc.ErrNotificationsUnsupported
+}
+
+func (f *fakeEndpoint) Subscribe(ctx context.Context, namespace string, channel any, args ...any) (ethereum.Subscription, error) {
+	return nil, rpc.ErrNotificationsUnsupported
+}
+
+type rpcError struct {
+	code int
+	msg  string
+}
+
+func (e *rpcError) Error() string  { return e.msg }
+func (e *rpcError) ErrorCode() int { return e.code }
+
+type blockResult struct {
+	Hash   common.Hash    `json:"hash"`
+	Number hexutil.Uint64 `json:"number"`
+}
+
+type receiptResult struct {
+	BlockHash common.Hash `json:"blockHash"`
+	TxHash    common.Hash `json:"transactionHash"`
+}
+
+func serveBlock(hash common.Hash) func(args []any) (any, error) {
+	return func(args []any) (any, error) {
+		return &blockResult{Hash: hash, Number: 16}, nil
+	}
+}
+
+type testEndpointMetrics struct {
+	lock      sync.Mutex
+	healthy   map[string]bool
+	failovers map[string]int
+}
+
+func (m *testEndpointMetrics) RecordRPCClientEndpointHealth(endpoint string, healthy bool, head uint64) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if m.healthy == nil {
+		m.healthy = make(map[string]bool)
+	}
+	m.healthy[endpoint] = healthy
+}
+
+func (m *testEndpointMetrics) RecordRPCClientFailover(endpoint string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if m.failovers == nil {
+		m.failovers = make(map[string]int)
+	}
+	m.failovers[endpoint]++
+}
+
+func setupMultiRPC(t *testing.T, cfg MultiRPCConfig, endpoints ...*fakeEndpoint) (*MultiRPC, *testEndpointMetrics) {
+	rpcs := make([]RPC, len(endpoints))
+	for i, e := range endpoints {
+		rpcs[i] = e
+	}
+	m := &testEndpointMetrics{}
+	mr, err := NewMultiRPC(testlog.Logger(t, log.LvlInfo), rpcs, cfg, m)
+	require.NoError(t, err)
+	// Only check the endpoint health when the test calls checkHealth
+	mr.lastHealthCheck.Store(time.Now().Add(time.Hour).UnixNano())
+	t.Cleanup(mr.Close)
+	return mr, m
+}
+
+func TestMultiRPC_Failover(t *testing.T) {
+	primary, backup := newFakeEndpoint(100), newFakeEndpoint(100)
+	primary.Handle("eth_chainId", func(args []any) (any, error) { return hexutil.Uint64(1), nil })
+	backup.Handle("eth_chainId", func(args []any) (any, error) { return hexutil.Uint64(1), nil })
+	mr, m := setupMultiRPC(t, DefaultMultiRPCConfig, primary, backup)
+
+	var id hexutil.Uint64
+	require.NoError(t, mr.CallContext(context.Background(), &id, "eth_chainId"))
+	require.Equal(t, 1, primary.Calls("eth_chainId"))
+	require.Equal(t, 0, backup.Calls("eth_chainId"))
+
+	// Fails over to the backup when the primary is unavailable
+	primary.SetErr(errUnavailable)
+	require.NoError(t, mr.CallContext(context.Background(), &id, "eth_chainId"))
+	require.Equal(t, 1, backup.Calls("eth_chainId"))
+	require.Equal(t, map[string]int{"0": 1}, m.failovers)
+
+	// Stays on the backup until the primary is healthy again
+	primary.SetErr(nil)
+	require.NoError(t, mr.CallContext(context.Background(), &id, "eth_chainId"))
+	require.Equal(t, 2, primary.Calls("eth_chainId"), "one successful and one failed call")
+	require.Equal(t, 2, backup.Calls("eth_chainId"))
+	mr.checkHealth(context.Background())
+	require.Equal(t, map[string]bool{"0": true, "1": true}, m.healthy)
+	require.NoError(t, mr.CallContext(context.Background(), &id, "eth_chainId"))
+	require.Equal(t, 3, primary.Calls("eth_chainId"))
+
+	// Errors returned by the RPC server are not failed over
+	require.ErrorContains(t, mr.CallContext(context.Background(), &id, "eth_unknown"), "method not found")
+	require.Equal(t, 0, backup.Calls("eth_unknown"))
+
+	// Unhealthy endpoints are still tried, if all endpoints are unhealthy
+	primary.SetErr(errUnavailable)
+	backup.SetErr(errUnavailable)
+	require.ErrorIs(t, mr.CallContext(context.Background(), &id, "eth_chainId"), errUnavailable)
+	primary.SetErr(nil)
+	require.NoError(t, mr.CallContext(context.Background(), &id, "eth_chainId"))
+}
+
+func TestMultiRPC_FailoverOnLag(t *testing.T) {
+	primary, backup := newFakeEndpoint(100), newFakeEndpoint(111)
+	primary.Handle("eth_chainId", func(args []any) (any, error) { return hexutil.Uint64(1), nil })
+	backup.Handle("eth_chainId", func(args []any) (any, error) { return hexutil.Uint64(1), nil })
+	cfg := DefaultMultiRPCConfig
+	cfg.MaxLagBlocks = 10
+	mr, m := setupMultiRPC(t, cfg, primary, backup)
+
+	mr.checkHealth(context.Background())
+	require.Equal(t, map[string]bool{"0": false, "1": true}, m.healthy)
+	var id hexutil.Uint64
+	require.NoError(t, mr.CallContext(context.Background(), &id, "eth_chainId"))
+	require.Equal(t, 0, primary.Calls("eth_chainId"))
+	require.Equal(t, 1, backup.Calls("eth_chainId"))
+
+	primary.head = 101
+	mr.checkHealth(context.Background())
+	require.Equal(t, map[string]bool{"0": true, "1": true}, m.healthy)
+	require.NoError(t, mr.CallContext(context.Background(), &id, "eth_chainId"))
+	require.Equal(t, 1, primary.Calls("eth_chainId"))
+}
+
+func TestMultiRPC_Quorum(t *testing.T) {
+	a, b, c := newFakeEndpoint(100), newFakeEndpoint(100), newFakeEndpoint(100)
+	canonical, reorged := common.Hash{0xaa}, common.Hash{0xbb}
+	a.Handle("eth_getBlockByNumber", serveBlock(canonical))
+	b.Handle("eth_getBlockByNumber", serveBlock(canonical))
+	c.Handle("eth_getBlockByNumber", serveBlock(reorged))
+	cfg := DefaultMultiRPCConfig
+	cfg.Quorum = 2
+	mr, _ := setupMultiRPC(t, cfg, a, b, c)
+
+	var block *blockResult
+	require.NoError(t, mr.CallContext(context.Background(), &block, "eth_getBlockByNumber", "0x10", false))
+	require.Equal(t, canonical, block.Hash)
+	require.Equal(t, 1, a.Calls("eth_getBlockByNumber"))
+	require.Equal(t, 1, b.Calls("eth_getBlockByNumber"))
+	require.Equal(t, 0, c.Calls("eth_getBlockByNumber"))
+
+	// Blocks by label are not checked for quorum
+	require.NoError(t, mr.CallContext(context.Background(), &block, "eth_getBlockByNumber", "latest", false))
+	require.Equal(t, 2, a.Calls("eth_getBlockByNumber"))
+	require.Equal(t, 1, b.Calls("eth_getBlockByNumber"))
+
+	// Fails if the endpoints disagree
+	b.SetErr(errUnavailable)
+	require.ErrorIs(t, mr.CallContext(context.Background(), &block, "eth_getBlockByNumber", "0x10", false), errUnavailable)
+	require.ErrorIs(t, mr.CallContext(context.Background(), &block, "eth_getBlockByNumber", "0x10", false), ErrNoQuorum)
+
+	// Fails if not enough endpoints are healthy
+	c.SetErr(errUnavailable)
+	require.Error(t, mr.CallContext(context.Background(), &block, "eth_getBlockByNumber", "0x10", false))
+	require.ErrorIs(t, mr.CallContext(context.Background(), &block, "eth_getBlockByNumber", "0x10", false), ErrNoQuorum)
+}
+
+func TestMultiRPC_QuorumBatch(t *testing.T) {
+	a, b := newFakeEndpoint(100), newFakeEndpoint(100)
+	blockHash := common.Hash{0xaa}
+	serveReceipt := func(blockHash common.Hash) func(args []any) (any, error) {
+		return func(args []any) (any, error) {
+			return &receiptResult{BlockHash: blockHash, TxHash: args[0].(common.Hash)}, nil
+		}
+	}
+	a.Handle("eth_getTransactionReceipt", serveReceipt(blockHash))
+	b.Handle("eth_getTransactionReceipt", serveReceipt(blockHash))
+	cfg := DefaultMultiRPCConfig
+	cfg.Quorum = 2
+	mr, _ := setupMultiRPC(t, cfg, a, b)
+
+	batch := func() ([]rpc.BatchElem, []*receiptResult) {
+		receipts := make([]*receiptResult, 2)
+		elems := make([]rpc.BatchElem, 2)
+		for i := range elems {
+			elems[i] = rpc.BatchElem{Method: "eth_getTransactionReceipt", Args: []any{common.Hash{byte(i)}}, Result: &receipts[i]}
+		}
+		return elems, receipts
+	}
+	elems, receipts := batch()
+	require.NoError(t, mr.BatchCallContext(context.Background(), elems))
+	for i, elem := range elems {
+		require.NoError(t, elem.Error)
+		require.Equal(t, &receiptResult{BlockHash: blockHash, TxHash: common.Hash{byte(i)}}, receipts[i])
+	}
+
+	b.Handle("eth_getTransactionReceipt", serveReceipt(common.Hash{0xbb}))
+	elems, _ = batch()
+	require.NoError(t, mr.BatchCallContext(context.Background(), elems))
+	for _, elem := range elems {
+		require.ErrorIs(t, elem.Error, ErrNoQuorum)
+	}
+}
+
+func TestGethRPCClient(t *testing.T) {
+	endpoint := newFakeEndpoint(100)
+	blockHash := common.Hash{0xaa}
+	endpoint.Handle("eth_getBlockByNumber", serveBlock(blockHash))
+	cl, err := NewGethRPCClient(endpoint)
+	require.NoError(t, err)
+	defer cl.Close()
+
+	var block *blockResult
+	require.NoError(t, cl.CallContext(context.Background(), &block, "eth_getBlockByNumber", "0x10", false))
+	require.Equal(t, &blockResult{Hash: blockHash, Number: 16}, block)
+
+	var head hexutil.Uint64
+	elems := []rpc.BatchElem{
+		{Method: "eth_blockNumber", Result: &head},
+		{Method: "eth_unknown", Result: new(any)},
+	}
+	require.NoError(t, cl.BatchCallContext(context.Background(), elems))
+	require.NoError(t, elems[0].Error)
+	require.Equal(t, hexutil.Uint64(100), head)
+	var rpcErr rpc.Error
+	require.ErrorAs(t, elems[1].Error, &rpcErr)
+	require.Equal(t, -32601, rpcErr.ErrorCode())
+
+	endpoint.SetErr(errUnavailable)
+	require.ErrorIs(t, cl.CallContext(context.Background(), &block, "eth_getBlockByNumber", "0x10", false), errUnavailable)
+	elems = []rpc.BatchElem{{Method: "eth_blockNumber", Result: &head}}
+	require.ErrorIs(t, cl.BatchCallContext(context.Background(), elems), errUnavailable)
+}
+
+func TestGethRPCClientSubscriptions(t *testing.T) {
+	endpoint := newFakeEndpoint(100)
+	cl, err := NewGethRPCClient(endpoint)
+	require.NoError(t, err)
+	defer cl.Close()
+
+	_, err = cl.EthSubscribe(context.Background(), make(chan any), "newHeads")
+	require.ErrorIs(t, err, rpc.ErrNotificationsUnsupported)
+
+	// Subscription methods that are called directly are not forwarded to the endpoint.
+	var id string
+	var rpcErr rpc.Error
+	require.ErrorAs(t, cl.CallContext(context.Background(), &id, "eth_subscribe", "newHeads"), &rpcErr)
+	require.Equal(t, -32601, rpcErr.ErrorCode())
+	var head hexutil.Uint64
+	elems := []rpc.BatchElem{
+		{Method: "eth_unsubscribe", Args: []any{"0x1"}, Result: new(bool)},
+		{Method: "eth_blockNumber", Result: &head},
+	}
+	require.NoError(t, cl.BatchCallContext(context.Background(), elems))
+	require.ErrorAs(t, elems[0].Error, &rpcErr)
+	require.Equal(t, -32601, rpcErr.ErrorCode())
+	require.NoError(t, elems[1].Error)
+	require.Equal(t, hexutil.Uint64(100), head)
+	require.Zero(t, endpoint.Calls("eth_subscribe"))
+	require.Zero(t, endpoint.Calls("eth_unsubscribe"))
+}
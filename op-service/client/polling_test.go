@@ -66,6 +66,11 @@ func (m *MockRPC) EthSubscribe(ctx context.Context, channel any, args ...any) (e
 	return nil, nil
 }
 
+func (m *MockRPC) Subscribe(ctx context.Context, namespace string, channel any, args ...any) (ethereum.Subscription, error) {
+	m.t.Fatal("Subscribe should not be called")
+	return nil, nil
+}
+
 func (m *MockRPC) popResult() {
 	m.mtx.Lock()
 	defer m.mtx.Unlock()
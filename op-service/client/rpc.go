@@ -15,6 +15,7 @@ import (
 	"golang.org/x/time/rate"
 
 	"github.com/ethereum-optimism/optimism/op-node/metrics"
+	opmetrics "github.com/ethereum-optimism/optimism/op-service/metrics"
 	"github.com/ethereum/go-ethereum/rpc"
 )
 
@@ -25,6 +26,7 @@ type RPC interface {
 	CallContext(ctx context.Context, result any, method string, args ...any) error
 	BatchCallContext(ctx context.Context, b []rpc.BatchElem) error
 	EthSubscribe(ctx context.Context, channel any, args ...any) (ethereum.Subscription, error)
+	Subscribe(ctx context.Context, namespace string, channel any, args ...any) (ethereum.Subscription, error)
 }
 
 type rpcConfig struct {
@@ -33,6 +35,8 @@ type rpcConfig struct {
 	backoffAttempts  int
 	limit            float64
 	burst            int
+	multiRPC         *MultiRPCConfig
+	endpointMetrics  opmetrics.RPCEndpointMetricer
 }
 
 type RPCOption func(cfg *rpcConfig) error
@@ -72,7 +76,26 @@ func WithRateLimit(rateLimit float64, burst int) RPCOption {
 	}
 }
 
+// WithMultiRPCConfig configures the failover and quorum behavior, if the RPC url lists several endpoints.
+// See NewMultiRPC for more details.
+func WithMultiRPCConfig(multiCfg MultiRPCConfig) RPCOption {
+	return func(cfg *rpcConfig) error {
+		cfg.multiRPC = &multiCfg
+		return nil
+	}
+}
+
+// WithRPCEndpointMetrics configures the metrics to record the endpoint health with, if the RPC url lists several
+// endpoints.
+func WithRPCEndpointMetrics(m opmetrics.RPCEndpointMetricer) RPCOption {
+	return func(cfg *rpcConfig) error {
+		cfg.endpointMetrics = m
+		return nil
+	}
+}
+
 // NewRPC returns the correct client.RPC instance for a given RPC url.
+// The url may list several comma-separated endpoints of the same chain, to fail over between with a MultiRPC.
 func NewRPC(ctx context.Context, lgr log.Logger, addr string, opts ...RPCOption) (RPC, error) {
 	var cfg rpcConfig
 	for i, opt := range opts {
@@ -85,6 +108,35 @@ func NewRPC(ctx context.Context, lgr log.Logger, addr string, opts ...RPCOption)
 		cfg.backoffAttempts = 1
 	}
 
+	if addrs := SplitAddrs(addr); len(addrs) > 1 {
+		return newMultiRPC(ctx, lgr, addrs, &cfg)
+	}
+	return newRPC(ctx, lgr, addr, &cfg)
+}
+
+func newMultiRPC(ctx context.Context, lgr log.Logger, addrs []string, cfg *rpcConfig) (RPC, error) {
+	multiCfg := DefaultMultiRPCConfig
+	if cfg.multiRPC != nil {
+		multiCfg = *cfg.multiRPC
+	}
+	if err := multiCfg.Check(len(addrs)); err != nil {
+		return nil, fmt.Errorf("invalid multi-RPC config: %w", err)
+	}
+	var endpoints []RPC
+	for i, addr := range addrs {
+		endpoint, err := newRPC(ctx, lgr.New("endpoint", i), addr, cfg)
+		if err != nil {
+			for _, e := range endpoints {
+				e.Close()
+			}
+			return nil, fmt.Errorf("failed to dial endpoint %d: %w", i, err)
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+	return NewMultiRPC(lgr, endpoints, multiCfg, cfg.endpointMetrics)
+}
+
+func newRPC(ctx context.Context, lgr log.Logger, addr string, cfg *rpcConfig) (RPC, error) {
 	underlying, err := dialRPCClientWithBackoff(ctx, lgr, addr, cfg.backoffAttempts, cfg.gethRPCOptions...)
 	if err != nil {
 		return nil, err
@@ -179,6 +231,10 @@ func (b *BaseRPCClient) EthSubscribe(ctx context.Context, channel any, args ...a
 	return b.c.EthSubscribe(ctx, channel, args...)
 }
 
+func (b *BaseRPCClient) Subscribe(ctx context.Context, namespace string, channel any, args ...any) (ethereum.Subscription, error) {
+	return b.c.Subscribe(ctx, namespace, channel, args...)
+}
+
 // InstrumentedRPCClient is an RPC client that tracks
 // Prometheus metrics for each call.
 type InstrumentedRPCClient struct {
@@ -214,6 +270,10 @@ func (ic *InstrumentedRPCClient) EthSubscribe(ctx context.Context, channel any,
 	return ic.c.EthSubscribe(ctx, channel, args...)
 }
 
+func (ic *InstrumentedRPCClient) Subscribe(ctx context.Context, namespace string, channel any, args ...any) (ethereum.Subscription, error) {
+	return ic.c.Subscribe(ctx, namespace, channel, args...)
+}
+
 // instrumentBatch handles metrics for batch calls. Request metrics are
 // increased for each batch element. Request durations are tracked for
 // the batch as a whole using a special <batch> method. Errors are tracked
@@ -0,0 +1,84 @@
+package dacommit
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// MaxInputSize is the maximum size of the data that is stored for a single commitment.
+const MaxInputSize = 10_000_000
+
+// DAClient stores and retrieves data on a DA server.
+//
+// The DA server protocol is:
+//   - PUT /put/<commitment> stores the request body, which must match the commitment.
+//   - GET /get/<commitment> returns the data of the commitment, or status 404 if it is unknown.
+//
+// Commitments are 0x-prefixed hex strings.
+type DAClient struct {
+	url    string
+	client *http.Client
+}
+
+func NewDAClient(url string) *DAClient {
+	return &DAClient{
+		url:    strings.TrimSuffix(url, "/"),
+		client: &http.Client{},
+	}
+}
+
+// GetInput returns the data of the commitment. It returns ErrNotFound if the
+// DA server does not have it, and verifies the returned data against the commitment.
+func (c *DAClient) GetInput(ctx context.Context, comm Commitment) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/get/%s", c.url, comm), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create get request: %w", err)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	} else if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get input %s: status %d", comm, resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, MaxInputSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read input %s: %w", comm, err)
+	}
+	if err := comm.Verify(data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// SetInput stores the data on the DA server and returns its commitment.
+func (c *DAClient) SetInput(ctx context.Context, data []byte) (Commitment, error) {
+	if len(data) == 0 {
+		return Commitment{}, fmt.Errorf("cannot store empty input")
+	}
+	if len(data) > MaxInputSize {
+		return Commitment{}, fmt.Errorf("input of %d bytes exceeds the maximum input size %d", len(data), MaxInputSize)
+	}
+	comm := ComputeCommitment(data)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, fmt.Sprintf("%s/put/%s", c.url, comm), bytes.NewReader(data))
+	if err != nil {
+		return Commitment{}, fmt.Errorf("failed to create put request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return Commitment{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return Commitment{}, fmt.Errorf("failed to store input %s: status %d", comm, resp.StatusCode)
+	}
+	return comm, nil
+}
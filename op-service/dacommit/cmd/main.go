@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/urfave/cli/v2"
+
+	"github.com/ethereum-optimism/optimism/op-service/cliapp"
+	"github.com/ethereum-optimism/optimism/op-service/dacommit"
+	oplog "github.com/ethereum-optimism/optimism/op-service/log"
+	"github.com/ethereum-optimism/optimism/op-service/opio"
+)
+
+const envVarPrefix = "DA_SERVER"
+
+var (
+	ListenAddrFlag = &cli.StringFlag{
+		Name:    "addr",
+		Usage:   "DA server listening address",
+		Value:   "127.0.0.1",
+		EnvVars: []string{envVarPrefix + "_ADDR"},
+	}
+	PortFlag = &cli.IntFlag{
+		Name:    "port",
+		Usage:   "DA server listening port",
+		Value:   3100,
+		EnvVars: []string{envVarPrefix + "_PORT"},
+	}
+	FileStorePathFlag = &cli.StringFlag{
+		Name:     "file.path",
+		Usage:    "Directory to store the data of all commitments in",
+		Required: true,
+		EnvVars:  []string{envVarPrefix + "_FILE_PATH"},
+	}
+)
+
+// main runs the reference DA server, which stores the data of every commitment in a file.
+func main() {
+	oplog.SetupDefaults()
+
+	app := cli.NewApp()
+	app.Flags = cliapp.ProtectFlags(append([]cli.Flag{ListenAddrFlag, PortFlag, FileStorePathFlag}, oplog.CLIFlags(envVarPrefix)...))
+	app.Name = "da-server"
+	app.Usage = "DA commitment server"
+	app.Description = "Reference DA server that stores batch data by its commitment on the local file system"
+	app.Action = run
+
+	if err := app.Run(os.Args); err != nil {
+		log.Crit("Application failed", "message", err)
+	}
+}
+
+func run(cliCtx *cli.Context) error {
+	logger := oplog.NewLogger(oplog.AppOut(cliCtx), oplog.ReadCLIConfig(cliCtx))
+	oplog.SetGlobalLogHandler(logger.GetHandler())
+
+	store, err := dacommit.NewFileStore(cliCtx.String(FileStorePathFlag.Name))
+	if err != nil {
+		return err
+	}
+	server := dacommit.NewDAServer(logger, store)
+	addr := net.JoinHostPort(cliCtx.String(ListenAddrFlag.Name), strconv.Itoa(cliCtx.Int(PortFlag.Name)))
+	if err := server.Start(addr); err != nil {
+		return err
+	}
+	opio.BlockOnInterrupts()
+	if err := server.Stop(context.Background()); err != nil {
+		return fmt.Errorf("failed to stop DA server: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package dacommit
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// TxDataVersion is the version byte of batcher transactions that carry a DA commitment
+// instead of frames. Regular batcher transactions use derivation version 0.
+const TxDataVersion byte = 0x01
+
+var (
+	// ErrNotFound is returned when the DA server does not have the data of a commitment.
+	ErrNotFound = errors.New("not found")
+	// ErrCommitmentMismatch is returned when data does not match its commitment.
+	ErrCommitmentMismatch = errors.New("commitment mismatch")
+)
+
+// Commitment is the keccak256 hash of the data that is stored on the DA server.
+type Commitment common.Hash
+
+// ComputeCommitment returns the commitment to the given data.
+func ComputeCommitment(data []byte) Commitment {
+	return Commitment(crypto.Keccak256Hash(data))
+}
+
+// DecodeCommitment decodes the commitment of the data of a batcher transaction,
+// including the version byte.
+func DecodeCommitment(txData []byte) (Commitment, error) {
+	if len(txData) != 1+common.HashLength {
+		return Commitment{}, fmt.Errorf("invalid DA commitment length %d", len(txData))
+	}
+	if txData[0] != TxDataVersion {
+		return Commitment{}, fmt.Errorf("invalid DA commitment version %d", txData[0])
+	}
+	return Commitment(common.BytesToHash(txData[1:])), nil
+}
+
+// TxData returns the data of a batcher transaction that carries the commitment.
+func (c Commitment) TxData() []byte {
+	return append([]byte{TxDataVersion}, c[:]...)
+}
+
+// Verify returns ErrCommitmentMismatch if the data does not match the commitment.
+func (c Commitment) Verify(data []byte) error {
+	if ComputeCommitment(data) != c {
+		return fmt.Errorf("%w: expected %s", ErrCommitmentMismatch, c)
+	}
+	return nil
+}
+
+func (c Commitment) String() string {
+	return common.Hash(c).String()
+}
+
+func (c Commitment) MarshalText() ([]byte, error) {
+	return common.Hash(c).MarshalText()
+}
+
+func (c *Commitment) UnmarshalText(text []byte) error {
+	return (*common.Hash)(c).UnmarshalText(text)
+}
@@ -0,0 +1,32 @@
+package dacommit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommitmentRoundTrip(t *testing.T) {
+	data := []byte("batch data")
+	comm := ComputeCommitment(data)
+	require.NoError(t, comm.Verify(data))
+	require.ErrorIs(t, comm.Verify([]byte("other data")), ErrCommitmentMismatch)
+
+	txData := comm.TxData()
+	require.Equal(t, TxDataVersion, txData[0])
+	decoded, err := DecodeCommitment(txData)
+	require.NoError(t, err)
+	require.Equal(t, comm, decoded)
+
+	_, err = DecodeCommitment(txData[:len(txData)-1])
+	require.ErrorContains(t, err, "invalid DA commitment length")
+	txData[0] = 0x00
+	_, err = DecodeCommitment(txData)
+	require.ErrorContains(t, err, "invalid DA commitment version")
+
+	text, err := comm.MarshalText()
+	require.NoError(t, err)
+	var unmarshaled Commitment
+	require.NoError(t, unmarshaled.UnmarshalText(text))
+	require.Equal(t, comm, unmarshaled)
+}
@@ -0,0 +1,63 @@
+package dacommit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+var _ KVStore = (*FileStore)(nil)
+
+// FileStore is a KVStore that stores the data of every commitment in its own file.
+// It is the reference implementation of the DA server storage, used to run the DA server locally.
+type FileStore struct {
+	directory string
+}
+
+func NewFileStore(directory string) (*FileStore, error) {
+	if err := os.MkdirAll(directory, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create DA store directory %v: %w", directory, err)
+	}
+	return &FileStore{directory: directory}, nil
+}
+
+func (s *FileStore) Get(_ context.Context, comm Commitment) ([]byte, error) {
+	data, err := os.ReadFile(s.fileName(comm))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read input %s: %w", comm, err)
+	}
+	return data, nil
+}
+
+// Put writes the data to a temp file first, before renaming it into place,
+// so a crash never leaves partially written data behind.
+func (s *FileStore) Put(_ context.Context, comm Commitment, data []byte) error {
+	file := s.fileName(comm)
+	tmpFile := file + ".tmp"
+	f, err := os.OpenFile(tmpFile, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return fmt.Errorf("open file (%v) for writing: %w", tmpFile, err)
+	}
+	defer f.Close() // Ensure file is closed even if write or sync fails
+	if _, err := f.Write(data); err != nil {
+		return fmt.Errorf("write input to temp file (%v): %w", tmpFile, err)
+	}
+	if err := f.Sync(); err != nil {
+		return fmt.Errorf("sync temp file (%v): %w", tmpFile, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("close temp file (%v): %w", tmpFile, err)
+	}
+	if err := os.Rename(tmpFile, file); err != nil {
+		return fmt.Errorf("rename temp file to final destination: %w", err)
+	}
+	return nil
+}
+
+func (s *FileStore) fileName(comm Commitment) string {
+	return filepath.Join(s.directory, comm.String())
+}
@@ -0,0 +1,117 @@
+package dacommit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/ethereum-optimism/optimism/op-service/httputil"
+)
+
+// KVStore is the storage backend of the DA server.
+type KVStore interface {
+	// Get returns the data of the commitment, or ErrNotFound if it is unknown.
+	Get(ctx context.Context, comm Commitment) ([]byte, error)
+	// Put stores the data of the commitment. The data has already been verified against the commitment.
+	Put(ctx context.Context, comm Commitment, data []byte) error
+}
+
+// DAServer serves the DA server protocol of the DAClient on top of a KVStore.
+type DAServer struct {
+	log   log.Logger
+	store KVStore
+
+	httpServer *httputil.HTTPServer
+}
+
+func NewDAServer(log log.Logger, store KVStore) *DAServer {
+	return &DAServer{log: log, store: store}
+}
+
+// Start starts serving the DA server protocol on the given address.
+func (s *DAServer) Start(addr string) error {
+	srv, err := httputil.StartHTTPServer(addr, s)
+	if err != nil {
+		return fmt.Errorf("failed to start DA server: %w", err)
+	}
+	s.httpServer = srv
+	s.log.Info("Started DA server", "addr", srv.Addr())
+	return nil
+}
+
+// Addr returns the address the DA server is listening on. It must be started first.
+func (s *DAServer) Addr() string {
+	return s.httpServer.Addr().String()
+}
+
+func (s *DAServer) Stop(ctx context.Context) error {
+	if s.httpServer == nil {
+		return nil
+	}
+	return s.httpServer.Stop(ctx)
+}
+
+func (s *DAServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/get/"):
+		s.handleGet(w, r, strings.TrimPrefix(r.URL.Path, "/get/"))
+	case r.Method == http.MethodPut && strings.HasPrefix(r.URL.Path, "/put/"):
+		s.handlePut(w, r, strings.TrimPrefix(r.URL.Path, "/put/"))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (s *DAServer) handleGet(w http.ResponseWriter, r *http.Request, key string) {
+	var comm Commitment
+	if err := comm.UnmarshalText([]byte(key)); err != nil {
+		http.Error(w, fmt.Sprintf("invalid commitment: %v", err), http.StatusBadRequest)
+		return
+	}
+	data, err := s.store.Get(r.Context(), comm)
+	if errors.Is(err, ErrNotFound) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	} else if err != nil {
+		s.log.Error("Failed to get input", "commitment", comm, "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/octet-stream")
+	if _, err := w.Write(data); err != nil {
+		s.log.Warn("Failed to write input", "commitment", comm, "err", err)
+	}
+}
+
+func (s *DAServer) handlePut(w http.ResponseWriter, r *http.Request, key string) {
+	var comm Commitment
+	if err := comm.UnmarshalText([]byte(key)); err != nil {
+		http.Error(w, fmt.Sprintf("invalid commitment: %v", err), http.StatusBadRequest)
+		return
+	}
+	data, err := io.ReadAll(io.LimitReader(r.Body, MaxInputSize+1))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to read input: %v", err), http.StatusBadRequest)
+		return
+	}
+	if len(data) == 0 || len(data) > MaxInputSize {
+		http.Error(w, fmt.Sprintf("input size must be in range [1, %d]", MaxInputSize), http.StatusBadRequest)
+		return
+	}
+	if err := comm.Verify(data); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := s.store.Put(r.Context(), comm, data); err != nil {
+		s.log.Error("Failed to store input", "commitment", comm, "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	s.log.Debug("Stored input", "commitment", comm, "size", len(data))
+	w.WriteHeader(http.StatusOK)
+}
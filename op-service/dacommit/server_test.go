@@ -0,0 +1,62 @@
+package dacommit
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+)
+
+func TestDAServerFileStore(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewFileStore(t.TempDir())
+	require.NoError(t, err)
+	server := NewDAServer(testlog.Logger(t, log.LvlInfo), store)
+	require.NoError(t, server.Start("127.0.0.1:0"))
+	t.Cleanup(func() {
+		require.NoError(t, server.Stop(context.Background()))
+	})
+	url := "http://" + server.Addr()
+	client := NewDAClient(url)
+
+	data := []byte("batch data")
+	_, err = client.GetInput(ctx, ComputeCommitment(data))
+	require.ErrorIs(t, err, ErrNotFound)
+
+	comm, err := client.SetInput(ctx, data)
+	require.NoError(t, err)
+	require.Equal(t, ComputeCommitment(data), comm)
+	stored, err := client.GetInput(ctx, comm)
+	require.NoError(t, err)
+	require.Equal(t, data, stored)
+
+	// storing the same input again is fine
+	_, err = client.SetInput(ctx, data)
+	require.NoError(t, err)
+
+	// a new server on the same store serves the persisted data
+	restarted := NewDAServer(testlog.Logger(t, log.LvlInfo), store)
+	require.NoError(t, restarted.Start("127.0.0.1:0"))
+	t.Cleanup(func() {
+		require.NoError(t, restarted.Stop(context.Background()))
+	})
+	stored, err = NewDAClient("http://"+restarted.Addr()).GetInput(ctx, comm)
+	require.NoError(t, err)
+	require.Equal(t, data, stored)
+
+	// the server rejects data that does not match the commitment
+	req, err := http.NewRequest(http.MethodPut, url+"/put/"+ComputeCommitment([]byte("other")).String(), bytes.NewReader(data))
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	_, err = client.SetInput(ctx, nil)
+	require.Error(t, err)
+}
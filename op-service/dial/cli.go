@@ -0,0 +1,72 @@
+package dial
+
+import (
+	"errors"
+
+	opservice "github.com/ethereum-optimism/optimism/op-service"
+	"github.com/ethereum-optimism/optimism/op-service/client"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	L1MaxLagBlocksFlagName = "l1-max-lag-blocks"
+	L1QuorumFlagName       = "l1-quorum"
+)
+
+func DefaultCLIConfig() CLIConfig {
+	return CLIConfig{
+		L1MaxLagBlocks: client.DefaultMultiRPCConfig.MaxLagBlocks,
+		L1Quorum:       client.DefaultMultiRPCConfig.Quorum,
+	}
+}
+
+// CLIFlags are the flags of the L1 endpoints, if several comma-separated L1 endpoints are configured.
+func CLIFlags(envPrefix string) []cli.Flag {
+	return []cli.Flag{
+		&cli.Uint64Flag{
+			Name:    L1MaxLagBlocksFlagName,
+			Usage:   "Number of blocks an L1 endpoint may lag behind the other L1 endpoints, before requests fail over to another endpoint. 0 disables the lag check. Only used with several L1 endpoints.",
+			Value:   client.DefaultMultiRPCConfig.MaxLagBlocks,
+			EnvVars: opservice.PrefixEnvVar(envPrefix, "L1_MAX_LAG_BLOCKS"),
+		},
+		&cli.IntFlag{
+			Name:    L1QuorumFlagName,
+			Usage:   "Number of L1 endpoints that must agree on the hash of L1 blocks fetched by number, and of L1 receipts. Only used with several L1 endpoints.",
+			Value:   client.DefaultMultiRPCConfig.Quorum,
+			EnvVars: opservice.PrefixEnvVar(envPrefix, "L1_QUORUM"),
+		},
+	}
+}
+
+type CLIConfig struct {
+	L1MaxLagBlocks uint64
+	// L1Quorum of 0 is the same as 1.
+	L1Quorum int
+}
+
+// Check checks the config independently of the number of L1 endpoints,
+// which is checked when the endpoints are dialed.
+func (c CLIConfig) Check() error {
+	if c.L1Quorum < 0 {
+		return errors.New("L1 quorum must not be negative")
+	}
+	return nil
+}
+
+// MultiRPCConfig returns the config of the client that fails over between the L1 endpoints.
+func (c CLIConfig) MultiRPCConfig() client.MultiRPCConfig {
+	cfg := client.DefaultMultiRPCConfig
+	cfg.MaxLagBlocks = c.L1MaxLagBlocks
+	if c.L1Quorum != 0 {
+		cfg.Quorum = c.L1Quorum
+	}
+	return cfg
+}
+
+func ReadCLIConfig(ctx *cli.Context) CLIConfig {
+	return CLIConfig{
+		L1MaxLagBlocks: ctx.Uint64(L1MaxLagBlocksFlagName),
+		L1Quorum:       ctx.Int(L1QuorumFlagName),
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/ethereum-optimism/optimism/op-service/client"
+	opmetrics "github.com/ethereum-optimism/optimism/op-service/metrics"
 	"github.com/ethereum-optimism/optimism/op-service/retry"
 	"github.com/ethereum-optimism/optimism/op-service/sources"
 	"github.com/ethereum/go-ethereum/ethclient"
@@ -18,19 +19,71 @@ const DefaultDialTimeout = 1 * time.Minute
 const defaultRetryCount = 30
 const defaultRetryTime = 2 * time.Second
 
+// EthClient is an ethclient.Client that also closes the endpoints it sends its requests to when it is closed.
+type EthClient struct {
+	*ethclient.Client
+	endpoints client.RPC
+}
+
+// Close closes the client, and the endpoints it fails over between.
+func (c *EthClient) Close() {
+	c.Client.Close()
+	if c.endpoints != nil {
+		c.endpoints.Close()
+	}
+}
+
 // DialEthClientWithTimeout attempts to dial the L1 provider using the provided
 // URL. If the dial doesn't complete within defaultDialTimeout seconds, this
 // method will return an error.
-func DialEthClientWithTimeout(ctx context.Context, timeout time.Duration, log log.Logger, url string) (*ethclient.Client, error) {
+// The URL may list several comma-separated endpoints of the same chain, to fail over between,
+// with the default client.MultiRPCConfig, see DialMultiEthClientWithTimeout.
+func DialEthClientWithTimeout(ctx context.Context, timeout time.Duration, log log.Logger, url string) (*EthClient, error) {
+	return DialMultiEthClientWithTimeout(ctx, timeout, log, url, client.DefaultMultiRPCConfig, nil)
+}
+
+// DialMultiEthClientWithTimeout is like DialEthClientWithTimeout, and fails over between the endpoints with the given
+// config and metrics if the URL lists several comma-separated endpoints, see client.MultiRPC.
+// Like with client.NewRPC, the endpoints may be HTTP or websocket endpoints, but the combined client
+// does not support subscriptions. The metrics may be nil.
+func DialMultiEthClientWithTimeout(ctx context.Context, timeout time.Duration, log log.Logger, url string, cfg client.MultiRPCConfig, m opmetrics.RPCEndpointMetricer) (*EthClient, error) {
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
-	c, err := dialRPCClientWithBackoff(ctx, log, url)
+	addrs := client.SplitAddrs(url)
+	if len(addrs) <= 1 {
+		c, err := dialRPCClientWithBackoff(ctx, log, url)
+		if err != nil {
+			return nil, err
+		}
+		return &EthClient{Client: ethclient.NewClient(c)}, nil
+	}
+
+	endpoints := make([]client.RPC, 0, len(addrs))
+	closeEndpoints := func() {
+		for _, e := range endpoints {
+			e.Close()
+		}
+	}
+	for i, addr := range addrs {
+		c, err := dialRPCClientWithBackoff(ctx, log, addr)
+		if err != nil {
+			closeEndpoints()
+			return nil, fmt.Errorf("failed to dial endpoint %d: %w", i, err)
+		}
+		endpoints = append(endpoints, client.NewBaseRPCClient(c))
+	}
+	multi, err := client.NewMultiRPC(log, endpoints, cfg, m)
 	if err != nil {
+		closeEndpoints()
 		return nil, err
 	}
-
-	return ethclient.NewClient(c), nil
+	c, err := client.NewGethRPCClient(multi)
+	if err != nil {
+		multi.Close()
+		return nil, err
+	}
+	return &EthClient{Client: ethclient.NewClient(c), endpoints: multi}, nil
 }
 
 // DialRollupClientWithTimeout attempts to dial the RPC provider using the provided URL.
@@ -0,0 +1,193 @@
+package eth
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"math/bits"
+	"reflect"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+const (
+	BlobSize             = 4096 * 32
+	FieldElementsPerBlob = 4096
+	// Every field element is prefixed with a zero byte to stay below the BLS modulus,
+	// leaving 31 usable bytes per field element.
+	usableBytesPerFieldElement = 31
+	// The first 4 usable bytes encode the version and the length of the data.
+	blobHeaderSize  = 4
+	MaxBlobDataSize = FieldElementsPerBlob*usableBytesPerFieldElement - blobHeaderSize
+
+	BlobEncodingVersion = 0
+
+	// MaxBlobsPerBlobTx is the maximum number of blobs a single transaction can carry,
+	// bounded by the maximum blob gas per block.
+	MaxBlobsPerBlobTx = params.MaxBlobGasPerBlock / params.BlobTxBlobGasPerBlob
+
+	// VersionedHashVersionKZG is the version byte of versioned hashes derived from KZG commitments (EIP-4844).
+	VersionedHashVersionKZG = 0x01
+)
+
+var (
+	ErrBlobInvalidFieldElement        = errors.New("invalid field element")
+	ErrBlobInvalidEncodingVersion     = errors.New("invalid encoding version")
+	ErrBlobInvalidLength              = errors.New("invalid length for blob")
+	ErrBlobInputTooLarge              = errors.New("too much data to encode in one blob")
+	ErrBlobExtraneousDataAfterPayload = errors.New("non-zero data encountered after the blob payload")
+)
+
+// Blob is the raw data of an EIP-4844 blob: 4096 field elements of 32 bytes each.
+type Blob [BlobSize]byte
+
+func (b *Blob) KZGBlob() *kzg4844.Blob {
+	return (*kzg4844.Blob)(b)
+}
+
+func (b *Blob) UnmarshalJSON(text []byte) error {
+	return hexutil.UnmarshalFixedJSON(reflect.TypeOf(b), text, b[:])
+}
+
+func (b *Blob) UnmarshalText(text []byte) error {
+	return hexutil.UnmarshalFixedText("Blob", text, b[:])
+}
+
+func (b *Blob) MarshalText() ([]byte, error) {
+	return hexutil.Bytes(b[:]).MarshalText()
+}
+
+func (b *Blob) String() string {
+	return hexutil.Encode(b[:])
+}
+
+// TerminalString implements log.TerminalStringer, formatting a string for console
+// output during logging.
+func (b *Blob) TerminalString() string {
+	return fmt.Sprintf("%x..%x", b[:3], b[BlobSize-3:])
+}
+
+// FromData encodes the given data into the blob.
+// Each field element starts with a zero byte, and carries 31 bytes of the payload.
+// The payload starts with a version byte and a 3 byte big-endian length prefix, followed by the data.
+func (b *Blob) FromData(data Data) error {
+	if len(data) > MaxBlobDataSize {
+		return fmt.Errorf("%w: len=%d", ErrBlobInputTooLarge, len(data))
+	}
+	b.Clear()
+
+	var header [blobHeaderSize]byte
+	binary.BigEndian.PutUint32(header[:], uint32(len(data)))
+	header[0] = BlobEncodingVersion
+
+	payload := append(header[:], data...)
+	for i := 0; i < FieldElementsPerBlob && len(payload) > 0; i++ {
+		n := copy(b[i*32+1:(i+1)*32], payload)
+		payload = payload[n:]
+	}
+	return nil
+}
+
+// ToData decodes the blob into raw byte data. See FromData for the encoding scheme.
+func (b *Blob) ToData() (Data, error) {
+	payload := make([]byte, 0, FieldElementsPerBlob*usableBytesPerFieldElement)
+	for i := 0; i < FieldElementsPerBlob; i++ {
+		if b[i*32] != 0 {
+			return nil, fmt.Errorf("%w: field element %d has a non-zero first byte", ErrBlobInvalidFieldElement, i)
+		}
+		payload = append(payload, b[i*32+1:(i+1)*32]...)
+	}
+	if payload[0] != BlobEncodingVersion {
+		return nil, fmt.Errorf("%w: expected version %d, got %d", ErrBlobInvalidEncodingVersion, BlobEncodingVersion, payload[0])
+	}
+	length := binary.BigEndian.Uint32(payload[:blobHeaderSize]) & 0x00ff_ffff
+	if length > MaxBlobDataSize {
+		return nil, fmt.Errorf("%w: got %d", ErrBlobInvalidLength, length)
+	}
+	end := blobHeaderSize + int(length)
+	for _, v := range payload[end:] {
+		if v != 0 {
+			return nil, ErrBlobExtraneousDataAfterPayload
+		}
+	}
+	return Data(payload[blobHeaderSize:end]), nil
+}
+
+func (b *Blob) Clear() {
+	for i := range b {
+		b[i] = 0
+	}
+}
+
+// KZGToVersionedHash computes the versioned hash of a KZG commitment, as defined in EIP-4844.
+func KZGToVersionedHash(commitment kzg4844.Commitment) (out common.Hash) {
+	h := sha256.Sum256(commitment[:])
+	out[0] = VersionedHashVersionKZG
+	copy(out[1:], h[1:])
+	return out
+}
+
+var (
+	// blsModulus is the order of the BLS12-381 scalar field that blob field elements are in.
+	blsModulus, _ = new(big.Int).SetString("52435875175126190479447740508185965837690552500527637822603658699938581184513", 10)
+	// rootOfUnity2Pow32 is a primitive root of unity of order 2^32 of the BLS12-381 scalar field.
+	rootOfUnity2Pow32, _ = new(big.Int).SetString("10238227357739495823651030575849232062558860180284477541189508159991286009131", 10)
+)
+
+// BlobRootsOfUnity returns the big-endian roots of unity that the field elements of a blob
+// are the evaluations of the blob polynomial at. Like the field elements, they are in
+// bit-reversed order, as defined by EIP-4844: the field element at index i is the
+// evaluation at root i.
+var BlobRootsOfUnity = sync.OnceValue(func() *[FieldElementsPerBlob][32]byte {
+	// The generator of the subgroup of order 4096 = 2^12.
+	generator := new(big.Int).Exp(rootOfUnity2Pow32, big.NewInt(1<<(32-12)), blsModulus)
+	var roots [FieldElementsPerBlob][32]byte
+	current := big.NewInt(1)
+	for i := uint(0); i < FieldElementsPerBlob; i++ {
+		current.FillBytes(roots[bits.Reverse(i)>>(bits.UintSize-12)][:])
+		current.Mul(current, generator).Mod(current, blsModulus)
+	}
+	return &roots
+})
+
+// VerifyBlobProof verifies that the given blob and proof are consistent with the KZG commitment.
+func VerifyBlobProof(blob *Blob, commitment kzg4844.Commitment, proof kzg4844.Proof) error {
+	return kzg4844.VerifyBlobProof(*blob.KZGBlob(), commitment, proof)
+}
+
+type Bytes48 [48]byte
+
+func (b *Bytes48) UnmarshalJSON(text []byte) error {
+	return hexutil.UnmarshalFixedJSON(reflect.TypeOf(b), text, b[:])
+}
+
+func (b *Bytes48) UnmarshalText(text []byte) error {
+	return hexutil.UnmarshalFixedText("Bytes48", text, b[:])
+}
+
+func (b Bytes48) MarshalText() ([]byte, error) {
+	return hexutil.Bytes(b[:]).MarshalText()
+}
+
+func (b Bytes48) String() string {
+	return hexutil.Encode(b[:])
+}
+
+// TerminalString implements log.TerminalStringer, formatting a string for console
+// output during logging.
+func (b Bytes48) TerminalString() string {
+	return fmt.Sprintf("%x..%x", b[:3], b[45:])
+}
+
+// IndexedBlobHash represents a blob hash that commits to a single blob confirmed in a block.
+// The index helps us avoid unnecessary blob to blob hash conversions to find the right content in a sidecar.
+type IndexedBlobHash struct {
+	Index uint64      // absolute index in the block, a.k.a. position in sidecar blobs array
+	Hash  common.Hash // hash of the blob, used for consistency checks
+}
@@ -0,0 +1,72 @@
+package eth
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlobEncodeDecode(t *testing.T) {
+	rng := rand.New(rand.NewSource(1234))
+	cases := []int{0, 1, 27, 28, 31, 1000, 100_000, MaxBlobDataSize}
+	for _, size := range cases {
+		data := make(Data, size)
+		rng.Read(data)
+
+		var b Blob
+		require.NoError(t, b.FromData(data))
+		for i := 0; i < FieldElementsPerBlob; i++ {
+			require.Zero(t, b[i*32], "first byte of field element %d must be zero", i)
+		}
+		decoded, err := b.ToData()
+		require.NoError(t, err)
+		require.Equal(t, data, decoded, "size %d", size)
+	}
+}
+
+func TestBlobTooLarge(t *testing.T) {
+	var b Blob
+	require.ErrorIs(t, b.FromData(make(Data, MaxBlobDataSize+1)), ErrBlobInputTooLarge)
+}
+
+func TestBlobDecodeErrors(t *testing.T) {
+	var b Blob
+	require.NoError(t, b.FromData(Data("hello world")))
+
+	invalidFE := b
+	invalidFE[32*10] = 1
+	_, err := invalidFE.ToData()
+	require.ErrorIs(t, err, ErrBlobInvalidFieldElement)
+
+	invalidVersion := b
+	invalidVersion[1] = 1
+	_, err = invalidVersion.ToData()
+	require.ErrorIs(t, err, ErrBlobInvalidEncodingVersion)
+
+	invalidLength := b
+	invalidLength[2], invalidLength[3], invalidLength[4] = 0xff, 0xff, 0xff
+	_, err = invalidLength.ToData()
+	require.ErrorIs(t, err, ErrBlobInvalidLength)
+
+	trailingData := b
+	trailingData[32*20+5] = 1
+	_, err = trailingData.ToData()
+	require.ErrorIs(t, err, ErrBlobExtraneousDataAfterPayload)
+}
+
+func TestBlobRootsOfUnity(t *testing.T) {
+	rng := rand.New(rand.NewSource(1234))
+	data := make(Data, MaxBlobDataSize)
+	rng.Read(data)
+	var b Blob
+	require.NoError(t, b.FromData(data))
+
+	roots := BlobRootsOfUnity()
+	for _, i := range []int{0, 1, 2, 1000, 2048, FieldElementsPerBlob - 1} {
+		_, claim, err := kzg4844.ComputeProof(*b.KZGBlob(), roots[i])
+		require.NoError(t, err)
+		require.Equal(t, b[i*32:(i+1)*32], claim[:], "field element %d must be the evaluation at root %d", i, i)
+	}
+}
@@ -0,0 +1,50 @@
+package eth
+
+import (
+	"strconv"
+)
+
+// Uint64String is a decimal string representation of an uint64, as used in the beacon-API.
+type Uint64String uint64
+
+func (v Uint64String) MarshalText() (out []byte, err error) {
+	out = strconv.AppendUint(out, uint64(v), 10)
+	return
+}
+
+func (v *Uint64String) UnmarshalText(b []byte) error {
+	n, err := strconv.ParseUint(string(b), 0, 64)
+	if err != nil {
+		return err
+	}
+	*v = Uint64String(n)
+	return nil
+}
+
+// BlobSidecar is a single blob with its KZG commitment and proof, as served by the beacon-API.
+type BlobSidecar struct {
+	Blob          Blob         `json:"blob"`
+	Index         Uint64String `json:"index"`
+	KZGCommitment Bytes48      `json:"kzg_commitment"`
+	KZGProof      Bytes48      `json:"kzg_proof"`
+}
+
+type APIGetBlobSidecarsResponse struct {
+	Data []*BlobSidecar `json:"data"`
+}
+
+type ReducedGenesisData struct {
+	GenesisTime Uint64String `json:"genesis_time"`
+}
+
+type APIGenesisResponse struct {
+	Data ReducedGenesisData `json:"data"`
+}
+
+type ReducedConfigData struct {
+	SecondsPerSlot Uint64String `json:"SECONDS_PER_SLOT"`
+}
+
+type APIConfigResponse struct {
+	Data ReducedConfigData `json:"data"`
+}
@@ -16,6 +16,12 @@ type OutputResponse struct {
 	Status                *SyncStatus `json:"syncStatus"`
 }
 
+// SafeHeadResponse is the L2 safe head that was fully derivable from the L1 chain up to and including L1Block.
+type SafeHeadResponse struct {
+	L1Block  BlockID `json:"l1Block"`
+	SafeHead BlockID `json:"safeHead"`
+}
+
 var (
 	ErrInvalidOutput        = errors.New("invalid output")
 	ErrInvalidOutputVersion = errors.New("invalid output version")
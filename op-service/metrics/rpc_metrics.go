@@ -20,6 +20,12 @@ type RPCMetricer interface {
 	RecordRPCClientResponse(method string, err error)
 }
 
+// RPCEndpointMetricer records the health of the endpoints of a client that fails over between several RPC endpoints.
+type RPCEndpointMetricer interface {
+	RecordRPCClientEndpointHealth(endpoint string, healthy bool, head uint64)
+	RecordRPCClientFailover(endpoint string)
+}
+
 // RPCMetrics tracks all the RPC metrics for the op-service RPC.
 type RPCMetrics struct {
 	RPCServerRequestsTotal          *prometheus.CounterVec
@@ -27,6 +33,9 @@ type RPCMetrics struct {
 	RPCClientRequestsTotal          *prometheus.CounterVec
 	RPCClientRequestDurationSeconds *prometheus.HistogramVec
 	RPCClientResponsesTotal         *prometheus.CounterVec
+	RPCClientEndpointHealthy        *prometheus.GaugeVec
+	RPCClientEndpointHead           *prometheus.GaugeVec
+	RPCClientFailoversTotal         *prometheus.CounterVec
 }
 
 // MakeRPCMetrics creates a new RPCMetrics instance with the given process name, and
@@ -76,6 +85,30 @@ func MakeRPCMetrics(ns string, factory Factory) RPCMetrics {
 			"method",
 			"error",
 		}),
+		RPCClientEndpointHealthy: factory.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: ns,
+			Subsystem: RPCClientSubsystem,
+			Name:      "endpoint_healthy",
+			Help:      "1 if the RPC endpoint is healthy, 0 if it failed or lags behind the other endpoints",
+		}, []string{
+			"endpoint",
+		}),
+		RPCClientEndpointHead: factory.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: ns,
+			Subsystem: RPCClientSubsystem,
+			Name:      "endpoint_head",
+			Help:      "Latest block number reported by the RPC endpoint",
+		}, []string{
+			"endpoint",
+		}),
+		RPCClientFailoversTotal: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: ns,
+			Subsystem: RPCClientSubsystem,
+			Name:      "failovers_total",
+			Help:      "Total number of times the RPC client switched away from the endpoint",
+		}, []string{
+			"endpoint",
+		}),
 	}
 }
 
@@ -126,6 +159,21 @@ func (m *RPCMetrics) RecordRPCClientResponse(method string, err error) {
 	m.RPCClientResponsesTotal.WithLabelValues(method, errStr).Inc()
 }
 
+// RecordRPCClientEndpointHealth records the health and latest head of an RPC endpoint.
+func (m *RPCMetrics) RecordRPCClientEndpointHealth(endpoint string, healthy bool, head uint64) {
+	var v float64
+	if healthy {
+		v = 1
+	}
+	m.RPCClientEndpointHealthy.WithLabelValues(endpoint).Set(v)
+	m.RPCClientEndpointHead.WithLabelValues(endpoint).Set(float64(head))
+}
+
+// RecordRPCClientFailover records that the RPC client switched away from the endpoint.
+func (m *RPCMetrics) RecordRPCClientFailover(endpoint string) {
+	m.RPCClientFailoversTotal.WithLabelValues(endpoint).Inc()
+}
+
 type NoopRPCMetrics struct{}
 
 func (n *NoopRPCMetrics) RecordRPCServerRequest(method string) func() {
@@ -138,4 +186,11 @@ func (n *NoopRPCMetrics) RecordRPCClientRequest(method string) func(err error) {
 func (n *NoopRPCMetrics) RecordRPCClientResponse(method string, err error) {
 }
 
+func (n *NoopRPCMetrics) RecordRPCClientEndpointHealth(endpoint string, healthy bool, head uint64) {
+}
+
+func (n *NoopRPCMetrics) RecordRPCClientFailover(endpoint string) {
+}
+
 var _ RPCMetricer = (*NoopRPCMetrics)(nil)
+var _ RPCEndpointMetricer = (*NoopRPCMetrics)(nil)
@@ -0,0 +1,283 @@
+package caching
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/cockroachdb/pebble"
+)
+
+var ErrDiskCacheClosed = errors.New("disk cache is closed")
+
+const (
+	// entryPrefix prefixes the keys of the cache entries, which are followed by the label length, label and key.
+	// The values are the sequence number of the entry, followed by the cached value.
+	entryPrefix byte = 0
+	// seqPrefix prefixes the keys of the entries in insertion order, which are followed by the sequence number.
+	// The values are the size of the entry, followed by the key of the entry.
+	seqPrefix byte = 1
+)
+
+// DiskCache is a key-value cache persisted in a pebble database, so it survives restarts.
+// The total size of the keys and values is bounded: the oldest entries are evicted first.
+// Values are content-addressed by the caller, e.g. by block hash, so adding an existing key is a no-op.
+type DiskCache struct {
+	m       Metrics
+	db      *pebble.DB
+	maxSize uint64
+
+	mu     sync.RWMutex
+	closed bool
+	size   uint64
+	seq    uint64
+	counts map[string]int
+}
+
+// NewDiskCache opens, or creates, the disk cache at path, bounded to maxSize bytes of keys and values.
+// Metrics are optional: no metrics will be tracked if m == nil.
+func NewDiskCache(m Metrics, path string, maxSize uint64) (*DiskCache, error) {
+	db, err := pebble.Open(path, &pebble.Options{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open disk cache %v: %w", path, err)
+	}
+	c := &DiskCache{
+		m:       m,
+		db:      db,
+		maxSize: maxSize,
+		counts:  make(map[string]int),
+	}
+	if err := c.load(); err != nil {
+		return nil, errors.Join(err, db.Close())
+	}
+	return c, nil
+}
+
+// load restores the size, entry counts and next sequence number from the insertion order index.
+func (c *DiskCache) load() error {
+	iter, err := c.db.NewIter(&pebble.IterOptions{
+		LowerBound: []byte{seqPrefix},
+		UpperBound: []byte{seqPrefix + 1},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create iterator: %w", err)
+	}
+	defer iter.Close()
+	for valid := iter.First(); valid; valid = iter.Next() {
+		seq, size, entryKey, err := decodeSeqEntry(iter.Key(), iter.Value())
+		if err != nil {
+			return err
+		}
+		label, err := entryLabel(entryKey)
+		if err != nil {
+			return err
+		}
+		c.size += size
+		c.seq = seq + 1
+		c.counts[label]++
+	}
+	if err := iter.Error(); err != nil {
+		return fmt.Errorf("failed to read disk cache index: %w", err)
+	}
+	return nil
+}
+
+// Get returns the value cached for the given key, with a label to group the entries by.
+func (c *DiskCache) Get(label string, key []byte) (value []byte, ok bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.closed {
+		return nil, false
+	}
+	value, ok = c.get(entryKey(label, key))
+	if c.m != nil {
+		c.m.CacheGet(label, ok)
+	}
+	return value, ok
+}
+
+func (c *DiskCache) get(k []byte) ([]byte, bool) {
+	v, closer, err := c.db.Get(k)
+	if err != nil {
+		return nil, false
+	}
+	defer closer.Close()
+	if len(v) < 8 {
+		return nil, false
+	}
+	// the returned value is only valid until the closer is closed
+	return append([]byte(nil), v[8:]...), true
+}
+
+// Add caches the value for the given key, with a label to group the entries by.
+// Older entries are evicted when the cache grows beyond its maximum size.
+// Values that do not fit the maximum size by themselves are not cached.
+func (c *DiskCache) Add(label string, key []byte, value []byte) (evicted bool, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return false, ErrDiskCacheClosed
+	}
+	k := entryKey(label, key)
+	if _, ok := c.get(k); ok {
+		return false, nil
+	}
+	size := uint64(len(k) + len(value))
+	if size > c.maxSize {
+		return false, nil
+	}
+	batch := c.db.NewBatch()
+	defer batch.Close()
+	if err := batch.Set(k, append(binary.BigEndian.AppendUint64(nil, c.seq), value...), nil); err != nil {
+		return false, fmt.Errorf("failed to add entry: %w", err)
+	}
+	if err := batch.Set(seqKey(c.seq), seqValue(size, k), nil); err != nil {
+		return false, fmt.Errorf("failed to index entry: %w", err)
+	}
+	evictions, evictedSize, err := c.evict(batch, c.size+size)
+	if err != nil {
+		return false, err
+	}
+	if err := batch.Commit(pebble.NoSync); err != nil {
+		return false, fmt.Errorf("failed to commit entry: %w", err)
+	}
+	c.seq++
+	c.size = c.size + size - evictedSize
+	c.counts[label]++
+	for _, l := range evictions {
+		c.counts[l]--
+	}
+	if c.m != nil {
+		c.m.CacheAdd(label, c.counts[label], len(evictions) > 0)
+	}
+	return len(evictions) > 0, nil
+}
+
+// evict adds the removal of the oldest entries to the batch, until the cache size fits the maximum size.
+// It returns the labels of the evicted entries and their total size.
+func (c *DiskCache) evict(batch *pebble.Batch, size uint64) (labels []string, evicted uint64, err error) {
+	if size <= c.maxSize {
+		return nil, 0, nil
+	}
+	iter, err := c.db.NewIter(&pebble.IterOptions{
+		LowerBound: []byte{seqPrefix},
+		UpperBound: []byte{seqPrefix + 1},
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to create iterator: %w", err)
+	}
+	defer iter.Close()
+	for valid := iter.First(); valid && size-evicted > c.maxSize; valid = iter.Next() {
+		_, entrySize, k, err := decodeSeqEntry(iter.Key(), iter.Value())
+		if err != nil {
+			return nil, 0, err
+		}
+		label, err := entryLabel(k)
+		if err != nil {
+			return nil, 0, err
+		}
+		if err := batch.Delete(k, nil); err != nil {
+			return nil, 0, fmt.Errorf("failed to evict entry: %w", err)
+		}
+		if err := batch.Delete(append([]byte(nil), iter.Key()...), nil); err != nil {
+			return nil, 0, fmt.Errorf("failed to evict entry index: %w", err)
+		}
+		labels = append(labels, label)
+		evicted += entrySize
+	}
+	if err := iter.Error(); err != nil {
+		return nil, 0, fmt.Errorf("failed to read disk cache index: %w", err)
+	}
+	return labels, evicted, nil
+}
+
+// Remove removes the value cached for the given key, e.g. when it turned out to be invalid.
+func (c *DiskCache) Remove(label string, key []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return ErrDiskCacheClosed
+	}
+	k := entryKey(label, key)
+	v, closer, err := c.db.Get(k)
+	if errors.Is(err, pebble.ErrNotFound) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to read entry: %w", err)
+	}
+	if len(v) < 8 {
+		_ = closer.Close()
+		return fmt.Errorf("invalid disk cache entry of length %d", len(v))
+	}
+	seq := binary.BigEndian.Uint64(v[:8])
+	_ = closer.Close()
+	size := uint64(len(k))
+	if v, closer, err := c.db.Get(seqKey(seq)); err == nil {
+		if len(v) >= 8 {
+			size = binary.BigEndian.Uint64(v[:8])
+		}
+		_ = closer.Close()
+	}
+	batch := c.db.NewBatch()
+	defer batch.Close()
+	if err := batch.Delete(k, nil); err != nil {
+		return fmt.Errorf("failed to remove entry: %w", err)
+	}
+	if err := batch.Delete(seqKey(seq), nil); err != nil {
+		return fmt.Errorf("failed to remove entry index: %w", err)
+	}
+	if err := batch.Commit(pebble.NoSync); err != nil {
+		return fmt.Errorf("failed to commit entry removal: %w", err)
+	}
+	c.size -= min(size, c.size)
+	c.counts[label]--
+	return nil
+}
+
+// Size returns the total size of the cached keys and values, in bytes.
+func (c *DiskCache) Size() uint64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.size
+}
+
+// Close closes the database. Later lookups miss, and later additions fail with ErrDiskCacheClosed.
+func (c *DiskCache) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+	return c.db.Close()
+}
+
+func entryKey(label string, key []byte) []byte {
+	k := make([]byte, 0, 2+len(label)+len(key))
+	k = append(k, entryPrefix, byte(len(label)))
+	k = append(k, label...)
+	return append(k, key...)
+}
+
+func entryLabel(k []byte) (string, error) {
+	if len(k) < 2 || k[0] != entryPrefix || len(k) < 2+int(k[1]) {
+		return "", fmt.Errorf("invalid disk cache entry key %x", k)
+	}
+	return string(k[2 : 2+int(k[1])]), nil
+}
+
+func seqKey(seq uint64) []byte {
+	return binary.BigEndian.AppendUint64([]byte{seqPrefix}, seq)
+}
+
+func seqValue(size uint64, entryKey []byte) []byte {
+	return append(binary.BigEndian.AppendUint64(nil, size), entryKey...)
+}
+
+func decodeSeqEntry(key []byte, value []byte) (seq uint64, size uint64, entryKey []byte, err error) {
+	if len(key) != 9 || len(value) < 8 {
+		return 0, 0, nil, fmt.Errorf("invalid disk cache index entry %x: %x", key, value)
+	}
+	return binary.BigEndian.Uint64(key[1:]), binary.BigEndian.Uint64(value[:8]), value[8:], nil
+}
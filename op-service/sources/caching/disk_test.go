@@ -0,0 +1,143 @@
+package caching
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiskCache(t *testing.T) {
+	t.Run("GetAndAdd", func(t *testing.T) {
+		c, err := NewDiskCache(nil, t.TempDir(), 1000)
+		require.NoError(t, err)
+		defer c.Close()
+
+		_, ok := c.Get("a", []byte("key"))
+		require.False(t, ok)
+		evicted, err := c.Add("a", []byte("key"), []byte("value"))
+		require.NoError(t, err)
+		require.False(t, evicted)
+		v, ok := c.Get("a", []byte("key"))
+		require.True(t, ok)
+		require.Equal(t, []byte("value"), v)
+
+		// labels are separate namespaces
+		_, ok = c.Get("b", []byte("key"))
+		require.False(t, ok)
+	})
+
+	t.Run("AddExistingKey", func(t *testing.T) {
+		c, err := NewDiskCache(nil, t.TempDir(), 1000)
+		require.NoError(t, err)
+		defer c.Close()
+
+		_, err = c.Add("a", []byte("key"), []byte("value"))
+		require.NoError(t, err)
+		size := c.Size()
+		_, err = c.Add("a", []byte("key"), []byte("value"))
+		require.NoError(t, err)
+		require.Equal(t, size, c.Size())
+	})
+
+	t.Run("EvictOldest", func(t *testing.T) {
+		// every entry is 2+1+4+6 = 13 bytes
+		c, err := NewDiskCache(nil, t.TempDir(), 13*3)
+		require.NoError(t, err)
+		defer c.Close()
+
+		for i := 0; i < 3; i++ {
+			evicted, err := c.Add("a", []byte(fmt.Sprintf("key%d", i)), []byte("value."))
+			require.NoError(t, err)
+			require.False(t, evicted)
+		}
+		evicted, err := c.Add("a", []byte("key3"), []byte("value."))
+		require.NoError(t, err)
+		require.True(t, evicted)
+		require.Equal(t, uint64(13*3), c.Size())
+		_, ok := c.Get("a", []byte("key0"))
+		require.False(t, ok)
+		for i := 1; i < 4; i++ {
+			_, ok := c.Get("a", []byte(fmt.Sprintf("key%d", i)))
+			require.True(t, ok)
+		}
+
+		// a large entry evicts several entries
+		evicted, err = c.Add("a", []byte("key4"), make([]byte, 13*2))
+		require.NoError(t, err)
+		require.True(t, evicted)
+		_, ok = c.Get("a", []byte("key3"))
+		require.False(t, ok)
+		_, ok = c.Get("a", []byte("key4"))
+		require.True(t, ok)
+	})
+
+	t.Run("TooLarge", func(t *testing.T) {
+		c, err := NewDiskCache(nil, t.TempDir(), 10)
+		require.NoError(t, err)
+		defer c.Close()
+
+		evicted, err := c.Add("a", []byte("key"), make([]byte, 10))
+		require.NoError(t, err)
+		require.False(t, evicted)
+		_, ok := c.Get("a", []byte("key"))
+		require.False(t, ok)
+		require.Zero(t, c.Size())
+	})
+
+	t.Run("Remove", func(t *testing.T) {
+		c, err := NewDiskCache(nil, t.TempDir(), 1000)
+		require.NoError(t, err)
+		defer c.Close()
+
+		_, err = c.Add("a", []byte("key"), []byte("value"))
+		require.NoError(t, err)
+		require.NoError(t, c.Remove("a", []byte("key")))
+		_, ok := c.Get("a", []byte("key"))
+		require.False(t, ok)
+		require.Zero(t, c.Size())
+		require.NoError(t, c.Remove("a", []byte("unknown")))
+	})
+
+	t.Run("Reopen", func(t *testing.T) {
+		dir := t.TempDir()
+		c, err := NewDiskCache(nil, dir, 13*2)
+		require.NoError(t, err)
+		_, err = c.Add("a", []byte("key0"), []byte("value."))
+		require.NoError(t, err)
+		_, err = c.Add("b", []byte("key1"), []byte("value."))
+		require.NoError(t, err)
+		require.NoError(t, c.Close())
+
+		c, err = NewDiskCache(nil, dir, 13*2)
+		require.NoError(t, err)
+		defer c.Close()
+		require.Equal(t, uint64(13*2), c.Size())
+		v, ok := c.Get("b", []byte("key1"))
+		require.True(t, ok)
+		require.Equal(t, []byte("value."), v)
+
+		// the size and insertion order are restored, and the oldest entry is evicted first
+		evicted, err := c.Add("a", []byte("key2"), []byte("value."))
+		require.NoError(t, err)
+		require.True(t, evicted)
+		_, ok = c.Get("a", []byte("key0"))
+		require.False(t, ok)
+		_, ok = c.Get("b", []byte("key1"))
+		require.True(t, ok)
+	})
+
+	t.Run("Closed", func(t *testing.T) {
+		c, err := NewDiskCache(nil, t.TempDir(), 1000)
+		require.NoError(t, err)
+		_, err = c.Add("a", []byte("key"), []byte("value"))
+		require.NoError(t, err)
+		require.NoError(t, c.Close())
+
+		_, ok := c.Get("a", []byte("key"))
+		require.False(t, ok)
+		_, err = c.Add("a", []byte("key"), []byte("value"))
+		require.ErrorIs(t, err, ErrDiskCacheClosed)
+		require.NoError(t, c.Close())
+	})
+}
@@ -0,0 +1,173 @@
+package sources
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+)
+
+// Labels of the entries of the EthClient in the disk cache. All entries are keyed by block hash.
+const (
+	diskCacheHeaders  = "headers"
+	diskCacheBlocks   = "blocks"
+	diskCacheReceipts = "receipts"
+)
+
+// diskBlock is the disk cache encoding of a block header with its transactions.
+type diskBlock struct {
+	Header       rlp.RawValue
+	Transactions types.Transactions
+}
+
+// diskCachedInfo returns the block header of the given block hash from the disk cache, if any.
+func (s *EthClient) diskCachedInfo(hash common.Hash) (eth.BlockInfo, bool) {
+	if s.diskCache == nil {
+		return nil, false
+	}
+	data, ok := s.diskCache.Get(diskCacheHeaders, hash[:])
+	if !ok {
+		return nil, false
+	}
+	var header types.Header
+	if err := rlp.DecodeBytes(data, &header); err != nil {
+		s.dropDiskCached(diskCacheHeaders, hash, fmt.Errorf("failed to decode header: %w", err))
+		return nil, false
+	}
+	if err := s.checkDiskCachedHeader(hash, &header); err != nil {
+		s.dropDiskCached(diskCacheHeaders, hash, err)
+		return nil, false
+	}
+	return &headerInfo{hash, &header}, true
+}
+
+// diskCachedInfoAndTxs returns the block header and transactions of the given block hash from the disk cache, if any.
+func (s *EthClient) diskCachedInfoAndTxs(hash common.Hash) (eth.BlockInfo, types.Transactions, bool) {
+	if s.diskCache == nil {
+		return nil, nil, false
+	}
+	data, ok := s.diskCache.Get(diskCacheBlocks, hash[:])
+	if !ok {
+		return nil, nil, false
+	}
+	var block diskBlock
+	if err := rlp.DecodeBytes(data, &block); err != nil {
+		s.dropDiskCached(diskCacheBlocks, hash, fmt.Errorf("failed to decode block: %w", err))
+		return nil, nil, false
+	}
+	var header types.Header
+	if err := rlp.DecodeBytes(block.Header, &header); err != nil {
+		s.dropDiskCached(diskCacheBlocks, hash, fmt.Errorf("failed to decode header: %w", err))
+		return nil, nil, false
+	}
+	if err := s.checkDiskCachedHeader(hash, &header); err != nil {
+		s.dropDiskCached(diskCacheBlocks, hash, err)
+		return nil, nil, false
+	}
+	if !s.trustRPC {
+		if computed := types.DeriveSha(block.Transactions, trie.NewStackTrie(nil)); computed != header.TxHash {
+			s.dropDiskCached(diskCacheBlocks, hash, fmt.Errorf("computed transactions root %s but header has %s", computed, header.TxHash))
+			return nil, nil, false
+		}
+	}
+	return &headerInfo{hash, &header}, block.Transactions, true
+}
+
+// diskCachedReceipts returns the receipts of the given block from the disk cache, if any.
+// The receipts are validated against the block header and transactions, like receipts fetched from the RPC.
+func (s *EthClient) diskCachedReceipts(info eth.BlockInfo, txs types.Transactions) (types.Receipts, bool) {
+	if s.diskCache == nil {
+		return nil, false
+	}
+	hash := info.Hash()
+	data, ok := s.diskCache.Get(diskCacheReceipts, hash[:])
+	if !ok {
+		return nil, false
+	}
+	// Receipts are JSON encoded, as the consensus encoding does not include the block and log metadata.
+	var receipts types.Receipts
+	if err := json.Unmarshal(data, &receipts); err != nil {
+		s.dropDiskCached(diskCacheReceipts, hash, fmt.Errorf("failed to decode receipts: %w", err))
+		return nil, false
+	}
+	if err := validateReceipts(eth.ToBlockID(info), info.ReceiptHash(), eth.TransactionsToHashes(txs), receipts); err != nil {
+		s.dropDiskCached(diskCacheReceipts, hash, err)
+		return nil, false
+	}
+	return receipts, true
+}
+
+// checkDiskCachedHeader verifies the block hash of a header from the disk cache, unless the RPC is trusted,
+// like the headers fetched from the RPC.
+func (s *EthClient) checkDiskCachedHeader(hash common.Hash, header *types.Header) error {
+	if s.trustRPC {
+		return nil
+	}
+	if computed := header.Hash(); computed != hash {
+		return fmt.Errorf("computed block hash %s", computed)
+	}
+	return nil
+}
+
+// diskCacheInfo adds the block header to the disk cache, if enabled.
+func (s *EthClient) diskCacheInfo(info eth.BlockInfo) {
+	if s.diskCache == nil {
+		return
+	}
+	data, err := info.HeaderRLP()
+	if err != nil {
+		s.log.Warn("Failed to encode header for disk cache", "block", eth.ToBlockID(info), "err", err)
+		return
+	}
+	s.addDiskCached(diskCacheHeaders, info.Hash(), data)
+}
+
+// diskCacheInfoAndTxs adds the block header and transactions to the disk cache, if enabled.
+func (s *EthClient) diskCacheInfoAndTxs(info eth.BlockInfo, txs types.Transactions) {
+	if s.diskCache == nil {
+		return
+	}
+	headerRLP, err := info.HeaderRLP()
+	if err != nil {
+		s.log.Warn("Failed to encode header for disk cache", "block", eth.ToBlockID(info), "err", err)
+		return
+	}
+	s.addDiskCached(diskCacheHeaders, info.Hash(), headerRLP)
+	data, err := rlp.EncodeToBytes(&diskBlock{Header: headerRLP, Transactions: txs})
+	if err != nil {
+		s.log.Warn("Failed to encode block for disk cache", "block", eth.ToBlockID(info), "err", err)
+		return
+	}
+	s.addDiskCached(diskCacheBlocks, info.Hash(), data)
+}
+
+// diskCacheReceipts adds the validated receipts of the given block to the disk cache, if enabled.
+func (s *EthClient) diskCacheReceipts(info eth.BlockInfo, receipts types.Receipts) {
+	if s.diskCache == nil {
+		return
+	}
+	data, err := json.Marshal(receipts)
+	if err != nil {
+		s.log.Warn("Failed to encode receipts for disk cache", "block", eth.ToBlockID(info), "err", err)
+		return
+	}
+	s.addDiskCached(diskCacheReceipts, info.Hash(), data)
+}
+
+func (s *EthClient) addDiskCached(label string, hash common.Hash, data []byte) {
+	if _, err := s.diskCache.Add(label, hash[:], data); err != nil {
+		s.log.Warn("Failed to add to disk cache", "type", label, "hash", hash, "err", err)
+	}
+}
+
+func (s *EthClient) dropDiskCached(label string, hash common.Hash, reason error) {
+	s.log.Warn("Dropping invalid disk cache entry", "type", label, "hash", hash, "err", reason)
+	if err := s.diskCache.Remove(label, hash[:]); err != nil {
+		s.log.Warn("Failed to remove from disk cache", "type", label, "hash", hash, "err", err)
+	}
+}
@@ -0,0 +1,118 @@
+package sources
+
+import (
+	"context"
+	"encoding/json"
+	"math/rand"
+	"strconv"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-service/client"
+	"github.com/ethereum-optimism/optimism/op-service/sources/caching"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+)
+
+func TestEthClient_DiskCache(t *testing.T) {
+	rng := rand.New(rand.NewSource(1234))
+	block, receipts := randomRpcBlockAndReceipts(rng, 4)
+	otherBlock, otherReceipts := randomRpcBlockAndReceipts(rng, 4)
+	ctx := context.Background()
+	logger := testlog.Logger(t, log.LvlError)
+
+	diskCache, err := caching.NewDiskCache(nil, t.TempDir(), 1_000_000)
+	require.NoError(t, err)
+	defer diskCache.Close()
+
+	newClient := func(t *testing.T) (*EthClient, *mock.Mock) {
+		srv := rpc.NewServer()
+		t.Cleanup(srv.Stop)
+		m := &mock.Mock{}
+		require.NoError(t, srv.RegisterName("eth", &ethBackend{Mock: m}))
+		cfg := *testEthClientConfig
+		cfg.DiskCache = diskCache
+		cl, err := NewEthClient(client.NewBaseRPCClient(rpc.DialInProc(srv)), logger, nil, &cfg)
+		require.NoError(t, err)
+		t.Cleanup(cl.Close)
+		return cl, m
+	}
+
+	t.Run("FetchFromRPC", func(t *testing.T) {
+		cl, m := newClient(t)
+		m.On("eth_getBlockByHash", block.Hash, true).Once().Return(block)
+		m.On("eth_getBlockReceipts", strconv.Itoa(int(block.Number))).Once().Return(receipts, new(error))
+		info, result, err := cl.FetchReceipts(ctx, block.Hash)
+		require.NoError(t, err)
+		require.Equal(t, block.Hash, info.Hash())
+		requireReceiptsEqual(t, receipts, result)
+		m.AssertExpectations(t)
+	})
+
+	t.Run("FetchFromDisk", func(t *testing.T) {
+		// A new client, with empty in-memory caches, does not fetch anything from the RPC
+		cl, m := newClient(t)
+		info, err := cl.InfoByHash(ctx, block.Hash)
+		require.NoError(t, err)
+		require.Equal(t, block.Hash, info.Hash())
+		info, result, err := cl.FetchReceipts(ctx, block.Hash)
+		require.NoError(t, err)
+		require.Equal(t, block.Hash, info.Hash())
+		requireReceiptsEqual(t, receipts, result)
+		m.AssertExpectations(t)
+
+		// The receipts from disk are kept in memory, and are not written to disk again
+		require.NoError(t, diskCache.Remove(diskCacheReceipts, block.Hash[:]))
+		_, result, err = cl.FetchReceipts(ctx, block.Hash)
+		require.NoError(t, err)
+		requireReceiptsEqual(t, receipts, result)
+		_, ok := diskCache.Get(diskCacheReceipts, block.Hash[:])
+		require.False(t, ok)
+		m.AssertExpectations(t)
+	})
+
+	t.Run("InvalidEntries", func(t *testing.T) {
+		// Entries that do not match the block are replaced with the data from the RPC
+		data, err := json.Marshal(otherReceipts)
+		require.NoError(t, err)
+		require.NoError(t, diskCache.Remove(diskCacheReceipts, block.Hash[:]))
+		_, err = diskCache.Add(diskCacheReceipts, block.Hash[:], data)
+		require.NoError(t, err)
+		otherHeader, err := rlp.EncodeToBytes(otherBlock.createGethHeader())
+		require.NoError(t, err)
+		require.NoError(t, diskCache.Remove(diskCacheHeaders, block.Hash[:]))
+		_, err = diskCache.Add(diskCacheHeaders, block.Hash[:], otherHeader)
+		require.NoError(t, err)
+
+		cl, m := newClient(t)
+		m.On("eth_getBlockByHash", block.Hash, false).Once().Return(block)
+		info, err := cl.InfoByHash(ctx, block.Hash)
+		require.NoError(t, err)
+		require.Equal(t, block.Hash, info.Hash())
+
+		// the block with the transactions is still valid in the disk cache, only the receipts are fetched again
+		m.On("eth_getBlockReceipts", strconv.Itoa(int(block.Number))).Once().Return(receipts, new(error))
+		_, result, err := cl.FetchReceipts(ctx, block.Hash)
+		require.NoError(t, err)
+		requireReceiptsEqual(t, receipts, result)
+		m.AssertExpectations(t)
+
+		cl, m = newClient(t)
+		_, result, err = cl.FetchReceipts(ctx, block.Hash)
+		require.NoError(t, err)
+		requireReceiptsEqual(t, receipts, result)
+		m.AssertExpectations(t)
+	})
+}
+
+func requireReceiptsEqual(t *testing.T, expected any, actual any) {
+	expectedJson, err := json.Marshal(expected)
+	require.NoError(t, err)
+	actualJson, err := json.Marshal(actual)
+	require.NoError(t, err)
+	require.JSONEq(t, string(expectedJson), string(actualJson))
+}
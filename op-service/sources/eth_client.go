@@ -66,6 +66,11 @@ type EthClientConfig struct {
 	// till we re-attempt the user-preferred methods.
 	// If this is 0 then the client does not fall back to less optimal but available methods.
 	MethodResetDuration time.Duration
+
+	// DiskCache optionally persists the block headers, transactions and receipts fetched by block hash,
+	// so they do not have to be fetched again after a restart. Cached entries are verified like RPC results.
+	// The client does not close the disk cache.
+	DiskCache *caching.DiskCache
 }
 
 func (c *EthClientConfig) Check() error {
@@ -124,6 +129,9 @@ type EthClient struct {
 	// common.Hash -> *eth.ExecutionPayload
 	payloadsCache *caching.LRUCache[common.Hash, *eth.ExecutionPayload]
 
+	// diskCache persists headers, transactions and receipts by block hash, may be nil
+	diskCache *caching.DiskCache
+
 	// availableReceiptMethods tracks which receipt methods can be used for fetching receipts
 	// This may be modified concurrently, but we don't lock since it's a single
 	// uint64 that's not critical (fine to miss or mix up a modification)
@@ -180,6 +188,7 @@ func NewEthClient(client client.RPC, log log.Logger, metrics caching.Metrics, co
 		transactionsCache:       caching.NewLRUCache[common.Hash, types.Transactions](metrics, "txs", config.TransactionsCacheSize),
 		headersCache:            caching.NewLRUCache[common.Hash, eth.BlockInfo](metrics, "headers", config.HeadersCacheSize),
 		payloadsCache:           caching.NewLRUCache[common.Hash, *eth.ExecutionPayload](metrics, "payloads", config.PayloadsCacheSize),
+		diskCache:               config.DiskCache,
 		availableReceiptMethods: AvailableReceiptsFetchingMethods(config.RPCProviderKind),
 		lastMethodsReset:        time.Now(),
 		methodResetDuration:     config.MethodResetDuration,
@@ -240,6 +249,7 @@ func (s *EthClient) headerCall(ctx context.Context, method string, id rpcBlockID
 		return nil, fmt.Errorf("fetched block header does not match requested ID: %w", err)
 	}
 	s.headersCache.Add(info.Hash(), info)
+	s.diskCacheInfo(info)
 	return info, nil
 }
 
@@ -351,6 +361,9 @@ func (s *EthClient) blockCall(ctx context.Context, method string, id rpcBlockID)
 	}
 	s.headersCache.Add(info.Hash(), info)
 	s.transactionsCache.Add(info.Hash(), txs)
+	if !flag { // only persist blocks decoded with their transactions
+		s.diskCacheInfoAndTxs(info, txs)
+	}
 	return info, txs, nil
 }
 
@@ -388,6 +401,10 @@ func (s *EthClient) InfoByHash(ctx context.Context, hash common.Hash) (eth.Block
 	if header, ok := s.headersCache.Get(hash); ok {
 		return header, nil
 	}
+	if header, ok := s.diskCachedInfo(hash); ok {
+		s.headersCache.Add(hash, header)
+		return header, nil
+	}
 	return s.headerCall(ctx, "eth_getBlockByHash", hashID(hash))
 }
 
@@ -407,6 +424,11 @@ func (s *EthClient) InfoAndTxsByHash(ctx context.Context, hash common.Hash) (eth
 			return header, txs, nil
 		}
 	}
+	if header, txs, ok := s.diskCachedInfoAndTxs(hash); ok {
+		s.headersCache.Add(hash, header)
+		s.transactionsCache.Add(hash, txs)
+		return header, txs, nil
+	}
 	return s.blockCall(ctx, "eth_getBlockByHash", hashID(hash))
 }
 
@@ -452,12 +474,20 @@ func (s *EthClient) FetchReceipts(ctx context.Context, blockHash common.Hash) (e
 	} else {
 		txHashes := eth.TransactionsToHashes(txs)
 		job = NewReceiptsFetchingJob(s, s.client, s.maxBatchSize, eth.ToBlockID(info), info.ReceiptHash(), txHashes)
+		if receipts, ok := s.diskCachedReceipts(info, txs); ok {
+			// Receipts from the disk cache complete the job, so later calls do not read them from disk again.
+			job.result = receipts
+		}
 		s.receiptsCache.Add(blockHash, job)
 	}
-	receipts, err := job.Fetch(ctx)
+	receipts, fetched, err := job.fetch(ctx)
 	if err != nil {
 		return nil, nil, err
 	}
+	// Only the receipts fetched from the RPC are new to the disk cache.
+	if fetched {
+		s.diskCacheReceipts(info, receipts)
+	}
 
 	return info, receipts, nil
 }
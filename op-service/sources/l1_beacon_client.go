@@ -0,0 +1,166 @@
+package sources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+
+	"github.com/ethereum-optimism/optimism/op-service/client"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+)
+
+const (
+	genesisMethod        = "eth/v1/beacon/genesis"
+	specMethod           = "eth/v1/config/spec"
+	sidecarsMethodPrefix = "eth/v1/beacon/blob_sidecars/"
+)
+
+// L1BeaconClient is a high level golang client for the Beacon API.
+// It fetches blob sidecars of L1 blocks, and verifies them against the blob hashes of the block.
+type L1BeaconClient struct {
+	cl client.HTTP
+
+	initLock     sync.Mutex
+	timeToSlotFn TimeToSlotFn
+}
+
+// TimeToSlotFn returns the slotnumber for a given timestamp
+type TimeToSlotFn func(timestamp uint64) (uint64, error)
+
+// NewL1BeaconClient returns a client for making requests to an L1 consensus layer node.
+func NewL1BeaconClient(cl client.HTTP) *L1BeaconClient {
+	return &L1BeaconClient{cl: cl}
+}
+
+func (cl *L1BeaconClient) apiReq(ctx context.Context, dest any, method string, query url.Values) error {
+	headers := http.Header{}
+	headers.Add("Accept", "application/json")
+	resp, err := cl.cl.Get(ctx, method, query, headers)
+	if err != nil {
+		return fmt.Errorf("http Get failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%w: failed request with status %d", ethereum.NotFound, resp.StatusCode)
+	} else if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed request with status %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(dest); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	// drain the remainder of the body, so the connection can be reused
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+// GetTimeToSlotFn returns a function that converts a timestamp to a slot number.
+func (cl *L1BeaconClient) GetTimeToSlotFn(ctx context.Context) (TimeToSlotFn, error) {
+	cl.initLock.Lock()
+	defer cl.initLock.Unlock()
+	if cl.timeToSlotFn != nil {
+		return cl.timeToSlotFn, nil
+	}
+
+	var genesisResp eth.APIGenesisResponse
+	if err := cl.apiReq(ctx, &genesisResp, genesisMethod, nil); err != nil {
+		return nil, fmt.Errorf("failed to fetch beacon genesis: %w", err)
+	}
+
+	var configResp eth.APIConfigResponse
+	if err := cl.apiReq(ctx, &configResp, specMethod, nil); err != nil {
+		return nil, fmt.Errorf("failed to fetch beacon config spec: %w", err)
+	}
+
+	genesisTime := uint64(genesisResp.Data.GenesisTime)
+	secondsPerSlot := uint64(configResp.Data.SecondsPerSlot)
+	if secondsPerSlot == 0 {
+		return nil, fmt.Errorf("got bad value for seconds per slot: %v", configResp.Data.SecondsPerSlot)
+	}
+	cl.timeToSlotFn = func(timestamp uint64) (uint64, error) {
+		if timestamp < genesisTime {
+			return 0, fmt.Errorf("provided timestamp (%v) precedes genesis time (%v)", timestamp, genesisTime)
+		}
+		return (timestamp - genesisTime) / secondsPerSlot, nil
+	}
+	return cl.timeToSlotFn, nil
+}
+
+// GetBlobSidecars fetches blob sidecars that were confirmed in the specified L1 block with the
+// given indexed hashes. Order of the returned sidecars is guaranteed to be that of the hashes.
+// Blob data is not checked for validity.
+func (cl *L1BeaconClient) GetBlobSidecars(ctx context.Context, ref eth.L1BlockRef, hashes []eth.IndexedBlobHash) ([]*eth.BlobSidecar, error) {
+	if len(hashes) == 0 {
+		return []*eth.BlobSidecar{}, nil
+	}
+	slotFn, err := cl.GetTimeToSlotFn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get time to slot function: %w", err)
+	}
+	slot, err := slotFn(ref.Time)
+	if err != nil {
+		return nil, fmt.Errorf("error in converting ref.Time to slot: %w", err)
+	}
+
+	builder := url.Values{}
+	for i := range hashes {
+		builder.Add("indices", strconv.FormatUint(hashes[i].Index, 10))
+	}
+
+	var resp eth.APIGetBlobSidecarsResponse
+	if err := cl.apiReq(ctx, &resp, sidecarsMethodPrefix+strconv.FormatUint(slot, 10), builder); err != nil {
+		return nil, fmt.Errorf("failed to fetch blob sidecars for slot %v block %v: %w", slot, ref, err)
+	}
+	if len(hashes) != len(resp.Data) {
+		return nil, fmt.Errorf("expected %v sidecars but got %v", len(hashes), len(resp.Data))
+	}
+
+	// The beacon node may return the sidecars in any order, match them up with the requested hashes.
+	byIndex := make(map[uint64]*eth.BlobSidecar, len(resp.Data))
+	for _, sidecar := range resp.Data {
+		byIndex[uint64(sidecar.Index)] = sidecar
+	}
+	out := make([]*eth.BlobSidecar, len(hashes))
+	for i, h := range hashes {
+		sidecar, ok := byIndex[h.Index]
+		if !ok {
+			return nil, fmt.Errorf("missing sidecar for blob index %d", h.Index)
+		}
+		out[i] = sidecar
+	}
+	return out, nil
+}
+
+// GetBlobs fetches blobs that were confirmed in the specified L1 block with the given indexed
+// hashes. The order of the returned blobs will match the order of `hashes`. Confirms each
+// blob's validity by checking its proof against the commitment, and confirming the commitment
+// hashes to the expected value. Returns error if any blob is found invalid.
+func (cl *L1BeaconClient) GetBlobs(ctx context.Context, ref eth.L1BlockRef, hashes []eth.IndexedBlobHash) ([]*eth.Blob, error) {
+	blobSidecars, err := cl.GetBlobSidecars(ctx, ref, hashes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blob sidecars for L1BlockRef %s: %w", ref, err)
+	}
+	out := make([]*eth.Blob, len(hashes))
+	for i, sidecar := range blobSidecars {
+		// make sure the blob's kzg commitment hashes to the expected value
+		commitment := kzg4844.Commitment(sidecar.KZGCommitment)
+		hash := eth.KZGToVersionedHash(commitment)
+		if hash != hashes[i].Hash {
+			return nil, fmt.Errorf("expected hash %s for blob at index %d but got %s", hashes[i].Hash, hashes[i].Index, hash)
+		}
+
+		// confirm blob data is valid by verifying its proof against the commitment
+		if err := eth.VerifyBlobProof(&sidecar.Blob, commitment, kzg4844.Proof(sidecar.KZGProof)); err != nil {
+			return nil, fmt.Errorf("blob at index %d failed verification: %w", i, err)
+		}
+		out[i] = &sidecar.Blob
+	}
+	return out, nil
+}
@@ -0,0 +1,145 @@
+package sources
+
+import (
+	"context"
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/ethereum-optimism/optimism/op-service/client"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+	"github.com/ethereum-optimism/optimism/op-service/testutils"
+)
+
+// fakeBeacon serves a minimal subset of the beacon-API, backed by in-memory blob sidecars per slot.
+type fakeBeacon struct {
+	genesisTime    uint64
+	secondsPerSlot uint64
+	sidecars       map[uint64][]*eth.BlobSidecar
+}
+
+func (f *fakeBeacon) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var resp any
+	switch path := r.URL.Path; {
+	case path == "/eth/v1/beacon/genesis":
+		resp = &eth.APIGenesisResponse{Data: eth.ReducedGenesisData{GenesisTime: eth.Uint64String(f.genesisTime)}}
+	case path == "/eth/v1/config/spec":
+		resp = &eth.APIConfigResponse{Data: eth.ReducedConfigData{SecondsPerSlot: eth.Uint64String(f.secondsPerSlot)}}
+	case len(path) > len(sidecarsMethodPrefix)+1 && path[:len(sidecarsMethodPrefix)+1] == "/"+sidecarsMethodPrefix:
+		slot, err := strconv.ParseUint(path[len(sidecarsMethodPrefix)+1:], 10, 64)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		sidecars, ok := f.sidecars[slot]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		out := &eth.APIGetBlobSidecarsResponse{}
+		for _, idx := range r.URL.Query()["indices"] {
+			i, err := strconv.ParseUint(idx, 10, 64)
+			if err != nil || i >= uint64(len(sidecars)) {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			out.Data = append(out.Data, sidecars[i])
+		}
+		resp = out
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func makeTestBlobSidecar(t *testing.T, rng *rand.Rand, index uint64) (eth.IndexedBlobHash, *eth.BlobSidecar) {
+	var blob eth.Blob
+	require.NoError(t, blob.FromData(testutils.RandomData(rng, 1000)))
+	commitment, err := kzg4844.BlobToCommitment(*blob.KZGBlob())
+	require.NoError(t, err)
+	proof, err := kzg4844.ComputeBlobProof(*blob.KZGBlob(), commitment)
+	require.NoError(t, err)
+	hash := eth.IndexedBlobHash{Index: index, Hash: eth.KZGToVersionedHash(commitment)}
+	return hash, &eth.BlobSidecar{
+		Blob:          blob,
+		Index:         eth.Uint64String(index),
+		KZGCommitment: eth.Bytes48(commitment),
+		KZGProof:      eth.Bytes48(proof),
+	}
+}
+
+func TestL1BeaconClient(t *testing.T) {
+	rng := rand.New(rand.NewSource(1234))
+	hash0, sidecar0 := makeTestBlobSidecar(t, rng, 0)
+	hash1, sidecar1 := makeTestBlobSidecar(t, rng, 1)
+	beacon := &fakeBeacon{
+		genesisTime:    10,
+		secondsPerSlot: 12,
+		sidecars:       map[uint64][]*eth.BlobSidecar{5: {sidecar0, sidecar1}},
+	}
+	srv := httptest.NewServer(beacon)
+	defer srv.Close()
+
+	logger := testlog.Logger(t, log.LvlInfo)
+	cl := NewL1BeaconClient(client.NewBasicHTTPClient(srv.URL, logger))
+	ctx := context.Background()
+	ref := eth.L1BlockRef{Number: 100, Time: 10 + 5*12}
+
+	t.Run("time to slot", func(t *testing.T) {
+		fn, err := cl.GetTimeToSlotFn(ctx)
+		require.NoError(t, err)
+		slot, err := fn(10 + 5*12 + 3)
+		require.NoError(t, err)
+		require.Equal(t, uint64(5), slot)
+		_, err = fn(9)
+		require.Error(t, err, "timestamp before genesis")
+	})
+
+	t.Run("get blobs", func(t *testing.T) {
+		blobs, err := cl.GetBlobs(ctx, ref, []eth.IndexedBlobHash{hash1, hash0})
+		require.NoError(t, err)
+		require.Len(t, blobs, 2)
+		require.Equal(t, sidecar1.Blob, *blobs[0])
+		require.Equal(t, sidecar0.Blob, *blobs[1])
+	})
+
+	t.Run("no hashes", func(t *testing.T) {
+		blobs, err := cl.GetBlobs(ctx, ref, nil)
+		require.NoError(t, err)
+		require.Empty(t, blobs)
+	})
+
+	t.Run("mismatched hash", func(t *testing.T) {
+		badHash := eth.IndexedBlobHash{Index: 0, Hash: hash1.Hash}
+		_, err := cl.GetBlobs(ctx, ref, []eth.IndexedBlobHash{badHash})
+		require.ErrorContains(t, err, "expected hash")
+	})
+
+	t.Run("invalid proof", func(t *testing.T) {
+		beacon.sidecars[6] = []*eth.BlobSidecar{{
+			Blob:          sidecar1.Blob,
+			Index:         0,
+			KZGCommitment: sidecar0.KZGCommitment,
+			KZGProof:      sidecar0.KZGProof,
+		}}
+		_, err := cl.GetBlobs(ctx, eth.L1BlockRef{Time: 10 + 6*12}, []eth.IndexedBlobHash{hash0})
+		require.ErrorContains(t, err, "failed verification")
+	})
+
+	t.Run("unknown slot", func(t *testing.T) {
+		_, err := cl.GetBlobs(ctx, eth.L1BlockRef{Time: 10 + 7*12}, []eth.IndexedBlobHash{hash0})
+		require.ErrorIs(t, err, ethereum.NotFound)
+	})
+}
@@ -74,6 +74,15 @@ func (lc *limitClient) EthSubscribe(ctx context.Context, channel any, args ...an
 	return lc.c.EthSubscribe(ctx, channel, args...)
 }
 
+func (lc *limitClient) Subscribe(ctx context.Context, namespace string, channel any, args ...any) (ethereum.Subscription, error) {
+	if !lc.joinWaitGroup() {
+		return nil, net.ErrClosed
+	}
+	defer lc.wg.Done()
+	// subscription doesn't count towards request limit
+	return lc.c.Subscribe(ctx, namespace, channel, args...)
+}
+
 func (lc *limitClient) Close() {
 	lc.mutex.Lock()
 	lc.closed = true // No new waitgroup members after this is set
@@ -486,24 +486,31 @@ func (job *receiptsFetchingJob) runAltMethod(ctx context.Context, m ReceiptsFetc
 // The job caches the result, so repeated Fetches add no additional cost.
 // Fetch is safe to be called concurrently, and will lock to avoid duplicate work or internal inconsistency.
 func (job *receiptsFetchingJob) Fetch(ctx context.Context) (types.Receipts, error) {
+	receipts, _, err := job.fetch(ctx)
+	return receipts, err
+}
+
+// fetch is like Fetch, and also reports whether the receipts were fetched by this call,
+// rather than by an earlier call.
+func (job *receiptsFetchingJob) fetch(ctx context.Context) (types.Receipts, bool, error) {
 	job.m.Lock()
 	defer job.m.Unlock()
 
 	if job.result != nil {
-		return job.result, nil
+		return job.result, false, nil
 	}
 
 	m := job.requester.PickReceiptsMethod(uint64(len(job.txHashes)))
 
 	if m == EthGetTransactionReceiptBatch {
 		if err := job.runFetcher(ctx); err != nil {
-			return nil, err
+			return nil, false, err
 		}
 	} else {
 		if err := job.runAltMethod(ctx, m); err != nil {
-			return nil, err
+			return nil, false, err
 		}
 	}
 
-	return job.result, nil
+	return job.result, true, nil
 }
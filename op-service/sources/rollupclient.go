@@ -4,12 +4,17 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
 
 	"github.com/ethereum-optimism/optimism/op-node/rollup"
 	"github.com/ethereum-optimism/optimism/op-service/client"
@@ -119,6 +124,12 @@ func (r *RollupClient) OutputAtBlock(ctx context.Context, blockNum uint64) (*eth
 	return output, err
 }
 
+func (r *RollupClient) SafeHeadAtL1Block(ctx context.Context, blockNum uint64) (*eth.SafeHeadResponse, error) {
+	var output *eth.SafeHeadResponse
+	err := r.rpc.CallContext(ctx, &output, "optimism_safeHeadAtL1Block", hexutil.Uint64(blockNum))
+	return output, err
+}
+
 func (r *RollupClient) SyncStatus(ctx context.Context) (*eth.SyncStatus, error) {
 	var output *eth.SyncStatus
 	err := sysncStatus(&output)
@@ -126,6 +137,116 @@ func (r *RollupClient) SyncStatus(ctx context.Context) (*eth.SyncStatus, error)
 	return output, err
 }
 
+// SubscribeSyncStatus sends the sync status to ch whenever it changes, starting with the current sync status.
+// If the connection or the rollup node does not support subscriptions, the sync status is polled every pollInterval
+// instead. Failed polls are logged with lgr and retried.
+func (r *RollupClient) SubscribeSyncStatus(ctx context.Context, lgr log.Logger, ch chan<- *eth.SyncStatus, pollInterval time.Duration) (ethereum.Subscription, error) {
+	sub, err := r.rpc.Subscribe(ctx, "optimism", ch, "syncStatus")
+	if !subscriptionUnsupported(err) {
+		return sub, err
+	}
+	var prev eth.SyncStatus
+	return r.pollSyncStatus(lgr, pollInterval, func(status *eth.SyncStatus, quit <-chan struct{}) {
+		if *status == prev {
+			return
+		}
+		prev = *status
+		select {
+		case ch <- status:
+		case <-quit:
+		}
+	}), nil
+}
+
+// SubscribeUnsafeHead sends the unsafe L2 head to ch whenever it changes, starting with the current unsafe head.
+// Like SubscribeSyncStatus, it falls back to polling every pollInterval.
+func (r *RollupClient) SubscribeUnsafeHead(ctx context.Context, lgr log.Logger, ch chan<- eth.L2BlockRef, pollInterval time.Duration) (ethereum.Subscription, error) {
+	return r.subscribeHead(ctx, lgr, "unsafeHead", ch, pollInterval, func(status *eth.SyncStatus) eth.L2BlockRef {
+		return status.UnsafeL2
+	})
+}
+
+// SubscribeSafeHead sends the safe L2 head to ch whenever it changes, starting with the current safe head.
+// Like SubscribeSyncStatus, it falls back to polling every pollInterval.
+func (r *RollupClient) SubscribeSafeHead(ctx context.Context, lgr log.Logger, ch chan<- eth.L2BlockRef, pollInterval time.Duration) (ethereum.Subscription, error) {
+	return r.subscribeHead(ctx, lgr, "safeHead", ch, pollInterval, func(status *eth.SyncStatus) eth.L2BlockRef {
+		return status.SafeL2
+	})
+}
+
+// SubscribeFinalizedHead sends the finalized L2 head to ch whenever it changes, starting with the current finalized
+// head. Like SubscribeSyncStatus, it falls back to polling every pollInterval.
+func (r *RollupClient) SubscribeFinalizedHead(ctx context.Context, lgr log.Logger, ch chan<- eth.L2BlockRef, pollInterval time.Duration) (ethereum.Subscription, error) {
+	return r.subscribeHead(ctx, lgr, "finalizedHead", ch, pollInterval, func(status *eth.SyncStatus) eth.L2BlockRef {
+		return status.FinalizedL2
+	})
+}
+
+func (r *RollupClient) subscribeHead(ctx context.Context, lgr log.Logger, topic string, ch chan<- eth.L2BlockRef, pollInterval time.Duration, head func(status *eth.SyncStatus) eth.L2BlockRef) (ethereum.Subscription, error) {
+	sub, err := r.rpc.Subscribe(ctx, "optimism", ch, topic)
+	if !subscriptionUnsupported(err) {
+		return sub, err
+	}
+	var prev eth.L2BlockRef
+	return r.pollSyncStatus(lgr, pollInterval, func(status *eth.SyncStatus, quit <-chan struct{}) {
+		ref := head(status)
+		if ref == prev {
+			return
+		}
+		prev = ref
+		select {
+		case ch <- ref:
+		case <-quit:
+		}
+	}), nil
+}
+
+// maxSyncStatusPollFailures is the number of consecutive failed sync status polls after which polling fails.
+const maxSyncStatusPollFailures = 10
+
+// pollSyncStatus fetches the sync status immediately and then every interval, and passes it to fn.
+// Like a websocket subscription, it runs until unsubscribed. A failed request is retried on the next tick,
+// and only after maxSyncStatusPollFailures consecutive failures the subscription fails with the last error.
+func (r *RollupClient) pollSyncStatus(lgr log.Logger, interval time.Duration, fn func(status *eth.SyncStatus, quit <-chan struct{})) ethereum.Subscription {
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		failures := 0
+		for {
+			ctx, cancel := context.WithTimeout(context.Background(), interval)
+			var status *eth.SyncStatus
+			err := r.rpc.CallContext(ctx, &status, "optimism_syncStatus")
+			cancel()
+			if err != nil {
+				failures++
+				if failures >= maxSyncStatusPollFailures {
+					return fmt.Errorf("failed to poll sync status %d times: %w", failures, err)
+				}
+				lgr.Warn("Failed to poll sync status, retrying", "failures", failures, "err", err)
+			} else {
+				failures = 0
+				fn(status, quit)
+			}
+			select {
+			case <-ticker.C:
+			case <-quit:
+				return nil
+			}
+		}
+	})
+}
+
+// subscriptionUnsupported returns true if a subscription failed because the connection does not support
+// subscriptions, e.g. HTTP, or because the rollup node does not provide it. Other errors are returned to the caller.
+func subscriptionUnsupported(err error) bool {
+	if errors.Is(err, rpc.ErrNotificationsUnsupported) {
+		return true
+	}
+	var rpcErr rpc.Error
+	// method not found, also returned for unknown subscriptions
+	return errors.As(err, &rpcErr) && rpcErr.ErrorCode() == -32601
+}
+
 func (r *RollupClient) RollupConfig(ctx context.Context) (*rollup.Config, error) {
 	var output *rollup.Config
 	err := r.rpc.CallContext(ctx, &output, "optimism_rollupConfig")
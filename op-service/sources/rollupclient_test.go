@@ -0,0 +1,177 @@
+package sources
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-service/client"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+	"github.com/ethereum-optimism/optimism/op-service/testutils"
+)
+
+// syncStatusAPI serves optimism_syncStatus, like a rollup node without subscription support.
+type syncStatusAPI struct {
+	lock   sync.Mutex
+	status *eth.SyncStatus
+	// failures is the number of following requests that fail
+	failures int
+}
+
+func (api *syncStatusAPI) SyncStatus(ctx context.Context) (*eth.SyncStatus, error) {
+	api.lock.Lock()
+	defer api.lock.Unlock()
+	if api.failures > 0 {
+		api.failures--
+		return nil, serverError{}
+	}
+	return api.status, nil
+}
+
+func (api *syncStatusAPI) Fail(failures int) {
+	api.lock.Lock()
+	defer api.lock.Unlock()
+	api.failures = failures
+}
+
+func (api *syncStatusAPI) Set(status eth.SyncStatus) {
+	api.lock.Lock()
+	defer api.lock.Unlock()
+	api.status = &status
+}
+
+// unsafeHeadSubscriptionAPI serves the optimism_subscribe unsafeHead subscription, sending a fixed head.
+type unsafeHeadSubscriptionAPI struct {
+	head eth.L2BlockRef
+}
+
+func (api *unsafeHeadSubscriptionAPI) UnsafeHead(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, _ := rpc.NotifierFromContext(ctx)
+	sub := notifier.CreateSubscription()
+	return sub, notifier.Notify(sub.ID, api.head)
+}
+
+// failingSubscriptionAPI rejects the optimism_subscribe safeHead subscription with a server error.
+type failingSubscriptionAPI struct {
+	syncStatusAPI
+}
+
+type serverError struct{}
+
+func (serverError) Error() string  { return "subscription limit reached" }
+func (serverError) ErrorCode() int { return -32000 }
+
+func (api *failingSubscriptionAPI) SafeHead(ctx context.Context) (*rpc.Subscription, error) {
+	return nil, serverError{}
+}
+
+func TestRollupClient_SubscribeFallsBackToPolling(t *testing.T) {
+	rng := rand.New(rand.NewSource(1234))
+	api := &syncStatusAPI{}
+	status := eth.SyncStatus{UnsafeL2: testutils.RandomL2BlockRef(rng), SafeL2: testutils.RandomL2BlockRef(rng)}
+	api.Set(status)
+	srv := rpc.NewServer()
+	require.NoError(t, srv.RegisterName("optimism", api))
+	cl := NewRollupClient(client.NewBaseRPCClient(rpc.DialInProc(srv)))
+	defer cl.Close()
+
+	statuses := make(chan *eth.SyncStatus, 10)
+	statusSub, err := cl.SubscribeSyncStatus(context.Background(), testlog.Logger(t, log.LvlInfo), statuses, 10*time.Millisecond)
+	require.NoError(t, err)
+	defer statusSub.Unsubscribe()
+	safeHeads := make(chan eth.L2BlockRef, 10)
+	safeSub, err := cl.SubscribeSafeHead(context.Background(), testlog.Logger(t, log.LvlInfo), safeHeads, 10*time.Millisecond)
+	require.NoError(t, err)
+	defer safeSub.Unsubscribe()
+
+	require.Equal(t, status, *receive(t, statuses))
+	require.Equal(t, status.SafeL2, receive(t, safeHeads))
+
+	// Only changes are sent, of the topic of the subscription
+	status.UnsafeL2 = testutils.RandomL2BlockRef(rng)
+	api.Set(status)
+	require.Equal(t, status, *receive(t, statuses))
+	status.SafeL2 = testutils.RandomL2BlockRef(rng)
+	api.Set(status)
+	require.Equal(t, status, *receive(t, statuses))
+	require.Equal(t, status.SafeL2, receive(t, safeHeads))
+	require.Empty(t, safeHeads)
+}
+
+func TestRollupClient_SubscribeWithNotifications(t *testing.T) {
+	rng := rand.New(rand.NewSource(1234))
+	head := testutils.RandomL2BlockRef(rng)
+	srv := rpc.NewServer()
+	require.NoError(t, srv.RegisterName("optimism", &unsafeHeadSubscriptionAPI{head: head}))
+	cl := NewRollupClient(client.NewBaseRPCClient(rpc.DialInProc(srv)))
+	defer cl.Close()
+
+	heads := make(chan eth.L2BlockRef, 10)
+	// The server does not serve optimism_syncStatus, so the head can only be received with the subscription
+	sub, err := cl.SubscribeUnsafeHead(context.Background(), testlog.Logger(t, log.LvlInfo), heads, time.Hour)
+	require.NoError(t, err)
+	defer sub.Unsubscribe()
+	require.Equal(t, head, receive(t, heads))
+}
+
+func TestRollupClient_SubscribeReturnsServerErrors(t *testing.T) {
+	api := &failingSubscriptionAPI{}
+	api.Set(eth.SyncStatus{})
+	srv := rpc.NewServer()
+	require.NoError(t, srv.RegisterName("optimism", api))
+	cl := NewRollupClient(client.NewBaseRPCClient(rpc.DialInProc(srv)))
+	defer cl.Close()
+
+	// Only unsupported subscriptions fall back to polling, other errors are returned.
+	_, err := cl.SubscribeSafeHead(context.Background(), testlog.Logger(t, log.LvlInfo), make(chan eth.L2BlockRef, 10), 10*time.Millisecond)
+	var rpcErr rpc.Error
+	require.ErrorAs(t, err, &rpcErr)
+	require.Equal(t, -32000, rpcErr.ErrorCode())
+	require.ErrorContains(t, err, "subscription limit reached")
+
+	sub, err := cl.SubscribeUnsafeHead(context.Background(), testlog.Logger(t, log.LvlInfo), make(chan eth.L2BlockRef, 10), 10*time.Millisecond)
+	require.NoError(t, err, "unknown subscription must fall back to polling")
+	sub.Unsubscribe()
+}
+
+func TestRollupClient_PollingRetriesFailures(t *testing.T) {
+	rng := rand.New(rand.NewSource(1234))
+	api := &syncStatusAPI{}
+	status := eth.SyncStatus{UnsafeL2: testutils.RandomL2BlockRef(rng)}
+	api.Set(status)
+	api.Fail(maxSyncStatusPollFailures - 1)
+	srv := rpc.NewServer()
+	require.NoError(t, srv.RegisterName("optimism", api))
+	cl := NewRollupClient(client.NewBaseRPCClient(rpc.DialInProc(srv)))
+	defer cl.Close()
+
+	heads := make(chan eth.L2BlockRef, 10)
+	sub, err := cl.SubscribeUnsafeHead(context.Background(), testlog.Logger(t, log.LvlCrit), heads, time.Millisecond)
+	require.NoError(t, err)
+	defer sub.Unsubscribe()
+	require.Equal(t, status.UnsafeL2, receive(t, heads))
+
+	// Polling fails after too many consecutive failures
+	api.Fail(maxSyncStatusPollFailures)
+	err = receive(t, sub.Err())
+	var rpcErr rpc.Error
+	require.ErrorAs(t, err, &rpcErr)
+	require.Equal(t, -32000, rpcErr.ErrorCode())
+}
+
+func receive[T any](t *testing.T, ch <-chan T) T {
+	select {
+	case v := <-ch:
+		return v
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for subscription update")
+		panic("unreachable")
+	}
+}
@@ -12,24 +12,36 @@ import (
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip4844"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/txpool"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/holiman/uint256"
 
+	"github.com/ethereum-optimism/optimism/op-service/eth"
 	"github.com/ethereum-optimism/optimism/op-service/retry"
 	"github.com/ethereum-optimism/optimism/op-service/txmgr/metrics"
 )
 
 const (
-	// Geth requires a minimum fee bump of 10% for tx resubmission
+	// Geth requires a minimum fee bump of 10% for regular tx resubmission
 	priceBump int64 = 10
+	// The blob pool of geth requires a minimum fee bump of 100% for blob tx resubmission
+	blobPriceBump int64 = 100
 )
 
 // new = old * (100 + priceBump) / 100
 var priceBumpPercent = big.NewInt(100 + priceBump)
+var blobPriceBumpPercent = big.NewInt(100 + blobPriceBump)
 var oneHundred = big.NewInt(100)
 
+// minBlobTxFee is the lower bound of the blob fee cap of blob txs.
+// The blob base fee can be as low as 1 wei, which would leave no room for fee bumps.
+var minBlobTxFee = big.NewInt(params.GWei)
+
 // TxManager is an interface that allows callers to reliably publish txs,
 // bumping the gas price if needed, and obtain the receipt of the resulting tx.
 //
@@ -146,6 +158,13 @@ type TxCandidate struct {
 	GasLimit uint64
 	// Value is the value to be used in the constructed tx.
 	Value *big.Int
+	// Blobs to send along in the tx (optional). If len(Blobs) > 0 then a blob tx
+	// will be sent instead of a DynamicFeeTx.
+	Blobs []*eth.Blob
+	// OnPublished is called (optional) with every version of the tx that is successfully
+	// published, including fee-bumped replacements. It delays the resubmission loop, so it
+	// should return quickly.
+	OnPublished func(tx *types.Transaction)
 }
 
 // Send is used to publish a transaction with incrementally higher gas prices
@@ -192,7 +211,7 @@ func (m *SimpleTxManager) send(ctx context.Context, candidate TxCandidate) (*typ
 	if err != nil {
 		return nil, fmt.Errorf("failed to create the tx: %w", err)
 	}
-	return m.sendTx(ctx, tx)
+	return m.sendTx(ctx, tx, candidate.OnPublished)
 }
 
 // craftTx creates the signed transaction
@@ -208,6 +227,10 @@ func (m *SimpleTxManager) craftTx(ctx context.Context, candidate TxCandidate) (*
 	}
 	gasFeeCap := calcGasFeeCap(basefee, gasTipCap)
 
+	if len(candidate.Blobs) > 0 {
+		return m.craftBlobTx(ctx, candidate, gasTipCap, gasFeeCap)
+	}
+
 	rawTx := &types.LegacyTx{
 		GasPrice: basefee,
 		To:       candidate.To,
@@ -249,12 +272,98 @@ func (m *SimpleTxManager) craftTx(ctx context.Context, candidate TxCandidate) (*
 	return m.signWithNextNonce(ctx, rawTx)
 }
 
+// craftBlobTx creates the signed blob transaction carrying the candidate's blobs.
+// Blob transactions cannot create contracts, so the candidate must have a recipient.
+func (m *SimpleTxManager) craftBlobTx(ctx context.Context, candidate TxCandidate, gasTipCap, gasFeeCap *big.Int) (*types.Transaction, error) {
+	if candidate.To == nil {
+		return nil, errors.New("blob txs cannot deploy contracts")
+	}
+	blobBaseFee, err := m.suggestBlobBaseFee(ctx)
+	if err != nil {
+		m.metr.RPCError()
+		return nil, fmt.Errorf("failed to get blob base fee: %w", err)
+	}
+	sidecar, blobHashes, err := MakeSidecar(candidate.Blobs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make sidecar: %w", err)
+	}
+
+	value := new(uint256.Int)
+	if candidate.Value != nil {
+		if overflow := value.SetFromBig(candidate.Value); overflow {
+			return nil, fmt.Errorf("tx value %v overflows uint256", candidate.Value)
+		}
+	}
+	rawTx := &types.BlobTx{
+		ChainID:    uint256.MustFromBig(m.chainID),
+		To:         *candidate.To,
+		GasTipCap:  uint256.MustFromBig(gasTipCap),
+		GasFeeCap:  uint256.MustFromBig(gasFeeCap),
+		BlobFeeCap: uint256.MustFromBig(calcBlobFeeCap(blobBaseFee)),
+		Value:      value,
+		Data:       candidate.TxData,
+		BlobHashes: blobHashes,
+		Sidecar:    sidecar,
+	}
+
+	m.l.Info("Creating blob tx", "to", rawTx.To, "from", m.cfg.From, "blobs", len(blobHashes), "blobFeeCap", rawTx.BlobFeeCap)
+
+	// Blob gas is priced separately, so the execution gas of a blob tx does not depend on the blobs.
+	if candidate.GasLimit != 0 {
+		rawTx.Gas = candidate.GasLimit
+	} else {
+		gas, err := m.backend.EstimateGas(ctx, ethereum.CallMsg{
+			From:      m.cfg.From,
+			To:        candidate.To,
+			GasFeeCap: gasFeeCap,
+			GasTipCap: gasTipCap,
+			Data:      candidate.TxData,
+			Value:     candidate.Value,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to estimate gas: %w", err)
+		}
+		rawTx.Gas = gas
+	}
+
+	return m.signWithNextNonce(ctx, rawTx)
+}
+
+// MakeSidecar builds the blob tx sidecar for the given blobs, and returns it
+// together with the versioned hashes of the blobs.
+func MakeSidecar(blobs []*eth.Blob) (*types.BlobTxSidecar, []common.Hash, error) {
+	sidecar := &types.BlobTxSidecar{}
+	blobHashes := make([]common.Hash, 0, len(blobs))
+	for i, blob := range blobs {
+		rawBlob := *blob.KZGBlob()
+		commitment, err := kzg4844.BlobToCommitment(rawBlob)
+		if err != nil {
+			return nil, nil, fmt.Errorf("cannot compute KZG commitment of blob %d in tx candidate: %w", i, err)
+		}
+		proof, err := kzg4844.ComputeBlobProof(rawBlob, commitment)
+		if err != nil {
+			return nil, nil, fmt.Errorf("cannot compute KZG proof for fast commitment verification of blob %d in tx candidate: %w", i, err)
+		}
+		sidecar.Blobs = append(sidecar.Blobs, rawBlob)
+		sidecar.Commitments = append(sidecar.Commitments, commitment)
+		sidecar.Proofs = append(sidecar.Proofs, proof)
+		blobHashes = append(blobHashes, eth.KZGToVersionedHash(commitment))
+	}
+	return sidecar, blobHashes, nil
+}
+
 // signWithNextNonce returns a signed transaction with the next available nonce.
 // The nonce is fetched once using eth_getTransactionCount with "latest", and
 // then subsequent calls simply increment this number. If the transaction manager
 // is reset, it will query the eth_getTransactionCount nonce again. If signing
 // fails, the nonce is not incremented.
-func (m *SimpleTxManager) signWithNextNonce(ctx context.Context, rawTx *types.LegacyTx) (*types.Transaction, error) {
+func (m *SimpleTxManager) signWithNextNonce(ctx context.Context, rawTx types.TxData) (*types.Transaction, error) {
+	switch rawTx.(type) {
+	case *types.LegacyTx, *types.BlobTx:
+	default:
+		return nil, fmt.Errorf("unrecognized tx type: %T", rawTx)
+	}
+
 	m.nonceLock.Lock()
 	defer m.nonceLock.Unlock()
 
@@ -272,7 +381,12 @@ func (m *SimpleTxManager) signWithNextNonce(ctx context.Context, rawTx *types.Le
 		*m.nonce++
 	}
 
-	rawTx.Nonce = *m.nonce
+	switch x := rawTx.(type) {
+	case *types.LegacyTx:
+		x.Nonce = *m.nonce
+	case *types.BlobTx:
+		x.Nonce = *m.nonce
+	}
 	ctx, cancel := context.WithTimeout(ctx, m.cfg.NetworkTimeout)
 	defer cancel()
 	tx, err := m.cfg.Signer(ctx, m.cfg.From, types.NewTx(rawTx))
@@ -296,7 +410,8 @@ func (m *SimpleTxManager) resetNonce() {
 
 // send submits the same transaction several times with increasing gas prices as necessary.
 // It waits for the transaction to be confirmed on chain.
-func (m *SimpleTxManager) sendTx(ctx context.Context, tx *types.Transaction) (*types.Receipt, error) {
+// The optional onPublished callback is called with every successfully published version of the transaction.
+func (m *SimpleTxManager) sendTx(ctx context.Context, tx *types.Transaction, onPublished func(tx *types.Transaction)) (*types.Receipt, error) {
 	var wg sync.WaitGroup
 	defer wg.Wait()
 	ctx, cancel := context.WithCancel(ctx)
@@ -308,6 +423,9 @@ func (m *SimpleTxManager) sendTx(ctx context.Context, tx *types.Transaction) (*t
 		wg.Add(1)
 		tx, published := m.publishTx(ctx, tx, sendState, bumpFees)
 		if published {
+			if onPublished != nil {
+				onPublished(tx)
+			}
 			go func() {
 				defer wg.Done()
 				m.waitForTx(ctx, tx, sendState, receiptChan)
@@ -354,7 +472,11 @@ func (m *SimpleTxManager) sendTx(ctx context.Context, tx *types.Transaction) (*t
 // Returns the latest fee bumped tx, and a boolean indicating whether the tx was sent or not
 func (m *SimpleTxManager) publishTx(ctx context.Context, tx *types.Transaction, sendState *SendState, bumpFeesImmediately bool) (*types.Transaction, bool) {
 	updateLogFields := func(tx *types.Transaction) log.Logger {
-		return m.l.New("hash", tx.Hash(), "nonce", tx.Nonce(), "gasTipCap", tx.GasTipCap(), "gasFeeCap", tx.GasFeeCap())
+		l := m.l.New("hash", tx.Hash(), "nonce", tx.Nonce(), "gasTipCap", tx.GasTipCap(), "gasFeeCap", tx.GasFeeCap())
+		if tx.Type() == types.BlobTxType {
+			l = l.New("blobFeeCap", tx.BlobGasFeeCap())
+		}
+		return l
 	}
 	l := updateLogFields(tx)
 
@@ -511,6 +633,7 @@ func (m *SimpleTxManager) queryReceipt(ctx context.Context, txHash common.Hash,
 // rules, and no lower than the values returned by the fee suggestion algorithm to ensure it
 // doesn't linger in the mempool. Finally to avoid runaway price increases, fees are capped at a
 // `feeLimitMultiplier` multiple of the suggested values.
+// Blob transactions are bumped by `blobPriceBump` percent instead, including their blob fee cap.
 func (m *SimpleTxManager) increaseGasPrice(ctx context.Context, tx *types.Transaction) (*types.Transaction, error) {
 	m.l.Info("bumping gas price for tx", "hash", tx.Hash(), "tip", tx.GasTipCap(), "fee", tx.GasFeeCap(), "gaslimit", tx.Gas())
 	tip, basefee, err := m.suggestGasPriceCaps(ctx)
@@ -518,7 +641,8 @@ func (m *SimpleTxManager) increaseGasPrice(ctx context.Context, tx *types.Transa
 		m.l.Warn("failed to get suggested gas tip and basefee", "err", err)
 		return nil, err
 	}
-	bumpedTip, bumpedFee := updateFees(tx.GasTipCap(), tx.GasFeeCap(), tip, basefee, m.l)
+	isBlobTx := tx.Type() == types.BlobTxType
+	bumpedTip, bumpedFee := updateFees(tx.GasTipCap(), tx.GasFeeCap(), tip, basefee, isBlobTx, m.l)
 
 	// Make sure increase is at most [FeeLimitMultiplier] the suggested values
 	maxTip := new(big.Int).Mul(tip, big.NewInt(int64(m.cfg.FeeLimitMultiplier)))
@@ -529,37 +653,47 @@ func (m *SimpleTxManager) increaseGasPrice(ctx context.Context, tx *types.Transa
 	if bumpedFee.Cmp(maxFee) > 0 {
 		return nil, fmt.Errorf("bumped fee 0x%s is over %dx multiple of the suggested value", bumpedFee.Text(16), m.cfg.FeeLimitMultiplier)
 	}
-	rawTx := &types.DynamicFeeTx{
-		ChainID:    tx.ChainId(),
-		Nonce:      tx.Nonce(),
-		GasTipCap:  bumpedTip,
-		GasFeeCap:  bumpedFee,
-		To:         tx.To(),
-		Value:      tx.Value(),
-		Data:       tx.Data(),
-		AccessList: tx.AccessList(),
-	}
 
-	// Re-estimate gaslimit in case things have changed or a previous gaslimit estimate was wrong
-	gas, err := m.backend.EstimateGas(ctx, ethereum.CallMsg{
-		From:      m.cfg.From,
-		To:        rawTx.To,
-		GasFeeCap: bumpedTip,
-		GasTipCap: bumpedFee,
-		Data:      rawTx.Data,
-	})
-	if err != nil {
-		// If this is a transaction resubmission, we sometimes see this outcome because the
-		// original tx can get included in a block just before the above call. In this case the
-		// error is due to the tx reverting with message "block number must be equal to next
-		// expected block number"
-		m.l.Warn("failed to re-estimate gas", "err", err, "gaslimit", tx.Gas())
-		return nil, err
-	}
-	if tx.Gas() != gas {
-		m.l.Info("re-estimated gas differs", "oldgas", tx.Gas(), "newgas", gas)
+	var rawTx types.TxData
+	if isBlobTx {
+		rawTx, err = m.bumpBlobTx(ctx, tx, bumpedTip, bumpedFee)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		dynTx := &types.DynamicFeeTx{
+			ChainID:    tx.ChainId(),
+			Nonce:      tx.Nonce(),
+			GasTipCap:  bumpedTip,
+			GasFeeCap:  bumpedFee,
+			To:         tx.To(),
+			Value:      tx.Value(),
+			Data:       tx.Data(),
+			AccessList: tx.AccessList(),
+		}
+
+		// Re-estimate gaslimit in case things have changed or a previous gaslimit estimate was wrong
+		gas, err := m.backend.EstimateGas(ctx, ethereum.CallMsg{
+			From:      m.cfg.From,
+			To:        dynTx.To,
+			GasFeeCap: bumpedTip,
+			GasTipCap: bumpedFee,
+			Data:      dynTx.Data,
+		})
+		if err != nil {
+			// If this is a transaction resubmission, we sometimes see this outcome because the
+			// original tx can get included in a block just before the above call. In this case the
+			// error is due to the tx reverting with message "block number must be equal to next
+			// expected block number"
+			m.l.Warn("failed to re-estimate gas", "err", err, "gaslimit", tx.Gas())
+			return nil, err
+		}
+		if tx.Gas() != gas {
+			m.l.Info("re-estimated gas differs", "oldgas", tx.Gas(), "newgas", gas)
+		}
+		dynTx.Gas = gas
+		rawTx = dynTx
 	}
-	rawTx.Gas = gas
 
 	ctx, cancel := context.WithTimeout(ctx, m.cfg.NetworkTimeout)
 	defer cancel()
@@ -571,6 +705,60 @@ func (m *SimpleTxManager) increaseGasPrice(ctx context.Context, tx *types.Transa
 	return newTx, nil
 }
 
+// bumpBlobTx clones the blob transaction with the given tip and fee cap. Its blob fee cap
+// is bumped to satisfy the blob pool replacement rules, and no lower than the fee cap
+// derived from the current blob base fee. The blob fee cap is limited to a
+// `feeLimitMultiplier` multiple of the suggested value.
+// The gas limit is kept as is, since blob txs do not carry any execution that could change it.
+func (m *SimpleTxManager) bumpBlobTx(ctx context.Context, tx *types.Transaction, bumpedTip, bumpedFee *big.Int) (*types.BlobTx, error) {
+	sidecar := tx.BlobTxSidecar()
+	if sidecar == nil {
+		return nil, errors.New("blob tx is missing its sidecar")
+	}
+	blobBaseFee, err := m.suggestBlobBaseFee(ctx)
+	if err != nil {
+		m.l.Warn("failed to get suggested blob basefee", "err", err)
+		return nil, err
+	}
+	bumpedBlobFee := calcThresholdValue(tx.BlobGasFeeCap(), true)
+	if newBlobFee := calcBlobFeeCap(blobBaseFee); newBlobFee.Cmp(bumpedBlobFee) > 0 {
+		bumpedBlobFee = newBlobFee
+	}
+	maxBlobFee := new(big.Int).Mul(calcBlobFeeCap(blobBaseFee), big.NewInt(int64(m.cfg.FeeLimitMultiplier)))
+	if bumpedBlobFee.Cmp(maxBlobFee) > 0 {
+		return nil, fmt.Errorf("bumped blob fee 0x%s is over %dx multiple of the suggested value", bumpedBlobFee.Text(16), m.cfg.FeeLimitMultiplier)
+	}
+	return &types.BlobTx{
+		ChainID:    uint256.MustFromBig(tx.ChainId()),
+		Nonce:      tx.Nonce(),
+		GasTipCap:  uint256.MustFromBig(bumpedTip),
+		GasFeeCap:  uint256.MustFromBig(bumpedFee),
+		Gas:        tx.Gas(),
+		To:         *tx.To(),
+		Value:      uint256.MustFromBig(tx.Value()),
+		Data:       tx.Data(),
+		AccessList: tx.AccessList(),
+		BlobFeeCap: uint256.MustFromBig(bumpedBlobFee),
+		BlobHashes: tx.BlobHashes(),
+		Sidecar:    sidecar,
+	}, nil
+}
+
+// suggestBlobBaseFee returns the blob base fee of the next block, derived from the excess blob gas
+// of the latest L1 header.
+func (m *SimpleTxManager) suggestBlobBaseFee(ctx context.Context) (*big.Int, error) {
+	cCtx, cancel := context.WithTimeout(ctx, m.cfg.NetworkTimeout)
+	defer cancel()
+	head, err := m.backend.HeaderByNumber(cCtx, nil)
+	if err != nil {
+		m.metr.RPCError()
+		return nil, fmt.Errorf("failed to fetch the latest header: %w", err)
+	} else if head.ExcessBlobGas == nil {
+		return nil, errors.New("txmgr does not support blob txs on pre-cancun blocks that do not have excess blob gas")
+	}
+	return eip4844.CalcBlobFee(*head.ExcessBlobGas), nil
+}
+
 // suggestGasPriceCaps suggests what the new tip & new basefee should be based on the current L1 conditions
 func (m *SimpleTxManager) suggestGasPriceCaps(ctx context.Context) (*big.Int, *big.Int, error) {
 	return big.NewInt(0), big.NewInt(150000000000), nil
@@ -595,9 +783,14 @@ func (m *SimpleTxManager) suggestGasPriceCaps(ctx context.Context) (*big.Int, *b
 	//return tip, head.BaseFee, nil
 }
 
-// calcThresholdValue returns x * priceBumpPercent / 100
-func calcThresholdValue(x *big.Int) *big.Int {
-	threshold := new(big.Int).Mul(priceBumpPercent, x)
+// calcThresholdValue returns x * priceBumpPercent / 100 for regular txs,
+// and x * blobPriceBumpPercent / 100 for blob txs.
+func calcThresholdValue(x *big.Int, isBlobTx bool) *big.Int {
+	bumpPercent := priceBumpPercent
+	if isBlobTx {
+		bumpPercent = blobPriceBumpPercent
+	}
+	threshold := new(big.Int).Mul(bumpPercent, x)
 	threshold = threshold.Div(threshold, oneHundred)
 	return threshold
 }
@@ -605,14 +798,15 @@ func calcThresholdValue(x *big.Int) *big.Int {
 // updateFees takes an old transaction's tip & fee cap plus a new tip & basefee, and returns
 // a suggested tip and fee cap such that:
 //
-//	(a) each satisfies geth's required tx-replacement fee bumps (we use a 10% increase), and
+//	(a) each satisfies geth's required tx-replacement fee bumps (we use a 10% increase,
+//	    or a 100% increase for blob txs), and
 //	(b) gasTipCap is no less than new tip, and
 //	(c) gasFeeCap is no less than calcGasFee(newBaseFee, newTip)
-func updateFees(oldTip, oldFeeCap, newTip, newBaseFee *big.Int, lgr log.Logger) (*big.Int, *big.Int) {
+func updateFees(oldTip, oldFeeCap, newTip, newBaseFee *big.Int, isBlobTx bool, lgr log.Logger) (*big.Int, *big.Int) {
 	newFeeCap := calcGasFeeCap(newBaseFee, newTip)
 	lgr = lgr.New("old_tip", oldTip, "old_feecap", oldFeeCap, "new_tip", newTip, "new_feecap", newFeeCap)
-	thresholdTip := calcThresholdValue(oldTip)
-	thresholdFeeCap := calcThresholdValue(oldFeeCap)
+	thresholdTip := calcThresholdValue(oldTip, isBlobTx)
+	thresholdFeeCap := calcThresholdValue(oldFeeCap, isBlobTx)
 	if newTip.Cmp(thresholdTip) >= 0 && newFeeCap.Cmp(thresholdFeeCap) >= 0 {
 		lgr.Debug("Using new tip and feecap")
 		return newTip, newFeeCap
@@ -645,6 +839,17 @@ func calcGasFeeCap(baseFee, gasTipCap *big.Int) *big.Int {
 	)
 }
 
+// calcBlobFeeCap computes a suggested blob fee cap that is twice the current blob base fee,
+// to allow for blob base fee increases while the tx is pending. It is no less than
+// minBlobTxFee, so a blob tx can still be bumped a few times when blob space is cheap.
+func calcBlobFeeCap(blobBaseFee *big.Int) *big.Int {
+	blobFeeCap := new(big.Int).Mul(blobBaseFee, big.NewInt(2))
+	if blobFeeCap.Cmp(minBlobTxFee) < 0 {
+		return new(big.Int).Set(minBlobTxFee)
+	}
+	return blobFeeCap
+}
+
 // errStringMatch returns true if err.Error() is a substring in target.Error() or if both are nil.
 // It can accept nil errors without issue.
 func errStringMatch(err, target error) bool {
@@ -11,6 +11,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/ethereum-optimism/optimism/op-service/eth"
 	"github.com/ethereum-optimism/optimism/op-service/testlog"
 	"github.com/ethereum-optimism/optimism/op-service/txmgr/metrics"
 
@@ -19,6 +20,7 @@ import (
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/holiman/uint256"
 )
 
 type sendTransactionFunc func(ctx context.Context, tx *types.Transaction) error
@@ -203,7 +205,8 @@ func (b *mockBackend) CallContract(ctx context.Context, call ethereum.CallMsg, b
 
 func (b *mockBackend) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
 	return &types.Header{
-		BaseFee: b.g.basefee(),
+		BaseFee:       b.g.basefee(),
+		ExcessBlobGas: new(uint64),
 	}, nil
 }
 
@@ -287,7 +290,7 @@ func TestTxMgrConfirmAtMinGasPrice(t *testing.T) {
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
-	receipt, err := h.mgr.sendTx(ctx, tx)
+	receipt, err := h.mgr.sendTx(ctx, tx, nil)
 	require.Nil(t, err)
 	require.NotNil(t, receipt)
 	require.Equal(t, gasPricer.expGasFeeCap().Uint64(), receipt.GasUsed)
@@ -315,7 +318,7 @@ func TestTxMgrNeverConfirmCancel(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	receipt, err := h.mgr.sendTx(ctx, tx)
+	receipt, err := h.mgr.sendTx(ctx, tx, nil)
 	require.Equal(t, err, context.DeadlineExceeded)
 	require.Nil(t, receipt)
 }
@@ -344,12 +347,44 @@ func TestTxMgrConfirmsAtHigherGasPrice(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	receipt, err := h.mgr.sendTx(ctx, tx)
+	receipt, err := h.mgr.sendTx(ctx, tx, nil)
 	require.Nil(t, err)
 	require.NotNil(t, receipt)
 	require.Equal(t, h.gasPricer.expGasFeeCap().Uint64(), receipt.GasUsed)
 }
 
+// TestTxMgrCallsOnPublished asserts that the published tx is reported to the
+// onPublished callback.
+func TestTxMgrCallsOnPublished(t *testing.T) {
+	t.Parallel()
+
+	h := newTestHarness(t)
+
+	gasTipCap, gasFeeCap := h.gasPricer.sample()
+	tx := types.NewTx(&types.DynamicFeeTx{
+		GasTipCap: gasTipCap,
+		GasFeeCap: gasFeeCap,
+	})
+	sendTx := func(ctx context.Context, tx *types.Transaction) error {
+		txHash := tx.Hash()
+		h.backend.mine(&txHash, tx.GasFeeCap())
+		return nil
+	}
+	h.backend.setTxSender(sendTx)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var published []common.Hash
+	receipt, err := h.mgr.sendTx(ctx, tx, func(tx *types.Transaction) {
+		published = append(published, tx.Hash())
+	})
+	require.NoError(t, err)
+	require.NotNil(t, receipt)
+	require.Equal(t, []common.Hash{tx.Hash()}, published)
+	require.Equal(t, tx.Hash(), receipt.TxHash)
+}
+
 // errRpcFailure is a sentinel error used in testing to fail publications.
 var errRpcFailure = errors.New("rpc failure")
 
@@ -375,7 +410,7 @@ func TestTxMgrBlocksOnFailingRpcCalls(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	receipt, err := h.mgr.sendTx(ctx, tx)
+	receipt, err := h.mgr.sendTx(ctx, tx, nil)
 	require.Equal(t, err, context.DeadlineExceeded)
 	require.Nil(t, receipt)
 }
@@ -403,6 +438,60 @@ func TestTxMgr_CraftTx(t *testing.T) {
 	require.Equal(t, candidate.GasLimit, tx.Gas())
 }
 
+// TestTxMgr_CraftBlobTx ensures that the tx manager creates a blob tx with a valid sidecar
+// when the candidate carries blobs.
+func TestTxMgr_CraftBlobTx(t *testing.T) {
+	t.Parallel()
+	cfg := configWithNumConfs(1)
+	cfg.ChainID = big.NewInt(1)
+	h := newTestHarnessWithConfig(t, cfg)
+	candidate := h.createTxCandidate()
+	candidate.TxData = nil
+	var blob eth.Blob
+	require.NoError(t, blob.FromData(eth.Data("batch data")))
+	candidate.Blobs = []*eth.Blob{&blob}
+
+	tx, err := h.mgr.craftTx(context.Background(), candidate)
+	require.NoError(t, err)
+	require.Equal(t, uint8(types.BlobTxType), tx.Type())
+	require.Equal(t, *candidate.To, *tx.To())
+	require.Equal(t, candidate.GasLimit, tx.Gas())
+	require.Zero(t, tx.Nonce())
+
+	// The sidecar must commit to the blob, and match the blob hashes of the tx.
+	sidecar := tx.BlobTxSidecar()
+	require.NotNil(t, sidecar)
+	require.Len(t, tx.BlobHashes(), 1)
+	require.Equal(t, sidecar.BlobHashes(), tx.BlobHashes())
+	require.NoError(t, eth.VerifyBlobProof(&blob, sidecar.Commitments[0], sidecar.Proofs[0]))
+
+	// Without excess blob gas, the blob base fee is at its minimum, so the blob fee cap is at its lower bound.
+	require.Equal(t, minBlobTxFee, tx.BlobGasFeeCap())
+}
+
+// TestTxMgr_CraftBlobTxPreCancun ensures that blob txs are not created when L1 does not support them.
+func TestTxMgr_CraftBlobTxPreCancun(t *testing.T) {
+	t.Parallel()
+	cfg := configWithNumConfs(1)
+	cfg.ChainID = big.NewInt(1)
+	cfg.Backend = &failingBackend{baseFee: big.NewInt(100), gasTip: big.NewInt(10)}
+	mgr := &SimpleTxManager{
+		chainID: cfg.ChainID,
+		name:    "TEST",
+		cfg:     cfg,
+		backend: cfg.Backend,
+		l:       testlog.Logger(t, log.LvlCrit),
+		metr:    &metrics.NoopTxMetrics{},
+	}
+	inbox := common.HexToAddress("0x42000000000000000000000000000000000000ff")
+	_, err := mgr.craftTx(context.Background(), TxCandidate{
+		To:       &inbox,
+		GasLimit: 21_000,
+		Blobs:    []*eth.Blob{{}},
+	})
+	require.ErrorContains(t, err, "pre-cancun")
+}
+
 // TestTxMgr_EstimateGas ensures that the tx manager will estimate
 // the gas when candidate gas limit is zero in [CraftTx].
 func TestTxMgr_EstimateGas(t *testing.T) {
@@ -512,7 +601,7 @@ func TestTxMgrOnlyOnePublicationSucceeds(t *testing.T) {
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
-	receipt, err := h.mgr.sendTx(ctx, tx)
+	receipt, err := h.mgr.sendTx(ctx, tx, nil)
 	require.Nil(t, err)
 
 	require.NotNil(t, receipt)
@@ -547,7 +636,7 @@ func TestTxMgrConfirmsMinGasPriceAfterBumping(t *testing.T) {
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
-	receipt, err := h.mgr.sendTx(ctx, tx)
+	receipt, err := h.mgr.sendTx(ctx, tx, nil)
 	require.Nil(t, err)
 	require.NotNil(t, receipt)
 	require.Equal(t, h.gasPricer.expGasFeeCap().Uint64(), receipt.GasUsed)
@@ -592,7 +681,7 @@ func TestTxMgrDoesntAbortNonceTooLowAfterMiningTx(t *testing.T) {
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
-	receipt, err := h.mgr.sendTx(ctx, tx)
+	receipt, err := h.mgr.sendTx(ctx, tx, nil)
 	require.Nil(t, err)
 	require.NotNil(t, receipt)
 	require.Equal(t, h.gasPricer.expGasFeeCap().Uint64(), receipt.GasUsed)
@@ -692,6 +781,7 @@ type failingBackend struct {
 	returnSuccessBlockNumber bool
 	returnSuccessReceipt     bool
 	baseFee, gasTip          *big.Int
+	excessBlobGas            *uint64
 }
 
 // BlockNumber for the failingBackend returns errRpcFailure on the first
@@ -723,7 +813,8 @@ func (b *failingBackend) TransactionReceipt(
 
 func (b *failingBackend) HeaderByNumber(_ context.Context, _ *big.Int) (*types.Header, error) {
 	return &types.Header{
-		BaseFee: b.baseFee,
+		BaseFee:       b.baseFee,
+		ExcessBlobGas: b.excessBlobGas,
 	}, nil
 }
 
@@ -885,6 +976,59 @@ func TestIncreaseGasPrice(t *testing.T) {
 	}
 }
 
+// TestIncreaseGasPriceBlobTx asserts that blob txs are bumped by at least 100%, including the blob
+// fee cap, and that their blobs are retained.
+func TestIncreaseGasPriceBlobTx(t *testing.T) {
+	t.Parallel()
+	require.Equal(t, int64(100), blobPriceBump, "test must be updated if blobPriceBump is adjusted")
+
+	excessBlobGas := uint64(0)
+	borkedBackend := failingBackend{
+		gasTip:        big.NewInt(0),
+		baseFee:       big.NewInt(150_000_000_000),
+		excessBlobGas: &excessBlobGas,
+	}
+	mgr := &SimpleTxManager{
+		cfg: Config{
+			FeeLimitMultiplier: 5,
+			Signer: func(ctx context.Context, from common.Address, tx *types.Transaction) (*types.Transaction, error) {
+				return tx, nil
+			},
+		},
+		name:    "TEST",
+		backend: &borkedBackend,
+		l:       testlog.Logger(t, log.LvlCrit),
+		metr:    &metrics.NoopTxMetrics{},
+	}
+
+	var blob eth.Blob
+	require.NoError(t, blob.FromData(eth.Data("batch data")))
+	sidecar, blobHashes, err := MakeSidecar([]*eth.Blob{&blob})
+	require.NoError(t, err)
+	tx := types.NewTx(&types.BlobTx{
+		ChainID:    uint256.NewInt(1),
+		GasTipCap:  uint256.NewInt(0),
+		GasFeeCap:  uint256.NewInt(300_000_000_000),
+		Gas:        21_000,
+		BlobFeeCap: uint256.MustFromBig(new(big.Int).Mul(minBlobTxFee, big.NewInt(2))),
+		BlobHashes: blobHashes,
+		Sidecar:    sidecar,
+	})
+
+	newTx, err := mgr.increaseGasPrice(context.Background(), tx)
+	require.NoError(t, err)
+	require.Equal(t, uint8(types.BlobTxType), newTx.Type())
+	require.Equal(t, big.NewInt(600_000_000_000), newTx.GasFeeCap(), "fee cap must be doubled")
+	require.Equal(t, new(big.Int).Mul(minBlobTxFee, big.NewInt(4)), newTx.BlobGasFeeCap(), "blob fee cap must be doubled")
+	require.Equal(t, tx.Gas(), newTx.Gas())
+	require.Equal(t, tx.BlobHashes(), newTx.BlobHashes())
+	require.Equal(t, sidecar, newTx.BlobTxSidecar())
+
+	// The blob fee cap is limited to a multiple of the suggested value.
+	_, err = mgr.increaseGasPrice(context.Background(), newTx)
+	require.ErrorContains(t, err, "blob fee")
+}
+
 // TestIncreaseGasPriceNotExponential asserts that if the L1 basefee & tip remain the
 // same, repeated calls to IncreaseGasPrice do not continually increase the gas price.
 func TestIncreaseGasPriceNotExponential(t *testing.T) {
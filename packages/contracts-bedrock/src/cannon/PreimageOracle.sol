@@ -106,4 +106,106 @@ contract PreimageOracle is IPreimageOracle {
         preimageParts[key][_partOffset] = part;
         preimageLengths[key] = size;
     }
+
+    /// @inheritdoc IPreimageOracle
+    function loadSha256PreimagePart(uint256 _partOffset, bytes calldata _preimage) external {
+        uint256 size;
+        bytes32 key;
+        bytes32 part;
+        assembly {
+            // len(sig) + len(partOffset) + len(preimage offset) = 4 + 32 + 32 = 0x44
+            size := calldataload(0x44)
+
+            // revert if part offset > size+8 (i.e. parts must be within bounds)
+            if gt(_partOffset, add(size, 8)) {
+                // Store "PartOffsetOOB()"
+                mstore(0, 0xfe254987)
+                // Revert with "PartOffsetOOB()"
+                revert(0x1c, 4)
+            }
+            // we leave solidity slots 0x40 and 0x60 untouched,
+            // and everything after as scratch-memory.
+            let ptr := 0x80
+            // put size as big-endian uint64 at start of pre-image
+            mstore(ptr, shl(192, size))
+            ptr := add(ptr, 8)
+            // copy preimage payload into memory so we can hash and read it.
+            calldatacopy(ptr, _preimage.offset, size)
+            // Note that it includes the 8-byte big-endian uint64 length prefix.
+            // this will be zero-padded at the end, since memory at end is clean.
+            part := mload(add(sub(ptr, 8), _partOffset))
+            // compute preimage sha256 hash with the SHA2-256 precompile
+            if iszero(staticcall(gas(), 0x02, ptr, size, 0x00, 0x20)) { revert(0, 0) }
+            let h := mload(0x00)
+            // mask out prefix byte, replace with type 4 byte
+            key := or(and(h, not(shl(248, 0xFF))), shl(248, 4))
+        }
+        preimagePartOk[key][_partOffset] = true;
+        preimageParts[key][_partOffset] = part;
+        preimageLengths[key] = size;
+    }
+
+    /// @inheritdoc IPreimageOracle
+    function loadBlobPreimagePart(
+        uint256 _z,
+        uint256 _y,
+        bytes calldata _commitment,
+        bytes calldata _proof,
+        uint256 _partOffset
+    )
+        external
+    {
+        // The pre-image is the 32 byte field element `_y`, after the 8-byte length prefix.
+        if (_partOffset > 40) revert PartOffsetOOB();
+        if (_commitment.length != 48 || _proof.length != 48) revert InvalidInputSize();
+
+        bytes32 key;
+        bytes32 part;
+        assembly {
+            // we leave solidity slots 0x40 and 0x60 untouched,
+            // and everything after as scratch-memory.
+            let ptr := 0x80
+
+            // Compute the versioned hash of the commitment: the SHA2-256 hash of the commitment,
+            // with the prefix byte replaced by the version byte 0x01.
+            calldatacopy(ptr, _commitment.offset, 48)
+            if iszero(staticcall(gas(), 0x02, ptr, 48, 0x00, 0x20)) { revert(0, 0) }
+            let versionedHash := or(and(mload(0x00), not(shl(248, 0xFF))), shl(248, 0x01))
+
+            // Verify the KZG proof with the point evaluation precompile. Its input is
+            // versioned_hash ++ z ++ y ++ commitment ++ proof. On success, it returns
+            // FIELD_ELEMENTS_PER_BLOB (4096) followed by BLS_MODULUS. The return data is
+            // checked, since a call to the precompile address succeeds without data on chains
+            // that do not support it yet.
+            mstore(ptr, versionedHash)
+            mstore(add(ptr, 0x20), _z)
+            mstore(add(ptr, 0x40), _y)
+            calldatacopy(add(ptr, 0x60), _commitment.offset, 48)
+            calldatacopy(add(ptr, 0x90), _proof.offset, 48)
+            let ok := staticcall(gas(), 0x0A, ptr, 0xC0, 0x00, 0x40)
+            if or(iszero(ok), or(iszero(eq(returndatasize(), 0x40)), iszero(eq(mload(0x00), 4096)))) {
+                // Store "InvalidProof()"
+                mstore(0, 0x09bde339)
+                // Revert with "InvalidProof()"
+                revert(0x1c, 4)
+            }
+
+            // The key is the keccak256 hash of commitment ++ z, with the prefix byte replaced by
+            // the type 5 byte.
+            calldatacopy(ptr, _commitment.offset, 48)
+            mstore(add(ptr, 48), _z)
+            let h := keccak256(ptr, 80)
+            key := or(and(h, not(shl(248, 0xFF))), shl(248, 5))
+
+            // Put the size as big-endian uint64 at the start of the pre-image, followed by y,
+            // and clean the memory after it, so the last part is zero-padded.
+            mstore(add(ptr, 0x28), 0)
+            mstore(ptr, shl(192, 32))
+            mstore(add(ptr, 8), _y)
+            part := mload(add(ptr, _partOffset))
+        }
+        preimagePartOk[key][_partOffset] = true;
+        preimageParts[key][_partOffset] = part;
+        preimageLengths[key] = 32;
+    }
 }
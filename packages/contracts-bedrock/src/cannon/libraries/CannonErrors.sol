@@ -3,3 +3,9 @@ pragma solidity 0.8.15;
 
 /// @notice Thrown when a passed part offset is out of bounds.
 error PartOffsetOOB();
+
+/// @notice Thrown when a passed KZG commitment or proof does not have the expected length.
+error InvalidInputSize();
+
+/// @notice Thrown when a KZG point evaluation proof is invalid.
+error InvalidProof();
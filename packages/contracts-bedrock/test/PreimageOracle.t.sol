@@ -154,6 +154,58 @@ contract PreimageOracle_Test is Test {
         oracle.loadKeccak256PreimagePart(offset, preimage);
     }
 
+    /// @notice Tests that a sha256 pre-image is correctly set.
+    function test_loadSha256PreimagePart_succeeds() public {
+        // Set the pre-image
+        bytes memory preimage = hex"deadbeef";
+        bytes32 key = (sha256(preimage) & ~bytes32(uint256(0xFF) << 248)) | bytes32(uint256(4) << 248);
+        uint256 offset = 0;
+        oracle.loadSha256PreimagePart(offset, preimage);
+
+        // Validate the pre-image part
+        bytes32 part = oracle.preimageParts(key, offset);
+        bytes32 expectedPart = 0x0000000000000004deadbeef0000000000000000000000000000000000000000;
+        assertEq(part, expectedPart);
+
+        // Validate the pre-image length
+        uint256 length = oracle.preimageLengths(key);
+        assertEq(length, preimage.length);
+
+        // Validate that the pre-image part is set
+        bool ok = oracle.preimagePartOk(key, offset);
+        assertTrue(ok);
+    }
+
+    /// @notice Tests that a sha256 pre-image cannot be set with an out-of-bounds offset.
+    function test_loadSha256PreimagePart_outOfBoundsOffset_reverts() public {
+        bytes memory preimage = hex"deadbeef";
+        uint256 offset = preimage.length + 9;
+
+        vm.expectRevert(PartOffsetOOB.selector);
+        oracle.loadSha256PreimagePart(offset, preimage);
+    }
+
+    /// @notice Tests that a blob pre-image cannot be set with an out-of-bounds offset.
+    function test_loadBlobPreimagePart_outOfBoundsOffset_reverts() public {
+        vm.expectRevert(PartOffsetOOB.selector);
+        oracle.loadBlobPreimagePart(0, 0, new bytes(48), new bytes(48), 41);
+    }
+
+    /// @notice Tests that a blob pre-image cannot be set with a commitment or proof of the wrong size.
+    function test_loadBlobPreimagePart_invalidInputSize_reverts() public {
+        vm.expectRevert(InvalidInputSize.selector);
+        oracle.loadBlobPreimagePart(0, 0, new bytes(47), new bytes(48), 0);
+
+        vm.expectRevert(InvalidInputSize.selector);
+        oracle.loadBlobPreimagePart(0, 0, new bytes(48), new bytes(49), 0);
+    }
+
+    /// @notice Tests that a blob pre-image cannot be set without a valid KZG proof.
+    function test_loadBlobPreimagePart_invalidProof_reverts() public {
+        vm.expectRevert(InvalidProof.selector);
+        oracle.loadBlobPreimagePart(1, 2, new bytes(48), new bytes(48), 0);
+    }
+
     /// @notice Reading a pre-image part that has not been set should revert.
     function testFuzz_readPreimage_missingPreimage_reverts(bytes32 key, uint256 offset) public {
         vm.expectRevert("pre-image must exist");